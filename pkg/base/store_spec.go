@@ -50,6 +50,12 @@ type StoreSpec struct {
 	SizePercent float64
 	InMemory    bool
 	Attributes  roachpb.Attributes
+	// EncryptionKey is the path to the key used to encrypt the files of the
+	// store, if any. OldEncryptionKeys are the paths to the keys previously
+	// used, which are needed until all the files encrypted with them have
+	// been rewritten.
+	EncryptionKey     string
+	OldEncryptionKeys []string
 }
 
 // String returns a fully parsable version of the store spec.
@@ -77,6 +83,12 @@ func (ss StoreSpec) String() string {
 		}
 		fmt.Fprintf(&buffer, ",")
 	}
+	if len(ss.EncryptionKey) != 0 {
+		fmt.Fprintf(&buffer, "key=%s,", ss.EncryptionKey)
+	}
+	if len(ss.OldEncryptionKeys) > 0 {
+		fmt.Fprintf(&buffer, "old-key=%s,", strings.Join(ss.OldEncryptionKeys, ":"))
+	}
 	// Trim the extra comma from the end if it exists.
 	if l := buffer.Len(); l > 0 {
 		buffer.Truncate(l - 1)
@@ -114,6 +126,12 @@ var fractionRegex = regexp.MustCompile(`^([0-9]+\.[0-9]*|[0-9]*\.[0-9]+|[0-9]+(\
 //   - 20%             -> 20% of the available space
 //   - 0.2             -> 20% of the available space
 // - attrs=xxx:yyy:zzz A colon separated list of optional attributes.
+// - key=xxx The path to a file containing a 16, 24 or 32 byte AES key used
+//   to encrypt the files of the store.
+// - old-key=xxx:yyy A colon separated list of paths to keys previously used
+//   to encrypt the store. Files encrypted with these keys are rewritten with
+//   the current key, or in plaintext if there is none, as they are
+//   compacted.
 // Note that commas are forbidden within any field name or value.
 func NewStoreSpec(value string) (StoreSpec, error) {
 	if len(value) == 0 {
@@ -202,6 +220,23 @@ func NewStoreSpec(value string) (StoreSpec, error) {
 				ss.Attributes.Attrs = append(ss.Attributes.Attrs, attribute)
 			}
 			sort.Strings(ss.Attributes.Attrs)
+		case "key":
+			var err error
+			ss.EncryptionKey, err = filepath.Abs(value)
+			if err != nil {
+				return StoreSpec{}, errors.Wrapf(err, "could not find absolute path for %s", value)
+			}
+		case "old-key":
+			for _, path := range strings.Split(value, ":") {
+				if len(path) == 0 {
+					return StoreSpec{}, fmt.Errorf("invalid old-key list: %s", value)
+				}
+				absPath, err := filepath.Abs(path)
+				if err != nil {
+					return StoreSpec{}, errors.Wrapf(err, "could not find absolute path for %s", path)
+				}
+				ss.OldEncryptionKeys = append(ss.OldEncryptionKeys, absPath)
+			}
 		case "type":
 			if value == "mem" {
 				ss.InMemory = true
@@ -220,6 +255,9 @@ func NewStoreSpec(value string) (StoreSpec, error) {
 		if ss.SizePercent == 0 && ss.SizeInBytes == 0 {
 			return StoreSpec{}, fmt.Errorf("size must be specified for an in memory store")
 		}
+		if ss.EncryptionKey != "" || len(ss.OldEncryptionKeys) > 0 {
+			return StoreSpec{}, fmt.Errorf("encryption keys specified for in memory store")
+		}
 	} else if ss.Path == "" {
 		return StoreSpec{}, fmt.Errorf("no path specified")
 	}
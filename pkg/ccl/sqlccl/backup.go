@@ -20,6 +20,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/build"
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl/intervalccl"
 	"github.com/cockroachdb/cockroach/pkg/gossip"
@@ -31,6 +32,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/interval"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -42,10 +45,18 @@ const (
 	// BackupDescriptorName is the file name used for serialized
 	// BackupDescriptor protos.
 	BackupDescriptorName = "BACKUP"
+	// BackupEncryptionInfoName is the file name used for the serialized
+	// EncryptionInfo of an encrypted backup. It is stored unencrypted.
+	BackupEncryptionInfoName = "BACKUP-ENCRYPTION"
 	// BackupFormatInitialVersion is the first version of backup and its files.
 	BackupFormatInitialVersion uint32 = 0
 )
 
+const (
+	backupOptRevisionHistory = "revision_history"
+	backupOptEncPassphrase   = "encryption_passphrase"
+)
+
 // exportStorageFromURI returns an ExportStorage for the given URI.
 func exportStorageFromURI(ctx context.Context, uri string) (storageccl.ExportStorage, error) {
 	conf, err := storageccl.ExportStorageConfFromURI(uri)
@@ -55,8 +66,11 @@ func exportStorageFromURI(ctx context.Context, uri string) (storageccl.ExportSto
 	return storageccl.MakeExportStorage(ctx, conf)
 }
 
-// readBackupDescriptor reads and unmarshals a BackupDescriptor from given base.
-func readBackupDescriptor(ctx context.Context, uri string) (BackupDescriptor, error) {
+// readBackupDescriptor reads and unmarshals a BackupDescriptor from given base,
+// decrypting it with encryptionKey if it is non-nil.
+func readBackupDescriptor(
+	ctx context.Context, uri string, encryptionKey []byte,
+) (BackupDescriptor, error) {
 	dir, err := exportStorageFromURI(ctx, uri)
 	if err != nil {
 		return BackupDescriptor{}, err
@@ -72,6 +86,15 @@ func readBackupDescriptor(ctx context.Context, uri string) (BackupDescriptor, er
 	if err != nil {
 		return BackupDescriptor{}, err
 	}
+	if encryptionKey != nil {
+		descBytes, err = storageccl.DecryptFile(descBytes, encryptionKey)
+		if err != nil {
+			return BackupDescriptor{}, err
+		}
+	} else if storageccl.AppearsEncrypted(descBytes) {
+		return BackupDescriptor{}, errors.Errorf(
+			"backup is encrypted, the %q option is required to read it", backupOptEncPassphrase)
+	}
 	var backupDesc BackupDescriptor
 	if err := backupDesc.Unmarshal(descBytes); err != nil {
 		return BackupDescriptor{}, err
@@ -82,16 +105,89 @@ func readBackupDescriptor(ctx context.Context, uri string) (BackupDescriptor, er
 	return backupDesc, nil
 }
 
+// readEncryptionInfo reads and unmarshals the EncryptionInfo of the encrypted
+// backup at the given base.
+func readEncryptionInfo(ctx context.Context, uri string) (EncryptionInfo, error) {
+	dir, err := exportStorageFromURI(ctx, uri)
+	if err != nil {
+		return EncryptionInfo{}, err
+	}
+	defer dir.Close()
+	r, err := dir.ReadFile(ctx, BackupEncryptionInfoName)
+	if err != nil {
+		return EncryptionInfo{}, errors.Wrapf(err, "reading %s (is the backup encrypted?)",
+			BackupEncryptionInfoName)
+	}
+	defer r.Close()
+	infoBytes, err := ioutil.ReadAll(r)
+	if err != nil {
+		return EncryptionInfo{}, err
+	}
+	var info EncryptionInfo
+	if err := info.Unmarshal(infoBytes); err != nil {
+		return EncryptionInfo{}, err
+	}
+	return info, nil
+}
+
+// backupEncryptionKey derives the key used to encrypt the files of a backup
+// from the encryption_passphrase option, returning nil if it is not set. The
+// salt is read from the existing backup at uri or, if uri is empty, newly
+// generated and returned in the EncryptionInfo to be stored with a new backup.
+func backupEncryptionKey(
+	ctx context.Context, opts parser.KVOptions, uri string,
+) ([]byte, *EncryptionInfo, error) {
+	passphrase, ok := opts.Get(backupOptEncPassphrase)
+	if !ok {
+		return nil, nil, nil
+	}
+	if passphrase == "" {
+		return nil, nil, errors.Errorf("option %q requires a value", backupOptEncPassphrase)
+	}
+	var info EncryptionInfo
+	if uri == "" {
+		salt, err := storageccl.GenerateSalt()
+		if err != nil {
+			return nil, nil, err
+		}
+		info.Salt = salt
+	} else {
+		var err error
+		if info, err = readEncryptionInfo(ctx, uri); err != nil {
+			return nil, nil, err
+		}
+	}
+	return storageccl.GenerateKey([]byte(passphrase), info.Salt), &info, nil
+}
+
+// redactBackupOptions returns opts with the value of the passphrase option
+// hidden, for use in job descriptions.
+func redactBackupOptions(opts parser.KVOptions) parser.KVOptions {
+	if _, ok := opts.Get(backupOptEncPassphrase); !ok {
+		return opts
+	}
+	redacted := make(parser.KVOptions, len(opts))
+	for i, opt := range opts {
+		if opt.Key == backupOptEncPassphrase {
+			opt.Value = "redacted"
+		}
+		redacted[i] = opt
+	}
+	return redacted
+}
+
 // ValidatePreviousBackups checks that the timestamps of previous backups are
 // consistent. The most recently backed-up time is returned.
-func ValidatePreviousBackups(ctx context.Context, uris []string) (hlc.Timestamp, error) {
+func ValidatePreviousBackups(
+	ctx context.Context, uris []string, encryptionKey []byte,
+) (hlc.Timestamp, error) {
 	if len(uris) == 0 || len(uris) == 1 && uris[0] == "" {
 		// Full backup.
 		return hlc.Timestamp{}, nil
 	}
 	backups := make([]BackupDescriptor, len(uris))
 	for i, uri := range uris {
-		desc, err := readBackupDescriptor(ctx, uri)
+		desc, err := readBackupDescriptor(ctx, uri, encryptionKey)
 		if err != nil {
 			return hlc.Timestamp{}, err
 		}
@@ -122,6 +218,106 @@ func allSQLDescriptors(ctx context.Context, txn *client.Txn) ([]sqlbase.Descript
 	return sqlDescs, nil
 }
 
+// getAllDescChanges returns every revision of the SQL descriptors written
+// between startTime and endTime. They are found by exporting all revisions of
+// the descriptor table into exportStore and reading the resulting files back.
+// The files are removed afterwards, since the descriptor table is exported
+// again along with the rest of the backup.
+func getAllDescChanges(
+	ctx context.Context,
+	db *client.DB,
+	exportStore storageccl.ExportStorage,
+	startTime, endTime hlc.Timestamp,
+	encryptionKey []byte,
+) ([]BackupDescriptor_DescriptorRevision, error) {
+	startKey := roachpb.Key(keys.MakeTablePrefix(keys.DescriptorTableID))
+	endKey := startKey.PrefixEnd()
+
+	header := roachpb.Header{Timestamp: endTime}
+	req := &roachpb.ExportRequest{
+		Span:          roachpb.Span{Key: startKey, EndKey: endKey},
+		Storage:       exportStore.Conf(),
+		StartTime:     startTime,
+		AllRevisions:  true,
+		EncryptionKey: encryptionKey,
+	}
+	res, pErr := client.SendWrappedWith(ctx, db.GetSender(), header, req)
+	if pErr != nil {
+		return nil, pErr.GoError()
+	}
+
+	var changes []BackupDescriptor_DescriptorRevision
+	for _, file := range res.(*roachpb.ExportResponse).Files {
+		fileChanges, err := readDescChanges(ctx, exportStore, file.Path, encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, fileChanges...)
+		if err := exportStore.Delete(ctx, file.Path); err != nil {
+			log.Warningf(ctx, "unable to remove temporary descriptor file %s: %+v", file.Path, err)
+		}
+	}
+	return changes, nil
+}
+
+// readDescChanges decodes the descriptor revisions in an exported file of the
+// descriptor table. A deleted descriptor is returned as a revision with a nil
+// Desc.
+func readDescChanges(
+	ctx context.Context, exportStore storageccl.ExportStorage, path string, encryptionKey []byte,
+) ([]BackupDescriptor_DescriptorRevision, error) {
+	r, err := exportStore.ReadFile(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	fileContents, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if encryptionKey != nil {
+		if fileContents, err = storageccl.DecryptFile(fileContents, encryptionKey); err != nil {
+			return nil, err
+		}
+	}
+	iter, err := engineccl.NewMemSSTIterator(fileContents)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var changes []BackupDescriptor_DescriptorRevision
+	for iter.Seek(engine.MVCCKey{Key: keys.MinKey}); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return nil, err
+		} else if !ok {
+			break
+		}
+		remaining, _, _, err := sqlbase.DecodeTableIDIndexID(iter.UnsafeKey().Key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "decoding descriptor key %s", iter.UnsafeKey())
+		}
+		_, id, err := encoding.DecodeUvarintAscending(remaining)
+		if err != nil {
+			return nil, errors.Wrapf(err, "decoding descriptor key %s", iter.UnsafeKey())
+		}
+		rev := BackupDescriptor_DescriptorRevision{
+			ID:   sqlbase.ID(id),
+			Time: iter.UnsafeKey().Timestamp,
+		}
+		if len(iter.UnsafeValue()) > 0 {
+			var desc sqlbase.Descriptor
+			value := roachpb.Value{RawBytes: iter.UnsafeValue()}
+			if err := value.GetProto(&desc); err != nil {
+				return nil, errors.Wrapf(err, "%s: unable to unmarshal SQL descriptor", iter.UnsafeKey())
+			}
+			rev.Desc = &desc
+		}
+		changes = append(changes, rev)
+	}
+	return changes, nil
+}
+
 func allRangeDescriptors(ctx context.Context, txn *client.Txn) ([]roachpb.RangeDescriptor, error) {
 	rows, err := txn.Scan(ctx, keys.Meta2Prefix, keys.MetaMax, 0)
 	if err != nil {
@@ -208,7 +404,7 @@ func backupJobDescription(
 ) (string, error) {
 	b := parser.Backup{
 		AsOf:    backup.AsOf,
-		Options: backup.Options,
+		Options: redactBackupOptions(backup.Options),
 		Targets: backup.Targets,
 	}
 
@@ -258,18 +454,32 @@ func (r backupFileDescriptors) Less(i, j int) bool {
 // - <dir> is given by the user and may be cloud storage
 // - Each file contains data for a key range that doesn't overlap with any other
 //   file.
+//
+// If encryption is set, every file, including the BackupDescriptor, is
+// encrypted with encryptionKey and the EncryptionInfo is written alongside
+// them so the key can be derived again on restore.
 func Backup(
 	ctx context.Context,
 	p sql.PlanHookState,
 	uri string,
 	targets parser.TargetList,
 	startTime, endTime hlc.Timestamp,
-	_ parser.KVOptions,
+	opts parser.KVOptions,
+	encryption *EncryptionInfo,
+	encryptionKey []byte,
 	jobLogger *jobs.JobLogger,
 ) (BackupDescriptor, error) {
 	// TODO(dan): Figure out how permissions should work. #6713 is tracking this
 	// for grpc.
 
+	var revisionHistory bool
+	if override, ok := opts.Get(backupOptRevisionHistory); ok {
+		if override != "" {
+			return BackupDescriptor{}, errors.Errorf("option %q does not take a value", backupOptRevisionHistory)
+		}
+		revisionHistory = true
+	}
+
 	var sqlDescs []sqlbase.Descriptor
 
 	exportStore, err := exportStorageFromURI(ctx, uri)
@@ -290,6 +500,16 @@ func Backup(
 		}
 	}
 
+	if encryption != nil {
+		infoBuf, err := encryption.Marshal()
+		if err != nil {
+			return BackupDescriptor{}, err
+		}
+		if err := exportStore.WriteFile(ctx, BackupEncryptionInfoName, bytes.NewReader(infoBuf)); err != nil {
+			return BackupDescriptor{}, err
+		}
+	}
+
 	db := p.ExecCfg().DB
 
 	{
@@ -334,6 +554,33 @@ func Backup(
 		}
 	}
 
+	// With revision history, the backup also records how the backed up
+	// descriptors changed between startTime and endTime, and covers the spans
+	// of every index the tables had in that interval, so that a restore to any
+	// time in it finds the data it needs.
+	var descChanges []BackupDescriptor_DescriptorRevision
+	spanTables := tables
+	if revisionHistory {
+		allChanges, err := getAllDescChanges(ctx, db, exportStore, startTime, endTime, encryptionKey)
+		if err != nil {
+			return BackupDescriptor{}, err
+		}
+		backedUp := make(map[sqlbase.ID]struct{}, len(sqlDescs))
+		for _, desc := range sqlDescs {
+			backedUp[desc.GetID()] = struct{}{}
+		}
+		spanTables = append([]*sqlbase.TableDescriptor(nil), tables...)
+		for _, change := range allChanges {
+			if _, ok := backedUp[change.ID]; !ok {
+				continue
+			}
+			descChanges = append(descChanges, change)
+			if tableDesc := change.Desc.GetTable(); tableDesc != nil {
+				spanTables = append(spanTables, tableDesc)
+			}
+		}
+	}
+
 	var ranges []roachpb.RangeDescriptor
 	if err := db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
 		var err error
@@ -348,7 +595,7 @@ func Backup(
 
 	// We split the spans into range-sized pieces so that we can use the number of
 	// completed requests as a rough measure of progress.
-	spans := splitSpansByRanges(spansForAllTableIndexes(tables), ranges)
+	spans := splitSpansByRanges(spansForAllTableIndexes(spanTables), ranges)
 
 	mu := struct {
 		syncutil.Mutex
@@ -404,9 +651,11 @@ func Backup(
 			defer func() { <-exportsSem }()
 
 			req := &roachpb.ExportRequest{
-				Span:      span,
-				Storage:   exportStore.Conf(),
-				StartTime: startTime,
+				Span:          span,
+				Storage:       exportStore.Conf(),
+				StartTime:     startTime,
+				AllRevisions:  revisionHistory,
+				EncryptionKey: encryptionKey,
 			}
 			res, pErr := client.SendWrappedWith(gCtx, db.GetSender(), header, req)
 			if pErr != nil {
@@ -438,16 +687,18 @@ func Backup(
 	files, summary := mu.files, mu.exported // No more concurrency, so this is safe.
 
 	desc := BackupDescriptor{
-		StartTime:     startTime,
-		EndTime:       endTime,
-		Descriptors:   sqlDescs,
-		Spans:         spans,
-		Files:         files,
-		EntryCounts:   summary,
-		FormatVersion: BackupFormatInitialVersion,
-		BuildInfo:     build.GetInfo(),
-		NodeID:        p.ExecCfg().NodeID.Get(),
-		ClusterID:     p.ExecCfg().ClusterID(),
+		StartTime:         startTime,
+		EndTime:           endTime,
+		Descriptors:       sqlDescs,
+		Spans:             spans,
+		Files:             files,
+		EntryCounts:       summary,
+		FormatVersion:     BackupFormatInitialVersion,
+		BuildInfo:         build.GetInfo(),
+		NodeID:            p.ExecCfg().NodeID.Get(),
+		ClusterID:         p.ExecCfg().ClusterID(),
+		RevisionHistory:   revisionHistory,
+		DescriptorChanges: descChanges,
 	}
 	sort.Sort(backupFileDescriptors(desc.Files))
 
@@ -455,6 +706,11 @@ func Backup(
 	if err != nil {
 		return BackupDescriptor{}, err
 	}
+	if encryptionKey != nil {
+		if descBuf, err = storageccl.EncryptFile(descBuf, encryptionKey); err != nil {
+			return BackupDescriptor{}, err
+		}
+	}
 
 	if err := exportStore.WriteFile(ctx, BackupDescriptorName, bytes.NewReader(descBuf)); err != nil {
 		return BackupDescriptor{}, err
@@ -510,10 +766,21 @@ func backupPlanHook(
 			return nil, err
 		}
 
+		// An incremental backup reuses the salt, and thus the key, of the
+		// backups it is incremental from.
+		var prevBackupURI string
+		if len(incrementalFrom) > 0 {
+			prevBackupURI = incrementalFrom[0]
+		}
+		encryptionKey, encryption, err := backupEncryptionKey(ctx, backup.Options, prevBackupURI)
+		if err != nil {
+			return nil, err
+		}
+
 		var startTime hlc.Timestamp
 		if backup.IncrementalFrom != nil {
 			var err error
-			startTime, err = ValidatePreviousBackups(ctx, incrementalFrom)
+			startTime, err = ValidatePreviousBackups(ctx, incrementalFrom, encryptionKey)
 			if err != nil {
 				return nil, err
 			}
@@ -540,6 +807,8 @@ func backupPlanHook(
 			backup.Targets,
 			startTime, endTime,
 			backup.Options,
+			encryption,
+			encryptionKey,
 			jobLogger,
 		)
 		if err != nil {
@@ -603,7 +872,11 @@ func showBackupPlanHook(
 		if err != nil {
 			return nil, err
 		}
-		desc, err := readBackupDescriptor(ctx, str)
+		encryptionKey, _, err := backupEncryptionKey(ctx, backup.Options, str)
+		if err != nil {
+			return nil, err
+		}
+		desc, err := readBackupDescriptor(ctx, str, encryptionKey)
 		if err != nil {
 			return nil, err
 		}
@@ -1163,6 +1163,79 @@ func TestBackupAsOfSystemTime(t *testing.T) {
 	}
 }
 
+func TestRestoreAsOfSystemTime(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 10
+
+	_, dir, _, sqlDB, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts)
+	defer cleanupFn()
+
+	fullBackup := filepath.Join(dir, "full")
+	incBackup := filepath.Join(dir, "inc")
+	noHistoryBackup := filepath.Join(dir, "nohistory")
+
+	var beforeUpdate, beforeAddColumn, afterBackups string
+
+	sqlDB.QueryRow(`SELECT cluster_logical_timestamp()`).Scan(&beforeUpdate)
+	beforeUpdateRows := sqlDB.QueryStr(`SELECT * FROM data.bank`)
+	sqlDB.Exec(`UPDATE data.bank SET balance = balance + 1`)
+	sqlDB.Exec(`BACKUP DATABASE data TO $1 WITH OPTIONS ('revision_history')`, fullBackup)
+	sqlDB.Exec(`BACKUP DATABASE data TO $1`, noHistoryBackup)
+
+	sqlDB.QueryRow(`SELECT cluster_logical_timestamp()`).Scan(&beforeAddColumn)
+	beforeAddColumnRows := sqlDB.QueryStr(`SELECT * FROM data.bank`)
+	sqlDB.Exec(`ALTER TABLE data.bank ADD COLUMN extra INT DEFAULT 7`)
+	sqlDB.Exec(`BACKUP DATABASE data TO $1 INCREMENTAL FROM $2 WITH OPTIONS ('revision_history')`,
+		incBackup, fullBackup)
+	afterBackupsRows := sqlDB.QueryStr(`SELECT * FROM data.bank`)
+	sqlDB.QueryRow(`SELECT cluster_logical_timestamp()`).Scan(&afterBackups)
+
+	restoreAsOf := func(intoDB string, ts string, from ...string) error {
+		sqlDB.Exec(fmt.Sprintf(`CREATE DATABASE %s`, intoDB))
+		_, err := sqlDB.DB.Exec(fmt.Sprintf(
+			`RESTORE data.bank FROM '%s' AS OF SYSTEM TIME %s WITH OPTIONS ('into_db'='%s')`,
+			strings.Join(from, `', '`), ts, intoDB,
+		))
+		return err
+	}
+
+	t.Run("within full backup", func(t *testing.T) {
+		if err := restoreAsOf("restored_full", beforeUpdate, fullBackup); err != nil {
+			t.Fatal(err)
+		}
+		sqlDB.CheckQueryResults(`SELECT * FROM restored_full.bank`, beforeUpdateRows)
+	})
+
+	t.Run("before schema change", func(t *testing.T) {
+		if err := restoreAsOf("restored_inc", beforeAddColumn, fullBackup, incBackup); err != nil {
+			t.Fatal(err)
+		}
+		sqlDB.CheckQueryResults(`SELECT * FROM restored_inc.bank`, beforeAddColumnRows)
+	})
+
+	t.Run("end of backups", func(t *testing.T) {
+		sqlDB.Exec(`CREATE DATABASE restored_latest`)
+		sqlDB.Exec(`RESTORE data.bank FROM $1, $2 WITH OPTIONS ('into_db'='restored_latest')`,
+			fullBackup, incBackup)
+		sqlDB.CheckQueryResults(`SELECT * FROM restored_latest.bank`, afterBackupsRows)
+	})
+
+	t.Run("without revision history", func(t *testing.T) {
+		err := restoreAsOf("restored_nohistory", beforeUpdate, noHistoryBackup)
+		if !testutils.IsError(err, "invalid RESTORE timestamp: backup covering .* was taken without") {
+			t.Fatalf("expected revision history error got: %v", err)
+		}
+	})
+
+	t.Run("after backups", func(t *testing.T) {
+		err := restoreAsOf("restored_after", afterBackups, fullBackup, incBackup)
+		if !testutils.IsError(err, "invalid RESTORE timestamp: supplied backups do not cover") {
+			t.Fatalf("expected coverage error got: %v", err)
+		}
+	})
+}
+
 func TestBackupRestoreChecksum(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -1448,6 +1521,159 @@ func TestRestoreInto(t *testing.T) {
 	sqlDB.CheckQueryResults(`SELECT * FROM data2.bank`, expected)
 }
 
+func TestRestoreAs(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 10
+	_, dir, _, sqlDB, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts)
+	defer cleanupFn()
+
+	sqlDB.Exec(`CREATE TABLE data.other (a INT PRIMARY KEY)`)
+	sqlDB.Exec(`INSERT INTO data.other VALUES (1), (2)`)
+	sqlDB.Exec(`BACKUP DATABASE data TO $1`, dir)
+	expected := sqlDB.QueryStr(`SELECT * FROM data.bank`)
+
+	t.Run("database", func(t *testing.T) {
+		// The live database is left alone while its backup is restored
+		// alongside it.
+		sqlDB.Exec(`RESTORE DATABASE data AS data_verify FROM $1`, dir)
+		sqlDB.CheckQueryResults(`SELECT * FROM data_verify.bank`, expected)
+		sqlDB.CheckQueryResults(`SELECT * FROM data_verify.other`, [][]string{{"1"}, {"2"}})
+		sqlDB.CheckQueryResults(`SELECT * FROM data.bank`, expected)
+
+		sqlDB.Exec(`INSERT INTO data_verify.other VALUES (3)`)
+		sqlDB.CheckQueryResults(`SELECT count(*) FROM data.other`, [][]string{{"2"}})
+
+		var description string
+		sqlDB.QueryRow(
+			`SELECT description FROM crdb_internal.jobs WHERE type = 'RESTORE' ORDER BY created DESC LIMIT 1`,
+		).Scan(&description)
+		if expected := fmt.Sprintf(`RESTORE DATABASE data AS data_verify FROM '%s'`, dir); description != expected {
+			t.Fatalf("expected description %q, got %q", expected, description)
+		}
+	})
+
+	t.Run("table", func(t *testing.T) {
+		sqlDB.Exec(`RESTORE TABLE data.bank AS bank_verify FROM $1`, dir)
+		sqlDB.CheckQueryResults(`SELECT * FROM data.bank_verify`, expected)
+
+		sqlDB.Exec(`CREATE DATABASE data2`)
+		sqlDB.Exec(`RESTORE data.bank AS bank2 FROM $1 WITH OPTIONS ('into_db'='data2')`, dir)
+		sqlDB.CheckQueryResults(`SELECT * FROM data2.bank2`, expected)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for i, tc := range []struct {
+			stmt     string
+			expected string
+		}{
+			{`RESTORE DATABASE data FROM $1`, `database "data" already exists`},
+			{`RESTORE DATABASE data AS data_verify FROM $1`, `database "data_verify" already exists`},
+			{`RESTORE DATABASE data, data2 AS d FROM $1`, `requires exactly one database`},
+			{`RESTORE DATABASE data AS d FROM $1 WITH OPTIONS ('into_db'='data2')`, `cannot use "into_db" option`},
+			{`RESTORE data.bank FROM $1`, `relation "bank" already exists`},
+			{`RESTORE data.bank AS other FROM $1`, `relation "other" already exists`},
+			{`RESTORE data.* AS t FROM $1`, `requires exactly one table`},
+		} {
+			if _, err := sqlDB.DB.Exec(tc.stmt, dir); !testutils.IsError(err, tc.expected) {
+				t.Errorf("%d: expected error %q, got %v", i, tc.expected, err)
+			}
+		}
+	})
+}
+
+func TestBackupRestoreEncrypted(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 10
+	_, dir, _, sqlDB, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts)
+	defer cleanupFn()
+
+	// The helper helpfully prefixes it, but we're going to do direct file IO.
+	rawDir := strings.TrimPrefix(dir, "nodelocal://")
+
+	fullBackup := filepath.Join(dir, "full")
+	incBackup := filepath.Join(dir, "inc")
+
+	sqlDB.Exec(`BACKUP DATABASE data TO $1 WITH OPTIONS ('encryption_passphrase'='abcdef')`, fullBackup)
+	sqlDB.Exec(`UPDATE data.bank SET balance = balance + 1`)
+	sqlDB.Exec(`BACKUP DATABASE data TO $1 INCREMENTAL FROM $2 WITH OPTIONS ('encryption_passphrase'='abcdef')`,
+		incBackup, fullBackup)
+	expected := sqlDB.QueryStr(`SELECT * FROM data.bank`)
+
+	t.Run("files are encrypted", func(t *testing.T) {
+		files, err := ioutil.ReadDir(filepath.Join(rawDir, "full"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range files {
+			if f.Name() == sqlccl.BackupEncryptionInfoName {
+				continue
+			}
+			contents, err := ioutil.ReadFile(filepath.Join(rawDir, "full", f.Name()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !storageccl.AppearsEncrypted(contents) {
+				t.Fatalf("expected %s to be encrypted", f.Name())
+			}
+		}
+	})
+
+	t.Run("show backup", func(t *testing.T) {
+		sqlDB.CheckQueryResults(fmt.Sprintf(
+			`SELECT "table" FROM [SHOW BACKUP '%s' WITH OPTIONS ('encryption_passphrase'='abcdef')]`,
+			fullBackup), [][]string{{"bank"}})
+		_, err := sqlDB.DB.Exec(`SHOW BACKUP $1`, fullBackup)
+		if !testutils.IsError(err, "backup is encrypted") {
+			t.Fatalf("expected encrypted backup error, got: %v", err)
+		}
+	})
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		sqlDB.Exec(`CREATE DATABASE restored_wrong`)
+		_, err := sqlDB.DB.Exec(`RESTORE data.bank FROM $1, $2
+			WITH OPTIONS ('into_db'='restored_wrong', 'encryption_passphrase'='wrong')`,
+			fullBackup, incBackup)
+		if !testutils.IsError(err, "incorrect passphrase") {
+			t.Fatalf("expected wrong passphrase error, got: %v", err)
+		}
+	})
+
+	t.Run("missing passphrase", func(t *testing.T) {
+		sqlDB.Exec(`CREATE DATABASE restored_missing`)
+		_, err := sqlDB.DB.Exec(`RESTORE data.bank FROM $1, $2 WITH OPTIONS ('into_db'='restored_missing')`,
+			fullBackup, incBackup)
+		if !testutils.IsError(err, "backup is encrypted") {
+			t.Fatalf("expected encrypted backup error, got: %v", err)
+		}
+	})
+
+	t.Run("unencrypted incremental", func(t *testing.T) {
+		_, err := sqlDB.DB.Exec(`BACKUP DATABASE data TO $1 INCREMENTAL FROM $2`,
+			filepath.Join(dir, "inc2"), fullBackup)
+		if !testutils.IsError(err, "backup is encrypted") {
+			t.Fatalf("expected encrypted backup error, got: %v", err)
+		}
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		sqlDB.Exec(`CREATE DATABASE restored`)
+		sqlDB.Exec(`RESTORE data.bank FROM $1, $2
+			WITH OPTIONS ('into_db'='restored', 'encryption_passphrase'='abcdef')`,
+			fullBackup, incBackup)
+		sqlDB.CheckQueryResults(`SELECT * FROM restored.bank`, expected)
+	})
+
+	t.Run("passphrase is redacted", func(t *testing.T) {
+		var count int
+		sqlDB.QueryRow(`SELECT count(*) FROM crdb_internal.jobs WHERE description LIKE '%abcdef%'`).Scan(&count)
+		if count != 0 {
+			t.Fatalf("expected passphrase to be redacted from %d job descriptions", count)
+		}
+	})
+}
+
 func TestBackupRestorePermissions(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
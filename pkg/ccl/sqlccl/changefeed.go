@@ -0,0 +1,809 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package sqlccl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/jobs"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+const changefeedOptResolvedInterval = "resolved_interval"
+
+var changefeedResolvedInterval = settings.RegisterDurationSetting(
+	"changefeed.experimental_resolved_interval",
+	"minimum interval between the resolved timestamps emitted by changefeeds",
+	time.Second,
+)
+
+// Sink is an abstraction for anything that a changefeed may emit into.
+type Sink interface {
+	// EmitRow emits a change to a row of the named table. The key is the JSON
+	// encoding of the row's primary key and the value is the JSON encoding of
+	// the row, or nil if the row was deleted. A change may be emitted more
+	// than once.
+	EmitRow(ctx context.Context, table string, key, value []byte, updated hlc.Timestamp) error
+	// EmitResolvedTimestamp emits a timestamp such that every change at or
+	// before it has already been emitted.
+	EmitResolvedTimestamp(ctx context.Context, resolved hlc.Timestamp) error
+	// Flush blocks until everything emitted so far has been written.
+	Flush(ctx context.Context) error
+	// Close releases the resources held by the sink.
+	Close() error
+}
+
+// testingChangefeedSink, if set, is used by changefeeds in place of the sink
+// named by their URI.
+var testingChangefeedSink Sink
+
+// TestingSetChangefeedSink makes changefeeds emit into sink instead of the
+// sink named by their URI, and returns a function restoring the default.
+func TestingSetChangefeedSink(sink Sink) func() {
+	saved := testingChangefeedSink
+	testingChangefeedSink = sink
+	return func() {
+		testingChangefeedSink = saved
+	}
+}
+
+// exportStorageSink is a Sink that writes newline-delimited JSON files to an
+// ExportStorage. Each Flush writes a new file, named after the last resolved
+// timestamp emitted, so the files sort in the order they were written.
+type exportStorageSink struct {
+	es       storageccl.ExportStorage
+	buf      bytes.Buffer
+	resolved hlc.Timestamp
+}
+
+var _ Sink = &exportStorageSink{}
+
+type changefeedRowMessage struct {
+	Table   string          `json:"table"`
+	Key     json.RawMessage `json:"key"`
+	Value   json.RawMessage `json:"value"`
+	Updated string          `json:"updated"`
+}
+
+type changefeedResolvedMessage struct {
+	Resolved string `json:"resolved"`
+}
+
+func (s *exportStorageSink) EmitRow(
+	_ context.Context, table string, key, value []byte, updated hlc.Timestamp,
+) error {
+	return s.appendLine(changefeedRowMessage{
+		Table:   table,
+		Key:     key,
+		Value:   value,
+		Updated: parser.TimestampToDecimal(updated).String(),
+	})
+}
+
+func (s *exportStorageSink) EmitResolvedTimestamp(_ context.Context, resolved hlc.Timestamp) error {
+	s.resolved = resolved
+	return s.appendLine(changefeedResolvedMessage{
+		Resolved: parser.TimestampToDecimal(resolved).String(),
+	})
+}
+
+func (s *exportStorageSink) appendLine(msg interface{}) error {
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	s.buf.Write(line)
+	s.buf.WriteByte('\n')
+	return nil
+}
+
+func (s *exportStorageSink) Flush(ctx context.Context) error {
+	if s.buf.Len() == 0 {
+		return nil
+	}
+	filename := fmt.Sprintf("%019d.%010d.ndjson", s.resolved.WallTime, s.resolved.Logical)
+	if err := s.es.WriteFile(ctx, filename, bytes.NewReader(s.buf.Bytes())); err != nil {
+		return err
+	}
+	s.buf.Reset()
+	return nil
+}
+
+func (s *exportStorageSink) Close() error {
+	return s.es.Close()
+}
+
+// ChangefeedEvent is a row change or a resolved timestamp emitted into a
+// channel sink.
+type ChangefeedEvent struct {
+	Table   string
+	Key     []byte
+	Value   []byte
+	Updated hlc.Timestamp
+	// Resolved is set, and the other fields are not, for resolved timestamps.
+	Resolved hlc.Timestamp
+}
+
+// channelSink is a Sink that sends everything emitted into it on a channel.
+type channelSink struct {
+	ch chan<- ChangefeedEvent
+}
+
+var _ Sink = channelSink{}
+
+// MakeChannelSink returns a Sink that sends everything emitted into it on ch.
+// It is intended for tests.
+func MakeChannelSink(ch chan<- ChangefeedEvent) Sink {
+	return channelSink{ch: ch}
+}
+
+func (s channelSink) send(ctx context.Context, e ChangefeedEvent) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case s.ch <- e:
+		return nil
+	}
+}
+
+func (s channelSink) EmitRow(
+	ctx context.Context, table string, key, value []byte, updated hlc.Timestamp,
+) error {
+	return s.send(ctx, ChangefeedEvent{Table: table, Key: key, Value: value, Updated: updated})
+}
+
+func (s channelSink) EmitResolvedTimestamp(ctx context.Context, resolved hlc.Timestamp) error {
+	return s.send(ctx, ChangefeedEvent{Resolved: resolved})
+}
+
+func (channelSink) Flush(context.Context) error { return nil }
+
+func (channelSink) Close() error { return nil }
+
+func makeChangefeedSink(ctx context.Context, sinkURI string) (Sink, error) {
+	if testingChangefeedSink != nil {
+		return testingChangefeedSink, nil
+	}
+	es, err := exportStorageFromURI(ctx, sinkURI)
+	if err != nil {
+		return nil, err
+	}
+	return &exportStorageSink{es: es}, nil
+}
+
+// changefeedResolvedIntervalOption returns the minimum interval between the
+// resolved timestamps emitted by a changefeed with the given options.
+func changefeedResolvedIntervalOption(opts map[string]string) (time.Duration, error) {
+	resolvedInterval := changefeedResolvedInterval.Get()
+	for key, value := range opts {
+		switch key {
+		case changefeedOptResolvedInterval:
+			var err error
+			resolvedInterval, err = time.ParseDuration(value)
+			if err != nil {
+				return 0, errors.Wrapf(err, "parsing %s", key)
+			}
+			if resolvedInterval <= 0 {
+				return 0, errors.Errorf("%s must be positive", key)
+			}
+		default:
+			return 0, errors.Errorf("unsupported option %q", key)
+		}
+	}
+	return resolvedInterval, nil
+}
+
+// checkChangefeedTable returns an error if changefeeds can't watch the table.
+func checkChangefeedTable(desc *sqlbase.TableDescriptor) error {
+	if !desc.IsTable() {
+		return errors.Errorf("CHANGEFEED cannot target %q, which is not a table", desc.Name)
+	}
+	if len(desc.PrimaryIndex.Interleave.Ancestors) > 0 || len(desc.PrimaryIndex.InterleavedBy) > 0 {
+		return errors.Errorf("CHANGEFEED does not support interleaved table %q", desc.Name)
+	}
+	// Each write to a row must carry the whole row.
+	if len(desc.Families) > 1 {
+		return errors.Errorf("CHANGEFEED does not support multiple column families (table %q)", desc.Name)
+	}
+	return nil
+}
+
+// datumToJSON returns the value with which a datum is encoded in the JSON
+// emitted by changefeeds.
+func datumToJSON(d parser.Datum) interface{} {
+	switch t := d.(type) {
+	case *parser.DBool:
+		return bool(*t)
+	case *parser.DInt:
+		return int64(*t)
+	case *parser.DFloat:
+		if f := float64(*t); !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return f
+		}
+	}
+	if d == parser.DNull {
+		return nil
+	}
+	return parser.AsStringWithFlags(d, parser.FmtBareStrings)
+}
+
+// changefeedTableReader decodes the rows written to a table.
+type changefeedTableReader struct {
+	desc    *sqlbase.TableDescriptor
+	fetcher sqlbase.RowFetcher
+	alloc   sqlbase.DatumAlloc
+	keyVals []sqlbase.EncDatum
+	keyDirs []encoding.Direction
+}
+
+func makeChangefeedTableReader(desc *sqlbase.TableDescriptor) (*changefeedTableReader, error) {
+	r := &changefeedTableReader{desc: desc}
+	colIdxMap := make(map[sqlbase.ColumnID]int, len(desc.Columns))
+	valNeededForCol := make([]bool, len(desc.Columns))
+	for i, c := range desc.Columns {
+		colIdxMap[c.ID] = i
+		valNeededForCol[i] = true
+	}
+	if err := r.fetcher.Init(
+		desc, colIdxMap, &desc.PrimaryIndex, false /* reverse */, false, /* isSecondaryIndex */
+		desc.Columns, valNeededForCol, false, /* returnRangeInfo */
+	); err != nil {
+		return nil, err
+	}
+	var colIDs []sqlbase.ColumnID
+	colIDs, r.keyDirs = desc.PrimaryIndex.FullColumnIDs()
+	var err error
+	r.keyVals, err = sqlbase.MakeEncodedKeyVals(desc, colIDs)
+	return r, err
+}
+
+// encodeRow returns the JSON encodings of the primary key and of the value of
+// the row written by kv. The value is nil if the write deleted the row.
+func (r *changefeedTableReader) encodeRow(
+	ctx context.Context, kv roachpb.KeyValue,
+) (key, value []byte, err error) {
+	if _, ok, err := sqlbase.DecodeIndexKey(
+		&r.alloc, r.desc, r.desc.PrimaryIndex.ID, r.keyVals, r.keyDirs, kv.Key,
+	); err != nil {
+		return nil, nil, err
+	} else if !ok {
+		return nil, nil, errors.Errorf("key %s does not belong to table %q", kv.Key, r.desc.Name)
+	}
+	keyJSON := make([]interface{}, len(r.keyVals))
+	for i := range r.keyVals {
+		if err := r.keyVals[i].EnsureDecoded(&r.alloc); err != nil {
+			return nil, nil, err
+		}
+		keyJSON[i] = datumToJSON(r.keyVals[i].Datum)
+	}
+	if key, err = json.Marshal(keyJSON); err != nil {
+		return nil, nil, err
+	}
+	if len(kv.Value.RawBytes) == 0 {
+		return key, nil, nil
+	}
+
+	// The table has a single column family, so the write carries the whole
+	// row.
+	if err := r.fetcher.StartScanFrom(ctx, []client.KeyValue{{Key: kv.Key, Value: &kv.Value}}); err != nil {
+		return nil, nil, err
+	}
+	row, err := r.fetcher.NextRowDecoded(ctx, false /* traceKV */)
+	if err != nil || row == nil {
+		return key, nil, err
+	}
+	valueJSON := make(map[string]interface{}, len(r.desc.Columns))
+	for i, col := range r.desc.Columns {
+		valueJSON[col.Name] = datumToJSON(row[i])
+	}
+	value, err = json.Marshal(valueJSON)
+	return key, value, err
+}
+
+// loadChangefeedTables returns a reader for each of the given tables, as they
+// are now. It fails if a table was dropped or can't be watched anymore.
+func loadChangefeedTables(
+	ctx context.Context, db *client.DB, tableIDs []sqlbase.ID,
+) (map[sqlbase.ID]*changefeedTableReader, error) {
+	var readers map[sqlbase.ID]*changefeedTableReader
+	err := db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		readers = make(map[sqlbase.ID]*changefeedTableReader, len(tableIDs))
+		for _, id := range tableIDs {
+			desc, err := sqlbase.GetTableDescFromID(ctx, txn, id)
+			if err != nil {
+				return err
+			}
+			if desc.Dropped() {
+				return errors.Errorf("table %q was dropped", desc.Name)
+			}
+			if err := checkChangefeedTable(desc); err != nil {
+				return err
+			}
+			if readers[id], err = makeChangefeedTableReader(desc); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return readers, err
+}
+
+// spanFrontier tracks the timestamp up to which each part of a set of spans
+// has been resolved.
+type spanFrontier struct {
+	// entries are sorted and don't overlap.
+	entries []spanFrontierEntry
+}
+
+type spanFrontierEntry struct {
+	span roachpb.Span
+	ts   hlc.Timestamp
+}
+
+// makeSpanFrontier returns a spanFrontier in which the given spans, which
+// must not overlap, are resolved up to ts.
+func makeSpanFrontier(ts hlc.Timestamp, spans ...roachpb.Span) *spanFrontier {
+	f := &spanFrontier{}
+	for _, span := range spans {
+		f.entries = append(f.entries, spanFrontierEntry{span: span, ts: ts})
+	}
+	sort.Slice(f.entries, func(i, j int) bool {
+		return f.entries[i].span.Key.Compare(f.entries[j].span.Key) < 0
+	})
+	return f
+}
+
+// Forward records that the tracked parts of span are resolved up to ts.
+func (f *spanFrontier) Forward(span roachpb.Span, ts hlc.Timestamp) {
+	var entries []spanFrontierEntry
+	add := func(e spanFrontierEntry) {
+		// Merge adjacent entries resolved up to the same timestamp.
+		if n := len(entries); n > 0 && entries[n-1].ts == e.ts &&
+			entries[n-1].span.EndKey.Equal(e.span.Key) {
+			entries[n-1].span.EndKey = e.span.EndKey
+			return
+		}
+		entries = append(entries, e)
+	}
+	for _, e := range f.entries {
+		if !e.span.Overlaps(span) || !e.ts.Less(ts) {
+			add(e)
+			continue
+		}
+		if e.span.Key.Compare(span.Key) < 0 {
+			add(spanFrontierEntry{span: roachpb.Span{Key: e.span.Key, EndKey: span.Key}, ts: e.ts})
+			e.span.Key = span.Key
+		}
+		var rest *spanFrontierEntry
+		if span.EndKey.Compare(e.span.EndKey) < 0 {
+			rest = &spanFrontierEntry{span: roachpb.Span{Key: span.EndKey, EndKey: e.span.EndKey}, ts: e.ts}
+			e.span.EndKey = span.EndKey
+		}
+		add(spanFrontierEntry{span: e.span, ts: ts})
+		if rest != nil {
+			add(*rest)
+		}
+	}
+	f.entries = entries
+}
+
+// Frontier returns the timestamp up to which all the spans are resolved.
+func (f *spanFrontier) Frontier() hlc.Timestamp {
+	var frontier hlc.Timestamp
+	for i, e := range f.entries {
+		if i == 0 || e.ts.Less(frontier) {
+			frontier = e.ts
+		}
+	}
+	return frontier
+}
+
+// changefeed is the state of a run of a CHANGEFEED job.
+type changefeed struct {
+	db               *client.DB
+	jobLogger        *jobs.JobLogger
+	sink             Sink
+	tableIDs         []sqlbase.ID
+	readers          map[sqlbase.ID]*changefeedTableReader
+	frontier         *spanFrontier
+	highwater        hlc.Timestamp
+	resolvedInterval time.Duration
+	lastResolved     time.Time
+}
+
+func (cf *changefeed) handleEvent(ctx context.Context, event *roachpb.RangeFeedEvent) error {
+	switch t := event.GetValue().(type) {
+	case *roachpb.RangeFeedValue:
+		return cf.emitRow(ctx, roachpb.KeyValue{Key: t.Key, Value: t.Value})
+	case *roachpb.RangeFeedCheckpoint:
+		cf.frontier.Forward(t.Span, t.ResolvedTS)
+		return cf.maybeEmitResolved(ctx)
+	}
+	return nil
+}
+
+// emitRow emits the change to a row written by kv.
+func (cf *changefeed) emitRow(ctx context.Context, kv roachpb.KeyValue) error {
+	// Writes at or below the highwater were emitted by a previous run, or
+	// before a range feed was restarted.
+	if !cf.highwater.Less(kv.Value.Timestamp) {
+		return nil
+	}
+	_, tableID, _, err := sqlbase.DecodeTableIDIndexID(kv.Key)
+	if err != nil {
+		return err
+	}
+	r, ok := cf.readers[tableID]
+	if !ok {
+		return errors.Errorf("key %s does not belong to a watched table", kv.Key)
+	}
+	key, value, err := r.encodeRow(ctx, kv)
+	if err != nil {
+		return err
+	}
+	return cf.sink.EmitRow(ctx, r.desc.Name, key, value, kv.Value.Timestamp)
+}
+
+// maybeEmitResolved emits the timestamp up to which all the watched tables
+// are resolved, and checkpoints it as the highwater, unless it hasn't
+// advanced or one was emitted less than the resolved interval ago.
+func (cf *changefeed) maybeEmitResolved(ctx context.Context) error {
+	resolved := cf.frontier.Frontier()
+	if !cf.highwater.Less(resolved) || timeutil.Since(cf.lastResolved) < cf.resolvedInterval {
+		return nil
+	}
+	// Reloading the tables notices if one was dropped, and decodes the next
+	// changes with the latest schema.
+	readers, err := loadChangefeedTables(ctx, cf.db, cf.tableIDs)
+	if err != nil {
+		return err
+	}
+	cf.readers = readers
+	if err := cf.sink.EmitResolvedTimestamp(ctx, resolved); err != nil {
+		return err
+	}
+	if err := cf.sink.Flush(ctx); err != nil {
+		return err
+	}
+	cf.highwater = resolved
+	cf.lastResolved = timeutil.Now()
+
+	// Checkpointing the highwater also notices if the job has been paused.
+	return cf.jobLogger.DetailProgressed(ctx, func(payload *jobs.JobPayload) float32 {
+		payload.GetChangefeed().Highwater = resolved
+		return 0
+	})
+}
+
+// runChangefeed runs, or continues, the given CHANGEFEED job, until it fails
+// or is paused. It establishes a range feed over the primary index of each
+// watched table from the job's highwater, emits the row carried by each write
+// it sends into the sink, and periodically emits and checkpoints the
+// timestamp up to which every range feed is resolved as the new highwater.
+//
+// Changes are delivered at least once: those emitted after the last
+// checkpoint are emitted again if the job is continued, and a change may be
+// emitted more than once if a range feed is restarted, for example because
+// its range split. The changes to a row are emitted in order, but the
+// changes to different rows aren't ordered by timestamp.
+func runChangefeed(
+	ctx context.Context, execCfg *sql.ExecutorConfig, jobLogger *jobs.JobLogger,
+) error {
+	ctx, span := tracing.ChildSpan(ctx, "runChangefeed")
+	defer tracing.FinishSpan(span)
+
+	details := jobLogger.Job.Details.(jobs.ChangefeedJobDetails)
+	resolvedInterval, err := changefeedResolvedIntervalOption(details.Options)
+	if err != nil {
+		return err
+	}
+	sink, err := makeChangefeedSink(ctx, details.SinkURI)
+	if err != nil {
+		return err
+	}
+	defer sink.Close()
+
+	readers, err := loadChangefeedTables(ctx, execCfg.DB, details.TableIDs)
+	if err != nil {
+		return err
+	}
+	spans := make([]roachpb.Span, 0, len(details.TableIDs))
+	for _, id := range details.TableIDs {
+		spans = append(spans, readers[id].desc.PrimaryIndexSpan())
+	}
+	cf := &changefeed{
+		db:               execCfg.DB,
+		jobLogger:        jobLogger,
+		sink:             sink,
+		tableIDs:         details.TableIDs,
+		readers:          readers,
+		frontier:         makeSpanFrontier(details.Highwater, spans...),
+		highwater:        details.Highwater,
+		resolvedInterval: resolvedInterval,
+		lastResolved:     timeutil.Now(),
+	}
+
+	g, gCtx := errgroup.WithContext(ctx)
+	eventCh := make(chan *roachpb.RangeFeedEvent, 128)
+	for _, span := range spans {
+		req := &roachpb.RangeFeedRequest{
+			Header: roachpb.Header{Timestamp: details.Highwater},
+			Span:   span,
+		}
+		g.Go(func() error {
+			return execCfg.DistSender.RangeFeed(gCtx, req, eventCh).GoError()
+		})
+	}
+	g.Go(func() error {
+		for {
+			select {
+			case event := <-eventCh:
+				if err := cf.handleEvent(gCtx, event); err != nil {
+					return err
+				}
+			case <-gCtx.Done():
+				return gCtx.Err()
+			}
+		}
+	})
+	return g.Wait()
+}
+
+// changefeedRun is a run of a CHANGEFEED job on this node.
+type changefeedRun struct {
+	cancel func()
+	done   chan struct{}
+}
+
+// changefeedRuns tracks the runs of CHANGEFEED jobs on this node, so that a
+// resumed job doesn't run concurrently with a previous run which hasn't
+// noticed that the job was paused yet.
+var changefeedRuns = struct {
+	syncutil.Mutex
+	m map[int64]*changefeedRun
+}{m: make(map[int64]*changefeedRun)}
+
+// startChangefeed runs the given CHANGEFEED job in the background, where it
+// doesn't depend on the session that started or resumed it. A previous run of
+// the job on this node is canceled, and waited for, first.
+func startChangefeed(execCfg *sql.ExecutorConfig, jobLogger *jobs.JobLogger) error {
+	jobID := *jobLogger.JobID()
+	ctx := execCfg.AmbientCtx.AnnotateCtx(context.Background())
+	runCtx, cancel := context.WithCancel(execCfg.Stopper.WithCancel(ctx))
+	run := &changefeedRun{cancel: cancel, done: make(chan struct{})}
+
+	changefeedRuns.Lock()
+	prev := changefeedRuns.m[jobID]
+	changefeedRuns.m[jobID] = run
+	changefeedRuns.Unlock()
+	if prev != nil {
+		prev.cancel()
+	}
+	finishRun := func() {
+		changefeedRuns.Lock()
+		if changefeedRuns.m[jobID] == run {
+			delete(changefeedRuns.m, jobID)
+		}
+		changefeedRuns.Unlock()
+		cancel()
+		close(run.done)
+	}
+
+	if err := execCfg.Stopper.RunAsyncTask(ctx, "changefeed", func(ctx context.Context) {
+		defer finishRun()
+		if prev != nil {
+			<-prev.done
+		}
+		err := runChangefeed(runCtx, execCfg, jobLogger)
+		if err := finishChangefeedJob(ctx, execCfg.Stopper, jobLogger, runCtx.Err() != nil, err); err != nil {
+			log.Infof(ctx, "CHANGEFEED job %d stopped: %s", jobID, err)
+		}
+	}); err != nil {
+		finishRun()
+		return err
+	}
+	return nil
+}
+
+// finishChangefeedJob records the outcome of a run of a CHANGEFEED job, which
+// only stops running when it fails, is paused or is canceled. A canceled run
+// doesn't fail the job: if the node is shutting down, the job is paused so
+// that it can be continued from its highwater with RESUME JOB, and otherwise
+// the run was canceled by a newer run of the job. The returned error, if any,
+// describes why the job stopped.
+func finishChangefeedJob(
+	ctx context.Context, stopper *stop.Stopper, jobLogger *jobs.JobLogger, canceled bool, err error,
+) error {
+	if canceled {
+		select {
+		case <-stopper.ShouldQuiesce():
+			if err := jobLogger.Paused(ctx); err != nil {
+				return errors.Wrap(err, "pausing job interrupted by shutdown")
+			}
+			return errors.New("job was paused by shutdown")
+		default:
+			return nil
+		}
+	}
+	// Checkpointing a paused job fails with an InvalidStatusError. The job's
+	// status can't be checked instead, as it may have been resumed since.
+	if _, ok := errors.Cause(err).(*jobs.InvalidStatusError); ok {
+		return errors.New("job was paused")
+	}
+	jobLogger.Failed(ctx, err)
+	return err
+}
+
+func changefeedJobDescription(
+	orig *parser.CreateChangefeed, sinkURI string,
+) (string, error) {
+	stmt := *orig
+	clean, err := storageccl.SanitizeExportStorageURI(sinkURI)
+	if err != nil {
+		return "", err
+	}
+	stmt.SinkURI = parser.NewDString(clean)
+	return stmt.String(), nil
+}
+
+func changefeedPlanHook(
+	stmt parser.Statement, p sql.PlanHookState,
+) (func(context.Context) ([]parser.Datums, error), sqlbase.ResultColumns, error) {
+	changefeedStmt, ok := stmt.(*parser.CreateChangefeed)
+	if !ok {
+		return nil, nil, nil
+	}
+	if err := utilccl.CheckEnterpriseEnabled(p.ExecCfg().ClusterID(), "CHANGEFEED"); err != nil {
+		return nil, nil, err
+	}
+
+	if err := p.RequireSuperUser("CREATE CHANGEFEED"); err != nil {
+		return nil, nil, err
+	}
+
+	if len(changefeedStmt.Targets.Databases) > 0 {
+		return nil, nil, errors.New("CHANGEFEED does not support DATABASE targets")
+	}
+
+	sinkURIFn, err := p.TypeAsString(changefeedStmt.SinkURI, "CREATE CHANGEFEED")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := sqlbase.ResultColumns{
+		{Name: "job_id", Typ: parser.TypeInt},
+	}
+	fn := func(ctx context.Context) ([]parser.Datums, error) {
+		// TODO(dan): Move this span into sql.
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer tracing.FinishSpan(span)
+
+		sinkURI, err := sinkURIFn()
+		if err != nil {
+			return nil, err
+		}
+
+		opts := make(map[string]string, len(changefeedStmt.Options))
+		for _, opt := range changefeedStmt.Options {
+			opts[opt.Key] = opt.Value
+		}
+		// Validate the options and the sink before creating the job.
+		if _, err := changefeedResolvedIntervalOption(opts); err != nil {
+			return nil, err
+		}
+		sink, err := makeChangefeedSink(ctx, sinkURI)
+		if err != nil {
+			return nil, err
+		}
+		if err := sink.Close(); err != nil {
+			return nil, err
+		}
+
+		// Changes are emitted from the time the changefeed is created.
+		highwater := p.ExecCfg().Clock.Now()
+		var sqlDescs []sqlbase.Descriptor
+		if err := p.ExecCfg().DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+			txn.SetFixedTimestamp(highwater)
+			var err error
+			sqlDescs, err = allSQLDescriptors(ctx, txn)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+		sqlDescs, err = descriptorsMatchingTargets(
+			p.EvalContext().Database, sqlDescs, changefeedStmt.Targets,
+		)
+		if err != nil {
+			return nil, err
+		}
+		var tableIDs []sqlbase.ID
+		for _, desc := range sqlDescs {
+			tableDesc := desc.GetTable()
+			if tableDesc == nil {
+				continue
+			}
+			if err := checkChangefeedTable(tableDesc); err != nil {
+				return nil, err
+			}
+			tableIDs = append(tableIDs, tableDesc.ID)
+		}
+		if len(tableIDs) == 0 {
+			return nil, errors.Errorf("no tables found: %s", parser.AsString(changefeedStmt.Targets))
+		}
+
+		description, err := changefeedJobDescription(changefeedStmt, sinkURI)
+		if err != nil {
+			return nil, err
+		}
+		jobLogger := jobs.NewJobLogger(p.ExecCfg().DB, sql.InternalExecutor{LeaseManager: p.LeaseMgr()}, jobs.JobRecord{
+			Description:   description,
+			Username:      p.User(),
+			DescriptorIDs: tableIDs,
+			Details: jobs.ChangefeedJobDetails{
+				TableIDs:  tableIDs,
+				SinkURI:   sinkURI,
+				Options:   opts,
+				Highwater: highwater,
+			},
+		})
+		if err := jobLogger.Created(ctx); err != nil {
+			return nil, err
+		}
+		if err := jobLogger.Started(ctx); err != nil {
+			return nil, err
+		}
+		if err := startChangefeed(p.ExecCfg(), jobLogger); err != nil {
+			jobLogger.Failed(ctx, err)
+			return nil, err
+		}
+		return []parser.Datums{{parser.NewDInt(parser.DInt(*jobLogger.JobID()))}}, nil
+	}
+	return fn, header, nil
+}
+
+// changefeedResumeHook continues a paused CHANGEFEED job from its highwater,
+// in the background.
+func changefeedResumeHook(
+	jobLogger *jobs.JobLogger, p sql.PlanHookState,
+) func(context.Context) error {
+	if _, ok := jobLogger.Job.Details.(jobs.ChangefeedJobDetails); !ok {
+		return nil
+	}
+	return func(context.Context) error {
+		return startChangefeed(p.ExecCfg(), jobLogger)
+	}
+}
+
+func init() {
+	sql.AddPlanHook(changefeedPlanHook)
+	sql.AddResumeHook(changefeedResumeHook)
+}
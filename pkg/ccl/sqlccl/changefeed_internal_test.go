@@ -0,0 +1,62 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package sqlccl
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestSpanFrontier(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	span := func(start, end string) roachpb.Span {
+		return roachpb.Span{Key: roachpb.Key(start), EndKey: roachpb.Key(end)}
+	}
+	ts := func(wallTime int64) hlc.Timestamp {
+		return hlc.Timestamp{WallTime: wallTime}
+	}
+	entries := func(f *spanFrontier) string {
+		var buf []string
+		for _, e := range f.entries {
+			buf = append(buf, fmt.Sprintf("[%s-%s)@%d", e.span.Key, e.span.EndKey, e.ts.WallTime))
+		}
+		return strings.Join(buf, " ")
+	}
+
+	f := makeSpanFrontier(ts(1), span("e", "g"), span("a", "d"))
+	for i, tc := range []struct {
+		span     roachpb.Span
+		ts       hlc.Timestamp
+		entries  string
+		frontier hlc.Timestamp
+	}{
+		{span("b", "c"), ts(3), `[a-b)@1 [b-c)@3 [c-d)@1 [e-g)@1`, ts(1)},
+		{span("a", "b"), ts(3), `[a-c)@3 [c-d)@1 [e-g)@1`, ts(1)},
+		{span("c", "d"), ts(2), `[a-c)@3 [c-d)@2 [e-g)@1`, ts(1)},
+		// Spans which aren't tracked are ignored.
+		{span("d", "f"), ts(4), `[a-c)@3 [c-d)@2 [e-f)@4 [f-g)@1`, ts(1)},
+		{span("f", "g"), ts(2), `[a-c)@3 [c-d)@2 [e-f)@4 [f-g)@2`, ts(2)},
+		// Timestamps never regress.
+		{span("a", "g"), ts(3), `[a-d)@3 [e-f)@4 [f-g)@3`, ts(3)},
+	} {
+		f.Forward(tc.span, tc.ts)
+		if actual := entries(f); actual != tc.entries {
+			t.Errorf("%d: expected entries %s, got %s", i, tc.entries, actual)
+		}
+		if actual := f.Frontier(); actual != tc.frontier {
+			t.Errorf("%d: expected frontier %s, got %s", i, tc.frontier, actual)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package sqlccl_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/sqlccl"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+// closeNotifyingSink is a Sink which signals every time it is closed, which
+// a changefeed does when a run of its job stops.
+type closeNotifyingSink struct {
+	sqlccl.Sink
+	closed chan<- struct{}
+}
+
+func (s closeNotifyingSink) Close() error {
+	s.closed <- struct{}{}
+	return s.Sink.Close()
+}
+
+// changefeedJobStatus returns the status of the given CHANGEFEED job.
+func changefeedJobStatus(sqlDB *sqlutils.SQLRunner, jobID int64) string {
+	var status string
+	sqlDB.QueryRow(`SELECT status FROM crdb_internal.jobs WHERE id = $1`, jobID).Scan(&status)
+	return status
+}
+
+func TestChangefeedChannelSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	events := make(chan sqlccl.ChangefeedEvent)
+	closed := make(chan struct{}, 1)
+	defer sqlccl.TestingSetChangefeedSink(closeNotifyingSink{
+		Sink: sqlccl.MakeChannelSink(events), closed: closed,
+	})()
+
+	ctx := context.Background()
+	tc := testcluster.StartTestCluster(t, singleNode, base.TestClusterArgs{})
+	defer tc.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(t, tc.Conns[0])
+	sqlDB.Exec(`CREATE DATABASE d`)
+	sqlDB.Exec(`CREATE TABLE d.foo (a INT PRIMARY KEY, b STRING)`)
+	sqlDB.Exec(`INSERT INTO d.foo VALUES (0, 'before')`)
+
+	// nextRows returns the next n row changes, checking that they are
+	// followed by a resolved timestamp covering them.
+	var resolved hlc.Timestamp
+	nextRows := func(t *testing.T, jobID int64, n int) []string {
+		var rows []string
+		for {
+			var e sqlccl.ChangefeedEvent
+			select {
+			case e = <-events:
+			case <-time.After(testutils.DefaultSucceedsSoonDuration):
+				t.Fatalf("no changes emitted by job with status %s", changefeedJobStatus(sqlDB, jobID))
+			}
+			if e.Resolved != (hlc.Timestamp{}) {
+				if e.Resolved.Less(resolved) {
+					t.Fatalf("resolved timestamp %s regressed from %s", e.Resolved, resolved)
+				}
+				resolved = e.Resolved
+				if len(rows) >= n {
+					return rows
+				}
+				continue
+			}
+			if !resolved.Less(e.Updated) {
+				t.Fatalf("row updated at %s, which is not after resolved %s", e.Updated, resolved)
+			}
+			rows = append(rows, fmt.Sprintf("%s %s -> %s", e.Table, e.Key, e.Value))
+		}
+	}
+	// waitForRunToStop drains the events emitted by the changefeed until the
+	// current run of its job stops.
+	waitForRunToStop := func() {
+		for {
+			select {
+			case <-events:
+			case <-closed:
+				return
+			}
+		}
+	}
+
+	// The changefeed runs in the background: the statement only returns the
+	// ID of its job.
+	var jobID int64
+	sqlDB.QueryRow(
+		`CREATE CHANGEFEED FOR TABLE d.foo INTO 'nodelocal:///unused' WITH OPTIONS ('resolved_interval'='10ms')`,
+	).Scan(&jobID)
+	// Checking the sink closes it.
+	<-closed
+	nextRows(t, jobID, 0)
+
+	sqlDB.Exec(`INSERT INTO d.foo VALUES (1, 'a')`)
+	sqlDB.Exec(`UPSERT INTO d.foo VALUES (1, 'b')`)
+	sqlDB.Exec(`DELETE FROM d.foo WHERE a = 1`)
+	sqlDB.Exec(`INSERT INTO d.foo VALUES (2, 'c')`)
+	sqlDB.Exec(`UPDATE d.foo SET b = 'd' WHERE a = 0`)
+	expected := []string{
+		`foo [1] -> {"a":1,"b":"a"}`,
+		`foo [1] -> {"a":1,"b":"b"}`,
+		`foo [1] -> `,
+		`foo [2] -> {"a":2,"b":"c"}`,
+		`foo [0] -> {"a":0,"b":"d"}`,
+	}
+	if rows := nextRows(t, jobID, len(expected)); strings.Join(rows, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("expected:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(rows, "\n"))
+	}
+	sqlDB.Exec(`PAUSE JOB $1`, jobID)
+	waitForRunToStop()
+	if status := changefeedJobStatus(sqlDB, jobID); status != "paused" {
+		t.Fatalf("expected paused job, got %s", status)
+	}
+
+	// A resumed changefeed continues from where it was paused.
+	sqlDB.Exec(`INSERT INTO d.foo VALUES (3, 'e')`)
+	sqlDB.Exec(`RESUME JOB $1`, jobID)
+	if rows := nextRows(t, jobID, 1); len(rows) != 1 || rows[0] != `foo [3] -> {"a":3,"b":"e"}` {
+		t.Fatalf("expected only the row inserted while paused, got %v", rows)
+	}
+
+	// Resuming the job before the previous run notices that it was paused
+	// cancels that run instead of failing the job.
+	sqlDB.Exec(`PAUSE JOB $1`, jobID)
+	sqlDB.Exec(`RESUME JOB $1`, jobID)
+	waitForRunToStop()
+	sqlDB.Exec(`INSERT INTO d.foo VALUES (4, 'f')`)
+	if rows := nextRows(t, jobID, 1); rows[len(rows)-1] != `foo [4] -> {"a":4,"b":"f"}` {
+		t.Fatalf("expected the row inserted after resuming, got %v", rows)
+	}
+	if status := changefeedJobStatus(sqlDB, jobID); status != "running" {
+		t.Fatalf("expected running job, got %s", status)
+	}
+	sqlDB.Exec(`PAUSE JOB $1`, jobID)
+	waitForRunToStop()
+}
+
+func TestChangefeedFileSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+
+	tc := testcluster.StartTestCluster(t, singleNode, base.TestClusterArgs{})
+	defer tc.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(t, tc.Conns[0])
+	sqlDB.Exec(`CREATE DATABASE d`)
+	sqlDB.Exec(`CREATE TABLE d.foo (a INT PRIMARY KEY, b STRING)`)
+	sqlDB.Exec(`CREATE VIEW d.v AS SELECT a FROM d.foo`)
+	sqlDB.Exec(`CREATE TABLE d.fams (a INT PRIMARY KEY, b STRING, FAMILY (a), FAMILY (b))`)
+
+	t.Run("files", func(t *testing.T) {
+		var jobID int64
+		sqlDB.QueryRow(fmt.Sprintf(
+			`CREATE CHANGEFEED FOR TABLE d.foo INTO 'nodelocal://%s' WITH OPTIONS ('resolved_interval'='10ms')`,
+			dir,
+		)).Scan(&jobID)
+		var description string
+		sqlDB.QueryRow(`SELECT description FROM crdb_internal.jobs WHERE id = $1`, jobID).Scan(&description)
+		if expected := fmt.Sprintf(`CREATE CHANGEFEED FOR TABLE d.foo INTO 'nodelocal://%s'`, dir); !strings.HasPrefix(description, expected) {
+			t.Fatalf("expected description to start with %q, got %q", expected, description)
+		}
+
+		sqlDB.Exec(`INSERT INTO d.foo VALUES (1, 'a')`)
+		testutils.SucceedsSoon(t, func() error {
+			files, err := filepath.Glob(filepath.Join(dir, "*.ndjson"))
+			if err != nil {
+				return err
+			}
+			var contents []string
+			for _, file := range files {
+				buf, err := ioutil.ReadFile(file)
+				if err != nil {
+					return err
+				}
+				contents = append(contents, string(buf))
+			}
+			all := strings.Join(contents, "")
+			if !strings.Contains(all, `{"table":"foo","key":[1],"value":{"a":1,"b":"a"},"updated":"`) {
+				return errors.Errorf("row not found in %d files", len(files))
+			}
+			if !strings.Contains(all, `{"resolved":"`) {
+				return errors.Errorf("resolved timestamp not found in %d files", len(files))
+			}
+			return nil
+		})
+
+		sqlDB.Exec(`PAUSE JOB $1`, jobID)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for i, tc := range []struct {
+			stmt     string
+			expected string
+		}{
+			{`CREATE CHANGEFEED FOR DATABASE d INTO 'nodelocal:///x'`, `does not support DATABASE targets`},
+			{`CREATE CHANGEFEED FOR TABLE d.v INTO 'nodelocal:///x'`, `which is not a table`},
+			{`CREATE CHANGEFEED FOR TABLE d.nope INTO 'nodelocal:///x'`, `table "nope" does not exist`},
+			{`CREATE CHANGEFEED FOR TABLE d.foo INTO 'nodelocal:///x' WITH OPTIONS ('foo'='bar')`, `unsupported option "foo"`},
+			{`CREATE CHANGEFEED FOR TABLE d.fams INTO 'nodelocal:///x'`, `does not support multiple column families`},
+			{`CREATE CHANGEFEED FOR TABLE d.foo INTO 'nodelocal:///x' WITH OPTIONS ('resolved_interval'='-1s')`, `resolved_interval must be positive`},
+		} {
+			if _, err := sqlDB.DB.Exec(tc.stmt); !testutils.IsError(err, tc.expected) {
+				t.Errorf("%d: expected error %q, got %v", i, tc.expected, err)
+			}
+		}
+	})
+}
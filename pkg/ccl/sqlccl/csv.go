@@ -0,0 +1,561 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package sqlccl
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
+	"github.com/cockroachdb/cockroach/pkg/sql/jobs"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+// csvKVTypes are the types of the rows exchanged between the readCSV and
+// sstWriter processors: the key and value of a KV.
+var csvKVTypes = []sqlbase.ColumnType{
+	{SemanticType: sqlbase.ColumnType_BYTES},
+	{SemanticType: sqlbase.ColumnType_BYTES},
+}
+
+// importProgress reports the progress of a processor's share of an IMPORT job.
+// Each processor owns a slot in the job's ImportJobDetails.Progress, in which
+// it records its completed fraction weighted by its contribution; the job's
+// fraction completed is the sum of all the slots.
+type importProgress struct {
+	spec           distsqlrun.JobProgress
+	jobLogger      *jobs.JobLogger
+	lastReportedAt time.Time
+}
+
+func makeImportProgress(
+	ctx context.Context, flowCtx *distsqlrun.FlowCtx, spec distsqlrun.JobProgress,
+) (importProgress, error) {
+	p := importProgress{spec: spec}
+	if spec.JobID == 0 {
+		// Not running as part of a job.
+		return p, nil
+	}
+	var err error
+	p.jobLogger, err = jobs.GetJobLogger(ctx, flowCtx.ClientDB(), flowCtx.SQLExecutor(), spec.JobID)
+	return p, err
+}
+
+// report records that the given fraction of the processor's work is complete.
+// Unless force is set, updates more frequent than progressTimeThreshold are
+// skipped. Reporting progress is also how processors notice that their job was
+// paused, in which case an error is returned; other errors are only logged.
+func (p *importProgress) report(ctx context.Context, fraction float32, force bool) error {
+	if p.jobLogger == nil {
+		return nil
+	}
+	if !force && timeutil.Since(p.lastReportedAt) < progressTimeThreshold {
+		return nil
+	}
+	p.lastReportedAt = timeutil.Now()
+	err := p.jobLogger.DetailProgressed(ctx, func(payload *jobs.JobPayload) float32 {
+		details := payload.Details.(*jobs.JobPayload_Import).Import
+		for len(details.Progress) <= int(p.spec.Slot) {
+			details.Progress = append(details.Progress, 0)
+		}
+		details.Progress[p.spec.Slot] = p.spec.Contribution * fraction
+		var total float32
+		for _, f := range details.Progress {
+			total += f
+		}
+		if total > 1 {
+			total = 1
+		}
+		return total
+	})
+	if _, ok := err.(*jobs.InvalidStatusError); ok {
+		return err
+	}
+	if err != nil {
+		log.Errorf(ctx, "IMPORT ignoring error while updating progress on job %d: %+v",
+			p.spec.JobID, err)
+	}
+	return nil
+}
+
+// readCSV is a processor that reads CSV files and converts their rows into
+// the KVs of the table they are imported into. In sampling mode, only a random
+// sample of the keys is emitted.
+type readCSV struct {
+	flowCtx *distsqlrun.FlowCtx
+	spec    distsqlrun.ReadCSVSpec
+	output  distsqlrun.RowReceiver
+}
+
+var _ distsqlrun.Processor = &readCSV{}
+
+func newReadCSVProcessor(
+	flowCtx *distsqlrun.FlowCtx, spec distsqlrun.ReadCSVSpec, output distsqlrun.RowReceiver,
+) (distsqlrun.Processor, error) {
+	return &readCSV{
+		flowCtx: flowCtx,
+		spec:    spec,
+		output:  output,
+	}, nil
+}
+
+// errConsumerDone is returned by readCSV.run when the consumer doesn't need
+// any more rows.
+var errConsumerDone = errors.New("consumer done")
+
+// Run is part of the distsqlrun.Processor interface.
+func (cp *readCSV) Run(ctx context.Context, wg *sync.WaitGroup) {
+	if wg != nil {
+		defer wg.Done()
+	}
+	ctx, span := tracing.ChildSpan(ctx, "readCSV")
+	defer tracing.FinishSpan(span)
+
+	err := cp.run(ctx)
+	if err == errConsumerDone {
+		err = nil
+	}
+	distsqlrun.DrainAndClose(ctx, cp.output, err)
+}
+
+func (cp *readCSV) run(ctx context.Context) error {
+	progress, err := makeImportProgress(ctx, cp.flowCtx, cp.spec.Progress)
+	if err != nil {
+		return err
+	}
+
+	// Default expressions are evaluated as of the time of the IMPORT, so that a
+	// resumed import produces the same KVs.
+	evalCtx := *cp.flowCtx.EvalCtx()
+	ts := time.Unix(0, cp.spec.Walltime).UTC()
+	evalCtx.SetTxnTimestamp(ts)
+	evalCtx.SetStmtTimestamp(ts)
+
+	tableDesc := &cp.spec.TableDesc
+	visibleCols := tableDesc.VisibleColumns()
+	cols, defaultExprs, err := sqlbase.ProcessDefaultColumns(
+		visibleCols, tableDesc, &parser.Parser{}, &evalCtx,
+	)
+	if err != nil {
+		return errors.Wrap(err, "process default columns")
+	}
+	ri, err := sqlbase.MakeRowInserter(nil /* txn */, tableDesc, nil /* fkTables */, cols, false /* checkFKs */)
+	if err != nil {
+		return errors.Wrap(err, "make row inserter")
+	}
+	defer ri.Close(ctx)
+	// The hidden rowid column, if any, is filled in deterministically from the
+	// position of the row in the input instead of with unique_rowid().
+	rowIDIdx := -1
+	for i := len(visibleCols); i < len(cols); i++ {
+		if cols[i].Hidden {
+			rowIDIdx = i
+		}
+	}
+
+	// Process the files in a deterministic order.
+	fileIndexes := make([]int, 0, len(cp.spec.URI))
+	for i := range cp.spec.URI {
+		fileIndexes = append(fileIndexes, int(i))
+	}
+	sort.Ints(fileIndexes)
+
+	var alloc sqlbase.DatumAlloc
+	emptyValue := parser.NewDBytes("")
+	var kvErr error
+	b := inserter(func(kv roachpb.KeyValue) {
+		if kvErr != nil {
+			return
+		}
+		kv.Value.InitChecksum(kv.Key)
+		value := parser.Datum(emptyValue)
+		if cp.spec.SampleSize > 0 {
+			// Emit a key with a probability proportional to the size of its KV.
+			sz := int64(len(kv.Key) + len(kv.Value.RawBytes))
+			if rand.Int63n(cp.spec.SampleSize) >= sz {
+				return
+			}
+		} else {
+			value = parser.NewDBytes(parser.DBytes(kv.Value.RawBytes))
+		}
+		row := sqlbase.EncDatumRow{
+			sqlbase.DatumToEncDatum(csvKVTypes[0], alloc.NewDBytes(parser.DBytes(kv.Key))),
+			sqlbase.DatumToEncDatum(csvKVTypes[1], value),
+		}
+		if cp.output.Push(row, distsqlrun.ProducerMetadata{}) != distsqlrun.NeedMoreRows {
+			kvErr = errConsumerDone
+		}
+	})
+
+	for n, fileIndex := range fileIndexes {
+		uri := cp.spec.URI[int32(fileIndex)]
+		log.VEventf(ctx, 1, "reading %s", uri)
+		err := cp.readFile(ctx, uri, func(rowNum int64, record []string) error {
+			row := make(parser.Datums, len(record))
+			for i, field := range record {
+				if cp.spec.Nullif != nil && field == *cp.spec.Nullif {
+					row[i] = parser.DNull
+					continue
+				}
+				var err error
+				row[i], err = parser.ParseStringAs(visibleCols[i].Type.ToDatumType(), field, time.UTC)
+				if err != nil {
+					return errors.Wrapf(err, "%s: row %d: parse %q", uri, rowNum, visibleCols[i].Name)
+				}
+			}
+			row, err := sql.GenerateInsertRow(
+				defaultExprs, ri.InsertColIDtoRowIndex, cols, evalCtx, tableDesc, row,
+			)
+			if err != nil {
+				return errors.Wrapf(err, "%s: row %d", uri, rowNum)
+			}
+			if rowIDIdx >= 0 {
+				// Row numbers are below 2^40 for any realistic file.
+				row[rowIDIdx] = parser.NewDInt(parser.DInt(int64(fileIndex)<<40 | rowNum))
+			}
+			if err := ri.InsertRow(ctx, b, row, true /* ignoreConflicts */, false /* traceKV */); err != nil {
+				return errors.Wrapf(err, "%s: row %d", uri, rowNum)
+			}
+			if kvErr != nil {
+				return kvErr
+			}
+			return progress.report(ctx, float32(n)/float32(len(fileIndexes)), false /* force */)
+		})
+		if err != nil {
+			return err
+		}
+		if err := progress.report(ctx, float32(n+1)/float32(len(fileIndexes)), true /* force */); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readFile calls fn for every record of the CSV file at uri. Row numbers start
+// at 1.
+func (cp *readCSV) readFile(
+	ctx context.Context, uri string, fn func(rowNum int64, record []string) error,
+) error {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return err
+	}
+	basename := path.Base(parsed.Path)
+	parsed.Path = path.Dir(parsed.Path)
+	es, err := exportStorageFromURI(ctx, parsed.String())
+	if err != nil {
+		return err
+	}
+	defer es.Close()
+	f, err := es.ReadFile(ctx, basename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	if cp.spec.Comma != 0 {
+		r.Comma = cp.spec.Comma
+	}
+	r.Comment = cp.spec.Comment
+	r.FieldsPerRecord = len(cp.spec.TableDesc.VisibleColumns())
+	for rowNum := int64(1); ; rowNum++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "%s: row %d", uri, rowNum)
+		}
+		if err := fn(rowNum, record); err != nil {
+			return err
+		}
+	}
+}
+
+// sstWriter is a processor that collects the KVs produced by readCSV
+// processors, sorts them, and ingests them into its spans as SSTables.
+type sstWriter struct {
+	flowCtx *distsqlrun.FlowCtx
+	spec    distsqlrun.SSTWriterSpec
+	input   distsqlrun.RowSource
+	output  distsqlrun.RowReceiver
+}
+
+var _ distsqlrun.Processor = &sstWriter{}
+
+func newSSTWriterProcessor(
+	flowCtx *distsqlrun.FlowCtx,
+	spec distsqlrun.SSTWriterSpec,
+	input distsqlrun.RowSource,
+	output distsqlrun.RowReceiver,
+) (distsqlrun.Processor, error) {
+	return &sstWriter{
+		flowCtx: flowCtx,
+		spec:    spec,
+		input:   input,
+		output:  output,
+	}, nil
+}
+
+// Run is part of the distsqlrun.Processor interface.
+func (sp *sstWriter) Run(ctx context.Context, wg *sync.WaitGroup) {
+	if wg != nil {
+		defer wg.Done()
+	}
+	ctx, span := tracing.ChildSpan(ctx, "sstWriter")
+	defer tracing.FinishSpan(span)
+
+	err := sp.run(ctx)
+	if err == errConsumerDone {
+		err = nil
+	}
+	distsqlrun.DrainAndClose(ctx, sp.output, err, sp.input)
+}
+
+// tempEngineCacheSize is the size of the block cache of the temporary engine
+// used to sort KVs.
+const tempEngineCacheSize = 64 << 20 // 64 MiB
+
+// makeTempEngine returns an engine for the KVs to be sorted, on disk in a
+// directory under dir if dir is set, and in memory otherwise.
+func makeTempEngine(dir string) (engine.Engine, func(), error) {
+	if dir == "" {
+		eng := engine.NewInMem(roachpb.Attributes{}, tempEngineCacheSize)
+		return eng, eng.Close, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, nil, err
+	}
+	tempDir, err := ioutil.TempDir(dir, "import")
+	if err != nil {
+		return nil, nil, err
+	}
+	cache := engine.NewRocksDBCache(tempEngineCacheSize)
+	defer cache.Release()
+	eng, err := engine.NewRocksDB(
+		roachpb.Attributes{}, tempDir, cache, 0 /* maxSize */, engine.DefaultMaxOpenFiles,
+	)
+	if err != nil {
+		_ = os.RemoveAll(tempDir)
+		return nil, nil, err
+	}
+	return eng, func() {
+		eng.Close()
+		if err := os.RemoveAll(tempDir); err != nil {
+			log.Warningf(context.TODO(), "could not remove %s: %v", tempDir, err)
+		}
+	}, nil
+}
+
+// sortBatchSize is the size at which the batch of KVs being written into the
+// temporary engine is committed.
+const sortBatchSize = 4 << 20 // 4 MiB
+
+func (sp *sstWriter) run(ctx context.Context) error {
+	progress, err := makeImportProgress(ctx, sp.flowCtx, sp.spec.Progress)
+	if err != nil {
+		return err
+	}
+
+	eng, cleanup, err := makeTempEngine(sp.flowCtx.TempStorageDir())
+	if err != nil {
+		return errors.Wrap(err, "creating temporary engine")
+	}
+	defer cleanup()
+
+	// Collect all the KVs into the temporary engine, which sorts them. Each KV
+	// is stored with a distinct sequence number as its timestamp, so that
+	// duplicate keys can be detected below.
+	var totalBytes int64
+	{
+		input := distsqlrun.MakeNoMetadataRowSource(sp.input, sp.output)
+		var alloc sqlbase.DatumAlloc
+		batch := eng.NewWriteOnlyBatch()
+		defer func() { batch.Close() }()
+		var batchBytes int64
+		for seq := int64(1); ; seq++ {
+			row, err := input.NextRow()
+			if err != nil {
+				return err
+			}
+			if row == nil {
+				break
+			}
+			if len(row) != 2 {
+				return errors.Errorf("expected 2 columns, got %d", len(row))
+			}
+			for i := range row {
+				if err := row[i].EnsureDecoded(&alloc); err != nil {
+					return err
+				}
+			}
+			key := *row[0].Datum.(*parser.DBytes)
+			value := *row[1].Datum.(*parser.DBytes)
+			mvccKey := engine.MVCCKey{Key: roachpb.Key(key), Timestamp: hlc.Timestamp{WallTime: seq}}
+			if err := batch.Put(mvccKey, []byte(value)); err != nil {
+				return err
+			}
+			batchBytes += int64(len(key) + len(value))
+			totalBytes += int64(len(key) + len(value))
+			if batchBytes >= sortBatchSize {
+				if err := batch.Commit(false /* sync */); err != nil {
+					return err
+				}
+				batch.Close()
+				batch = eng.NewWriteOnlyBatch()
+				batchBytes = 0
+			}
+		}
+		if err := batch.Commit(false /* sync */); err != nil {
+			return err
+		}
+	}
+
+	ts := hlc.Timestamp{WallTime: sp.spec.Walltime}
+	var writtenBytes int64
+	iter := eng.NewIterator(false /* prefix */)
+	defer iter.Close()
+	for _, span := range sp.spec.Spans {
+		var counts storageccl.RowCounter
+		sst, err := engine.MakeRocksDBSstFileWriter()
+		if err != nil {
+			return err
+		}
+		var firstKey, lastKey roachpb.Key
+		flush := func() error {
+			if firstKey == nil {
+				return nil
+			}
+			data, err := sst.Finish()
+			if err != nil {
+				return errors.Wrap(err, "finishing constructed sstable")
+			}
+			counts.DataSize += sst.DataSize
+			writtenBytes += sst.DataSize
+			sst.Close()
+			if err := addSSTable(ctx, sp.flowCtx.ClientDB(), firstKey, lastKey.Next(), data); err != nil {
+				return err
+			}
+			firstKey = nil
+			sst, err = engine.MakeRocksDBSstFileWriter()
+			if err != nil {
+				return err
+			}
+			var fraction float32 = 1
+			if totalBytes > 0 {
+				fraction = float32(writtenBytes) / float32(totalBytes)
+			}
+			return progress.report(ctx, fraction, false /* force */)
+		}
+
+		for iter.Seek(engine.MakeMVCCMetadataKey(span.Key)); ; iter.Next() {
+			if ok, err := iter.Valid(); err != nil {
+				sst.Close()
+				return err
+			} else if !ok {
+				break
+			}
+			key := iter.UnsafeKey().Key
+			if key.Compare(span.EndKey) >= 0 {
+				break
+			}
+			if lastKey != nil && key.Equal(lastKey) {
+				sst.Close()
+				return errors.Errorf("duplicate key: %s", key)
+			}
+			if firstKey != nil && sst.DataSize >= sp.spec.SSTSize {
+				// Don't split the KVs of a row across SSTables.
+				if row, err := keys.EnsureSafeSplitKey(key); err != nil || !bytes.HasPrefix(lastKey, row) {
+					if err := flush(); err != nil {
+						sst.Close()
+						return err
+					}
+				}
+			}
+			lastKey = append(lastKey[:0], key...)
+			if firstKey == nil {
+				firstKey = append(roachpb.Key(nil), key...)
+			}
+			if err := counts.Count(lastKey); err != nil {
+				sst.Close()
+				return err
+			}
+			kv := engine.MVCCKeyValue{
+				Key:   engine.MVCCKey{Key: lastKey, Timestamp: ts},
+				Value: iter.UnsafeValue(),
+			}
+			if err := sst.Add(kv); err != nil {
+				sst.Close()
+				return errors.Wrapf(err, "adding key %s", key)
+			}
+		}
+		err = flush()
+		sst.Close()
+		if err != nil {
+			return err
+		}
+
+		row := sqlbase.EncDatumRow{
+			sqlbase.DatumToEncDatum(sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT}, parser.NewDInt(parser.DInt(counts.Rows))),
+			sqlbase.DatumToEncDatum(sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT}, parser.NewDInt(parser.DInt(counts.IndexEntries))),
+			sqlbase.DatumToEncDatum(sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT}, parser.NewDInt(parser.DInt(counts.DataSize))),
+		}
+		if sp.output.Push(row, distsqlrun.ProducerMetadata{}) != distsqlrun.NeedMoreRows {
+			return errConsumerDone
+		}
+	}
+	return progress.report(ctx, 1, true /* force */)
+}
+
+// addSSTable ingests an SSTable covering [start, end), retrying on ambiguous
+// results. Ingesting the same SSTable several times is harmless.
+func addSSTable(ctx context.Context, db *client.DB, start, end roachpb.Key, data []byte) error {
+	const maxAddSSTableRetries = 10
+	for i := 0; ; i++ {
+		log.VEventf(ctx, 2, "addsstable [%s,%s)", start, end)
+		// TODO(dan): This will fail if the range has split.
+		err := db.ExperimentalAddSSTable(ctx, start, end, data)
+		if err == nil {
+			return nil
+		}
+		if _, ok := err.(*roachpb.AmbiguousResultError); i == maxAddSSTableRetries || !ok {
+			return errors.Wrapf(err, "addsstable [%s,%s)", start, end)
+		}
+		log.Warningf(ctx, "addsstable [%s,%s) attempt %d failed: %+v", start, end, i, err)
+	}
+}
+
+func init() {
+	distsqlrun.NewReadCSVProcessor = newReadCSVProcessor
+	distsqlrun.NewSSTWriterProcessor = newSSTWriterProcessor
+}
@@ -0,0 +1,267 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package sqlccl
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+const (
+	exportOptionDelimiter = "delimiter"
+	exportOptionNullAs    = "nullas"
+	exportOptionChunkRows = "chunk_rows"
+)
+
+// exportChunkRowsDefault is the default maximum number of rows per exported
+// file.
+const exportChunkRowsDefault = 100000
+
+// makeCSVWriterSpec returns the template of the CSVWriter processors that
+// write the files of an EXPORT to the given destination.
+func makeCSVWriterSpec(
+	destination string, opts parser.KVOptions,
+) (distsqlrun.CSVWriterSpec, error) {
+	spec := distsqlrun.CSVWriterSpec{
+		Destination: destination,
+		ChunkRows:   exportChunkRowsDefault,
+	}
+	for _, opt := range opts {
+		var err error
+		switch opt.Key {
+		case exportOptionDelimiter:
+			spec.Delimiter, err = parseRuneOption(opt.Key, opt.Value)
+		case exportOptionNullAs:
+			nullAs := opt.Value
+			spec.NullEncoding = &nullAs
+		case exportOptionChunkRows:
+			spec.ChunkRows, err = strconv.ParseInt(opt.Value, 10, 64)
+			if err == nil && spec.ChunkRows <= 0 {
+				err = errors.Errorf("%s must be positive", opt.Key)
+			}
+		default:
+			err = errors.Errorf("unsupported option %q", opt.Key)
+		}
+		if err != nil {
+			return distsqlrun.CSVWriterSpec{}, err
+		}
+	}
+	return spec, nil
+}
+
+func exportPlanHook(
+	stmt parser.Statement, p sql.PlanHookState,
+) (func(context.Context) ([]parser.Datums, error), sqlbase.ResultColumns, error) {
+	exportStmt, ok := stmt.(*parser.Export)
+	if !ok {
+		return nil, nil, nil
+	}
+	if err := utilccl.CheckEnterpriseEnabled(p.ExecCfg().ClusterID(), "EXPORT"); err != nil {
+		return nil, nil, err
+	}
+
+	if err := p.RequireSuperUser("EXPORT"); err != nil {
+		return nil, nil, err
+	}
+
+	if exportStmt.FileFormat != "CSV" {
+		return nil, nil, errors.Errorf("unsupported export format: %q", exportStmt.FileFormat)
+	}
+
+	fileFn, err := p.TypeAsString(exportStmt.File, "EXPORT")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := sqlbase.ResultColumns{
+		{Name: "filename", Typ: parser.TypeString},
+		{Name: "rows", Typ: parser.TypeInt},
+		{Name: "bytes", Typ: parser.TypeInt},
+	}
+	fn := func(ctx context.Context) ([]parser.Datums, error) {
+		// TODO(dan): Move this span into sql.
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer tracing.FinishSpan(span)
+
+		file, err := fileFn()
+		if err != nil {
+			return nil, err
+		}
+		spec, err := makeCSVWriterSpec(file, exportStmt.Options)
+		if err != nil {
+			return nil, err
+		}
+
+		// Check the destination before running the query.
+		es, err := exportStorageFromURI(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+		if err := es.Close(); err != nil {
+			return nil, err
+		}
+
+		// The files of different EXPORTs to the same destination must not
+		// collide.
+		exportID := parser.GenerateUniqueInt(p.EvalContext().NodeID)
+		spec.NamePattern = fmt.Sprintf("export%x-%s.csv", exportID, sql.ExportFilePatternPart)
+		return p.ExportCSV(ctx, exportStmt.Query, spec)
+	}
+	return fn, header, nil
+}
+
+// csvWriter is a processor that writes the rows it consumes into CSV files in
+// an export store, starting a new file every spec.ChunkRows rows. It emits a
+// row of (filename, rows, bytes) for each file.
+type csvWriter struct {
+	flowCtx *distsqlrun.FlowCtx
+	spec    distsqlrun.CSVWriterSpec
+	input   distsqlrun.RowSource
+	output  distsqlrun.RowReceiver
+}
+
+var _ distsqlrun.Processor = &csvWriter{}
+
+func newCSVWriterProcessor(
+	flowCtx *distsqlrun.FlowCtx,
+	spec distsqlrun.CSVWriterSpec,
+	input distsqlrun.RowSource,
+	output distsqlrun.RowReceiver,
+) (distsqlrun.Processor, error) {
+	return &csvWriter{
+		flowCtx: flowCtx,
+		spec:    spec,
+		input:   input,
+		output:  output,
+	}, nil
+}
+
+// Run is part of the distsqlrun.Processor interface.
+func (sp *csvWriter) Run(ctx context.Context, wg *sync.WaitGroup) {
+	if wg != nil {
+		defer wg.Done()
+	}
+	ctx, span := tracing.ChildSpan(ctx, "csvWriter")
+	defer tracing.FinishSpan(span)
+
+	err := sp.run(ctx)
+	if err == errConsumerDone {
+		err = nil
+	}
+	distsqlrun.DrainAndClose(ctx, sp.output, err, sp.input)
+}
+
+func (sp *csvWriter) run(ctx context.Context) error {
+	es, err := exportStorageFromURI(ctx, sp.spec.Destination)
+	if err != nil {
+		return err
+	}
+	defer es.Close()
+
+	var nullAs string
+	if sp.spec.NullEncoding != nil {
+		nullAs = *sp.spec.NullEncoding
+	}
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if sp.spec.Delimiter != 0 {
+		writer.Comma = sp.spec.Delimiter
+	}
+
+	input := distsqlrun.MakeNoMetadataRowSource(sp.input, sp.output)
+	var alloc sqlbase.DatumAlloc
+	var record []string
+	for chunk, done := 0, false; !done; chunk++ {
+		buf.Reset()
+		var rows int64
+		for sp.spec.ChunkRows == 0 || rows < sp.spec.ChunkRows {
+			row, err := input.NextRow()
+			if err != nil {
+				return err
+			}
+			if row == nil {
+				done = true
+				break
+			}
+			record = record[:0]
+			for i := range row {
+				if err := row[i].EnsureDecoded(&alloc); err != nil {
+					return err
+				}
+				record = append(record, csvString(row[i].Datum, nullAs))
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+			rows++
+		}
+		if rows == 0 {
+			break
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+
+		filename := strings.Replace(
+			sp.spec.NamePattern, sql.ExportFilePatternPart, strconv.Itoa(chunk), -1,
+		)
+		size := int64(buf.Len())
+		if err := es.WriteFile(ctx, filename, bytes.NewReader(buf.Bytes())); err != nil {
+			return errors.Wrapf(err, "writing %s", filename)
+		}
+
+		res := sqlbase.EncDatumRow{
+			sqlbase.DatumToEncDatum(sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_STRING}, parser.NewDString(filename)),
+			sqlbase.DatumToEncDatum(sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT}, parser.NewDInt(parser.DInt(rows))),
+			sqlbase.DatumToEncDatum(sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT}, parser.NewDInt(parser.DInt(size))),
+		}
+		if sp.output.Push(res, distsqlrun.ProducerMetadata{}) != distsqlrun.NeedMoreRows {
+			return errConsumerDone
+		}
+	}
+	return nil
+}
+
+// csvString returns the representation of d in a CSV file, which IMPORT
+// parses back into the same value.
+func csvString(d parser.Datum, nullAs string) string {
+	if d == parser.DNull {
+		return nullAs
+	}
+	switch t := d.(type) {
+	case *parser.DString:
+		return string(*t)
+	case *parser.DBytes:
+		return string(*t)
+	case *parser.DCollatedString:
+		return t.Contents
+	default:
+		return parser.AsStringWithFlags(d, parser.FmtBareStrings)
+	}
+}
+
+func init() {
+	sql.AddPlanHook(exportPlanHook)
+	distsqlrun.NewCSVWriterProcessor = newCSVWriterProcessor
+}
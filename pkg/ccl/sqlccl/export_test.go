@@ -0,0 +1,131 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package sqlccl_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestExportStmt(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numRows = 1000
+	ctx := context.Background()
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+
+	tc := testcluster.StartTestCluster(t, multiNode, base.TestClusterArgs{})
+	defer tc.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(t, tc.Conns[0])
+	sqlDB.Exec(`CREATE DATABASE d`)
+	sqlDB.Exec(`CREATE TABLE d.t (a INT PRIMARY KEY, b STRING, c FLOAT)`)
+	sqlDB.Exec(fmt.Sprintf(
+		`INSERT INTO d.t SELECT i, IF(i %% 10 = 0, NULL, 'x' || i::STRING), i::FLOAT / 2 FROM generate_series(1, %d) AS g(i)`,
+		numRows,
+	))
+	sqlDB.Exec(`ALTER TABLE d.t SPLIT AT VALUES (250), (500), (750)`)
+	sqlDB.Exec(`ALTER TABLE d.t SCATTER`)
+
+	// export runs the given EXPORT and returns the URIs of the files it
+	// wrote, as a SQL list, after checking the number of rows they contain.
+	export := func(t *testing.T, stmt string, expectedRows int) string {
+		rows := sqlDB.Query(stmt)
+		defer rows.Close()
+		var uris []string
+		var total int
+		for rows.Next() {
+			var filename string
+			var n, size int
+			if err := rows.Scan(&filename, &n, &size); err != nil {
+				t.Fatal(err)
+			}
+			content, err := ioutil.ReadFile(filepath.Join(dir, filename))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(content) != size || strings.Count(string(content), "\n") != n {
+				t.Fatalf("%s: expected %d rows and %d bytes, got %q", filename, n, size, content)
+			}
+			uris = append(uris, fmt.Sprintf("'nodelocal://%s'", filepath.Join(dir, filename)))
+			total += n
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if total != expectedRows {
+			t.Fatalf("expected %d rows, got %d", expectedRows, total)
+		}
+		return strings.Join(uris, ", ")
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		files := export(t, fmt.Sprintf(
+			`EXPORT INTO CSV 'nodelocal://%s' WITH OPTIONS ('chunk_rows'='100', 'nullas'='N') FROM SELECT * FROM d.t`,
+			dir,
+		), numRows)
+		if n := strings.Count(files, ","); n < numRows/100-1 {
+			t.Fatalf("expected at least %d files, got %d", numRows/100, n+1)
+		}
+
+		sqlDB.Exec(fmt.Sprintf(
+			`IMPORT TABLE d.t2 (a INT PRIMARY KEY, b STRING, c FLOAT) CSV DATA (%s) WITH OPTIONS ('nullif'='N')`,
+			files,
+		))
+		var count int
+		sqlDB.QueryRow(
+			`SELECT count(*) FROM d.t FULL OUTER JOIN d.t2 USING (a)
+			WHERE d.t.b IS DISTINCT FROM d.t2.b OR d.t.c IS DISTINCT FROM d.t2.c`,
+		).Scan(&count)
+		if count != 0 {
+			t.Fatalf("expected the imported table to match, found %d different rows", count)
+		}
+	})
+
+	t.Run("query", func(t *testing.T) {
+		files := export(t, fmt.Sprintf(
+			`EXPORT INTO CSV 'nodelocal://%s' WITH OPTIONS ('delimiter'='|') FROM SELECT a, b FROM d.t WHERE a > 900`,
+			dir,
+		), 100)
+		content, err := ioutil.ReadFile(strings.Trim(strings.TrimPrefix(files, "'nodelocal://"), "'"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), "901|x901\n") {
+			t.Fatalf("unexpected content %q", content)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for i, tc := range []struct {
+			stmt     string
+			expected string
+		}{
+			{`EXPORT INTO CSV 'nodelocal://%s' WITH OPTIONS ('foo'='bar') FROM TABLE d.t`, `unsupported option "foo"`},
+			{`EXPORT INTO CSV 'nodelocal://%s' WITH OPTIONS ('chunk_rows'='0') FROM TABLE d.t`, `chunk_rows must be positive`},
+			{`EXPORT INTO PARQUET 'nodelocal://%s' FROM TABLE d.t`, `unsupported export format: "PARQUET"`},
+			{`EXPORT INTO CSV 'nodelocal://%s' FROM TABLE d.nope`, `table "d.nope" does not exist`},
+		} {
+			if _, err := sqlDB.DB.Exec(fmt.Sprintf(tc.stmt, dir)); !testutils.IsError(err, tc.expected) {
+				t.Errorf("%d: expected error %q, got %v", i, tc.expected, err)
+			}
+		}
+	})
+}
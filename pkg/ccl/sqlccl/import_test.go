@@ -0,0 +1,167 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package sqlccl_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/sql/jobs"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+// writeImportFiles writes numFiles CSV files of rowsPerFile rows of (a INT,
+// b STRING) into dir, using comma as the separator, and returns their
+// nodelocal URIs as a SQL list.
+func writeImportFiles(t *testing.T, dir string, numFiles, rowsPerFile int, comma string) string {
+	var uris []string
+	for f := 0; f < numFiles; f++ {
+		var buf bytes.Buffer
+		for i := 0; i < rowsPerFile; i++ {
+			n := f*rowsPerFile + i
+			fmt.Fprintf(&buf, "%d%s%s\n", n, comma, fmt.Sprintf("x%d", n))
+		}
+		name := fmt.Sprintf("data-%d.csv", f)
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(buf.String()), 0666); err != nil {
+			t.Fatal(err)
+		}
+		uris = append(uris, fmt.Sprintf("'nodelocal://%s'", filepath.Join(dir, name)))
+	}
+	return strings.Join(uris, ", ")
+}
+
+func TestImportStmt(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	// Snapshots that use ClearRange can lose ingested SSTables; see
+	// TestBackupRestoreAddSSTable.
+	defer storage.TestingSetDisableSnapshotClearRange(true)()
+
+	const numFiles, rowsPerFile = 5, 1000
+	ctx := context.Background()
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+
+	tc := testcluster.StartTestCluster(t, multiNode, base.TestClusterArgs{})
+	defer tc.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(t, tc.Conns[0])
+	sqlDB.Exec(`CREATE DATABASE d`)
+
+	files := writeImportFiles(t, dir, numFiles, rowsPerFile, ",")
+	schema := filepath.Join(dir, "schema.sql")
+	if err := ioutil.WriteFile(
+		schema, []byte(`CREATE TABLE d.t2 (a INT PRIMARY KEY, b STRING, INDEX (b))`), 0666,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("inline schema", func(t *testing.T) {
+		var jobID, rows, idx, sys, size int64
+		var status string
+		var fraction float64
+		sqlDB.QueryRow(
+			fmt.Sprintf(`IMPORT TABLE d.t (a INT, b STRING) CSV DATA (%s) WITH OPTIONS ('sstsize'='10KB')`, files),
+		).Scan(&jobID, &status, &fraction, &rows, &idx, &sys, &size)
+		if status != string(jobs.JobStatusSucceeded) || fraction != 1 {
+			t.Fatalf("unexpected status %s, fraction %f", status, fraction)
+		}
+		if expected := int64(numFiles * rowsPerFile); rows != expected || idx != 0 {
+			t.Fatalf("expected %d rows and no index entries, got %d and %d", expected, rows, idx)
+		}
+
+		var count, sum int
+		sqlDB.QueryRow(`SELECT count(*), sum(a) FROM d.t`).Scan(&count, &sum)
+		if n := numFiles * rowsPerFile; count != n || sum != n*(n-1)/2 {
+			t.Fatalf("unexpected count %d and sum %d", count, sum)
+		}
+		var b string
+		sqlDB.QueryRow(`SELECT b FROM d.t WHERE a = 1234`).Scan(&b)
+		if b != "x1234" {
+			t.Fatalf("expected x1234, got %s", b)
+		}
+
+		var jobStatus string
+		var jobFraction float64
+		sqlDB.QueryRow(
+			`SELECT status, fraction_completed FROM crdb_internal.jobs WHERE id = $1 AND type = 'IMPORT'`, jobID,
+		).Scan(&jobStatus, &jobFraction)
+		if jobStatus != string(jobs.JobStatusSucceeded) || jobFraction != 1 {
+			t.Fatalf("unexpected job status %s, fraction %f", jobStatus, jobFraction)
+		}
+	})
+
+	t.Run("schema file", func(t *testing.T) {
+		var jobID, rows, idx, sys, size int64
+		var status string
+		var fraction float64
+		sqlDB.QueryRow(
+			fmt.Sprintf(`IMPORT TABLE d.t2 CREATE USING 'nodelocal://%s' CSV DATA (%s)`, schema, files),
+		).Scan(&jobID, &status, &fraction, &rows, &idx, &sys, &size)
+		if expected := int64(numFiles * rowsPerFile); rows != expected || idx != expected {
+			t.Fatalf("expected %d rows and index entries, got %d and %d", expected, rows, idx)
+		}
+		var count int
+		sqlDB.QueryRow(`SELECT count(*) FROM d.t2@t2_b_idx WHERE b > 'x2'`).Scan(&count)
+		if count == 0 {
+			t.Fatal("expected rows in the secondary index")
+		}
+	})
+
+	t.Run("options", func(t *testing.T) {
+		nulls := filepath.Join(dir, "nulls.csv")
+		if err := ioutil.WriteFile(nulls, []byte("# comment\n1|N\n2|y\n"), 0666); err != nil {
+			t.Fatal(err)
+		}
+		sqlDB.Exec(fmt.Sprintf(
+			`IMPORT TABLE d.t3 (a INT PRIMARY KEY, b STRING) CSV DATA ('nodelocal://%s')
+			WITH OPTIONS ('delimiter'='|', 'comment'='#', 'nullif'='N')`,
+			nulls,
+		))
+		var count int
+		sqlDB.QueryRow(`SELECT count(*) FROM d.t3 WHERE b IS NULL`).Scan(&count)
+		if count != 1 {
+			t.Fatalf("expected 1 NULL, got %d", count)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for i, tc := range []struct {
+			stmt     string
+			expected string
+		}{
+			{`IMPORT TABLE d.t (a INT) CSV DATA (%s)`, `relation "t" already exists`},
+			{`IMPORT TABLE d.e (a INT) CSV DATA (%s) WITH OPTIONS ('foo'='bar')`, `unsupported option "foo"`},
+			{`IMPORT TABLE d.e (a INT) CSV DATA (%s) WITH OPTIONS ('delimiter'='ab')`, `delimiter must be a single character`},
+			{`IMPORT TABLE d.e (a INT) CSV DATA (%s)`, `wrong number of fields`},
+			{`IMPORT TABLE d.e (a INT PRIMARY KEY, b STRING) CSV DATA (%[1]s, %[1]s)`, `duplicate key`},
+			{`IMPORT TABLE nope.e (a INT) CSV DATA (%s)`, `a database named "nope" needs to exist`},
+		} {
+			stmt := fmt.Sprintf(tc.stmt, fmt.Sprintf("'nodelocal://%s'", filepath.Join(dir, "data-0.csv")))
+			if _, err := sqlDB.DB.Exec(stmt); !testutils.IsError(err, tc.expected) {
+				t.Errorf("%d: expected error %q, got %v", i, tc.expected, err)
+			}
+		}
+		// Failed imports don't leave a table behind.
+		var count int
+		sqlDB.QueryRow(`SELECT count(*) FROM [SHOW TABLES FROM d] WHERE "Table" = 'e'`).Scan(&count)
+		if count != 0 {
+			t.Fatal("expected table e not to exist")
+		}
+	})
+}
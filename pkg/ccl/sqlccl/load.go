@@ -93,6 +93,7 @@ func Load(
 	var currentCmd bytes.Buffer
 	scanner := bufio.NewReader(r)
 	var ri sqlbase.RowInserter
+	defer func() { ri.Close(ctx) }()
 	var defaultExprs []parser.TypedExpr
 	var cols []sqlbase.ColumnDescriptor
 	var tableDesc *sqlbase.TableDescriptor
@@ -159,6 +160,7 @@ func Load(
 				Union: &sqlbase.Descriptor_Table{Table: tableDesc},
 			})
 
+			ri.Close(ctx)
 			ri, err = sqlbase.MakeRowInserter(nil, tableDesc, nil, tableDesc.Columns, true)
 			if err != nil {
 				return BackupDescriptor{}, errors.Wrap(err, "make row inserter")
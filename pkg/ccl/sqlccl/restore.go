@@ -9,6 +9,8 @@
 package sqlccl
 
 import (
+	"sort"
+
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 	"golang.org/x/sync/errgroup"
@@ -39,14 +41,17 @@ const (
 
 // Import loads some data in sstables into an empty range. Only the keys between
 // startKey and endKey are loaded. Every row's key is rewritten to be for
-// newTableID.
+// newTableID. If endTime is set, the data is loaded as of that time. If
+// encryptionKey is set, it is used to decrypt the sstables.
 func Import(
 	ctx context.Context,
 	db client.DB,
 	startKey, endKey roachpb.Key,
+	endTime hlc.Timestamp,
 	files []roachpb.ImportRequest_File,
 	kr *storageccl.KeyRewriter,
 	rekeys []roachpb.ImportRequest_TableRekey,
+	encryptionKey []byte,
 ) (*roachpb.ImportResponse, error) {
 	var newStartKey, newEndKey roachpb.Key
 	{
@@ -77,8 +82,10 @@ func Import(
 			Key:    startKey,
 			EndKey: endKey,
 		},
-		Files:  files,
-		Rekeys: rekeys,
+		Files:         files,
+		Rekeys:        rekeys,
+		EndTime:       endTime,
+		EncryptionKey: encryptionKey,
 	}
 	res, pErr := client.SendWrapped(ctx, db.GetSender(), req)
 	if pErr != nil {
@@ -88,11 +95,13 @@ func Import(
 	return res.(*roachpb.ImportResponse), nil
 }
 
-func loadBackupDescs(ctx context.Context, uris []string) ([]BackupDescriptor, error) {
+func loadBackupDescs(
+	ctx context.Context, uris []string, encryptionKey []byte,
+) ([]BackupDescriptor, error) {
 	backupDescs := make([]BackupDescriptor, len(uris))
 
 	for i, uri := range uris {
-		desc, err := readBackupDescriptor(ctx, uri)
+		desc, err := readBackupDescriptor(ctx, uri, encryptionKey)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to read backup descriptor")
 		}
@@ -104,15 +113,116 @@ func loadBackupDescs(ctx context.Context, uris []string) ([]BackupDescriptor, er
 	return backupDescs, nil
 }
 
+// backupsCoveringTime returns the prefix of the backups, which are ordered by
+// time, needed to restore as of asOf: the ones that end before it plus the one
+// covering it. Unless that last one ends exactly at asOf, it must have been
+// taken with revision history.
+func backupsCoveringTime(
+	backupDescs []BackupDescriptor, asOf hlc.Timestamp,
+) ([]BackupDescriptor, error) {
+	for i, b := range backupDescs {
+		if asOf == b.EndTime {
+			return backupDescs[:i+1], nil
+		}
+		if asOf.Less(b.EndTime) {
+			if !b.RevisionHistory {
+				return nil, errors.Errorf(
+					"invalid RESTORE timestamp: backup covering %s was taken without %q",
+					asOf, backupOptRevisionHistory)
+			}
+			return backupDescs[:i+1], nil
+		}
+	}
+	return nil, errors.Errorf(
+		"invalid RESTORE timestamp: supplied backups do not cover requested time %s", asOf)
+}
+
+// loadSQLDescsAsOfTime returns the SQL descriptors as they were at asOf,
+// starting from the ones in the second to last backup and replaying the
+// revisions recorded in the last one.
+func loadSQLDescsAsOfTime(backupDescs []BackupDescriptor, asOf hlc.Timestamp) []sqlbase.Descriptor {
+	lastBackupDesc := backupDescs[len(backupDescs)-1]
+	if asOf == lastBackupDesc.EndTime {
+		return lastBackupDesc.Descriptors
+	}
+
+	byID := make(map[sqlbase.ID]*sqlbase.Descriptor)
+	if len(backupDescs) > 1 {
+		prev := backupDescs[len(backupDescs)-2].Descriptors
+		for i := range prev {
+			byID[prev[i].GetID()] = &prev[i]
+		}
+	}
+
+	changes := append([]BackupDescriptor_DescriptorRevision(nil), lastBackupDesc.DescriptorChanges...)
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Time.Less(changes[j].Time)
+	})
+	for _, change := range changes {
+		if asOf.Less(change.Time) {
+			break
+		}
+		if change.Desc == nil {
+			delete(byID, change.ID)
+		} else {
+			byID[change.ID] = change.Desc
+		}
+	}
+
+	sqlDescs := make([]sqlbase.Descriptor, 0, len(byID))
+	for _, desc := range byID {
+		if tableDesc := desc.GetTable(); tableDesc != nil && tableDesc.Dropped() {
+			continue
+		}
+		sqlDescs = append(sqlDescs, *desc)
+	}
+	sort.Slice(sqlDescs, func(i, j int) bool {
+		return sqlDescs[i].GetID() < sqlDescs[j].GetID()
+	})
+	return sqlDescs
+}
+
+// createRestoredDatabases allocates IDs for the databases created by the
+// restore, keyed by their IDs in the backup, and checks that their names are
+// not in use. The databases themselves are written by restoreTableDescs.
+func createRestoredDatabases(
+	ctx context.Context, txn *client.Txn, databases map[sqlbase.ID]*sqlbase.DatabaseDescriptor,
+) error {
+	for _, database := range databases {
+		existing, err := txn.Get(ctx, sqlbase.MakeNameMetadataKey(keys.RootNamespaceID, database.Name))
+		if err != nil {
+			return err
+		}
+		if existing.Exists() {
+			return sqlbase.NewDatabaseAlreadyExistsError(database.Name)
+		}
+		newID, err := sql.GenerateUniqueDescID(ctx, txn)
+		if err != nil {
+			return err
+		}
+		database.ID = newID
+	}
+	return nil
+}
+
 func reassignParentIDs(
 	ctx context.Context,
 	txn *client.Txn,
 	p sql.PlanHookState,
 	databasesByID map[sqlbase.ID]*sqlbase.DatabaseDescriptor,
+	newDatabases map[sqlbase.ID]*sqlbase.DatabaseDescriptor,
 	tables []*sqlbase.TableDescriptor,
 	opt parser.KVOptions,
 ) error {
 	for _, table := range tables {
+		// Tables in a database created by the restore go in it, with its
+		// privileges.
+		if database, ok := newDatabases[table.ParentID]; ok {
+			table.ParentID = database.ID
+			table.Privileges = database.GetPrivileges()
+			continue
+		}
+
 		// Update the parentID to point to the named DB in the new cluster.
 		{
 			var targetDB string
@@ -567,12 +677,22 @@ func PresplitRanges(baseCtx context.Context, db client.DB, input []roachpb.Key)
 
 // Write the new descriptors. First the ID -> TableDescriptor for the new table,
 // then flip (or initialize) the name -> ID entry so any new queries will use
-// the new one.
-func restoreTableDescs(ctx context.Context, db client.DB, tables []*sqlbase.TableDescriptor) error {
+// the new one. Databases created by the restore are written in the same
+// transaction, so they only appear once their tables have been restored.
+func restoreTableDescs(
+	ctx context.Context,
+	db client.DB,
+	databases map[sqlbase.ID]*sqlbase.DatabaseDescriptor,
+	tables []*sqlbase.TableDescriptor,
+) error {
 	ctx, span := tracing.ChildSpan(ctx, "restoreTableDescs")
 	defer tracing.FinishSpan(span)
 	err := db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
 		b := txn.NewBatch()
+		for _, database := range databases {
+			b.CPut(sqlbase.MakeDescMetadataKey(database.ID), sqlbase.WrapDescriptor(database), nil)
+			b.CPut(sqlbase.MakeNameMetadataKey(keys.RootNamespaceID, database.Name), database.ID, nil)
+		}
 		for _, table := range tables {
 			b.CPut(table.GetDescMetadataKey(), sqlbase.WrapDescriptor(table), nil)
 			b.CPut(table.GetNameMetadataKey(), table.ID, nil)
@@ -594,8 +714,9 @@ func restoreTableDescs(ctx context.Context, db client.DB, tables []*sqlbase.Tabl
 func restoreJobDescription(restore *parser.Restore, from []string) (string, error) {
 	r := parser.Restore{
 		AsOf:    restore.AsOf,
-		Options: restore.Options,
+		Options: redactBackupOptions(restore.Options),
 		Targets: restore.Targets,
+		As:      restore.As,
 		From:    make(parser.Exprs, len(restore.From)),
 	}
 
@@ -611,13 +732,19 @@ func restoreJobDescription(restore *parser.Restore, from []string) (string, erro
 }
 
 // Restore imports a SQL table (or tables) from sets of non-overlapping sstable
-// files.
+// files. If endTime is set, the tables are restored as they were at that time,
+// which must be the end of one of the backups or covered by a backup with
+// revision history. Databases in targets are created by the restore, which
+// fails if they already exist. If newName is set, the single database or table
+// in targets is restored under that name.
 func Restore(
 	ctx context.Context,
 	p sql.PlanHookState,
 	uris []string,
 	targets parser.TargetList,
+	newName parser.Name,
 	opt parser.KVOptions,
+	endTime hlc.Timestamp,
 	jobLogger *jobs.JobLogger,
 ) (roachpb.BulkOpSummary, error) {
 
@@ -626,22 +753,41 @@ func Restore(
 	failed := roachpb.BulkOpSummary{}
 
 	if len(targets.Databases) > 0 {
-		return failed, errors.Errorf("RESTORE DATABASE is not yet supported " +
-			"(but you can use 'RESTORE somedb.*' to restore all backed up tables for a given DB).")
+		if _, ok := opt.Get(restoreOptIntoDB); ok {
+			return failed, errors.Errorf("cannot use %q option with RESTORE DATABASE", restoreOptIntoDB)
+		}
+		if newName != "" && len(targets.Databases) != 1 {
+			return failed, errors.New("RESTORE DATABASE ... AS requires exactly one database")
+		}
+	}
+
+	// The key is derived, and the backup descriptors decrypted with it, before
+	// anything is written, so a wrong passphrase fails the restore early.
+	var encryptionKey []byte
+	if len(uris) > 0 {
+		var err error
+		if encryptionKey, _, err = backupEncryptionKey(ctx, opt, uris[0]); err != nil {
+			return failed, err
+		}
 	}
 
-	backupDescs, err := loadBackupDescs(ctx, uris)
+	backupDescs, err := loadBackupDescs(ctx, uris, encryptionKey)
 	if err != nil {
 		return failed, err
 	}
-	lastBackupDesc := backupDescs[len(backupDescs)-1]
+	sqlDescs := backupDescs[len(backupDescs)-1].Descriptors
+	if endTime != (hlc.Timestamp{}) {
+		if backupDescs, err = backupsCoveringTime(backupDescs, endTime); err != nil {
+			return failed, err
+		}
+		sqlDescs = loadSQLDescsAsOfTime(backupDescs, endTime)
+	}
 
 	databasesByID := make(map[sqlbase.ID]*sqlbase.DatabaseDescriptor)
 	var tables []*sqlbase.TableDescriptor
 	{
 		// TODO(dan): Plumb the session database down.
 		sessionDatabase := ""
-		sqlDescs := lastBackupDesc.Descriptors
 		var err error
 		if sqlDescs, err = descriptorsMatchingTargets(sessionDatabase, sqlDescs, targets); err != nil {
 			return failed, err
@@ -658,10 +804,47 @@ func Restore(
 		}
 	}
 
-	// Fail fast if the necessary databases don't exist since the below logic
-	// leaks table IDs when Restore fails.
+	// Databases named as targets are restored as new databases, copied so that
+	// the descriptors in the backup are left untouched.
+	newDatabases := make(map[sqlbase.ID]*sqlbase.DatabaseDescriptor)
+	if len(targets.Databases) > 0 {
+		for id, database := range databasesByID {
+			newDatabase := *database
+			newDatabase.Privileges = sqlbase.NewDefaultPrivilegeDescriptor()
+			if newName != "" {
+				newDatabase.Name = string(newName)
+			}
+			newDatabases[id] = &newDatabase
+		}
+	}
+	if newName != "" {
+		if len(targets.Databases) > 0 {
+			// A view's query names the database of each table it uses, so it
+			// would still refer to the original database.
+			for _, table := range tables {
+				if table.IsView() {
+					return failed, errors.Errorf(
+						"cannot restore view %q when renaming its database", table.Name)
+				}
+			}
+		} else {
+			if len(tables) != 1 {
+				return failed, errors.Errorf(
+					"RESTORE ... AS requires exactly one table, but %s matches %d",
+					parser.AsString(targets), len(tables))
+			}
+			tables[0].Name = string(newName)
+		}
+	}
+
+	// Fail fast if the necessary databases don't exist (or, for the ones being
+	// created, do exist) since the below logic leaks table IDs when Restore
+	// fails.
 	if err := db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
-		return reassignParentIDs(ctx, txn, p, databasesByID, tables, opt)
+		if err := createRestoredDatabases(ctx, txn, newDatabases); err != nil {
+			return err
+		}
+		return reassignParentIDs(ctx, txn, p, databasesByID, newDatabases, tables, opt)
 	}); err != nil {
 		return failed, err
 	}
@@ -781,7 +964,7 @@ func Restore(
 		g.Go(func() error {
 			defer func() { <-importsSem }()
 
-			res, err := Import(gCtx, db, ir.Key, ir.EndKey, ir.files, kr, rekeys)
+			res, err := Import(gCtx, db, ir.Key, ir.EndKey, endTime, ir.files, kr, rekeys, encryptionKey)
 			if err != nil {
 				return err
 			}
@@ -808,7 +991,7 @@ func Restore(
 	// Write the new TableDescriptors and flip the namespace entries over to
 	// them. After this call, any queries on a table will be served by the newly
 	// restored data.
-	if err := restoreTableDescs(ctx, db, tables); err != nil {
+	if err := restoreTableDescs(ctx, db, newDatabases, tables); err != nil {
 		return failed, errors.Wrapf(err, "restoring %d TableDescriptors", len(tables))
 	}
 
@@ -857,6 +1040,14 @@ func restorePlanHook(
 		if err != nil {
 			return nil, err
 		}
+		var endTime hlc.Timestamp
+		if restore.AsOf.Expr != nil {
+			var err error
+			endTime, err = sql.EvalAsOfTimestamp(nil, restore.AsOf, p.ExecCfg().Clock.Now())
+			if err != nil {
+				return nil, err
+			}
+		}
 		description, err := restoreJobDescription(restore, from)
 		if err != nil {
 			return nil, err
@@ -871,7 +1062,9 @@ func restorePlanHook(
 			p,
 			from,
 			restore.Targets,
+			restore.As,
 			restore.Options,
+			endTime,
 			jobLogger,
 		)
 		if err != nil {
@@ -0,0 +1,312 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package sqlccl
+
+import (
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/jobs"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+// scheduledBackupDirFormat is the time format of the directory, under the
+// schedule's base URI, that each scheduled backup is written to.
+const scheduledBackupDirFormat = "20060102-150405.000000"
+
+// qualifyTargets returns a copy of targets in which every table pattern names
+// its database, so that the targets resolve the same way when the schedule is
+// run in a session without one.
+func qualifyTargets(targets parser.TargetList, sessionDatabase string) (parser.TargetList, error) {
+	ret := parser.TargetList{Databases: targets.Databases}
+	for _, pattern := range targets.Tables {
+		pattern, err := pattern.NormalizeTablePattern()
+		if err != nil {
+			return parser.TargetList{}, err
+		}
+		switch p := pattern.(type) {
+		case *parser.TableName:
+			tn := *p
+			if err := tn.QualifyWithDatabase(sessionDatabase); err != nil {
+				return parser.TargetList{}, err
+			}
+			ret.Tables = append(ret.Tables, &tn)
+		case *parser.AllTablesSelector:
+			at := *p
+			if err := at.QualifyWithDatabase(sessionDatabase); err != nil {
+				return parser.TargetList{}, err
+			}
+			ret.Tables = append(ret.Tables, &at)
+		default:
+			return parser.TargetList{}, errors.Errorf("unknown pattern %T: %+v", pattern, pattern)
+		}
+	}
+	return ret, nil
+}
+
+// scheduledBackupURI returns the URI of the directory under baseURI that a
+// backup started at now is written to.
+func scheduledBackupURI(baseURI string, now time.Time) (string, error) {
+	uri, err := url.Parse(baseURI)
+	if err != nil {
+		return "", err
+	}
+	uri.Path = path.Join(uri.Path, now.UTC().Format(scheduledBackupDirFormat))
+	return uri.String(), nil
+}
+
+func createScheduleBackupPlanHook(
+	stmt parser.Statement, p sql.PlanHookState,
+) (func(context.Context) ([]parser.Datums, error), sqlbase.ResultColumns, error) {
+	schedule, ok := stmt.(*parser.CreateSchedule)
+	if !ok {
+		return nil, nil, nil
+	}
+
+	if err := utilccl.CheckEnterpriseEnabled(p.ExecCfg().ClusterID(), "CREATE SCHEDULE"); err != nil {
+		return nil, nil, err
+	}
+
+	// Scheduled backups are run as the user that created the schedule.
+	if err := p.RequireSuperUser("CREATE SCHEDULE"); err != nil {
+		return nil, nil, err
+	}
+
+	toFn, err := p.TypeAsString(schedule.Backup.To, "CREATE SCHEDULE")
+	if err != nil {
+		return nil, nil, err
+	}
+	recurrenceFn, err := p.TypeAsString(schedule.Recurrence, "CREATE SCHEDULE")
+	if err != nil {
+		return nil, nil, err
+	}
+	fullRecurrenceFn := func() (string, error) { return "", nil }
+	if schedule.FullBackupRecurrence != nil {
+		fullRecurrenceFn, err = p.TypeAsString(schedule.FullBackupRecurrence, "CREATE SCHEDULE")
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	header := sqlbase.ResultColumns{
+		{Name: "schedule_id", Typ: parser.TypeInt},
+		{Name: "next_run", Typ: parser.TypeTimestamp},
+	}
+	fn := func(ctx context.Context) ([]parser.Datums, error) {
+		// TODO(dan): Move this span into sql.
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer tracing.FinishSpan(span)
+
+		to, err := toFn()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := storageccl.ExportStorageConfFromURI(to); err != nil {
+			return nil, err
+		}
+		recurrenceExpr, err := recurrenceFn()
+		if err != nil {
+			return nil, err
+		}
+		recurrence, err := jobs.ParseRecurrence(recurrenceExpr)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid RECURRING")
+		}
+		fullRecurrenceExpr, err := fullRecurrenceFn()
+		if err != nil {
+			return nil, err
+		}
+		if fullRecurrenceExpr != "" {
+			if _, err := jobs.ParseRecurrence(fullRecurrenceExpr); err != nil {
+				return nil, errors.Wrap(err, "invalid FULL BACKUP")
+			}
+		}
+		targets, err := qualifyTargets(schedule.Backup.Targets, p.EvalContext().Database)
+		if err != nil {
+			return nil, err
+		}
+		opts := make(map[string]string, len(schedule.Backup.Options))
+		for _, opt := range schedule.Backup.Options {
+			// The options are stored with the schedule in plaintext.
+			if opt.Key == backupOptEncPassphrase {
+				return nil, errors.Errorf("CREATE SCHEDULE does not support the %s option", backupOptEncPassphrase)
+			}
+			opts[opt.Key] = opt.Value
+		}
+
+		name := string(schedule.Name)
+		if name == "" {
+			name = "backup"
+		}
+		now := p.ExecCfg().Clock.PhysicalTime()
+		payload := &jobs.SchedulePayload{
+			Name:       name,
+			Username:   p.User(),
+			Recurrence: recurrenceExpr,
+			Details: &jobs.SchedulePayload_Backup{Backup: &jobs.BackupScheduleDetails{
+				Targets:        parser.AsString(targets),
+				BaseURI:        to,
+				Options:        opts,
+				FullRecurrence: fullRecurrenceExpr,
+				// The first backup taken by a schedule is always a full backup.
+				NextFullMicros: now.UnixNano() / time.Microsecond.Nanoseconds(),
+			}},
+		}
+		nextRun := recurrence.Next(now)
+		var id int64
+		if err := p.ExecCfg().DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+			var err error
+			id, err = jobs.CreateSchedule(ctx, sql.InternalExecutor{LeaseManager: p.LeaseMgr()}, txn, payload, nextRun)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+		return []parser.Datums{{
+			parser.NewDInt(parser.DInt(id)),
+			parser.MakeDTimestamp(nextRun, time.Microsecond),
+		}}, nil
+	}
+	return fn, header, nil
+}
+
+// scheduledBackupStmt returns the BACKUP statement taken by a backup schedule
+// into uri, incremental on top of the backups in incrementalFrom, if any.
+func scheduledBackupStmt(
+	details *jobs.BackupScheduleDetails, uri string, incrementalFrom []string,
+) (string, error) {
+	// The targets are stored formatted, so parse them back out of a
+	// placeholder statement.
+	stmt, err := parser.ParseOne("BACKUP " + details.Targets + " TO ''")
+	if err != nil {
+		return "", err
+	}
+	backup, ok := stmt.(*parser.Backup)
+	if !ok {
+		return "", errors.Errorf("expected BACKUP, got %T", stmt)
+	}
+	backup.To = parser.NewDString(uri)
+	for _, from := range incrementalFrom {
+		backup.IncrementalFrom = append(backup.IncrementalFrom, parser.NewDString(from))
+	}
+	keys := make([]string, 0, len(details.Options))
+	for k := range details.Options {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		backup.Options = append(backup.Options, parser.KVOption{Key: k, Value: details.Options[k]})
+	}
+	return parser.AsString(backup), nil
+}
+
+// redactURIError returns err with the values of the query parameters of uri,
+// which hold the credentials of the export storage, hidden from its message.
+// It is used for the errors recorded with a schedule and shown by SHOW
+// SCHEDULES.
+func redactURIError(err error, uri string) error {
+	parsed, parseErr := url.Parse(uri)
+	if parseErr != nil {
+		return errors.New("invalid URI")
+	}
+	// The values may appear in the message as they are or escaped, e.g. as
+	// part of the URI.
+	var secrets []string
+	for _, values := range parsed.Query() {
+		for _, v := range values {
+			if v != "" {
+				secrets = append(secrets, url.QueryEscape(v), v)
+			}
+		}
+	}
+	msg := err.Error()
+	redacted := msg
+	for _, secret := range secrets {
+		redacted = strings.Replace(redacted, secret, "redacted", -1)
+	}
+	if redacted == msg {
+		return err
+	}
+	return errors.New(redacted)
+}
+
+// backupScheduleRunner takes a scheduled backup. Each run takes a full backup
+// if one is due according to the schedule's FULL BACKUP recurrence, and
+// otherwise an incremental backup on top of the chain of backups taken since
+// the last full backup.
+func backupScheduleRunner(
+	payload *jobs.SchedulePayload,
+) func(context.Context, time.Time, sql.ScheduleExecFn) error {
+	details := payload.GetBackup()
+	if details == nil {
+		return nil
+	}
+	return func(ctx context.Context, now time.Time, exec sql.ScheduleExecFn) (err error) {
+		defer func() {
+			if err != nil {
+				err = redactURIError(err, details.BaseURI)
+			}
+		}()
+		nowMicros := now.UnixNano() / time.Microsecond.Nanoseconds()
+		full := details.FullRecurrence == "" || len(details.Chain) == 0 ||
+			nowMicros >= details.NextFullMicros
+
+		uri, err := scheduledBackupURI(details.BaseURI, now)
+		if err != nil {
+			return err
+		}
+		var incrementalFrom []string
+		if !full {
+			incrementalFrom = details.Chain
+		}
+		stmt, err := scheduledBackupStmt(details, uri, incrementalFrom)
+		if err != nil {
+			return err
+		}
+		rows, err := exec(ctx, stmt)
+		if err != nil {
+			return err
+		}
+		if len(rows) != 1 {
+			return errors.Errorf("expected 1 row from BACKUP, got %d", len(rows))
+		}
+		payload.LastJobID = int64(parser.MustBeDInt(rows[0][0]))
+
+		if details.FullRecurrence == "" {
+			return nil
+		}
+		if full {
+			fullRecurrence, err := jobs.ParseRecurrence(details.FullRecurrence)
+			if err != nil {
+				return err
+			}
+			details.Chain = []string{uri}
+			details.NextFullMicros = fullRecurrence.Next(now).UnixNano() / time.Microsecond.Nanoseconds()
+		} else {
+			details.Chain = append(details.Chain, uri)
+		}
+		return nil
+	}
+}
+
+func init() {
+	sql.AddPlanHook(createScheduleBackupPlanHook)
+	sql.AddScheduleRunner(backupScheduleRunner)
+}
@@ -0,0 +1,37 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package sqlccl
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestRedactURIError(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const uri = "s3://bucket/path?AWS_ACCESS_KEY_ID=key&AWS_SECRET_ACCESS_KEY=se%2Fcret"
+	for _, tc := range []struct {
+		err      string
+		expected string
+	}{
+		{"access denied", "access denied"},
+		{"access denied for key", "access denied for redacted"},
+		{"writing to " + uri + ": timeout",
+			"writing to s3://bucket/path?AWS_ACCESS_KEY_ID=redacted&AWS_SECRET_ACCESS_KEY=redacted: timeout"},
+		{"bad secret se/cret", "bad secret redacted"},
+	} {
+		if err := redactURIError(errors.New(tc.err), uri); err.Error() != tc.expected {
+			t.Errorf("%q: expected %q, got %q", tc.err, tc.expected, err)
+		}
+	}
+}
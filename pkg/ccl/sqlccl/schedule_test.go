@@ -0,0 +1,138 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package sqlccl_test
+
+import (
+	gosql "database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestScheduledBackup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer settings.TestingSetDuration(&sql.SchedulerInterval, 10*time.Millisecond)()
+
+	const numAccounts = 10
+	_, dir, _, sqlDB, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts)
+	defer cleanupFn()
+
+	var scheduleID int64
+	var nextRun time.Time
+	sqlDB.QueryRow(
+		`CREATE SCHEDULE nightly FOR BACKUP DATABASE data TO $1 RECURRING '@daily' FULL BACKUP '@weekly'`, dir,
+	).Scan(&scheduleID, &nextRun)
+	if now := time.Now(); !nextRun.After(now) || nextRun.Sub(now) > 24*time.Hour {
+		t.Fatalf("expected next run within a day of %s, got %s", now, nextRun)
+	}
+
+	// runNow makes the schedule due and returns the description of the
+	// BACKUP job it starts.
+	var lastJobID gosql.NullInt64
+	runNow := func(t *testing.T) string {
+		sqlDB.Exec(`UPDATE system.schedules SET next_run = now() WHERE id = $1`, scheduleID)
+		testutils.SucceedsSoon(t, func() error {
+			var jobID gosql.NullInt64
+			var scheduleErr string
+			sqlDB.QueryRow(
+				`SELECT last_job_id, error FROM crdb_internal.schedules WHERE id = $1`, scheduleID,
+			).Scan(&jobID, &scheduleErr)
+			if scheduleErr != "" {
+				t.Fatalf("schedule failed: %s", scheduleErr)
+			}
+			if !jobID.Valid || jobID == lastJobID {
+				return errors.New("schedule has not run")
+			}
+			lastJobID = jobID
+			return nil
+		})
+		var description string
+		sqlDB.QueryRow(
+			`SELECT description FROM crdb_internal.jobs WHERE id = $1 AND status = 'succeeded'`, lastJobID.Int64,
+		).Scan(&description)
+		if !strings.HasPrefix(description, "BACKUP DATABASE data TO '"+dir+"/") {
+			t.Fatalf("unexpected job description %q", description)
+		}
+		return description
+	}
+
+	// The first run takes a full backup and later ones are incremental, until
+	// the next full backup is due.
+	if description := runNow(t); strings.Contains(description, "INCREMENTAL FROM") {
+		t.Fatalf("expected full backup, got %q", description)
+	}
+	for i := 1; i <= 2; i++ {
+		description := runNow(t)
+		// The backup's own URI, followed by the i backups it is incremental from.
+		if !strings.Contains(description, "INCREMENTAL FROM") ||
+			strings.Count(description, "'"+dir+"/") != i+1 {
+			t.Fatalf("expected backup incremental from %d backups, got %q", i, description)
+		}
+	}
+
+	// Running the schedule moved its next run back into the future.
+	var next time.Time
+	sqlDB.QueryRow(`SELECT next_run FROM [SHOW SCHEDULES] WHERE id = $1`, scheduleID).Scan(&next)
+	if !next.After(time.Now()) {
+		t.Fatalf("next run %s is not in the future", next)
+	}
+
+	sqlDB.CheckQueryResults(
+		`SELECT name, type, status, recurrence FROM [SHOW SCHEDULES]`,
+		[][]string{{"nightly", "BACKUP", "active", "@daily"}},
+	)
+
+	t.Run("pause", func(t *testing.T) {
+		sqlDB.Exec(`PAUSE SCHEDULE $1`, scheduleID)
+		sqlDB.CheckQueryResults(
+			`SELECT status, next_run FROM [SHOW SCHEDULES]`, [][]string{{"paused", "NULL"}},
+		)
+		if _, err := sqlDB.DB.Exec(`PAUSE SCHEDULE $1`, scheduleID); !testutils.IsError(err, "is paused") {
+			t.Fatalf("expected paused error, got %v", err)
+		}
+		sqlDB.Exec(`RESUME SCHEDULE $1`, scheduleID)
+		sqlDB.CheckQueryResults(`SELECT status FROM [SHOW SCHEDULES]`, [][]string{{"active"}})
+		if _, err := sqlDB.DB.Exec(`RESUME SCHEDULE $1`, scheduleID); !testutils.IsError(err, "is active") {
+			t.Fatalf("expected active error, got %v", err)
+		}
+	})
+
+	t.Run("drop", func(t *testing.T) {
+		sqlDB.Exec(`DROP SCHEDULE $1`, scheduleID)
+		sqlDB.CheckQueryResults(`SELECT count(*) FROM [SHOW SCHEDULES]`, [][]string{{"0"}})
+		if _, err := sqlDB.DB.Exec(`DROP SCHEDULE $1`, scheduleID); !testutils.IsError(err, "not found") {
+			t.Fatalf("expected not found error, got %v", err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for i, tc := range []struct {
+			stmt     string
+			expected string
+		}{
+			{`CREATE SCHEDULE FOR BACKUP DATABASE data TO 'nodelocal:///x' RECURRING 'sometimes'`, `invalid RECURRING`},
+			{`CREATE SCHEDULE FOR BACKUP DATABASE data TO 'nodelocal:///x' RECURRING '@daily' FULL BACKUP '0 0 30 2 *'`, `invalid FULL BACKUP`},
+			{`CREATE SCHEDULE FOR BACKUP TABLE bank TO 'nodelocal:///x' RECURRING '@daily'`, `no database specified`},
+			{`CREATE SCHEDULE FOR BACKUP DATABASE data TO 'nope:///x' RECURRING '@daily'`, `unsupported storage scheme`},
+			{`CREATE SCHEDULE FOR BACKUP DATABASE data TO 'nodelocal:///x' WITH OPTIONS ('encryption_passphrase'='abc') RECURRING '@daily'`,
+				`does not support the encryption_passphrase option`},
+		} {
+			if _, err := sqlDB.DB.Exec(tc.stmt); !testutils.IsError(err, tc.expected) {
+				t.Errorf("%d: expected error %q, got %v", i, tc.expected, err)
+			}
+		}
+	})
+}
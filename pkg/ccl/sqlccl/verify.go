@@ -0,0 +1,445 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package sqlccl
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+const verifyBackupOptFingerprints = "fingerprints"
+
+// readBackupFile reads one of the data files of a backup, checking it against
+// its checksum and decrypting it with encryptionKey if it is non-nil.
+func readBackupFile(
+	ctx context.Context, file roachpb.ImportRequest_File, encryptionKey []byte,
+) ([]byte, error) {
+	dir, err := storageccl.MakeExportStorage(ctx, file.Dir)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+	r, err := dir.ReadFile(ctx, file.Path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching %q", file.Path)
+	}
+	defer r.Close()
+	fileContents, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching %q", file.Path)
+	}
+	if len(file.Sha512) > 0 {
+		if checksum := sha512.Sum512(fileContents); !bytes.Equal(checksum[:], file.Sha512) {
+			return nil, errors.Errorf("checksum mismatch for %s", file.Path)
+		}
+	}
+	if encryptionKey != nil {
+		fileContents, err = storageccl.DecryptFile(fileContents, encryptionKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "decrypting %q", file.Path)
+		}
+	} else if storageccl.AppearsEncrypted(fileContents) {
+		return nil, errors.Errorf("%q appears to be encrypted but no key was provided", file.Path)
+	}
+	return fileContents, nil
+}
+
+// spansCover returns whether the union of spans, which must be sorted and
+// non-overlapping, contains span.
+func spansCover(spans []roachpb.Span, span roachpb.Span) bool {
+	covered := span.Key
+	for _, s := range spans {
+		if s.Key.Compare(covered) > 0 {
+			break
+		}
+		if s.EndKey.Compare(covered) > 0 {
+			covered = s.EndKey
+		}
+		if covered.Compare(span.EndKey) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// backupVerification summarizes the data files of a backup that were verified.
+type backupVerification struct {
+	files, keys, bytes int64
+}
+
+// verifyBackupFiles reads every data file of a backup and checks that it
+// matches its checksum, that its files lie within the spans of the backup
+// without overlapping each other, and that every key in them is in the span
+// of its file and was written in the time the backup covers.
+func verifyBackupFiles(
+	ctx context.Context, desc BackupDescriptor, encryptionKey []byte,
+) (backupVerification, error) {
+	var res backupVerification
+
+	spans := append(roachpb.Spans(nil), desc.Spans...)
+	sort.Sort(spans)
+	files := append(backupFileDescriptors(nil), desc.Files...)
+	sort.Sort(files)
+	for i, file := range files {
+		if !spansCover(spans, file.Span) {
+			return res, errors.Errorf("file %s covers %s, which is not in the backup's spans",
+				file.Path, file.Span)
+		}
+		if i > 0 && files[i-1].Span.Overlaps(file.Span) {
+			return res, errors.Errorf("files %s and %s overlap", files[i-1].Path, file.Path)
+		}
+		if len(file.Path) == 0 {
+			// No data was exported for this span.
+			continue
+		}
+
+		fileContents, err := readBackupFile(ctx, roachpb.ImportRequest_File{
+			Dir: desc.Dir, Path: file.Path, Sha512: file.Sha512,
+		}, encryptionKey)
+		if err != nil {
+			return res, err
+		}
+		res.files++
+		res.bytes += int64(len(fileContents))
+
+		if err := func() error {
+			iter, err := engineccl.NewMemSSTIterator(fileContents)
+			if err != nil {
+				return errors.Wrapf(err, "reading %q", file.Path)
+			}
+			defer iter.Close()
+			for iter.Seek(engine.MVCCKey{}); ; iter.Next() {
+				ok, err := iter.Valid()
+				if err != nil {
+					return errors.Wrapf(err, "reading %q", file.Path)
+				}
+				if !ok {
+					return nil
+				}
+				key := iter.UnsafeKey()
+				if !file.Span.Contains(roachpb.Span{Key: key.Key}) {
+					return errors.Errorf("file %s contains key %s outside of its span %s",
+						file.Path, key.Key, file.Span)
+				}
+				if !desc.StartTime.Less(key.Timestamp) || desc.EndTime.Less(key.Timestamp) {
+					return errors.Errorf("file %s contains key %s at %s, outside of the backup's time (%s,%s]",
+						file.Path, key.Key, key.Timestamp, desc.StartTime, desc.EndTime)
+				}
+				res.keys++
+			}
+		}(); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// verifyBackupDescriptors checks that every table in a backup belongs to a
+// database that is also in it and that the backup covers the spans of all of
+// the table's indexes.
+func verifyBackupDescriptors(desc BackupDescriptor) error {
+	spans := append(roachpb.Spans(nil), desc.Spans...)
+	sort.Sort(spans)
+	databases := make(map[sqlbase.ID]struct{})
+	for _, descriptor := range desc.Descriptors {
+		if database := descriptor.GetDatabase(); database != nil {
+			databases[database.ID] = struct{}{}
+		}
+	}
+	for _, descriptor := range desc.Descriptors {
+		table := descriptor.GetTable()
+		if table == nil || table.Dropped() {
+			continue
+		}
+		if _, ok := databases[table.ParentID]; !ok {
+			return errors.Errorf("table %q references database %d, which is not in the backup",
+				table.Name, table.ParentID)
+		}
+		for _, index := range table.AllNonDropIndexes() {
+			if span := table.IndexSpan(index.ID); !spansCover(spans, span) {
+				return errors.Errorf("index %q of table %q (%s) is not covered by the backup",
+					index.Name, table.Name, span)
+			}
+		}
+	}
+	return nil
+}
+
+// fingerprintBackupIndex computes the fingerprint, as SHOW
+// EXPERIMENTAL_FINGERPRINTS would at the end time of the last backup, of an
+// index from the data in a chain of backups.
+func fingerprintBackupIndex(
+	ctx context.Context,
+	evalCtx *parser.EvalContext,
+	backups []BackupDescriptor,
+	encryptionKey []byte,
+	table *sqlbase.TableDescriptor,
+	index sqlbase.IndexDescriptor,
+) (parser.Datum, error) {
+	fingerprinter := sql.MakeIndexFingerprinter(table, index)
+
+	colIdxMap := make(map[sqlbase.ColumnID]int, len(table.Columns))
+	for i, col := range table.Columns {
+		colIdxMap[col.ID] = i
+	}
+	var rf sqlbase.RowFetcher
+	if err := rf.Init(
+		table, colIdxMap, &index, false /* reverse */, index.ID != table.PrimaryIndex.ID,
+		table.Columns, fingerprinter.NeededColumns(len(table.Columns)), false, /* returnRangeInfo */
+	); err != nil {
+		return nil, err
+	}
+
+	// This reuses Restore's logic for finding the files that hold each part of
+	// the index. The files are split at row boundaries, so each entry holds
+	// whole rows.
+	entries, endTime, err := makeImportRequests([]roachpb.Span{table.IndexSpan(index.ID)}, backups)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		kvs, err := readBackupKVs(ctx, entry.Span, entry.files, endTime, encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(kvs) == 0 {
+			continue
+		}
+		if err := rf.StartScanFrom(ctx, kvs); err != nil {
+			return nil, err
+		}
+		for {
+			row, err := rf.NextRowDecoded(ctx, false /* traceKV */)
+			if err != nil {
+				return nil, err
+			}
+			if row == nil {
+				break
+			}
+			if err := fingerprinter.AddRow(evalCtx, row); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return fingerprinter.Fingerprint(), nil
+}
+
+// readBackupKVs returns the latest value as of endTime of each key in span
+// that was not deleted, as recorded in files.
+func readBackupKVs(
+	ctx context.Context,
+	span roachpb.Span,
+	files []roachpb.ImportRequest_File,
+	endTime hlc.Timestamp,
+	encryptionKey []byte,
+) ([]client.KeyValue, error) {
+	iters := make([]engine.SimpleIterator, 0, len(files))
+	defer func() {
+		for _, iter := range iters {
+			iter.Close()
+		}
+	}()
+	for _, file := range files {
+		fileContents, err := readBackupFile(ctx, file, encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		iter, err := engineccl.NewMemSSTIterator(fileContents)
+		if err != nil {
+			return nil, err
+		}
+		iters = append(iters, iter)
+	}
+
+	var kvs []client.KeyValue
+	iter := engineccl.MakeMultiIterator(iters)
+	endKeyMVCC := engine.MVCCKey{Key: span.EndKey}
+	for iter.Seek(engine.MVCCKey{Key: span.Key}); ; {
+		ok, err := iter.Valid()
+		if err != nil {
+			return nil, err
+		}
+		if !ok || !iter.UnsafeKey().Less(endKeyMVCC) {
+			break
+		}
+		if endTime.Less(iter.UnsafeKey().Timestamp) {
+			iter.Next()
+			continue
+		}
+		if len(iter.UnsafeValue()) == 0 {
+			// Value is deleted.
+			iter.NextKey()
+			continue
+		}
+		kvs = append(kvs, client.KeyValue{
+			Key:   append(roachpb.Key(nil), iter.UnsafeKey().Key...),
+			Value: &roachpb.Value{RawBytes: append([]byte(nil), iter.UnsafeValue()...)},
+		})
+		iter.NextKey()
+	}
+	return kvs, nil
+}
+
+// fingerprintBackups returns the fingerprint of every index of every table in
+// the last of a chain of backups, computed from the data in the chain.
+func fingerprintBackups(
+	ctx context.Context,
+	evalCtx *parser.EvalContext,
+	backups []BackupDescriptor,
+	encryptionKey []byte,
+) ([]parser.Datums, error) {
+	last := backups[len(backups)-1]
+	databases := make(map[sqlbase.ID]string)
+	for _, descriptor := range last.Descriptors {
+		if database := descriptor.GetDatabase(); database != nil {
+			databases[database.ID] = database.Name
+		}
+	}
+	var ret []parser.Datums
+	for _, descriptor := range last.Descriptors {
+		table := descriptor.GetTable()
+		if table == nil || table.Dropped() || !table.IsPhysicalTable() {
+			continue
+		}
+		for _, index := range table.AllNonDropIndexes() {
+			fingerprint, err := fingerprintBackupIndex(ctx, evalCtx, backups, encryptionKey, table, index)
+			if err != nil {
+				return nil, errors.Wrapf(err, "fingerprinting index %q of table %q", index.Name, table.Name)
+			}
+			ret = append(ret, parser.Datums{
+				parser.NewDString(databases[table.ParentID]),
+				parser.NewDString(table.Name),
+				parser.NewDString(index.Name),
+				fingerprint,
+			})
+		}
+	}
+	return ret, nil
+}
+
+// verifyBackupPlanHook implements VERIFY BACKUP, which reads every file of a
+// chain of backups, given in the order they were taken, to check that they
+// are complete, readable and consistent with their BackupDescriptors. The
+// first problem found is returned as an error. With the fingerprints option,
+// it returns the fingerprint of each index in the backups, which can be
+// compared to the output of SHOW EXPERIMENTAL_FINGERPRINTS.
+func verifyBackupPlanHook(
+	stmt parser.Statement, p sql.PlanHookState,
+) (func(context.Context) ([]parser.Datums, error), sqlbase.ResultColumns, error) {
+	verify, ok := stmt.(*parser.VerifyBackup)
+	if !ok {
+		return nil, nil, nil
+	}
+
+	if err := utilccl.CheckEnterpriseEnabled(p.ExecCfg().ClusterID(), "VERIFY BACKUP"); err != nil {
+		return nil, nil, err
+	}
+
+	if err := p.RequireSuperUser("VERIFY BACKUP"); err != nil {
+		return nil, nil, err
+	}
+
+	fromFn, err := p.TypeAsStringArray(verify.From, "VERIFY BACKUP")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, fingerprints := verify.Options.Get(verifyBackupOptFingerprints)
+	header := sqlbase.ResultColumns{
+		{Name: "uri", Typ: parser.TypeString},
+		{Name: "start_time", Typ: parser.TypeTimestamp},
+		{Name: "end_time", Typ: parser.TypeTimestamp},
+		{Name: "files", Typ: parser.TypeInt},
+		{Name: "keys", Typ: parser.TypeInt},
+		{Name: "bytes", Typ: parser.TypeInt},
+	}
+	if fingerprints {
+		header = sqlbase.ResultColumns{
+			{Name: "database", Typ: parser.TypeString},
+			{Name: "table", Typ: parser.TypeString},
+			{Name: "index", Typ: parser.TypeString},
+			{Name: "fingerprint", Typ: parser.TypeString},
+		}
+	}
+	fn := func(ctx context.Context) ([]parser.Datums, error) {
+		// TODO(dan): Move this span into sql.
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer tracing.FinishSpan(span)
+
+		from, err := fromFn()
+		if err != nil {
+			return nil, err
+		}
+		var encryptionKey []byte
+		if len(from) > 0 {
+			if encryptionKey, _, err = backupEncryptionKey(ctx, verify.Options, from[0]); err != nil {
+				return nil, err
+			}
+		}
+		backups, err := loadBackupDescs(ctx, from, encryptionKey)
+		if err != nil {
+			return nil, err
+		}
+		// Check that the backups line up in time, the same way Restore does.
+		if _, _, err := makeImportRequests(nil, backups); err != nil {
+			return nil, err
+		}
+
+		var ret []parser.Datums
+		for i, backup := range backups {
+			uri, err := storageccl.SanitizeExportStorageURI(from[i])
+			if err != nil {
+				return nil, err
+			}
+			res, err := verifyBackupFiles(ctx, backup, encryptionKey)
+			if err != nil {
+				return nil, errors.Wrapf(err, "backup %s", uri)
+			}
+			if err := verifyBackupDescriptors(backup); err != nil {
+				return nil, errors.Wrapf(err, "backup %s", uri)
+			}
+			ret = append(ret, parser.Datums{
+				parser.NewDString(uri),
+				parser.MakeDTimestamp(time.Unix(0, backup.StartTime.WallTime), time.Microsecond),
+				parser.MakeDTimestamp(time.Unix(0, backup.EndTime.WallTime), time.Microsecond),
+				parser.NewDInt(parser.DInt(res.files)),
+				parser.NewDInt(parser.DInt(res.keys)),
+				parser.NewDInt(parser.DInt(res.bytes)),
+			})
+		}
+		if fingerprints {
+			evalCtx := p.EvalContext()
+			return fingerprintBackups(ctx, &evalCtx, backups, encryptionKey)
+		}
+		return ret, nil
+	}
+	return fn, header, nil
+}
+
+func init() {
+	sql.AddPlanHook(verifyBackupPlanHook)
+}
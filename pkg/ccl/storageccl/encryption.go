@@ -0,0 +1,115 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package storageccl
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+
+	"github.com/pkg/errors"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// encryptionPreamble is a constant string prepended in cleartext to encrypted
+// files, allowing them to be distinguished from unencrypted ones.
+const encryptionPreamble = "encrypt"
+
+const (
+	encryptionSaltSize    = 16
+	encryptionVersionSize = 1
+	// encryptionVersionGCM is AES-256 in GCM mode, with a random nonce
+	// prepended to the ciphertext.
+	encryptionVersionGCM = 1
+	nonceSize            = 12 // GCM standard nonce.
+	headerSize           = len(encryptionPreamble) + encryptionVersionSize + nonceSize
+
+	// keyDerivationIterations is the number of PBKDF2 iterations used to
+	// derive a key from a passphrase.
+	keyDerivationIterations = 64000
+	// encryptionKeySize is the size of the derived key, selecting AES-256.
+	encryptionKeySize = 32
+)
+
+// GenerateSalt returns a new random salt for use with GenerateKey.
+func GenerateSalt() ([]byte, error) {
+	salt := make([]byte, encryptionSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// GenerateKey derives an encryption key from the given passphrase and salt.
+func GenerateKey(passphrase, salt []byte) []byte {
+	return pbkdf2.Key(passphrase, salt, keyDerivationIterations, encryptionKeySize, sha256.New)
+}
+
+// AppearsEncrypted checks if passed bytes begin with an encryption preamble.
+func AppearsEncrypted(text []byte) bool {
+	return bytes.HasPrefix(text, []byte(encryptionPreamble))
+}
+
+// EncryptFile encrypts a file with the supplied key using AES-GCM, prefixed
+// with the encryption preamble, a version byte and the random nonce.
+func EncryptFile(plaintext, key []byte) ([]byte, error) {
+	gcm, err := aesgcm(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext := make([]byte, headerSize, headerSize+len(plaintext)+gcm.Overhead())
+	copy(ciphertext, encryptionPreamble)
+	ciphertext[len(encryptionPreamble)] = encryptionVersionGCM
+	nonce := ciphertext[len(encryptionPreamble)+encryptionVersionSize:]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(ciphertext, nonce, plaintext, nil), nil
+}
+
+// DecryptFile decrypts a file encrypted by EncryptFile, using the supplied key.
+// An error is returned if the key is incorrect or the file has been modified.
+func DecryptFile(ciphertext, key []byte) ([]byte, error) {
+	if !AppearsEncrypted(ciphertext) {
+		return nil, errors.New("file does not appear to be encrypted")
+	}
+	ciphertext = ciphertext[len(encryptionPreamble):]
+
+	if len(ciphertext) < encryptionVersionSize+nonceSize {
+		return nil, errors.New("invalid encryption header")
+	}
+	if version := ciphertext[0]; version != encryptionVersionGCM {
+		return nil, errors.Errorf("unexpected encryption version %d", version)
+	}
+	ciphertext = ciphertext[encryptionVersionSize:]
+
+	gcm, err := aesgcm(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt (incorrect passphrase or key?)")
+	}
+	return plaintext, nil
+}
+
+func aesgcm(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,78 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package storageccl
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	salt, err := GenerateSalt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := GenerateKey([]byte("passphrase"), salt)
+	if !bytes.Equal(key, GenerateKey([]byte("passphrase"), salt)) {
+		t.Fatal("expected key derivation to be deterministic")
+	}
+
+	for _, plaintext := range [][]byte{
+		nil,
+		[]byte("a"),
+		[]byte("the quick brown fox jumps over the lazy dog"),
+		bytes.Repeat([]byte("x"), 1<<16),
+	} {
+		ciphertext, err := EncryptFile(plaintext, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !AppearsEncrypted(ciphertext) {
+			t.Fatal("expected ciphertext to appear encrypted")
+		}
+		if len(plaintext) > 0 && bytes.Contains(ciphertext, plaintext) {
+			t.Fatal("expected ciphertext to not contain plaintext")
+		}
+
+		decrypted, err := DecryptFile(ciphertext, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(plaintext, decrypted) {
+			t.Fatalf("expected %q got %q", plaintext, decrypted)
+		}
+
+		t.Run("wrong key", func(t *testing.T) {
+			wrongKey := GenerateKey([]byte("wrong"), salt)
+			if _, err := DecryptFile(ciphertext, wrongKey); !testutils.IsError(err, "incorrect passphrase") {
+				t.Fatalf("expected wrong key error, got %v", err)
+			}
+		})
+
+		t.Run("modified", func(t *testing.T) {
+			modified := append([]byte(nil), ciphertext...)
+			modified[len(modified)-1] ^= 1
+			if _, err := DecryptFile(modified, key); !testutils.IsError(err, "failed to decrypt") {
+				t.Fatalf("expected authentication error, got %v", err)
+			}
+		})
+	}
+
+	if AppearsEncrypted([]byte("plain sst contents")) {
+		t.Fatal("expected plaintext to not appear encrypted")
+	}
+	if _, err := DecryptFile([]byte("plain sst contents"), key); !testutils.IsError(err, "does not appear to be encrypted") {
+		t.Fatalf("expected not encrypted error, got %v", err)
+	}
+}
@@ -21,12 +21,14 @@ import (
 // [startKey,endKey) and time range [startTime,endTime). If a key was added or
 // modified between startTime and endTime, the iterator will position at the
 // most recent version (before endTime) of that key. If the key was most
-// recently deleted, this is signalled with an empty value.
+// recently deleted, this is signalled with an empty value. NextKey moves on to
+// the next key, while Next moves on to the next (older) version of the same
+// key, as long as that version is not older than startTime.
 //
 // Expected usage:
 //    iter := NewMVCCIncrementalIterator(e)
 //    defer iter.Close()
-//    for iter.Reset(startKey, endKey, ...); iter.Valid(); iter.Next() {
+//    for iter.Reset(startKey, endKey, ...); iter.Valid(); iter.NextKey() {
 //        [code using iter.Key() and iter.Value()]
 //    }
 //    if err := iter.Error(); err != nil {
@@ -42,7 +44,6 @@ type MVCCIncrementalIterator struct {
 	endTime   hlc.Timestamp
 	err       error
 	valid     bool
-	nextkey   bool
 
 	// For allocation avoidance.
 	meta enginepb.MVCCMetadata
@@ -82,8 +83,7 @@ func (i *MVCCIncrementalIterator) Reset(startKey, endKey roachpb.Key) {
 	i.endKey = engine.MakeMVCCMetadataKey(endKey)
 	i.err = nil
 	i.valid = true
-	i.nextkey = false
-	i.Next()
+	i.advance()
 }
 
 // Close frees up resources held by the iterator.
@@ -91,8 +91,30 @@ func (i *MVCCIncrementalIterator) Close() {
 	i.iter.Close()
 }
 
-// Next advances the iterator to the next key/value in the iteration.
+// Next advances the iterator to the next key/value in the iteration. If there
+// is a version of the current key older than the current one but in the time
+// range, it's positioned at that version, otherwise at the next key.
 func (i *MVCCIncrementalIterator) Next() {
+	if !i.valid {
+		return
+	}
+	i.iter.Next()
+	i.advance()
+}
+
+// NextKey advances the iterator to the next key/value in the iteration,
+// skipping any remaining versions of the current key.
+func (i *MVCCIncrementalIterator) NextKey() {
+	if !i.valid {
+		return
+	}
+	i.iter.NextKey()
+	i.advance()
+}
+
+// advance positions the underlying iterator at the next version that is in
+// the time range, starting with the one it's currently at.
+func (i *MVCCIncrementalIterator) advance() {
 	for {
 		if !i.valid {
 			return
@@ -103,12 +125,6 @@ func (i *MVCCIncrementalIterator) Next() {
 			return
 		}
 
-		if i.nextkey {
-			i.nextkey = false
-			i.iter.NextKey()
-			continue
-		}
-
 		unsafeMetaKey := i.iter.UnsafeKey()
 		if !unsafeMetaKey.Less(i.endKey) {
 			i.valid = false
@@ -162,7 +178,6 @@ func (i *MVCCIncrementalIterator) Next() {
 			continue
 		}
 
-		i.nextkey = true
 		break
 	}
 }
@@ -190,13 +205,13 @@ func (i *MVCCIncrementalIterator) Value() []byte {
 }
 
 // UnsafeKey returns the same key as Key, but the memory is invalidated on the
-// next call to {Next,Reset,Close}.
+// next call to {Next,NextKey,Reset,Close}.
 func (i *MVCCIncrementalIterator) UnsafeKey() engine.MVCCKey {
 	return i.iter.UnsafeKey()
 }
 
 // UnsafeValue returns the same value as Value, but the memory is invalidated on
-// the next call to {Next,Reset,Close}.
+// the next call to {Next,NextKey,Reset,Close}.
 func (i *MVCCIncrementalIterator) UnsafeValue() []byte {
 	return i.iter.UnsafeValue()
 }
@@ -34,7 +34,7 @@ func iterateExpectErr(
 	return func(t *testing.T) {
 		iter := NewMVCCIncrementalIterator(e, startTime, endTime)
 		defer iter.Close()
-		for iter.Reset(startKey, endKey); iter.Valid(); iter.Next() {
+		for iter.Reset(startKey, endKey); iter.Valid(); iter.NextKey() {
 			// pass
 		}
 		if err := iter.Error(); !testutils.IsError(err, errString) {
@@ -48,13 +48,28 @@ func assertEqualKVs(
 	startKey, endKey roachpb.Key,
 	startTime, endTime hlc.Timestamp,
 	expected []engine.MVCCKeyValue,
+) func(*testing.T) {
+	return assertEqualKVsRevisions(e, startKey, endKey, startTime, endTime, false, expected)
+}
+
+func assertEqualKVsRevisions(
+	e engine.Engine,
+	startKey, endKey roachpb.Key,
+	startTime, endTime hlc.Timestamp,
+	allRevisions bool,
+	expected []engine.MVCCKeyValue,
 ) func(*testing.T) {
 	return func(t *testing.T) {
 		iter := NewMVCCIncrementalIterator(e, startTime, endTime)
 		defer iter.Close()
 		var kvs []engine.MVCCKeyValue
-		for iter.Reset(startKey, endKey); iter.Valid(); iter.Next() {
+		for iter.Reset(startKey, endKey); iter.Valid(); {
 			kvs = append(kvs, engine.MVCCKeyValue{Key: iter.Key(), Value: iter.Value()})
+			if allRevisions {
+				iter.Next()
+			} else {
+				iter.NextKey()
+			}
 		}
 
 		if len(kvs) != len(expected) {
@@ -146,12 +161,22 @@ func runMVCCIterateIncremental(t *testing.T) {
 	t.Run("kv 1-1", assertEqualKVs(e, testKey1, testKey1, ts0, tsMax, nil))
 	t.Run("kv 1-2", assertEqualKVs(e, testKey1, testKey2, ts0, tsMax, kvs(kv1_2_2)))
 
+	// Exercise iterating over all revisions.
+	t.Run("revisions ts 0-∞", assertEqualKVsRevisions(
+		e, keyMin, keyMax, ts0, tsMax, true, kvs(kv1_2_2, kv1_1_1, kv2_2_2)))
+	t.Run("revisions ts 1-2", assertEqualKVsRevisions(
+		e, keyMin, keyMax, ts1, ts2, true, kvs(kv1_1_1)))
+	t.Run("revisions ts 2-∞", assertEqualKVsRevisions(
+		e, keyMin, keyMax, ts2, tsMax, true, kvs(kv1_2_2, kv2_2_2)))
+
 	// Exercise deletion.
 	if err := engine.MVCCDelete(ctx, e, nil, testKey1, ts3, nil); err != nil {
 		t.Fatal(err)
 	}
 	mustFlush()
 	t.Run("del", assertEqualKVs(e, keyMin, keyMax, ts0, tsMax, kvs(kv1_3Deleted, kv2_2_2)))
+	t.Run("del revisions", assertEqualKVsRevisions(
+		e, keyMin, keyMax, ts0, tsMax, true, kvs(kv1_3Deleted, kv1_2_2, kv1_1_1, kv2_2_2)))
 
 	// Exercise intent handling.
 	txn1ID := uuid.MakeV4()
@@ -260,7 +285,7 @@ func TestMVCCIterateTimeBound(t *testing.T) {
 			defer iter.Close()
 
 			var expectedKVs []engine.MVCCKeyValue
-			for iter.Reset(keys.MinKey, keys.MaxKey); iter.Valid(); iter.Next() {
+			for iter.Reset(keys.MinKey, keys.MaxKey); iter.Valid(); iter.NextKey() {
 				expectedKVs = append(expectedKVs, engine.MVCCKeyValue{Key: iter.Key(), Value: iter.Value()})
 			}
 
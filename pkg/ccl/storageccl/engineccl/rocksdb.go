@@ -154,6 +154,7 @@ func cStatsToGoStats(stats C.MVCCStatsResult, nowNanos int64) (enginepb.MVCCStat
 	ms.GCBytesAge = int64(stats.gc_bytes_age)
 	ms.SysBytes = int64(stats.sys_bytes)
 	ms.SysCount = int64(stats.sys_count)
+	ms.RangeTombstoneCount = int64(stats.range_tombstone_count)
 	ms.LastUpdateNanos = nowNanos
 	return ms, nil
 }
@@ -12,6 +12,7 @@ import (
 	"bytes"
 	"crypto/sha512"
 	"fmt"
+	"sort"
 
 	"golang.org/x/net/context"
 
@@ -53,12 +54,17 @@ func declareKeysExport(
 	spans.Add(storage.SpanReadOnly, roachpb.Span{Key: keys.RangeLastGCKey(header.RangeID)})
 }
 
-type rowCounter struct {
+// RowCounter is a helper that counts how many distinct rows appear in the KVs
+// that is is shown via `Count`. Note: the `DataSize` field of the BulkOpSummary
+// is *not* populated by this and should be set separately.
+type RowCounter struct {
 	prev roachpb.Key
 	roachpb.BulkOpSummary
 }
 
-func (r *rowCounter) count(key roachpb.Key) error {
+// Count examines each key passed to it and increments the running count when it
+// sees a key that belongs to a new row.
+func (r *RowCounter) Count(key roachpb.Key) error {
 	// EnsureSafeSplitKey is usually used to avoid splitting a row across ranges,
 	// by returning the row's key prefix.
 	// We reuse it here to count "rows" by counting when it changes.
@@ -140,12 +146,54 @@ func evalExport(
 	}
 	defer sst.Close()
 
-	var rows rowCounter
+	// The keys deleted by range tombstones are exported as deleted at the
+	// timestamp of the tombstone, merged into the versions found by the
+	// iterator below.
+	deletions, err := rangeTombstoneDeletions(ctx, batch, args.Span, args.StartTime, h.Timestamp)
+	if err != nil {
+		return storage.EvalResult{}, err
+	}
+	if !args.AllRevisions {
+		deletions = latestDeletions(deletions)
+	}
+	addDeletion := func(key engine.MVCCKey) error {
+		if log.V(3) {
+			log.Infof(ctx, "Export %s (range tombstone)", key)
+		}
+		if err := sst.Add(engine.MVCCKeyValue{Key: key}); err != nil {
+			return errors.Wrapf(err, "adding key %s", key)
+		}
+		return nil
+	}
+
+	var rows RowCounter
 	// TODO(dan): Move all this iteration into cpp to avoid the cgo calls.
 	// TODO(dan): Consider checking ctx periodically during the MVCCIterate call.
 	iter := engineccl.NewMVCCIncrementalIterator(batch, args.StartTime, h.Timestamp)
 	defer iter.Close()
-	for iter.Reset(args.Key, args.EndKey); iter.Valid(); iter.Next() {
+	for iter.Reset(args.Key, args.EndKey); iter.Valid(); {
+		var shadowed bool
+		for len(deletions) > 0 && deletions[0].Less(iter.UnsafeKey()) {
+			if err := addDeletion(deletions[0]); err != nil {
+				return storage.EvalResult{}, err
+			}
+			// Unless all revisions are exported, a deletion more recent than
+			// the version found by the iterator replaces it.
+			shadowed = !args.AllRevisions && deletions[0].Key.Equal(iter.UnsafeKey().Key)
+			deletions = deletions[1:]
+			if shadowed {
+				break
+			}
+		}
+		if shadowed {
+			iter.NextKey()
+			continue
+		}
+		if !args.AllRevisions && len(deletions) > 0 && deletions[0].Key.Equal(iter.UnsafeKey().Key) {
+			// The version is more recent than the deletion.
+			deletions = deletions[1:]
+		}
+
 		if log.V(3) {
 			v := roachpb.Value{RawBytes: iter.UnsafeValue()}
 			log.Infof(ctx, "Export %s %s", iter.UnsafeKey(), v.PrettyPrint())
@@ -153,19 +201,30 @@ func evalExport(
 
 		// Pass only non-tombstone KVs to the row counter.
 		if len(iter.UnsafeValue()) != 0 {
-			if err := rows.count(iter.UnsafeKey().Key); err != nil {
+			if err := rows.Count(iter.UnsafeKey().Key); err != nil {
 				return storage.EvalResult{}, errors.Wrapf(err, "decoding %s", iter.UnsafeKey())
 			}
 		}
 		if err := sst.Add(engine.MVCCKeyValue{Key: iter.UnsafeKey(), Value: iter.UnsafeValue()}); err != nil {
 			return storage.EvalResult{}, errors.Wrapf(err, "adding key %s", iter.UnsafeKey())
 		}
+
+		if args.AllRevisions {
+			iter.Next()
+		} else {
+			iter.NextKey()
+		}
 	}
 	if err := iter.Error(); err != nil {
 		// The error may be a WriteIntentError. In which case, returning it will
 		// cause this command to be retried.
 		return storage.EvalResult{}, err
 	}
+	for _, key := range deletions {
+		if err := addDeletion(key); err != nil {
+			return storage.EvalResult{}, err
+		}
+	}
 
 	if sst.DataSize == 0 {
 		// Let the defer Close the sstable.
@@ -179,6 +238,13 @@ func evalExport(
 		return storage.EvalResult{}, err
 	}
 
+	if args.EncryptionKey != nil {
+		sstContents, err = EncryptFile(sstContents, args.EncryptionKey)
+		if err != nil {
+			return storage.EvalResult{}, err
+		}
+	}
+
 	// Compute the checksum before we upload and remove the local file.
 	checksum, err := sha512ChecksumData(sstContents)
 	if err != nil {
@@ -200,6 +266,61 @@ func evalExport(
 	return storage.EvalResult{}, nil
 }
 
+// rangeTombstoneDeletions returns, in MVCC key order, a deletion at the
+// timestamp of every range tombstone written in (startTime, endTime] for each
+// key in the span which was live just before it. Without these, restoring an
+// incremental backup would miss the deletion of the keys which weren't
+// otherwise written to in its time window.
+func rangeTombstoneDeletions(
+	ctx context.Context,
+	batch engine.Reader,
+	span roachpb.Span,
+	startTime, endTime hlc.Timestamp,
+) ([]engine.MVCCKey, error) {
+	tombstones, err := engine.MVCCRangeTombstones(batch, span.Key, span.EndKey, endTime)
+	if err != nil {
+		return nil, err
+	}
+	var deletions []engine.MVCCKey
+	for _, t := range tombstones {
+		if !startTime.Less(t.Timestamp) {
+			continue
+		}
+		startKey, endKey := t.StartKey, t.EndKey
+		if bytes.Compare(startKey, span.Key) < 0 {
+			startKey = span.Key
+		}
+		if bytes.Compare(span.EndKey, endKey) < 0 {
+			endKey = span.EndKey
+		}
+		ts := t.Timestamp
+		if _, err := engine.MVCCIterate(
+			ctx, batch, startKey, endKey, ts.Prev(), true /* consistent */, nil, /* txn */
+			false /* reverse */, func(kv roachpb.KeyValue) (bool, error) {
+				deletions = append(deletions, engine.MVCCKey{Key: kv.Key, Timestamp: ts})
+				return false, nil
+			},
+		); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(deletions, func(i, j int) bool { return deletions[i].Less(deletions[j]) })
+	return deletions, nil
+}
+
+// latestDeletions returns the most recent of the deletions of every key,
+// which must be in MVCC key order.
+func latestDeletions(deletions []engine.MVCCKey) []engine.MVCCKey {
+	var latest []engine.MVCCKey
+	for _, key := range deletions {
+		if n := len(latest); n > 0 && latest[n-1].Key.Equal(key.Key) {
+			continue
+		}
+		latest = append(latest, key)
+	}
+	return latest
+}
+
 func sha512ChecksumData(data []byte) ([]byte, error) {
 	h := sha512.New()
 	if _, err := h.Write(data); err != nil {
@@ -39,10 +39,13 @@ func TestExport(t *testing.T) {
 	sqlDB := sqlutils.MakeSQLRunner(t, tc.Conns[0])
 	kvDB := tc.Server(0).KVClient().(*client.DB)
 
-	exportAndSlurp := func(start hlc.Timestamp) (hlc.Timestamp, []string, []engine.MVCCKeyValue) {
+	exportAndSlurp := func(
+		start hlc.Timestamp, allRevisions bool,
+	) (hlc.Timestamp, []string, []engine.MVCCKeyValue) {
 		req := &roachpb.ExportRequest{
-			Span:      roachpb.Span{Key: keys.UserTableDataMin, EndKey: keys.MaxKey},
-			StartTime: start,
+			Span:         roachpb.Span{Key: keys.UserTableDataMin, EndKey: keys.MaxKey},
+			StartTime:    start,
+			AllRevisions: allRevisions,
 			Storage: roachpb.ExportStorage{
 				Provider:  roachpb.ExportStorageProvider_LocalFile,
 				LocalFile: roachpb.ExportStorage_LocalFilePath{Path: dir},
@@ -85,7 +88,7 @@ func TestExport(t *testing.T) {
 	sqlDB.Exec(`CREATE DATABASE export`)
 	sqlDB.Exec(`CREATE TABLE export.export (id INT PRIMARY KEY)`)
 	sqlDB.Exec(`INSERT INTO export.export VALUES (1), (3)`)
-	ts1, paths1, kvs1 := exportAndSlurp(hlc.Timestamp{})
+	ts1, paths1, kvs1 := exportAndSlurp(hlc.Timestamp{}, false)
 	if expected := 1; len(paths1) != expected {
 		t.Fatalf("expected %d files in export got %d", expected, len(paths1))
 	}
@@ -94,19 +97,19 @@ func TestExport(t *testing.T) {
 	}
 
 	// If nothing has changed, nothing should be exported.
-	ts2, paths2, _ := exportAndSlurp(ts1)
+	ts2, paths2, _ := exportAndSlurp(ts1, false)
 	if expected := 0; len(paths2) != expected {
 		t.Fatalf("expected %d files in export got %d", expected, len(paths2))
 	}
 
 	sqlDB.Exec(`INSERT INTO export.export VALUES (2)`)
-	ts3, _, kvs3 := exportAndSlurp(ts2)
+	ts3, _, kvs3 := exportAndSlurp(ts2, false)
 	if expected := 1; len(kvs3) != expected {
 		t.Fatalf("expected %d kvs in export got %d", expected, len(kvs3))
 	}
 
 	sqlDB.Exec(`DELETE FROM export.export WHERE id = 3`)
-	_, _, kvs4 := exportAndSlurp(ts3)
+	_, _, kvs4 := exportAndSlurp(ts3, false)
 	if expected := 1; len(kvs4) != expected {
 		t.Fatalf("expected %d kvs in export got %d", expected, len(kvs4))
 	}
@@ -116,13 +119,22 @@ func TestExport(t *testing.T) {
 	}
 
 	sqlDB.Exec(`ALTER TABLE export.export SPLIT AT VALUES (2)`)
-	_, paths5, kvs5 := exportAndSlurp(hlc.Timestamp{})
+	_, paths5, kvs5 := exportAndSlurp(hlc.Timestamp{}, false)
 	if expected := 2; len(paths5) != expected {
 		t.Fatalf("expected %d files in export got %d", expected, len(paths5))
 	}
 	if expected := 3; len(kvs5) != expected {
 		t.Fatalf("expected %d kvs in export got %d", expected, len(kvs5))
 	}
+
+	// Exporting all revisions includes both the deleted row and its tombstone.
+	_, paths6, kvs6 := exportAndSlurp(hlc.Timestamp{}, true)
+	if expected := 2; len(paths6) != expected {
+		t.Fatalf("expected %d files in export got %d", expected, len(paths6))
+	}
+	if expected := 4; len(kvs6) != expected {
+		t.Fatalf("expected %d kvs in export got %d", expected, len(kvs6))
+	}
 }
 
 func TestExportGCThreshold(t *testing.T) {
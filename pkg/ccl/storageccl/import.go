@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
@@ -209,7 +210,7 @@ func evalImport(ctx context.Context, cArgs storage.CommandArgs) (*roachpb.Import
 	defer importRequestLimiter.endLimitedRequest()
 	log.Infof(ctx, "import [%s,%s)", importStart, importEnd)
 
-	var rows rowCounter
+	var rows RowCounter
 	var iters []engine.SimpleIterator
 	for _, file := range args.Files {
 		if log.V(2) {
@@ -252,6 +253,15 @@ func evalImport(ctx context.Context, cArgs storage.CommandArgs) (*roachpb.Import
 			}
 		}
 
+		if args.EncryptionKey != nil {
+			fileContents, err = DecryptFile(fileContents, args.EncryptionKey)
+			if err != nil {
+				return nil, errors.Wrapf(err, "decrypting %q", file.Path)
+			}
+		} else if AppearsEncrypted(fileContents) {
+			return nil, errors.Errorf("%q appears to be encrypted but no key was provided", file.Path)
+		}
+
 		iter, err := engineccl.NewMemSSTIterator(fileContents)
 		if err != nil {
 			return nil, err
@@ -286,7 +296,7 @@ func evalImport(ctx context.Context, cArgs storage.CommandArgs) (*roachpb.Import
 	iter := engineccl.MakeMultiIterator(iters)
 	defer iter.Close()
 	var keyScratch, valueScratch []byte
-	for iter.Seek(startKeyMVCC); ; iter.NextKey() {
+	for iter.Seek(startKeyMVCC); ; {
 		ok, err := iter.Valid()
 		if err != nil {
 			return nil, err
@@ -294,8 +304,17 @@ func evalImport(ctx context.Context, cArgs storage.CommandArgs) (*roachpb.Import
 		if !ok || !iter.UnsafeKey().Less(endKeyMVCC) {
 			break
 		}
+		if args.EndTime != (hlc.Timestamp{}) && args.EndTime.Less(iter.UnsafeKey().Timestamp) {
+			// The files may contain revisions newer than the requested time;
+			// skip them to get to the latest one that is not.
+			// TODO(dan): If we have to skip past a lot of versions looking for
+			// the latest one before args.EndTime, then this could be slow.
+			iter.Next()
+			continue
+		}
 		if len(iter.UnsafeValue()) == 0 {
 			// Value is deleted.
+			iter.NextKey()
 			continue
 		}
 
@@ -303,6 +322,7 @@ func evalImport(ctx context.Context, cArgs storage.CommandArgs) (*roachpb.Import
 		valueScratch = append(valueScratch[:0], iter.UnsafeValue()...)
 		key := engine.MVCCKey{Key: keyScratch, Timestamp: iter.UnsafeKey().Timestamp}
 		value := roachpb.Value{RawBytes: valueScratch}
+		iter.NextKey()
 
 		key.Key, ok, err = kr.RewriteKey(key.Key)
 		if err != nil {
@@ -325,7 +345,7 @@ func evalImport(ctx context.Context, cArgs storage.CommandArgs) (*roachpb.Import
 			log.Infof(ctx, "Put %s -> %s", key.Key, value.PrettyPrint())
 		}
 
-		if err := rows.count(key.Key); err != nil {
+		if err := rows.Count(key.Key); err != nil {
 			return nil, errors.Wrapf(err, "decoding %s", key.Key)
 		}
 
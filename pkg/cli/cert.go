@@ -17,13 +17,18 @@
 package cli
 
 import (
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/identmap"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -228,11 +233,100 @@ func runListCerts(cmd *cobra.Command, args []string) error {
 	return printQueryOutput(os.Stdout, certTableHeaders, newRowSliceIter(rows), "", cliCtx.tableDisplayFormat)
 }
 
+var identityMap string
+
+// A mapIdentity command shows the users that client certificates
+// authenticate according to an identity map.
+var mapIdentityCmd = &cobra.Command{
+	Use:   "map-identity --identity-map=<identity map> [<cert-file> ...]",
+	Short: "show the users authenticated by client certs",
+	Long: `
+Show the SQL users that client certificates authenticate when the identity map
+is set as the server.identity_map.configuration cluster setting.
+
+A certificate authenticates the user named by its common name, and the users to
+which the identity map maps its common name or one of its subject alternative
+names. If no certificate file is given, the client certificates in
+"<certs-dir>" are used.
+`,
+	RunE: MaybeDecorateGRPCError(runMapIdentity),
+}
+
+// runMapIdentity loads the given certs, or the client certs of the certs
+// directory, and lists the users they authenticate.
+func runMapIdentity(cmd *cobra.Command, args []string) error {
+	conf, err := identmap.Parse(identityMap)
+	if err != nil {
+		return errors.Wrap(err, "invalid identity map")
+	}
+
+	type certFile struct {
+		filename string
+		cert     *x509.Certificate
+	}
+	var certs []certFile
+	if len(args) == 0 {
+		cm, err := baseCfg.GetCertificateManager()
+		if err != nil {
+			return errors.Wrap(err, "could not get certificate manager")
+		}
+		clientCerts := cm.ClientCerts()
+		var users []string
+		for user := range clientCerts {
+			users = append(users, user)
+		}
+		sort.Strings(users)
+		for _, user := range users {
+			ci := clientCerts[user]
+			if ci.Error != nil || len(ci.ParsedCertificates) == 0 {
+				continue
+			}
+			certs = append(certs, certFile{ci.Filename, ci.ParsedCertificates[0]})
+		}
+	}
+	for _, filename := range args {
+		contents, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		parsed, err := security.PEMContentsToX509(contents)
+		if err == nil && len(parsed) == 0 {
+			err = errors.New("no certificates found")
+		}
+		if err != nil {
+			return errors.Wrapf(err, "could not parse certificate %s", filename)
+		}
+		certs = append(certs, certFile{filename, parsed[0]})
+	}
+
+	var rows [][]string
+	for _, c := range certs {
+		rows = append(rows, []string{
+			c.filename,
+			"CN=" + c.cert.Subject.CommonName,
+			parser.Name(c.cert.Subject.CommonName).Normalize(),
+		})
+		for _, id := range identmap.CertificateIdentities(c.cert) {
+			for _, user := range conf.Map(id.Value) {
+				rows = append(rows, []string{
+					c.filename,
+					id.Field + "=" + id.Value,
+					parser.Name(user).Normalize(),
+				})
+			}
+		}
+	}
+
+	return printQueryOutput(os.Stdout, []string{"Certificate File", "Identity", "User"},
+		newRowSliceIter(rows), "", cliCtx.tableDisplayFormat)
+}
+
 var certCmds = []*cobra.Command{
 	createCACertCmd,
 	createNodeCertCmd,
 	createClientCertCmd,
 	listCertsCmd,
+	mapIdentityCmd,
 }
 
 var certCmd = &cobra.Command{
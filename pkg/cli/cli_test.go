@@ -1118,16 +1118,16 @@ func Example_user() {
 	// Output:
 	// user ls
 	// 0 rows
-	// username
+	// username	login	valid_until	connection_limit
 	// user ls --format=pretty
-	// +----------+
-	// | username |
-	// +----------+
-	// +----------+
+	// +----------+-------+-------------+------------------+
+	// | username | login | valid_until | connection_limit |
+	// +----------+-------+-------------+------------------+
+	// +----------+-------+-------------+------------------+
 	// (0 rows)
 	// user ls --format=tsv
 	// 0 rows
-	// username
+	// username	login	valid_until	connection_limit
 	// user set FOO
 	// INSERT 1
 	// user set Foo
@@ -1167,37 +1167,37 @@ func Example_user() {
 	// user set table
 	// INSERT 1
 	// user ls --format=pretty
-	// +-----------------------------------------------------------------+
-	// |                            username                             |
-	// +-----------------------------------------------------------------+
-	// | _foo                                                            |
-	// | and                                                             |
-	// | f0oo                                                            |
-	// | f_oo                                                            |
-	// | foo                                                             |
-	// | foo0                                                            |
-	// | foo_                                                            |
-	// | foofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoo |
-	// | table                                                           |
-	// | ομηρος                                                          |
-	// +-----------------------------------------------------------------+
+	// +-----------------------------------------------------------------+-------+-------------+------------------+
+	// |                            username                             | login | valid_until | connection_limit |
+	// +-----------------------------------------------------------------+-------+-------------+------------------+
+	// | _foo                                                            | true  | NULL        |               -1 |
+	// | and                                                             | true  | NULL        |               -1 |
+	// | f0oo                                                            | true  | NULL        |               -1 |
+	// | f_oo                                                            | true  | NULL        |               -1 |
+	// | foo                                                             | true  | NULL        |               -1 |
+	// | foo0                                                            | true  | NULL        |               -1 |
+	// | foo_                                                            | true  | NULL        |               -1 |
+	// | foofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoo | true  | NULL        |               -1 |
+	// | table                                                           | true  | NULL        |               -1 |
+	// | ομηρος                                                          | true  | NULL        |               -1 |
+	// +-----------------------------------------------------------------+-------+-------------+------------------+
 	// (10 rows)
 	// user rm foo
 	// DELETE 1
 	// user ls --format=pretty
-	// +-----------------------------------------------------------------+
-	// |                            username                             |
-	// +-----------------------------------------------------------------+
-	// | _foo                                                            |
-	// | and                                                             |
-	// | f0oo                                                            |
-	// | f_oo                                                            |
-	// | foo0                                                            |
-	// | foo_                                                            |
-	// | foofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoo |
-	// | table                                                           |
-	// | ομηρος                                                          |
-	// +-----------------------------------------------------------------+
+	// +-----------------------------------------------------------------+-------+-------------+------------------+
+	// |                            username                             | login | valid_until | connection_limit |
+	// +-----------------------------------------------------------------+-------+-------------+------------------+
+	// | _foo                                                            | true  | NULL        |               -1 |
+	// | and                                                             | true  | NULL        |               -1 |
+	// | f0oo                                                            | true  | NULL        |               -1 |
+	// | f_oo                                                            | true  | NULL        |               -1 |
+	// | foo0                                                            | true  | NULL        |               -1 |
+	// | foo_                                                            | true  | NULL        |               -1 |
+	// | foofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoofoo | true  | NULL        |               -1 |
+	// | table                                                           | true  | NULL        |               -1 |
+	// | ομηρος                                                          | true  | NULL        |               -1 |
+	// +-----------------------------------------------------------------+-------+-------------+------------------+
 	// (9 rows)
 }
 
@@ -1209,11 +1209,35 @@ func Example_cert() {
 	c.RunWithCAArgs([]string{"cert", "create-client", "Ομηρος"})
 	c.RunWithCAArgs([]string{"cert", "create-client", "0foo"})
 
+	// map-identity does not take a CA key, so it cannot use RunWithCAArgs.
+	fmt.Println("cert map-identity")
+	if err := Run([]string{
+		"cert", "map-identity", "--certs-dir=" + c.certsDir, "--identity-map=root Admin\n/^f(.*)$ \\1",
+	}); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println("cert map-identity")
+	if err := Run([]string{
+		"cert", "map-identity", "--certs-dir=" + c.certsDir, "--identity-map=root",
+	}); err != nil {
+		fmt.Println(err)
+	}
+
 	// Output:
 	// cert create-client foo
 	// cert create-client Ομηρος
 	// cert create-client 0foo
 	// failed to generate client certificate and key: username "0foo" invalid; usernames are case insensitive, must start with a letter or underscore, may contain letters, digits or underscores, and must not exceed 63 characters
+	// cert map-identity
+	// 5 rows
+	// Certificate File	Identity	User
+	// client.foo.crt	CN=foo	foo
+	// client.foo.crt	CN=foo	oo
+	// client.root.crt	CN=root	root
+	// client.root.crt	CN=root	admin
+	// client.ομηρος.crt	CN=ομηρος	ομηρος
+	// cert map-identity
+	// invalid identity map: line 1: expected 2 fields, found 1
 }
 
 // TestFlagUsage is a basic test to make sure the fragile
@@ -1616,6 +1640,10 @@ writing ` + os.DevNull + `
   debug/nodes/1/ranges/8
   debug/nodes/1/ranges/9
   debug/nodes/1/ranges/10
+  debug/nodes/1/ranges/11
+  debug/nodes/1/ranges/12
+  debug/nodes/1/ranges/13
+  debug/nodes/1/ranges/14
   debug/schema/system@details
   debug/schema/system/descriptor
   debug/schema/system/eventlog
@@ -1623,8 +1651,12 @@ writing ` + os.DevNull + `
   debug/schema/system/lease
   debug/schema/system/namespace
   debug/schema/system/rangelog
+  debug/schema/system/role_members
+  debug/schema/system/roles
+  debug/schema/system/schedules
   debug/schema/system/settings
   debug/schema/system/ui
+  debug/schema/system/user_options
   debug/schema/system/users
   debug/schema/system/zones
 `
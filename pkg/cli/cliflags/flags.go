@@ -315,6 +315,10 @@ following naming scheme:
   - CA certificate and key: ca.crt, ca.key
   - Server certificate and key: node.crt, node.key
   - Client certificate and key: client.<user>.crt, client.<user>.key
+  - Certificate revocation list (optional): ca.crl
+
+Certificates listed in the revocation list are rejected. The list is reloaded
+with the certificates on SIGHUP.
 
 When running client commands, the user can be specified with the --user flag.
 </PRE>
@@ -335,6 +339,16 @@ disabled by setting the environment variable COCKROACH_SKIP_KEY_PERMISSION_CHECK
 		Description: `Path to the CA key.`,
 	}
 
+	IdentityMap = FlagInfo{
+		Name: "identity-map",
+		Description: `
+Identity map to apply to the certificates, in the format of the
+server.identity_map.configuration cluster setting. For example:
+<PRE>
+
+  --identity-map="$(cat ident.conf)"`,
+	}
+
 	MaxOffset = FlagInfo{
 		Name: "max-offset",
 		Description: `
@@ -407,6 +421,19 @@ memory that the store may consume, for example:
   --store=type=mem,size=20GiB
   --store=type=mem,size=90%
 
+</PRE>
+The files of an on-disk store can be encrypted by setting the "key" field to
+the path of a file containing a 16, 24 or 32 byte AES key. To rotate the key,
+set the "key" field to the new key and list the previous keys, separated by
+colons, in the "old-key" field. New files are encrypted with the new key, and
+existing files are rewritten with it as they are compacted. The progress of
+the rotation is reported by the /_status/encryption/local endpoint. Once no
+file uses an old key, it can be removed from the "old-key" field, for example:
+<PRE>
+
+  --store=path=/mnt/ssd01,key=/mnt/keys/k1
+  --store=path=/mnt/ssd01,key=/mnt/keys/k2,old-key=/mnt/keys/k1
+
 </PRE>
 Commas are forbidden in all values, since they are used to separate fields.
 Also, if you use equal signs in the file path to a store, you must use the
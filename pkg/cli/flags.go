@@ -301,6 +301,8 @@ func init() {
 		boolFlag(f, &overwriteFiles, cliflags.OverwriteFiles, false)
 	}
 
+	stringFlag(mapIdentityCmd.Flags(), &identityMap, cliflags.IdentityMap, "")
+
 	boolFlag(setUserCmd.Flags(), &password, cliflags.Password, false)
 
 	clientCmds := []*cobra.Command{
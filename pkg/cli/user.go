@@ -55,7 +55,7 @@ var lsUsersCmd = &cobra.Command{
 	Use:   "ls [options]",
 	Short: "list all users",
 	Long: `
-List all users.
+List all users, with their login restrictions.
 `,
 	RunE: MaybeDecorateGRPCError(runLsUsers),
 }
@@ -70,7 +70,11 @@ func runLsUsers(cmd *cobra.Command, args []string) error {
 	}
 	defer conn.Close()
 	return runQueryAndFormatResults(conn, os.Stdout,
-		makeQuery(`SELECT username FROM system.users`), cliCtx.tableDisplayFormat)
+		makeQuery(`SELECT u.username, COALESCE(NOT o."noLogin", true) AS login, `+
+			`o."validUntil" AS valid_until, COALESCE(o."connectionLimit", -1) AS connection_limit `+
+			`FROM system.users AS u LEFT JOIN system.user_options AS o ON u.username = o.username `+
+			`ORDER BY u.username`),
+		cliCtx.tableDisplayFormat)
 }
 
 // A rmUserCmd command removes the user for the specified username.
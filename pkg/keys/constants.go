@@ -69,8 +69,9 @@ var (
 	// key suffixes.
 	localSuffixLength = 4
 
-	// There are three types of local key data enumerated below:
-	// store-local, range-local by ID, and range-local by key.
+	// There are four types of local key data enumerated below:
+	// store-local, range-local by ID, range-local by key and range
+	// tombstones.
 
 	// localStorePrefix is the prefix identifying per-store data.
 	localStorePrefix = makeKey(localPrefix, roachpb.Key("s"))
@@ -174,6 +175,15 @@ var (
 	// LocalQueueLastProcessedSuffix is the suffix for replica queue state keys.
 	LocalQueueLastProcessedSuffix = roachpb.RKey("qlpt")
 
+	// LocalRangeTombstonePrefix is the prefix for MVCC range tombstones.
+	// Each tombstone fragment is stored under the end key of the span it
+	// deletes, encoded using EncodeBytes, as a versioned value whose
+	// timestamp is the deletion timestamp and whose value is the start key
+	// of the span. Fragments never overlap, which means the fragment
+	// covering a key, if any, is the first one found at or after it.
+	LocalRangeTombstonePrefix = roachpb.Key(makeKey(localPrefix, roachpb.RKey("t")))
+	LocalRangeTombstoneMax    = LocalRangeTombstonePrefix.PrefixEnd()
+
 	// Meta1Prefix is the first level of key addressing. It is selected such that
 	// all range addressing records sort before any system tables which they
 	// might describe. The value is a RangeDescriptor struct.
@@ -289,11 +299,15 @@ const (
 	// Reserved IDs for other system tables. If you're adding a new system table,
 	// it probably belongs here.
 	// NOTE: IDs must be <= MaxReservedDescID.
-	LeaseTableID      = 11
-	EventLogTableID   = 12
-	RangeEventTableID = 13
-	UITableID         = 14
-	JobsTableID       = 15
+	LeaseTableID       = 11
+	EventLogTableID    = 12
+	RangeEventTableID  = 13
+	UITableID          = 14
+	JobsTableID        = 15
+	SchedulesTableID   = 19
+	RolesTableID       = 20
+	RoleMembersTableID = 21
+	UserOptionsTableID = 22
 
 	// Reserved IDs used to refer to certain parts of the system ranges that
 	// come before the system config span and user table ranges.
@@ -337,6 +337,39 @@ func QueueLastProcessedKey(key roachpb.RKey, queue string) roachpb.Key {
 	return MakeRangeKey(key, LocalQueueLastProcessedSuffix, roachpb.RKey(queue))
 }
 
+// RangeTombstoneKey returns the key under which the fragment of an MVCC range
+// tombstone ending at the specified key is stored.
+func RangeTombstoneKey(endKey roachpb.Key) roachpb.Key {
+	buf := make(roachpb.Key, 0, len(LocalRangeTombstonePrefix)+len(endKey)+1)
+	buf = append(buf, LocalRangeTombstonePrefix...)
+	buf = encoding.EncodeBytesAscending(buf, endKey)
+	return buf
+}
+
+// DecodeRangeTombstoneKey decodes the end key of a range tombstone fragment
+// from its key.
+func DecodeRangeTombstoneKey(key roachpb.Key) (roachpb.Key, error) {
+	if !bytes.HasPrefix(key, LocalRangeTombstonePrefix) {
+		return nil, errors.Errorf("key %q does not have %q prefix",
+			key, LocalRangeTombstonePrefix)
+	}
+	_, endKey, err := encoding.DecodeBytesAscending(key[len(LocalRangeTombstonePrefix):], nil)
+	if err != nil {
+		return nil, err
+	}
+	return endKey, nil
+}
+
+// MakeRangeTombstoneSpan returns the span of the keys under which the range
+// tombstone fragments within [startKey, endKey) are stored, that is, the
+// fragments ending in (startKey, endKey].
+func MakeRangeTombstoneSpan(startKey, endKey roachpb.Key) roachpb.Span {
+	return roachpb.Span{
+		Key:    RangeTombstoneKey(startKey.Next()),
+		EndKey: RangeTombstoneKey(endKey.Next()),
+	}
+}
+
 // IsLocal performs a cheap check that returns true iff a range-local key is
 // passed, that is, a key for which `Addr` would return a non-identical RKey
 // (or a decoding error).
@@ -363,7 +396,7 @@ func IsLocal(k roachpb.Key) bool {
 // local keys incorporating a range key (start key or transaction key) are
 // addressable (e.g. range metadata and txn records). Range local keys
 // incorporating the Range ID are not (e.g. abort cache entries, and range
-// stats).
+// stats), and neither are range tombstones.
 func Addr(k roachpb.Key) (roachpb.RKey, error) {
 	if !IsLocal(k) {
 		return roachpb.RKey(k), nil
@@ -376,6 +409,9 @@ func Addr(k roachpb.Key) (roachpb.RKey, error) {
 		if bytes.HasPrefix(k, LocalRangeIDPrefix) {
 			return nil, errors.Errorf("local range ID key %q is not addressable", k)
 		}
+		if bytes.HasPrefix(k, LocalRangeTombstonePrefix) {
+			return nil, errors.Errorf("range tombstone key %q is not addressable", k)
+		}
 		if !bytes.HasPrefix(k, LocalRangePrefix) {
 			return nil, errors.Errorf("local key %q malformed; should contain prefix %q",
 				k, LocalRangePrefix)
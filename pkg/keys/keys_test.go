@@ -113,6 +113,9 @@ func TestKeyAddressError(t *testing.T) {
 			RangeLastVerificationTimestampKeyDeprecated(0),
 			RangeDescriptorKey(roachpb.RKey(RangeLastVerificationTimestampKeyDeprecated(0))),
 		},
+		"range tombstone key .* is not addressable": {
+			RangeTombstoneKey(roachpb.Key("a")),
+		},
 		"local key .* malformed": {
 			makeKey(localPrefix, roachpb.Key("z")),
 		},
@@ -72,6 +72,8 @@ var (
 				ppFunc: localRangeIDKeyPrint, psFunc: localRangeIDKeyParse},
 			{name: "/Range", prefix: LocalRangePrefix, ppFunc: localRangeKeyPrint,
 				psFunc: parseUnsupported},
+			{name: "/RangeTombstone", prefix: LocalRangeTombstonePrefix,
+				ppFunc: decodeKeyPrint, psFunc: parseUnsupported},
 		}},
 		{name: "/Meta1", start: Meta1Prefix, end: Meta1KeyMax, entries: []dictEntry{
 			{name: "", prefix: Meta1Prefix, ppFunc: print,
@@ -519,6 +521,7 @@ func prettyPrintInternal(key roachpb.Key, quoteRawKeys bool) string {
 //			/RangeDescriptor/[key]                       "\x01k"+[key]+"rdsc"
 //			/Transaction/addrKey:[key]/id:[id]	         "\x01k"+[key]+"txn-"+[txn-id]
 //			/QueueLastProcessed/addrKey:[key]/id:[queue] "\x01k"+[key]+"qlpt"+[queue]
+//		/RangeTombstone/[key]                          "\x01t"+[key]
 // /Local/Max                                        "\x02"
 //
 // /Meta1/[key]                                      "\x02"+[key]
@@ -71,6 +71,8 @@ func TestPrettyPrint(t *testing.T) {
 		{TransactionKey(roachpb.Key("111"), txnID), fmt.Sprintf(`/Local/Range/"111"/Transaction/addrKey:/id:%q`, txnID)},
 		{QueueLastProcessedKey(roachpb.RKey("111"), "foo"), `/Local/Range/"111"/QueueLastProcessed/addrKey:/id:"foo"`},
 
+		{RangeTombstoneKey(roachpb.Key("111")), `/Local/RangeTombstone/"111"`},
+
 		{LocalMax, `/Meta1/""`}, // LocalMax == Meta1Prefix
 
 		// system
@@ -0,0 +1,257 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kv
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+)
+
+// RangeFeed divides a RangeFeed request on range boundaries and establishes
+// a range feed to each of the ranges, sending the events of all of them to
+// the provided channel until the context is canceled or an error occurs.
+// The range feed of a range which splits, moves or changes lease holders is
+// restarted from the last timestamp it resolved, so values may be sent more
+// than once.
+func (ds *DistSender) RangeFeed(
+	ctx context.Context, args *roachpb.RangeFeedRequest, eventCh chan<- *roachpb.RangeFeedEvent,
+) *roachpb.Error {
+	ctx = ds.AnnotateCtx(ctx)
+	startKey, err := keys.Addr(args.Span.Key)
+	if err != nil {
+		return roachpb.NewError(err)
+	}
+	endKey, err := keys.Addr(args.Span.EndKey)
+	if err != nil {
+		return roachpb.NewError(err)
+	}
+	rs := roachpb.RSpan{Key: startKey, EndKey: endKey}
+
+	g, gCtx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return ds.divideAndRunRangeFeed(gCtx, g, rs, args.Timestamp, eventCh)
+	})
+	if err := g.Wait(); err != nil {
+		return roachpb.NewError(err)
+	}
+	return nil
+}
+
+// divideAndRunRangeFeed starts a partial range feed for each of the ranges
+// overlapping the span in the group.
+func (ds *DistSender) divideAndRunRangeFeed(
+	ctx context.Context,
+	g *errgroup.Group,
+	rs roachpb.RSpan,
+	ts hlc.Timestamp,
+	eventCh chan<- *roachpb.RangeFeedEvent,
+) error {
+	ri := NewRangeIterator(ds)
+	for ri.Seek(ctx, rs.Key, Ascending); ri.Valid(); ri.Next(ctx) {
+		desc, evictToken := ri.Desc(), ri.Token()
+		g.Go(func() error {
+			return ds.partialRangeFeed(ctx, g, rs, ts, desc, evictToken, eventCh)
+		})
+		if !ri.NeedAnother(rs) {
+			return nil
+		}
+	}
+	return ri.Error().GoError()
+}
+
+// partialRangeFeed runs a range feed over the part of the span covered by
+// desc. The range feed is restarted in a retry loop to handle failures. If
+// the range seems to have split, divideAndRunRangeFeed is invoked again to
+// run a range feed over each of the new ranges.
+func (ds *DistSender) partialRangeFeed(
+	ctx context.Context,
+	g *errgroup.Group,
+	rs roachpb.RSpan,
+	ts hlc.Timestamp,
+	desc *roachpb.RangeDescriptor,
+	evictToken *EvictionToken,
+	eventCh chan<- *roachpb.RangeFeedEvent,
+) error {
+	// Truncate the span to the range descriptor.
+	rs, err := rs.Intersect(desc)
+	if err != nil {
+		return err
+	}
+	span := roachpb.Span{Key: rs.Key.AsRawKey(), EndKey: rs.EndKey.AsRawKey()}
+
+	var pErr *roachpb.Error
+	for r := retry.StartWithCtx(ctx, ds.rpcRetryOptions); r.Next(); {
+		// If we've cleared the descriptor on a failure, re-lookup.
+		if desc == nil {
+			desc, evictToken, err = ds.getDescriptor(ctx, rs.Key, nil, false)
+			if err != nil {
+				log.ErrEventf(ctx, "range descriptor re-lookup failed: %s", err)
+				continue
+			}
+		}
+
+		var resolved hlc.Timestamp
+		resolved, pErr = ds.singleRangeFeed(ctx, span, ts, desc, eventCh)
+		if ts.Less(resolved) {
+			// The range feed made progress, so restart it from where it left
+			// off without backing off.
+			ts = resolved
+			r.Reset()
+		}
+		log.ErrEventf(ctx, "range feed error %s: %s", span, pErr)
+
+		switch tErr := pErr.GetDetail().(type) {
+		case *roachpb.SendError, *roachpb.RangeNotFoundError:
+			// We've tried all the replicas without success. Either they're all
+			// down, or we're using an out-of-date range descriptor. Invalidate
+			// the cache and try again with the new metadata.
+			if err := evictToken.Evict(ctx); err != nil {
+				return err
+			}
+			desc = nil
+			continue
+		case *roachpb.RangeKeyMismatchError:
+			// The range likely split or merged. Evict the descriptor and run a
+			// range feed over each of the ranges which now cover the span.
+			if err := evictToken.Evict(ctx); err != nil {
+				return err
+			}
+			log.VEventf(ctx, 1, "likely split; restarting range feed over span: %s", tErr)
+			return ds.divideAndRunRangeFeed(ctx, g, rs, ts, eventCh)
+		}
+		break
+	}
+
+	// Propagate error if either the retry closer or context done channels
+	// were closed.
+	if pErr == nil {
+		if pErr = ds.deduceRetryEarlyExitError(ctx); pErr == nil {
+			log.Fatal(ctx, "exited retry loop without an error")
+		}
+	}
+	return pErr.GoError()
+}
+
+// singleRangeFeed runs a range feed over the span on one of the replicas of
+// desc, starting with the lease holder, and sends its events to the channel
+// until it fails. It returns the last timestamp resolved by the range feed
+// along with the error.
+func (ds *DistSender) singleRangeFeed(
+	ctx context.Context,
+	span roachpb.Span,
+	ts hlc.Timestamp,
+	desc *roachpb.RangeDescriptor,
+	eventCh chan<- *roachpb.RangeFeedEvent,
+) (hlc.Timestamp, *roachpb.Error) {
+	args := roachpb.RangeFeedRequest{
+		Header: roachpb.Header{Timestamp: ts, RangeID: desc.RangeID},
+		Span:   span,
+	}
+
+	replicas := NewReplicaSlice(ds.gossip, desc)
+	replicas.OptimizeReplicaOrder(ds.getNodeDescriptor())
+	if leaseHolder, ok := ds.leaseHolderCache.Lookup(ctx, desc.RangeID); ok {
+		if i := replicas.FindReplica(leaseHolder.StoreID); i >= 0 {
+			replicas.MoveToFront(i)
+		}
+	}
+
+	for len(replicas) > 0 {
+		replica := replicas[0]
+		replicas = replicas[1:]
+		args.Replica = replica.ReplicaDescriptor
+
+		pErr, err := ds.replicaRangeFeed(ctx, &args, replica, eventCh)
+		if err != nil {
+			if ctx.Err() != nil {
+				return args.Timestamp, roachpb.NewError(ctx.Err())
+			}
+			log.ErrEventf(ctx, "range feed on %s failed: %s", replica, err)
+			continue
+		}
+		switch tErr := pErr.GetDetail().(type) {
+		case *roachpb.StoreNotFoundError, *roachpb.NodeUnavailableError:
+			// These errors are likely to be unique to the replica that reported
+			// them, so try the next one.
+			continue
+		case *roachpb.NotLeaseHolderError:
+			ds.metrics.NotLeaseHolderErrCount.Inc(1)
+			if lh := tErr.LeaseHolder; lh != nil {
+				// If the replica we contacted knows the new lease holder, update
+				// the cache and try it next.
+				ds.updateLeaseHolderCache(ctx, desc.RangeID, *lh)
+				if i := replicas.FindReplica(lh.StoreID); i >= 0 {
+					replicas.MoveToFront(i)
+				} else if lh.StoreID != replica.StoreID {
+					// If the implicated lease holder is not a known replica,
+					// return a RangeNotFoundError to signal eviction of the
+					// cached RangeDescriptor.
+					return args.Timestamp, roachpb.NewError(roachpb.NewRangeNotFoundError(desc.RangeID))
+				}
+			}
+			continue
+		}
+		return args.Timestamp, pErr
+	}
+	return args.Timestamp, roachpb.NewError(roachpb.NewSendError(
+		fmt.Sprintf("range feed failed on all replicas of r%d", desc.RangeID)))
+}
+
+// replicaRangeFeed runs a range feed on the replica and sends its events to
+// the channel, forwarding the timestamp of the request as they are resolved.
+// It returns the error sent by the replica, or the error of the RPC if the
+// range feed failed to connect or was interrupted.
+func (ds *DistSender) replicaRangeFeed(
+	ctx context.Context,
+	args *roachpb.RangeFeedRequest,
+	replica ReplicaInfo,
+	eventCh chan<- *roachpb.RangeFeedEvent,
+) (*roachpb.Error, error) {
+	conn, err := ds.rpcContext.GRPCDial(replica.NodeDesc.Address.String())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := roachpb.NewInternalClient(conn).RangeFeed(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		switch t := event.GetValue().(type) {
+		case *roachpb.RangeFeedCheckpoint:
+			args.Timestamp.Forward(t.ResolvedTS)
+		case *roachpb.RangeFeedError:
+			return &t.Error, nil
+		}
+		select {
+		case eventCh <- event:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
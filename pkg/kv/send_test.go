@@ -60,6 +60,10 @@ func (n Node) Batch(
 	return &roachpb.BatchResponse{}, nil
 }
 
+func (n Node) RangeFeed(_ *roachpb.RangeFeedRequest, _ roachpb.Internal_RangeFeedServer) error {
+	panic("unimplemented")
+}
+
 func TestInvalidAddrLength(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
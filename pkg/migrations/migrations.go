@@ -65,6 +65,24 @@ var backwardCompatibleMigrations = []migrationDescriptor{
 		name:   "enable diagnostics reporting",
 		workFn: optInToDiagnosticsStatReporting,
 	},
+	{
+		name:           "create system.schedules table",
+		workFn:         createSchedulesTable,
+		newDescriptors: 1,
+		newRanges:      1,
+	},
+	{
+		name:           "create system.roles and system.role_members tables",
+		workFn:         createRoleTables,
+		newDescriptors: 2,
+		newRanges:      2,
+	},
+	{
+		name:           "create system.user_options table",
+		workFn:         createUserOptionsTable,
+		newDescriptors: 1,
+		newRanges:      1,
+	},
 }
 
 // migrationDescriptor describes a single migration hook that's used to modify
@@ -349,6 +367,21 @@ func createSettingsTable(ctx context.Context, r runner) error {
 	return createSystemTable(ctx, r, sqlbase.SettingsTable)
 }
 
+func createSchedulesTable(ctx context.Context, r runner) error {
+	return createSystemTable(ctx, r, sqlbase.SchedulesTable)
+}
+
+func createRoleTables(ctx context.Context, r runner) error {
+	if err := createSystemTable(ctx, r, sqlbase.RolesTable); err != nil {
+		return err
+	}
+	return createSystemTable(ctx, r, sqlbase.RoleMembersTable)
+}
+
+func createUserOptionsTable(ctx context.Context, r runner) error {
+	return createSystemTable(ctx, r, sqlbase.UserOptionsTable)
+}
+
 func createSystemTable(ctx context.Context, r runner, desc sqlbase.TableDescriptor) error {
 	// We install the table at the KV layer so that we can choose a known ID in
 	// the reserved ID space. (The SQL layer doesn't allow this.)
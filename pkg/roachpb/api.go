@@ -878,6 +878,11 @@ func (drr *DeleteRangeRequest) flags() int {
 	if drr.Inline {
 		return isWrite | isRange | isAlone
 	}
+	// Like inline deletions, deletions using a range tombstone are
+	// non-transactional, but they must still be pushed above prior reads.
+	if drr.UseRangeTombstone {
+		return isWrite | isRange | isAlone | consultsTSCache
+	}
 	// DeleteRange updates the timestamp cache as it doesn't leave
 	// intents or tombstones for keys which don't yet exist. By updating
 	// the write timestamp cache, it forces subsequent writes to get a
@@ -122,27 +122,67 @@ func UserAuthCertHook(insecureMode bool, tlsState *tls.ConnectionState) (UserAut
 // mode, password, and its potentially matching hash.
 func UserAuthPasswordHook(insecureMode bool, password string, hashedPassword []byte) UserAuthHook {
 	return func(requestedUser string, clientConnection bool) error {
-		if len(requestedUser) == 0 {
-			return errors.New("user is missing")
+		if done, err := checkPasswordAuthAllowed(insecureMode, requestedUser, clientConnection); done {
+			return err
 		}
 
-		if !clientConnection {
-			return errors.New("password authentication is only available for client connections")
+		// If the requested user has an empty password, disallow authentication.
+		if len(password) == 0 || compareHashAndPassword(hashedPassword, password) != nil {
+			return errors.New("invalid password")
 		}
 
-		if insecureMode {
-			return nil
-		}
+		return nil
+	}
+}
 
-		if requestedUser == RootUser {
-			return errors.Errorf("user %s must use certificate authentication instead of password authentication", RootUser)
+// UserAuthScramHook builds an authentication hook based on the security
+// mode and the user's hashed password. The hook runs the SCRAM exchange
+// with the client through the exchange function; the exchange fails if the
+// hashed password is not a SCRAM verifier.
+func UserAuthScramHook(
+	insecureMode bool, hashedPassword []byte, exchange func(*ScramServer) error,
+) UserAuthHook {
+	return func(requestedUser string, clientConnection bool) error {
+		if done, err := checkPasswordAuthAllowed(insecureMode, requestedUser, clientConnection); done {
+			return err
 		}
 
-		// If the requested user has an empty password, disallow authentication.
-		if len(password) == 0 || compareHashAndPassword(hashedPassword, password) != nil {
-			return errors.New("invalid password")
+		// Users without a password, or whose password was not stored as a
+		// SCRAM verifier, cannot use SCRAM authentication. The exchange is
+		// still run, against a mock verifier, so that its failure looks
+		// like a wrong password and does not reveal which users exist or
+		// have a password.
+		if !IsScramVerifier(hashedPassword) {
+			return exchange(newMockScramServer(requestedUser))
+		}
+		srv, err := NewScramServer(hashedPassword)
+		if err != nil {
+			return err
 		}
+		return exchange(srv)
+	}
+}
 
-		return nil
+// checkPasswordAuthAllowed performs the checks common to the password
+// authentication hooks. If done is true, the authentication is finished
+// and err is its outcome.
+func checkPasswordAuthAllowed(
+	insecureMode bool, requestedUser string, clientConnection bool,
+) (done bool, err error) {
+	if len(requestedUser) == 0 {
+		return true, errors.New("user is missing")
+	}
+
+	if !clientConnection {
+		return true, errors.New("password authentication is only available for client connections")
+	}
+
+	if insecureMode {
+		return true, nil
+	}
+
+	if requestedUser == RootUser {
+		return true, errors.Errorf("user %s must use certificate authentication instead of password authentication", RootUser)
 	}
+	return false, nil
 }
@@ -88,6 +88,8 @@ const (
 	// Filename extenstions.
 	certExtension = `.crt`
 	keyExtension  = `.key`
+	// Filename of the certificate revocation list.
+	crlFilename = `ca.crl`
 	// Certificate directory permissions.
 	defaultCertsDirPerm = 0700
 )
@@ -155,6 +157,7 @@ type CertificateLoader struct {
 	certsDir             string
 	skipPermissionChecks bool
 	certificates         []*CertInfo
+	crl                  []byte
 }
 
 // Certificates returns the loaded certificates.
@@ -162,6 +165,12 @@ func (cl *CertificateLoader) Certificates() []*CertInfo {
 	return cl.certificates
 }
 
+// CRL returns the raw contents of the certificate revocation list, or nil if
+// the certs directory does not contain one.
+func (cl *CertificateLoader) CRL() []byte {
+	return cl.crl
+}
+
 // NewCertificateLoader creates a new instance of the certificate loader.
 func NewCertificateLoader(certsDir string) *CertificateLoader {
 	return &CertificateLoader{
@@ -200,7 +209,8 @@ func (cl *CertificateLoader) TestDisablePermissionChecks() {
 
 // Load examines all .crt files in the certs directory, determines their
 // usage, and looks for their keys.
-// It populates the certificates field.
+// It populates the certificates field, and the crl field if the directory
+// contains a certificate revocation list.
 func (cl *CertificateLoader) Load() error {
 	fileInfos, err := assetLoaderImpl.ReadDir(cl.certsDir)
 	if err != nil {
@@ -231,6 +241,17 @@ func (cl *CertificateLoader) Load() error {
 			continue
 		}
 
+		if filename == crlFilename {
+			// A revocation list that cannot be read must not be ignored,
+			// or revoked certificates would be accepted.
+			crl, err := assetLoaderImpl.ReadFile(fullPath)
+			if err != nil {
+				return errors.Wrapf(err, "could not read certificate revocation list %s", fullPath)
+			}
+			cl.crl = crl
+			continue
+		}
+
 		if !isCertificateFile(filename) {
 			if log.V(3) {
 				log.Infof(context.Background(), "skipping non-certificate file %s", filename)
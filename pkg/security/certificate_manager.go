@@ -22,11 +22,13 @@ import (
 	"os/signal"
 	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"golang.org/x/net/context"
 
 	"github.com/pkg/errors"
@@ -39,6 +41,12 @@ var (
 	metaNodeExpiration = metric.Metadata{
 		Name: "security.certificate.expiration.node",
 		Help: "Expiration timestamp for the node certificate. 0 means no certificate or error."}
+	metaRevokedRejections = metric.Metadata{
+		Name: "security.certificate.revoked_rejections",
+		Help: "Number of TLS connections rejected because a peer certificate is revoked"}
+	metaOCSPErrors = metric.Metadata{
+		Name: "security.certificate.ocsp_errors",
+		Help: "Number of certificate revocation checks that failed to get an OCSP response"}
 )
 
 // CertificateManager lives for the duration of the process and manages certificates and keys.
@@ -54,6 +62,9 @@ type CertificateManager struct {
 	// The metrics struct is initialized at init time and metrics do their
 	// own locking.
 	certMetrics CertificateMetrics
+	// ocsp checks certificates against the OCSP responder configured by the
+	// server.ocsp.url cluster setting. It does its own locking.
+	ocsp *ocspChecker
 
 	// mu protects all remaining fields.
 	mu syncutil.RWMutex
@@ -65,6 +76,9 @@ type CertificateManager struct {
 	caCert      *CertInfo
 	nodeCert    *CertInfo
 	clientCerts map[string]*CertInfo
+	// crl is the certificate revocation list of the certs directory. May be
+	// nil.
+	crl *revocationList
 
 	// TLS configs. Initialized lazily. Wiped on every successful Load().
 	// Server-side config.
@@ -78,16 +92,23 @@ type CertificateManager struct {
 // These are initialized when the certificate manager is created and updated
 // on reload.
 type CertificateMetrics struct {
-	CAExpiration   *metric.Gauge
-	NodeExpiration *metric.Gauge
+	CAExpiration      *metric.Gauge
+	NodeExpiration    *metric.Gauge
+	RevokedRejections *metric.Counter
+	OCSPErrors        *metric.Counter
 }
 
 func makeCertificateManager(certsDir string) *CertificateManager {
-	cm := &CertificateManager{certsDir: os.ExpandEnv(certsDir)}
+	cm := &CertificateManager{
+		certsDir: os.ExpandEnv(certsDir),
+		ocsp:     newOCSPChecker(),
+	}
 	// Initialize metrics:
 	cm.certMetrics = CertificateMetrics{
-		CAExpiration:   metric.NewGauge(metaCAExpiration),
-		NodeExpiration: metric.NewGauge(metaNodeExpiration),
+		CAExpiration:      metric.NewGauge(metaCAExpiration),
+		NodeExpiration:    metric.NewGauge(metaNodeExpiration),
+		RevokedRejections: metric.NewCounter(metaRevokedRejections),
+		OCSPErrors:        metric.NewCounter(metaOCSPErrors),
 	}
 	return cm
 }
@@ -218,6 +239,19 @@ func (cm *CertificateManager) LoadCertificates() error {
 		}
 	}
 
+	var crl *revocationList
+	if crlBytes := cl.CRL(); crlBytes != nil {
+		var err error
+		crlPath := filepath.Join(cm.certsDir, crlFilename)
+		if crl, err = parseRevocationList(crlBytes, caCert); err != nil {
+			return errors.Wrapf(err, "problem with certificate revocation list %s", crlPath)
+		}
+		if !crl.nextUpdate.IsZero() && timeutil.Now().After(crl.nextUpdate) {
+			log.Warningf(context.Background(), "certificate revocation list %s was due for an update on %s",
+				crlPath, crl.nextUpdate.Format(time.RFC3339))
+		}
+	}
+
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 	if cm.initialized {
@@ -234,6 +268,7 @@ func (cm *CertificateManager) LoadCertificates() error {
 	cm.caCert = caCert
 	cm.nodeCert = nodeCert
 	cm.clientCerts = clientCerts
+	cm.crl = crl
 	cm.initialized = true
 
 	cm.serverConfig = nil
@@ -309,6 +344,7 @@ func (cm *CertificateManager) GetEmbeddedServerTLSConfig(
 	if err != nil {
 		return nil, err
 	}
+	cfg.VerifyPeerCertificate = cm.verifyPeerCertificate
 
 	cm.serverConfig = cfg
 	return cfg, nil
@@ -360,6 +396,7 @@ func (cm *CertificateManager) GetClientTLSConfig(user string) (*tls.Config, erro
 		if err != nil {
 			return nil, err
 		}
+		cfg.VerifyPeerCertificate = cm.verifyPeerCertificate
 
 		return cfg, nil
 	}
@@ -382,6 +419,7 @@ func (cm *CertificateManager) GetClientTLSConfig(user string) (*tls.Config, erro
 	if err != nil {
 		return nil, err
 	}
+	cfg.VerifyPeerCertificate = cm.verifyPeerCertificate
 
 	// Cache the config.
 	cm.clientConfig = cfg
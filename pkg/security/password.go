@@ -26,6 +26,8 @@ import (
 
 	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
 )
 
 // BCrypt cost should increase along with computation power.
@@ -33,16 +35,40 @@ import (
 // For now, we use the library's default cost.
 const bcryptCost = bcrypt.DefaultCost
 
+// StoreScramVerifiers controls whether passwords are stored as SCRAM
+// verifiers, which previous versions cannot check. It must only be set once
+// every node of the cluster runs a version supporting them.
+var StoreScramVerifiers = settings.RegisterBoolSetting(
+	"server.user_login.store_scram_verifiers",
+	"if set, passwords are stored as SCRAM-SHA-256 verifiers; set it once every node runs a version supporting SCRAM authentication",
+	false,
+)
+
 // ErrEmptyPassword indicates that an empty password was attempted to be set.
 var ErrEmptyPassword = errors.New("empty passwords are not permitted")
 
+// compareHashAndPassword checks a password against its hash, which is
+// either a SCRAM verifier or a legacy bcrypt hash.
 func compareHashAndPassword(hashedPassword []byte, password string) error {
+	if IsScramVerifier(hashedPassword) {
+		return compareScramVerifierAndPassword(hashedPassword, password)
+	}
 	h := sha256.New()
 	return bcrypt.CompareHashAndPassword(hashedPassword, h.Sum([]byte(password)))
 }
 
-// HashPassword takes a raw password and returns a bcrypt hashed password.
+// HashPassword takes a raw password and returns a SCRAM-SHA-256 verifier
+// if StoreScramVerifiers is set, and a bcrypt hash otherwise. Bcrypt hashes
+// are accepted by password authentication but cannot be used with SCRAM.
 func HashPassword(password string) ([]byte, error) {
+	if StoreScramVerifiers.Get() {
+		return hashPasswordScram(password)
+	}
+	return hashPasswordBcrypt(password)
+}
+
+// hashPasswordBcrypt returns a legacy bcrypt hashed password.
+func hashPasswordBcrypt(password string) ([]byte, error) {
 	h := sha256.New()
 	return bcrypt.GenerateFromPassword(h.Sum([]byte(password)), bcryptCost)
 }
@@ -87,7 +113,7 @@ func PromptForPasswordTwice() (string, error) {
 	return string(one), nil
 }
 
-// PromptForPasswordAndHash prompts for a password twice and returns the bcrypt
+// PromptForPasswordAndHash prompts for a password twice and returns its
 // hash.
 func PromptForPasswordAndHash() ([]byte, error) {
 	password, err := PromptForPasswordTwice()
@@ -0,0 +1,259 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package security
+
+import (
+	"bytes"
+	"crypto/x509"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+var ocspURL = settings.RegisterStringSetting(
+	"server.ocsp.url",
+	"URL of the OCSP responder used to check the revocation of node and client certificates (empty to disable)",
+	"",
+)
+
+var ocspCacheTTL = settings.RegisterNonNegativeDurationSetting(
+	"server.ocsp.cache_ttl",
+	"the maximum amount of time an OCSP response is cached",
+	time.Hour,
+)
+
+var ocspStrict = settings.RegisterBoolSetting(
+	"server.ocsp.strict",
+	"if set, reject certificates whose revocation status cannot be obtained from the OCSP responder",
+	false,
+)
+
+const (
+	// ocspTimeout bounds the duration of a request to the OCSP responder,
+	// which is made during the TLS handshake.
+	ocspTimeout = 5 * time.Second
+	// maxOCSPResponseSize bounds the size of the responses read from the
+	// OCSP responder.
+	maxOCSPResponseSize = 1 << 20
+	// maxOCSPCacheSize is the number of cached OCSP responses above which
+	// the expired responses are evicted.
+	maxOCSPCacheSize = 1000
+)
+
+// revokedCertError is returned by the revocation checks for revoked certificates.
+type revokedCertError struct {
+	cert *x509.Certificate
+}
+
+func (e *revokedCertError) Error() string {
+	return "certificate " + e.cert.Subject.CommonName +
+		" (serial " + e.cert.SerialNumber.String() + ") has been revoked"
+}
+
+// revocationList is a parsed certificate revocation list.
+type revocationList struct {
+	// issuer is the raw subject of the CA certificate that signed the list.
+	issuer []byte
+	// serials is the set of the serial numbers of the revoked certificates.
+	serials map[string]struct{}
+	// nextUpdate is the time by which a newer list should be issued.
+	nextUpdate time.Time
+}
+
+// parseRevocationList parses a PEM or DER encoded certificate revocation
+// list. The list must be signed by one of the certificates of the CA file.
+func parseRevocationList(crlBytes []byte, ca *CertInfo) (*revocationList, error) {
+	if err := checkCertIsValid(ca); err != nil {
+		return nil, errors.Wrap(err, "problem with CA certificate")
+	}
+	crl, err := x509.ParseCRL(crlBytes)
+	if err != nil {
+		return nil, err
+	}
+	for _, caCert := range ca.ParsedCertificates {
+		if caCert.CheckCRLSignature(crl) != nil {
+			continue
+		}
+		rl := &revocationList{
+			issuer:     caCert.RawSubject,
+			serials:    make(map[string]struct{}, len(crl.TBSCertList.RevokedCertificates)),
+			nextUpdate: crl.TBSCertList.NextUpdate,
+		}
+		for _, revoked := range crl.TBSCertList.RevokedCertificates {
+			rl.serials[revoked.SerialNumber.String()] = struct{}{}
+		}
+		return rl, nil
+	}
+	return nil, errors.New("the list is not signed by the CA certificate")
+}
+
+// isRevoked returns true if the list revokes the given certificate.
+func (rl *revocationList) isRevoked(cert *x509.Certificate) bool {
+	if rl == nil || !bytes.Equal(cert.RawIssuer, rl.issuer) {
+		return false
+	}
+	_, ok := rl.serials[cert.SerialNumber.String()]
+	return ok
+}
+
+type ocspCacheEntry struct {
+	revoked    bool
+	expiration time.Time
+}
+
+// ocspChecker queries an OCSP responder for the revocation status of
+// certificates, and caches the responses.
+type ocspChecker struct {
+	httpClient http.Client
+
+	mu struct {
+		syncutil.Mutex
+		// cache maps the responder URL and the serial number of a
+		// certificate to its revocation status.
+		cache map[string]ocspCacheEntry
+	}
+}
+
+func newOCSPChecker() *ocspChecker {
+	c := &ocspChecker{httpClient: http.Client{Timeout: ocspTimeout}}
+	c.mu.cache = make(map[string]ocspCacheEntry)
+	return c
+}
+
+// isRevoked returns true if the OCSP responder at url reports the given
+// certificate as revoked. An error is returned if the revocation status
+// could not be obtained.
+func (c *ocspChecker) isRevoked(url string, cert, issuer *x509.Certificate) (bool, error) {
+	key := url + "|" + cert.SerialNumber.String()
+	now := timeutil.Now()
+	c.mu.Lock()
+	entry, ok := c.mu.cache[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiration) {
+		return entry.revoked, nil
+	}
+
+	req, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return false, err
+	}
+	httpResp, err := c.httpClient.Post(url, "application/ocsp-request", bytes.NewReader(req))
+	if err != nil {
+		return false, errors.Wrap(err, "OCSP request failed")
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		return false, errors.Errorf("OCSP responder returned %s", httpResp.Status)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(httpResp.Body, maxOCSPResponseSize))
+	if err != nil {
+		return false, errors.Wrap(err, "could not read OCSP response")
+	}
+	resp, err := ocsp.ParseResponse(body, issuer)
+	if err != nil {
+		return false, errors.Wrap(err, "invalid OCSP response")
+	}
+	if resp.SerialNumber == nil || resp.SerialNumber.Cmp(cert.SerialNumber) != 0 {
+		return false, errors.Errorf("OCSP response for serial %s does not match certificate", resp.SerialNumber)
+	}
+
+	switch resp.Status {
+	case ocsp.Good:
+		entry.revoked = false
+	case ocsp.Revoked:
+		entry.revoked = true
+	default:
+		return false, errors.Errorf("OCSP responder does not know certificate %s", cert.Subject.CommonName)
+	}
+
+	// Responses are cached until the responder promises newer information,
+	// but no longer than the configured TTL.
+	entry.expiration = now.Add(ocspCacheTTL.Get())
+	if !resp.NextUpdate.IsZero() && resp.NextUpdate.Before(entry.expiration) {
+		entry.expiration = resp.NextUpdate
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.mu.cache) >= maxOCSPCacheSize {
+		for k, e := range c.mu.cache {
+			if !now.Before(e.expiration) {
+				delete(c.mu.cache, k)
+			}
+		}
+	}
+	c.mu.cache[key] = entry
+	return entry.revoked, nil
+}
+
+// checkRevocation returns an error if a certificate of the verified chain
+// is revoked by the revocation list, or if the leaf certificate is revoked
+// according to the OCSP responder, when one is configured. The last
+// certificate of the chain is the self-signed CA certificate, which is not
+// checked.
+func (cm *CertificateManager) checkRevocation(crl *revocationList, chain []*x509.Certificate) error {
+	for _, cert := range chain[:len(chain)-1] {
+		if crl.isRevoked(cert) {
+			return &revokedCertError{cert: cert}
+		}
+	}
+
+	url := ocspURL.Get()
+	if url == "" || len(chain) < 2 {
+		return nil
+	}
+	revoked, err := cm.ocsp.isRevoked(url, chain[0], chain[1])
+	if err != nil {
+		cm.certMetrics.OCSPErrors.Inc(1)
+		if ocspStrict.Get() {
+			return errors.Wrapf(err, "could not check the revocation of certificate %s",
+				chain[0].Subject.CommonName)
+		}
+		return nil
+	}
+	if revoked {
+		return &revokedCertError{cert: chain[0]}
+	}
+	return nil
+}
+
+// verifyPeerCertificate is set as the VerifyPeerCertificate callback of the
+// TLS configs built by the certificate manager, for both the client and the
+// server side of connections. It is called after the peer certificates have
+// been verified against the CA, and rejects revoked certificates.
+func (cm *CertificateManager) verifyPeerCertificate(
+	_ [][]byte, verifiedChains [][]*x509.Certificate,
+) error {
+	cm.mu.RLock()
+	crl := cm.crl
+	cm.mu.RUnlock()
+
+	for _, chain := range verifiedChains {
+		if err := cm.checkRevocation(crl, chain); err != nil {
+			if _, ok := err.(*revokedCertError); ok {
+				cm.certMetrics.RevokedRejections.Inc(1)
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package security
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// testHandshake opens a TLS connection between a server and a client of the
+// given user, both using the certificates of the certificate manager.
+func testHandshake(cm *CertificateManager, user string) error {
+	serverConfig, err := cm.GetServerTLSConfig()
+	if err != nil {
+		return err
+	}
+	clientConfig, err := cm.GetClientTLSConfig(user)
+	if err != nil {
+		return err
+	}
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// The write fails if the handshake fails.
+		_, _ = conn.Write([]byte{0})
+	}()
+
+	conn, err := tls.Dial("tcp", ln.Addr().String(), clientConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	// With some TLS versions, the client completes its handshake before the
+	// server has verified the client certificate.
+	_, err = conn.Read(make([]byte, 1))
+	return err
+}
+
+func TestCertificateRevocation(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	// Do not mock cert access for this test.
+	savedAssetLoader := assetLoaderImpl
+	ResetAssetLoader()
+	defer SetAssetLoader(savedAssetLoader)
+
+	certsDir, err := ioutil.TempDir("", "revocation_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(certsDir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	caKeyPath := filepath.Join(certsDir, "ca.key")
+	if err := CreateCAPair(certsDir, caKeyPath, 512, time.Hour*96, true, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateNodePair(
+		certsDir, caKeyPath, 512, time.Hour*48, true, []string{"127.0.0.1"},
+	); err != nil {
+		t.Fatal(err)
+	}
+	for _, user := range []string{RootUser, "testuser"} {
+		if err := CreateClientPair(certsDir, caKeyPath, 512, time.Hour*48, true, user); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cm, err := NewCertificateManager(certsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, caKey, err := loadCACertAndKey(cm.CACertPath(), caKeyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial := func(user string) string {
+		return cm.ClientCerts()[user].ParsedCertificates[0].SerialNumber.String()
+	}
+
+	for _, user := range []string{NodeUser, RootUser, "testuser"} {
+		if err := testHandshake(cm, user); err != nil {
+			t.Fatalf("%s: %v", user, err)
+		}
+	}
+
+	t.Run("CRL", func(t *testing.T) {
+		now := timeutil.Now()
+		crlBytes, err := caCert.CreateCRL(rand.Reader, caKey, []pkix.RevokedCertificate{{
+			SerialNumber:   cm.ClientCerts()["testuser"].ParsedCertificates[0].SerialNumber,
+			RevocationTime: now,
+		}}, now, now.Add(time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		crlPath := filepath.Join(certsDir, crlFilename)
+		if err := WritePEMToFile(
+			crlPath, certFileMode, true, &pem.Block{Type: "X509 CRL", Bytes: crlBytes},
+		); err != nil {
+			t.Fatal(err)
+		}
+		if err := cm.LoadCertificates(); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := testHandshake(cm, RootUser); err != nil {
+			t.Fatal(err)
+		}
+		if err := testHandshake(cm, "testuser"); !isError(err, "bad certificate") {
+			t.Fatalf("expected revoked certificate to be rejected, got %v", err)
+		}
+		if count := cm.Metrics().RevokedRejections.Count(); count != 1 {
+			t.Fatalf("expected 1 revoked rejection, got %d", count)
+		}
+
+		// An invalid revocation list is refused, and removing the list
+		// lifts the revocations.
+		if err := ioutil.WriteFile(crlPath, []byte("not a CRL"), certFileMode); err != nil {
+			t.Fatal(err)
+		}
+		if err := cm.LoadCertificates(); !isError(err, "problem with certificate revocation list") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := os.Remove(crlPath); err != nil {
+			t.Fatal(err)
+		}
+		if err := cm.LoadCertificates(); err != nil {
+			t.Fatal(err)
+		}
+		if err := testHandshake(cm, "testuser"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("OCSP", func(t *testing.T) {
+		var requests int32
+		responder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			req, err := ocsp.ParseRequest(body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			now := timeutil.Now()
+			template := ocsp.Response{
+				Status:       ocsp.Good,
+				SerialNumber: req.SerialNumber,
+				ThisUpdate:   now,
+				NextUpdate:   now.Add(time.Hour),
+			}
+			if req.SerialNumber.String() == serial(RootUser) {
+				template.Status = ocsp.Revoked
+				template.RevokedAt = now
+			}
+			resp, err := ocsp.CreateResponse(caCert, caCert, template, caKey.(crypto.Signer))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write(resp)
+		}))
+		defer responder.Close()
+		defer settings.TestingSetString(&ocspURL, responder.URL)()
+
+		rejections := cm.Metrics().RevokedRejections.Count()
+		if err := testHandshake(cm, RootUser); !isError(err, "bad certificate") {
+			t.Fatalf("expected revoked certificate to be rejected, got %v", err)
+		}
+		if count := cm.Metrics().RevokedRejections.Count(); count != rejections+1 {
+			t.Fatalf("expected %d revoked rejections, got %d", rejections+1, count)
+		}
+		if err := testHandshake(cm, "testuser"); err != nil {
+			t.Fatal(err)
+		}
+
+		// The responses are cached.
+		n := atomic.LoadInt32(&requests)
+		if err := testHandshake(cm, "testuser"); err != nil {
+			t.Fatal(err)
+		}
+		if m := atomic.LoadInt32(&requests); m != n {
+			t.Fatalf("expected cached OCSP responses, got %d more requests", m-n)
+		}
+
+		// When the responder cannot be reached, certificates are accepted
+		// unless server.ocsp.strict is set.
+		unreachable := httptest.NewServer(http.NotFoundHandler())
+		unreachable.Close()
+		defer settings.TestingSetString(&ocspURL, unreachable.URL)()
+		if err := testHandshake(cm, "testuser"); err != nil {
+			t.Fatal(err)
+		}
+		if count := cm.Metrics().OCSPErrors.Count(); count == 0 {
+			t.Fatal("expected OCSP errors to be counted")
+		}
+		defer settings.TestingSetBool(&ocspStrict, true)()
+		if err := testHandshake(cm, "testuser"); !isError(err, "could not check the revocation") {
+			t.Fatalf("expected certificate to be rejected, got %v", err)
+		}
+	})
+}
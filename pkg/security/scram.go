@@ -0,0 +1,305 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package security
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// ScramMechanism is the name of the SASL mechanism implemented by
+// ScramServer.
+const ScramMechanism = "SCRAM-SHA-256"
+
+const (
+	// scramIterations is the PBKDF2 iteration count used for new
+	// verifiers. This is the default used by PostgreSQL.
+	scramIterations = 4096
+	scramSaltLen    = 16
+	scramNonceLen   = 18
+)
+
+// scramVerifierPrefix identifies SCRAM verifiers among the hashed
+// passwords stored in system.users; the other ones are bcrypt hashes.
+const scramVerifierPrefix = ScramMechanism + "$"
+
+// scramVerifier holds what the server needs to know about a password to
+// run a SCRAM exchange. It is stored in the same format as PostgreSQL:
+//
+//   SCRAM-SHA-256$<iterations>:<salt>$<StoredKey>:<ServerKey>
+//
+// where the salt and the keys are base64-encoded.
+type scramVerifier struct {
+	iterations int
+	salt       []byte
+	storedKey  []byte
+	serverKey  []byte
+}
+
+func (v scramVerifier) encode() []byte {
+	enc := base64.StdEncoding.EncodeToString
+	return []byte(fmt.Sprintf("%s%d:%s$%s:%s", scramVerifierPrefix,
+		v.iterations, enc(v.salt), enc(v.storedKey), enc(v.serverKey)))
+}
+
+// IsScramVerifier returns whether the hashed password is a SCRAM-SHA-256
+// verifier, as opposed to a legacy bcrypt hash.
+func IsScramVerifier(hashedPassword []byte) bool {
+	return bytes.HasPrefix(hashedPassword, []byte(scramVerifierPrefix))
+}
+
+func parseScramVerifier(hashedPassword []byte) (scramVerifier, error) {
+	var v scramVerifier
+	errInvalid := errors.New("invalid SCRAM verifier")
+	if !IsScramVerifier(hashedPassword) {
+		return v, errInvalid
+	}
+	parts := strings.Split(string(hashedPassword[len(scramVerifierPrefix):]), "$")
+	if len(parts) != 2 {
+		return v, errInvalid
+	}
+	iterSalt := strings.Split(parts[0], ":")
+	keys := strings.Split(parts[1], ":")
+	if len(iterSalt) != 2 || len(keys) != 2 {
+		return v, errInvalid
+	}
+	var err error
+	if v.iterations, err = strconv.Atoi(iterSalt[0]); err != nil || v.iterations <= 0 {
+		return v, errInvalid
+	}
+	dec := base64.StdEncoding.DecodeString
+	if v.salt, err = dec(iterSalt[1]); err != nil {
+		return v, errInvalid
+	}
+	if v.storedKey, err = dec(keys[0]); err != nil {
+		return v, errInvalid
+	}
+	if v.serverKey, err = dec(keys[1]); err != nil {
+		return v, errInvalid
+	}
+	return v, nil
+}
+
+// makeScramVerifier derives a verifier from a password.
+//
+// Note that PostgreSQL clients normalize the password with SASLprep
+// before deriving the keys, which only makes a difference for passwords
+// containing non-ASCII characters. Passwords are used as is here.
+func makeScramVerifier(password string, salt []byte, iterations int) scramVerifier {
+	saltedPassword := pbkdf2.Key([]byte(password), salt, iterations, sha256.Size, sha256.New)
+	clientKey := scramHMAC(saltedPassword, "Client Key")
+	storedKey := sha256.Sum256(clientKey)
+	return scramVerifier{
+		iterations: iterations,
+		salt:       salt,
+		storedKey:  storedKey[:],
+		serverKey:  scramHMAC(saltedPassword, "Server Key"),
+	}
+}
+
+// hashPasswordScram returns a new SCRAM-SHA-256 verifier for the password,
+// using a random salt.
+func hashPasswordScram(password string) ([]byte, error) {
+	salt := make([]byte, scramSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return makeScramVerifier(password, salt, scramIterations).encode(), nil
+}
+
+// compareScramVerifierAndPassword checks a cleartext password against a
+// SCRAM verifier.
+func compareScramVerifierAndPassword(hashedPassword []byte, password string) error {
+	v, err := parseScramVerifier(hashedPassword)
+	if err != nil {
+		return err
+	}
+	expected := makeScramVerifier(password, v.salt, v.iterations)
+	if subtle.ConstantTimeCompare(expected.storedKey, v.storedKey) != 1 ||
+		subtle.ConstantTimeCompare(expected.serverKey, v.serverKey) != 1 {
+		return errors.New("password mismatch")
+	}
+	return nil
+}
+
+func scramHMAC(key []byte, msg string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(msg))
+	return mac.Sum(nil)
+}
+
+// scramMockSecret is a random secret from which the salts of the mock
+// verifiers are derived.
+var scramMockSecret = func() []byte {
+	b := make([]byte, sha256.Size)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return b
+}()
+
+// makeMockScramVerifier returns a verifier for a user who cannot use SCRAM
+// authentication, against which an exchange can be run but always fails.
+// Like PostgreSQL does, the salt is derived from the user name so that
+// repeated attempts see the same salt, and the exchange cannot be told apart
+// from one with a wrong password.
+func makeMockScramVerifier(user string) scramVerifier {
+	salt := scramHMAC(scramMockSecret, user)[:scramSaltLen]
+	keys := make([]byte, 2*sha256.Size)
+	if _, err := rand.Read(keys); err != nil {
+		panic(err)
+	}
+	return scramVerifier{
+		iterations: scramIterations,
+		salt:       salt,
+		storedKey:  keys[:sha256.Size],
+		serverKey:  keys[sha256.Size:],
+	}
+}
+
+// scramServerNonce generates the server part of the exchange nonce. It is
+// a variable so that tests can make it deterministic.
+var scramServerNonce = func() (string, error) {
+	b := make([]byte, scramNonceLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// ScramServer implements the server side of a SCRAM-SHA-256 exchange, as
+// specified in RFC 5802 and RFC 7677. Channel binding is not supported.
+//
+// The user name sent by the client is ignored, like PostgreSQL does: the
+// user is the one given in the connection startup message.
+type ScramServer struct {
+	verifier scramVerifier
+
+	// State of the exchange, set by ClientFirst.
+	gs2Header       string
+	clientFirstBare string
+	serverFirst     string
+	nonce           string
+}
+
+// NewScramServer prepares an exchange for a user with the given hashed
+// password, which must be a SCRAM verifier.
+func NewScramServer(hashedPassword []byte) (*ScramServer, error) {
+	v, err := parseScramVerifier(hashedPassword)
+	if err != nil {
+		return nil, err
+	}
+	return &ScramServer{verifier: v}, nil
+}
+
+// newMockScramServer prepares an exchange for a user who cannot use SCRAM
+// authentication. See makeMockScramVerifier.
+func newMockScramServer(user string) *ScramServer {
+	return &ScramServer{verifier: makeMockScramVerifier(user)}
+}
+
+// ClientFirst processes the client-first-message and returns the
+// server-first-message.
+func (s *ScramServer) ClientFirst(msg []byte) ([]byte, error) {
+	// client-first-message = gs2-header client-first-message-bare
+	// gs2-header = gs2-cbind-flag "," [ authzid ] ","
+	parts := strings.SplitN(string(msg), ",", 3)
+	if len(parts) != 3 {
+		return nil, errors.New("malformed SCRAM client-first-message")
+	}
+	switch {
+	case parts[0] == "n", parts[0] == "y":
+	case strings.HasPrefix(parts[0], "p="):
+		return nil, errors.New("SCRAM channel binding is not supported")
+	default:
+		return nil, errors.New("malformed SCRAM client-first-message")
+	}
+	if parts[1] != "" {
+		return nil, errors.New("SCRAM authorization identities are not supported")
+	}
+	s.gs2Header = parts[0] + "," + parts[1] + ","
+	s.clientFirstBare = parts[2]
+
+	// client-first-message-bare = [reserved-mext ","] username "," nonce ["," extensions]
+	attrs := strings.Split(s.clientFirstBare, ",")
+	if len(attrs) < 2 || !strings.HasPrefix(attrs[0], "n=") || !strings.HasPrefix(attrs[1], "r=") {
+		return nil, errors.New("malformed SCRAM client-first-message")
+	}
+	clientNonce := attrs[1][len("r="):]
+	if clientNonce == "" {
+		return nil, errors.New("malformed SCRAM client-first-message")
+	}
+	serverNonce, err := scramServerNonce()
+	if err != nil {
+		return nil, err
+	}
+	s.nonce = clientNonce + serverNonce
+	s.serverFirst = fmt.Sprintf("r=%s,s=%s,i=%d",
+		s.nonce, base64.StdEncoding.EncodeToString(s.verifier.salt), s.verifier.iterations)
+	return []byte(s.serverFirst), nil
+}
+
+// ClientFinal processes the client-final-message. If the client proved
+// that it knows the password, it returns the server-final-message.
+func (s *ScramServer) ClientFinal(msg []byte) ([]byte, error) {
+	if s.serverFirst == "" {
+		return nil, errors.New("SCRAM client-final-message received before client-first-message")
+	}
+	// client-final-message = channel-binding "," nonce ["," extensions] "," proof
+	str := string(msg)
+	idx := strings.LastIndex(str, ",p=")
+	if idx < 0 {
+		return nil, errors.New("malformed SCRAM client-final-message")
+	}
+	withoutProof := str[:idx]
+	proof, err := base64.StdEncoding.DecodeString(str[idx+len(",p="):])
+	if err != nil || len(proof) != sha256.Size {
+		return nil, errors.New("malformed SCRAM client-final-message")
+	}
+	attrs := strings.Split(withoutProof, ",")
+	if len(attrs) < 2 || !strings.HasPrefix(attrs[0], "c=") || !strings.HasPrefix(attrs[1], "r=") {
+		return nil, errors.New("malformed SCRAM client-final-message")
+	}
+	if attrs[0][len("c="):] != base64.StdEncoding.EncodeToString([]byte(s.gs2Header)) {
+		return nil, errors.New("SCRAM channel binding mismatch")
+	}
+	if attrs[1][len("r="):] != s.nonce {
+		return nil, errors.New("SCRAM nonce mismatch")
+	}
+
+	authMessage := s.clientFirstBare + "," + s.serverFirst + "," + withoutProof
+	clientSignature := scramHMAC(s.verifier.storedKey, authMessage)
+	clientKey := make([]byte, len(proof))
+	for i := range proof {
+		clientKey[i] = proof[i] ^ clientSignature[i]
+	}
+	storedKey := sha256.Sum256(clientKey)
+	if subtle.ConstantTimeCompare(storedKey[:], s.verifier.storedKey) != 1 {
+		return nil, errors.New("invalid password")
+	}
+
+	serverSignature := scramHMAC(s.verifier.serverKey, authMessage)
+	return []byte("v=" + base64.StdEncoding.EncodeToString(serverSignature)), nil
+}
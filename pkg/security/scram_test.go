@@ -0,0 +1,203 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package security
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+// isError is like testutils.IsError, which cannot be used here because
+// testutils depends on this package.
+func isError(err error, re string) bool {
+	if err == nil {
+		return re == ""
+	}
+	return re != "" && strings.Contains(err.Error(), re)
+}
+
+// TestScramServer runs the example exchange of RFC 7677, section 3.
+func TestScramServer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	defer func(f func() (string, error)) { scramServerNonce = f }(scramServerNonce)
+	scramServerNonce = func() (string, error) { return "%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0", nil }
+
+	salt, err := base64.StdEncoding.DecodeString("W22ZaJ0SNY7soEsUEjb6gQ==")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashed := makeScramVerifier("pencil", salt, 4096).encode()
+
+	const (
+		clientFirst = "n,,n=user,r=rOprNGfwEbeRWgbNEkqO"
+		serverFirst = "r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0," +
+			"s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096"
+		clientFinalWithoutProof = "c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0"
+		proof                   = "dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ="
+		serverFinal             = "v=6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4="
+	)
+
+	for _, tc := range []struct {
+		clientFinal string
+		expected    string
+		expectedErr string
+	}{
+		{clientFinalWithoutProof + ",p=" + proof, serverFinal, ""},
+		{clientFinalWithoutProof + ",p=" + base64.StdEncoding.EncodeToString(make([]byte, 32)),
+			"", "invalid password"},
+		{"c=biws,r=rOprNGfwEbeRWgbNEkqO,p=" + proof, "", "nonce mismatch"},
+		{"c=eSws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,p=" + proof,
+			"", "channel binding mismatch"},
+		{clientFinalWithoutProof, "", "malformed"},
+	} {
+		srv, err := NewScramServer(hashed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err := srv.ClientFirst([]byte(clientFirst))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(msg) != serverFirst {
+			t.Fatalf("expected server-first-message %q, got %q", serverFirst, msg)
+		}
+		msg, err = srv.ClientFinal([]byte(tc.clientFinal))
+		if !isError(err, tc.expectedErr) {
+			t.Errorf("%s: expected error %q, got %v", tc.clientFinal, tc.expectedErr, err)
+		} else if string(msg) != tc.expected {
+			t.Errorf("%s: expected %q, got %q", tc.clientFinal, tc.expected, msg)
+		}
+	}
+}
+
+func TestScramServerClientFirstError(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	hashed, err := hashPasswordScram("pencil")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		clientFirst string
+		expectedErr string
+	}{
+		{"n,,n=user", "malformed"},
+		{"n,,r=abc", "malformed"},
+		{"x,,n=user,r=abc", "malformed"},
+		{"p=tls-server-end-point,,n=user,r=abc", "channel binding is not supported"},
+		{"n,a=admin,n=user,r=abc", "authorization identities are not supported"},
+	} {
+		srv, err := NewScramServer(hashed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := srv.ClientFirst([]byte(tc.clientFirst)); !isError(err, tc.expectedErr) {
+			t.Errorf("%s: expected error %q, got %v", tc.clientFirst, tc.expectedErr, err)
+		}
+	}
+}
+
+func TestCompareHashAndPassword(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, hash := range []func(string) ([]byte, error){hashPasswordScram, hashPasswordBcrypt} {
+		hashed, err := hash("pencil")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := compareHashAndPassword(hashed, "pencil"); err != nil {
+			t.Errorf("%s: %v", hashed, err)
+		}
+		if err := compareHashAndPassword(hashed, "pen"); err == nil {
+			t.Errorf("%s: expected wrong password to be rejected", hashed)
+		}
+	}
+
+	if _, err := NewScramServer([]byte("SCRAM-SHA-256$4096:abc")); !isError(err, "invalid SCRAM verifier") {
+		t.Errorf("expected invalid verifier error, got %v", err)
+	}
+}
+
+// TestScramMockVerifier checks that the SCRAM exchange of a user without a
+// SCRAM verifier fails like one with a wrong password.
+func TestScramMockVerifier(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	scramHashed, err := hashPasswordScram("pencil")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bcryptHashed, err := hashPasswordBcrypt("pencil")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// authenticate runs an exchange with a wrong password and returns the
+	// salt sent by the server.
+	authenticate := func(user string, hashed []byte) string {
+		var salt string
+		hook := UserAuthScramHook(false /* insecureMode */, hashed, func(srv *ScramServer) error {
+			msg, err := srv.ClientFirst([]byte("n,,n=,r=abc"))
+			if err != nil {
+				return err
+			}
+			attrs := strings.Split(string(msg), ",")
+			salt = attrs[1]
+			proof := base64.StdEncoding.EncodeToString(make([]byte, 32))
+			_, err = srv.ClientFinal([]byte("c=biws," + attrs[0] + ",p=" + proof))
+			return err
+		})
+		if err := hook(user, true /* clientConnection */); !isError(err, "invalid password") {
+			t.Errorf("%s: expected invalid password error, got %v", hashed, err)
+		}
+		return salt
+	}
+
+	authenticate("carl", scramHashed)
+	for _, hashed := range [][]byte{bcryptHashed, nil} {
+		salt := authenticate("carl", hashed)
+		if again := authenticate("carl", hashed); again != salt {
+			t.Errorf("%s: expected the same salt for the same user, got %s and %s", hashed, salt, again)
+		}
+		if other := authenticate("dave", hashed); other == salt {
+			t.Errorf("%s: expected different salts for different users, got %s", hashed, salt)
+		}
+	}
+}
+
+func TestHashPassword(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, store := range []bool{false, true} {
+		func() {
+			defer settings.TestingSetBool(&StoreScramVerifiers, store)()
+			hashed, err := HashPassword("pencil")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if IsScramVerifier(hashed) != store {
+				t.Errorf("store SCRAM verifiers %t: unexpected hashed password %s", store, hashed)
+			}
+			if err := compareHashAndPassword(hashed, "pencil"); err != nil {
+				t.Errorf("%s: %v", hashed, err)
+			}
+		}()
+	}
+}
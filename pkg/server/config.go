@@ -459,14 +459,19 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 					spec.SizePercent, spec.Path, humanizeutil.IBytes(sizeInBytes), humanizeutil.IBytes(base.MinimumStoreSize))
 			}
 
-			details = append(details, fmt.Sprintf("store %d: RocksDB, max size %s, max open file limit %d",
-				i, humanizeutil.IBytes(sizeInBytes), openFileLimitPerStore))
-			eng, err := engine.NewRocksDB(
+			encryption := engine.EncryptionOptions{
+				KeyFile:     spec.EncryptionKey,
+				OldKeyFiles: spec.OldEncryptionKeys,
+			}
+			details = append(details, fmt.Sprintf("store %d: RocksDB, max size %s, max open file limit %d, encrypted %t",
+				i, humanizeutil.IBytes(sizeInBytes), openFileLimitPerStore, encryption.KeyFile != ""))
+			eng, err := engine.NewEncryptedRocksDB(
 				spec.Attributes,
 				spec.Path,
 				cache,
 				sizeInBytes,
 				openFileLimitPerStore,
+				encryption,
 			)
 			if err != nil {
 				return Engines{}, err
@@ -800,9 +800,8 @@ func (n *Node) recordJoinEvent() {
 	})
 }
 
-func (n *Node) batchInternal(
-	ctx context.Context, args *roachpb.BatchRequest,
-) (*roachpb.BatchResponse, error) {
+// checkNodeUser returns an error if the RPC wasn't issued by a node.
+func checkNodeUser(ctx context.Context) error {
 	// TODO(marc): grpc's authentication model (which gives credential access in
 	// the request handler) doesn't really fit with the current design of the
 	// security package (which assumes that TLS state is only given at connection
@@ -813,13 +812,22 @@ func (n *Node) batchInternal(
 		if tlsInfo, ok := peer.AuthInfo.(credentials.TLSInfo); ok {
 			certUser, err := security.GetCertificateUser(&tlsInfo.State)
 			if err != nil {
-				return nil, err
+				return err
 			}
 			if certUser != security.NodeUser {
-				return nil, errors.Errorf("user %s is not allowed", certUser)
+				return errors.Errorf("user %s is not allowed", certUser)
 			}
 		}
 	}
+	return nil
+}
+
+func (n *Node) batchInternal(
+	ctx context.Context, args *roachpb.BatchRequest,
+) (*roachpb.BatchResponse, error) {
+	if err := checkNodeUser(ctx); err != nil {
+		return nil, err
+	}
 
 	var br *roachpb.BatchResponse
 
@@ -883,6 +891,28 @@ func (n *Node) Batch(
 	return br, nil
 }
 
+// RangeFeed implements the roachpb.InternalServer interface.
+func (n *Node) RangeFeed(
+	args *roachpb.RangeFeedRequest, stream roachpb.Internal_RangeFeedServer,
+) error {
+	growStack()
+
+	ctx := stream.Context()
+	if err := checkNodeUser(ctx); err != nil {
+		return err
+	}
+	return n.stopper.RunTaskWithErr(ctx, "node.Node: range feed", func(ctx context.Context) error {
+		if pErr := n.stores.RangeFeed(args, stream); pErr != nil {
+			// Like for Batch, errors are returned as part of the stream so that
+			// their structure is preserved.
+			var event roachpb.RangeFeedEvent
+			event.SetValue(&roachpb.RangeFeedError{Error: *pErr})
+			return stream.Send(&event)
+		}
+		return nil
+	})
+}
+
 // setupSpanForIncomingRPC takes a context and returns a derived context with a
 // new span in it. Depending on the input context, that span might be a root
 // span or a child span. If it is a child span, it might be a child span of a
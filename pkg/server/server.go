@@ -28,6 +28,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -285,6 +286,16 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		ParentMemoryMonitor: &rootSQLMemoryMonitor,
 		Counter:             distSQLMetrics.CurBytesCount,
 		Hist:                distSQLMetrics.MaxBytesHist,
+
+		SQLExecutor: sql.InternalExecutor{LeaseManager: s.leaseMgr},
+	}
+	// Processors store temporary data (e.g. the KVs sorted by IMPORT) next to
+	// the first on-disk store. If all stores are in memory, so is that data.
+	for _, spec := range s.cfg.Stores.Specs {
+		if !spec.InMemory {
+			distSQLCfg.TempStorageDir = filepath.Join(spec.Path, "distsql-tmp")
+			break
+		}
 	}
 	if s.cfg.TestingKnobs.DistSQL != nil {
 		distSQLCfg.TestingKnobs = *s.cfg.TestingKnobs.DistSQL.(*distsqlrun.TestingKnobs)
@@ -374,6 +385,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		DistSQLSrv:              s.distSQLServer,
 		StatusServer:            s.status,
 		SessionRegistry:         s.sessionRegistry,
+		Stopper:                 s.stopper,
 		HistogramWindowInterval: s.cfg.HistogramWindowInterval(),
 		RangeDescriptorCache:    s.distSender.RangeDescriptorCache(),
 		LeaseHolderCache:        s.distSender.LeaseHolderCache(),
@@ -884,6 +896,10 @@ func (s *Server) Start(ctx context.Context) error {
 	// executes a SQL query, this must be done after the SQL layer is ready.
 	s.node.recordJoinEvent()
 
+	// Start running scheduled jobs. This requires the system.schedules table
+	// created by the migrations above.
+	sql.NewScheduler(s.sqlExecutor, &s.internalMemMetrics).Start(s.stopper)
+
 	if s.cfg.PIDFile != "" {
 		if err := ioutil.WriteFile(s.cfg.PIDFile, []byte(fmt.Sprintf("%d\n", os.Getpid())), 0644); err != nil {
 			log.Error(ctx, err)
@@ -47,6 +47,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/server/status"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/util/httputil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
@@ -266,6 +267,56 @@ func (s *statusServer) Certificates(
 	return cr, nil
 }
 
+// EncryptionStatus reports the progress of the encryption at rest of the
+// on-disk stores of a node.
+func (s *statusServer) EncryptionStatus(
+	ctx context.Context, req *serverpb.EncryptionStatusRequest,
+) (*serverpb.EncryptionStatusResponse, error) {
+	ctx = s.AnnotateCtx(ctx)
+	nodeID, local, err := s.parseNodeID(req.NodeId)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	if !local {
+		status, err := s.dialNode(nodeID)
+		if err != nil {
+			return nil, err
+		}
+		return status.EncryptionStatus(ctx, req)
+	}
+
+	resp := &serverpb.EncryptionStatusResponse{}
+	err = s.stores.VisitStores(func(store *storage.Store) error {
+		rocksdb, ok := store.Engine().(*engine.RocksDB)
+		if !ok {
+			// In-memory stores are never encrypted.
+			return nil
+		}
+		status, err := rocksdb.EncryptionStatus()
+		if err != nil {
+			return err
+		}
+		storeStatus := serverpb.StoreEncryptionStatus{
+			StoreID:     store.Ident.StoreID,
+			ActiveKeyID: status.ActiveKeyID,
+		}
+		for _, u := range status.Usage {
+			storeStatus.Usage = append(storeStatus.Usage, serverpb.EncryptionKeyUsage{
+				KeyID: u.KeyID,
+				Files: u.Files,
+				Bytes: u.Bytes,
+			})
+		}
+		resp.Stores = append(resp.Stores, storeStatus)
+		return nil
+	})
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, err.Error())
+	}
+	return resp, nil
+}
+
 // Details returns node details.
 func (s *statusServer) Details(
 	ctx context.Context, req *serverpb.DetailsRequest,
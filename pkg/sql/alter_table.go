@@ -214,6 +214,9 @@ func (n *alterTableNode) Start(ctx context.Context) error {
 			if n.tableDesc.PrimaryIndex.ContainsColumnID(col.ID) {
 				return fmt.Errorf("column %q is referenced by the primary key", col.Name)
 			}
+			if err := checkColumnNotInPolicies(n.tableDesc, col); err != nil {
+				return err
+			}
 			for _, idx := range n.tableDesc.AllNonDropIndexes() {
 				// We automatically drop indexes on that column that only
 				// index that column (and no other columns). If CASCADE is
@@ -236,8 +239,23 @@ func (n *alterTableNode) Start(ctx context.Context) error {
 				// includes non-PK columns other than the one being dropped.
 				containsOnlyThisColumn := true
 
-				// Analyze the index.
-				for _, id := range idx.ColumnIDs {
+				// Analyze the index. An expression element is considered to
+				// be defined over the columns its expression refers to.
+				for i, id := range idx.ColumnIDs {
+					if i < len(idx.ColumnExprs) && idx.ColumnExprs[i] != "" {
+						sourceIDs, err := n.tableDesc.IndexExprSourceColumnIDs(idx.ColumnExprs[i])
+						if err != nil {
+							return err
+						}
+						for _, sourceID := range sourceIDs {
+							if sourceID == col.ID {
+								containsThisColumn = true
+							} else {
+								containsOnlyThisColumn = false
+							}
+						}
+						continue
+					}
 					if id == col.ID {
 						containsThisColumn = true
 					} else {
@@ -265,6 +283,21 @@ func (n *alterTableNode) Start(ctx context.Context) error {
 						containsThisColumn = true
 					}
 				}
+				// The predicate of a partial index is considered like the
+				// columns of the index.
+				if idx.IsPartial() {
+					predIDs, err := n.tableDesc.IndexExprSourceColumnIDs(idx.Predicate)
+					if err != nil {
+						return err
+					}
+					for _, id := range predIDs {
+						if id == col.ID {
+							containsThisColumn = true
+						} else {
+							containsOnlyThisColumn = false
+						}
+					}
+				}
 
 				// Perform the DROP.
 				if containsThisColumn {
@@ -395,6 +428,20 @@ func (n *alterTableNode) Start(ctx context.Context) error {
 				return errors.Errorf("validating %s constraint %q unsupported", constraint.Kind, t.Constraint)
 			}
 
+		case *parser.AlterTableSetAudit:
+			if err := n.p.RequireSuperUser("change auditing settings on a table"); err != nil {
+				return err
+			}
+			changed, err := n.tableDesc.SetAuditMode(t.Mode)
+			if err != nil {
+				return err
+			}
+			descriptorChanged = descriptorChanged || changed
+
+		case *parser.AlterTableSetRowLevelSecurity:
+			descriptorChanged = descriptorChanged || n.tableDesc.RowLevelSecurity != t.Enabled
+			n.tableDesc.RowLevelSecurity = t.Enabled
+
 		case parser.ColumnMutationCmd:
 			// Column mutations
 			col, dropped, err := n.tableDesc.FindColumnByName(t.GetColumn())
@@ -0,0 +1,177 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+)
+
+// userOptionValues holds the columns of system.user_options set by the
+// options of a CREATE USER or ALTER USER statement, and their values.
+type userOptionValues struct {
+	columns []string
+	values  []interface{}
+}
+
+// resolveUserOptions validates the login options of a CREATE USER or ALTER
+// USER statement and converts them to values of system.user_options. The
+// options that lift a restriction (LOGIN, VALID UNTIL 'infinity' and
+// CONNECTION LIMIT -1) are stored as NULL.
+func (p *planner) resolveUserOptions(opts parser.UserOptions) (userOptionValues, error) {
+	var res userOptionValues
+	add := func(column string, value parser.Datum) {
+		res.columns = append(res.columns, column)
+		res.values = append(res.values, value)
+	}
+	if opts.Login != nil {
+		if *opts.Login {
+			add("noLogin", parser.DNull)
+		} else {
+			add("noLogin", parser.DBoolTrue)
+		}
+	}
+	if opts.ValidUntil != nil {
+		if *opts.ValidUntil == "infinity" {
+			add("validUntil", parser.DNull)
+		} else {
+			d, err := parser.ParseDTimestampTZ(*opts.ValidUntil, p.session.Location, time.Microsecond)
+			if err != nil {
+				return userOptionValues{}, err
+			}
+			add("validUntil", d)
+		}
+	}
+	if opts.ConnectionLimit != nil {
+		switch limit := *opts.ConnectionLimit; {
+		case limit == -1:
+			add("connectionLimit", parser.DNull)
+		case limit < -1:
+			return userOptionValues{}, errors.Errorf("invalid connection limit %d", limit)
+		default:
+			add("connectionLimit", parser.NewDInt(parser.DInt(limit)))
+		}
+	}
+	return res, nil
+}
+
+// setUserOptions writes the resolved login options of the given user to
+// system.user_options. The options that are not set are left unchanged.
+func (p *planner) setUserOptions(
+	ctx context.Context, username string, opts userOptionValues,
+) error {
+	if len(opts.columns) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	buf.WriteString(`UPSERT INTO system.user_options (username`)
+	for _, c := range opts.columns {
+		buf.WriteString(", ")
+		parser.Name(c).Format(&buf, parser.FmtSimple)
+	}
+	buf.WriteString(`) VALUES ($1`)
+	for i := range opts.columns {
+		fmt.Fprintf(&buf, ", $%d", i+2)
+	}
+	buf.WriteString(`)`)
+
+	internalExecutor := InternalExecutor{LeaseManager: p.LeaseMgr()}
+	_, err := internalExecutor.ExecuteStatementInTransaction(
+		ctx, "set-user-options", p.txn, buf.String(),
+		append([]interface{}{username}, opts.values...)...,
+	)
+	return err
+}
+
+type alterUserNode struct {
+	p        *planner
+	n        *parser.AlterUser
+	password *string
+	options  userOptionValues
+}
+
+// AlterUser changes the password and the login options of a user.
+// Privileges: UPDATE on system.users.
+func (p *planner) AlterUser(ctx context.Context, n *parser.AlterUser) (planNode, error) {
+	tDesc, err := getTableDesc(ctx, p.txn, p.getVirtualTabler(), &parser.TableName{DatabaseName: "system", TableName: "users"})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(tDesc, privilege.UPDATE); err != nil {
+		return nil, err
+	}
+
+	if n.Options.Password != nil && *n.Options.Password == "" {
+		return nil, security.ErrEmptyPassword
+	}
+
+	options, err := p.resolveUserOptions(n.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &alterUserNode{p: p, n: n, password: n.Options.Password, options: options}, nil
+}
+
+func (n *alterUserNode) Start(ctx context.Context) error {
+	normalizedUsername, err := NormalizeAndValidateUsername(string(n.n.Name))
+	if err != nil {
+		return err
+	}
+
+	internalExecutor := InternalExecutor{LeaseManager: n.p.LeaseMgr()}
+	if exists, err := userExists(ctx, internalExecutor, n.p.txn, normalizedUsername); err != nil {
+		return err
+	} else if !exists {
+		if n.n.IfExists {
+			return nil
+		}
+		return errors.Errorf("user %s does not exist", normalizedUsername)
+	}
+
+	if n.password != nil {
+		hashedPassword, err := security.HashPassword(*n.password)
+		if err != nil {
+			return err
+		}
+		if _, err := internalExecutor.ExecuteStatementInTransaction(
+			ctx,
+			"alter-user-password",
+			n.p.txn,
+			`UPDATE system.users SET "hashedPassword" = $2 WHERE username = $1`,
+			normalizedUsername,
+			hashedPassword,
+		); err != nil {
+			return err
+		}
+	}
+
+	return n.p.setUserOptions(ctx, normalizedUsername, n.options)
+}
+
+func (*alterUserNode) Next(context.Context) (bool, error) { return false, nil }
+func (*alterUserNode) Close(context.Context)              {}
+func (*alterUserNode) Values() parser.Datums              { return parser.Datums{} }
+func (*alterUserNode) DebugValues() debugValues           { return debugValues{} }
+func (*alterUserNode) MarkDebug(mode explainMode)         {}
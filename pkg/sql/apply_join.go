@@ -0,0 +1,377 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// outerScope is a data source whose columns can be referenced from
+// expressions planned "inside" of it, for example the arguments of a
+// set-returning function in FROM that refer to the FROM items to its
+// left (a "lateral" reference), or a correlated sub-query that refers
+// to the data source of the surrounding expression.
+//
+// The references are represented in expressions by outerVar nodes;
+// they evaluate to the values of the current row of the scope, which
+// is populated by the applyJoinNode or the subquery that owns the
+// scope.
+type outerScope struct {
+	info *dataSourceInfo
+
+	// used is set during name resolution when an expression refers to
+	// a column of this scope; usedCols indicates which columns.
+	used     bool
+	usedCols []bool
+
+	// row is the current row of the data source.
+	row parser.Datums
+}
+
+// markUsed records that the given column of the scope is referenced.
+func (s *outerScope) markUsed(colIdx int) {
+	if s.usedCols == nil {
+		s.usedCols = make([]bool, len(s.info.sourceColumns))
+	}
+	s.used = true
+	s.usedCols[colIdx] = true
+}
+
+// outerVar is a reference to a column of an outerScope. It is
+// carried in the expression tree in the same way as an IndexedVar.
+type outerVar struct {
+	scope  *outerScope
+	colIdx int
+}
+
+var _ parser.TypedExpr = &outerVar{}
+var _ parser.VariableExpr = &outerVar{}
+
+func (v *outerVar) Format(buf *bytes.Buffer, f parser.FmtFlags) {
+	v.scope.info.FormatVar(buf, f, v.colIdx)
+}
+
+func (v *outerVar) String() string { return parser.AsString(v) }
+
+func (v *outerVar) Walk(_ parser.Visitor) parser.Expr { return v }
+
+func (v *outerVar) Variable() {}
+
+func (v *outerVar) TypeCheck(_ *parser.SemaContext, _ parser.Type) (parser.TypedExpr, error) {
+	return v, nil
+}
+
+func (v *outerVar) ResolvedType() parser.Type {
+	return v.scope.info.sourceColumns[v.colIdx].Typ
+}
+
+func (v *outerVar) Eval(_ *parser.EvalContext) (parser.Datum, error) {
+	if v.scope.row == nil || v.scope.row[v.colIdx] == nil {
+		return nil, errors.Errorf("reference to %s evaluated outside of its scope", v)
+	}
+	return v.scope.row[v.colIdx], nil
+}
+
+// findOuterColumn resolves a column reference using the outer scopes,
+// starting from the innermost one. It returns nil if the column is
+// not found in any scope.
+//
+// When the plan is built for a specific row of the scope, which is
+// the case when an applyJoinNode or a correlated sub-query re-plans
+// its operand, the value of the column is known already; it is then
+// substituted directly, so that the plan can use it e.g. for index
+// selection. NULL values are not substituted as they do not carry a
+// type.
+func (v *nameResolutionVisitor) findOuterColumn(c *parser.ColumnItem) (parser.TypedExpr, error) {
+	for i := len(v.outerScopes) - 1; i >= 0; i-- {
+		scope := v.outerScopes[i]
+		_, colIdx, err := multiSourceInfo{scope.info}.findColumn(c)
+		if err != nil {
+			if _, ok := err.(*unresolvedNameError); ok {
+				continue
+			}
+			return nil, err
+		}
+		if scope.row != nil {
+			if d := scope.row[colIdx]; d != nil && d != parser.DNull {
+				return d, nil
+			}
+		}
+		scope.markUsed(colIdx)
+		return &outerVar{scope: scope, colIdx: colIdx}, nil
+	}
+	return nil, nil
+}
+
+// applyJoinNode is a planNode whose rows are the result of an inner
+// or left outer join where the right operand refers to the columns of
+// the left operand. The right operand is planned and run anew for
+// every row of the left operand, with the left row made visible to it
+// through an outerScope.
+type applyJoinNode struct {
+	planner  *planner
+	joinType joinType
+
+	// The data sources. The plan in right was built when the join was
+	// constructed; it describes the columns of the right operand and
+	// the shape of the plan for EXPLAIN, but it is never started.
+	left  planDataSource
+	right planDataSource
+
+	// planRight constructs a new plan for the right operand. It is
+	// invoked for every row of the left operand.
+	planRight func(context.Context) (planDataSource, error)
+
+	// scopes is the stack of outer scopes visible to the right
+	// operand, including scope (the left operand) at the end.
+	scopes []*outerScope
+	scope  *outerScope
+
+	// pred represents the join predicate.
+	pred *joinPredicate
+
+	// columns contains the metadata for the results of this node.
+	columns sqlbase.ResultColumns
+
+	// curRight is the plan for the right operand for the current
+	// left row, or nil if a new left row must be read.
+	curRight planNode
+
+	// leftEncoding is the encoding of the equality columns of the
+	// current left row.
+	leftEncoding []byte
+	// rightScratch is used to encode the equality columns of the
+	// right rows.
+	rightScratch []byte
+
+	// foundMatch indicates whether the current left row has been
+	// matched with at least one right row.
+	foundMatch bool
+
+	// output contains the last generated row of results from this node.
+	output parser.Datums
+
+	// emptyRight contains tuples of NULL values to use on the right for
+	// left outer joins when the on condition fails.
+	emptyRight parser.Datums
+
+	// explain indicates whether this node is running on behalf of
+	// EXPLAIN(DEBUG).
+	explain explainMode
+	// rowCount is used for DebugValues() only.
+	rowCount int
+}
+
+// makeApplyJoin constructs a planDataSource for a join where the
+// right operand refers to columns of the left operand via scope.
+// The right argument is the plan for the right operand built with
+// scope visible; planRight must construct an equivalent plan every
+// time it is called.
+func (p *planner) makeApplyJoin(
+	ctx context.Context,
+	astJoinType string,
+	left planDataSource,
+	right planDataSource,
+	planRight func(context.Context) (planDataSource, error),
+	scopes []*outerScope,
+	cond parser.JoinCond,
+) (planDataSource, error) {
+	typ, err := joinTypeFromAST(astJoinType)
+	if err != nil {
+		return planDataSource{}, err
+	}
+	if typ != joinTypeInner && typ != joinTypeLeftOuter {
+		return planDataSource{}, errors.New(
+			"the combining JOIN type must be INNER or LEFT for a LATERAL reference")
+	}
+
+	pred, info, err := p.makeJoinPredicate(ctx, left.info, right.info, cond)
+	if err != nil {
+		return planDataSource{}, err
+	}
+
+	n := &applyJoinNode{
+		planner:   p,
+		joinType:  typ,
+		left:      left,
+		right:     right,
+		planRight: planRight,
+		scopes:    scopes,
+		scope:     scopes[len(scopes)-1],
+		pred:      pred,
+		columns:   info.sourceColumns,
+	}
+	return planDataSource{
+		info: info,
+		plan: n,
+	}, nil
+}
+
+// MarkDebug implements the planNode interface.
+func (n *applyJoinNode) MarkDebug(mode explainMode) {
+	if mode != explainDebug {
+		panic(fmt.Sprintf("unknown debug mode %d", mode))
+	}
+	// The operands are not marked: the join needs their actual results
+	// to compute its own.
+	n.explain = mode
+}
+
+// Start implements the planNode interface.
+func (n *applyJoinNode) Start(ctx context.Context) error {
+	if err := n.left.plan.Start(ctx); err != nil {
+		return err
+	}
+
+	n.output = make(parser.Datums, len(n.columns))
+	if n.joinType == joinTypeLeftOuter {
+		n.emptyRight = make(parser.Datums, len(planColumns(n.right.plan)))
+		for i := range n.emptyRight {
+			n.emptyRight[i] = parser.DNull
+		}
+	}
+	return nil
+}
+
+// startRight plans and starts the right operand for the current
+// left row.
+func (n *applyJoinNode) startRight(ctx context.Context) error {
+	p := n.planner
+
+	savedScopes := p.outerScopes
+	p.outerScopes = n.scopes
+	src, err := n.planRight(ctx)
+	p.outerScopes = savedScopes
+	if err != nil {
+		return err
+	}
+
+	plan, err := p.optimizePlan(ctx, src.plan, allColumns(src.plan))
+	if err != nil {
+		src.plan.Close(ctx)
+		return err
+	}
+	if err := p.startPlan(ctx, plan); err != nil {
+		plan.Close(ctx)
+		return err
+	}
+	n.curRight = plan
+	return nil
+}
+
+// Next implements the planNode interface.
+func (n *applyJoinNode) Next(ctx context.Context) (bool, error) {
+	for {
+		if n.curRight == nil {
+			hasRow, err := n.left.plan.Next(ctx)
+			if err != nil || !hasRow {
+				return false, err
+			}
+			n.scope.row = n.left.plan.Values()
+			n.foundMatch = false
+
+			var containsNull bool
+			n.leftEncoding, containsNull, err = n.pred.encode(
+				n.leftEncoding[:0], n.scope.row, n.pred.leftEqualityIndices)
+			if err != nil {
+				return false, err
+			}
+			if containsNull {
+				// NULL never compares equal: the left row cannot be matched.
+				if n.joinType == joinTypeLeftOuter {
+					n.pred.prepareRow(n.output, n.scope.row, n.emptyRight)
+					n.rowCount++
+					return true, nil
+				}
+				continue
+			}
+
+			if err := n.startRight(ctx); err != nil {
+				return false, err
+			}
+		}
+
+		hasRow, err := n.curRight.Next(ctx)
+		if err != nil {
+			return false, err
+		}
+		if !hasRow {
+			n.curRight.Close(ctx)
+			n.curRight = nil
+			if !n.foundMatch && n.joinType == joinTypeLeftOuter {
+				n.pred.prepareRow(n.output, n.scope.row, n.emptyRight)
+				n.rowCount++
+				return true, nil
+			}
+			continue
+		}
+
+		lrow, rrow := n.scope.row, n.curRight.Values()
+		if len(n.pred.rightEqualityIndices) > 0 {
+			var containsNull bool
+			n.rightScratch, containsNull, err = n.pred.encode(
+				n.rightScratch[:0], rrow, n.pred.rightEqualityIndices)
+			if err != nil {
+				return false, err
+			}
+			if containsNull || !bytes.Equal(n.leftEncoding, n.rightScratch) {
+				continue
+			}
+		}
+		passesOnCond, err := n.pred.eval(&n.planner.evalCtx, n.output, lrow, rrow)
+		if err != nil {
+			return false, err
+		}
+		if !passesOnCond {
+			continue
+		}
+		n.foundMatch = true
+		n.pred.prepareRow(n.output, lrow, rrow)
+		n.rowCount++
+		return true, nil
+	}
+}
+
+// Values implements the planNode interface.
+func (n *applyJoinNode) Values() parser.Datums {
+	return n.output
+}
+
+// DebugValues implements the planNode interface.
+func (n *applyJoinNode) DebugValues() debugValues {
+	return debugValues{
+		rowIdx: n.rowCount,
+		key:    fmt.Sprintf("%d", n.rowCount),
+		value:  n.output.String(),
+		output: debugValueRow,
+	}
+}
+
+// Close implements the planNode interface.
+func (n *applyJoinNode) Close(ctx context.Context) {
+	if n.curRight != nil {
+		n.curRight.Close(ctx)
+		n.curRight = nil
+	}
+	n.right.plan.Close(ctx)
+	n.left.plan.Close(ctx)
+}
@@ -0,0 +1,82 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// auditLogger receives the entries of the SQL audit log. It is shared by
+// all the executors in the process so that they write to the same files,
+// kept separate from the main log.
+var auditLogger = log.NewSecondaryLogger("sql-audit", true /* enableGc */, true /* forceSyncWrites */)
+
+// auditEvent represents an access to an audited table by the statement
+// currently being planned.
+type auditEvent struct {
+	desc    *sqlbase.TableDescriptor
+	writing bool
+}
+
+// maybeAudit registers an audit event for the given table, if the table
+// is audited. priv is the privilege that was checked to access the table;
+// it determines whether the access is a read or a write.
+func (p *planner) maybeAudit(desc *sqlbase.TableDescriptor, priv privilege.Kind) {
+	if desc.AuditMode != sqlbase.TableDescriptor_READWRITE {
+		return
+	}
+	writing := priv == privilege.INSERT || priv == privilege.UPDATE || priv == privilege.DELETE
+	for i := range p.auditEvents {
+		ev := &p.auditEvents[i]
+		if ev.desc.ID == desc.ID {
+			// The table is already audited for this statement. Writes
+			// usually scan the table too; report the access as a write.
+			ev.writing = ev.writing || writing
+			return
+		}
+	}
+	p.auditEvents = append(p.auditEvents, auditEvent{desc: desc, writing: writing})
+}
+
+// maybeLogAudit writes an entry to the SQL audit log for every audited
+// table accessed by the statement just executed.
+func (e *Executor) maybeLogAudit(p *planner, stmt Statement, result Result, err error) {
+	if len(p.auditEvents) == 0 {
+		return
+	}
+	outcome := "OK"
+	if err != nil {
+		outcome = err.Error()
+	}
+	rows := result.RowsAffected
+	if result.Type == parser.Rows && result.Rows != nil {
+		rows = result.Rows.Len()
+	}
+	// The statement is logged with its constants hidden, like in the
+	// statement statistics, so that the log does not leak the data.
+	stmtStr := parser.AsStringWithFlags(stmt.AST, parser.FmtHideConstants)
+	for _, ev := range p.auditEvents {
+		mode := "r"
+		if ev.writing {
+			mode = "rw"
+		}
+		auditLogger.Logf(p.session.Ctx(),
+			"user=%s table=%q mode=%s stmt=%q rows=%d outcome=%q",
+			p.session.User, ev.desc.Name, mode, stmtStr, rows, outcome)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql_test
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// readAuditLog returns the contents of the SQL audit log files.
+func readAuditLog(t *testing.T) string {
+	log.Flush()
+	files, err := log.ListLogFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var contents []string
+	for _, f := range files {
+		if !strings.HasSuffix(f.Details.Program, "-sql-audit") {
+			continue
+		}
+		r, err := log.GetLogReader(f.Name, true /* restricted */)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadAll(r)
+		if err := r.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents = append(contents, string(b))
+	}
+	return strings.Join(contents, "")
+}
+
+func TestAuditLogging(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	s := log.ScopeWithoutShowLogs(t)
+	defer s.Close(t)
+
+	params, _ := createTestServerParams()
+	srv, sqlDB, _ := serverutils.StartServer(t, params)
+	defer srv.Stopper().Stop(context.TODO())
+
+	if _, err := sqlDB.Exec(`
+		CREATE DATABASE d;
+		CREATE TABLE d.audited (k INT PRIMARY KEY, v INT);
+		CREATE TABLE d.other (k INT PRIMARY KEY);
+		ALTER TABLE d.audited EXPERIMENTAL_AUDIT SET READ WRITE;
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := sqlDB.Exec(`INSERT INTO d.audited VALUES (1, 1), (2, 2)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sqlDB.Exec(`SELECT * FROM d.audited WHERE k = $1`, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sqlDB.Exec(`INSERT INTO d.audited VALUES (1, 1)`); !testutils.IsError(err, "duplicate key value") {
+		t.Fatalf("expected duplicate key error, got %v", err)
+	}
+	if _, err := sqlDB.Exec(`SELECT * FROM d.audited WHERE v = 12345`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sqlDB.Exec(`SELECT * FROM d.other`); err != nil {
+		t.Fatal(err)
+	}
+
+	auditLog := readAuditLog(t)
+	for _, expected := range []string{
+		`user=root table="audited" mode=rw stmt="INSERT INTO d.audited VALUES (_, _)" rows=2 outcome="OK"`,
+		`user=root table="audited" mode=r stmt="SELECT * FROM d.audited WHERE k = $1" rows=1 outcome="OK"`,
+		`user=root table="audited" mode=rw stmt="INSERT INTO d.audited VALUES (_, _)" rows=0 outcome="duplicate key value`,
+		`user=root table="audited" mode=r stmt="SELECT * FROM d.audited WHERE v = _" rows=0 outcome="OK"`,
+	} {
+		if !strings.Contains(auditLog, expected) {
+			t.Errorf("expected audit log to contain %s, got:\n%s", expected, auditLog)
+		}
+	}
+	if strings.Contains(auditLog, "12345") {
+		t.Errorf("unexpected constant in the audit log:\n%s", auditLog)
+	}
+	if strings.Contains(auditLog, `table="other"`) {
+		t.Errorf("unexpected entry for an unaudited table:\n%s", auditLog)
+	}
+
+	if _, err := sqlDB.Exec(`ALTER TABLE d.audited EXPERIMENTAL_AUDIT SET OFF`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sqlDB.Exec(`SELECT * FROM d.audited WHERE v > 2`); err != nil {
+		t.Fatal(err)
+	}
+	if auditLog := readAuditLog(t); strings.Contains(auditLog, "v > _") {
+		t.Errorf("unexpected entry after auditing was disabled:\n%s", auditLog)
+	}
+}
@@ -17,9 +17,15 @@ package sql
 import (
 	"fmt"
 
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 )
 
 // AuthorizationAccessor for checking authorization (e.g. desc privileges).
@@ -43,22 +49,126 @@ var _ AuthorizationAccessor = &planner{}
 func (p *planner) CheckPrivilege(
 	descriptor sqlbase.DescriptorProto, privilege privilege.Kind,
 ) error {
-	if descriptor.GetPrivileges().CheckPrivilege(p.session.User, privilege) {
+	privs := descriptor.GetPrivileges()
+	if privs.CheckPrivilege(p.session.User, privilege) {
 		return nil
 	}
+	// The user does not hold the privilege itself; it may have been granted to
+	// one of the roles the user is a member of.
+	memberOf, err := p.MemberOf(p.session.Ctx(), p.session.User)
+	if err != nil {
+		return err
+	}
+	for role := range memberOf {
+		if privs.CheckPrivilege(role, privilege) {
+			return nil
+		}
+	}
 	return fmt.Errorf("user %s does not have %s privilege on %s %s",
 		p.session.User, privilege, descriptor.TypeName(), descriptor.GetName())
 }
 
 // anyPrivilege implements the AuthorizationAccessor interface.
 func (p *planner) anyPrivilege(descriptor sqlbase.DescriptorProto) error {
-	if userCanSeeDescriptor(descriptor, p.session.User) {
+	memberOf, err := p.MemberOf(p.session.Ctx(), p.session.User)
+	if err != nil {
+		return err
+	}
+	if userCanSeeDescriptor(descriptor, p.session.User, memberOf) {
 		return nil
 	}
 	return fmt.Errorf("user %s has no privileges on %s %s",
 		p.session.User, descriptor.TypeName(), descriptor.GetName())
 }
 
+// columnPrivileges returns whether the user has `privilege` on each of the
+// given columns of a table, either on the table or on the column itself.
+func (p *planner) columnPrivileges(
+	desc *sqlbase.TableDescriptor, privilege privilege.Kind, cols []sqlbase.ColumnDescriptor,
+) ([]bool, error) {
+	memberOf, err := p.MemberOf(p.session.Ctx(), p.session.User)
+	if err != nil {
+		return nil, err
+	}
+	privs := desc.GetPrivileges()
+	allowed := make([]bool, len(cols))
+	for i := range cols {
+		allowed[i] = privs.CheckColumnPrivilege(p.session.User, privilege, cols[i].ID)
+		for role := range memberOf {
+			if allowed[i] {
+				break
+			}
+			allowed[i] = privs.CheckColumnPrivilege(role, privilege, cols[i].ID)
+		}
+	}
+	return allowed, nil
+}
+
+// checkColumnPrivileges verifies that the user has `privilege` on all the
+// given columns of a table. If the user does not have the privilege on any
+// column of the table, the error is the same as CheckPrivilege's.
+func (p *planner) checkColumnPrivileges(
+	desc *sqlbase.TableDescriptor, privilege privilege.Kind, cols []sqlbase.ColumnDescriptor,
+) error {
+	tableErr := p.CheckPrivilege(desc, privilege)
+	if tableErr == nil {
+		return nil
+	}
+	allowed, err := p.columnPrivileges(desc, privilege, desc.Columns)
+	if err != nil {
+		return err
+	}
+	anyAllowed := false
+	for _, ok := range allowed {
+		anyAllowed = anyAllowed || ok
+	}
+	if !anyAllowed {
+		return tableErr
+	}
+	if allowed, err = p.columnPrivileges(desc, privilege, cols); err != nil {
+		return err
+	}
+	for i, ok := range allowed {
+		if !ok {
+			return pgerror.NewErrorf(pgerror.CodeInsufficientPrivilegeError,
+				"user %s does not have %s privilege on column %q of table %s",
+				p.session.User, privilege, cols[i].Name, desc.GetName())
+		}
+	}
+	return nil
+}
+
+// denyColumns is used when the user does not have the SELECT privilege on a
+// table, and marks the result columns of the table that the user is not
+// allowed to read. cols and resultCols must be in the same order. tableErr
+// is returned if the user cannot read any column.
+func (p *planner) denyColumns(
+	desc *sqlbase.TableDescriptor,
+	cols []sqlbase.ColumnDescriptor,
+	resultCols sqlbase.ResultColumns,
+	tableErr error,
+) error {
+	allowed, err := p.columnPrivileges(desc, privilege.SELECT, cols)
+	if err != nil {
+		return err
+	}
+	anyAllowed := false
+	for i, ok := range allowed {
+		if ok {
+			anyAllowed = true
+		} else if _, err := desc.FindActiveColumnByID(cols[i].ID); err == nil && !resultCols[i].Hidden {
+			// Hidden columns, like the implicit primary key, are not
+			// covered by column privileges. Neither are the columns being
+			// added or dropped, which are only used internally.
+			resultCols[i].Denied = true
+		}
+	}
+	if !anyAllowed {
+		return tableErr
+	}
+	return nil
+}
+
 // RequireSuperUser implements the AuthorizationAccessor interface.
 func (p *planner) RequireSuperUser(action string) error {
 	if p.session.User != security.RootUser && p.session.User != security.NodeUser {
@@ -67,6 +177,131 @@ func (p *planner) RequireSuperUser(action string) error {
 	return nil
 }
 
-func userCanSeeDescriptor(descriptor sqlbase.DescriptorProto, user string) bool {
-	return descriptor.GetPrivileges().AnyPrivilege(user) || isVirtualDescriptor(descriptor)
+// userCanSeeDescriptor returns true if the user, or any of the roles in
+// memberOf, has a privilege on the descriptor.
+func userCanSeeDescriptor(
+	descriptor sqlbase.DescriptorProto, user string, memberOf map[string]bool,
+) bool {
+	if isVirtualDescriptor(descriptor) {
+		return true
+	}
+	privs := descriptor.GetPrivileges()
+	if privs.AnyPrivilege(user) {
+		return true
+	}
+	for role := range memberOf {
+		if privs.AnyPrivilege(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// roleMembershipCache caches the result of MemberOf for each user. The
+// memberships are only valid for a given version of the system.role_members
+// table descriptor: every statement changing role memberships bumps that
+// version, which reaches all the nodes through the gossiped system config.
+type roleMembershipCache struct {
+	syncutil.Mutex
+	// tableVersion is the version of system.role_members the cached
+	// memberships were read at.
+	tableVersion sqlbase.DescriptorVersion
+	// userCache maps a user to the roles it is a member of, directly or
+	// indirectly, and whether it has the ADMIN OPTION on them.
+	userCache map[string]map[string]bool
+}
+
+// roleMembersTableVersion returns the version of the system.role_members
+// table descriptor in the system config used by the current transaction,
+// or 0 if it is not known.
+func (p *planner) roleMembersTableVersion() sqlbase.DescriptorVersion {
+	cfg := p.session.tables.databaseCache.systemConfig
+	val := cfg.GetValue(sqlbase.MakeDescMetadataKey(keys.RoleMembersTableID))
+	if val == nil {
+		return 0
+	}
+	var desc sqlbase.Descriptor
+	if err := val.GetProto(&desc); err != nil {
+		return 0
+	}
+	if table := desc.GetTable(); table != nil {
+		return table.Version
+	}
+	return 0
+}
+
+// MemberOf returns all the roles the given user is a member of, directly or
+// through other roles, mapped to whether the user has the ADMIN OPTION on the
+// role. The ADMIN OPTION of a role also applies to all of the roles it is a
+// member of.
+//
+// The result is cached across sessions and must not be modified.
+func (p *planner) MemberOf(ctx context.Context, user string) (map[string]bool, error) {
+	if user == security.RootUser || user == security.NodeUser {
+		// These users are not members of any role; they don't need to be.
+		return nil, nil
+	}
+
+	cache := p.session.roleMembers
+	tableVersion := p.roleMembersTableVersion()
+	if cache == nil || tableVersion == 0 {
+		return p.resolveMemberOf(ctx, user)
+	}
+
+	cache.Lock()
+	if cache.tableVersion != tableVersion {
+		cache.tableVersion = tableVersion
+		cache.userCache = make(map[string]map[string]bool)
+	}
+	memberOf, ok := cache.userCache[user]
+	cache.Unlock()
+	if ok {
+		return memberOf, nil
+	}
+
+	// The lookup is done without holding the lock. The memberships are only
+	// cached if the table version hasn't changed in the meantime.
+	memberOf, err := p.resolveMemberOf(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	cache.Lock()
+	if cache.tableVersion == tableVersion {
+		cache.userCache[user] = memberOf
+	}
+	cache.Unlock()
+	return memberOf, nil
+}
+
+// resolveMemberOf reads the transitive role memberships of a user from
+// system.role_members, bypassing the cache.
+func (p *planner) resolveMemberOf(ctx context.Context, user string) (map[string]bool, error) {
+	ie := InternalExecutor{LeaseManager: p.LeaseMgr()}
+	const lookupRoles = `SELECT role, "isAdmin" FROM system.role_members WHERE member = $1`
+
+	ret := make(map[string]bool)
+	// visited tracks the members whose roles have already been looked up, and
+	// whether the ADMIN OPTION carries over to their roles.
+	visited := map[string]bool{user: false}
+	toVisit := []string{user}
+	for len(toVisit) > 0 {
+		member := toVisit[0]
+		toVisit = toVisit[1:]
+		rows, err := ie.QueryRowsInTransaction(ctx, "lookup-role-members", p.txn, lookupRoles, member)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			role := string(parser.MustBeDString(row[0]))
+			isAdmin := bool(*row[1].(*parser.DBool)) || visited[member]
+			ret[role] = ret[role] || isAdmin
+			if wasAdmin, ok := visited[role]; !ok || (isAdmin && !wasAdmin) {
+				// Revisit roles when the ADMIN OPTION is found for them, so
+				// that it propagates to their own roles.
+				visited[role] = isAdmin
+				toVisit = append(toVisit, role)
+			}
+		}
+	}
+	return ret, nil
 }
@@ -142,7 +142,7 @@ func (sc *SchemaChanger) runBackfill(
 			switch t := m.Descriptor_.(type) {
 			case *sqlbase.DescriptorMutation_Column:
 				desc := m.GetColumn()
-				if desc.DefaultExpr != nil || !desc.Nullable {
+				if desc.DefaultExpr != nil || !desc.Nullable || tableDesc.IsIndexExprColumn(desc.ID) {
 					needColumnBackfill = true
 				}
 			case *sqlbase.DescriptorMutation_Index:
@@ -298,6 +298,7 @@ func (sc *SchemaChanger) truncateIndexes(
 					return err
 				}
 				td := tableDeleter{rd: rd}
+				defer td.close(ctx)
 				if err := td.init(txn); err != nil {
 					return err
 				}
@@ -29,17 +29,27 @@ import (
 )
 
 type checkHelper struct {
-	exprs        []parser.TypedExpr
+	exprs []parser.TypedExpr
+	// policyExpr, if set, is the row level security expression new rows must
+	// satisfy.
+	policyExpr   parser.TypedExpr
+	tableName    string
 	cols         []sqlbase.ColumnDescriptor
 	sourceInfo   *dataSourceInfo
 	ivars        []parser.IndexedVar
 	curSourceRow parser.Datums
 }
 
+// init prepares the CHECK constraints of a table, and the row level security
+// policy expression if it is not nil, to be evaluated on new rows.
 func (c *checkHelper) init(
-	ctx context.Context, p *planner, tn *parser.TableName, tableDesc *sqlbase.TableDescriptor,
+	ctx context.Context,
+	p *planner,
+	tn *parser.TableName,
+	tableDesc *sqlbase.TableDescriptor,
+	policy parser.Expr,
 ) error {
-	if len(tableDesc.Checks) == 0 {
+	if len(tableDesc.Checks) == 0 && policy == nil {
 		return nil
 	}
 
@@ -67,6 +77,15 @@ func (c *checkHelper) init(
 		}
 		c.exprs[i] = typedExpr
 	}
+	if policy != nil {
+		typedExpr, err := p.analyzeExpr(ctx, policy, multiSourceInfo{c.sourceInfo}, ivarHelper,
+			parser.TypeBool, false, "")
+		if err != nil {
+			return err
+		}
+		c.policyExpr = typedExpr
+		c.tableName = tableDesc.Name
+	}
 	c.ivars = ivarHelper.GetIndexedVars()
 	c.curSourceRow = make(parser.Datums, len(c.cols))
 	return nil
@@ -78,7 +97,7 @@ func (c *checkHelper) init(
 func (c *checkHelper) loadRow(
 	colIdx map[sqlbase.ColumnID]int, row parser.Datums, merge bool,
 ) error {
-	if len(c.exprs) == 0 {
+	if len(c.exprs) == 0 && c.policyExpr == nil {
 		return nil
 	}
 	// Populate IndexedVars.
@@ -128,6 +147,15 @@ func (c *checkHelper) check(ctx *parser.EvalContext) error {
 			return fmt.Errorf("failed to satisfy CHECK constraint (%s)", expr)
 		}
 	}
+	if c.policyExpr != nil {
+		// Unlike CHECK constraints, a NULL policy result rejects the row.
+		if d, err := c.policyExpr.Eval(ctx); err != nil {
+			return err
+		} else if d != parser.DBoolTrue {
+			return pgerror.NewErrorf(pgerror.CodeInsufficientPrivilegeError,
+				"new row violates row level security policy for table %q", c.tableName)
+		}
+	}
 	return nil
 }
 
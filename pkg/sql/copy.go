@@ -20,7 +20,6 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"time"
 	"unsafe"
 
 	"golang.org/x/net/context"
@@ -194,55 +193,15 @@ func (n *copyNode) addRow(ctx context.Context, line []byte) error {
 			exprs[i] = parser.DNull
 			continue
 		}
-		var d parser.Datum
-		switch t := n.resultColumns[i].Typ; t {
-		case parser.TypeBool:
-			d, err = parser.ParseDBool(s)
-		case parser.TypeBytes:
-			s, err = decodeCopy(s)
-			d = parser.NewDBytes(parser.DBytes(s))
-		case parser.TypeDate:
-			s, err = decodeCopy(s)
-			if err != nil {
-				break
-			}
-			d, err = parser.ParseDDate(s, n.p.session.Location)
-		case parser.TypeDecimal:
-			d, err = parser.ParseDDecimal(s)
-		case parser.TypeFloat:
-			d, err = parser.ParseDFloat(s)
-		case parser.TypeInt:
-			d, err = parser.ParseDInt(s)
-		case parser.TypeInterval:
-			s, err = decodeCopy(s)
-			if err != nil {
-				break
-			}
-			d, err = parser.ParseDInterval(s)
-		case parser.TypeString:
-			s, err = decodeCopy(s)
-			d = parser.NewDString(s)
-		case parser.TypeTimestamp:
-			s, err = decodeCopy(s)
-			if err != nil {
-				break
-			}
-			d, err = parser.ParseDTimestamp(s, time.Microsecond)
-		case parser.TypeTimestampTZ:
-			s, err = decodeCopy(s)
-			if err != nil {
-				break
-			}
-			d, err = parser.ParseDTimestampTZ(s, n.p.session.Location, time.Microsecond)
-		case parser.TypeUUID:
-			s, err = decodeCopy(s)
-			if err != nil {
-				break
-			}
-			d, err = parser.ParseDUuidFromString(s)
+		switch n.resultColumns[i].Typ {
+		case parser.TypeBool, parser.TypeDecimal, parser.TypeFloat, parser.TypeInt:
+			// These types cannot contain escape sequences.
 		default:
-			return fmt.Errorf("unknown type %s", t)
+			if s, err = decodeCopy(s); err != nil {
+				return err
+			}
 		}
+		d, err := parser.ParseStringAs(n.resultColumns[i].Typ, s, n.p.session.Location)
 		if err != nil {
 			return err
 		}
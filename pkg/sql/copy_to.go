@@ -0,0 +1,221 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// CopyOutFormat describes how the rows of a COPY TO statement are encoded.
+//
+// See: https://www.postgresql.org/docs/9.6/static/sql-copy.html
+type CopyOutFormat struct {
+	// CSV selects the CSV format. Otherwise the text format is used.
+	CSV bool
+	// Delimiter separates the columns of a row.
+	Delimiter byte
+	// Null is the string written for NULL values.
+	Null string
+	// Header, which is only allowed in CSV format, makes the output start
+	// with a line containing the column names.
+	Header bool
+}
+
+// CopyOutWriter receives the rows of a COPY TO STDOUT statement while the
+// statement executes, so that they can be streamed to the client without
+// buffering the whole result. It is implemented by pgwire connections.
+type CopyOutWriter interface {
+	// BeginCopyOut is called once, before the first row is produced.
+	BeginCopyOut(ctx context.Context, columns sqlbase.ResultColumns, format CopyOutFormat) error
+	// CopyOutRow encodes a row and sends it to the client.
+	CopyOutRow(ctx context.Context, row parser.Datums) error
+}
+
+// copyToNode is the planNode for COPY TO STDOUT. Instead of returning rows
+// to the executor, it hands every row of its source to the session's
+// CopyOutWriter as soon as it is produced. Next() returns true once per row
+// sent, so that the executor can count them.
+type copyToNode struct {
+	p       *planner
+	source  planNode
+	columns sqlbase.ResultColumns
+	format  CopyOutFormat
+}
+
+// CopyTo streams the rows of a table or query to the client.
+// Privileges: SELECT on table.
+func (p *planner) CopyTo(ctx context.Context, n *parser.CopyTo) (planNode, error) {
+	if p.session.CopyOut == nil {
+		return nil, errors.New("COPY TO STDOUT is only supported over the PostgreSQL wire protocol")
+	}
+	format, err := makeCopyOutFormat(n.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	query := n.Query
+	if query == nil {
+		exprs := parser.SelectExprs{{Expr: parser.StarExpr()}}
+		if len(n.Columns) > 0 {
+			exprs = make(parser.SelectExprs, len(n.Columns))
+			for i, c := range n.Columns {
+				exprs[i] = parser.SelectExpr{Expr: c}
+			}
+		}
+		query = &parser.Select{
+			Select: &parser.SelectClause{
+				Exprs: exprs,
+				From:  &parser.From{Tables: parser.TableExprs{&n.Table}},
+			},
+		}
+	}
+
+	source, err := p.newPlan(ctx, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	columns := planColumns(source)
+	for _, c := range columns {
+		if err := checkResultType(c.Typ); err != nil {
+			source.Close(ctx)
+			return nil, err
+		}
+	}
+	return &copyToNode{
+		p:       p,
+		source:  source,
+		columns: columns,
+		format:  format,
+	}, nil
+}
+
+// makeCopyOutFormat interprets the options of a COPY TO statement. Both the
+// generic "WITH (option value, ...)" and the older "WITH CSV HEADER" syntax
+// are reduced to the same options by the parser.
+func makeCopyOutFormat(opts parser.KVOptions) (CopyOutFormat, error) {
+	format := CopyOutFormat{Delimiter: '\t', Null: nullString}
+	var delimiter, null *string
+	var header bool
+	for _, opt := range opts {
+		switch strings.ToLower(opt.Key) {
+		case "format":
+			switch strings.ToLower(opt.Value) {
+			case "text":
+				format.CSV = false
+			case "csv":
+				format.CSV = true
+			case "binary":
+				return CopyOutFormat{}, errors.New("COPY TO STDOUT does not support the binary format")
+			default:
+				return CopyOutFormat{}, errors.Errorf("COPY format %q not recognized", opt.Value)
+			}
+		case "delimiter":
+			v := opt.Value
+			delimiter = &v
+		case "null":
+			v := opt.Value
+			null = &v
+		case "header":
+			switch strings.ToLower(opt.Value) {
+			case "", "true", "on", "1":
+				header = true
+			case "false", "off", "0":
+				header = false
+			default:
+				return CopyOutFormat{}, errors.Errorf("header requires a boolean value, got %q", opt.Value)
+			}
+		default:
+			return CopyOutFormat{}, errors.Errorf("COPY option %q not recognized", opt.Key)
+		}
+	}
+
+	if format.CSV {
+		format.Delimiter = ','
+		format.Null = ""
+	}
+	if delimiter != nil {
+		if len(*delimiter) != 1 {
+			return CopyOutFormat{}, errors.New("COPY delimiter must be a single one-byte character")
+		}
+		format.Delimiter = (*delimiter)[0]
+	}
+	if null != nil {
+		format.Null = *null
+	}
+	if header && !format.CSV {
+		return CopyOutFormat{}, errors.New("COPY HEADER is only available in CSV mode")
+	}
+	format.Header = header
+
+	switch d := format.Delimiter; {
+	case d == '\r' || d == '\n' || d == '\\' || d == '"' && format.CSV:
+		return CopyOutFormat{}, errors.Errorf("COPY delimiter cannot be %q", d)
+	case strings.IndexByte(format.Null, d) >= 0:
+		return CopyOutFormat{}, errors.New("COPY delimiter must not appear in the NULL specification")
+	}
+	return format, nil
+}
+
+// checkCopyToAlone returns an error if the statement list contains a COPY TO
+// statement alongside other statements. COPY TO sends its rows to the client
+// while it executes, ahead of the buffered results of any statement
+// preceding it, so it has to be the only statement of a query.
+func checkCopyToAlone(stmts parser.StatementList) error {
+	if len(stmts) < 2 {
+		return nil
+	}
+	for _, stmt := range stmts {
+		if _, ok := stmt.(*parser.CopyTo); ok {
+			return errors.New("COPY TO STDOUT must be the only statement in a query")
+		}
+	}
+	return nil
+}
+
+// Start implements the planNode interface.
+func (n *copyToNode) Start(ctx context.Context) error {
+	if err := n.source.Start(ctx); err != nil {
+		return err
+	}
+	return n.p.session.CopyOut.BeginCopyOut(ctx, n.columns, n.format)
+}
+
+// Next implements the planNode interface.
+func (n *copyToNode) Next(ctx context.Context) (bool, error) {
+	if next, err := n.source.Next(ctx); !next || err != nil {
+		return false, err
+	}
+	if err := n.p.session.CopyOut.CopyOutRow(ctx, n.source.Values()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Values implements the planNode interface.
+func (*copyToNode) Values() parser.Datums { return nil }
+
+// Close implements the planNode interface.
+func (n *copyToNode) Close(ctx context.Context) {
+	n.source.Close(ctx)
+}
+
+func (*copyToNode) MarkDebug(_ explainMode)  { panic("unimplemented") }
+func (*copyToNode) DebugValues() debugValues { panic("unimplemented") }
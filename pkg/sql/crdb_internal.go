@@ -40,6 +40,7 @@ var crdbInternal = virtualSchema{
 		crdbInternalSchemaChangesTable,
 		crdbInternalStmtStatsTable,
 		crdbInternalJobsTable,
+		crdbInternalSchedulesTable,
 		crdbInternalSessionTraceTable,
 	},
 }
@@ -102,6 +103,10 @@ CREATE TABLE crdb_internal.tables (
 		if err != nil {
 			return err
 		}
+		memberOf, err := p.MemberOf(ctx, p.session.User)
+		if err != nil {
+			return err
+		}
 		dbNames := make(map[sqlbase.ID]string)
 		// Record database descriptors for name lookups.
 		for _, desc := range descs {
@@ -114,7 +119,7 @@ CREATE TABLE crdb_internal.tables (
 		// include added and dropped descriptors.
 		for _, desc := range descs {
 			table, ok := desc.(*sqlbase.TableDescriptor)
-			if !ok || !userCanSeeDescriptor(table, p.session.User) {
+			if !ok || !userCanSeeDescriptor(table, p.session.User, memberOf) {
 				continue
 			}
 			dbName := dbNames[table.ParentID]
@@ -172,11 +177,15 @@ CREATE TABLE crdb_internal.schema_changes (
 		if err != nil {
 			return err
 		}
+		memberOf, err := p.MemberOf(ctx, p.session.User)
+		if err != nil {
+			return err
+		}
 		// Note: we do not use forEachTableDesc() here because we want to
 		// include added and dropped descriptors.
 		for _, desc := range descs {
 			table, ok := desc.(*sqlbase.TableDescriptor)
-			if !ok || !userCanSeeDescriptor(table, p.session.User) {
+			if !ok || !userCanSeeDescriptor(table, p.session.User, memberOf) {
 				continue
 			}
 			tableID := parser.NewDInt(parser.DInt(int64(table.ID)))
@@ -225,10 +234,15 @@ CREATE TABLE crdb_internal.leases (
   deleted     BOOL NOT NULL
 );
 `,
-	populate: func(_ context.Context, p *planner, addRow func(...parser.Datum) error) error {
+	populate: func(ctx context.Context, p *planner, addRow func(...parser.Datum) error) error {
 		leaseMgr := p.LeaseMgr()
 		nodeID := parser.NewDInt(parser.DInt(int64(leaseMgr.nodeID.Get())))
 
+		memberOf, err := p.MemberOf(ctx, p.session.User)
+		if err != nil {
+			return err
+		}
+
 		leaseMgr.mu.Lock()
 		defer leaseMgr.mu.Unlock()
 
@@ -242,7 +256,7 @@ CREATE TABLE crdb_internal.leases (
 				dropped := parser.MakeDBool(parser.DBool(ts.dropped))
 
 				for _, state := range ts.active.data {
-					if !userCanSeeDescriptor(&state.TableDescriptor, p.session.User) {
+					if !userCanSeeDescriptor(&state.TableDescriptor, p.session.User, memberOf) {
 						continue
 					}
 					if state.lease == nil || state.invalid {
@@ -335,6 +349,68 @@ CREATE TABLE crdb_internal.jobs (
 	},
 }
 
+var crdbInternalSchedulesTable = virtualSchemaTable{
+	schema: `
+CREATE TABLE crdb_internal.schedules (
+	id          INT,
+	name        STRING,
+	type        STRING,
+	username    STRING,
+	status      STRING,
+	created     TIMESTAMP,
+	recurrence  STRING,
+	next_run    TIMESTAMP,
+	last_run    TIMESTAMP,
+	last_job_id INT,
+	error       STRING
+);
+`,
+	populate: func(ctx context.Context, p *planner, addRow func(...parser.Datum) error) error {
+		rows, err := p.queryRows(ctx, `SELECT id, status, created, next_run, payload FROM system.schedules`)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range rows {
+			id, status, created, nextRun, bytes := r[0], r[1], r[2], r[3], r[4]
+			payload, err := jobs.UnmarshalSchedulePayload(bytes)
+			if err != nil {
+				return err
+			}
+			// A paused schedule keeps a next_run, but it is not going to run then.
+			if string(parser.MustBeDString(status)) != string(jobs.ScheduleStatusActive) {
+				nextRun = parser.DNull
+			}
+			lastRun := parser.DNull
+			if payload.LastRunMicros != 0 {
+				ts := time.Unix(0, payload.LastRunMicros*time.Microsecond.Nanoseconds())
+				lastRun = parser.MakeDTimestamp(ts, time.Microsecond)
+			}
+			lastJobID := parser.DNull
+			if payload.LastJobID != 0 {
+				lastJobID = parser.NewDInt(parser.DInt(payload.LastJobID))
+			}
+			if err := addRow(
+				id,
+				parser.NewDString(payload.Name),
+				parser.NewDString(payload.Typ()),
+				parser.NewDString(payload.Username),
+				status,
+				created,
+				parser.NewDString(payload.Recurrence),
+				nextRun,
+				lastRun,
+				lastJobID,
+				parser.NewDString(payload.Error),
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+}
+
 type stmtList []stmtKey
 
 func (s stmtList) Len() int {
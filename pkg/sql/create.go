@@ -167,10 +167,25 @@ func (n *createIndexNode) Start(ctx context.Context) error {
 		Unique:           n.n.Unique,
 		StoreColumnNames: n.n.Storing.ToStrings(),
 	}
-	if err := indexDesc.FillColumns(n.n.Columns); err != nil {
+	columns, exprCols, exprs, err := makeIndexExprColumns(
+		n.tableDesc, n.n.Columns, n.p.session.SearchPath)
+	if err != nil {
+		return err
+	}
+	if err := indexDesc.FillColumns(columns); err != nil {
+		return err
+	}
+	indexDesc.ColumnExprs = exprs
+	if indexDesc.Predicate, err = makeIndexPredicate(
+		n.tableDesc, n.n.Predicate, n.p.session.SearchPath); err != nil {
 		return err
 	}
 
+	// The hidden columns of the expression elements are added and backfilled
+	// before the index itself.
+	for _, col := range exprCols {
+		n.tableDesc.AddColumnMutation(col, sqlbase.DescriptorMutation_ADD)
+	}
 	mutationIdx := len(n.tableDesc.Mutations)
 	n.tableDesc.AddIndexMutation(indexDesc, sqlbase.DescriptorMutation_ADD)
 	if err := n.tableDesc.AllocateIDs(); err != nil {
@@ -230,6 +245,7 @@ type createUserNode struct {
 	p        *planner
 	n        *parser.CreateUser
 	password string
+	options  userOptionValues
 }
 
 // CreateUser creates a user.
@@ -252,13 +268,18 @@ func (p *planner) CreateUser(ctx context.Context, n *parser.CreateUser) (planNod
 
 	var resolvedPassword string
 	if n.HasPassword() {
-		resolvedPassword = *n.Password
+		resolvedPassword = *n.Options.Password
 		if resolvedPassword == "" {
 			return nil, security.ErrEmptyPassword
 		}
 	}
 
-	return &createUserNode{p: p, n: n, password: resolvedPassword}, nil
+	options, err := p.resolveUserOptions(n.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &createUserNode{p: p, n: n, password: resolvedPassword, options: options}, nil
 }
 
 const usernameHelp = "usernames are case insensitive, must start with a letter " +
@@ -299,6 +320,12 @@ func (n *createUserNode) Start(ctx context.Context) error {
 	}
 
 	internalExecutor := InternalExecutor{LeaseManager: n.p.LeaseMgr()}
+	if exists, err := roleExists(ctx, internalExecutor, n.p.txn, normalizedUsername); err != nil {
+		return err
+	} else if exists {
+		return errors.Errorf("a role named %s already exists", normalizedUsername)
+	}
+
 	rowsAffected, err := internalExecutor.ExecuteStatementInTransaction(
 		ctx,
 		"create-user",
@@ -318,7 +345,7 @@ func (n *createUserNode) Start(ctx context.Context) error {
 		)
 	}
 
-	return nil
+	return n.p.setUserOptions(ctx, normalizedUsername, n.options)
 }
 
 func (*createUserNode) Next(context.Context) (bool, error) { return false, nil }
@@ -750,6 +777,11 @@ func matchesIndex(
 	if len(cols) > len(idx.ColumnIDs) || (exact && len(cols) != len(idx.ColumnIDs)) {
 		return false
 	}
+	// A partial index does not contain every row, so it cannot be used to
+	// check foreign keys.
+	if idx.IsPartial() {
+		return false
+	}
 
 	for i := range cols {
 		if cols[i].ID != idx.ColumnIDs[i] {
@@ -1283,7 +1315,14 @@ func MakeTableDesc(
 				Name:             string(d.Name),
 				StoreColumnNames: d.Storing.ToStrings(),
 			}
-			if err := idx.FillColumns(d.Columns); err != nil {
+			columns, err := addIndexExprColumns(&desc, &idx, d.Columns, searchPath)
+			if err != nil {
+				return desc, err
+			}
+			if err := idx.FillColumns(columns); err != nil {
+				return desc, err
+			}
+			if idx.Predicate, err = makeIndexPredicate(&desc, d.Predicate, searchPath); err != nil {
 				return desc, err
 			}
 			if err := desc.AddIndex(idx, false); err != nil {
@@ -1298,7 +1337,17 @@ func MakeTableDesc(
 				Unique:           true,
 				StoreColumnNames: d.Storing.ToStrings(),
 			}
-			if err := idx.FillColumns(d.Columns); err != nil {
+			columns := d.Columns
+			if !d.PrimaryKey {
+				columns, err = addIndexExprColumns(&desc, &idx, d.Columns, searchPath)
+				if err != nil {
+					return desc, err
+				}
+			}
+			if err := idx.FillColumns(columns); err != nil {
+				return desc, err
+			}
+			if idx.Predicate, err = makeIndexPredicate(&desc, d.Predicate, searchPath); err != nil {
 				return desc, err
 			}
 			if err := desc.AddIndex(idx, d.PrimaryKey); err != nil {
@@ -1553,6 +1602,188 @@ func makeCheckConstraint(
 	return &sqlbase.TableDescriptor_CheckConstraint{Expr: parser.Serialize(d.Expr), Name: name}, nil
 }
 
+// makeIndexExprColumns turns the expression elements of an index definition
+// into hidden columns, whose values are computed from the
+// expressions whenever a row is written. It returns the index elements
+// rewritten to refer to those columns, the new columns, and the serialized
+// expressions paralleling the elements (empty for plain column elements). If
+// no element is an expression, the elements are returned unchanged and the
+// expressions are nil.
+func makeIndexExprColumns(
+	desc *sqlbase.TableDescriptor, elems parser.IndexElemList, searchPath parser.SearchPath,
+) (parser.IndexElemList, []sqlbase.ColumnDescriptor, []string, error) {
+	hasExpr := false
+	for _, elem := range elems {
+		if elem.Expr != nil {
+			hasExpr = true
+			break
+		}
+	}
+	if !hasExpr {
+		return elems, nil, nil, nil
+	}
+
+	newElems := make(parser.IndexElemList, len(elems))
+	exprStrs := make([]string, len(elems))
+	var cols []sqlbase.ColumnDescriptor
+	// usedNames holds the names allocated to the columns of this index, which
+	// are not added to desc yet.
+	usedNames := map[string]struct{}{}
+	for i, elem := range elems {
+		newElems[i] = elem
+		if elem.Expr == nil {
+			continue
+		}
+
+		typ, stored, err := sanitizeIndexExpr(
+			desc, elem.Expr, parser.TypeAny, "index expression", searchPath)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		switch typ {
+		case parser.TypeBool, parser.TypeInt, parser.TypeFloat, parser.TypeDecimal,
+			parser.TypeBytes, parser.TypeString, parser.TypeName, parser.TypeDate,
+			parser.TypeTimestamp, parser.TypeTimestampTZ, parser.TypeInterval,
+			parser.TypeUUID, parser.TypeOid, parser.TypeINet, parser.TypeTime, parser.TypeBitArray:
+		default:
+			if _, ok := typ.(parser.TCollatedString); !ok {
+				return nil, nil, nil, fmt.Errorf("index expression %s has unsupported type %s", elem.Expr, typ)
+			}
+		}
+
+		name := "crdb_idx_expr"
+		for j := 1; ; j++ {
+			_, _, err := desc.FindColumnByName(parser.Name(name))
+			if _, ok := usedNames[name]; err != nil && !ok {
+				break
+			}
+			name = fmt.Sprintf("crdb_idx_expr_%d", j)
+		}
+		usedNames[name] = struct{}{}
+		cols = append(cols, sqlbase.ColumnDescriptor{
+			Name:     name,
+			Type:     sqlbase.DatumTypeToColumnType(typ),
+			Nullable: true,
+			Hidden:   true,
+		})
+
+		newElems[i] = parser.IndexElem{Column: parser.Name(name), Direction: elem.Direction}
+		exprStrs[i] = stored
+	}
+	return newElems, cols, exprStrs, nil
+}
+
+// sanitizeIndexExpr checks that expr, which may refer to the columns of the
+// table, can be evaluated whenever a row is written: it must be pure, must
+// not contain aggregate or window functions and must have the expected type.
+// kind describes the expression in error messages. It returns the type of the
+// expression and its serialization with unqualified column names.
+func sanitizeIndexExpr(
+	desc *sqlbase.TableDescriptor,
+	expr parser.Expr,
+	expectedType parser.Type,
+	kind string,
+	searchPath parser.SearchPath,
+) (parser.Type, string, error) {
+	// Replace the column references with dummy nodes of the correct type to
+	// type check the expression, and with unqualified names to store it.
+	typedCols := func(expr parser.Expr) (err error, recurse bool, newExpr parser.Expr) {
+		col, ok, err := indexExprColumn(desc, expr)
+		if err != nil || !ok {
+			return err, err == nil, expr
+		}
+		return nil, false, dummyColumnItem{col.Type.ToDatumType()}
+	}
+	bareNames := func(expr parser.Expr) (err error, recurse bool, newExpr parser.Expr) {
+		col, ok, err := indexExprColumn(desc, expr)
+		if err != nil || !ok {
+			return err, err == nil, expr
+		}
+		return nil, false, parser.UnresolvedName{parser.Name(col.Name)}
+	}
+	typedColsExpr, err := parser.SimpleVisit(expr, typedCols)
+	if err != nil {
+		return nil, "", err
+	}
+	var p parser.Parser
+	if err := p.AssertNoAggregationOrWindowing(typedColsExpr, kind+"s", searchPath); err != nil {
+		return nil, "", err
+	}
+	typedExpr, err := sqlbase.SanitizeVarFreeExpr(typedColsExpr, expectedType, kind, searchPath)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := parser.SimpleVisit(typedExpr, func(expr parser.Expr) (error, bool, parser.Expr) {
+		if f, ok := expr.(*parser.FuncExpr); ok && f.IsImpure() {
+			return fmt.Errorf("impure function %s() not allowed in %s", f.Func, kind), false, expr
+		}
+		return nil, true, expr
+	}); err != nil {
+		return nil, "", err
+	}
+	stored, err := parser.SimpleVisit(expr, bareNames)
+	if err != nil {
+		return nil, "", err
+	}
+	return typedExpr.ResolvedType(), parser.Serialize(stored), nil
+}
+
+// makeIndexPredicate checks the predicate of a partial index and returns its
+// serialization, or an empty string if there is no predicate.
+func makeIndexPredicate(
+	desc *sqlbase.TableDescriptor, pred parser.Expr, searchPath parser.SearchPath,
+) (string, error) {
+	if pred == nil {
+		return "", nil
+	}
+	_, stored, err := sanitizeIndexExpr(desc, pred, parser.TypeBool, "index predicate", searchPath)
+	return stored, err
+}
+
+// addIndexExprColumns is used when creating a table to add the hidden columns
+// of the expression elements of an index definition to desc directly. It sets
+// the expressions of idx and returns the rewritten index elements.
+func addIndexExprColumns(
+	desc *sqlbase.TableDescriptor,
+	idx *sqlbase.IndexDescriptor,
+	elems parser.IndexElemList,
+	searchPath parser.SearchPath,
+) (parser.IndexElemList, error) {
+	columns, exprCols, exprs, err := makeIndexExprColumns(desc, elems, searchPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, col := range exprCols {
+		desc.AddColumn(col)
+	}
+	idx.ColumnExprs = exprs
+	return columns, nil
+}
+
+// indexExprColumn returns the active column referred to by expr if it is a
+// column reference.
+func indexExprColumn(
+	desc *sqlbase.TableDescriptor, expr parser.Expr,
+) (sqlbase.ColumnDescriptor, bool, error) {
+	vBase, ok := expr.(parser.VarName)
+	if !ok {
+		return sqlbase.ColumnDescriptor{}, false, nil
+	}
+	v, err := vBase.NormalizeVarName()
+	if err != nil {
+		return sqlbase.ColumnDescriptor{}, false, err
+	}
+	c, ok := v.(*parser.ColumnItem)
+	if !ok {
+		return sqlbase.ColumnDescriptor{}, false, nil
+	}
+	col, err := desc.FindActiveColumnByName(c.ColumnName)
+	if err != nil {
+		return sqlbase.ColumnDescriptor{}, false, err
+	}
+	return col, true, nil
+}
+
 // resolveViewDependencies looks up the tables included in a view's query
 // and adds metadata representing those dependencies to both the new view's
 // descriptor and the dependend-upon tables' descriptors. The modified table
@@ -263,6 +263,23 @@ func (p *planner) getSources(
 		if err != nil {
 			return planDataSource{}, err
 		}
+		for _, src := range sources[1:] {
+			if !isLateralSource(src) {
+				continue
+			}
+			// Some source may refer to the sources on its left. Combine
+			// the sources from left to right so that the sources on the
+			// left are already planned when a lateral source is planned;
+			// joinSource only uses an apply join if it actually refers to
+			// them.
+			for _, src := range sources[1:] {
+				left, err = p.joinSource(ctx, "CROSS JOIN", left, src, nil, scanVisibility)
+				if err != nil {
+					return planDataSource{}, err
+				}
+			}
+			return left, nil
+		}
 		right, err := p.getSources(ctx, sources[1:], scanVisibility)
 		if err != nil {
 			return planDataSource{}, err
@@ -271,6 +288,60 @@ func (p *planner) getSources(
 	}
 }
 
+// isLateralSource returns true if the given FROM source can refer to
+// the columns of the sources to its left. As in PostgreSQL, this is
+// the case for function calls and sources preceded by LATERAL.
+func isLateralSource(src parser.TableExpr) bool {
+	switch t := src.(type) {
+	case *parser.FuncExpr:
+		return true
+	case *parser.AliasedTableExpr:
+		return t.Lateral || isLateralSource(t.Expr)
+	case *parser.ParenTableExpr:
+		return isLateralSource(t.Expr)
+	}
+	return false
+}
+
+// joinSource joins the data source left with the source described by
+// right. If right is a lateral source that refers to the columns of
+// left, an applyJoinNode is used to evaluate right once per row of
+// left; otherwise, this is equivalent to makeJoin().
+func (p *planner) joinSource(
+	ctx context.Context,
+	astJoinType string,
+	left planDataSource,
+	right parser.TableExpr,
+	cond parser.JoinCond,
+	scanVisibility scanVisibility,
+) (planDataSource, error) {
+	if !isLateralSource(right) {
+		rightSrc, err := p.getDataSource(ctx, right, nil, scanVisibility)
+		if err != nil {
+			return planDataSource{}, err
+		}
+		return p.makeJoin(ctx, astJoinType, left, rightSrc, cond)
+	}
+
+	scope := &outerScope{info: left.info}
+	scopes := append(append([]*outerScope(nil), p.outerScopes...), scope)
+	planRight := func(ctx context.Context) (planDataSource, error) {
+		savedScopes := p.outerScopes
+		p.outerScopes = scopes
+		src, err := p.getDataSource(ctx, right, nil, scanVisibility)
+		p.outerScopes = savedScopes
+		return src, err
+	}
+	rightSrc, err := planRight(ctx)
+	if err != nil {
+		return planDataSource{}, err
+	}
+	if !scope.used {
+		return p.makeJoin(ctx, astJoinType, left, rightSrc, cond)
+	}
+	return p.makeApplyJoin(ctx, astJoinType, left, rightSrc, planRight, scopes, cond)
+}
+
 // getVirtualDataSource attempts to find a virtual table with the
 // given name.
 func (p *planner) getVirtualDataSource(
@@ -336,11 +407,7 @@ func (p *planner) getDataSource(
 		if err != nil {
 			return left, err
 		}
-		right, err := p.getDataSource(ctx, t.Right, nil, scanVisibility)
-		if err != nil {
-			return right, err
-		}
-		return p.makeJoin(ctx, t.Join, left, right, t.Cond)
+		return p.joinSource(ctx, t.Join, left, t.Right, t.Cond, scanVisibility)
 
 	case *parser.StatementSource:
 		plan, err := p.newPlan(ctx, t.Statement, nil)
@@ -522,7 +589,7 @@ func (p *planner) getPlanForDesc(
 
 	// This name designates a real table.
 	scan := p.Scan()
-	if err := scan.initTable(p, desc, hints, scanVisibility, wantedColumns); err != nil {
+	if err := scan.initTable(ctx, p, desc, hints, scanVisibility, wantedColumns); err != nil {
 		return planDataSource{}, err
 	}
 
@@ -552,9 +619,13 @@ func (p *planner) getViewPlan(
 	// the underlying tables as well would defeat the purpose of having separate
 	// SELECT privileges on the view, which is intended to allow for exposing
 	// some subset of a restricted table's data to less privileged users.
+	cols := sqlbase.ResultColumnsFromColDescs(desc.Columns)
 	if !p.skipSelectPrivilegeChecks {
 		if err := p.CheckPrivilege(desc, privilege.SELECT); err != nil {
-			return planDataSource{}, err
+			// The user may still read some of the columns of the view.
+			if err := p.denyColumns(desc, desc.Columns, cols, err); err != nil {
+				return planDataSource{}, err
+			}
 		}
 		p.skipSelectPrivilegeChecks = true
 		defer func() { p.skipSelectPrivilegeChecks = false }()
@@ -563,7 +634,7 @@ func (p *planner) getViewPlan(
 	// TODO(a-robinson): Support ORDER BY and LIMIT in views. Is it as simple as
 	// just passing the entire select here or will inserting an ORDER BY in the
 	// middle of a query plan break things?
-	plan, err := p.getSubqueryPlan(ctx, *tn, sel.Select, sqlbase.ResultColumnsFromColDescs(desc.Columns))
+	plan, err := p.getSubqueryPlan(ctx, *tn, sel.Select, cols)
 	if err != nil {
 		return plan, err
 	}
@@ -611,13 +682,17 @@ func (src *dataSourceInfo) expandStar(
 		return nil, nil, fmt.Errorf("cannot use %q without a FROM clause", v)
 	}
 
-	colSel := func(idx int) {
+	colSel := func(idx int) error {
 		col := src.sourceColumns[idx]
 		if !col.Hidden {
+			if col.Denied {
+				return newColumnPrivilegeError(col.Name)
+			}
 			ivar := ivarHelper.IndexedVar(idx)
 			columns = append(columns, sqlbase.ResultColumn{Name: col.Name, Typ: ivar.ResolvedType()})
 			exprs = append(exprs, ivar)
 		}
+		return nil
 	}
 
 	tableName := parser.TableName{}
@@ -626,7 +701,9 @@ func (src *dataSourceInfo) expandStar(
 	}
 	if tableName.Table() == "" {
 		for i := 0; i < len(src.sourceColumns); i++ {
-			colSel(i)
+			if err := colSel(i); err != nil {
+				return nil, nil, err
+			}
 		}
 	} else {
 		norm := tableName.NormalizedTableName()
@@ -641,7 +718,9 @@ func (src *dataSourceInfo) expandStar(
 			return nil, nil, fmt.Errorf("table %q not found", tableName.String())
 		}
 		for _, i := range colRange {
-			colSel(i)
+			if err := colSel(i); err != nil {
+				return nil, nil, err
+			}
 		}
 	}
 
@@ -668,7 +747,7 @@ func (sources multiSourceInfo) checkDatabaseName(tn parser.TableName) (parser.Ta
 			}
 		}
 		if !found {
-			return parser.TableName{}, fmt.Errorf("source name %q not found in FROM clause", tn.TableName)
+			return parser.TableName{}, newUnresolvedNameErrorf("source name %q not found in FROM clause", tn.TableName)
 		}
 		return tn, nil
 	}
@@ -685,7 +764,7 @@ func (sources multiSourceInfo) checkDatabaseName(tn parser.TableName) (parser.Ta
 		}
 	}
 	if !found {
-		return parser.TableName{}, fmt.Errorf("table %q not selected in FROM clause", &tn)
+		return parser.TableName{}, newUnresolvedNameErrorf("table %q not selected in FROM clause", &tn)
 	}
 	return tn, nil
 }
@@ -718,6 +797,21 @@ func (src *dataSourceInfo) checkDatabaseName(tn parser.TableName) (parser.TableN
 	return tn, nil
 }
 
+// unresolvedNameError is reported by findColumn() when a column
+// reference does not match any of the data sources. Name resolution
+// can then try to resolve the reference in an outer scope.
+type unresolvedNameError struct {
+	msg string
+}
+
+func newUnresolvedNameErrorf(format string, args ...interface{}) error {
+	return &unresolvedNameError{msg: fmt.Sprintf(format, args...)}
+}
+
+func (e *unresolvedNameError) Error() string {
+	return e.msg
+}
+
 // findColumn looks up the column specified by a ColumnItem. The
 // function returns the index of the source in the multiSourceInfo
 // array and the column index for the column array of that
@@ -788,12 +882,22 @@ func (sources multiSourceInfo) findColumn(
 	}
 
 	if colIdx == invalidColIdx {
-		return invalidSrcIdx, invalidColIdx, fmt.Errorf("column name %q not found", c)
+		return invalidSrcIdx, invalidColIdx, newUnresolvedNameErrorf("column name %q not found", c)
+	}
+	if col := sources[srcIdx].sourceColumns[colIdx]; col.Denied {
+		return invalidSrcIdx, invalidColIdx, newColumnPrivilegeError(col.Name)
 	}
 
 	return srcIdx, colIdx, nil
 }
 
+// newColumnPrivilegeError is reported when a query references a column
+// which the user does not have the privilege to read.
+func newColumnPrivilegeError(colName string) error {
+	return pgerror.NewErrorf(pgerror.CodeInsufficientPrivilegeError,
+		"user does not have SELECT privilege on column %q", colName)
+}
+
 // findTableAlias returns the first table alias providing the column
 // index given as argument. The index must be valid.
 func (src *dataSourceInfo) findTableAlias(colIdx int) (parser.TableName, bool) {
@@ -0,0 +1,527 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+//
+// This file implements the decorrelation of sub-queries, that is the
+// transformation of correlated sub-queries into joins. Correlated
+// sub-queries that cannot be decorrelated are planned and run once
+// per row of the surrounding query instead (see subquery.go).
+
+package sql
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+)
+
+// decorrelateSubqueries rewrites the correlated sub-queries of the
+// given SELECT clause that follow common patterns into joins with the
+// data source of the renderNode:
+//
+// - the conjuncts of the WHERE clause of the form EXISTS (...), NOT
+//   EXISTS (...) and x IN (...) become semi- and anti-joins;
+// - scalar sub-queries that compute a single aggregate, and whose
+//   correlation is expressed as equalities in their WHERE clause,
+//   become left outer joins with the aggregation grouped by the
+//   correlated columns.
+//
+// The SELECT clause is not modified; a copy with the rewritten WHERE
+// clause and render expressions is returned instead.
+func (r *renderNode) decorrelateSubqueries(
+	ctx context.Context, parsed *parser.SelectClause,
+) (*parser.SelectClause, error) {
+	hasSubquery := func(expr parser.Expr) bool {
+		_, ok := expr.(*parser.Subquery)
+		return ok
+	}
+	if parsed.Where == nil && !containsExpr(parsed.Exprs, hasSubquery) {
+		return parsed, nil
+	}
+	res := *parsed
+
+	if parsed.Where != nil {
+		var where parser.Expr
+		for _, c := range splitAndExprs(parsed.Where.Expr, nil) {
+			ok, err := r.decorrelateConjunct(ctx, c)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				where = mergeAndExprs(where, c)
+			}
+		}
+		res.Where = nil
+		if where != nil {
+			res.Where = &parser.Where{Type: parsed.Where.Type, Expr: where}
+		}
+	}
+
+	// Scalar sub-queries are only decorrelated when the SELECT does not
+	// aggregate, as the columns of the joined sub-query would otherwise
+	// have to be grouped too.
+	if r.planner.parser.IsAggregate(parsed, r.planner.session.SearchPath) {
+		return &res, nil
+	}
+	v := scalarDecorrelationVisitor{r: r, ctx: ctx}
+	if res.Where != nil {
+		v.path = v.path[:0]
+		expr, _ := parser.WalkExpr(&v, res.Where.Expr)
+		res.Where = &parser.Where{Type: res.Where.Type, Expr: expr}
+	}
+	res.Exprs = append(parser.SelectExprs(nil), res.Exprs...)
+	for i := range res.Exprs {
+		v.path = v.path[:0]
+		res.Exprs[i].Expr, _ = parser.WalkExpr(&v, res.Exprs[i].Expr)
+	}
+	if v.err != nil {
+		return nil, v.err
+	}
+	return &res, nil
+}
+
+// decorrelateConjunct attempts to transform the given conjunct of the
+// WHERE clause into a semi- or anti-join with the data source of the
+// renderNode. It returns false if the conjunct does not contain a
+// sub-query that can be decorrelated; the conjunct must then be
+// filtered as usual.
+func (r *renderNode) decorrelateConjunct(ctx context.Context, expr parser.Expr) (bool, error) {
+	typ := joinTypeSemi
+	var lhs parser.Expr
+	var sq *parser.Subquery
+	switch t := stripParens(expr).(type) {
+	case *parser.ExistsExpr:
+		sq, _ = stripParens(t.Subquery).(*parser.Subquery)
+	case *parser.NotExpr:
+		// NOT IN is not an anti-join: x NOT IN (...) is NULL, not true,
+		// when the sub-query returns NULLs.
+		if e, ok := stripParens(t.Expr).(*parser.ExistsExpr); ok {
+			typ = joinTypeAnti
+			sq, _ = stripParens(e.Subquery).(*parser.Subquery)
+		}
+	case *parser.ComparisonExpr:
+		if t.Operator == parser.In {
+			lhs = t.Left
+			sq, _ = stripParens(t.Right).(*parser.Subquery)
+		}
+	}
+	if sq == nil {
+		return false, nil
+	}
+
+	clause := r.decorrelatableClause(sq)
+	if clause == nil || clause.Where == nil {
+		return false, nil
+	}
+	cond := clause.Where.Expr
+	if lhs != nil {
+		if len(clause.Exprs) != 1 || isStarExpr(clause.Exprs[0].Expr) {
+			return false, nil
+		}
+		cond = &parser.AndExpr{
+			Left:  &parser.ComparisonExpr{Operator: parser.EQ, Left: lhs, Right: clause.Exprs[0].Expr},
+			Right: cond,
+		}
+	}
+
+	// Any error is reported again when the sub-query is planned as
+	// usual, so we simply give up on the decorrelation here. This is
+	// notably the case when the sub-query refers to a column name
+	// that exists on both sides of the join: the sub-query would use
+	// its own column, whereas the join predicate is ambiguous.
+	right, err := r.planner.getSources(ctx, clause.From.Tables, publicColumns)
+	if err != nil {
+		return false, nil
+	}
+	pred, _, err := r.planner.makeJoinPredicate(
+		ctx, r.source.info, right.info, &parser.OnJoinCond{Expr: cond})
+	if err != nil || !pred.refersToLeft() {
+		// An uncorrelated sub-query is better evaluated only once.
+		right.plan.Close(ctx)
+		return false, nil
+	}
+
+	r.source = r.planner.makeJoinNode(typ, r.source, right, pred, nil)
+	r.sourceInfo = multiSourceInfo{r.source.info}
+	return true, nil
+}
+
+// refersToLeft returns true if the predicate depends on the columns
+// of the left operand.
+func (p *joinPredicate) refersToLeft() bool {
+	if len(p.leftEqualityIndices) > 0 {
+		return true
+	}
+	for i := 0; i < p.numLeftCols; i++ {
+		if p.iVarHelper.IndexedVarUsed(p.numMergedEqualityColumns + i) {
+			return true
+		}
+	}
+	return false
+}
+
+// decorrelatableClause returns the SELECT clause of the given
+// sub-query if the number of its result rows only depends on its
+// FROM and WHERE clauses, and nil otherwise.
+func (r *renderNode) decorrelatableClause(sq *parser.Subquery) *parser.SelectClause {
+	var stmt parser.Statement = sq.Select
+	for {
+		switch t := stmt.(type) {
+		case *parser.ParenSelect:
+			stmt = t.Select
+			continue
+		case *parser.Select:
+			if t.Limit != nil {
+				return nil
+			}
+			stmt = t.Select
+			continue
+		case *parser.SelectClause:
+			if t.From == nil || t.Distinct || len(t.Window) > 0 || t.From.AsOf.Expr != nil ||
+				r.planner.parser.IsAggregate(t, r.planner.session.SearchPath) ||
+				r.containsGenerator(t.Exprs) {
+				return nil
+			}
+			return t
+		}
+		return nil
+	}
+}
+
+// containsGenerator returns true if any of the given render
+// expressions calls a set-returning function.
+func (r *renderNode) containsGenerator(exprs parser.SelectExprs) bool {
+	searchPath := r.planner.session.SearchPath
+	return containsExpr(exprs, func(expr parser.Expr) bool {
+		f, ok := expr.(*parser.FuncExpr)
+		if !ok {
+			return false
+		}
+		fd, err := f.Func.Resolve(searchPath)
+		if err != nil {
+			return true
+		}
+		_, ok = parser.Generators[fd.Name]
+		return ok
+	})
+}
+
+// scalarDecorrelationVisitor replaces the scalar sub-queries that can
+// be decorrelated by a reference to a column of a new data source,
+// which is left-joined with the data source of the renderNode.
+type scalarDecorrelationVisitor struct {
+	r    *renderNode
+	ctx  context.Context
+	err  error
+	path []parser.Expr
+}
+
+var _ parser.Visitor = &scalarDecorrelationVisitor{}
+
+func (v *scalarDecorrelationVisitor) VisitPre(expr parser.Expr) (recurse bool, newExpr parser.Expr) {
+	if v.err != nil {
+		return false, expr
+	}
+	sq, ok := expr.(*parser.Subquery)
+	if !ok {
+		v.path = append(v.path, expr)
+		return true, expr
+	}
+	// Only sub-queries used as a scalar value are candidates; the
+	// operands of EXISTS, IN, etc. are not.
+	for i := len(v.path) - 1; i >= 0; i-- {
+		switch t := v.path[i].(type) {
+		case *parser.ParenExpr:
+			continue
+		case *parser.ExistsExpr, *parser.ArrayFlatten:
+			return false, expr
+		case *parser.ComparisonExpr:
+			if t.Operator != parser.EQ && t.Operator != parser.NE && t.Operator != parser.LT &&
+				t.Operator != parser.LE && t.Operator != parser.GT && t.Operator != parser.GE {
+				return false, expr
+			}
+		}
+		break
+	}
+	newExpr, err := v.r.decorrelateScalar(v.ctx, sq)
+	if err != nil {
+		v.err = err
+		return false, expr
+	}
+	if newExpr == nil {
+		return false, expr
+	}
+	return false, newExpr
+}
+
+func (v *scalarDecorrelationVisitor) VisitPost(expr parser.Expr) parser.Expr {
+	v.path = v.path[:len(v.path)-1]
+	return expr
+}
+
+// decorrelateScalar attempts to transform a scalar sub-query of the
+// form:
+//
+//   (SELECT agg(...) FROM ... WHERE inner_conds AND inner = outer)
+//
+// into a left outer join of the data source of the renderNode with:
+//
+//   (SELECT inner, agg(...) FROM ... WHERE inner_conds GROUP BY inner)
+//
+// on outer = inner. It returns the expression that replaces the
+// sub-query, or nil if the sub-query cannot be transformed.
+func (r *renderNode) decorrelateScalar(
+	ctx context.Context, sq *parser.Subquery,
+) (parser.Expr, error) {
+	searchPath := r.planner.session.SearchPath
+	var clause *parser.SelectClause
+	if s, ok := sq.Select.(*parser.ParenSelect); ok && s.Select.OrderBy == nil && s.Select.Limit == nil {
+		clause, _ = s.Select.Select.(*parser.SelectClause)
+	}
+	if clause == nil || clause.From == nil || clause.Where == nil || clause.Distinct || len(clause.GroupBy) > 0 ||
+		clause.Having != nil || len(clause.Window) > 0 || clause.From.AsOf.Expr != nil ||
+		len(clause.Exprs) != 1 {
+		return nil, nil
+	}
+	agg, ok := clause.Exprs[0].Expr.(*parser.FuncExpr)
+	if !ok || agg.IsWindowFunctionApplication() {
+		return nil, nil
+	}
+	fd, err := agg.Func.Resolve(searchPath)
+	if err != nil {
+		return nil, nil
+	}
+	if _, ok := parser.Aggregates[fd.Name]; !ok {
+		return nil, nil
+	}
+
+	// Plan the FROM clause of the sub-query to determine which column
+	// references are correlated.
+	inner, err := r.planner.getSources(ctx, clause.From.Tables, publicColumns)
+	if err != nil {
+		return nil, nil
+	}
+	c := columnClassifier{inner: multiSourceInfo{inner.info}, outer: r.sourceInfo}
+	var innerConds parser.Expr
+	var innerKeys, outerKeys parser.Exprs
+	for _, e := range splitAndExprs(clause.Where.Expr, nil) {
+		hasInner, hasOuter := c.classify(e)
+		if !hasOuter {
+			innerConds = mergeAndExprs(innerConds, e)
+			continue
+		}
+		cmp, ok := stripParens(e).(*parser.ComparisonExpr)
+		if !ok || cmp.Operator != parser.EQ || !hasInner {
+			c.failed = true
+			break
+		}
+		leftInner, leftOuter := c.classify(cmp.Left)
+		rightInner, rightOuter := c.classify(cmp.Right)
+		switch {
+		case leftInner && !leftOuter && rightOuter && !rightInner:
+			innerKeys, outerKeys = append(innerKeys, cmp.Left), append(outerKeys, cmp.Right)
+		case rightInner && !rightOuter && leftOuter && !leftInner:
+			innerKeys, outerKeys = append(innerKeys, cmp.Right), append(outerKeys, cmp.Left)
+		default:
+			c.failed = true
+		}
+	}
+	if _, hasOuter := c.classify(agg); hasOuter {
+		c.failed = true
+	}
+	inner.plan.Close(ctx)
+	if c.failed || len(innerKeys) == 0 {
+		return nil, nil
+	}
+
+	alias := parser.Name(fmt.Sprintf("__sq%d", len(r.sourceInfo[0].sourceAliases)))
+	grouped := &parser.SelectClause{
+		From:    clause.From,
+		GroupBy: parser.GroupBy(innerKeys),
+	}
+	if innerConds != nil {
+		grouped.Where = &parser.Where{Type: clause.Where.Type, Expr: innerConds}
+	}
+	var on parser.Expr
+	for i := range innerKeys {
+		col := parser.Name(fmt.Sprintf("k%d", i+1))
+		grouped.Exprs = append(grouped.Exprs, parser.SelectExpr{Expr: innerKeys[i], As: col})
+		on = mergeAndExprs(on, &parser.ComparisonExpr{
+			Operator: parser.EQ,
+			Left:     outerKeys[i],
+			Right:    parser.UnresolvedName{alias, col},
+		})
+	}
+	grouped.Exprs = append(grouped.Exprs, parser.SelectExpr{Expr: agg, As: "v"})
+
+	right, err := r.planner.getDataSource(ctx, &parser.AliasedTableExpr{
+		Expr: &parser.Subquery{Select: &parser.ParenSelect{Select: &parser.Select{Select: grouped}}},
+		As:   parser.AliasClause{Alias: alias},
+	}, nil, publicColumns)
+	if err != nil {
+		return nil, err
+	}
+	src, err := r.planner.makeJoin(ctx, "LEFT JOIN", r.source, right, &parser.OnJoinCond{Expr: on})
+	if err != nil {
+		right.plan.Close(ctx)
+		return nil, err
+	}
+	// The columns of the sub-query are not visible to SELECT *.
+	numLeftCols := len(r.source.info.sourceColumns)
+	for i := numLeftCols; i < len(src.info.sourceColumns); i++ {
+		src.info.sourceColumns[i].Hidden = true
+	}
+	r.source = src
+	r.sourceInfo = multiSourceInfo{r.source.info}
+
+	var res parser.Expr = parser.UnresolvedName{alias, parser.Name("v")}
+	if fd.Name == "count" {
+		// COUNT over no rows is 0, not NULL.
+		res = &parser.CoalesceExpr{Name: "COALESCE", Exprs: parser.Exprs{res, parser.NewDInt(0)}}
+	}
+	return res, nil
+}
+
+// columnClassifier determines whether expressions refer to the
+// columns of the inner or the outer data sources of a sub-query.
+type columnClassifier struct {
+	inner, outer multiSourceInfo
+	hasInner     bool
+	hasOuter     bool
+	// failed is set when an expression cannot be classified, for
+	// example because it contains a sub-query.
+	failed bool
+}
+
+var _ parser.Visitor = &columnClassifier{}
+
+// classify returns whether the expression refers to columns of the
+// inner and outer data sources.
+func (c *columnClassifier) classify(expr parser.Expr) (hasInner, hasOuter bool) {
+	c.hasInner, c.hasOuter = false, false
+	parser.WalkExprConst(c, expr)
+	return c.hasInner, c.hasOuter
+}
+
+func (c *columnClassifier) VisitPre(expr parser.Expr) (recurse bool, newExpr parser.Expr) {
+	switch t := expr.(type) {
+	case *parser.FuncExpr:
+		if len(t.Exprs) == 1 && isStarExpr(t.Exprs[0]) {
+			// COUNT(*) does not refer to any column.
+			return false, expr
+		}
+	case parser.UnresolvedName:
+		vn, err := t.NormalizeVarName()
+		if err != nil {
+			c.failed = true
+			return false, expr
+		}
+		return c.VisitPre(vn)
+	case *parser.ColumnItem:
+		if _, _, err := c.inner.findColumn(t); err == nil {
+			c.hasInner = true
+		} else if _, ok := err.(*unresolvedNameError); !ok {
+			c.failed = true
+		} else if _, _, err := c.outer.findColumn(t); err == nil {
+			c.hasOuter = true
+		} else {
+			c.failed = true
+		}
+		return false, expr
+	case parser.UnqualifiedStar, *parser.AllColumnsSelector, *parser.IndexedVar, *parser.Subquery:
+		c.failed = true
+		return false, expr
+	}
+	return true, expr
+}
+
+func (*columnClassifier) VisitPost(expr parser.Expr) parser.Expr { return expr }
+
+// splitAndExprs appends the conjuncts of the given expression to
+// exprs.
+func splitAndExprs(expr parser.Expr, exprs parser.Exprs) parser.Exprs {
+	if t, ok := stripParens(expr).(*parser.AndExpr); ok {
+		return splitAndExprs(t.Right, splitAndExprs(t.Left, exprs))
+	}
+	return append(exprs, expr)
+}
+
+// mergeAndExprs combines two expressions with AND; either can be nil.
+func mergeAndExprs(left, right parser.Expr) parser.Expr {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	return &parser.AndExpr{Left: left, Right: right}
+}
+
+// stripParens removes the parentheses around an expression.
+func stripParens(expr parser.Expr) parser.Expr {
+	for {
+		p, ok := expr.(*parser.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = p.Expr
+	}
+}
+
+// isStarExpr returns true if the render expression is a star.
+func isStarExpr(expr parser.Expr) bool {
+	if n, ok := expr.(parser.UnresolvedName); ok {
+		if vn, err := n.NormalizeVarName(); err == nil {
+			expr = vn
+		}
+	}
+	switch expr.(type) {
+	case parser.UnqualifiedStar, *parser.AllColumnsSelector:
+		return true
+	}
+	return false
+}
+
+// containsExpr returns true if any of the render expressions contains
+// a sub-expression for which fn returns true. Sub-queries are not
+// searched.
+func containsExpr(exprs parser.SelectExprs, fn func(parser.Expr) bool) bool {
+	v := exprFinder{fn: fn}
+	for _, e := range exprs {
+		parser.WalkExprConst(&v, e.Expr)
+		if v.found {
+			return true
+		}
+	}
+	return false
+}
+
+type exprFinder struct {
+	fn    func(parser.Expr) bool
+	found bool
+}
+
+var _ parser.Visitor = &exprFinder{}
+
+func (v *exprFinder) VisitPre(expr parser.Expr) (recurse bool, newExpr parser.Expr) {
+	if v.found || v.fn(expr) {
+		v.found = true
+		return false, expr
+	}
+	_, isSubquery := expr.(*parser.Subquery)
+	return !isSubquery, expr
+}
+
+func (*exprFinder) VisitPost(expr parser.Expr) parser.Expr { return expr }
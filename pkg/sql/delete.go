@@ -58,6 +58,11 @@ func (p *planner) Delete(
 		return nil, err
 	}
 
+	where, err := p.rowLevelSecurityWhere(ctx, en.tableDesc, sqlbase.TableDescriptor_Policy_DELETE, n.Where)
+	if err != nil {
+		return nil, err
+	}
+
 	var requestedCols []sqlbase.ColumnDescriptor
 	if _, retExprs := n.Returning.(*parser.ReturningExprs); retExprs {
 		// TODO(dan): This could be made tighter, just the rows needed for RETURNING
@@ -73,7 +78,7 @@ func (p *planner) Delete(
 	if err != nil {
 		return nil, err
 	}
-	tw := tableDeleter{rd: rd, autoCommit: p.autoCommit}
+	tw := tableDeleter{rd: rd, autoCommit: p.autoCommit, db: p.ExecCfg().DB}
 
 	// TODO(knz): Until we split the creation of the node from Start()
 	// for the SelectClause too, we cannot cache this. This is because
@@ -83,7 +88,7 @@ func (p *planner) Delete(
 	rows, err := p.SelectClause(ctx, &parser.SelectClause{
 		Exprs: sqlbase.ColumnsSelectors(rd.FetchCols),
 		From:  &parser.From{Tables: []parser.TableExpr{n.Table}},
-		Where: n.Where,
+		Where: where,
 	}, nil, nil, nil, publicAndNonPublicColumns)
 	if err != nil {
 		return nil, err
@@ -132,6 +137,7 @@ func (d *deleteNode) Start(ctx context.Context) error {
 
 func (d *deleteNode) Close(ctx context.Context) {
 	d.run.rows.Close(ctx)
+	d.tw.close(ctx)
 }
 
 func (d *deleteNode) FastPathResults() (int, bool) {
@@ -0,0 +1,125 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql_test
+
+import (
+	"bytes"
+	"sync/atomic"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/storagebase"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+// TestDeleteRangeTombstones tests that the statements deleting spans of rows
+// outside of explicit transactions use range tombstones.
+func TestDeleteRangeTombstones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// This filter counts the range tombstones written over user table data.
+	var tombstones uint64
+	filter := func(filterArgs storagebase.FilterArgs) *roachpb.Error {
+		if bytes.Compare(filterArgs.Req.Header().Key, keys.UserTableDataMin) >= 0 {
+			if req, ok := filterArgs.Req.(*roachpb.DeleteRangeRequest); ok && req.UseRangeTombstone {
+				atomic.AddUint64(&tombstones, 1)
+			}
+		}
+		return nil
+	}
+
+	s, conn, kvDB := serverutils.StartServer(t, base.TestServerArgs{
+		Knobs: base.TestingKnobs{Store: &storage.StoreTestingKnobs{
+			TestingEvalFilter: filter,
+		}},
+	})
+	defer s.Stopper().Stop(context.TODO())
+	sqlDB := sqlutils.MakeSQLRunner(t, conn)
+	sqlDB.Exec(`CREATE DATABASE d`)
+	sqlDB.Exec(`CREATE TABLE d.kv (k INT PRIMARY KEY, v INT)`)
+	sqlDB.Exec(`CREATE TABLE d.other (k INT PRIMARY KEY)`)
+	insert := func() {
+		sqlDB.Exec(`INSERT INTO d.kv SELECT generate_series(1, 10), 1`)
+	}
+	expectTombstones := func(expected bool) {
+		if n := atomic.SwapUint64(&tombstones, 0); (n > 0) != expected {
+			t.Errorf("expected range tombstones: %t; got %d", expected, n)
+		}
+	}
+	insert()
+
+	// A span of rows is deleted using a range tombstone, and the number of rows
+	// deleted is still known.
+	atomic.StoreUint64(&tombstones, 0)
+	sqlDB.ExecRowsAffected(6, `DELETE FROM d.kv WHERE k >= 5`)
+	expectTombstones(true)
+	sqlDB.CheckQueryResults(`SELECT k FROM d.kv WHERE k > 3`, [][]string{{"4"}})
+
+	// A single row isn't worth a range tombstone.
+	sqlDB.ExecRowsAffected(1, `DELETE FROM d.kv WHERE k = 1`)
+	expectTombstones(false)
+
+	// Range tombstones can't be written within an explicit transaction, whose
+	// deletions must be rolled back along with it.
+	tx, err := conn.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(`DELETE FROM d.kv`); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	expectTombstones(false)
+	sqlDB.CheckQueryResults(`SELECT COUNT(*) FROM d.kv`, [][]string{{"3"}})
+
+	// Deleting the rows again doesn't count the rows deleted previously.
+	sqlDB.ExecRowsAffected(3, `DELETE FROM d.kv WHERE k > 0`)
+	expectTombstones(true)
+
+	// Truncating a single table uses a range tombstone, but truncating several
+	// ones needs a transaction.
+	insert()
+	sqlDB.Exec(`TRUNCATE d.kv`)
+	expectTombstones(true)
+	sqlDB.CheckQueryResults(`SELECT COUNT(*) FROM d.kv`, [][]string{{"0"}})
+	insert()
+	sqlDB.Exec(`INSERT INTO d.other VALUES (1)`)
+	sqlDB.Exec(`TRUNCATE d.kv, d.other`)
+	expectTombstones(false)
+	sqlDB.CheckQueryResults(`SELECT COUNT(*) FROM d.kv`, [][]string{{"0"}})
+	sqlDB.CheckQueryResults(`SELECT COUNT(*) FROM d.other`, [][]string{{"0"}})
+
+	// The data of a dropped table is deleted using a range tombstone.
+	insert()
+	tableDesc := sqlbase.GetTableDescriptor(kvDB, "d", "kv")
+	sqlDB.Exec(`DROP TABLE d.kv`)
+	expectTombstones(true)
+	tableSpan := tableDesc.TableSpan()
+	if kvs, err := kvDB.Scan(context.TODO(), tableSpan.Key, tableSpan.EndKey, 0); err != nil {
+		t.Fatal(err)
+	} else if len(kvs) != 0 {
+		t.Fatalf("expected the data of the dropped table to be deleted; got %d keys", len(kvs))
+	}
+}
@@ -130,6 +130,10 @@ func (v *distSQLExprCheckVisitor) VisitPre(expr parser.Expr) (recurse bool, newE
 		v.err = newQueryNotSupportedError("subqueries not supported yet")
 		return false, expr
 
+	case *outerVar:
+		v.err = newQueryNotSupportedError("outer column references not supported")
+		return false, expr
+
 	case *parser.FuncExpr:
 		if t.IsDistSQLBlacklist() {
 			v.err = newQueryNotSupportedErrorf("function %s cannot be executed with distsql", t)
@@ -400,6 +404,24 @@ type spanPartition struct {
 	spans roachpb.Spans
 }
 
+// checkNodeHealth returns an error if the node with the given ID and address is
+// known to be unhealthy, in which case no processors should be planned on it.
+func (dsp *distSQLPlanner) checkNodeHealth(
+	ctx context.Context, nodeID roachpb.NodeID, addr string,
+) error {
+	var err error
+	if dsp.testingKnobs.OverrideHealthCheck != nil {
+		err = dsp.testingKnobs.OverrideHealthCheck(nodeID, addr)
+	} else {
+		err = dsp.rpcContext.ConnHealth(addr)
+	}
+	if err != nil && err != rpc.ErrNotConnected && err != rpc.ErrNotHeartbeated {
+		log.VEventf(ctx, 1, "marking node %d as unhealthy for this plan: %v", nodeID, err)
+		return err
+	}
+	return nil
+}
+
 // partitionSpans finds out which nodes are owners for ranges touching the given
 // spans, and splits the spans according to owning nodes. The result is a set of
 // spanPartitions (one for each relevant node), which form a partitioning of the
@@ -467,18 +489,11 @@ func (dsp *distSQLPlanner) partitionSpans(
 				addr, inAddrMap := planCtx.nodeAddresses[nodeID]
 				if !inAddrMap {
 					addr = replInfo.NodeDesc.Address.String()
-					var err error
-					if dsp.testingKnobs.OverrideHealthCheck != nil {
-						err = dsp.testingKnobs.OverrideHealthCheck(replInfo.NodeDesc.NodeID, addr)
-					} else {
-						err = dsp.rpcContext.ConnHealth(addr)
-					}
-					if err != nil && err != rpc.ErrNotConnected && err != rpc.ErrNotHeartbeated {
+					if err := dsp.checkNodeHealth(ctx, nodeID, addr); err != nil {
 						// This host is known to be unhealthy. Don't use it (use the gateway
 						// instead). Note: this can never happen for our nodeID (which
 						// always has its address in the nodeMap).
 						addr = ""
-						log.VEventf(ctx, 1, "marking node %d as unhealthy for this plan: %v", nodeID, err)
 					}
 					planCtx.nodeAddresses[nodeID] = addr
 				}
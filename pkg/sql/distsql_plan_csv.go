@@ -0,0 +1,408 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+//
+
+package sql
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
+	"github.com/cockroachdb/cockroach/pkg/sql/mon"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+// DistLoader uses DistSQL to convert external data formats (CSV, etc) into
+// sstables of our mvcc-format key values and to ingest them.
+type DistLoader struct {
+	distSQLPlanner *distSQLPlanner
+}
+
+// csvOutputTypes are the types of the rows produced by the ReadCSV processors:
+// the key and value of a KV.
+var csvOutputTypes = []sqlbase.ColumnType{
+	{SemanticType: sqlbase.ColumnType_BYTES},
+	{SemanticType: sqlbase.ColumnType_BYTES},
+}
+
+// sstWriterOutputTypes are the types of the rows produced by the SSTWriter
+// processors: the number of rows, index entries and bytes ingested.
+var sstWriterOutputTypes = []sqlbase.ColumnType{
+	{SemanticType: sqlbase.ColumnType_INT},
+	{SemanticType: sqlbase.ColumnType_INT},
+	{SemanticType: sqlbase.ColumnType_INT},
+}
+
+// csvWriterOutputTypes are the types of the rows produced by the CSVWriter
+// processors: the name, number of rows and size of a written file.
+var csvWriterOutputTypes = []sqlbase.ColumnType{
+	{SemanticType: sqlbase.ColumnType_STRING},
+	{SemanticType: sqlbase.ColumnType_INT},
+	{SemanticType: sqlbase.ColumnType_INT},
+}
+
+// ExportFilePatternPart is the placeholder in the NamePattern of a
+// CSVWriterSpec that is replaced with a name unique to each file.
+const ExportFilePatternPart = "%part%"
+
+// SampleCSV reads the CSV files at the given URIs, spread among the given
+// nodes, and returns a random sample of the keys of the KVs that the rows of
+// the files are converted into.
+//
+// spec is used as the template of the ReadCSV processors; its SampleSize must
+// be non-zero. See planReadCSV for how progress is reported.
+func (l *DistLoader) SampleCSV(
+	ctx context.Context,
+	db *client.DB,
+	evalCtx parser.EvalContext,
+	nodes []roachpb.NodeDescriptor,
+	uris []string,
+	spec distsqlrun.ReadCSVSpec,
+) ([]roachpb.Key, error) {
+	dsp := l.distSQLPlanner
+	txn := client.NewTxn(db)
+	planCtx := dsp.NewPlanningCtx(ctx, txn)
+
+	p, _ := l.planReadCSV(&planCtx, nodes, uris, spec)
+	dsp.FinalizePlan(&planCtx, &p)
+
+	var keys []roachpb.Key
+	err := l.run(&planCtx, txn, &p, evalCtx, func(rows *sqlbase.RowContainer) error {
+		keys = make([]roachpb.Key, rows.Len())
+		for i := range keys {
+			keys[i] = roachpb.Key(*rows.At(i)[0].(*parser.DBytes))
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// LoadCSV reads the CSV files at the given URIs, spread among the given nodes,
+// converts their rows into KVs and ingests them into the table's key span.
+//
+// The KVs are routed by key to SSTWriter processors, which sort them into
+// sstables and ingest those with AddSSTable. The table's span is divided at
+// splitKeys, which should be the (sorted) boundaries of the table's ranges,
+// and the resulting spans are distributed among the nodes, so that each
+// sstable is ingested into a single range.
+//
+// readSpec and writeSpec are used as the templates of the ReadCSV and SSTWriter
+// processors. See planReadCSV for how progress is reported.
+func (l *DistLoader) LoadCSV(
+	ctx context.Context,
+	db *client.DB,
+	evalCtx parser.EvalContext,
+	nodes []roachpb.NodeDescriptor,
+	uris []string,
+	readSpec distsqlrun.ReadCSVSpec,
+	writeSpec distsqlrun.SSTWriterSpec,
+	splitKeys []roachpb.Key,
+) (roachpb.BulkOpSummary, error) {
+	dsp := l.distSQLPlanner
+	txn := client.NewTxn(db)
+	planCtx := dsp.NewPlanningCtx(ctx, txn)
+
+	p, nodeIDs := l.planReadCSV(&planCtx, nodes, uris, readSpec)
+	readers := append([]distsqlplan.ProcessorIdx(nil), p.ResultRouters...)
+
+	// Divide the table's span at the split keys; any split key outside of it, or
+	// equal to the previous one, is ignored.
+	tableSpan := readSpec.TableDesc.TableSpan()
+	var spans []roachpb.Span
+	start := tableSpan.Key
+	for _, k := range splitKeys {
+		if bytes.Compare(k, start) <= 0 || bytes.Compare(k, tableSpan.EndKey) >= 0 {
+			continue
+		}
+		spans = append(spans, roachpb.Span{Key: start, EndKey: k})
+		start = k
+	}
+	spans = append(spans, roachpb.Span{Key: start, EndKey: tableSpan.EndKey})
+
+	// Assign the spans to the SSTWriter processors in a round-robin fashion;
+	// neighboring spans are thus handled by different nodes.
+	numWriters := len(nodeIDs)
+	if numWriters > len(spans) {
+		numWriters = len(spans)
+	}
+	writerSpecs := make([]*distsqlrun.SSTWriterSpec, numWriters)
+	routerSpec := distsqlrun.OutputRouterSpec_RangeRouterSpec{
+		Encodings: []distsqlrun.OutputRouterSpec_RangeRouterSpec_ColumnEncoding{
+			{Column: 0, Encoding: sqlbase.DatumEncoding_ASCENDING_KEY},
+		},
+	}
+	for i, span := range spans {
+		w := i % numWriters
+		if writerSpecs[w] == nil {
+			s := writeSpec
+			s.Spans = nil
+			s.Progress.Slot = writeSpec.Progress.Slot + int32(w)
+			writerSpecs[w] = &s
+		}
+		writerSpecs[w].Spans = append(writerSpecs[w].Spans, span)
+		// The router encodes the key column the same way as these bounds.
+		routerSpec.Spans = append(routerSpec.Spans, distsqlrun.OutputRouterSpec_RangeRouterSpec_Span{
+			Start:  encoding.EncodeBytesAscending(nil, span.Key),
+			End:    encoding.EncodeBytesAscending(nil, span.EndKey),
+			Stream: int32(w),
+		})
+	}
+	for _, s := range writerSpecs {
+		s.Progress.Contribution = writeSpec.Progress.Contribution * float32(len(s.Spans)) / float32(len(spans))
+	}
+
+	for _, r := range readers {
+		p.Processors[r].Spec.Output[0] = distsqlrun.OutputRouterSpec{
+			Type:            distsqlrun.OutputRouterSpec_BY_RANGE,
+			RangeRouterSpec: routerSpec,
+		}
+	}
+	stageID := p.NewStageID()
+	p.ResultRouters = p.ResultRouters[:0]
+	for i, s := range writerSpecs {
+		pIdx := p.AddProcessor(distsqlplan.Processor{
+			Node: nodeIDs[i],
+			Spec: distsqlrun.ProcessorSpec{
+				Input: []distsqlrun.InputSyncSpec{{
+					Type:        distsqlrun.InputSyncSpec_UNORDERED,
+					ColumnTypes: csvOutputTypes,
+				}},
+				Core:    distsqlrun.ProcessorCoreUnion{SSTWriter: s},
+				Output:  []distsqlrun.OutputRouterSpec{{Type: distsqlrun.OutputRouterSpec_PASS_THROUGH}},
+				StageID: stageID,
+			},
+		})
+		p.ResultRouters = append(p.ResultRouters, pIdx)
+	}
+	// Connect every reader to every writer. The streams of a router must be
+	// added in the order of the router's slots.
+	for _, r := range readers {
+		for w, pIdx := range p.ResultRouters {
+			p.Streams = append(p.Streams, distsqlplan.Stream{
+				SourceProcessor:  r,
+				SourceRouterSlot: w,
+				DestProcessor:    pIdx,
+				DestInput:        0,
+			})
+		}
+	}
+	p.ResultTypes = sstWriterOutputTypes
+	p.planToStreamColMap = identityMap(p.planToStreamColMap, len(sstWriterOutputTypes))
+	dsp.FinalizePlan(&planCtx, &p)
+
+	var res roachpb.BulkOpSummary
+	err := l.run(&planCtx, txn, &p, evalCtx, func(rows *sqlbase.RowContainer) error {
+		for i := 0; i < rows.Len(); i++ {
+			row := rows.At(i)
+			res.Rows += int64(parser.MustBeDInt(row[0]))
+			res.IndexEntries += int64(parser.MustBeDInt(row[1]))
+			res.DataSize += int64(parser.MustBeDInt(row[2]))
+		}
+		return nil
+	})
+	return res, err
+}
+
+// planReadCSV creates a plan with a stage of ReadCSV processors on the given
+// nodes that aren't known to be unhealthy (the gateway is always used), and
+// returns the IDs of these nodes. The files are assigned to the processors in a
+// round-robin fashion and are identified by their index in uris.
+//
+// The processors report the progress of their share of the work in the details
+// of the job given by spec.Progress: processor i uses slot spec.Progress.Slot+i,
+// and the contributions of all processors add up to
+// spec.Progress.Contribution.
+func (l *DistLoader) planReadCSV(
+	planCtx *planningCtx, nodes []roachpb.NodeDescriptor, uris []string, spec distsqlrun.ReadCSVSpec,
+) (physicalPlan, []roachpb.NodeID) {
+	dsp := l.distSQLPlanner
+	nodeIDs := []roachpb.NodeID{dsp.nodeDesc.NodeID}
+	for _, node := range nodes {
+		if _, ok := planCtx.nodeAddresses[node.NodeID]; ok {
+			continue
+		}
+		addr := node.Address.String()
+		if err := dsp.checkNodeHealth(planCtx.ctx, node.NodeID, addr); err != nil {
+			continue
+		}
+		planCtx.nodeAddresses[node.NodeID] = addr
+		nodeIDs = append(nodeIDs, node.NodeID)
+	}
+	// Make the plan deterministic.
+	sort.Slice(nodeIDs, func(i, j int) bool { return nodeIDs[i] < nodeIDs[j] })
+
+	var specs []*distsqlrun.ReadCSVSpec
+	for i, uri := range uris {
+		n := i % len(nodeIDs)
+		if n == len(specs) {
+			s := spec
+			s.URI = make(map[int32]string)
+			s.Progress.Slot = spec.Progress.Slot + int32(n)
+			specs = append(specs, &s)
+		}
+		specs[n].URI[int32(i)] = uri
+	}
+
+	p := physicalPlan{}
+	stageID := p.NewStageID()
+	for i, s := range specs {
+		s.Progress.Contribution = spec.Progress.Contribution * float32(len(s.URI)) / float32(len(uris))
+		pIdx := p.AddProcessor(distsqlplan.Processor{
+			Node: nodeIDs[i],
+			Spec: distsqlrun.ProcessorSpec{
+				Core:    distsqlrun.ProcessorCoreUnion{ReadCSV: s},
+				Output:  []distsqlrun.OutputRouterSpec{{Type: distsqlrun.OutputRouterSpec_PASS_THROUGH}},
+				StageID: stageID,
+			},
+		})
+		p.ResultRouters = append(p.ResultRouters, pIdx)
+	}
+	p.ResultTypes = csvOutputTypes
+	p.planToStreamColMap = identityMap(nil, len(csvOutputTypes))
+	return p, nodeIDs
+}
+
+// run runs a finalized plan and calls fn with the rows it produced.
+func (l *DistLoader) run(
+	planCtx *planningCtx,
+	txn *client.Txn,
+	p *physicalPlan,
+	evalCtx parser.EvalContext,
+	fn func(*sqlbase.RowContainer) error,
+) error {
+	ctx := planCtx.ctx
+	// The results (sampled keys or per-span counts) are small, so they are not
+	// accounted against a session's memory.
+	monitor := mon.MakeUnlimitedMonitor(ctx, "dist-loader", nil, nil, math.MaxInt64)
+	defer monitor.Stop(ctx)
+	rows := sqlbase.NewRowContainer(
+		monitor.MakeBoundAccount(), sqlbase.ColTypeInfoFromColTypes(p.ResultTypes), 0,
+	)
+	defer rows.Close(ctx)
+	recv, err := makeDistSQLReceiver(
+		ctx,
+		rows,
+		nil, /* rangeCache */
+		nil, /* leaseCache */
+		nil, /* txn - the flow does not run wholly in a txn */
+		nil, /* updateClock - the flow does not generate errors with time signal */
+	)
+	if err != nil {
+		return err
+	}
+	if err := l.distSQLPlanner.Run(planCtx, txn, p, &recv, evalCtx); err != nil {
+		return err
+	}
+	if recv.err != nil {
+		return recv.err
+	}
+	return fn(rows)
+}
+
+// ExportCSV implements the PlanHookState interface. It runs query with DistSQL
+// in the planner's transaction and writes its results to CSV files with a
+// CSVWriter processor after each of the processors producing them, so every
+// node writes its share of the rows. It returns one row of (filename, rows,
+// bytes) per file written.
+//
+// spec is used as the template of the CSVWriter processors; the index of the
+// processor is added to ExportFilePatternPart in its NamePattern so that the
+// file names are unique.
+func (p *planner) ExportCSV(
+	ctx context.Context, query *parser.Select, spec distsqlrun.CSVWriterSpec,
+) ([]parser.Datums, error) {
+	plan, err := p.makePlan(ctx, Statement{AST: query})
+	if err != nil {
+		return nil, err
+	}
+	defer plan.Close(ctx)
+
+	dsp := p.session.distSQLPlanner
+	if _, err := dsp.CheckSupport(plan); err != nil {
+		return nil, err
+	}
+	planCtx := dsp.NewPlanningCtx(ctx, p.txn)
+	phys, err := dsp.createPlanForNode(&planCtx, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	// The files have the columns of the query, in order. The streams are never
+	// merged, so their ordering is not needed.
+	phys.MergeOrdering = distsqlrun.Ordering{}
+	cols := make([]uint32, len(phys.planToStreamColMap))
+	for i, c := range phys.planToStreamColMap {
+		cols[i] = uint32(c)
+	}
+	phys.AddProjection(cols)
+
+	phys.AddNoGroupingStage(
+		distsqlrun.ProcessorCoreUnion{CSVWriter: &spec},
+		distsqlrun.PostProcessSpec{},
+		csvWriterOutputTypes,
+		distsqlrun.Ordering{},
+	)
+	for i, r := range phys.ResultRouters {
+		s := spec
+		s.NamePattern = strings.Replace(
+			spec.NamePattern, ExportFilePatternPart, fmt.Sprintf("%d.%s", i, ExportFilePatternPart), -1,
+		)
+		phys.Processors[r].Spec.Core.CSVWriter = &s
+	}
+	phys.planToStreamColMap = identityMap(phys.planToStreamColMap, len(csvWriterOutputTypes))
+	dsp.FinalizePlan(&planCtx, &phys)
+
+	rows := sqlbase.NewRowContainer(
+		p.evalCtx.Mon.MakeBoundAccount(), sqlbase.ColTypeInfoFromColTypes(phys.ResultTypes), 0,
+	)
+	defer rows.Close(ctx)
+	execCfg := p.ExecCfg()
+	recv, err := makeDistSQLReceiver(
+		ctx,
+		rows,
+		execCfg.RangeDescriptorCache,
+		execCfg.LeaseHolderCache,
+		p.txn,
+		func(ts hlc.Timestamp) {
+			_ = execCfg.Clock.Update(ts)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := dsp.Run(&planCtx, p.txn, &phys, &recv, p.evalCtx); err != nil {
+		return nil, err
+	}
+	if recv.err != nil {
+		return nil, recv.err
+	}
+
+	res := make([]parser.Datums, rows.Len())
+	for i := range res {
+		res[i] = append(parser.Datums(nil), rows.At(i)...)
+	}
+	return res, nil
+}
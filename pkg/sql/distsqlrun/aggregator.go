@@ -101,7 +101,7 @@ type aggregator struct {
 	out procOutputHelper
 }
 
-var _ processor = &aggregator{}
+var _ Processor = &aggregator{}
 
 func newAggregator(
 	flowCtx *FlowCtx,
@@ -37,7 +37,7 @@ type columnBackfiller struct {
 	updateExprs []parser.TypedExpr
 }
 
-var _ processor = &columnBackfiller{}
+var _ Processor = &columnBackfiller{}
 var _ chunkBackfiller = &columnBackfiller{}
 
 // ColumnMutationFilter is a filter that allows mutations that add or drop
@@ -79,16 +79,22 @@ func (cb *columnBackfiller) init() error {
 	// not null constraint.
 	// TODO(jordan): detect this earlier. #14455
 	addingNonNullableColumn := false
+	// The hidden columns of expression indexes are computed by the rowUpdater
+	// and need to be backfilled as well.
+	addingIndexExprColumn := false
 	if len(desc.Mutations) > 0 {
 		for _, m := range desc.Mutations {
 			if ColumnMutationFilter(m) {
 				switch m.Direction {
 				case sqlbase.DescriptorMutation_ADD:
-					desc := *m.GetColumn()
-					cb.added = append(cb.added, desc)
-					if desc.DefaultExpr == nil && !desc.Nullable {
+					col := *m.GetColumn()
+					cb.added = append(cb.added, col)
+					if col.DefaultExpr == nil && !col.Nullable {
 						addingNonNullableColumn = true
 					}
+					if desc.IsIndexExprColumn(col.ID) {
+						addingIndexExprColumn = true
+					}
 				case sqlbase.DescriptorMutation_DROP:
 					cb.dropped = append(cb.dropped, *m.GetColumn())
 				}
@@ -101,7 +107,7 @@ func (cb *columnBackfiller) init() error {
 	}
 
 	cb.updateCols = append(cb.added, cb.dropped...)
-	if len(cb.dropped) > 0 || addingNonNullableColumn || len(defaultExprs) > 0 {
+	if len(cb.dropped) > 0 || addingNonNullableColumn || addingIndexExprColumn || len(defaultExprs) > 0 {
 		// Populate default values.
 		cb.updateExprs = make([]parser.TypedExpr, len(cb.updateCols))
 		for j := range cb.added {
@@ -173,6 +179,7 @@ func (cb *columnBackfiller) runChunk(
 		if err != nil {
 			return err
 		}
+		defer ru.Close(ctx)
 
 		// TODO(dan): This check is an unfortunate bleeding of the internals of
 		// rowUpdater. Extract the sql row to k/v mapping logic out into something
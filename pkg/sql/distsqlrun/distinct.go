@@ -38,7 +38,7 @@ type distinct struct {
 	out          procOutputHelper
 }
 
-var _ processor = &distinct{}
+var _ Processor = &distinct{}
 
 func newDistinct(
 	flowCtx *FlowCtx, spec *DistinctSpec, input RowSource, post *PostProcessSpec, output RowReceiver,
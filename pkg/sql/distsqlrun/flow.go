@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
@@ -64,12 +65,42 @@ type FlowCtx struct {
 	// TxnCoordSender. Used via setupTxn() for running requests on behalf of the
 	// query's transaction.
 	remoteTxnDB *client.DB
+	// sqlExecutor is used to run SQL statements outside of the flow's
+	// transaction.
+	sqlExecutor sqlutil.InternalExecutor
+	// tempStorageDir is a directory in which processors can create temporary
+	// files; empty if temporary data has to be kept in memory.
+	tempStorageDir string
 	// nodeID is the ID of the node on which the processors using this FlowCtx
 	// run.
 	nodeID       roachpb.NodeID
 	testingKnobs TestingKnobs
 }
 
+// EvalCtx returns the context used by the processors in the flow to evaluate
+// expressions.
+func (flowCtx *FlowCtx) EvalCtx() *parser.EvalContext {
+	return &flowCtx.evalCtx
+}
+
+// ClientDB returns a handle to the cluster that can be used to perform requests
+// outside of the flow's transaction.
+func (flowCtx *FlowCtx) ClientDB() *client.DB {
+	return flowCtx.clientDB
+}
+
+// SQLExecutor returns an executor for SQL statements that run outside of the
+// flow's transaction.
+func (flowCtx *FlowCtx) SQLExecutor() sqlutil.InternalExecutor {
+	return flowCtx.sqlExecutor
+}
+
+// TempStorageDir returns the directory in which processors can create temporary
+// files. It is empty if temporary data has to be kept in memory.
+func (flowCtx *FlowCtx) TempStorageDir() string {
+	return flowCtx.tempStorageDir
+}
+
 func (flowCtx *FlowCtx) setupTxn() *client.Txn {
 	txn := client.NewTxnWithProto(flowCtx.remoteTxnDB, *flowCtx.txnProto)
 	// DistSQL transactions get retryable errors that would otherwise be handled
@@ -92,7 +123,7 @@ type Flow struct {
 	FlowCtx
 
 	flowRegistry *flowRegistry
-	processors   []processor
+	processors   []Processor
 	outboxes     []*outbox
 	// syncFlowConsumer is a special outbox which instead of sending rows to
 	// another host, returns them directly (as a result to a SetupSyncFlow RPC,
@@ -219,7 +250,7 @@ func checkNumInOut(inputs []RowSource, outputs []RowReceiver, numIn, numOut int)
 	return nil
 }
 
-func (f *Flow) makeProcessor(ps *ProcessorSpec, inputs []RowSource) (processor, error) {
+func (f *Flow) makeProcessor(ps *ProcessorSpec, inputs []RowSource) (Processor, error) {
 	if len(ps.Output) != 1 {
 		return nil, errors.Errorf("only single-output processors supported")
 	}
@@ -286,7 +317,7 @@ func (f *Flow) setup(ctx context.Context, spec *FlowSpec) error {
 		}
 	}
 
-	f.processors = make([]processor, len(spec.Processors))
+	f.processors = make([]Processor, len(spec.Processors))
 
 	for i := range spec.Processors {
 		var err error
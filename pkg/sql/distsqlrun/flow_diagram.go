@@ -28,6 +28,7 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	humanize "github.com/dustin/go-humanize"
 	"github.com/pkg/errors"
 )
@@ -162,6 +163,36 @@ func (bf *BackfillerSpec) summary() (string, []string) {
 	return "Backfiller", details
 }
 
+func (rc *ReadCSVSpec) summary() (string, []string) {
+	ss := make([]string, 0, len(rc.URI))
+	for _, s := range rc.URI {
+		ss = append(ss, s)
+	}
+	sort.Strings(ss)
+	details := []string{rc.TableDesc.Name}
+	details = append(details, ss...)
+	if rc.SampleSize != 0 {
+		details = append(details, fmt.Sprintf("Sample size: %s", humanizeutil.IBytes(rc.SampleSize)))
+	}
+	return "ReadCSV", details
+}
+
+func (s *SSTWriterSpec) summary() (string, []string) {
+	details := []string{fmt.Sprintf("SST size: %s", humanizeutil.IBytes(s.SSTSize))}
+	for _, span := range s.Spans {
+		details = append(details, span.String())
+	}
+	return "SSTWriter", details
+}
+
+func (s *CSVWriterSpec) summary() (string, []string) {
+	details := []string{s.Destination}
+	if s.ChunkRows != 0 {
+		details = append(details, fmt.Sprintf("Chunk rows: %d", s.ChunkRows))
+	}
+	return "CSVWriter", details
+}
+
 func (d *DistinctSpec) summary() (string, []string) {
 	details := []string{
 		colListStr(d.DistinctColumns),
@@ -37,7 +37,7 @@ type indexBackfiller struct {
 	da      sqlbase.DatumAlloc
 }
 
-var _ processor = &indexBackfiller{}
+var _ Processor = &indexBackfiller{}
 var _ chunkBackfiller = &indexBackfiller{}
 
 // IndexMutationFilter is a filter that allows mutations that add indexes.
@@ -97,6 +97,17 @@ func (ib *indexBackfiller) init() error {
 			for i, col := range cols {
 				valNeededForCol[i] = valNeededForCol[i] || idx.ContainsColumnID(col.ID)
 			}
+			// The predicate of a partial index determines which rows are added
+			// to it.
+			if idx.IsPartial() {
+				predIDs, err := desc.IndexExprSourceColumnIDs(idx.Predicate)
+				if err != nil {
+					return err
+				}
+				for _, id := range predIDs {
+					valNeededForCol[ib.colIdxMap[id]] = true
+				}
+			}
 		}
 	}
 
@@ -113,7 +124,12 @@ func (ib *indexBackfiller) runChunk(
 		added[i] = *m.GetIndex()
 	}
 	secondaryIndexEntries := make([]sqlbase.IndexEntry, len(mutations))
-	err := ib.flowCtx.clientDB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+	predicates, err := sqlbase.MakeIndexPredicates(&ib.spec.Table, added)
+	if err != nil {
+		return nil, err
+	}
+	defer predicates.Close(ctx)
+	err = ib.flowCtx.clientDB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
 		if ib.flowCtx.testingKnobs.RunBeforeBackfillChunk != nil {
 			if err := ib.flowCtx.testingKnobs.RunBeforeBackfillChunk(sp); err != nil {
 				return err
@@ -158,7 +174,14 @@ func (ib *indexBackfiller) runChunk(
 				ib.rowVals, secondaryIndexEntries); err != nil {
 				return err
 			}
+			if err := predicates.Filter(ib.colIdxMap, ib.rowVals, secondaryIndexEntries); err != nil {
+				return err
+			}
 			for _, secondaryIndexEntry := range secondaryIndexEntries {
+				if secondaryIndexEntry.Key == nil {
+					// The row is not part of this partial index.
+					continue
+				}
 				log.VEventf(ctx, 3, "InitPut %s -> %v", secondaryIndexEntry.Key,
 					secondaryIndexEntry.Value)
 				b.InitPut(secondaryIndexEntry.Key, &secondaryIndexEntry.Value)
@@ -22,7 +22,9 @@
 package distsqlrun
 
 import (
+	"bytes"
 	"hash/crc32"
+	"sort"
 
 	"golang.org/x/net/context"
 
@@ -51,6 +53,9 @@ func makeRouter(spec *OutputRouterSpec, streams []RowReceiver) (RowReceiver, err
 	case OutputRouterSpec_MIRROR:
 		return makeMirrorRouter(streams)
 
+	case OutputRouterSpec_BY_RANGE:
+		return makeRangeRouter(streams, spec.RangeRouterSpec)
+
 	default:
 		return nil, errors.Errorf("router type %s not supported", spec.Type)
 	}
@@ -91,8 +96,19 @@ type hashRouter struct {
 	alloc    sqlbase.DatumAlloc
 }
 
+type rangeRouter struct {
+	routerBase
+
+	alloc sqlbase.DatumAlloc
+	// b is a temp storage location used during encoding
+	b         []byte
+	encodings []OutputRouterSpec_RangeRouterSpec_ColumnEncoding
+	spans     []OutputRouterSpec_RangeRouterSpec_Span
+}
+
 var _ RowReceiver = &hashRouter{}
 var _ RowReceiver = &mirrorRouter{}
+var _ RowReceiver = &rangeRouter{}
 
 var crc32Table = crc32.MakeTable(crc32.Castagnoli)
 
@@ -118,6 +134,33 @@ func makeHashRouter(hashCols []uint32, streams []RowReceiver) (*hashRouter, erro
 	}, nil
 }
 
+func makeRangeRouter(
+	streams []RowReceiver, spec OutputRouterSpec_RangeRouterSpec,
+) (*rangeRouter, error) {
+	if len(spec.Encodings) == 0 {
+		return nil, errors.New("missing encodings for BY_RANGE router")
+	}
+	var prevKey []byte
+	// Verify spans are sorted and non-overlapping.
+	for i, span := range spec.Spans {
+		if bytes.Compare(prevKey, span.Start) > 0 {
+			return nil, errors.Errorf("span %d not after previous span", i)
+		}
+		if bytes.Compare(span.Start, span.End) >= 0 {
+			return nil, errors.Errorf("span %d: empty or inverted span", i)
+		}
+		if span.Stream < 0 || int(span.Stream) >= len(streams) {
+			return nil, errors.Errorf("span %d: stream %d out of range", i, span.Stream)
+		}
+		prevKey = span.End
+	}
+	return &rangeRouter{
+		routerBase: makeRouterBase(streams),
+		spans:      spec.Spans,
+		encodings:  spec.Encodings,
+	}, nil
+}
+
 // ProducerDone is part of the RowReceiver interface.
 func (rb *routerBase) ProducerDone() {
 	for _, s := range rb.streams {
@@ -232,3 +275,67 @@ func (hr *hashRouter) computeDestination(row sqlbase.EncDatumRow) (int, error) {
 	// accelerated).
 	return int(crc32.Update(0, crc32Table, hr.buffer) % uint32(len(hr.streams))), nil
 }
+
+// Push is part of the RowReceiver interface.
+func (rr *rangeRouter) Push(row sqlbase.EncDatumRow, meta ProducerMetadata) ConsumerStatus {
+	if !meta.Empty() {
+		rr.fwdMetadata(meta)
+		return rr.aggregatedStatus
+	}
+	if rr.aggregatedStatus != NeedMoreRows {
+		return rr.aggregatedStatus
+	}
+
+	streamIdx, err := rr.computeDestination(row)
+	if err != nil {
+		rr.fwdMetadata(ProducerMetadata{Err: err})
+		rr.aggregatedStatus = ConsumerClosed
+		return ConsumerClosed
+	}
+
+	if rr.streamStatus[streamIdx] == NeedMoreRows {
+		newStatus := rr.streams[streamIdx].Push(row, ProducerMetadata{})
+		rr.updateStreamState(streamIdx, newStatus)
+	}
+	return rr.aggregatedStatus
+}
+
+// computeDestination encodes the row's columns as specified by the router's
+// encodings and returns the index of the stream of the span containing the
+// resulting key.
+func (rr *rangeRouter) computeDestination(row sqlbase.EncDatumRow) (int, error) {
+	var err error
+	rr.b = rr.b[:0]
+	for _, enc := range rr.encodings {
+		if int(enc.Column) >= len(row) {
+			return -1, errors.Errorf("range column %d, row with only %d columns", enc.Column, len(row))
+		}
+		rr.b, err = row[enc.Column].Encode(&rr.alloc, enc.Encoding, rr.b)
+		if err != nil {
+			return -1, err
+		}
+	}
+	i := rr.spanForData(rr.b)
+	if i == -1 {
+		return -1, errors.New("no span found for key")
+	}
+	return int(rr.spans[i].Stream), nil
+}
+
+// spanForData returns the index of the span that contains the given key, or -1
+// if no span contains it.
+func (rr *rangeRouter) spanForData(data []byte) int {
+	i := sort.Search(len(rr.spans), func(i int) bool {
+		return bytes.Compare(rr.spans[i].End, data) > 0
+	})
+
+	// No span ends after data.
+	if i == len(rr.spans) {
+		return -1
+	}
+	// Make sure the Start is <= data.
+	if bytes.Compare(rr.spans[i].Start, data) > 0 {
+		return -1
+	}
+	return i
+}
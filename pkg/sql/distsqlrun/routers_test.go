@@ -27,6 +27,8 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 )
@@ -214,6 +216,91 @@ func TestMirrorRouter(t *testing.T) {
 // returned while there's at least one consumer that's not draining, then
 // DrainRequested should be returned while there's at least one consumer that's
 // not closed, and ConsumerClosed should be returned afterwards.
+func TestRangeRouter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	intType := sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT}
+	key := func(i int64) []byte {
+		return encoding.EncodeVarintAscending(nil, i)
+	}
+	spec := OutputRouterSpec_RangeRouterSpec{
+		Spans: []OutputRouterSpec_RangeRouterSpec_Span{
+			{Start: key(0), End: key(10), Stream: 0},
+			{Start: key(10), End: key(20), Stream: 1},
+			{Start: key(30), End: key(40), Stream: 0},
+		},
+		Encodings: []OutputRouterSpec_RangeRouterSpec_ColumnEncoding{
+			{Column: 1, Encoding: sqlbase.DatumEncoding_ASCENDING_KEY},
+		},
+	}
+
+	bufs := []*RowBuffer{{}, {}}
+	recvs := []RowReceiver{bufs[0], bufs[1]}
+	rr, err := makeRangeRouter(recvs, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := int64(0); i < 40; i++ {
+		if i >= 20 && i < 30 {
+			// Not in any span.
+			continue
+		}
+		row := sqlbase.EncDatumRow{
+			sqlbase.DatumToEncDatum(intType, parser.NewDInt(parser.DInt(-i))),
+			sqlbase.DatumToEncDatum(intType, parser.NewDInt(parser.DInt(i))),
+		}
+		if status := rr.Push(row, ProducerMetadata{}); status != NeedMoreRows {
+			t.Fatalf("unexpected status: %d", status)
+		}
+	}
+	rr.ProducerDone()
+
+	for streamIdx, b := range bufs {
+		for _, row := range getRowsFromBuffer(t, b) {
+			i := int64(*row[1].Datum.(*parser.DInt))
+			expected := 0
+			if i >= 10 && i < 20 {
+				expected = 1
+			}
+			if streamIdx != expected {
+				t.Errorf("row %s: expected stream %d, got %d", row, expected, streamIdx)
+			}
+		}
+	}
+
+	t.Run("no span", func(t *testing.T) {
+		bufs := []*RowBuffer{{}, {}}
+		rr, err := makeRangeRouter([]RowReceiver{bufs[0], bufs[1]}, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		row := sqlbase.EncDatumRow{
+			sqlbase.DatumToEncDatum(intType, parser.NewDInt(0)),
+			sqlbase.DatumToEncDatum(intType, parser.NewDInt(25)),
+		}
+		if status := rr.Push(row, ProducerMetadata{}); status != ConsumerClosed {
+			t.Fatalf("unexpected status: %d", status)
+		}
+		if _, meta := bufs[0].Next(); !testutils.IsError(meta.Err, "no span found for key") {
+			t.Fatalf("unexpected metadata: %v", meta)
+		}
+	})
+
+	t.Run("overlapping spans", func(t *testing.T) {
+		spec := OutputRouterSpec_RangeRouterSpec{
+			Spans: []OutputRouterSpec_RangeRouterSpec_Span{
+				{Start: key(0), End: key(10), Stream: 0},
+				{Start: key(5), End: key(20), Stream: 1},
+			},
+			Encodings: spec.Encodings,
+		}
+		if _, err := makeRangeRouter(recvs, spec); !testutils.IsError(err, "not after previous span") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
 func TestConsumerStatus(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	mirrorRouterFactory := func() (RowReceiver, []RowSource, error) {
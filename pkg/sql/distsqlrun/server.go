@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/mon"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
@@ -93,6 +94,13 @@ type ServerConfig struct {
 	// NodeID is the id of the node on which this Server is running.
 	NodeID    *base.NodeIDContainer
 	ClusterID uuid.UUID
+
+	// SQLExecutor is used by processors that need to run SQL statements outside
+	// of their flow's transaction (e.g. to report the progress of a job).
+	SQLExecutor sqlutil.InternalExecutor
+	// TempStorageDir is a directory in which processors can create temporary
+	// files. If empty, processors have to keep their temporary data in memory.
+	TempStorageDir string
 }
 
 // ServerImpl implements the server for the distributed SQL APIs.
@@ -197,6 +205,8 @@ func (ds *ServerImpl) setupFlow(
 		txnProto:       &req.Txn,
 		clientDB:       ds.DB,
 		remoteTxnDB:    ds.FlowDB,
+		sqlExecutor:    ds.SQLExecutor,
+		tempStorageDir: ds.TempStorageDir,
 		testingKnobs:   ds.TestingKnobs,
 		nodeID:         nodeID,
 	}
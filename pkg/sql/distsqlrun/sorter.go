@@ -45,7 +45,7 @@ type sorter struct {
 	count int64
 }
 
-var _ processor = &sorter{}
+var _ Processor = &sorter{}
 
 func newSorter(
 	flowCtx *FlowCtx, spec *SorterSpec, input RowSource, post *PostProcessSpec, output RowReceiver,
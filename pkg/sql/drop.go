@@ -362,6 +362,20 @@ func (p *planner) dropIndexByName(
 	if !found {
 		return fmt.Errorf("index %q in the middle of being added, try again later", idxName)
 	}
+	// The hidden columns computed for the expression elements of the index
+	// are dropped along with it.
+	for i, exprStr := range idx.ColumnExprs {
+		if exprStr == "" {
+			continue
+		}
+		for j := range tableDesc.Columns {
+			if tableDesc.Columns[j].ID == idx.ColumnIDs[i] {
+				tableDesc.AddColumnMutation(tableDesc.Columns[j], sqlbase.DescriptorMutation_DROP)
+				tableDesc.Columns = append(tableDesc.Columns[:j], tableDesc.Columns[j+1:]...)
+				break
+			}
+		}
+	}
 
 	if err := tableDesc.Validate(ctx, p.txn); err != nil {
 		return err
@@ -1104,6 +1118,7 @@ type dropUserNode struct {
 
 func (n *dropUserNode) Start(ctx context.Context) error {
 	numDeleted := 0
+	removedMemberships := false
 	for _, name := range n.n.Names {
 		normalizedUsername, err := NormalizeAndValidateUsername(string(name))
 		if err != nil {
@@ -1133,10 +1148,37 @@ func (n *dropUserNode) Start(ctx context.Context) error {
 		}
 
 		numDeleted += rowsAffected
+
+		if _, err := internalExecutor.ExecuteStatementInTransaction(
+			ctx,
+			"drop-user-options",
+			n.p.txn,
+			"DELETE FROM system.user_options WHERE username=$1",
+			normalizedUsername,
+		); err != nil {
+			return err
+		}
+
+		memberships, err := internalExecutor.ExecuteStatementInTransaction(
+			ctx,
+			"drop-user-roles",
+			n.p.txn,
+			"DELETE FROM system.role_members WHERE member=$1",
+			normalizedUsername,
+		)
+		if err != nil {
+			return err
+		}
+		if memberships > 0 {
+			removedMemberships = true
+		}
 	}
 
 	n.numDeleted = numDeleted
 
+	if removedMemberships {
+		return n.p.bumpRoleMembersVersion(ctx)
+	}
 	return nil
 }
 
@@ -1159,5 +1201,26 @@ func (p *planner) DropUser(ctx context.Context, n *parser.DropUser) (planNode, e
 		return nil, err
 	}
 
+	// Dropping a user removes its role memberships, which changes the
+	// system.role_members descriptor version like a schema change does.
+	internalExecutor := InternalExecutor{LeaseManager: p.LeaseMgr()}
+	for _, name := range n.Names {
+		row, err := internalExecutor.QueryRowInTransaction(
+			ctx, "check-user-roles", p.txn,
+			`SELECT role FROM system.role_members WHERE member = $1 LIMIT 1`,
+			name.Normalize(),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if row != nil {
+			if err := p.txn.SetSystemConfigTrigger(); err != nil {
+				return nil, errors.Wrap(err,
+					"dropping a member of a role cannot follow a statement that has written in the same transaction")
+			}
+			break
+		}
+	}
+
 	return &dropUserNode{p: p, n: n}, nil
 }
@@ -227,6 +227,9 @@ type Executor struct {
 	// Application-level SQL statistics
 	sqlStats sqlStats
 
+	// Role memberships, shared by all sessions.
+	roleMembers roleMembershipCache
+
 	// Attempts to use unimplemented features.
 	unimplementedErrors struct {
 		syncutil.Mutex
@@ -251,6 +254,9 @@ type ExecutorConfig struct {
 	DistSQLSrv      *distsqlrun.ServerImpl
 	StatusServer    serverpb.StatusServer
 	SessionRegistry *SessionRegistry
+	// Stopper is used to run the work of jobs which outlives the statements
+	// that started them.
+	Stopper *stop.Stopper
 
 	TestingKnobs              *ExecutorTestingKnobs
 	SchemaChangerTestingKnobs *SchemaChangerTestingKnobs
@@ -642,6 +648,9 @@ func (e *Executor) execRequest(
 	} else {
 		var sl parser.StatementList
 		sl, err = parser.Parse(sql)
+		if err == nil {
+			err = checkCopyToAlone(sl)
+		}
 		stmts = NewStatementList(sl)
 	}
 	session.phaseTimes[sessionEndParse] = timeutil.Now()
@@ -1446,7 +1455,7 @@ func (e *Executor) execStmtInOpenTxn(
 
 	tResult := &traceResult{tag: result.PGTag, count: -1}
 	switch result.Type {
-	case parser.RowsAffected:
+	case parser.RowsAffected, parser.CopyOut:
 		tResult.count = result.RowsAffected
 	case parser.Rows:
 		tResult.count = result.Rows.Len()
@@ -1597,6 +1606,20 @@ func (e *Executor) execClassic(planner *planner, plan planNode, result *Result)
 		if err != nil {
 			return err
 		}
+
+	case parser.CopyOut:
+		// The rows are sent to the client by the copyToNode itself; all that
+		// is left here is to count them.
+		next, err := plan.Next(ctx)
+		for ; next; next, err = plan.Next(ctx) {
+			planner.evalCtx.ActiveMemAcc.Close(ctx)
+			rowAcc = planner.evalCtx.Mon.MakeBoundAccount()
+			planner.evalCtx.ActiveMemAcc = &rowAcc
+			result.RowsAffected++
+		}
+		if err != nil {
+			return err
+		}
 	case parser.DDL:
 		if n, ok := plan.(*createTableNode); ok && n.n.As() {
 			result.RowsAffected += n.count
@@ -1616,6 +1639,11 @@ func (e *Executor) shouldUseDistSQL(planner *planner, plan planNode) (bool, erro
 	if _, ok := plan.(*emptyNode); ok {
 		return false, nil
 	}
+	// COPY TO streams rows to the client as they are produced, which distSQL
+	// does not support.
+	if _, ok := plan.(*copyToNode); ok {
+		return false, nil
+	}
 
 	var err error
 	var distribute bool
@@ -1712,6 +1740,7 @@ func (e *Executor) execStmt(
 	e.recordStatementSummary(
 		planner, stmt, useDistSQL, automaticRetryCount, result, err,
 	)
+	e.maybeLogAudit(planner, stmt, result, err)
 	if err != nil {
 		result.Close(session.Ctx())
 		return Result{}, err
@@ -1759,6 +1788,7 @@ func (e *Executor) execStmtInParallel(stmt Statement, planner *planner) (Result,
 		err = e.execClassic(planner, plan, &result)
 		planner.phaseTimes[plannerEndExecStmt] = timeutil.Now()
 		e.recordStatementSummary(planner, stmt, false, 0, result, err)
+		e.maybeLogAudit(planner, stmt, result, err)
 		return err
 	})
 	return mockResult, nil
@@ -1870,6 +1900,9 @@ func checkResultType(typ parser.Type) error {
 	case parser.TypeTimestampTZ:
 	case parser.TypeInterval:
 	case parser.TypeUUID:
+	case parser.TypeINet:
+	case parser.TypeTime:
+	case parser.TypeBitArray:
 	case parser.TypeStringArray:
 	case parser.TypeNameArray:
 	case parser.TypeIntArray:
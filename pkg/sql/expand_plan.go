@@ -130,6 +130,13 @@ func doExpandPlan(
 		}
 		n.right.plan, err = doExpandPlan(ctx, p, noParams, n.right.plan)
 
+	case *applyJoinNode:
+		n.left.plan, err = doExpandPlan(ctx, p, noParams, n.left.plan)
+		if err != nil {
+			return plan, err
+		}
+		n.right.plan, err = doExpandPlan(ctx, p, noParams, n.right.plan)
+
 	case *ordinalityNode:
 		// There may be too many columns in the required ordering. Filter them.
 		params.desiredOrdering = n.restrictOrdering(params.desiredOrdering)
@@ -227,17 +234,27 @@ func doExpandPlan(
 	case *relocateNode:
 		n.rows, err = doExpandPlan(ctx, p, noParams, n.rows)
 
+	case *copyToNode:
+		n.source, err = doExpandPlan(ctx, p, noParams, n.source)
+
 	case *valuesNode:
 	case *alterTableNode:
 	case *copyNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
+	case *createPolicyNode:
+	case *createRoleNode:
 	case *createUserNode:
+	case *alterUserNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
+	case *dropPolicyNode:
+	case *dropRoleNode:
 	case *dropUserNode:
+	case *grantRoleNode:
+	case *revokeRoleNode:
 	case *emptyNode:
 	case *hookFnNode:
 	case *valueGenerator:
@@ -449,6 +466,10 @@ func simplifyOrderings(plan planNode, usefulOrdering sqlbase.ColumnOrdering) pla
 		n.left.plan = simplifyOrderings(n.left.plan, nil)
 		n.right.plan = simplifyOrderings(n.right.plan, nil)
 
+	case *applyJoinNode:
+		n.left.plan = simplifyOrderings(n.left.plan, nil)
+		n.right.plan = simplifyOrderings(n.right.plan, nil)
+
 	case *ordinalityNode:
 		// The ordinality node either passes through the source ordering, or if
 		// there is none it creates an ordering on the ordinality column (see the
@@ -542,17 +563,27 @@ func simplifyOrderings(plan planNode, usefulOrdering sqlbase.ColumnOrdering) pla
 	case *relocateNode:
 		n.rows = simplifyOrderings(n.rows, nil)
 
+	case *copyToNode:
+		n.source = simplifyOrderings(n.source, nil)
+
 	case *valuesNode:
 	case *alterTableNode:
 	case *copyNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
+	case *createPolicyNode:
+	case *createRoleNode:
 	case *createUserNode:
+	case *alterUserNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
+	case *dropPolicyNode:
+	case *dropRoleNode:
 	case *dropUserNode:
+	case *grantRoleNode:
+	case *revokeRoleNode:
 	case *emptyNode:
 	case *hookFnNode:
 	case *valueGenerator:
@@ -71,9 +71,14 @@ func (v *varConvertVisitor) VisitPre(expr parser.Expr) (recurse bool, newExpr pa
 	}
 
 	if varExpr, ok := expr.(parser.VariableExpr); ok {
-		// Ignore sub-queries and placeholders
-		switch expr.(type) {
-		case *subquery, *parser.Placeholder:
+		// Ignore sub-queries, placeholders and references to outer
+		// scopes, which are constant for the duration of the plan. The
+		// variables of correlated sub-queries are checked and converted
+		// like the others.
+		switch t := expr.(type) {
+		case *subquery:
+			return t.correlated(), expr
+		case *parser.Placeholder, *outerVar:
 			return false, expr
 		}
 
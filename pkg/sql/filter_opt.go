@@ -194,6 +194,11 @@ func (p *planner) propagateFilters(
 
 	case *scanNode:
 		n.filter = mergeConj(n.filter, n.filterVars.Rebind(extraFilter, true, false))
+		if n.filter != nil {
+			if n.filter, err = p.replaceIndexExprs(n); err != nil {
+				return plan, extraFilter, err
+			}
+		}
 		return plan, parser.DBoolTrue, nil
 
 	case *renderNode:
@@ -202,6 +207,16 @@ func (p *planner) propagateFilters(
 	case *joinNode:
 		return p.addJoinFilter(ctx, n, extraFilter)
 
+	case *applyJoinNode:
+		// TODO(knz): propagate the parts of the filter that only
+		// depend on the left operand.
+		if n.left.plan, err = p.triggerFilterPropagation(ctx, n.left.plan); err != nil {
+			return plan, extraFilter, err
+		}
+		if n.right.plan, err = p.triggerFilterPropagation(ctx, n.right.plan); err != nil {
+			return plan, extraFilter, err
+		}
+
 	case *indexJoinNode:
 		panic("filter optimization must occur before index selection")
 
@@ -317,17 +332,29 @@ func (p *planner) propagateFilters(
 			return plan, extraFilter, err
 		}
 
+	case *copyToNode:
+		if n.source, err = p.triggerFilterPropagation(ctx, n.source); err != nil {
+			return plan, extraFilter, err
+		}
+
 	case *alterTableNode:
 	case *copyNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
+	case *createPolicyNode:
+	case *createRoleNode:
 	case *createUserNode:
+	case *alterUserNode:
 	case *delayedNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
+	case *dropPolicyNode:
+	case *dropRoleNode:
 	case *dropUserNode:
+	case *grantRoleNode:
+	case *revokeRoleNode:
 	case *hookFnNode:
 	case *valueGenerator:
 	case *valuesNode:
@@ -521,7 +548,7 @@ func (p *planner) addJoinFilter(
 	ctx context.Context, n *joinNode, extraFilter parser.TypedExpr,
 ) (planNode, parser.TypedExpr, error) {
 	// TODO(knz): support outer joins.
-	if n.joinType != joinTypeInner {
+	if n.joinType != joinTypeInner && n.joinType != joinTypeSemi && n.joinType != joinTypeAnti {
 		// Outer joins not supported; simply trigger filter optimization in the sub-nodes.
 		var err error
 		if n.left.plan, err = p.triggerFilterPropagation(ctx, n.left.plan); err == nil {
@@ -629,7 +656,8 @@ func (p *planner) addJoinFilter(
 	//   in SQL the USING/NATURAL syntax is mutually exclusive with ON
 	// - at every subsequent round of filter optimization, changes to
 	//   n.pred.onCond have been processed by the code above.
-	initialPred := mergeConj(n.pred.iVarHelper.Rebind(extraFilter, false, false), n.pred.onCond)
+	extraFilter = n.pred.iVarHelper.Rebind(extraFilter, false, false)
+	initialPred := mergeConj(extraFilter, n.pred.onCond)
 
 	// Split the initial predicate into left, right and combined parts.
 	// In this process we shift the IndexedVars in the left and right
@@ -642,6 +670,13 @@ func (p *planner) addJoinFilter(
 			}
 			return false, expr
 		})
+	if n.joinType == joinTypeAnti {
+		// The ON predicate of an anti-join determines which left rows
+		// are *not* emitted, so its left-only parts must stay in the
+		// predicate. Only the extra filter, which refers to the left
+		// columns exclusively, can be propagated to the left operand.
+		leftExpr, remainder = extraFilter, n.pred.onCond
+	}
 	rightExpr, combinedExpr := splitFilter(remainder,
 		func(expr parser.VariableExpr) (bool, parser.Expr) {
 			if iv, ok := expr.(*parser.IndexedVar); ok && iv.Idx >= rightBegin {
@@ -70,7 +70,15 @@ func (p *planner) makeGenerator(ctx context.Context, t *parser.FuncExpr) (planNo
 	}
 
 	var columns sqlbase.ResultColumns
-	if len(tType.Cols) == 1 {
+	if tType.Labels != nil {
+		columns = make(sqlbase.ResultColumns, len(tType.Cols))
+		for i, t := range tType.Cols {
+			columns[i] = sqlbase.ResultColumn{
+				Name: tType.Labels[i],
+				Typ:  t,
+			}
+		}
+	} else if len(tType.Cols) == 1 {
 		columns = sqlbase.ResultColumns{sqlbase.ResultColumn{Name: origName, Typ: tType.Cols[0]}}
 	} else {
 		columns = make(sqlbase.ResultColumns, len(tType.Cols))
@@ -20,15 +20,21 @@ import (
 	"golang.org/x/net/context"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 )
 
+// changePrivileges applies changePrivilege to the privilege descriptor of
+// all the targets, for each grantee. If columns is set, the targets must be
+// tables and changePrivilege is also passed the IDs of these columns in each
+// table.
 func (p *planner) changePrivileges(
 	ctx context.Context,
 	targets parser.TargetList,
+	columns parser.NameList,
 	grantees parser.NameList,
-	changePrivilege func(*sqlbase.PrivilegeDescriptor, string),
+	changePrivilege func(*sqlbase.PrivilegeDescriptor, string, []sqlbase.ColumnID),
 ) (planNode, error) {
 	descriptors, err := getDescriptorsFromTargetList(ctx, p.txn, p.getVirtualTabler(), p.session.Database, targets)
 	if err != nil {
@@ -39,9 +45,25 @@ func (p *planner) changePrivileges(
 		if err := p.CheckPrivilege(descriptor, privilege.GRANT); err != nil {
 			return nil, err
 		}
+		var columnIDs []sqlbase.ColumnID
+		if columns != nil {
+			tableDesc, ok := descriptor.(*sqlbase.TableDescriptor)
+			if !ok {
+				return nil, pgerror.NewErrorf(pgerror.CodeInvalidGrantOperationError,
+					"column privileges can only be granted on tables")
+			}
+			columnIDs = make([]sqlbase.ColumnID, len(columns))
+			for i, name := range columns {
+				col, err := tableDesc.FindActiveColumnByName(name)
+				if err != nil {
+					return nil, err
+				}
+				columnIDs[i] = col.ID
+			}
+		}
 		privileges := descriptor.GetPrivileges()
 		for _, grantee := range grantees {
-			changePrivilege(privileges, string(grantee))
+			changePrivilege(privileges, string(grantee), columnIDs)
 		}
 
 		switch d := descriptor.(type) {
@@ -81,10 +103,24 @@ func (p *planner) changePrivileges(
 // Privileges: GRANT on database/table/view.
 //   Notes: postgres requires the object owner.
 //          mysql requires the "grant option" and the same privileges, and sometimes superuser.
+// Column privileges: only SELECT and UPDATE can be granted on columns of
+// tables and views. They apply in addition to the privileges on the table.
 func (p *planner) Grant(ctx context.Context, n *parser.Grant) (planNode, error) {
-	return p.changePrivileges(ctx, n.Targets, n.Grantees, func(privDesc *sqlbase.PrivilegeDescriptor, grantee string) {
-		privDesc.Grant(grantee, n.Privileges)
-	})
+	if n.Columns != nil {
+		if err := checkColumnPrivilegeList(n.Privileges); err != nil {
+			return nil, err
+		}
+	}
+	return p.changePrivileges(ctx, n.Targets, n.Columns, n.Grantees,
+		func(privDesc *sqlbase.PrivilegeDescriptor, grantee string, columnIDs []sqlbase.ColumnID) {
+			if columnIDs == nil {
+				privDesc.Grant(grantee, n.Privileges)
+				return
+			}
+			for _, id := range columnIDs {
+				privDesc.GrantColumn(id, grantee, n.Privileges)
+			}
+		})
 }
 
 // Revoke removes privileges from users.
@@ -96,8 +132,34 @@ func (p *planner) Grant(ctx context.Context, n *parser.Grant) (planNode, error)
 // Privileges: GRANT on database/table/view.
 //   Notes: postgres requires the object owner.
 //          mysql requires the "grant option" and the same privileges, and sometimes superuser.
+// Revoking privileges on a table also revokes them on all of its columns.
 func (p *planner) Revoke(ctx context.Context, n *parser.Revoke) (planNode, error) {
-	return p.changePrivileges(ctx, n.Targets, n.Grantees, func(privDesc *sqlbase.PrivilegeDescriptor, grantee string) {
-		privDesc.Revoke(grantee, n.Privileges)
-	})
+	if n.Columns != nil {
+		if err := checkColumnPrivilegeList(n.Privileges); err != nil {
+			return nil, err
+		}
+	}
+	return p.changePrivileges(ctx, n.Targets, n.Columns, n.Grantees,
+		func(privDesc *sqlbase.PrivilegeDescriptor, grantee string, columnIDs []sqlbase.ColumnID) {
+			if columnIDs == nil {
+				privDesc.Revoke(grantee, n.Privileges)
+				return
+			}
+			for _, id := range columnIDs {
+				privDesc.RevokeColumn(id, grantee, n.Privileges)
+			}
+		})
+}
+
+// checkColumnPrivilegeList checks that the privileges of a GRANT or REVOKE
+// statement with a column list can be granted on columns.
+func checkColumnPrivilegeList(privs privilege.List) error {
+	allowed := sqlbase.AllowedColumnPrivileges.ToBitField() | privilege.ALL.Mask()
+	for _, priv := range privs {
+		if priv.Mask()&allowed == 0 {
+			return pgerror.NewErrorf(pgerror.CodeInvalidGrantOperationError,
+				"invalid privilege type %s for column", priv)
+		}
+	}
+	return nil
 }
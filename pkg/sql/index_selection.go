@@ -105,6 +105,14 @@ func (p *planner) selectIndex(
 	if s.specifiedIndex != nil {
 		// An explicit secondary index was requested. Only add it to the candidate
 		// indexes list.
+		if s.specifiedIndex.IsPartial() {
+			if implied, err := p.filterImpliesPredicate(s, s.specifiedIndex); err != nil {
+				return nil, err
+			} else if !implied {
+				return nil, fmt.Errorf("index %q is a partial index whose predicate is not implied by "+
+					"the query's filter", s.specifiedIndex.Name)
+			}
+		}
 		candidates = append(candidates, &indexInfo{
 			desc:  &s.desc,
 			index: s.specifiedIndex,
@@ -115,6 +123,15 @@ func (p *planner) selectIndex(
 			index: &s.desc.PrimaryIndex,
 		})
 		for i := range s.desc.Indexes {
+			// A partial index can only be used if it contains every row the
+			// filter may accept.
+			if s.desc.Indexes[i].IsPartial() {
+				if implied, err := p.filterImpliesPredicate(s, &s.desc.Indexes[i]); err != nil {
+					return nil, err
+				} else if !implied {
+					continue
+				}
+			}
 			candidates = append(candidates, &indexInfo{
 				desc:  &s.desc,
 				index: &s.desc.Indexes[i],
@@ -263,6 +280,137 @@ func (p *planner) selectIndex(
 	return plan, nil
 }
 
+// replaceIndexExprs returns the scan filter with every subexpression that
+// matches an expression element of one of the table's indexes replaced by the
+// hidden column computed from that expression. This lets the constraints on
+// the expression be used to select and constrain the index.
+func (p *planner) replaceIndexExprs(s *scanNode) (parser.TypedExpr, error) {
+	v, err := p.makeIndexExprReplacer(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(v.exprs) == 0 {
+		return s.filter, nil
+	}
+	expr, changed := parser.WalkExpr(&v, s.filter)
+	if !changed {
+		return s.filter, nil
+	}
+	// Rebind the filter so that the columns it no longer refers to are not
+	// considered needed.
+	return s.filterVars.Rebind(expr.(parser.TypedExpr), true, false), nil
+}
+
+// makeIndexExprReplacer returns an indexExprReplacer for the expression
+// elements of the table's indexes which are available in the scan.
+func (p *planner) makeIndexExprReplacer(s *scanNode) (indexExprReplacer, error) {
+	v := indexExprReplacer{s: s}
+	ivarHelper := parser.MakeIndexedVarHelper(s, len(s.cols))
+	for i := range s.desc.Indexes {
+		index := &s.desc.Indexes[i]
+		for j, exprStr := range index.ColumnExprs {
+			if exprStr == "" {
+				continue
+			}
+			colIdx, ok := s.colIdxMap[index.ColumnIDs[j]]
+			if !ok {
+				continue
+			}
+			typedExpr, err := p.parseIndexExpr(s, exprStr, &ivarHelper, parser.TypeAny)
+			if err != nil {
+				return indexExprReplacer{}, err
+			}
+			v.exprs = append(v.exprs, typedExpr.String())
+			v.cols = append(v.cols, colIdx)
+		}
+	}
+	return v, nil
+}
+
+// parseIndexExpr parses, type checks and normalizes the expression of an
+// index element or the predicate of a partial index. The scan columns start
+// with the public columns of the table, so the IndexedVars of the parsed
+// expression refer to the same columns as the ones in the scan filter.
+func (p *planner) parseIndexExpr(
+	s *scanNode, exprStr string, ivarHelper *parser.IndexedVarHelper, desired parser.Type,
+) (parser.TypedExpr, error) {
+	expr, err := sqlbase.ParseIndexExpr(&s.desc, exprStr, ivarHelper)
+	if err != nil {
+		return nil, err
+	}
+	typedExpr, err := parser.TypeCheck(expr, &p.semaCtx, desired)
+	if err != nil {
+		return nil, err
+	}
+	return p.parser.NormalizeExpr(&p.evalCtx, typedExpr)
+}
+
+// filterImpliesPredicate returns true if the scan filter implies the
+// predicate of the given partial index, i.e. if the index contains every row
+// the filter may accept. The implication is only recognized when every
+// conjunct of the predicate is also a conjunct of the filter.
+func (p *planner) filterImpliesPredicate(
+	s *scanNode, index *sqlbase.IndexDescriptor,
+) (bool, error) {
+	if s.filter == nil {
+		return false, nil
+	}
+	ivarHelper := parser.MakeIndexedVarHelper(s, len(s.cols))
+	pred, err := p.parseIndexExpr(s, index.Predicate, &ivarHelper, parser.TypeBool)
+	if err != nil {
+		return false, err
+	}
+	// The filter refers to the hidden columns of expression indexes instead
+	// of the expressions they are computed from; so must the predicate.
+	v, err := p.makeIndexExprReplacer(s)
+	if err != nil {
+		return false, err
+	}
+	if len(v.exprs) > 0 {
+		expr, _ := parser.WalkExpr(&v, pred)
+		pred = expr.(parser.TypedExpr)
+	}
+
+	filterConjuncts := make(map[string]struct{})
+	for _, e := range splitAndExpr(&p.evalCtx, s.filter, nil) {
+		filterConjuncts[e.String()] = struct{}{}
+	}
+	for _, e := range splitAndExpr(&p.evalCtx, pred, nil) {
+		if d, ok := e.(*parser.DBool); ok && bool(*d) {
+			continue
+		}
+		if _, ok := filterConjuncts[e.String()]; !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+type indexExprReplacer struct {
+	s *scanNode
+	// exprs are the formatted index expressions and cols the positions of the
+	// corresponding hidden columns in the scan.
+	exprs []string
+	cols  []int
+}
+
+var _ parser.Visitor = &indexExprReplacer{}
+
+func (v *indexExprReplacer) VisitPre(expr parser.Expr) (recurse bool, newExpr parser.Expr) {
+	if _, ok := expr.(parser.Datum); ok {
+		return false, expr
+	}
+	str := expr.String()
+	for i := range v.exprs {
+		if v.exprs[i] == str {
+			return false, parser.NewOrdinalReference(v.cols[i])
+		}
+	}
+	return true, expr
+}
+
+func (*indexExprReplacer) VisitPost(expr parser.Expr) parser.Expr { return expr }
+
 type indexConstraint struct {
 	start *parser.ComparisonExpr
 	end   *parser.ComparisonExpr
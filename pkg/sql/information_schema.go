@@ -36,6 +36,7 @@ const (
 var informationSchema = virtualSchema{
 	name: informationSchemaName,
 	tables: []virtualSchemaTable{
+		informationSchemaColumnPrivileges,
 		informationSchemaColumnsTable,
 		informationSchemaKeyColumnUsageTable,
 		informationSchemaSchemataTable,
@@ -97,6 +98,49 @@ func dIntFnOrNull(fn func() (int32, bool)) parser.Datum {
 	return parser.DNull
 }
 
+var informationSchemaColumnPrivileges = virtualSchemaTable{
+	schema: `
+CREATE TABLE information_schema.column_privileges (
+	GRANTOR STRING NOT NULL DEFAULT '',
+	GRANTEE STRING NOT NULL DEFAULT '',
+	TABLE_CATALOG STRING NOT NULL DEFAULT '',
+	TABLE_SCHEMA STRING NOT NULL DEFAULT '',
+	TABLE_NAME STRING NOT NULL DEFAULT '',
+	COLUMN_NAME STRING NOT NULL DEFAULT '',
+	PRIVILEGE_TYPE STRING NOT NULL DEFAULT '',
+	IS_GRANTABLE BOOL NOT NULL DEFAULT FALSE
+);
+`,
+	populate: func(ctx context.Context, p *planner, addRow func(...parser.Datum) error) error {
+		return forEachTableDesc(ctx, p, func(db *sqlbase.DatabaseDescriptor, table *sqlbase.TableDescriptor) error {
+			for _, c := range table.Privileges.Columns {
+				column, err := table.FindActiveColumnByID(c.ColumnID)
+				if err != nil {
+					// The column is being dropped.
+					continue
+				}
+				for _, u := range table.Privileges.ShowColumn(c.ColumnID) {
+					for _, privilege := range u.Privileges {
+						if err := addRow(
+							parser.DNull,                   // grantor
+							parser.NewDString(u.User),      // grantee
+							defString,                      // table_catalog
+							parser.NewDString(db.Name),     // table_schema
+							parser.NewDString(table.Name),  // table_name
+							parser.NewDString(column.Name), // column_name
+							parser.NewDString(privilege),   // privilege_type
+							parser.DNull,                   // is_grantable
+						); err != nil {
+							return err
+						}
+					}
+				}
+			}
+			return nil
+		})
+	},
+}
+
 var informationSchemaColumnsTable = virtualSchemaTable{
 	schema: `
 CREATE TABLE information_schema.columns (
@@ -580,9 +624,14 @@ func forEachDatabaseDesc(
 		dbDescs = append(dbDescs, schema.desc)
 	}
 
+	memberOf, err := p.MemberOf(ctx, p.session.User)
+	if err != nil {
+		return err
+	}
+
 	sort.Sort(sortedDBDescs(dbDescs))
 	for _, db := range dbDescs {
-		if userCanSeeDatabase(db, p.session.User) {
+		if userCanSeeDatabase(db, p.session.User, memberOf) {
 			if err := fn(db); err != nil {
 				return err
 			}
@@ -706,6 +755,11 @@ func forEachTableDescWithTableLookup(
 		return nil, nil
 	}
 
+	memberOf, err := p.MemberOf(ctx, p.session.User)
+	if err != nil {
+		return err
+	}
+
 	// Below we use the same trick twice of sorting a slice of strings lexicographically
 	// and iterating through these strings to index into a map. Effectively, this allows
 	// us to iterate through a map in sorted order.
@@ -726,7 +780,7 @@ func forEachTableDescWithTableLookup(
 		sort.Strings(dbTableNames)
 		for _, tableName := range dbTableNames {
 			tableDesc := db.tables[tableName]
-			if userCanSeeTable(tableDesc, p.session.User) {
+			if userCanSeeTable(tableDesc, p.session.User, memberOf) {
 				if err := fn(db.desc, tableDesc, tableLookup); err != nil {
 					return err
 				}
@@ -819,10 +873,14 @@ func forEachUser(ctx context.Context, p *planner, fn func(username string) error
 	return nil
 }
 
-func userCanSeeDatabase(db *sqlbase.DatabaseDescriptor, user string) bool {
-	return userCanSeeDescriptor(db, user)
+func userCanSeeDatabase(
+	db *sqlbase.DatabaseDescriptor, user string, memberOf map[string]bool,
+) bool {
+	return userCanSeeDescriptor(db, user, memberOf)
 }
 
-func userCanSeeTable(table *sqlbase.TableDescriptor, user string) bool {
-	return userCanSeeDescriptor(table, user) && table.State == sqlbase.TableDescriptor_PUBLIC
+func userCanSeeTable(
+	table *sqlbase.TableDescriptor, user string, memberOf map[string]bool,
+) bool {
+	return userCanSeeDescriptor(table, user, memberOf) && table.State == sqlbase.TableDescriptor_PUBLIC
 }
@@ -137,6 +137,15 @@ func (p *planner) Insert(
 		return nil, err
 	}
 
+	policy, err := p.rowLevelSecurityExpr(ctx, en.tableDesc, sqlbase.TableDescriptor_Policy_INSERT, true)
+	if err != nil {
+		return nil, err
+	}
+	if policy != nil && n.OnConflict != nil && !n.OnConflict.DoNothing {
+		return nil, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"UPSERT and INSERT ... ON CONFLICT DO UPDATE are not supported on tables with row level security")
+	}
+
 	var tw tableWriter
 	if n.OnConflict == nil {
 		tw = &tableInserter{ri: ri, autoCommit: p.autoCommit}
@@ -173,6 +182,9 @@ func (p *planner) Insert(
 				if err != nil {
 					return nil, err
 				}
+				if en.tableDesc.IsIndexExprColumn(col.ID) {
+					return nil, indexExprColumnWriteError(col)
+				}
 				updateCols[i] = col
 			}
 
@@ -207,7 +219,7 @@ func (p *planner) Insert(
 		tw: tw,
 	}
 
-	if err := in.checkHelper.init(ctx, p, tn, en.tableDesc); err != nil {
+	if err := in.checkHelper.init(ctx, p, tn, en.tableDesc, policy); err != nil {
 		return nil, err
 	}
 
@@ -252,6 +264,7 @@ func (n *insertNode) Start(ctx context.Context) error {
 
 func (n *insertNode) Close(ctx context.Context) {
 	n.run.rows.Close(ctx)
+	n.tw.close(ctx)
 }
 
 func (n *insertNode) Next(ctx context.Context) (bool, error) {
@@ -377,6 +390,9 @@ func (p *planner) processColumns(
 		if err != nil {
 			return nil, err
 		}
+		if tableDesc.IsIndexExprColumn(col.ID) {
+			return nil, indexExprColumnWriteError(col)
+		}
 
 		if _, ok := colIDSet[col.ID]; ok {
 			return nil, fmt.Errorf("multiple assignments to the same column %q", n)
@@ -388,6 +404,10 @@ func (p *planner) processColumns(
 	return cols, nil
 }
 
+func indexExprColumnWriteError(col sqlbase.ColumnDescriptor) error {
+	return fmt.Errorf("cannot write directly to column %q computed for an expression index", col.Name)
+}
+
 // extractInsertSource removes the parentheses around the data source of an INSERT statement.
 // If the data source is a VALUES clause not further qualified with LIMIT/OFFSET and ORDER BY,
 // the 2nd return value is a pre-casted pointer to the VALUES clause.
@@ -17,6 +17,7 @@
 package jobs
 
 import (
+	"fmt"
 	"time"
 
 	"golang.org/x/net/context"
@@ -77,8 +78,23 @@ const (
 	JobStatusFailed JobStatus = "failed"
 	// JobStatusSucceeded is for jobs that have successfully completed.
 	JobStatusSucceeded JobStatus = "succeeded"
+	// JobStatusPaused is for jobs that are not currently performing work, but
+	// have saved their state and can be resumed by the user later.
+	JobStatusPaused JobStatus = "paused"
 )
 
+// InvalidStatusError is the error returned when the desired operation is
+// invalid given the job's current status.
+type InvalidStatusError struct {
+	id     int64
+	status JobStatus
+	op     string
+}
+
+func (e *InvalidStatusError) Error() string {
+	return fmt.Sprintf("cannot %s %s job (id %d)", e.op, e.status, e.id)
+}
+
 // NewJobLogger creates a new JobLogger.
 func NewJobLogger(db *client.DB, ex sqlutil.InternalExecutor, job JobRecord) *JobLogger {
 	return &JobLogger{
@@ -99,7 +115,7 @@ func GetJobLogger(
 		jobID: &jobID,
 	}
 	if err := jl.runInTxn(ctx, func(ctx context.Context, txn *client.Txn) error {
-		payload, err := jl.retrieveJobPayload(ctx, txn)
+		_, payload, err := jl.retrieveJobRecord(ctx, txn)
 		if err != nil {
 			return err
 		}
@@ -113,6 +129,10 @@ func GetJobLogger(
 			jl.Job.Details = *d.Restore
 		case *JobPayload_SchemaChange:
 			jl.Job.Details = *d.SchemaChange
+		case *JobPayload_Import:
+			jl.Job.Details = *d.Import
+		case *JobPayload_Changefeed:
+			jl.Job.Details = *d.Changefeed
 		default:
 			return errors.Errorf("JobLogger: unsupported job details type %T", d)
 		}
@@ -170,6 +190,10 @@ func (jl *JobLogger) Created(ctx context.Context) error {
 		payload.Details = &JobPayload_Restore{Restore: &d}
 	case SchemaChangeJobDetails:
 		payload.Details = &JobPayload_SchemaChange{SchemaChange: &d}
+	case ImportJobDetails:
+		payload.Details = &JobPayload_Import{Import: &d}
+	case ChangefeedJobDetails:
+		payload.Details = &JobPayload_Changefeed{Changefeed: &d}
 	default:
 		return errors.Errorf("JobLogger: unsupported job details type %T", d)
 	}
@@ -178,7 +202,7 @@ func (jl *JobLogger) Created(ctx context.Context) error {
 
 // Started marks the tracked job as started.
 func (jl *JobLogger) Started(ctx context.Context) error {
-	return jl.updateJobRecord(ctx, JobStatusRunning, func(payload *JobPayload) (bool, error) {
+	return jl.updateJobRecord(ctx, JobStatusRunning, func(_ JobStatus, payload *JobPayload) (bool, error) {
 		if payload.StartedMicros != 0 {
 			// Already started - do nothing.
 			return false, nil
@@ -190,29 +214,113 @@ func (jl *JobLogger) Started(ctx context.Context) error {
 
 // Progressed updates the progress of the tracked job to fractionCompleted. A
 // fractionCompleted that is less than the currently-recorded fractionCompleted
-// will be silently ignored.
+// will be silently ignored. An InvalidStatusError is returned if the job has
+// been paused, so that the caller can stop working on it.
 func (jl *JobLogger) Progressed(ctx context.Context, fractionCompleted float32) error {
+	if err := jl.checkFractionCompleted(fractionCompleted); err != nil {
+		return err
+	}
+	return jl.updateJobRecord(ctx, JobStatusRunning, func(status JobStatus, payload *JobPayload) (bool, error) {
+		if err := jl.checkProgressable(status, payload); err != nil {
+			return false, err
+		}
+		if fractionCompleted <= payload.FractionCompleted {
+			return false, nil
+		}
+		payload.FractionCompleted = fractionCompleted
+		return true, nil
+	})
+}
+
+// DetailProgressed is like Progressed, but the new fraction completed is
+// computed by progressedFn, which may also modify the details of the payload
+// it is passed. The details and the fraction completed are updated in the same
+// transaction, which allows several processes to report their share of a job's
+// progress concurrently.
+func (jl *JobLogger) DetailProgressed(
+	ctx context.Context, progressedFn func(*JobPayload) float32,
+) error {
+	return jl.updateJobRecord(ctx, JobStatusRunning, func(status JobStatus, payload *JobPayload) (bool, error) {
+		if err := jl.checkProgressable(status, payload); err != nil {
+			return false, err
+		}
+		fractionCompleted := progressedFn(payload)
+		if err := jl.checkFractionCompleted(fractionCompleted); err != nil {
+			return false, err
+		}
+		if fractionCompleted > payload.FractionCompleted {
+			payload.FractionCompleted = fractionCompleted
+		}
+		return true, nil
+	})
+}
+
+func (jl *JobLogger) checkFractionCompleted(fractionCompleted float32) error {
 	if fractionCompleted < 0.0 || fractionCompleted > 1.0 {
 		return errors.Errorf(
 			"JobLogger: fractionCompleted %f is outside allowable range [0.0, 1.0] (job %d)",
 			fractionCompleted, jl.jobID,
 		)
 	}
-	return jl.updateJobRecord(ctx, JobStatusRunning, func(payload *JobPayload) (bool, error) {
-		if payload.StartedMicros == 0 {
-			return false, errors.Errorf("JobLogger: job %d not started", jl.jobID)
+	return nil
+}
+
+func (jl *JobLogger) checkProgressable(status JobStatus, payload *JobPayload) error {
+	if status == JobStatusPaused {
+		return &InvalidStatusError{*jl.jobID, status, "update progress on"}
+	}
+	if payload.StartedMicros == 0 {
+		return errors.Errorf("JobLogger: job %d not started", jl.jobID)
+	}
+	if payload.FinishedMicros != 0 {
+		return errors.Errorf("JobLogger: job %d already finished", jl.jobID)
+	}
+	return nil
+}
+
+// Paused marks the tracked job as paused. Only pending and running jobs can be
+// paused. The process running the job is expected to notice the change the next
+// time it reports progress and to stop working on the job until it is resumed.
+func (jl *JobLogger) Paused(ctx context.Context) error {
+	return jl.updateJobRecord(ctx, JobStatusPaused, func(status JobStatus, _ *JobPayload) (bool, error) {
+		if status == JobStatusPaused {
+			// Already paused - do nothing.
+			return false, nil
 		}
-		if payload.FinishedMicros != 0 {
-			return false, errors.Errorf("JobLogger: job %d already finished", jl.jobID)
+		if status != JobStatusPending && status != JobStatusRunning {
+			return false, &InvalidStatusError{*jl.jobID, status, "pause"}
 		}
-		if fractionCompleted <= payload.FractionCompleted {
-			return false, nil
+		return true, nil
+	})
+}
+
+// Resumed marks the tracked job, which must be paused, as running again.
+func (jl *JobLogger) Resumed(ctx context.Context) error {
+	return jl.updateJobRecord(ctx, JobStatusRunning, func(status JobStatus, _ *JobPayload) (bool, error) {
+		if status != JobStatusPaused {
+			return false, &InvalidStatusError{*jl.jobID, status, "resume"}
 		}
-		payload.FractionCompleted = fractionCompleted
 		return true, nil
 	})
 }
 
+// Status returns the status of the tracked job as currently recorded in the
+// system.jobs table.
+func (jl *JobLogger) Status(ctx context.Context) (JobStatus, error) {
+	if jl.jobID == nil {
+		return "", errors.New("JobLogger cannot get status: job not created")
+	}
+	var status JobStatus
+	if err := jl.runInTxn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		var err error
+		status, _, err = jl.retrieveJobRecord(ctx, txn)
+		return err
+	}); err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
 // Failed marks the tracked job as having failed with the given error. Any
 // errors encountered while updating the jobs table are logged but not returned,
 // under the assumption that the the caller is already handling a more important
@@ -223,7 +331,7 @@ func (jl *JobLogger) Failed(ctx context.Context, err error) {
 	if jl.jobID == nil {
 		return
 	}
-	internalErr := jl.updateJobRecord(ctx, JobStatusFailed, func(payload *JobPayload) (bool, error) {
+	internalErr := jl.updateJobRecord(ctx, JobStatusFailed, func(_ JobStatus, payload *JobPayload) (bool, error) {
 		if payload.FinishedMicros != 0 {
 			// Already finished - do nothing.
 			return false, nil
@@ -241,7 +349,7 @@ func (jl *JobLogger) Failed(ctx context.Context, err error) {
 // Succeeded marks the tracked job as having succeeded and sets its fraction
 // completed to 1.0.
 func (jl *JobLogger) Succeeded(ctx context.Context) error {
-	return jl.updateJobRecord(ctx, JobStatusSucceeded, func(payload *JobPayload) (bool, error) {
+	return jl.updateJobRecord(ctx, JobStatusSucceeded, func(_ JobStatus, payload *JobPayload) (bool, error) {
 		if payload.FinishedMicros != 0 {
 			// Already finished - do nothing.
 			return false, nil
@@ -286,18 +394,33 @@ func (jl *JobLogger) insertJobRecord(ctx context.Context, payload *JobPayload) e
 	return nil
 }
 
-func (jl *JobLogger) retrieveJobPayload(ctx context.Context, txn *client.Txn) (*JobPayload, error) {
-	const selectStmt = "SELECT payload FROM system.jobs WHERE id = $1"
+func (jl *JobLogger) retrieveJobRecord(
+	ctx context.Context, txn *client.Txn,
+) (JobStatus, *JobPayload, error) {
+	const selectStmt = "SELECT status, payload FROM system.jobs WHERE id = $1"
 	row, err := jl.ex.QueryRowInTransaction(ctx, "log-job", txn, selectStmt, *jl.jobID)
 	if err != nil {
-		return nil, err
+		return "", nil, err
+	}
+	if row == nil {
+		return "", nil, errors.Errorf("JobLogger: job %d not found", *jl.jobID)
 	}
 
-	return UnmarshalJobPayload(row[0])
+	statusString, ok := row[0].(*parser.DString)
+	if !ok {
+		return "", nil, errors.Errorf("JobLogger: job %d: expected string status, but got %T", *jl.jobID, row[0])
+	}
+	payload, err := UnmarshalJobPayload(row[1])
+	if err != nil {
+		return "", nil, err
+	}
+	return JobStatus(*statusString), payload, nil
 }
 
 func (jl *JobLogger) updateJobRecord(
-	ctx context.Context, newStatus JobStatus, updateFn func(*JobPayload) (doUpdate bool, err error),
+	ctx context.Context,
+	newStatus JobStatus,
+	updateFn func(JobStatus, *JobPayload) (doUpdate bool, err error),
 ) error {
 	if jl.jobID == nil {
 		return errors.New("JobLogger cannot update job: job not created")
@@ -305,12 +428,13 @@ func (jl *JobLogger) updateJobRecord(
 
 	var payload *JobPayload
 	if err := jl.runInTxn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		var status JobStatus
 		var err error
-		payload, err = jl.retrieveJobPayload(ctx, txn)
+		status, payload, err = jl.retrieveJobRecord(ctx, txn)
 		if err != nil {
 			return err
 		}
-		doUpdate, err := updateFn(payload)
+		doUpdate, err := updateFn(status, payload)
 		if err != nil {
 			return err
 		}
@@ -349,6 +473,8 @@ const (
 	JobTypeBackup       string = "BACKUP"
 	JobTypeRestore      string = "RESTORE"
 	JobTypeSchemaChange string = "SCHEMA CHANGE"
+	JobTypeImport       string = "IMPORT"
+	JobTypeChangefeed   string = "CHANGEFEED"
 )
 
 // Typ returns the payload's job type.
@@ -360,6 +486,10 @@ func (jp *JobPayload) Typ() string {
 		return JobTypeRestore
 	case *JobPayload_SchemaChange:
 		return JobTypeSchemaChange
+	case *JobPayload_Import:
+		return JobTypeImport
+	case *JobPayload_Changefeed:
+		return JobTypeChangefeed
 	default:
 		panic("JobPayload.Typ called on a payload with an unknown details type")
 	}
@@ -390,3 +520,5 @@ func jobTimestamp(ts time.Time) int64 {
 var _ JobDetails = BackupJobDetails{}
 var _ JobDetails = RestoreJobDetails{}
 var _ JobDetails = SchemaChangeJobDetails{}
+var _ JobDetails = ImportJobDetails{}
+var _ JobDetails = ChangefeedJobDetails{}
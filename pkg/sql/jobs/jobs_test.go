@@ -142,7 +142,7 @@ func verifyJobRecord(
 	if started.Valid && created.Time.After(started.Time) {
 		return errors.Errorf("created time %v is after started time %v", created, started)
 	}
-	if status == jobs.JobStatusRunning {
+	if status == jobs.JobStatusRunning || status == jobs.JobStatusPaused {
 		return verifyModifiedAgainst("started", started.Time)
 	}
 
@@ -418,4 +418,93 @@ func TestJobLogger(t *testing.T) {
 			t.Fatal(err)
 		}
 	})
+
+	t.Run("paused jobs refuse progress until resumed", func(t *testing.T) {
+		db := sqlutils.MakeSQLRunner(t, rawSQLDB)
+		job := jobs.JobRecord{Details: jobs.ImportJobDetails{}}
+		expectation := jobExpectation{
+			Job:               job,
+			Type:              jobs.JobTypeImport,
+			Before:            timeutil.Now(),
+			FractionCompleted: 0.2,
+		}
+		logger := jobs.NewJobLogger(kvDB, executor, job)
+		if err := logger.Created(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err := logger.Resumed(ctx); !testutils.IsError(err, `cannot resume pending job`) {
+			t.Fatalf("expected 'cannot resume pending job' error, but got %v", err)
+		}
+		if err := logger.Started(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err := logger.Progressed(ctx, 0.2); err != nil {
+			t.Fatal(err)
+		}
+		if err := logger.Paused(ctx); err != nil {
+			t.Fatal(err)
+		}
+		// Pausing twice is fine.
+		if err := logger.Paused(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err := verifyJobRecord(db, kvDB, executor, logger, jobs.JobStatusPaused, expectation); err != nil {
+			t.Fatal(err)
+		}
+		if status, err := logger.Status(ctx); err != nil {
+			t.Fatal(err)
+		} else if status != jobs.JobStatusPaused {
+			t.Fatalf("expected status %s, but got %s", jobs.JobStatusPaused, status)
+		}
+		if err := logger.Progressed(ctx, 0.5); !testutils.IsError(err, `cannot update progress on paused job`) {
+			t.Fatalf("expected 'cannot update progress on paused job' error, but got %v", err)
+		}
+		if err := logger.Resumed(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err := logger.Progressed(ctx, 0.5); err != nil {
+			t.Fatal(err)
+		}
+		if err := logger.Succeeded(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err := logger.Paused(ctx); !testutils.IsError(err, `cannot pause succeeded job`) {
+			t.Fatalf("expected 'cannot pause succeeded job' error, but got %v", err)
+		}
+	})
+
+	t.Run("detail progress is updated with the fraction completed", func(t *testing.T) {
+		logger := jobs.NewJobLogger(kvDB, executor, jobs.JobRecord{
+			Details: jobs.ImportJobDetails{},
+		})
+		if err := logger.Created(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err := logger.Started(ctx); err != nil {
+			t.Fatal(err)
+		}
+		for slot, f := range []float32{0.25, 0.5} {
+			if err := logger.DetailProgressed(ctx, func(payload *jobs.JobPayload) float32 {
+				details := payload.GetImport()
+				for len(details.Progress) <= slot {
+					details.Progress = append(details.Progress, 0)
+				}
+				details.Progress[slot] = f
+				var sum float32
+				for _, p := range details.Progress {
+					sum += p
+				}
+				return sum
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		payload := logger.Payload()
+		if e, a := []float32{0.25, 0.5}, payload.GetImport().Progress; !reflect.DeepEqual(e, a) {
+			t.Fatalf("expected progress %v, but got %v", e, a)
+		}
+		if e, a := float32(0.75), payload.FractionCompleted; e != a {
+			t.Fatalf("expected fraction completed %f, but got %f", e, a)
+		}
+	})
 }
@@ -0,0 +1,426 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package jobs
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// ScheduleStatus represents the status of a schedule in the system.schedules
+// table.
+type ScheduleStatus string
+
+const (
+	// ScheduleStatusActive is for schedules that are run whenever they are due.
+	ScheduleStatusActive ScheduleStatus = "active"
+	// ScheduleStatusPaused is for schedules that are not run until they are
+	// resumed by the user.
+	ScheduleStatusPaused ScheduleStatus = "paused"
+)
+
+// Schedule types are named for the SQL statement that they run.
+const (
+	ScheduleTypeBackup string = "BACKUP"
+)
+
+// Typ returns the payload's schedule type.
+func (sp *SchedulePayload) Typ() string {
+	switch sp.Details.(type) {
+	case *SchedulePayload_Backup:
+		return ScheduleTypeBackup
+	default:
+		panic("SchedulePayload.Typ called on a payload with an unknown details type")
+	}
+}
+
+// UnmarshalSchedulePayload unmarshals and returns the SchedulePayload encoded
+// in the input datum, which should be a DBytes.
+func UnmarshalSchedulePayload(datum parser.Datum) (*SchedulePayload, error) {
+	payload := &SchedulePayload{}
+	bytes, ok := datum.(*parser.DBytes)
+	if !ok {
+		return nil, errors.Errorf(
+			"failed to unmarshal schedule payload as DBytes (was %T)", datum)
+	}
+	if err := proto.Unmarshal([]byte(*bytes), payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// CreateSchedule inserts an active schedule, first due at nextRun, into the
+// system.schedules table and returns its ID.
+func CreateSchedule(
+	ctx context.Context,
+	ex sqlutil.InternalExecutor,
+	txn *client.Txn,
+	payload *SchedulePayload,
+	nextRun time.Time,
+) (int64, error) {
+	payloadBytes, err := protoutil.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+	const stmt = `INSERT INTO system.schedules (status, next_run, payload) VALUES ($1, $2, $3) RETURNING id`
+	row, err := ex.QueryRowInTransaction(
+		ctx, "schedule-insert", txn, stmt, ScheduleStatusActive, nextRun, payloadBytes)
+	if err != nil {
+		return 0, err
+	}
+	return int64(*row[0].(*parser.DInt)), nil
+}
+
+// GetSchedule returns the status and payload of the schedule with the given
+// ID.
+func GetSchedule(
+	ctx context.Context, ex sqlutil.InternalExecutor, txn *client.Txn, id int64,
+) (ScheduleStatus, *SchedulePayload, error) {
+	const stmt = `SELECT status, payload FROM system.schedules WHERE id = $1`
+	row, err := ex.QueryRowInTransaction(ctx, "schedule-get", txn, stmt, id)
+	if err != nil {
+		return "", nil, err
+	}
+	if row == nil {
+		return "", nil, errors.Errorf("schedule %d not found", id)
+	}
+	payload, err := UnmarshalSchedulePayload(row[1])
+	if err != nil {
+		return "", nil, err
+	}
+	return ScheduleStatus(*row[0].(*parser.DString)), payload, nil
+}
+
+// UpdateSchedule sets the status, next run time and payload of the schedule
+// with the given ID.
+func UpdateSchedule(
+	ctx context.Context,
+	ex sqlutil.InternalExecutor,
+	txn *client.Txn,
+	id int64,
+	status ScheduleStatus,
+	nextRun time.Time,
+	payload *SchedulePayload,
+) error {
+	payloadBytes, err := protoutil.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	const stmt = `UPDATE system.schedules SET status = $2, next_run = $3, payload = $4 WHERE id = $1`
+	n, err := ex.ExecuteStatementInTransaction(
+		ctx, "schedule-update", txn, stmt, id, status, nextRun, payloadBytes)
+	if err != nil {
+		return err
+	}
+	if n != 1 {
+		return errors.Errorf("expected exactly one row affected, but %d rows affected by schedule update", n)
+	}
+	return nil
+}
+
+// DeleteSchedule removes the schedule with the given ID.
+func DeleteSchedule(
+	ctx context.Context, ex sqlutil.InternalExecutor, txn *client.Txn, id int64,
+) error {
+	const stmt = `DELETE FROM system.schedules WHERE id = $1`
+	_, err := ex.ExecuteStatementInTransaction(ctx, "schedule-delete", txn, stmt, id)
+	return err
+}
+
+// ScheduleRunExpiration is how long after the last heartbeat of a run of a
+// schedule the run is assumed to have died (e.g. with the node running it).
+// Until then, occurrences of the schedule that come due are skipped rather
+// than run concurrently with it.
+const ScheduleRunExpiration = 5 * time.Minute
+
+// ClaimDueSchedule finds an active schedule that was due to run at or before
+// now and advances its next run time to its next occurrence after now, so that
+// the occurrence is only claimed once across the cluster. It returns the ID and
+// payload of the claimed schedule, or a nil payload if no schedule is due.
+//
+// Occurrences of a schedule whose previous run is still in progress are
+// skipped, so that runs of a schedule never overlap. The claimed run must
+// call HeartbeatScheduleRun more often than ScheduleRunExpiration while it
+// runs, and SaveScheduleRun once it is done.
+func ClaimDueSchedule(
+	ctx context.Context, db *client.DB, ex sqlutil.InternalExecutor, now time.Time,
+) (int64, *SchedulePayload, error) {
+	var id int64
+	var payload *SchedulePayload
+	err := db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		id, payload = 0, nil
+		for {
+			const stmt = `SELECT id, payload FROM system.schedules
+WHERE status = $1 AND next_run <= $2 ORDER BY next_run LIMIT 1`
+			row, err := ex.QueryRowInTransaction(ctx, "schedule-claim", txn, stmt, ScheduleStatusActive, now)
+			if err != nil || row == nil {
+				return err
+			}
+			dueID := int64(*row[0].(*parser.DInt))
+			p, err := UnmarshalSchedulePayload(row[1])
+			if err != nil {
+				return err
+			}
+			recurrence, err := ParseRecurrence(p.Recurrence)
+			if err != nil {
+				return err
+			}
+			running := p.HeartbeatMicros != 0 &&
+				jobTimestamp(now)-p.HeartbeatMicros < ScheduleRunExpiration.Nanoseconds()/1000
+			if !running {
+				p.LastRunMicros = jobTimestamp(now)
+				p.HeartbeatMicros = p.LastRunMicros
+			}
+			if err := UpdateSchedule(
+				ctx, ex, txn, dueID, ScheduleStatusActive, recurrence.Next(now), p,
+			); err != nil {
+				return err
+			}
+			if !running {
+				id, payload = dueID, p
+				return nil
+			}
+		}
+	})
+	return id, payload, err
+}
+
+// getScheduleRun returns the payload of the schedule with the given ID, after
+// checking that the run that started at lastRunMicros is still its most
+// recent run.
+func getScheduleRun(
+	ctx context.Context, ex sqlutil.InternalExecutor, txn *client.Txn, id int64, lastRunMicros int64,
+) (*SchedulePayload, error) {
+	const stmt = `SELECT payload FROM system.schedules WHERE id = $1`
+	row, err := ex.QueryRowInTransaction(ctx, "schedule-get-run", txn, stmt, id)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil {
+		return nil, errors.Errorf("schedule %d not found", id)
+	}
+	p, err := UnmarshalSchedulePayload(row[0])
+	if err != nil {
+		return nil, err
+	}
+	if p.LastRunMicros != lastRunMicros {
+		return nil, errors.Errorf(
+			"schedule %d was run again at %d while its run at %d was in progress",
+			id, p.LastRunMicros, lastRunMicros)
+	}
+	return p, nil
+}
+
+func saveSchedulePayload(
+	ctx context.Context, ex sqlutil.InternalExecutor, txn *client.Txn, id int64, payload *SchedulePayload,
+) error {
+	payloadBytes, err := protoutil.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	const stmt = `UPDATE system.schedules SET payload = $2 WHERE id = $1`
+	_, err = ex.ExecuteStatementInTransaction(ctx, "schedule-save-run", txn, stmt, id, payloadBytes)
+	return err
+}
+
+// HeartbeatScheduleRun records that the run of a schedule claimed with
+// ClaimDueSchedule, which started at lastRunMicros, is still in progress. It
+// returns an error if the schedule has since been run again, e.g. because the
+// run missed its heartbeats for longer than ScheduleRunExpiration.
+func HeartbeatScheduleRun(
+	ctx context.Context,
+	db *client.DB,
+	ex sqlutil.InternalExecutor,
+	id int64,
+	lastRunMicros int64,
+	now time.Time,
+) error {
+	return db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		p, err := getScheduleRun(ctx, ex, txn, id, lastRunMicros)
+		if err != nil {
+			return err
+		}
+		p.HeartbeatMicros = jobTimestamp(now)
+		return saveSchedulePayload(ctx, ex, txn, id, p)
+	})
+}
+
+// SaveScheduleRun records the payload of a schedule after one of its
+// occurrences was run, without changing its status or next run time, and marks
+// the run as no longer in progress. It returns an error without saving the
+// payload if the schedule has since been run again, as the payload would then
+// overwrite the results of that run.
+func SaveScheduleRun(
+	ctx context.Context, db *client.DB, ex sqlutil.InternalExecutor, id int64, payload *SchedulePayload,
+) error {
+	return db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		if _, err := getScheduleRun(ctx, ex, txn, id, payload.LastRunMicros); err != nil {
+			return err
+		}
+		payload.HeartbeatMicros = 0
+		return saveSchedulePayload(ctx, ex, txn, id, payload)
+	})
+}
+
+// Recurrence is a parsed crontab expression, describing the times at which a
+// schedule runs. All times are in UTC.
+type Recurrence struct {
+	minute, hour, dom, month, dow uint64
+	// If either of the day-of-month or day-of-week fields is restricted (i.e.
+	// not "*"), a day matches if it matches either of the restricted fields, as
+	// in cron(8).
+	domStar, dowStar bool
+}
+
+var recurrenceMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// recurrenceSearchLimit bounds how far ahead Next looks for an occurrence.
+const recurrenceSearchLimit = 5 * 366 * 24 * time.Hour
+
+// ParseRecurrence parses a crontab expression made of five space-separated
+// fields (minute, hour, day of month, month and day of week), each a
+// comma-separated list of "*", numbers or ranges with an optional "/step", or
+// one of the @yearly, @monthly, @weekly, @daily and @hourly macros.
+func ParseRecurrence(expr string) (*Recurrence, error) {
+	spec := strings.TrimSpace(expr)
+	if macro, ok := recurrenceMacros[strings.ToLower(spec)]; ok {
+		spec = macro
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, errors.Errorf("invalid recurrence %q: expected 5 fields or a macro such as @daily", expr)
+	}
+	var r Recurrence
+	var err error
+	for i, f := range []struct {
+		name     string
+		min, max int
+		bits     *uint64
+		star     *bool
+	}{
+		{name: "minute", min: 0, max: 59, bits: &r.minute},
+		{name: "hour", min: 0, max: 23, bits: &r.hour},
+		{name: "day of month", min: 1, max: 31, bits: &r.dom, star: &r.domStar},
+		{name: "month", min: 1, max: 12, bits: &r.month},
+		{name: "day of week", min: 0, max: 7, bits: &r.dow, star: &r.dowStar},
+	} {
+		if *f.bits, err = parseRecurrenceField(fields[i], f.min, f.max); err != nil {
+			return nil, errors.Wrapf(err, "invalid recurrence %q: %s", expr, f.name)
+		}
+		if f.star != nil {
+			*f.star = fields[i] == "*"
+		}
+	}
+	// Both 0 and 7 are Sunday.
+	if r.dow&(1<<7) != 0 {
+		r.dow |= 1
+	}
+	if r.Next(time.Unix(0, 0)).IsZero() {
+		return nil, errors.Errorf("invalid recurrence %q: never occurs", expr)
+	}
+	return &r, nil
+}
+
+func parseRecurrenceField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, errors.Errorf("invalid step in %q", part)
+			}
+			rng = part[:i]
+		}
+		lo, hi := min, max
+		if rng != "*" {
+			var err error
+			bounds := strings.SplitN(rng, "-", 2)
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, errors.Errorf("invalid value %q", part)
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, errors.Errorf("invalid value %q", part)
+				}
+			} else if step != 1 {
+				// "N/step" means from N to the maximum.
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, errors.Errorf("%q out of range [%d, %d]", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the first time strictly after t at which the recurrence occurs,
+// or the zero time if it does not occur in the following five years.
+func (r *Recurrence) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(recurrenceSearchLimit)
+	for t.Before(limit) {
+		if r.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !r.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if r.hour&(1<<uint(t.Hour())) == 0 {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if r.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (r *Recurrence) matchesDay(t time.Time) bool {
+	dom := r.dom&(1<<uint(t.Day())) != 0
+	dow := r.dow&(1<<uint(t.Weekday())) != 0
+	if r.domStar || r.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,179 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package jobs_test
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/jobs"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+func TestRecurrence(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// 2017-06-14 is a Wednesday.
+	from := time.Date(2017, 6, 14, 10, 30, 15, 0, time.UTC)
+	for _, tc := range []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"@hourly", time.Date(2017, 6, 14, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2017, 6, 15, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2017, 6, 18, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2017, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"* * * * *", time.Date(2017, 6, 14, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2017, 6, 14, 10, 45, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2017, 6, 15, 10, 30, 0, 0, time.UTC)},
+		{"0 2,14 * * *", time.Date(2017, 6, 14, 14, 0, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2017, 6, 14, 13, 0, 0, 0, time.UTC)},
+		{"0 0 * * 1-5", time.Date(2017, 6, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2017, 6, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 * *", time.Date(2017, 7, 31, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Restricting both the day of month and day of week matches either.
+		{"0 0 1 * 5", time.Date(2017, 6, 16, 0, 0, 0, 0, time.UTC)},
+	} {
+		r, err := jobs.ParseRecurrence(tc.expr)
+		if err != nil {
+			t.Errorf("%q: %v", tc.expr, err)
+			continue
+		}
+		if next := r.Next(from); !next.Equal(tc.expected) {
+			t.Errorf("%q: expected next occurrence %s, got %s", tc.expr, tc.expected, next)
+		}
+	}
+
+	for _, tc := range []struct {
+		expr     string
+		expected string
+	}{
+		{"", "expected 5 fields"},
+		{"@sometimes", "expected 5 fields"},
+		{"* * * *", "expected 5 fields"},
+		{"60 * * * *", "out of range"},
+		{"* * 0 * *", "out of range"},
+		{"5-1 * * * *", "out of range"},
+		{"*/0 * * * *", "invalid step"},
+		{"a * * * *", "invalid value"},
+		{"0 0 30 2 *", "never occurs"},
+	} {
+		if _, err := jobs.ParseRecurrence(tc.expr); !testutils.IsError(err, tc.expected) {
+			t.Errorf("%q: expected error %q, got %v", tc.expr, tc.expected, err)
+		}
+	}
+}
+
+func TestClaimDueScheduleInFlight(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	// Keep the server's own scheduler from claiming the schedule.
+	defer settings.TestingSetDuration(&sql.SchedulerInterval, time.Hour)()
+
+	ctx := context.TODO()
+	s, _, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	ex := sql.InternalExecutor{LeaseManager: s.LeaseManager().(*sql.LeaseManager)}
+
+	now := timeutil.Now().Truncate(time.Minute)
+	var id int64
+	if err := kvDB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		var err error
+		id, err = jobs.CreateSchedule(ctx, ex, txn, &jobs.SchedulePayload{
+			Name:       "every minute",
+			Recurrence: "* * * * *",
+			Details:    &jobs.SchedulePayload_Backup{Backup: &jobs.BackupScheduleDetails{}},
+		}, now.Add(-time.Minute))
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	claim := func(at time.Time) *jobs.SchedulePayload {
+		claimedID, payload, err := jobs.ClaimDueSchedule(ctx, kvDB, ex, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if payload != nil && claimedID != id {
+			t.Fatalf("expected schedule %d to be claimed, got %d", id, claimedID)
+		}
+		return payload
+	}
+
+	first := claim(now)
+	if first == nil {
+		t.Fatal("expected the due schedule to be claimed")
+	}
+	if first.HeartbeatMicros != first.LastRunMicros {
+		t.Fatalf("expected the claimed run to be in progress, got %+v", first)
+	}
+
+	// The next occurrence is skipped while the first run is in progress, and
+	// heartbeats keep it from expiring.
+	if err := jobs.HeartbeatScheduleRun(
+		ctx, kvDB, ex, id, first.LastRunMicros, now.Add(4*time.Minute),
+	); err != nil {
+		t.Fatal(err)
+	}
+	if p := claim(now.Add(8 * time.Minute)); p != nil {
+		t.Fatalf("expected the schedule not to be claimed while it runs, got %+v", p)
+	}
+
+	// Once the heartbeats stop for long enough, the run is assumed dead and the
+	// schedule is claimed again.
+	second := claim(now.Add(10 * time.Minute))
+	if second == nil {
+		t.Fatal("expected the schedule to be claimed after its run expired")
+	}
+
+	// The expired run can no longer heartbeat or overwrite the second run.
+	if err := jobs.HeartbeatScheduleRun(
+		ctx, kvDB, ex, id, first.LastRunMicros, now.Add(10*time.Minute),
+	); !testutils.IsError(err, "was run again") {
+		t.Fatalf("expected heartbeat of expired run to fail, got %v", err)
+	}
+	if err := jobs.SaveScheduleRun(ctx, kvDB, ex, id, first); !testutils.IsError(err, "was run again") {
+		t.Fatalf("expected save of expired run to fail, got %v", err)
+	}
+	if err := jobs.SaveScheduleRun(ctx, kvDB, ex, id, second); err != nil {
+		t.Fatal(err)
+	}
+	var saved *jobs.SchedulePayload
+	if err := kvDB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		var err error
+		_, saved, err = jobs.GetSchedule(ctx, ex, txn, id)
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if saved.LastRunMicros != second.LastRunMicros || saved.HeartbeatMicros != 0 {
+		t.Fatalf("expected the second run to be saved and finished, got %+v", saved)
+	}
+
+	// With no run in progress, the next occurrence is claimed.
+	if p := claim(now.Add(11 * time.Minute)); p == nil {
+		t.Fatal("expected the schedule to be claimed after its run finished")
+	}
+}
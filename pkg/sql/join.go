@@ -35,6 +35,13 @@ const (
 	joinTypeLeftOuter
 	joinTypeRightOuter
 	joinTypeFullOuter
+	// joinTypeSemi and joinTypeAnti are not available in SQL syntax;
+	// they are produced by the decorrelation of sub-queries. A semi-join
+	// returns the rows of the left operand that match at least one
+	// row of the right operand, and an anti-join the rows that match
+	// none. Only the columns of the left operand are returned.
+	joinTypeSemi
+	joinTypeAnti
 )
 
 // bucket here is the set of rows for a given group key (comprised of
@@ -121,8 +128,8 @@ func (b *buckets) Fetch(encoding []byte) (*bucket, bool) {
 	return bk, ok
 }
 
-// joinNode is a planNode whose rows are the result of an inner or
-// left/right outer join.
+// joinNode is a planNode whose rows are the result of an inner,
+// left/right outer, semi- or anti-join.
 type joinNode struct {
 	planner  *planner
 	joinType joinType
@@ -190,32 +197,28 @@ func commonColumns(left, right *dataSourceInfo) parser.NameList {
 	return res
 }
 
-// makeJoin constructs a planDataSource for a JOIN node.
-// The tableInfo field from the left node is taken over (overwritten)
-// by the new node.
-func (p *planner) makeJoin(
-	ctx context.Context,
-	astJoinType string,
-	left planDataSource,
-	right planDataSource,
-	cond parser.JoinCond,
-) (planDataSource, error) {
-	var typ joinType
+// joinTypeFromAST converts the join type name from the syntax tree
+// to a joinType.
+func joinTypeFromAST(astJoinType string) (joinType, error) {
 	switch astJoinType {
 	case "JOIN", "INNER JOIN", "CROSS JOIN":
-		typ = joinTypeInner
+		return joinTypeInner, nil
 	case "LEFT JOIN":
-		typ = joinTypeLeftOuter
+		return joinTypeLeftOuter, nil
 	case "RIGHT JOIN":
-		typ = joinTypeRightOuter
+		return joinTypeRightOuter, nil
 	case "FULL JOIN":
-		typ = joinTypeFullOuter
+		return joinTypeFullOuter, nil
 	default:
-		return planDataSource{}, errors.Errorf("unsupported JOIN type %T", astJoinType)
+		return 0, errors.Errorf("unsupported JOIN type %T", astJoinType)
 	}
+}
 
-	leftInfo, rightInfo := left.info, right.info
-
+// makeJoinPredicate constructs the predicate and the result
+// columns for a join between the given operands.
+func (p *planner) makeJoinPredicate(
+	ctx context.Context, leftInfo, rightInfo *dataSourceInfo, cond parser.JoinCond,
+) (*joinPredicate, *dataSourceInfo, error) {
 	// Check that the same table name is not used on both sides.
 	for _, alias := range rightInfo.sourceAliases {
 		if _, ok := leftInfo.sourceAliases.srcIdx(alias.name); ok {
@@ -227,33 +230,63 @@ func (p *planner) makeJoin(
 				// ambiguity later.
 				continue
 			}
-			return planDataSource{}, fmt.Errorf(
+			return nil, nil, fmt.Errorf(
 				"cannot join columns from the same source name %q (missing AS clause)", t)
 		}
 	}
 
-	var (
-		info *dataSourceInfo
-		pred *joinPredicate
-		err  error
-	)
-
 	if cond == nil {
-		pred, info, err = makeCrossPredicate(leftInfo, rightInfo)
-	} else {
-		switch t := cond.(type) {
-		case *parser.OnJoinCond:
-			pred, info, err = p.makeOnPredicate(ctx, leftInfo, rightInfo, t.Expr)
-		case parser.NaturalJoinCond:
-			cols := commonColumns(leftInfo, rightInfo)
-			pred, info, err = makeUsingPredicate(leftInfo, rightInfo, cols)
-		case *parser.UsingJoinCond:
-			pred, info, err = makeUsingPredicate(leftInfo, rightInfo, t.Cols)
-		}
+		return makeCrossPredicate(leftInfo, rightInfo)
+	}
+	switch t := cond.(type) {
+	case *parser.OnJoinCond:
+		return p.makeOnPredicate(ctx, leftInfo, rightInfo, t.Expr)
+	case parser.NaturalJoinCond:
+		cols := commonColumns(leftInfo, rightInfo)
+		return makeUsingPredicate(leftInfo, rightInfo, cols)
+	case *parser.UsingJoinCond:
+		return makeUsingPredicate(leftInfo, rightInfo, t.Cols)
+	default:
+		return nil, nil, errors.Errorf("unsupported JOIN condition %T", cond)
+	}
+}
+
+// makeJoin constructs a planDataSource for a JOIN node.
+// The tableInfo field from the left node is taken over (overwritten)
+// by the new node.
+func (p *planner) makeJoin(
+	ctx context.Context,
+	astJoinType string,
+	left planDataSource,
+	right planDataSource,
+	cond parser.JoinCond,
+) (planDataSource, error) {
+	typ, err := joinTypeFromAST(astJoinType)
+	if err != nil {
+		return planDataSource{}, err
 	}
+
+	pred, info, err := p.makeJoinPredicate(ctx, left.info, right.info, cond)
 	if err != nil {
 		return planDataSource{}, err
 	}
+	return p.makeJoinNode(typ, left, right, pred, info), nil
+}
+
+// makeJoinNode constructs a planDataSource for a JOIN node given an
+// already constructed predicate. info describes the result of the
+// join; it is ignored for semi- and anti-joins, which produce the
+// columns of the left operand.
+func (p *planner) makeJoinNode(
+	typ joinType, left planDataSource, right planDataSource, pred *joinPredicate, info *dataSourceInfo,
+) planDataSource {
+	var columns sqlbase.ResultColumns
+	if typ == joinTypeSemi || typ == joinTypeAnti {
+		info = left.info
+		columns = append(columns, info.sourceColumns...)
+	} else {
+		columns = info.sourceColumns
+	}
 
 	n := &joinNode{
 		planner:  p,
@@ -261,7 +294,7 @@ func (p *planner) makeJoin(
 		right:    right,
 		joinType: typ,
 		pred:     pred,
-		columns:  info.sourceColumns,
+		columns:  columns,
 	}
 
 	n.buffer = &RowBuffer{
@@ -283,7 +316,7 @@ func (p *planner) makeJoin(
 	return planDataSource{
 		info: info,
 		plan: n,
-	}, nil
+	}
 }
 
 // Ordering implements the planNode interface.
@@ -313,8 +346,10 @@ func (n *joinNode) Start(ctx context.Context) error {
 		}
 	}
 
-	// Pre-allocate the space for output rows.
-	n.output = make(parser.Datums, len(n.columns))
+	// Pre-allocate the space for output rows. For semi- and anti-joins,
+	// this is only used to evaluate the predicate and thus must be
+	// able to hold the columns of both operands.
+	n.output = make(parser.Datums, len(n.pred.info.sourceColumns))
 
 	// If needed, pre-allocate left and right rows of NULL tuples for when the
 	// join predicate fails to match.
@@ -400,7 +435,7 @@ func (n *joinNode) Next(ctx context.Context) (res bool, err error) {
 	wantUnmatchedRight := n.joinType == joinTypeRightOuter || n.joinType == joinTypeFullOuter
 
 	if len(n.buckets.Buckets()) == 0 {
-		if !wantUnmatchedLeft {
+		if !wantUnmatchedLeft && n.joinType != joinTypeAnti {
 			// No rows on right; don't even try.
 			return false, nil
 		}
@@ -423,6 +458,22 @@ func (n *joinNode) Next(ctx context.Context) (res bool, err error) {
 			return false, err
 		}
 
+		if n.joinType == joinTypeSemi || n.joinType == joinTypeAnti {
+			// The left row is emitted as-is, at most once.
+			foundMatch, err := n.hasMatch(lrow, encoding, containsNull)
+			if err != nil {
+				return false, err
+			}
+			if foundMatch == (n.joinType == joinTypeSemi) {
+				if _, err := n.buffer.AddRow(ctx, lrow); err != nil {
+					return false, err
+				}
+				return n.buffer.Next(), nil
+			}
+			scratch = encoding[:0]
+			continue
+		}
+
 		// We make the explicit check for whether or not lrow contained a NULL
 		// tuple. The reasoning here is because of the way we expect NULL
 		// equality checks to behave (i.e. NULL != NULL) and the fact that we
@@ -557,6 +608,27 @@ func (n *joinNode) Next(ctx context.Context) (res bool, err error) {
 	return n.buffer.Next(), nil
 }
 
+// hasMatch returns true if the given left row, whose equality columns
+// have the given encoding, matches at least one row of the right
+// operand.
+func (n *joinNode) hasMatch(lrow parser.Datums, encoding []byte, containsNull bool) (bool, error) {
+	if containsNull {
+		// NULL never compares equal.
+		return false, nil
+	}
+	b, ok := n.buckets.Fetch(encoding)
+	if !ok {
+		return false, nil
+	}
+	for _, rrow := range b.Rows() {
+		passesOnCond, err := n.pred.eval(&n.planner.evalCtx, n.output, lrow, rrow)
+		if err != nil || passesOnCond {
+			return passesOnCond, err
+		}
+	}
+	return false, nil
+}
+
 // Values implements the planNode interface.
 func (n *joinNode) Values() parser.Datums {
 	return n.buffer.Values()
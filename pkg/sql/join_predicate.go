@@ -480,5 +480,8 @@ func pickUsingColumn(
 	if idx == invalidColIdx {
 		return idx, nil, fmt.Errorf("column \"%s\" specified in USING clause does not exist in %s table", colName, context)
 	}
+	if cols[idx].Denied {
+		return idx, nil, newColumnPrivilegeError(cols[idx].Name)
+	}
 	return idx, cols[idx].Typ, nil
 }
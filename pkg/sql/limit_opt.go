@@ -127,6 +127,9 @@ func applyLimit(plan planNode, numRows int64, soft bool) {
 		setUnlimited(n.left.plan)
 		setUnlimited(n.right.plan)
 
+	case *applyJoinNode:
+		setUnlimited(n.left.plan)
+
 	case *ordinalityNode:
 		applyLimit(n.source, numRows, soft)
 
@@ -164,17 +167,27 @@ func applyLimit(plan planNode, numRows int64, soft bool) {
 	case *relocateNode:
 		setUnlimited(n.rows)
 
+	case *copyToNode:
+		setUnlimited(n.source)
+
 	case *valuesNode:
 	case *alterTableNode:
 	case *copyNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
+	case *createPolicyNode:
+	case *createRoleNode:
 	case *createUserNode:
+	case *alterUserNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
+	case *dropPolicyNode:
+	case *dropRoleNode:
 	case *dropUserNode:
+	case *grantRoleNode:
+	case *revokeRoleNode:
 	case *emptyNode:
 	case *hookFnNode:
 	case *valueGenerator:
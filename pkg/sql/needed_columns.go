@@ -72,10 +72,24 @@ func setNeededColumns(plan planNode, needed []bool) {
 		setNeededColumns(n.left, needed)
 		setNeededColumns(n.right, needed)
 
+	case *applyJoinNode:
+		// The right operand may refer to any column of the left
+		// operand, and it is re-planned with all its columns for every
+		// left row.
+		setNeededColumns(n.left.plan, allColumns(n.left.plan))
+		setNeededColumns(n.right.plan, allColumns(n.right.plan))
+
 	case *joinNode:
+		neededJoined := needed
+		if n.joinType == joinTypeSemi || n.joinType == joinTypeAnti {
+			// The predicate is defined over the columns of both operands,
+			// but only the left columns are returned.
+			neededJoined = make([]bool, len(n.pred.info.sourceColumns))
+			copy(neededJoined[n.pred.numMergedEqualityColumns:], needed)
+		}
 		// Note: getNeededColumns takes into account both the columns
 		// tested for equality and the join predicate expression.
-		leftNeeded, rightNeeded := n.pred.getNeededColumns(needed)
+		leftNeeded, rightNeeded := n.pred.getNeededColumns(neededJoined)
 		setNeededColumns(n.left.plan, leftNeeded)
 		setNeededColumns(n.right.plan, rightNeeded)
 		markOmitted(n.columns, needed)
@@ -185,17 +199,27 @@ func setNeededColumns(plan planNode, needed []bool) {
 	case *relocateNode:
 		setNeededColumns(n.rows, allColumns(n.rows))
 
+	case *copyToNode:
+		setNeededColumns(n.source, allColumns(n.source))
+
 	case *alterTableNode:
 	case *copyNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
+	case *createPolicyNode:
+	case *createRoleNode:
 	case *createUserNode:
+	case *alterUserNode:
 	case *delayedNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
+	case *dropPolicyNode:
+	case *dropRoleNode:
 	case *dropUserNode:
+	case *grantRoleNode:
+	case *revokeRoleNode:
 	case *emptyNode:
 	case *hookFnNode:
 	case *valueGenerator:
@@ -39,6 +39,12 @@ func (p *planner) optimizePlan(
 		return plan, err
 	}
 
+	// Filters propagated into scans may have been rewritten to use the
+	// hidden columns of expression indexes instead of the source columns of
+	// the expressions, so propagate the needed columns again before index
+	// selection.
+	setNeededColumns(newPlan, needed)
+
 	// Perform plan expansion; this does index selection, sort
 	// optimization etc.
 	newPlan, err = p.expandPlan(ctx, newPlan)
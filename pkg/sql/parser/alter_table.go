@@ -60,19 +60,23 @@ type AlterTableCmd interface {
 	alterTableCmd()
 }
 
-func (*AlterTableAddColumn) alterTableCmd()          {}
-func (*AlterTableAddConstraint) alterTableCmd()      {}
-func (*AlterTableDropColumn) alterTableCmd()         {}
-func (*AlterTableDropConstraint) alterTableCmd()     {}
-func (*AlterTableDropNotNull) alterTableCmd()        {}
-func (*AlterTableSetDefault) alterTableCmd()         {}
-func (*AlterTableValidateConstraint) alterTableCmd() {}
+func (*AlterTableAddColumn) alterTableCmd()           {}
+func (*AlterTableAddConstraint) alterTableCmd()       {}
+func (*AlterTableDropColumn) alterTableCmd()          {}
+func (*AlterTableDropConstraint) alterTableCmd()      {}
+func (*AlterTableDropNotNull) alterTableCmd()         {}
+func (*AlterTableSetAudit) alterTableCmd()            {}
+func (*AlterTableSetRowLevelSecurity) alterTableCmd() {}
+func (*AlterTableSetDefault) alterTableCmd()          {}
+func (*AlterTableValidateConstraint) alterTableCmd()  {}
 
 var _ AlterTableCmd = &AlterTableAddColumn{}
 var _ AlterTableCmd = &AlterTableAddConstraint{}
 var _ AlterTableCmd = &AlterTableDropColumn{}
 var _ AlterTableCmd = &AlterTableDropConstraint{}
 var _ AlterTableCmd = &AlterTableDropNotNull{}
+var _ AlterTableCmd = &AlterTableSetAudit{}
+var _ AlterTableCmd = &AlterTableSetRowLevelSecurity{}
 var _ AlterTableCmd = &AlterTableSetDefault{}
 var _ AlterTableCmd = &AlterTableValidateConstraint{}
 
@@ -229,3 +233,49 @@ func (node *AlterTableDropNotNull) Format(buf *bytes.Buffer, f FmtFlags) {
 	FormatNode(buf, f, node.Column)
 	buf.WriteString(" DROP NOT NULL")
 }
+
+// AuditMode represents a table audit mode.
+type AuditMode int
+
+const (
+	// AuditModeDisable is the default mode: no audit.
+	AuditModeDisable AuditMode = iota
+	// AuditModeReadWrite enables audit on read or write statements.
+	AuditModeReadWrite
+)
+
+var auditModeName = [...]string{
+	AuditModeDisable:   "OFF",
+	AuditModeReadWrite: "READ WRITE",
+}
+
+func (m AuditMode) String() string {
+	return auditModeName[m]
+}
+
+// AlterTableSetAudit represents an EXPERIMENTAL_AUDIT SET command.
+type AlterTableSetAudit struct {
+	Mode AuditMode
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableSetAudit) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("EXPERIMENTAL_AUDIT SET ")
+	buf.WriteString(node.Mode.String())
+}
+
+// AlterTableSetRowLevelSecurity represents an ENABLE or DISABLE ROW LEVEL
+// SECURITY command.
+type AlterTableSetRowLevelSecurity struct {
+	Enabled bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableSetRowLevelSecurity) Format(buf *bytes.Buffer, f FmtFlags) {
+	if node.Enabled {
+		buf.WriteString("ENABLE")
+	} else {
+		buf.WriteString("DISABLE")
+	}
+	buf.WriteString(" ROW LEVEL SECURITY")
+}
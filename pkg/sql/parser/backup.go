@@ -50,9 +50,40 @@ func (node *Backup) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 }
 
+// CreateSchedule represents a CREATE SCHEDULE statement.
+type CreateSchedule struct {
+	Name       Name
+	Backup     *Backup
+	Recurrence Expr
+	// FullBackupRecurrence is the recurrence of full backups, if any. Other
+	// backups taken by the schedule are incremental.
+	FullBackupRecurrence Expr
+}
+
+var _ Statement = &CreateSchedule{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateSchedule) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CREATE SCHEDULE ")
+	if node.Name != "" {
+		FormatNode(buf, f, node.Name)
+		buf.WriteString(" ")
+	}
+	buf.WriteString("FOR ")
+	FormatNode(buf, f, node.Backup)
+	buf.WriteString(" RECURRING ")
+	FormatNode(buf, f, node.Recurrence)
+	if node.FullBackupRecurrence != nil {
+		buf.WriteString(" FULL BACKUP ")
+		FormatNode(buf, f, node.FullBackupRecurrence)
+	}
+}
+
 // Restore represents a RESTORE statement.
 type Restore struct {
 	Targets TargetList
+	// As, if set, is the new name of the single database or table restored.
+	As      Name
 	From    Exprs
 	AsOf    AsOfClause
 	Options KVOptions
@@ -64,6 +95,10 @@ var _ Statement = &Restore{}
 func (node *Restore) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString("RESTORE ")
 	FormatNode(buf, f, node.Targets)
+	if node.As != "" {
+		buf.WriteString(" AS ")
+		FormatNode(buf, f, node.As)
+	}
 	buf.WriteString(" FROM ")
 	FormatNode(buf, f, node.From)
 	if node.AsOf.Expr != nil {
@@ -77,6 +112,111 @@ func (node *Restore) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 }
 
+// Import represents an IMPORT statement.
+type Import struct {
+	Table      NormalizableTableName
+	CreateFile Expr
+	CreateDefs TableDefs
+	FileFormat string
+	Files      Exprs
+	Options    KVOptions
+}
+
+var _ Statement = &Import{}
+
+// Format implements the NodeFormatter interface.
+func (node *Import) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("IMPORT TABLE ")
+	FormatNode(buf, f, &node.Table)
+
+	if node.CreateFile != nil {
+		buf.WriteString(" CREATE USING ")
+		FormatNode(buf, f, node.CreateFile)
+		buf.WriteString(" ")
+	} else {
+		buf.WriteString(" (")
+		FormatNode(buf, f, node.CreateDefs)
+		buf.WriteString(") ")
+	}
+
+	buf.WriteString(node.FileFormat)
+	buf.WriteString(" DATA (")
+	FormatNode(buf, f, node.Files)
+	buf.WriteString(")")
+
+	if node.Options != nil {
+		buf.WriteString(" WITH OPTIONS (")
+		FormatNode(buf, f, node.Options)
+		buf.WriteString(")")
+	}
+}
+
+// VerifyBackup represents a VERIFY BACKUP statement.
+type VerifyBackup struct {
+	From    Exprs
+	Options KVOptions
+}
+
+var _ Statement = &VerifyBackup{}
+
+// Format implements the NodeFormatter interface.
+func (node *VerifyBackup) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("VERIFY BACKUP ")
+	FormatNode(buf, f, node.From)
+	if node.Options != nil {
+		buf.WriteString(" WITH OPTIONS (")
+		FormatNode(buf, f, node.Options)
+		buf.WriteString(")")
+	}
+}
+
+// Export represents an EXPORT statement.
+type Export struct {
+	Query      *Select
+	FileFormat string
+	File       Expr
+	Options    KVOptions
+}
+
+var _ Statement = &Export{}
+
+// Format implements the NodeFormatter interface.
+func (node *Export) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("EXPORT INTO ")
+	buf.WriteString(node.FileFormat)
+	buf.WriteString(" ")
+	FormatNode(buf, f, node.File)
+	if node.Options != nil {
+		buf.WriteString(" WITH OPTIONS (")
+		FormatNode(buf, f, node.Options)
+		buf.WriteString(")")
+	}
+	buf.WriteString(" FROM ")
+	FormatNode(buf, f, node.Query)
+}
+
+// CreateChangefeed represents a CREATE CHANGEFEED statement.
+type CreateChangefeed struct {
+	Targets TargetList
+	SinkURI Expr
+	Options KVOptions
+}
+
+var _ Statement = &CreateChangefeed{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateChangefeed) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CREATE CHANGEFEED FOR ")
+	FormatNode(buf, f, node.Targets)
+	buf.WriteString(" INTO ")
+	FormatNode(buf, f, node.SinkURI)
+	if node.Options != nil {
+		buf.WriteString(" WITH OPTIONS (")
+		FormatNode(buf, f, node.Options)
+		buf.WriteString(")")
+	}
+}
+
 // KVOption is a key-value option.
 type KVOption struct {
 	Key   string
@@ -46,6 +46,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/pkg/errors"
@@ -155,7 +156,7 @@ func (b Builtin) preferred() bool {
 
 func categorizeType(t Type) string {
 	switch t {
-	case TypeDate, TypeInterval, TypeTimestamp, TypeTimestampTZ:
+	case TypeDate, TypeInterval, TypeTimestamp, TypeTimestampTZ, TypeTime:
 		return categoryDateAndTime
 	case TypeInt, TypeDecimal, TypeFloat:
 		return categoryMath
@@ -256,6 +257,31 @@ var Builtins = map[string][]Builtin{
 		bytesBuiltin1(func(_ *EvalContext, s string) (Datum, error) {
 			return NewDInt(DInt(len(s))), nil
 		}, TypeInt, "Calculates the number of bytes in `val`."),
+		Builtin{
+			Types:      ArgTypes{{"val", TypeBitArray}},
+			ReturnType: fixedReturnType(TypeInt),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				return NewDInt(DInt(args[0].(*DBitArray).BitLen())), nil
+			},
+			Info: "Calculates the number of bits in `val`.",
+		},
+	},
+
+	"bit_length": {
+		stringBuiltin1(func(_ *EvalContext, s string) (Datum, error) {
+			return NewDInt(DInt(len(s) * 8)), nil
+		}, TypeInt, "Calculates the number of bits used to represent `val`."),
+		bytesBuiltin1(func(_ *EvalContext, s string) (Datum, error) {
+			return NewDInt(DInt(len(s) * 8)), nil
+		}, TypeInt, "Calculates the number of bits in `val`."),
+		Builtin{
+			Types:      ArgTypes{{"val", TypeBitArray}},
+			ReturnType: fixedReturnType(TypeInt),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				return NewDInt(DInt(args[0].(*DBitArray).BitLen())), nil
+			},
+			Info: "Calculates the number of bits in `val`.",
+		},
 	},
 
 	"octet_length": {
@@ -419,6 +445,132 @@ var Builtins = map[string][]Builtin{
 		},
 	},
 
+	"broadcast": {
+		Builtin{
+			Types:      ArgTypes{{"val", TypeINet}},
+			ReturnType: fixedReturnType(TypeINet),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				ipAddr := args[0].(*DIPAddr).IPAddr
+				return NewDIPAddr(DIPAddr{ipAddr.Broadcast()}), nil
+			},
+			Info: "Gets the broadcast address for the network address represented by `val`.",
+		},
+	},
+
+	"family": {
+		Builtin{
+			Types:      ArgTypes{{"val", TypeINet}},
+			ReturnType: fixedReturnType(TypeInt),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				ipAddr := args[0].(*DIPAddr).IPAddr
+				return NewDInt(DInt(ipAddr.Family)), nil
+			},
+			Info: "Extracts the IP family of the value; 4 for IPv4, 6 for IPv6.",
+		},
+	},
+
+	"host": {
+		Builtin{
+			Types:      ArgTypes{{"val", TypeINet}},
+			ReturnType: fixedReturnType(TypeString),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				ipAddr := args[0].(*DIPAddr).IPAddr
+				return NewDString(ipAddr.Host()), nil
+			},
+			Info: "Extracts the address part of the combined address/prefixlen value as text.",
+		},
+	},
+
+	"hostmask": {
+		Builtin{
+			Types:      ArgTypes{{"val", TypeINet}},
+			ReturnType: fixedReturnType(TypeINet),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				ipAddr := args[0].(*DIPAddr).IPAddr
+				return NewDIPAddr(DIPAddr{ipAddr.Hostmask()}), nil
+			},
+			Info: "Creates an IP host mask corresponding to the prefix length in the value.",
+		},
+	},
+
+	"masklen": {
+		Builtin{
+			Types:      ArgTypes{{"val", TypeINet}},
+			ReturnType: fixedReturnType(TypeInt),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				ipAddr := args[0].(*DIPAddr).IPAddr
+				return NewDInt(DInt(ipAddr.Mask)), nil
+			},
+			Info: "Retrieves the prefix length stored in the value.",
+		},
+	},
+
+	"netmask": {
+		Builtin{
+			Types:      ArgTypes{{"val", TypeINet}},
+			ReturnType: fixedReturnType(TypeINet),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				ipAddr := args[0].(*DIPAddr).IPAddr
+				return NewDIPAddr(DIPAddr{ipAddr.Netmask()}), nil
+			},
+			Info: "Creates an IP network mask corresponding to the prefix length in the value.",
+		},
+	},
+
+	"network": {
+		Builtin{
+			Types:      ArgTypes{{"val", TypeINet}},
+			ReturnType: fixedReturnType(TypeINet),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				ipAddr := args[0].(*DIPAddr).IPAddr
+				return NewDIPAddr(DIPAddr{ipAddr.Network()}), nil
+			},
+			Info: "Gets the network address for the value, with the bits to the right of the prefix " +
+				"length zeroed.",
+		},
+	},
+
+	"set_masklen": {
+		Builtin{
+			Types:      ArgTypes{{"val", TypeINet}, {"prefixlen", TypeInt}},
+			ReturnType: fixedReturnType(TypeINet),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				ipAddr := args[0].(*DIPAddr).IPAddr
+				r, err := ipAddr.SetMask(int(MustBeDInt(args[1])))
+				if err != nil {
+					return nil, err
+				}
+				return NewDIPAddr(DIPAddr{r}), nil
+			},
+			Info: "Sets the prefix length of `val` to `prefixlen`.",
+		},
+	},
+
+	"text": {
+		Builtin{
+			Types:      ArgTypes{{"val", TypeINet}},
+			ReturnType: fixedReturnType(TypeString),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				ipAddr := args[0].(*DIPAddr).IPAddr
+				return NewDString(ipAddr.CIDRString()), nil
+			},
+			Info: "Converts the IP address and prefix length to text.",
+		},
+	},
+
+	"inet_same_family": {
+		Builtin{
+			Types:      ArgTypes{{"val", TypeINet}, {"val", TypeINet}},
+			ReturnType: fixedReturnType(TypeBool),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				first := args[0].(*DIPAddr)
+				other := args[1].(*DIPAddr)
+				return MakeDBool(DBool(first.Family == other.Family)), nil
+			},
+			Info: "Checks if two IP addresses are of the same IP family.",
+		},
+	},
+
 	"split_part": {
 		Builtin{
 			Types: ArgTypes{
@@ -1010,6 +1162,20 @@ var Builtins = map[string][]Builtin{
 		},
 	},
 
+	"localtime": {
+		Builtin{
+			Types:      ArgTypes{},
+			ReturnType: fixedReturnType(TypeTime),
+			impure:     true,
+			fn: func(ctx *EvalContext, args Datums) (Datum, error) {
+				t := ctx.GetTxnTimestamp(time.Microsecond).Time.In(ctx.GetLocation())
+				return MakeDTime(timeofday.FromTime(t)), nil
+			},
+			Info: "Returns the current time of day of the transaction's timestamp, in the " +
+				"session time zone.",
+		},
+	},
+
 	"now":                   txnTSImpl,
 	"current_timestamp":     txnTSImpl,
 	"transaction_timestamp": txnTSImpl,
@@ -1102,6 +1268,25 @@ var Builtins = map[string][]Builtin{
 				"dayofweek<br/>&#8226; dayofyear<br/>&#8226; hour<br/>&#8226; minute<br/>&#8226; " +
 				"second<br/>&#8226; millisecond<br/>&#8226; microsecond<br/>&#8226; epoch",
 		},
+		Builtin{
+			Types:      ArgTypes{{"element", TypeString}, {"input", TypeTime}},
+			ReturnType: fixedReturnType(TypeInt),
+			category:   categoryDateAndTime,
+			fn: func(ctx *EvalContext, args Datums) (Datum, error) {
+				timeSpan := strings.ToLower(string(MustBeDString(args[0])))
+				switch timeSpan {
+				case "hour", "hours", "minute", "minutes", "second", "seconds",
+					"millisecond", "milliseconds", "microsecond", "microseconds", "epoch":
+				default:
+					return nil, fmt.Errorf("unsupported timespan: %s", timeSpan)
+				}
+				t := timeofday.TimeOfDay(*args[1].(*DTime)).ToTime()
+				return extractStringFromTimestamp(ctx, t, timeSpan)
+			},
+			Info: "Extracts `element` from `input`. Compatible `elements` are: <br/>&#8226; " +
+				"hour<br/>&#8226; minute<br/>&#8226; second<br/>&#8226; millisecond<br/>&#8226; " +
+				"microsecond<br/>&#8226; epoch",
+		},
 	},
 
 	"extract_duration": {
@@ -1590,6 +1775,22 @@ var Builtins = map[string][]Builtin{
 		},
 	},
 
+	"current_user": {
+		Builtin{
+			Types:            ArgTypes{},
+			ReturnType:       fixedReturnType(TypeString),
+			category:         categorySystemInfo,
+			distsqlBlacklist: true,
+			fn: func(ctx *EvalContext, args Datums) (Datum, error) {
+				if len(ctx.User) == 0 {
+					return DNull, nil
+				}
+				return NewDString(ctx.User), nil
+			},
+			Info: "Returns the current user.",
+		},
+	},
+
 	"current_schema": {
 		Builtin{
 			Types:      ArgTypes{},
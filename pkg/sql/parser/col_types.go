@@ -50,6 +50,9 @@ func (*TimestampColType) columnType()      {}
 func (*TimestampTZColType) columnType()    {}
 func (*IntervalColType) columnType()       {}
 func (*UUIDColType) columnType()           {}
+func (*INetColType) columnType()           {}
+func (*TimeColType) columnType()           {}
+func (*BitArrayColType) columnType()       {}
 func (*StringColType) columnType()         {}
 func (*NameColType) columnType()           {}
 func (*BytesColType) columnType()          {}
@@ -68,6 +71,9 @@ func (*TimestampColType) castTargetType()      {}
 func (*TimestampTZColType) castTargetType()    {}
 func (*IntervalColType) castTargetType()       {}
 func (*UUIDColType) castTargetType()           {}
+func (*INetColType) castTargetType()           {}
+func (*TimeColType) castTargetType()           {}
+func (*BitArrayColType) castTargetType()       {}
 func (*StringColType) castTargetType()         {}
 func (*NameColType) castTargetType()           {}
 func (*BytesColType) castTargetType()          {}
@@ -94,7 +100,6 @@ func (node *BoolColType) Format(buf *bytes.Buffer, f FmtFlags) {
 
 // Pre-allocated immutable integer column types.
 var (
-	intColTypeBit         = &IntColType{Name: "BIT", N: 1, ImplicitWidth: true}
 	intColTypeInt         = &IntColType{Name: "INT"}
 	intColTypeInt8        = &IntColType{Name: "INT8"}
 	intColTypeInt64       = &IntColType{Name: "INT64"}
@@ -106,28 +111,14 @@ var (
 	intColTypeBigSerial   = &IntColType{Name: "BIGSERIAL"}
 )
 
-var errBitLengthNotPositive = errors.New("length for type bit must be at least 1")
-
-func newIntBitType(n int) (*IntColType, error) {
-	if n < 1 {
-		return nil, errBitLengthNotPositive
-	}
-	return &IntColType{Name: "BIT", N: n}, nil
-}
-
 // IntColType represents an INT, INTEGER, SMALLINT or BIGINT type.
 type IntColType struct {
-	Name          string
-	N             int
-	ImplicitWidth bool
+	Name string
 }
 
 // Format implements the NodeFormatter interface.
 func (node *IntColType) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString(node.Name)
-	if node.N > 0 && !node.ImplicitWidth {
-		fmt.Fprintf(buf, "(%d)", node.N)
-	}
 }
 
 // IsSerial returns true when this column should be given a DEFAULT of a unique,
@@ -292,6 +283,76 @@ func (node *UUIDColType) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString("UUID")
 }
 
+// Pre-allocated immutable inet column types.
+var (
+	inetColTypeINet = &INetColType{Name: "INET"}
+	inetColTypeCIDR = &INetColType{Name: "CIDR"}
+)
+
+// INetColType represents an INET or CIDR type. CIDR values may not have bits
+// set to the right of their network mask.
+type INetColType struct {
+	Name string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *INetColType) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString(node.Name)
+}
+
+// IsCIDR returns true if the type only holds network addresses.
+func (node *INetColType) IsCIDR() bool {
+	return node.Name == inetColTypeCIDR.Name
+}
+
+// Pre-allocated immutable time column type.
+var timeColTypeTime = &TimeColType{}
+
+// TimeColType represents a TIME type.
+type TimeColType struct {
+}
+
+// Format implements the NodeFormatter interface.
+func (node *TimeColType) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("TIME")
+}
+
+// Pre-allocated immutable bit array column types.
+var (
+	bitArrayColTypeBit    = &BitArrayColType{Name: "BIT", Width: 1, ImplicitWidth: true}
+	bitArrayColTypeVarBit = &BitArrayColType{Name: "VARBIT", Variable: true}
+)
+
+var errBitLengthNotPositive = errors.New("length for type bit must be at least 1")
+
+func newBitArrayColType(width int, variable bool) (*BitArrayColType, error) {
+	if width < 1 {
+		return nil, errBitLengthNotPositive
+	}
+	if variable {
+		return &BitArrayColType{Name: "VARBIT", Width: uint(width), Variable: true}, nil
+	}
+	return &BitArrayColType{Name: "BIT", Width: uint(width)}, nil
+}
+
+// BitArrayColType represents a BIT or VARBIT type. Values of a BIT type must
+// have exactly Width bits, while values of a VARBIT type can have at most
+// Width bits, or any number of bits if Width is 0.
+type BitArrayColType struct {
+	Name          string
+	Width         uint
+	Variable      bool
+	ImplicitWidth bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *BitArrayColType) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString(node.Name)
+	if node.Width > 0 && !node.ImplicitWidth {
+		fmt.Fprintf(buf, "(%d)", node.Width)
+	}
+}
+
 // Pre-allocated immutable string column types.
 var (
 	stringColTypeChar    = &StringColType{Name: "CHAR"}
@@ -476,6 +537,9 @@ func (node *TimestampColType) String() string      { return AsString(node) }
 func (node *TimestampTZColType) String() string    { return AsString(node) }
 func (node *IntervalColType) String() string       { return AsString(node) }
 func (node *UUIDColType) String() string           { return AsString(node) }
+func (node *INetColType) String() string           { return AsString(node) }
+func (node *TimeColType) String() string           { return AsString(node) }
+func (node *BitArrayColType) String() string       { return AsString(node) }
 func (node *StringColType) String() string         { return AsString(node) }
 func (node *NameColType) String() string           { return AsString(node) }
 func (node *BytesColType) String() string          { return AsString(node) }
@@ -505,6 +569,12 @@ func DatumTypeToColumnType(t Type) (ColumnType, error) {
 		return intervalColTypeInterval, nil
 	case TypeUUID:
 		return uuidColTypeUUID, nil
+	case TypeINet:
+		return inetColTypeINet, nil
+	case TypeTime:
+		return timeColTypeTime, nil
+	case TypeBitArray:
+		return bitArrayColTypeVarBit, nil
 	case TypeDate:
 		return dateColTypeDate, nil
 	case TypeString:
@@ -566,6 +636,12 @@ func CastTargetToDatumType(t CastTargetType) Type {
 		return TypeInterval
 	case *UUIDColType:
 		return TypeUUID
+	case *INetColType:
+		return TypeINet
+	case *TimeColType:
+		return TypeTime
+	case *BitArrayColType:
+		return TypeBitArray
 	case *CollatedStringColType:
 		return TCollatedString{Locale: ct.Locale}
 	case *ArrayColType:
@@ -27,8 +27,10 @@ func TestParseColumnType(t *testing.T) {
 		str          string
 		expectedType ColumnType
 	}{
-		{"BIT", &IntColType{Name: "BIT", N: 1, ImplicitWidth: true}},
-		{"BIT(2)", &IntColType{Name: "BIT", N: 2}},
+		{"BIT", &BitArrayColType{Name: "BIT", Width: 1, ImplicitWidth: true}},
+		{"BIT(2)", &BitArrayColType{Name: "BIT", Width: 2}},
+		{"VARBIT", &BitArrayColType{Name: "VARBIT", Variable: true}},
+		{"VARBIT(2)", &BitArrayColType{Name: "VARBIT", Width: 2, Variable: true}},
 		{"BOOL", &BoolColType{Name: "BOOL"}},
 		{"BOOLEAN", &BoolColType{Name: "BOOLEAN"}},
 		{"SMALLINT", &IntColType{Name: "SMALLINT"}},
@@ -49,7 +51,10 @@ func TestParseColumnType(t *testing.T) {
 		{"DATE", &DateColType{}},
 		{"TIMESTAMP", &TimestampColType{}},
 		{"TIMESTAMP WITH TIME ZONE", &TimestampTZColType{}},
+		{"TIME", &TimeColType{}},
 		{"INTERVAL", &IntervalColType{}},
+		{"INET", &INetColType{Name: "INET"}},
+		{"CIDR", &INetColType{Name: "CIDR"}},
 		{"STRING", &StringColType{Name: "STRING"}},
 		{"CHAR", &StringColType{Name: "CHAR"}},
 		{"VARCHAR", &StringColType{Name: "VARCHAR"}},
@@ -369,6 +369,9 @@ var (
 		TypeTimestampTZ,
 		TypeInterval,
 		TypeUUID,
+		TypeINet,
+		TypeTime,
+		TypeBitArray,
 	}
 	strValAvailBytesString = []Type{TypeBytes, TypeString, TypeUUID}
 	strValAvailBytes       = []Type{TypeBytes, TypeUUID}
@@ -440,6 +443,12 @@ func (expr *StrVal) ResolveAsType(ctx *SemaContext, typ Type) (Datum, error) {
 			return ParseDUuidFromBytes([]byte(expr.s))
 		}
 		return ParseDUuidFromString(expr.s)
+	case TypeINet:
+		return ParseDIPAddrFromINetString(expr.s)
+	case TypeTime:
+		return ParseDTime(expr.s)
+	case TypeBitArray:
+		return ParseDBitArray(expr.s)
 	default:
 		return nil, fmt.Errorf("could not resolve %T %v into a %T", expr, expr, typ)
 	}
@@ -219,6 +219,28 @@ func mustParseDInterval(t *testing.T, s string) Datum {
 	return d
 }
 
+func mustParseDTime(t *testing.T, s string) Datum {
+	d, err := ParseDTime(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+func mustParseDIPAddr(t *testing.T, s string) Datum {
+	d, err := ParseDIPAddrFromINetString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+func mustParseDBitArray(t *testing.T, s string) Datum {
+	d, err := ParseDBitArray(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
 var parseFuncs = map[Type]func(*testing.T, string) Datum{
 	TypeString:      func(t *testing.T, s string) Datum { return NewDString(s) },
 	TypeBytes:       func(t *testing.T, s string) Datum { return NewDBytes(DBytes(s)) },
@@ -227,6 +249,9 @@ var parseFuncs = map[Type]func(*testing.T, string) Datum{
 	TypeTimestamp:   mustParseDTimestamp,
 	TypeTimestampTZ: mustParseDTimestampTZ,
 	TypeInterval:    mustParseDInterval,
+	TypeTime:        mustParseDTime,
+	TypeINet:        mustParseDIPAddr,
+	TypeBitArray:    mustParseDBitArray,
 }
 
 func typeSet(types ...Type) map[Type]struct{} {
@@ -257,15 +282,27 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 		},
 		{
 			c:            &StrVal{s: "2010-09-28", bytesEsc: false},
-			parseOptions: typeSet(TypeString, TypeBytes, TypeDate, TypeTimestamp, TypeTimestampTZ),
+			parseOptions: typeSet(TypeString, TypeBytes, TypeDate, TypeTimestamp, TypeTimestampTZ, TypeTime),
 		},
 		{
 			c:            &StrVal{s: "2010-09-28 12:00:00.1", bytesEsc: false},
-			parseOptions: typeSet(TypeString, TypeBytes, TypeTimestamp, TypeTimestampTZ, TypeDate),
+			parseOptions: typeSet(TypeString, TypeBytes, TypeTimestamp, TypeTimestampTZ, TypeDate, TypeTime),
 		},
 		{
 			c:            &StrVal{s: "2006-07-08T00:00:00.000000123Z", bytesEsc: false},
-			parseOptions: typeSet(TypeString, TypeBytes, TypeTimestamp, TypeTimestampTZ, TypeDate),
+			parseOptions: typeSet(TypeString, TypeBytes, TypeTimestamp, TypeTimestampTZ, TypeDate, TypeTime),
+		},
+		{
+			c:            &StrVal{s: "12:00:00.1", bytesEsc: false},
+			parseOptions: typeSet(TypeString, TypeBytes, TypeTime, TypeInterval),
+		},
+		{
+			c:            &StrVal{s: "192.168.1.2/24", bytesEsc: false},
+			parseOptions: typeSet(TypeString, TypeBytes, TypeINet, TypeInterval),
+		},
+		{
+			c:            &StrVal{s: "0101", bytesEsc: false},
+			parseOptions: typeSet(TypeString, TypeBytes, TypeBitArray, TypeInterval),
 		},
 		{
 			c:            &StrVal{s: "PT12H2M", bytesEsc: false},
@@ -39,3 +39,46 @@ func (node *CopyFrom) Format(buf *bytes.Buffer, f FmtFlags) {
 		buf.WriteString("STDIN")
 	}
 }
+
+// CopyTo represents a COPY TO statement. Exactly one of Table and Query is
+// set.
+type CopyTo struct {
+	Table   NormalizableTableName
+	Columns UnresolvedNames
+	Query   *Select
+	Stdout  bool
+	Options KVOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CopyTo) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("COPY ")
+	if node.Query != nil {
+		FormatNode(buf, f, node.Query)
+	} else {
+		FormatNode(buf, f, node.Table)
+		if len(node.Columns) > 0 {
+			buf.WriteString(" (")
+			FormatNode(buf, f, node.Columns)
+			buf.WriteString(")")
+		}
+	}
+	buf.WriteString(" TO ")
+	if node.Stdout {
+		buf.WriteString("STDOUT")
+	}
+	if len(node.Options) > 0 {
+		buf.WriteString(" WITH (")
+		for i, o := range node.Options {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			FormatNode(buf, f, Name(o.Key))
+			if len(o.Value) != 0 {
+				buf.WriteByte(' ')
+				encodeSQLStringWithFlags(buf, o.Value, f)
+			}
+		}
+		buf.WriteString(")")
+	}
+}
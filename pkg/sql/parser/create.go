@@ -66,15 +66,29 @@ func (node *CreateDatabase) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 }
 
-// IndexElem represents a column with a direction in a CREATE INDEX statement.
+// IndexElem represents a column or an expression with a direction in a
+// CREATE INDEX statement. Exactly one of Column and Expr is set.
 type IndexElem struct {
 	Column    Name
+	Expr      Expr
 	Direction Direction
 }
 
 // Format implements the NodeFormatter interface.
 func (node IndexElem) Format(buf *bytes.Buffer, f FmtFlags) {
-	FormatNode(buf, f, node.Column)
+	if node.Expr != nil {
+		// Function calls can be written without parentheses; other
+		// expressions need them to be distinguished from a column name.
+		if _, ok := node.Expr.(*FuncExpr); ok {
+			FormatNode(buf, f, node.Expr)
+		} else {
+			buf.WriteByte('(')
+			FormatNode(buf, f, node.Expr)
+			buf.WriteByte(')')
+		}
+	} else {
+		FormatNode(buf, f, node.Column)
+	}
 	if node.Direction != DefaultDirection {
 		buf.WriteByte(' ')
 		buf.WriteString(node.Direction.String())
@@ -106,6 +120,9 @@ type CreateIndex struct {
 	// for improved reading performance.
 	Storing    NameList
 	Interleave *InterleaveDef
+	// Predicate restricts a partial index to the rows which satisfy it. It is
+	// nil for an index over all the rows of the table.
+	Predicate Expr
 }
 
 // Format implements the NodeFormatter interface.
@@ -135,6 +152,10 @@ func (node *CreateIndex) Format(buf *bytes.Buffer, f FmtFlags) {
 	if node.Interleave != nil {
 		FormatNode(buf, f, node.Interleave)
 	}
+	if node.Predicate != nil {
+		buf.WriteString(" WHERE ")
+		FormatNode(buf, f, node.Predicate)
+	}
 }
 
 // TableDef represents a column, index or constraint definition within a CREATE
@@ -435,6 +456,7 @@ type IndexTableDef struct {
 	Columns    IndexElemList
 	Storing    NameList
 	Interleave *InterleaveDef
+	Predicate  Expr
 }
 
 func (node *IndexTableDef) setName(name Name) {
@@ -459,6 +481,10 @@ func (node *IndexTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
 	if node.Interleave != nil {
 		FormatNode(buf, f, node.Interleave)
 	}
+	if node.Predicate != nil {
+		buf.WriteString(" WHERE ")
+		FormatNode(buf, f, node.Predicate)
+	}
 }
 
 // ConstraintTableDef represents a constraint definition within a CREATE TABLE
@@ -502,6 +528,10 @@ func (node *UniqueConstraintTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
 	if node.Interleave != nil {
 		FormatNode(buf, f, node.Interleave)
 	}
+	if node.Predicate != nil {
+		buf.WriteString(" WHERE ")
+		FormatNode(buf, f, node.Predicate)
+	}
 }
 
 // ForeignKeyConstraintTableDef represents a FOREIGN KEY constraint in the AST.
@@ -657,27 +687,169 @@ func (node *CreateTable) Format(buf *bytes.Buffer, f FmtFlags) {
 
 // CreateUser represents a CREATE USER statement.
 type CreateUser struct {
-	Name     Name
-	Password *string // pointer so that empty and nil can be differentiated
+	Name    Name
+	Options UserOptions
 }
 
 // HasPassword returns if the CreateUser has a password.
 func (node *CreateUser) HasPassword() bool {
-	return node.Password != nil
+	return node.Options.Password != nil
 }
 
 // Format implements the NodeFormatter interface.
 func (node *CreateUser) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString("CREATE USER ")
 	FormatNode(buf, f, node.Name)
-	if node.HasPassword() {
-		buf.WriteString(" WITH PASSWORD ")
+	if !node.Options.IsEmpty() {
+		buf.WriteString(" WITH")
+		FormatNode(buf, f, &node.Options)
+	}
+}
+
+// UserOptions represents the options of a CREATE USER or ALTER USER
+// statement. The options which are not specified are nil.
+type UserOptions struct {
+	Password *string // pointer so that empty and nil can be differentiated
+	// Login is false for NOLOGIN.
+	Login *bool
+	// ValidUntil is the time after which the password of the user is no
+	// longer valid, or 'infinity'.
+	ValidUntil *string
+	// ConnectionLimit is the maximum number of concurrent connections of the
+	// user, or -1 for no limit.
+	ConnectionLimit *int64
+}
+
+// IsEmpty returns true if no option is specified.
+func (o *UserOptions) IsEmpty() bool {
+	return o.Password == nil && o.Login == nil && o.ValidUntil == nil && o.ConnectionLimit == nil
+}
+
+// merge adds the options specified in other to o. An option can only be
+// specified once.
+func (o *UserOptions) merge(other UserOptions) error {
+	if (o.Password != nil && other.Password != nil) ||
+		(o.Login != nil && other.Login != nil) ||
+		(o.ValidUntil != nil && other.ValidUntil != nil) ||
+		(o.ConnectionLimit != nil && other.ConnectionLimit != nil) {
+		return errors.New("conflicting or redundant options")
+	}
+	if other.Password != nil {
+		o.Password = other.Password
+	}
+	if other.Login != nil {
+		o.Login = other.Login
+	}
+	if other.ValidUntil != nil {
+		o.ValidUntil = other.ValidUntil
+	}
+	if other.ConnectionLimit != nil {
+		o.ConnectionLimit = other.ConnectionLimit
+	}
+	return nil
+}
+
+// Format implements the NodeFormatter interface. Each option is preceded
+// by a space.
+func (o *UserOptions) Format(buf *bytes.Buffer, f FmtFlags) {
+	if o.Password != nil {
+		buf.WriteString(" PASSWORD ")
 		if f.showPasswords {
-			encodeSQLString(buf, *node.Password)
+			encodeSQLString(buf, *o.Password)
 		} else {
 			buf.WriteString("*****")
 		}
 	}
+	if o.Login != nil {
+		if *o.Login {
+			buf.WriteString(" LOGIN")
+		} else {
+			buf.WriteString(" NOLOGIN")
+		}
+	}
+	if o.ConnectionLimit != nil {
+		fmt.Fprintf(buf, " CONNECTION LIMIT %d", *o.ConnectionLimit)
+	}
+	if o.ValidUntil != nil {
+		buf.WriteString(" VALID UNTIL ")
+		encodeSQLString(buf, *o.ValidUntil)
+	}
+}
+
+// PolicyCommand represents the statements a row level security policy
+// applies to.
+type PolicyCommand int
+
+const (
+	// PolicyAll applies the policy to all statements.
+	PolicyAll PolicyCommand = iota
+	// PolicySelect applies the policy to the rows read by statements.
+	PolicySelect
+	// PolicyInsert applies the policy to the rows inserted.
+	PolicyInsert
+	// PolicyUpdate applies the policy to the rows updated.
+	PolicyUpdate
+	// PolicyDelete applies the policy to the rows deleted.
+	PolicyDelete
+)
+
+var policyCommandName = [...]string{
+	PolicyAll:    "ALL",
+	PolicySelect: "SELECT",
+	PolicyInsert: "INSERT",
+	PolicyUpdate: "UPDATE",
+	PolicyDelete: "DELETE",
+}
+
+func (c PolicyCommand) String() string {
+	return policyCommandName[c]
+}
+
+// CreatePolicy represents a CREATE POLICY statement.
+type CreatePolicy struct {
+	Name      Name
+	Table     NormalizableTableName
+	Command   PolicyCommand
+	Roles     NameList
+	Using     Expr
+	WithCheck Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreatePolicy) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CREATE POLICY ")
+	FormatNode(buf, f, node.Name)
+	buf.WriteString(" ON ")
+	FormatNode(buf, f, node.Table)
+	if node.Command != PolicyAll {
+		buf.WriteString(" FOR ")
+		buf.WriteString(node.Command.String())
+	}
+	if len(node.Roles) > 0 {
+		buf.WriteString(" TO ")
+		FormatNode(buf, f, node.Roles)
+	}
+	if node.Using != nil {
+		buf.WriteString(" USING (")
+		FormatNode(buf, f, node.Using)
+		buf.WriteByte(')')
+	}
+	if node.WithCheck != nil {
+		buf.WriteString(" WITH CHECK (")
+		FormatNode(buf, f, node.WithCheck)
+		buf.WriteByte(')')
+	}
+}
+
+// CreateRole represents a CREATE ROLE statement.
+type CreateRole struct {
+	Name Name
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateRole) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CREATE ROLE ")
+	FormatNode(buf, f, node.Name)
 }
 
 // CreateView represents a CREATE VIEW statement.
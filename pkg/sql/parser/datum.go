@@ -36,7 +36,10 @@ import (
 
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
@@ -1052,6 +1055,106 @@ func (d *DUuid) Size() uintptr {
 	return unsafe.Sizeof(*d)
 }
 
+// DIPAddr is the IPAddr Datum.
+type DIPAddr struct {
+	ipaddr.IPAddr
+}
+
+// NewDIPAddr is a helper routine to create a *DIPAddr initialized from its
+// argument.
+func NewDIPAddr(d DIPAddr) *DIPAddr {
+	return &d
+}
+
+// ParseDIPAddrFromINetString parses and returns the *DIPAddr Datum value
+// represented by the provided INET string, or an error if parsing is
+// unsuccessful.
+func ParseDIPAddrFromINetString(s string) (*DIPAddr, error) {
+	ipAddr, err := ipaddr.ParseINet(s)
+	if err != nil {
+		return nil, makeParseError(s, TypeINet, err)
+	}
+	return NewDIPAddr(DIPAddr{ipAddr}), nil
+}
+
+// ParseDIPAddrFromCIDRString parses and returns the *DIPAddr Datum value
+// represented by the provided CIDR string, or an error if parsing is
+// unsuccessful or the address has bits set to the right of its mask.
+func ParseDIPAddrFromCIDRString(s string) (*DIPAddr, error) {
+	ipAddr, err := ipaddr.ParseCIDR(s)
+	if err != nil {
+		return nil, makeParseError(s, TypeINet, err)
+	}
+	return NewDIPAddr(DIPAddr{ipAddr}), nil
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DIPAddr) ResolvedType() Type {
+	return TypeINet
+}
+
+// Compare implements the Datum interface.
+func (d *DIPAddr) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := other.(*DIPAddr)
+	if !ok {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	return d.IPAddr.Compare(v.IPAddr)
+}
+
+// Prev implements the Datum interface.
+func (d *DIPAddr) Prev() (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DIPAddr) Next() (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DIPAddr) IsMax() bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DIPAddr) IsMin() bool {
+	return false
+}
+
+// min implements the Datum interface.
+func (*DIPAddr) min() (Datum, bool) {
+	return nil, false
+}
+
+// max implements the Datum interface.
+func (*DIPAddr) max() (Datum, bool) {
+	return nil, false
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DIPAddr) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DIPAddr) Format(buf *bytes.Buffer, f FmtFlags) {
+	if !f.bareStrings {
+		buf.WriteByte('\'')
+	}
+	buf.WriteString(d.IPAddr.String())
+	if !f.bareStrings {
+		buf.WriteByte('\'')
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DIPAddr) Size() uintptr {
+	return unsafe.Sizeof(*d) + uintptr(len(d.Addr))
+}
+
 // DDate is the date Datum represented as the number of days after
 // the Unix epoch.
 type DDate int64
@@ -1161,6 +1264,115 @@ func (d *DDate) Size() uintptr {
 	return unsafe.Sizeof(*d)
 }
 
+// DTime is the time Datum, holding a time of day without a date or a time
+// zone.
+type DTime timeofday.TimeOfDay
+
+// MakeDTime creates a DTime from a TimeOfDay.
+func MakeDTime(t timeofday.TimeOfDay) *DTime {
+	d := DTime(t)
+	return &d
+}
+
+var timeOfDayFormats = []string{
+	"15:04:05.999999999",
+	"15:04",
+}
+
+// ParseDTime parses and returns the *DTime Datum value represented by the
+// provided string, or an error if parsing is unsuccessful. A full timestamp
+// is accepted as well, in which case its date and time zone are ignored.
+func ParseDTime(s string) (*DTime, error) {
+	for _, format := range timeOfDayFormats {
+		if t, err := time.Parse(format, s); err == nil {
+			return MakeDTime(timeofday.FromTime(t.Round(time.Microsecond))), nil
+		}
+	}
+	t, err := parseTimestampInLocation(s, time.UTC, TypeTime)
+	if err != nil {
+		return nil, err
+	}
+	return MakeDTime(timeofday.FromTime(t.Round(time.Microsecond))), nil
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DTime) ResolvedType() Type {
+	return TypeTime
+}
+
+// Compare implements the Datum interface.
+func (d *DTime) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := other.(*DTime)
+	if !ok {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	if *d < *v {
+		return -1
+	}
+	if *v < *d {
+		return 1
+	}
+	return 0
+}
+
+// Prev implements the Datum interface.
+func (d *DTime) Prev() (Datum, bool) {
+	prev := *d - 1
+	return &prev, true
+}
+
+// Next implements the Datum interface.
+func (d *DTime) Next() (Datum, bool) {
+	next := *d + 1
+	return &next, true
+}
+
+var dTimeMin = MakeDTime(timeofday.Min)
+var dTimeMax = MakeDTime(timeofday.Max)
+
+// IsMax implements the Datum interface.
+func (d *DTime) IsMax() bool {
+	return *d == *dTimeMax
+}
+
+// IsMin implements the Datum interface.
+func (d *DTime) IsMin() bool {
+	return *d == *dTimeMin
+}
+
+// max implements the Datum interface.
+func (d *DTime) max() (Datum, bool) {
+	return dTimeMax, true
+}
+
+// min implements the Datum interface.
+func (d *DTime) min() (Datum, bool) {
+	return dTimeMin, true
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DTime) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DTime) Format(buf *bytes.Buffer, f FmtFlags) {
+	if !f.bareStrings {
+		buf.WriteByte('\'')
+	}
+	buf.WriteString(timeofday.TimeOfDay(*d).String())
+	if !f.bareStrings {
+		buf.WriteByte('\'')
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DTime) Size() uintptr {
+	return unsafe.Sizeof(*d)
+}
+
 // DTimestamp is the timestamp Datum.
 type DTimestamp struct {
 	time.Time
@@ -1694,6 +1906,95 @@ func (d *DInterval) Size() uintptr {
 	return unsafe.Sizeof(*d)
 }
 
+// DBitArray is the BIT/VARBIT Datum.
+type DBitArray struct {
+	bitarray.BitArray
+}
+
+// ParseDBitArray parses and returns the *DBitArray Datum value represented by
+// the provided string of binary digits, or an error if parsing is
+// unsuccessful.
+func ParseDBitArray(s string) (*DBitArray, error) {
+	a, err := bitarray.Parse(s)
+	if err != nil {
+		return nil, makeParseError(s, TypeBitArray, err)
+	}
+	return &DBitArray{BitArray: a}, nil
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DBitArray) ResolvedType() Type {
+	return TypeBitArray
+}
+
+// Compare implements the Datum interface.
+func (d *DBitArray) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := other.(*DBitArray)
+	if !ok {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	return bitarray.Compare(d.BitArray, v.BitArray)
+}
+
+// Prev implements the Datum interface.
+func (d *DBitArray) Prev() (Datum, bool) {
+	return nil, false
+}
+
+var bitArrayZero = bitarray.FromInt(1, 0)
+
+// Next implements the Datum interface.
+func (d *DBitArray) Next() (Datum, bool) {
+	// The smallest bit array greater than d is d followed by a zero bit.
+	return &DBitArray{BitArray: bitarray.Concat(d.BitArray, bitArrayZero)}, true
+}
+
+// IsMax implements the Datum interface.
+func (d *DBitArray) IsMax() bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DBitArray) IsMin() bool {
+	return d.BitLen() == 0
+}
+
+var dEmptyBitArray = &DBitArray{}
+
+// min implements the Datum interface.
+func (d *DBitArray) min() (Datum, bool) {
+	return dEmptyBitArray, true
+}
+
+// max implements the Datum interface.
+func (d *DBitArray) max() (Datum, bool) {
+	return nil, false
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DBitArray) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DBitArray) Format(buf *bytes.Buffer, f FmtFlags) {
+	if !f.bareStrings {
+		buf.WriteByte('\'')
+	}
+	buf.WriteString(d.BitArray.String())
+	if !f.bareStrings {
+		buf.WriteByte('\'')
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DBitArray) Size() uintptr {
+	data, _ := d.EncodingParts()
+	return unsafe.Sizeof(*d) + uintptr(len(data))
+}
+
 // DTuple is the tuple Datum.
 type DTuple struct {
 	D Datums
@@ -2202,7 +2503,7 @@ func (t *DTable) Format(buf *bytes.Buffer, _ FmtFlags) {
 
 // ResolvedType implements the TypedExpr interface.
 func (t *DTable) ResolvedType() Type {
-	return TTable{t.ValueGenerator.ColumnTypes()}
+	return TTable{Cols: t.ValueGenerator.ColumnTypes()}
 }
 
 // Compare implements the Datum interface.
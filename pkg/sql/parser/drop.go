@@ -133,3 +133,36 @@ func (node *DropUser) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 	FormatNode(buf, f, node.Names)
 }
+
+// DropRole represents a DROP ROLE statement
+type DropRole struct {
+	Names    NameList
+	IfExists bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropRole) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("DROP ROLE ")
+	if node.IfExists {
+		buf.WriteString("IF EXISTS ")
+	}
+	FormatNode(buf, f, node.Names)
+}
+
+// DropPolicy represents a DROP POLICY statement.
+type DropPolicy struct {
+	Name     Name
+	Table    NormalizableTableName
+	IfExists bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropPolicy) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("DROP POLICY ")
+	if node.IfExists {
+		buf.WriteString("IF EXISTS ")
+	}
+	FormatNode(buf, f, node.Name)
+	buf.WriteString(" ON ")
+	FormatNode(buf, f, node.Table)
+}
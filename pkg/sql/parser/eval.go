@@ -33,8 +33,10 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/mon"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
@@ -58,6 +60,11 @@ type UnaryOp struct {
 	ReturnType Type
 	fn         func(*EvalContext, Datum) (Datum, error)
 
+	// See Builtin.preferredOverload. The integer overloads of the bitwise
+	// operators are preferred so that placeholders keep resolving to INT now
+	// that the operators are also defined on bit strings.
+	preferredOverload bool
+
 	types   typeList
 	retType returnTyper
 }
@@ -70,8 +77,8 @@ func (op UnaryOp) returnType() returnTyper {
 	return op.retType
 }
 
-func (UnaryOp) preferred() bool {
-	return false
+func (op UnaryOp) preferred() bool {
+	return op.preferredOverload
 }
 
 func init() {
@@ -163,6 +170,14 @@ var UnaryOps = map[UnaryOperator]unaryOpOverload{
 			fn: func(_ *EvalContext, d Datum) (Datum, error) {
 				return NewDInt(^MustBeDInt(d)), nil
 			},
+			preferredOverload: true,
+		},
+		UnaryOp{
+			Typ:        TypeBitArray,
+			ReturnType: TypeBitArray,
+			fn: func(_ *EvalContext, d Datum) (Datum, error) {
+				return &DBitArray{BitArray: bitarray.Not(d.(*DBitArray).BitArray)}, nil
+			},
 		},
 	},
 }
@@ -174,6 +189,9 @@ type BinOp struct {
 	ReturnType Type
 	fn         func(*EvalContext, Datum, Datum) (Datum, error)
 
+	// See UnaryOp.preferredOverload.
+	preferredOverload bool
+
 	types   typeList
 	retType returnTyper
 }
@@ -190,8 +208,8 @@ func (op BinOp) returnType() returnTyper {
 	return op.retType
 }
 
-func (BinOp) preferred() bool {
-	return false
+func (op BinOp) preferred() bool {
+	return op.preferredOverload
 }
 
 func init() {
@@ -228,6 +246,19 @@ var BinOps = map[BinaryOperator]binOpOverload{
 			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
 				return NewDInt(MustBeDInt(left) & MustBeDInt(right)), nil
 			},
+			preferredOverload: true,
+		},
+		BinOp{
+			LeftType:   TypeBitArray,
+			RightType:  TypeBitArray,
+			ReturnType: TypeBitArray,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				a, err := bitarray.And(left.(*DBitArray).BitArray, right.(*DBitArray).BitArray)
+				if err != nil {
+					return nil, err
+				}
+				return &DBitArray{BitArray: a}, nil
+			},
 		},
 	},
 
@@ -239,6 +270,19 @@ var BinOps = map[BinaryOperator]binOpOverload{
 			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
 				return NewDInt(MustBeDInt(left) | MustBeDInt(right)), nil
 			},
+			preferredOverload: true,
+		},
+		BinOp{
+			LeftType:   TypeBitArray,
+			RightType:  TypeBitArray,
+			ReturnType: TypeBitArray,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				a, err := bitarray.Or(left.(*DBitArray).BitArray, right.(*DBitArray).BitArray)
+				if err != nil {
+					return nil, err
+				}
+				return &DBitArray{BitArray: a}, nil
+			},
 		},
 	},
 
@@ -250,6 +294,19 @@ var BinOps = map[BinaryOperator]binOpOverload{
 			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
 				return NewDInt(MustBeDInt(left) ^ MustBeDInt(right)), nil
 			},
+			preferredOverload: true,
+		},
+		BinOp{
+			LeftType:   TypeBitArray,
+			RightType:  TypeBitArray,
+			ReturnType: TypeBitArray,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				a, err := bitarray.Xor(left.(*DBitArray).BitArray, right.(*DBitArray).BitArray)
+				if err != nil {
+					return nil, err
+				}
+				return &DBitArray{BitArray: a}, nil
+			},
 		},
 	},
 
@@ -393,6 +450,40 @@ var BinOps = map[BinaryOperator]binOpOverload{
 				return MakeDTimestampTZ(t, time.Microsecond), nil
 			},
 		},
+		BinOp{
+			LeftType:   TypeTime,
+			RightType:  TypeInterval,
+			ReturnType: TypeTime,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				t := timeofday.TimeOfDay(*left.(*DTime))
+				return MakeDTime(t.Add(right.(*DInterval).Duration)), nil
+			},
+		},
+		BinOp{
+			LeftType:   TypeInterval,
+			RightType:  TypeTime,
+			ReturnType: TypeTime,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				t := timeofday.TimeOfDay(*right.(*DTime))
+				return MakeDTime(t.Add(left.(*DInterval).Duration)), nil
+			},
+		},
+		BinOp{
+			LeftType:   TypeDate,
+			RightType:  TypeTime,
+			ReturnType: TypeTimestamp,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return makeDTimestampFromDateAndTime(left.(*DDate), right.(*DTime)), nil
+			},
+		},
+		BinOp{
+			LeftType:   TypeTime,
+			RightType:  TypeDate,
+			ReturnType: TypeTimestamp,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return makeDTimestampFromDateAndTime(right.(*DDate), left.(*DTime)), nil
+			},
+		},
 	},
 
 	Minus: {
@@ -544,6 +635,25 @@ var BinOps = map[BinaryOperator]binOpOverload{
 				return &DInterval{Duration: left.(*DInterval).Duration.Sub(right.(*DInterval).Duration)}, nil
 			},
 		},
+		BinOp{
+			LeftType:   TypeTime,
+			RightType:  TypeInterval,
+			ReturnType: TypeTime,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				t := timeofday.TimeOfDay(*left.(*DTime))
+				return MakeDTime(t.Add(right.(*DInterval).Duration.Mul(-1))), nil
+			},
+		},
+		BinOp{
+			LeftType:   TypeTime,
+			RightType:  TypeTime,
+			ReturnType: TypeInterval,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				t1 := timeofday.TimeOfDay(*left.(*DTime))
+				t2 := timeofday.TimeOfDay(*right.(*DTime))
+				return &DInterval{Duration: timeofday.Difference(t1, t2)}, nil
+			},
+		},
 	},
 
 	Mult: {
@@ -876,6 +986,15 @@ var BinOps = map[BinaryOperator]binOpOverload{
 				return NewDBytes(*left.(*DBytes) + *right.(*DBytes)), nil
 			},
 		},
+		BinOp{
+			LeftType:   TypeBitArray,
+			RightType:  TypeBitArray,
+			ReturnType: TypeBitArray,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				a := bitarray.Concat(left.(*DBitArray).BitArray, right.(*DBitArray).BitArray)
+				return &DBitArray{BitArray: a}, nil
+			},
+		},
 	},
 
 	// TODO(pmattis): Check that the shift is valid.
@@ -887,6 +1006,24 @@ var BinOps = map[BinaryOperator]binOpOverload{
 			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
 				return NewDInt(MustBeDInt(left) << uint(MustBeDInt(right))), nil
 			},
+			preferredOverload: true,
+		},
+		BinOp{
+			LeftType:   TypeBitArray,
+			RightType:  TypeInt,
+			ReturnType: TypeBitArray,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				a := left.(*DBitArray).LeftShift(int64(MustBeDInt(right)))
+				return &DBitArray{BitArray: a}, nil
+			},
+		},
+		BinOp{
+			LeftType:   TypeINet,
+			RightType:  TypeINet,
+			ReturnType: TypeBool,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(right.(*DIPAddr).Contains(left.(*DIPAddr).IPAddr))), nil
+			},
 		},
 	},
 
@@ -898,6 +1035,46 @@ var BinOps = map[BinaryOperator]binOpOverload{
 			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
 				return NewDInt(MustBeDInt(left) >> uint(MustBeDInt(right))), nil
 			},
+			preferredOverload: true,
+		},
+		BinOp{
+			LeftType:   TypeBitArray,
+			RightType:  TypeInt,
+			ReturnType: TypeBitArray,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				a := left.(*DBitArray).LeftShift(-int64(MustBeDInt(right)))
+				return &DBitArray{BitArray: a}, nil
+			},
+		},
+		BinOp{
+			LeftType:   TypeINet,
+			RightType:  TypeINet,
+			ReturnType: TypeBool,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(left.(*DIPAddr).Contains(right.(*DIPAddr).IPAddr))), nil
+			},
+		},
+	},
+
+	INetContainedByOrEquals: {
+		BinOp{
+			LeftType:   TypeINet,
+			RightType:  TypeINet,
+			ReturnType: TypeBool,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(right.(*DIPAddr).ContainsOrEquals(left.(*DIPAddr).IPAddr))), nil
+			},
+		},
+	},
+
+	INetContainsOrEquals: {
+		BinOp{
+			LeftType:   TypeINet,
+			RightType:  TypeINet,
+			ReturnType: TypeBool,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(left.(*DIPAddr).ContainsOrEquals(right.(*DIPAddr).IPAddr))), nil
+			},
 		},
 	},
 
@@ -960,6 +1137,14 @@ var BinOps = map[BinaryOperator]binOpOverload{
 	},
 }
 
+// makeDTimestampFromDateAndTime returns the timestamp at time t on date d.
+func makeDTimestampFromDateAndTime(d *DDate, t *DTime) *DTimestamp {
+	year, month, day := time.Unix(int64(*d)*secondsInDay, 0).UTC().Date()
+	tod := timeofday.TimeOfDay(*t)
+	return MakeDTimestamp(time.Date(year, month, day, tod.Hour(), tod.Minute(), tod.Second(),
+		tod.Microsecond()*int(time.Microsecond), time.UTC), time.Microsecond)
+}
+
 var timestampMinusBinOp BinOp
 
 func init() {
@@ -1141,6 +1326,21 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 			RightType: TypeUUID,
 			fn:        cmpOpScalarEQFn,
 		},
+		CmpOp{
+			LeftType:  TypeINet,
+			RightType: TypeINet,
+			fn:        cmpOpScalarEQFn,
+		},
+		CmpOp{
+			LeftType:  TypeTime,
+			RightType: TypeTime,
+			fn:        cmpOpScalarEQFn,
+		},
+		CmpOp{
+			LeftType:  TypeBitArray,
+			RightType: TypeBitArray,
+			fn:        cmpOpScalarEQFn,
+		},
 		CmpOp{
 			LeftType:  TypeOid,
 			RightType: TypeOid,
@@ -1276,6 +1476,21 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 			RightType: TypeUUID,
 			fn:        cmpOpScalarLTFn,
 		},
+		CmpOp{
+			LeftType:  TypeINet,
+			RightType: TypeINet,
+			fn:        cmpOpScalarLTFn,
+		},
+		CmpOp{
+			LeftType:  TypeTime,
+			RightType: TypeTime,
+			fn:        cmpOpScalarLTFn,
+		},
+		CmpOp{
+			LeftType:  TypeBitArray,
+			RightType: TypeBitArray,
+			fn:        cmpOpScalarLTFn,
+		},
 		CmpOp{
 			LeftType:  TypeTuple,
 			RightType: TypeTuple,
@@ -1406,6 +1621,21 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 			RightType: TypeUUID,
 			fn:        cmpOpScalarLEFn,
 		},
+		CmpOp{
+			LeftType:  TypeINet,
+			RightType: TypeINet,
+			fn:        cmpOpScalarLEFn,
+		},
+		CmpOp{
+			LeftType:  TypeTime,
+			RightType: TypeTime,
+			fn:        cmpOpScalarLEFn,
+		},
+		CmpOp{
+			LeftType:  TypeBitArray,
+			RightType: TypeBitArray,
+			fn:        cmpOpScalarLEFn,
+		},
 		CmpOp{
 			LeftType:  TypeTuple,
 			RightType: TypeTuple,
@@ -1428,6 +1658,9 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeEvalTupleIn(TypeTimestampTZ),
 		makeEvalTupleIn(TypeInterval),
 		makeEvalTupleIn(TypeUUID),
+		makeEvalTupleIn(TypeINet),
+		makeEvalTupleIn(TypeTime),
+		makeEvalTupleIn(TypeBitArray),
 		makeEvalTupleIn(TypeTuple),
 		makeEvalTupleIn(TypeOid),
 	},
@@ -1732,6 +1965,8 @@ type EvalContext struct {
 	Location **time.Location
 	// Database is the database in the current Session.
 	Database string
+	// User is the user of the current Session.
+	User string
 	// SearchPath is the search path for databases used when encountering an
 	// unqualified table name. Names in the search path are normalized already.
 	// This must not be modified (this is shared from the session).
@@ -2076,6 +2311,25 @@ func queryOid(ctx *EvalContext, typ *OidColType, d Datum) (*DOid, error) {
 	return queryOidWithJoin(ctx, typ, d, "", "")
 }
 
+// castStringToBitArray parses a string of binary digits as a value of the
+// given BIT or VARBIT type. Unlike casts from other bit arrays, the parsed
+// value is never truncated or padded to fit the type.
+func castStringToBitArray(s string, typ *BitArrayColType) (*DBitArray, error) {
+	d, err := ParseDBitArray(s)
+	if err != nil {
+		return nil, err
+	}
+	if typ.Width > 0 {
+		if typ.Variable && d.BitLen() > typ.Width {
+			return nil, errors.Errorf("bit string too long for type %s", typ)
+		}
+		if !typ.Variable && d.BitLen() != typ.Width {
+			return nil, errors.Errorf("bit string length %d does not match type %s", d.BitLen(), typ)
+		}
+	}
+	return d, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 	d, err := expr.Expr.(TypedExpr).Eval(ctx)
@@ -2162,6 +2416,11 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 			res = NewDInt(DInt(v.Nanos / 1000000000))
 		case *DOid:
 			res = &v.DInt
+		case *DBitArray:
+			if v.BitLen() > 64 {
+				return nil, errIntOutOfRange
+			}
+			res = NewDInt(DInt(v.AsInt()))
 		}
 		return res, nil
 
@@ -2258,7 +2517,7 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 		switch t := d.(type) {
 		case *DBool, *DInt, *DFloat, *DDecimal, dNull:
 			s = d.String()
-		case *DTimestamp, *DTimestampTZ, *DDate:
+		case *DTimestamp, *DTimestampTZ, *DDate, *DTime, *DIPAddr, *DBitArray:
 			s = AsStringWithFlags(d, FmtBareStrings)
 		case *DInterval:
 			// When converting an interval to string, we need a string representation
@@ -2322,6 +2581,51 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 			return d, nil
 		}
 
+	case *INetColType:
+		var ipAddr *DIPAddr
+		switch t := d.(type) {
+		case *DString:
+			if typ.IsCIDR() {
+				return ParseDIPAddrFromCIDRString(string(*t))
+			}
+			return ParseDIPAddrFromINetString(string(*t))
+		case *DCollatedString:
+			if typ.IsCIDR() {
+				return ParseDIPAddrFromCIDRString(t.Contents)
+			}
+			return ParseDIPAddrFromINetString(t.Contents)
+		case *DIPAddr:
+			ipAddr = t
+		}
+		if ipAddr != nil {
+			if typ.IsCIDR() {
+				// Casting an INET to CIDR zeroes the bits to the right of the mask.
+				return NewDIPAddr(DIPAddr{ipAddr.Network()}), nil
+			}
+			return ipAddr, nil
+		}
+
+	case *BitArrayColType:
+		switch t := d.(type) {
+		case *DString:
+			return castStringToBitArray(string(*t), typ)
+		case *DCollatedString:
+			return castStringToBitArray(t.Contents, typ)
+		case *DInt:
+			width := typ.Width
+			if width == 0 {
+				width = 64
+			}
+			return &DBitArray{BitArray: bitarray.FromInt(width, int64(*t))}, nil
+		case *DBitArray:
+			// Casting between bit array types truncates or pads with zeros,
+			// except that arrays are never padded when cast to VARBIT.
+			if typ.Width == 0 || (typ.Variable && t.BitLen() <= typ.Width) {
+				return d, nil
+			}
+			return &DBitArray{BitArray: t.ToWidth(typ.Width)}, nil
+		}
+
 	case *DateColType:
 		switch d := d.(type) {
 		case *DString:
@@ -2373,6 +2677,22 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 			return d, nil
 		}
 
+	case *TimeColType:
+		switch d := d.(type) {
+		case *DString:
+			return ParseDTime(string(*d))
+		case *DCollatedString:
+			return ParseDTime(d.Contents)
+		case *DTime:
+			return d, nil
+		case *DTimestamp:
+			return MakeDTime(timeofday.FromTime(d.Time)), nil
+		case *DTimestampTZ:
+			return MakeDTime(timeofday.FromTime(d.Time.In(ctx.GetLocation()))), nil
+		case *DInterval:
+			return MakeDTime(timeofday.Min.Add(d.Duration)), nil
+		}
+
 	case *IntervalColType:
 		// TODO(knz): Interval from float, decimal.
 		switch v := d.(type) {
@@ -2383,6 +2703,8 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 		case *DInt:
 			// An integer duration represents a duration in microseconds.
 			return &DInterval{Duration: duration.Duration{Nanos: int64(*v) * 1000}}, nil
+		case *DTime:
+			return &DInterval{Duration: timeofday.Difference(timeofday.TimeOfDay(*v), timeofday.Min)}, nil
 		case *DInterval:
 			return d, nil
 		}
@@ -2859,6 +3181,21 @@ func (t *DUuid) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (t *DIPAddr) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (t *DTime) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (t *DBitArray) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (t *DDate) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
@@ -211,6 +211,24 @@ func TestEval(t *testing.T) {
 		{`'1 year 2 months 3 days 4 hours 5 minutes 6 seconds'::interval >= '1 year 2 months 3 days 4 hours 5 minutes 7 seconds'::interval`, `false`},
 		{`'5 minutes 6 seconds'::interval = '5 minutes 6 seconds'::interval`, `true`},
 		{`'PT2H30M'::interval = 'PT2H30M'::interval`, `true`},
+		{`'12:00:00'::time < '12:00:01'::time`, `true`},
+		{`'12:00:00'::time + '1h30m'::interval`, `'13:30:00'`},
+		{`'01:00:00'::time - '2h'::interval`, `'23:00:00'`},
+		{`'13:00:00'::time - '12:30:00'::time`, `'30m'`},
+		{`'2017-01-02'::date + '03:04:05'::time`, `'2017-01-02 03:04:05+00:00'`},
+		{`'192.168.1.2'::inet < '192.168.1.3'::inet`, `true`},
+		{`'192.168.1.2/24'::inet << '192.168.0.0/16'::inet`, `true`},
+		{`'192.168.0.0/16'::inet >> '192.168.0.0/16'::inet`, `false`},
+		{`'192.168.0.0/16'::inet >>= '192.168.0.0/16'::inet`, `true`},
+		{`'::1'::inet <<= '::/0'::inet`, `true`},
+		{`'101'::varbit & '110'::varbit`, `'100'`},
+		{`'101'::varbit | '110'::varbit`, `'111'`},
+		{`'101'::varbit # '110'::varbit`, `'011'`},
+		{`~'101'::varbit`, `'010'`},
+		{`'101'::varbit || '1'::varbit`, `'1011'`},
+		{`'1011'::varbit << 1`, `'0110'`},
+		{`'1011'::varbit >> 1`, `'0101'`},
+		{`'01'::varbit < '1'::varbit`, `true`},
 		// Comparisons against NULL result in NULL.
 		{`0 = NULL`, `NULL`},
 		{`0 < NULL`, `NULL`},
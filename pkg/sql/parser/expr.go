@@ -750,6 +750,8 @@ const (
 	Concat
 	LShift
 	RShift
+	INetContainedByOrEquals
+	INetContainsOrEquals
 )
 
 var binaryOpName = [...]string{
@@ -766,6 +768,9 @@ var binaryOpName = [...]string{
 	Concat:   "||",
 	LShift:   "<<",
 	RShift:   ">>",
+
+	INetContainedByOrEquals: "<<=",
+	INetContainsOrEquals:    ">>=",
 }
 
 func (i BinaryOperator) String() string {
@@ -1074,19 +1079,23 @@ func (node *CastExpr) castType() Type {
 var (
 	boolCastTypes = []Type{TypeNull, TypeBool, TypeInt, TypeFloat, TypeDecimal, TypeString, TypeCollatedString}
 	intCastTypes  = []Type{TypeNull, TypeBool, TypeInt, TypeFloat, TypeDecimal, TypeString, TypeCollatedString,
-		TypeTimestamp, TypeTimestampTZ, TypeDate, TypeInterval, TypeOid}
+		TypeTimestamp, TypeTimestampTZ, TypeDate, TypeInterval, TypeOid, TypeBitArray}
 	floatCastTypes = []Type{TypeNull, TypeBool, TypeInt, TypeFloat, TypeDecimal, TypeString, TypeCollatedString,
 		TypeTimestamp, TypeTimestampTZ, TypeDate, TypeInterval}
 	decimalCastTypes = []Type{TypeNull, TypeBool, TypeInt, TypeFloat, TypeDecimal, TypeString, TypeCollatedString,
 		TypeTimestamp, TypeTimestampTZ, TypeDate, TypeInterval}
 	stringCastTypes = []Type{TypeNull, TypeBool, TypeInt, TypeFloat, TypeDecimal, TypeString, TypeCollatedString,
-		TypeBytes, TypeTimestamp, TypeTimestampTZ, TypeInterval, TypeUUID, TypeDate, TypeOid}
+		TypeBytes, TypeTimestamp, TypeTimestampTZ, TypeInterval, TypeUUID, TypeDate, TypeOid,
+		TypeINet, TypeTime, TypeBitArray}
 	bytesCastTypes     = []Type{TypeNull, TypeString, TypeCollatedString, TypeBytes, TypeUUID}
 	dateCastTypes      = []Type{TypeNull, TypeString, TypeCollatedString, TypeDate, TypeTimestamp, TypeTimestampTZ, TypeInt}
 	timestampCastTypes = []Type{TypeNull, TypeString, TypeCollatedString, TypeDate, TypeTimestamp, TypeTimestampTZ, TypeInt}
-	intervalCastTypes  = []Type{TypeNull, TypeString, TypeCollatedString, TypeInt, TypeInterval}
+	timeCastTypes      = []Type{TypeNull, TypeString, TypeCollatedString, TypeTime, TypeTimestamp, TypeTimestampTZ, TypeInterval}
+	intervalCastTypes  = []Type{TypeNull, TypeString, TypeCollatedString, TypeInt, TypeTime, TypeInterval}
 	oidCastTypes       = []Type{TypeNull, TypeString, TypeCollatedString, TypeInt, TypeOid}
 	uuidCastTypes      = []Type{TypeNull, TypeString, TypeCollatedString, TypeBytes, TypeUUID}
+	inetCastTypes      = []Type{TypeNull, TypeString, TypeCollatedString, TypeINet}
+	bitArrayCastTypes  = []Type{TypeNull, TypeString, TypeCollatedString, TypeInt, TypeBitArray}
 )
 
 // validCastTypes returns a set of types that can be cast into the provided type.
@@ -1108,10 +1117,16 @@ func validCastTypes(t Type) []Type {
 		return dateCastTypes
 	case TypeTimestamp, TypeTimestampTZ:
 		return timestampCastTypes
+	case TypeTime:
+		return timeCastTypes
 	case TypeInterval:
 		return intervalCastTypes
 	case TypeUUID:
 		return uuidCastTypes
+	case TypeINet:
+		return inetCastTypes
+	case TypeBitArray:
+		return bitArrayCastTypes
 	case TypeOid, TypeRegClass, TypeRegNamespace, TypeRegProc, TypeRegProcedure, TypeRegType:
 		return oidCastTypes
 	default:
@@ -1216,6 +1231,7 @@ func (node *CoalesceExpr) String() string     { return AsString(node) }
 func (node *CollateExpr) String() string      { return AsString(node) }
 func (node *ComparisonExpr) String() string   { return AsString(node) }
 func (node *Datums) String() string           { return AsString(node) }
+func (node *DBitArray) String() string        { return AsString(node) }
 func (node *DBool) String() string            { return AsString(node) }
 func (node *DBytes) String() string           { return AsString(node) }
 func (node *DDate) String() string            { return AsString(node) }
@@ -1223,9 +1239,11 @@ func (node *DDecimal) String() string         { return AsString(node) }
 func (node *DFloat) String() string           { return AsString(node) }
 func (node *DInt) String() string             { return AsString(node) }
 func (node *DInterval) String() string        { return AsString(node) }
+func (node *DIPAddr) String() string          { return AsString(node) }
 func (node *DUuid) String() string            { return AsString(node) }
 func (node *DString) String() string          { return AsString(node) }
 func (node *DCollatedString) String() string  { return AsString(node) }
+func (node *DTime) String() string            { return AsString(node) }
 func (node *DTimestamp) String() string       { return AsString(node) }
 func (node *DTimestampTZ) String() string     { return AsString(node) }
 func (node *DTuple) String() string           { return AsString(node) }
@@ -19,6 +19,11 @@ package parser
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 )
 
 // Table generators, also called "set-generating functions", are
@@ -31,7 +36,7 @@ import (
 //
 // - the return type of generators is a TTable. This describes objects
 //   that are conceptually sets of rows. A TTable type is
-//   characterized by its column types (no names).
+//   characterized by its column types and, optionally, column labels.
 //
 // - a DTable doesn't carry the contents of a table directly; instead
 //   it carries a ValueGenerator reference.
@@ -79,7 +84,10 @@ type ValueGenerator interface {
 }
 
 var _ ValueGenerator = &seriesValueGenerator{}
+var _ ValueGenerator = &seriesTSValueGenerator{}
 var _ ValueGenerator = &arrayValueGenerator{}
+var _ ValueGenerator = &multipleArrayValueGenerator{}
+var _ ValueGenerator = &keywordsValueGenerator{}
 
 func initGeneratorBuiltins() {
 	// Add all windows to the Builtins map after a few sanity checks.
@@ -99,6 +107,10 @@ func initGeneratorBuiltins() {
 
 // Generators is a map from name to slice of Builtins for all built-in
 // generators.
+//
+// The JSON table functions of PostgreSQL (json_each and the like) are not
+// provided, since there is no JSON type to produce their arguments or
+// results yet.
 var Generators = map[string][]Builtin{
 	"generate_series": {
 		makeGeneratorBuiltin(
@@ -113,28 +125,57 @@ var Generators = map[string][]Builtin{
 			makeSeriesGenerator,
 			"Produces a virtual table containing the integer values from `start` to `end`, inclusive, by increment of `step`.",
 		),
+		makeGeneratorBuiltin(
+			ArgTypes{{"start", TypeTimestamp}, {"end", TypeTimestamp}, {"step", TypeInterval}},
+			TTuple{TypeTimestamp},
+			makeTSSeriesGenerator,
+			"Produces a virtual table containing the timestamp values from `start` to `end`, inclusive, by increment of `step`.",
+		),
+		makeGeneratorBuiltin(
+			ArgTypes{{"start", TypeTimestampTZ}, {"end", TypeTimestampTZ}, {"step", TypeInterval}},
+			TTuple{TypeTimestampTZ},
+			makeTSSeriesGenerator,
+			"Produces a virtual table containing the timestamp values from `start` to `end`, inclusive, by increment of `step`.",
+		),
+	},
+	"pg_get_keywords": {
+		makeGeneratorBuiltinWithReturnType(
+			ArgTypes{},
+			fixedReturnType(keywordsValueGeneratorType),
+			makeKeywordsGenerator,
+			"Produces a virtual table containing the keywords known to the SQL parser.",
+		),
 	},
 	"unnest": {
 		makeGeneratorBuiltinWithReturnType(
-			ArgTypes{{"input", TypeAnyArray}},
+			VariadicType{TypeAnyArray},
 			func(args []TypedExpr) Type {
 				if len(args) == 0 {
 					return unknownReturnType
 				}
-				return TTable{Cols: TTuple{args[0].ResolvedType().(TArray).Typ}}
+				cols := make(TTuple, len(args))
+				for i, arg := range args {
+					if t, ok := arg.ResolvedType().(TArray); ok {
+						cols[i] = t.Typ
+					} else {
+						cols[i] = TypeNull
+					}
+				}
+				return TTable{Cols: cols}
 			},
 			makeArrayGenerator,
-			"Returns the input array as a set of rows",
+			"Returns the input arrays as a set of rows. When several arrays are given, "+
+				"they are expanded side by side and the shorter ones are padded with NULLs.",
 		),
 	},
 }
 
-func makeGeneratorBuiltin(in ArgTypes, ret TTuple, g generatorFactory, info string) Builtin {
+func makeGeneratorBuiltin(in typeList, ret TTuple, g generatorFactory, info string) Builtin {
 	return makeGeneratorBuiltinWithReturnType(in, fixedReturnType(TTable{Cols: ret}), g, info)
 }
 
 func makeGeneratorBuiltinWithReturnType(
-	in ArgTypes, retType returnTyper, g generatorFactory, info string,
+	in typeList, retType returnTyper, g generatorFactory, info string,
 ) Builtin {
 	return Builtin{
 		impure:     true,
@@ -201,9 +242,99 @@ func (s *seriesValueGenerator) Values() Datums {
 	return Datums{NewDInt(DInt(s.value))}
 }
 
+// seriesTSValueGenerator supports the execution of generate_series()
+// with timestamp bounds and an interval step.
+type seriesTSValueGenerator struct {
+	value, start, stop time.Time
+	step               duration.Duration
+	// forward is true if adding step to a timestamp moves it forward
+	// in time.
+	forward bool
+	// withTZ determines whether the generator produces TIMESTAMPTZ or
+	// TIMESTAMP values.
+	withTZ bool
+}
+
+func makeTSSeriesGenerator(_ *EvalContext, args Datums) (ValueGenerator, error) {
+	var start, stop time.Time
+	withTZ := false
+	switch t := args[0].(type) {
+	case *DTimestamp:
+		start = t.Time
+		stop = args[1].(*DTimestamp).Time
+	case *DTimestampTZ:
+		start = t.Time
+		stop = args[1].(*DTimestampTZ).Time
+		withTZ = true
+	default:
+		return nil, fmt.Errorf("unsupported type for generate_series: %s", args[0].ResolvedType())
+	}
+	step := args[2].(*DInterval).Duration
+	next := duration.Add(start, step)
+	if next.Equal(start) {
+		return nil, errStepCannotBeZero
+	}
+	return &seriesTSValueGenerator{
+		start:   start,
+		stop:    stop,
+		step:    step,
+		forward: next.After(start),
+		withTZ:  withTZ,
+	}, nil
+}
+
+// ColumnTypes implements the ValueGenerator interface.
+func (s *seriesTSValueGenerator) ColumnTypes() TTuple {
+	if s.withTZ {
+		return TTuple{TypeTimestampTZ}
+	}
+	return TTuple{TypeTimestamp}
+}
+
+// Start implements the ValueGenerator interface.
+func (s *seriesTSValueGenerator) Start() error { return nil }
+
+// Close implements the ValueGenerator interface.
+func (s *seriesTSValueGenerator) Close() {}
+
+// Next implements the ValueGenerator interface.
+func (s *seriesTSValueGenerator) Next() (bool, error) {
+	if !s.forward && s.start.Before(s.stop) {
+		return false, nil
+	}
+	if s.forward && s.stop.Before(s.start) {
+		return false, nil
+	}
+	s.value = s.start
+	s.start = duration.Add(s.start, s.step)
+	return true, nil
+}
+
+// Values implements the ValueGenerator interface.
+func (s *seriesTSValueGenerator) Values() Datums {
+	if s.withTZ {
+		return Datums{MakeDTimestampTZ(s.value, time.Microsecond)}
+	}
+	return Datums{MakeDTimestamp(s.value, time.Microsecond)}
+}
+
 func makeArrayGenerator(_ *EvalContext, args Datums) (ValueGenerator, error) {
-	arr := MustBeDArray(args[0])
-	return &arrayValueGenerator{array: arr}, nil
+	if len(args) == 1 {
+		if args[0] == DNull {
+			return &arrayValueGenerator{array: NewDArray(TypeNull)}, nil
+		}
+		return &arrayValueGenerator{array: MustBeDArray(args[0])}, nil
+	}
+	arrays := make([]*DArray, len(args))
+	for i, arg := range args {
+		if arg == DNull {
+			// A NULL array produces no values, like an empty array.
+			arrays[i] = NewDArray(TypeNull)
+		} else {
+			arrays[i] = MustBeDArray(arg)
+		}
+	}
+	return &multipleArrayValueGenerator{arrays: arrays}, nil
 }
 
 // arrayValueGenerator is a value generator that returns each element of an
@@ -238,3 +369,104 @@ func (s *arrayValueGenerator) Next() (bool, error) {
 func (s *arrayValueGenerator) Values() Datums {
 	return Datums{s.array.Array[s.nextIndex]}
 }
+
+// multipleArrayValueGenerator is a value generator that returns the
+// elements of several arrays side by side. The number of rows is that
+// of the longest array; the shorter arrays are padded with NULLs.
+type multipleArrayValueGenerator struct {
+	arrays    []*DArray
+	nextIndex int
+	datums    Datums
+}
+
+// ColumnTypes implements the ValueGenerator interface.
+func (s *multipleArrayValueGenerator) ColumnTypes() TTuple {
+	ret := make(TTuple, len(s.arrays))
+	for i, arr := range s.arrays {
+		ret[i] = arr.ParamTyp
+	}
+	return ret
+}
+
+// Start implements the ValueGenerator interface.
+func (s *multipleArrayValueGenerator) Start() error {
+	s.datums = make(Datums, len(s.arrays))
+	s.nextIndex = -1
+	return nil
+}
+
+// Close implements the ValueGenerator interface.
+func (s *multipleArrayValueGenerator) Close() {}
+
+// Next implements the ValueGenerator interface.
+func (s *multipleArrayValueGenerator) Next() (bool, error) {
+	s.nextIndex++
+	found := false
+	for i, arr := range s.arrays {
+		s.datums[i] = DNull
+		if s.nextIndex < arr.Len() {
+			s.datums[i] = arr.Array[s.nextIndex]
+			found = true
+		}
+	}
+	return found, nil
+}
+
+// Values implements the ValueGenerator interface.
+func (s *multipleArrayValueGenerator) Values() Datums {
+	return s.datums
+}
+
+// keywordsValueGeneratorType is the type of the values produced by
+// pg_get_keywords().
+var keywordsValueGeneratorType = TTable{
+	Cols:   TTuple{TypeString, TypeString, TypeString},
+	Labels: []string{"word", "catcode", "catdesc"},
+}
+
+// keywordsValueGenerator supports the execution of pg_get_keywords().
+type keywordsValueGenerator struct {
+	words     []string
+	nextIndex int
+}
+
+func makeKeywordsGenerator(_ *EvalContext, _ Datums) (ValueGenerator, error) {
+	words := make([]string, 0, len(keywords))
+	for kw := range keywords {
+		words = append(words, kw)
+	}
+	sort.Strings(words)
+	return &keywordsValueGenerator{words: words}, nil
+}
+
+// ColumnTypes implements the ValueGenerator interface.
+func (k *keywordsValueGenerator) ColumnTypes() TTuple { return keywordsValueGeneratorType.Cols }
+
+// Start implements the ValueGenerator interface.
+func (k *keywordsValueGenerator) Start() error {
+	k.nextIndex = -1
+	return nil
+}
+
+// Close implements the ValueGenerator interface.
+func (k *keywordsValueGenerator) Close() {}
+
+// Next implements the ValueGenerator interface.
+func (k *keywordsValueGenerator) Next() (bool, error) {
+	k.nextIndex++
+	return k.nextIndex < len(k.words), nil
+}
+
+// Values implements the ValueGenerator interface.
+func (k *keywordsValueGenerator) Values() Datums {
+	kw := k.words[k.nextIndex]
+	catCode, catDesc := "U", "unreserved"
+	if _, ok := reservedKeywords[kw]; ok {
+		catCode, catDesc = "R", "reserved"
+	}
+	return Datums{
+		NewDString(strings.ToLower(kw)),
+		NewDString(catCode),
+		NewDString(catDesc),
+	}
+}
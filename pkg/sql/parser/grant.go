@@ -31,8 +31,11 @@ import (
 // Grant represents a GRANT statement.
 type Grant struct {
 	Privileges privilege.List
-	Targets    TargetList
-	Grantees   NameList
+	// Columns, if set, restricts the privileges to these columns of the
+	// target tables.
+	Columns  NameList
+	Targets  TargetList
+	Grantees NameList
 }
 
 // TargetList represents a list of targets.
@@ -56,8 +59,31 @@ func (tl TargetList) Format(buf *bytes.Buffer, f FmtFlags) {
 func (node *Grant) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString("GRANT ")
 	node.Privileges.Format(buf)
+	if node.Columns != nil {
+		buf.WriteString(" (")
+		FormatNode(buf, f, node.Columns)
+		buf.WriteByte(')')
+	}
 	buf.WriteString(" ON ")
 	FormatNode(buf, f, node.Targets)
 	buf.WriteString(" TO ")
 	FormatNode(buf, f, node.Grantees)
 }
+
+// GrantRole represents a GRANT <role> statement.
+type GrantRole struct {
+	Roles       NameList
+	Members     NameList
+	AdminOption bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GrantRole) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("GRANT ")
+	FormatNode(buf, f, node.Roles)
+	buf.WriteString(" TO ")
+	FormatNode(buf, f, node.Members)
+	if node.AdminOption {
+		buf.WriteString(" WITH ADMIN OPTION")
+	}
+}
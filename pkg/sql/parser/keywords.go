@@ -5,6 +5,7 @@ package parser
 var keywords = map[string]int{
 	"ACTION":                    ACTION,
 	"ADD":                       ADD,
+	"ADMIN":                     ADMIN,
 	"ALL":                       ALL,
 	"ALTER":                     ALTER,
 	"ANALYSE":                   ANALYSE,
@@ -34,10 +35,12 @@ var keywords = map[string]int{
 	"CASCADE":                   CASCADE,
 	"CASE":                      CASE,
 	"CAST":                      CAST,
+	"CHANGEFEED":                CHANGEFEED,
 	"CHAR":                      CHAR,
 	"CHARACTER":                 CHARACTER,
 	"CHARACTERISTICS":           CHARACTERISTICS,
 	"CHECK":                     CHECK,
+	"CIDR":                      CIDR,
 	"CLUSTER":                   CLUSTER,
 	"COALESCE":                  COALESCE,
 	"COLLATE":                   COLLATE,
@@ -47,12 +50,14 @@ var keywords = map[string]int{
 	"COMMIT":                    COMMIT,
 	"COMMITTED":                 COMMITTED,
 	"CONFLICT":                  CONFLICT,
+	"CONNECTION":                CONNECTION,
 	"CONSTRAINT":                CONSTRAINT,
 	"CONSTRAINTS":               CONSTRAINTS,
 	"COPY":                      COPY,
 	"COVERING":                  COVERING,
 	"CREATE":                    CREATE,
 	"CROSS":                     CROSS,
+	"CSV":                       CSV,
 	"CUBE":                      CUBE,
 	"CURRENT":                   CURRENT,
 	"CURRENT_CATALOG":           CURRENT_CATALOG,
@@ -73,19 +78,24 @@ var keywords = map[string]int{
 	"DEFAULT":                   DEFAULT,
 	"DEFERRABLE":                DEFERRABLE,
 	"DELETE":                    DELETE,
+	"DELIMITER":                 DELIMITER,
 	"DESC":                      DESC,
+	"DISABLE":                   DISABLE,
 	"DISTINCT":                  DISTINCT,
 	"DO":                        DO,
 	"DOUBLE":                    DOUBLE,
 	"DROP":                      DROP,
 	"ELSE":                      ELSE,
+	"ENABLE":                    ENABLE,
 	"ENCODING":                  ENCODING,
 	"END":                       END,
 	"EXCEPT":                    EXCEPT,
 	"EXECUTE":                   EXECUTE,
 	"EXISTS":                    EXISTS,
+	"EXPERIMENTAL_AUDIT":        EXPERIMENTAL_AUDIT,
 	"EXPERIMENTAL_FINGERPRINTS": EXPERIMENTAL_FINGERPRINTS,
 	"EXPLAIN":                   EXPLAIN,
+	"EXPORT":                    EXPORT,
 	"EXTRACT":                   EXTRACT,
 	"EXTRACT_DURATION":          EXTRACT_DURATION,
 	"FALSE":                     FALSE,
@@ -106,16 +116,19 @@ var keywords = map[string]int{
 	"GROUP":                     GROUP,
 	"GROUPING":                  GROUPING,
 	"HAVING":                    HAVING,
+	"HEADER":                    HEADER,
 	"HELP":                      HELP,
 	"HIGH":                      HIGH,
 	"HOUR":                      HOUR,
 	"IF":                        IF,
 	"IFNULL":                    IFNULL,
 	"ILIKE":                     ILIKE,
+	"IMPORT":                    IMPORT,
 	"IN":                        IN,
 	"INCREMENTAL":               INCREMENTAL,
 	"INDEX":                     INDEX,
 	"INDEXES":                   INDEXES,
+	"INET":                      INET,
 	"INITIALLY":                 INITIALLY,
 	"INNER":                     INNER,
 	"INSERT":                    INSERT,
@@ -147,6 +160,7 @@ var keywords = map[string]int{
 	"LOCAL":                     LOCAL,
 	"LOCALTIME":                 LOCALTIME,
 	"LOCALTIMESTAMP":            LOCALTIMESTAMP,
+	"LOGIN":                     LOGIN,
 	"LOW":                       LOW,
 	"MATCH":                     MATCH,
 	"MINUTE":                    MINUTE,
@@ -157,6 +171,7 @@ var keywords = map[string]int{
 	"NATURAL":                   NATURAL,
 	"NEXT":                      NEXT,
 	"NO":                        NO,
+	"NOLOGIN":                   NOLOGIN,
 	"NORMAL":                    NORMAL,
 	"NOT":                       NOT,
 	"NOTHING":                   NOTHING,
@@ -171,6 +186,7 @@ var keywords = map[string]int{
 	"OID":                       OID,
 	"ON":                        ON,
 	"ONLY":                      ONLY,
+	"OPTION":                    OPTION,
 	"OPTIONS":                   OPTIONS,
 	"OR":                        OR,
 	"ORDER":                     ORDER,
@@ -186,6 +202,7 @@ var keywords = map[string]int{
 	"PASSWORD":                  PASSWORD,
 	"PAUSE":                     PAUSE,
 	"PLACING":                   PLACING,
+	"POLICY":                    POLICY,
 	"POSITION":                  POSITION,
 	"PRECEDING":                 PRECEDING,
 	"PRECISION":                 PRECISION,
@@ -197,6 +214,7 @@ var keywords = map[string]int{
 	"RANGE":                     RANGE,
 	"READ":                      READ,
 	"REAL":                      REAL,
+	"RECURRING":                 RECURRING,
 	"RECURSIVE":                 RECURSIVE,
 	"REF":                       REF,
 	"REFERENCES":                REFERENCES,
@@ -215,14 +233,19 @@ var keywords = map[string]int{
 	"RETURNING":                 RETURNING,
 	"REVOKE":                    REVOKE,
 	"RIGHT":                     RIGHT,
+	"ROLE":                      ROLE,
+	"ROLES":                     ROLES,
 	"ROLLBACK":                  ROLLBACK,
 	"ROLLUP":                    ROLLUP,
 	"ROW":                       ROW,
 	"ROWS":                      ROWS,
 	"SAVEPOINT":                 SAVEPOINT,
 	"SCATTER":                   SCATTER,
+	"SCHEDULE":                  SCHEDULE,
+	"SCHEDULES":                 SCHEDULES,
 	"SEARCH":                    SEARCH,
 	"SECOND":                    SECOND,
+	"SECURITY":                  SECURITY,
 	"SELECT":                    SELECT,
 	"SERIAL":                    SERIAL,
 	"SERIALIZABLE":              SERIALIZABLE,
@@ -244,6 +267,7 @@ var keywords = map[string]int{
 	"START":                     START,
 	"STATUS":                    STATUS,
 	"STDIN":                     STDIN,
+	"STDOUT":                    STDOUT,
 	"STORING":                   STORING,
 	"STRICT":                    STRICT,
 	"STRING":                    STRING,
@@ -274,6 +298,7 @@ var keywords = map[string]int{
 	"UNION":                     UNION,
 	"UNIQUE":                    UNIQUE,
 	"UNKNOWN":                   UNKNOWN,
+	"UNTIL":                     UNTIL,
 	"UPDATE":                    UPDATE,
 	"UPSERT":                    UPSERT,
 	"USE":                       USE,
@@ -285,9 +310,11 @@ var keywords = map[string]int{
 	"VALIDATE":                  VALIDATE,
 	"VALUE":                     VALUE,
 	"VALUES":                    VALUES,
+	"VARBIT":                    VARBIT,
 	"VARCHAR":                   VARCHAR,
 	"VARIADIC":                  VARIADIC,
 	"VARYING":                   VARYING,
+	"VERIFY":                    VERIFY,
 	"VIEW":                      VIEW,
 	"WHEN":                      WHEN,
 	"WHERE":                     WHERE,
@@ -521,19 +521,27 @@ func typeCheckOverloadedExprs(
 		}
 	}
 
+	var preferredIdxs []int
+	for _, i := range overloadIdxs {
+		if overloads[i].preferred() {
+			preferredIdxs = append(preferredIdxs, i)
+		}
+	}
+
+	// If a single preferred candidate remains, let it determine the types of
+	// any placeholders instead of failing to infer them.
+	if len(preferredIdxs) == 1 && len(placeholderExprs) > 0 {
+		overloadIdxs = preferredIdxs
+		_, fn, err := checkReturn()
+		return typedExprs, fn, err
+	}
+
 	if err := defaultTypeCheck(len(overloadIdxs) > 0); err != nil {
 		return nil, nil, err
 	}
 
-	var preferred overloadImpl
-	for _, i := range overloadIdxs {
-		c := overloads[i]
-		if c.preferred() {
-			if preferred != nil {
-				return typedExprs, nil, nil
-			}
-			preferred = c
-		}
+	if len(preferredIdxs) != 1 {
+		return typedExprs, nil, nil
 	}
-	return typedExprs, preferred, nil
+	return typedExprs, overloads[preferredIdxs[0]], nil
 }
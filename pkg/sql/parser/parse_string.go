@@ -0,0 +1,60 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+//
+
+package parser
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ParseStringAs parses s as type t. It is used to convert the textual
+// representation of a value in an external data format (e.g. COPY or CSV) into
+// a Datum. Strings and bytes are taken verbatim.
+func ParseStringAs(t Type, s string, loc *time.Location) (Datum, error) {
+	switch t {
+	case TypeBool:
+		return ParseDBool(s)
+	case TypeBytes:
+		return NewDBytes(DBytes(s)), nil
+	case TypeDate:
+		return ParseDDate(s, loc)
+	case TypeDecimal:
+		return ParseDDecimal(s)
+	case TypeFloat:
+		return ParseDFloat(s)
+	case TypeInt:
+		return ParseDInt(s)
+	case TypeInterval:
+		return ParseDInterval(s)
+	case TypeString:
+		return NewDString(s), nil
+	case TypeTimestamp:
+		return ParseDTimestamp(s, time.Microsecond)
+	case TypeTimestampTZ:
+		return ParseDTimestampTZ(s, loc, time.Microsecond)
+	case TypeUUID:
+		return ParseDUuidFromString(s)
+	case TypeINet:
+		return ParseDIPAddrFromINetString(s)
+	case TypeTime:
+		return ParseDTime(s)
+	case TypeBitArray:
+		return ParseDBitArray(s)
+	default:
+		return nil, errors.Errorf("unknown type %s", t)
+	}
+}
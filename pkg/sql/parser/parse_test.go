@@ -78,6 +78,14 @@ func TestParse(t *testing.T) {
 		{`CREATE INDEX ON a (b) STORING (c)`},
 		{`CREATE INDEX ON a (b) INTERLEAVE IN PARENT c (d)`},
 		{`CREATE INDEX ON a (b ASC, c DESC)`},
+		{`CREATE INDEX ON a (lower(b))`},
+		{`CREATE INDEX ON a (lower(b) DESC, c)`},
+		{`CREATE INDEX ON a ((b + c))`},
+		{`CREATE INDEX ON a ((b || 'x') ASC) STORING (c)`},
+		{`CREATE INDEX ON a (b) WHERE c IS NULL`},
+		{`CREATE UNIQUE INDEX a ON b (c) STORING (d) WHERE (e > 0) AND (f = 'x')`},
+		{`CREATE INDEX IF NOT EXISTS a ON b (lower(c)) WHERE NOT d`},
+		{`CREATE UNIQUE INDEX a ON b (lower(c))`},
 		{`CREATE UNIQUE INDEX a ON b (c)`},
 		{`CREATE UNIQUE INDEX a ON b (c) STORING (d)`},
 		{`CREATE UNIQUE INDEX a ON b (c) INTERLEAVE IN PARENT d (e, f)`},
@@ -97,6 +105,12 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b SMALLSERIAL)`},
 		{`CREATE TABLE a (b BIGSERIAL)`},
 		{`CREATE TABLE a (b UUID)`},
+		{`CREATE TABLE a (b INET)`},
+		{`CREATE TABLE a (b CIDR)`},
+		{`CREATE TABLE a (b TIME)`},
+		{`CREATE TABLE a (b BIT(3))`},
+		{`CREATE TABLE a (b VARBIT)`},
+		{`CREATE TABLE a (b VARBIT(3))`},
 		{`CREATE TABLE a (b INT NULL)`},
 		{`CREATE TABLE a (b INT CONSTRAINT maybe NULL)`},
 		{`CREATE TABLE a (b INT NOT NULL)`},
@@ -121,6 +135,9 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT, c TEXT, FOREIGN KEY (b, c) REFERENCES other (x, y))`},
 		{`CREATE TABLE a (b INT, c TEXT, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y))`},
 		{`CREATE TABLE a (b INT, c TEXT, INDEX (b, c))`},
+		{`CREATE TABLE a (b INT, c TEXT, INDEX (lower(c), b))`},
+		{`CREATE TABLE a (b INT, c BOOL, INDEX (b) WHERE c)`},
+		{`CREATE TABLE a (b INT, c BOOL, CONSTRAINT d UNIQUE (b) WHERE NOT c)`},
 		{`CREATE TABLE a (b INT, c TEXT, INDEX d (b, c))`},
 		{`CREATE TABLE a (b INT, c TEXT, CONSTRAINT d UNIQUE (b, c))`},
 		{`CREATE TABLE a (b INT, c TEXT, CONSTRAINT d UNIQUE (b, c) INTERLEAVE IN PARENT d (e, f))`},
@@ -203,9 +220,24 @@ func TestParse(t *testing.T) {
 		{`DROP VIEW a.b CASCADE`},
 		{`DROP VIEW a, b CASCADE`},
 
+		{`CREATE USER a`},
+		{`CREATE USER a WITH NOLOGIN CONNECTION LIMIT 3 VALID UNTIL '2017-01-01'`},
+		{`ALTER USER a WITH LOGIN`},
+		{`ALTER USER IF EXISTS a WITH CONNECTION LIMIT -1 VALID UNTIL 'infinity'`},
 		{`DROP USER a`},
 		{`DROP USER a, b`},
 
+		{`CREATE ROLE a`},
+		{`DROP ROLE a`},
+		{`DROP ROLE IF EXISTS a, b`},
+
+		{`CREATE POLICY p ON a`},
+		{`CREATE POLICY p ON a.b FOR SELECT TO c, d USING (e = current_user())`},
+		{`CREATE POLICY p ON a FOR UPDATE USING (b > 1) WITH CHECK (b < 10)`},
+		{`CREATE POLICY p ON a FOR INSERT WITH CHECK (b = 1)`},
+		{`DROP POLICY p ON a`},
+		{`DROP POLICY IF EXISTS p ON a.b`},
+
 		{`EXPLAIN SELECT 1`},
 		{`EXPLAIN EXPLAIN SELECT 1`},
 		{`EXPLAIN (DEBUG) SELECT 1`},
@@ -234,6 +266,7 @@ func TestParse(t *testing.T) {
 		{`SHOW CONSTRAINTS FROM a.b.c`},
 		{`SHOW TABLES FROM a; SHOW COLUMNS FROM b`},
 		{`SHOW USERS`},
+		{`SHOW ROLES`},
 		{`SHOW CLUSTER QUERIES`},
 		{`SHOW LOCAL QUERIES`},
 		{`SHOW CLUSTER SESSIONS`},
@@ -288,6 +321,13 @@ func TestParse(t *testing.T) {
 		{`GRANT SELECT, INSERT ON DATABASE bar TO foo, bar, baz`},
 		{`GRANT SELECT, INSERT ON DATABASE db1, db2 TO foo, bar, baz`},
 		{`GRANT SELECT, INSERT ON DATABASE db1, db2 TO "test-user"`},
+		{`GRANT SELECT (a, b) ON foo TO root`},
+		{`GRANT SELECT, UPDATE (a) ON foo, db.foo TO root, bar`},
+		{`GRANT ALL (a) ON foo TO bar`},
+		{`GRANT foo TO bar`},
+		{`GRANT foo, bar TO baz, qux`},
+		{`GRANT foo TO bar WITH ADMIN OPTION`},
+		{`GRANT "select" TO bar`},
 
 		// Tables are the default, but can also be specified with
 		// REVOKE x ON TABLE y. However, the stringer does not output TABLE.
@@ -297,6 +337,11 @@ func TestParse(t *testing.T) {
 		{`REVOKE ALL ON DATABASE foo FROM root, test`},
 		{`REVOKE SELECT, INSERT ON DATABASE bar FROM foo, bar, baz`},
 		{`REVOKE SELECT, INSERT ON DATABASE db1, db2 FROM foo, bar, baz`},
+		{`REVOKE SELECT (a, b) ON foo FROM root`},
+		{`REVOKE ALL (a) ON foo FROM bar`},
+		{`REVOKE foo FROM bar`},
+		{`REVOKE foo, bar FROM baz, qux`},
+		{`REVOKE ADMIN OPTION FOR foo FROM bar`},
 
 		{`INSERT INTO a VALUES (1)`},
 		{`INSERT INTO a.b VALUES (1)`},
@@ -405,6 +450,7 @@ func TestParse(t *testing.T) {
 		{`SELECT DATE 'foo'`},
 		{`SELECT TIMESTAMP 'foo'`},
 		{`SELECT TIMESTAMP WITH TIME ZONE 'foo'`},
+		{`SELECT TIME 'foo'`},
 		{`SELECT CHAR 'foo'`},
 
 		{`SELECT 'a' AS "12345"`},
@@ -442,6 +488,13 @@ func TestParse(t *testing.T) {
 		{`SELECT a FROM generate_series(1, 32)`},
 		{`SELECT a FROM generate_series(1, 32) AS s (x)`},
 		{`SELECT a FROM generate_series(1, 32) WITH ORDINALITY AS s (x)`},
+		{`SELECT a FROM pg_get_keywords()`},
+		{`SELECT a FROM pg_get_keywords() WITH ORDINALITY AS k (w, c, d, n)`},
+		{`SELECT * FROM t, LATERAL (SELECT * FROM u WHERE u.x = t.x) AS l`},
+		{`SELECT * FROM t JOIN LATERAL (SELECT * FROM u WHERE u.x = t.x LIMIT 2) AS l ON true`},
+		{`SELECT * FROM t LEFT JOIN LATERAL generate_series(1, t.x) WITH ORDINALITY AS s (x, n) ON true`},
+		{`SELECT * FROM t, LATERAL pg_get_keywords()`},
+		{`SELECT a FROM unnest(ARRAY[1, 2], ARRAY['a']) AS u (x, y)`},
 		{`SELECT a FROM t1, t2`},
 		{`SELECT a FROM t AS t1`},
 		{`SELECT a FROM t AS t1 (c1)`},
@@ -648,6 +701,10 @@ func TestParse(t *testing.T) {
 		{`ALTER TABLE a DROP CONSTRAINT b CASCADE`},
 		{`ALTER TABLE a DROP CONSTRAINT IF EXISTS b RESTRICT`},
 		{`ALTER TABLE a VALIDATE CONSTRAINT a`},
+		{`ALTER TABLE a EXPERIMENTAL_AUDIT SET READ WRITE`},
+		{`ALTER TABLE a EXPERIMENTAL_AUDIT SET OFF`},
+		{`ALTER TABLE a ENABLE ROW LEVEL SECURITY`},
+		{`ALTER TABLE a DISABLE ROW LEVEL SECURITY`},
 
 		{`ALTER TABLE a ALTER COLUMN b SET DEFAULT 42`},
 		{`ALTER TABLE a ALTER COLUMN b SET DEFAULT NULL`},
@@ -657,6 +714,11 @@ func TestParse(t *testing.T) {
 
 		{`COPY t FROM STDIN`},
 		{`COPY t (a, b, c) FROM STDIN`},
+		{`COPY t TO STDOUT`},
+		{`COPY t (a, b, c) TO STDOUT`},
+		{`COPY (SELECT a FROM t WHERE b > 1) TO STDOUT`},
+		{`COPY t TO STDOUT WITH (format 'csv', header)`},
+		{`COPY t TO STDOUT WITH (delimiter '|', "null" 'NULL')`},
 
 		{`ALTER TABLE a SPLIT AT VALUES (1)`},
 		{`ALTER TABLE a SPLIT AT SELECT * FROM t`},
@@ -692,8 +754,29 @@ func TestParse(t *testing.T) {
 		{`RESTORE DATABASE foo FROM 'bar'`},
 		{`RESTORE DATABASE foo, baz FROM 'bar'`},
 		{`RESTORE DATABASE foo, baz FROM 'bar' AS OF SYSTEM TIME '1'`},
+		{`RESTORE DATABASE foo AS baz FROM 'bar'`},
+		{`RESTORE foo.bar AS baz FROM 'bar' AS OF SYSTEM TIME '1'`},
 		{`BACKUP foo TO 'bar' WITH OPTIONS ('key1', 'key2'='value')`},
 		{`RESTORE foo FROM 'bar' WITH OPTIONS ('key1', 'key2'='value')`},
+		{`SHOW BACKUP 'bar' WITH OPTIONS ('encryption_passphrase'='secret')`},
+		{`VERIFY BACKUP 'bar'`},
+		{`VERIFY BACKUP 'bar', 'baz' WITH OPTIONS ('fingerprints')`},
+		{`VERIFY BACKUP $1, $2`},
+		{`CREATE CHANGEFEED FOR TABLE foo INTO 'sink'`},
+		{`CREATE CHANGEFEED FOR TABLE foo, db.bar INTO $1 WITH OPTIONS ('poll_interval'='1s')`},
+		{`CREATE SCHEDULE FOR BACKUP foo TO 'bar' RECURRING '@daily'`},
+		{`CREATE SCHEDULE nightly FOR BACKUP DATABASE foo TO $1 WITH OPTIONS ('key1'='value') RECURRING '@daily' FULL BACKUP '@weekly'`},
+		{`SHOW SCHEDULES`},
+		{`PAUSE SCHEDULE 1`},
+		{`RESUME SCHEDULE 1`},
+		{`DROP SCHEDULE 1`},
+		{`IMPORT TABLE foo CREATE USING 'nodelocal:///some/file' CSV DATA ('path/to/some/file', $1) WITH OPTIONS ('temp'='path/to/temp')`},
+		{`IMPORT TABLE foo (id INT PRIMARY KEY, email STRING, age INT) CSV DATA ('path/to/some/file', $1) WITH OPTIONS ('temp'='path/to/temp')`},
+		{`IMPORT TABLE foo.bar CREATE USING $1 CSV DATA ($2)`},
+
+		{`EXPORT INTO CSV 'a' FROM TABLE a`},
+		{`EXPORT INTO CSV 'a' FROM SELECT * FROM a`},
+		{`EXPORT INTO CSV 's3://my/path' WITH OPTIONS ('delimiter'='|') FROM SELECT a, sum(b) FROM c WHERE d = 1 ORDER BY sum(b) DESC LIMIT 10`},
 		{`SET ROW (1, true, NULL)`},
 
 		// Regression for #15926
@@ -721,6 +804,11 @@ func TestParse2(t *testing.T) {
 	}{
 		{`CREATE DATABASE a WITH ENCODING = 'foo'`,
 			`CREATE DATABASE a ENCODING = 'foo'`},
+		{`CREATE USER a VALID UNTIL '2017-01-01' NOLOGIN`,
+			`CREATE USER a WITH NOLOGIN VALID UNTIL '2017-01-01'`},
+		{`ALTER USER a LOGIN`, `ALTER USER a WITH LOGIN`},
+		{`CREATE POLICY p ON a FOR ALL USING (true)`,
+			`CREATE POLICY p ON a USING (true)`},
 		{`CREATE DATABASE a TEMPLATE = template0`,
 			`CREATE DATABASE a TEMPLATE = 'template0'`},
 		{`CREATE DATABASE a TEMPLATE = invalid`,
@@ -729,6 +817,8 @@ func TestParse2(t *testing.T) {
 			`CREATE TABLE a (b INT, CONSTRAINT foo UNIQUE (b))`},
 		{`CREATE TABLE a (b INT, UNIQUE INDEX foo (b) INTERLEAVE IN PARENT c (d))`,
 			`CREATE TABLE a (b INT, CONSTRAINT foo UNIQUE (b) INTERLEAVE IN PARENT c (d))`},
+		{`CREATE TABLE a (b INT, c BOOL, UNIQUE INDEX d (b) WHERE NOT c)`,
+			`CREATE TABLE a (b INT, c BOOL, CONSTRAINT d UNIQUE (b) WHERE NOT c)`},
 		{`CREATE INDEX ON a (b) COVERING (c)`, `CREATE INDEX ON a (b) STORING (c)`},
 
 		{`SELECT TIMESTAMP WITHOUT TIME ZONE 'foo'`, `SELECT TIMESTAMP 'foo'`},
@@ -781,6 +871,8 @@ func TestParse2(t *testing.T) {
 			`SELECT "a'a" FROM t`},
 		// Hexadecimal literal strings are turned into regular strings.
 		{`SELECT x'61'`, `SELECT b'a'`},
+		{`CREATE TABLE a (b BIT VARYING(3))`, `CREATE TABLE a (b VARBIT(3))`},
+		{`CREATE TABLE a (b TIME WITHOUT TIME ZONE)`, `CREATE TABLE a (b TIME)`},
 		{`SELECT X'61'`, `SELECT b'a'`},
 		// Comments are stripped.
 		{`SELECT 1 FROM t -- hello world`,
@@ -807,6 +899,10 @@ func TestParse2(t *testing.T) {
 			`SELECT current_timestamp()`},
 		{`SELECT CURRENT_DATE`,
 			`SELECT current_date()`},
+		{`SELECT LOCALTIME`,
+			`SELECT localtime()`},
+		{`SELECT FAMILY(a)`,
+			`SELECT family(a)`},
 		{`SELECT POSITION(a IN b)`,
 			`SELECT strpos(b, a)`},
 		{`SELECT TRIM(BOTH a FROM b)`,
@@ -967,6 +1063,17 @@ func TestParse2(t *testing.T) {
 			`BACKUP DATABASE foo TO 'bar.12' INCREMENTAL FROM 'baz.34'`},
 		{`RESTORE DATABASE foo FROM bar`,
 			`RESTORE DATABASE foo FROM 'bar'`},
+		{`IMPORT TABLE foo CREATE USING 'create.sql' csv DATA ('a', 'b')`,
+			`IMPORT TABLE foo CREATE USING 'create.sql' CSV DATA ('a', 'b')`},
+		{`EXPORT INTO csv 'a' FROM SELECT 1`,
+			`EXPORT INTO CSV 'a' FROM SELECT 1`},
+
+		{`COPY t TO STDOUT CSV HEADER`,
+			`COPY t TO STDOUT WITH (format 'csv', header 'true')`},
+		{`COPY t TO STDOUT WITH DELIMITER AS ',' NULL 'x'`,
+			`COPY t TO STDOUT WITH (delimiter ',', "null" 'x')`},
+		{`COPY t TO STDOUT (FORMAT csv, HEADER true)`,
+			`COPY t TO STDOUT WITH (format 'csv', header 'true')`},
 
 		{`SHOW ALL CLUSTER SETTINGS`, `SHOW CLUSTER SETTING all`},
 
@@ -1255,6 +1362,13 @@ SELECT CAST(1.2+2.3 AS notatype)
 			`syntax error at or near "notatype"
 SELECT ANNOTATE_TYPE(1.2+2.3, notatype)
                               ^
+`,
+		},
+		{
+			`GRANT foo ON t TO bar`,
+			`not a valid privilege: "foo" at or near "on"
+GRANT foo ON t TO bar
+          ^
 `,
 		},
 		{
@@ -1373,7 +1487,7 @@ func TestParsePrecedence(t *testing.T) {
 	//   0: - ~
 	//   1: * / // %
 	//   2: + -
-	//   3: << >>
+	//   3: << >> <<= >>=
 	//   4: &
 	//   5: ^
 	//   6: |
@@ -1474,6 +1588,8 @@ func TestParsePrecedence(t *testing.T) {
 		{`1<<2>>3`, binary(RShift, binary(LShift, one, two), three)},
 		{`1>>2<<3`, binary(LShift, binary(RShift, one, two), three)},
 		{`1>>2>>3`, binary(RShift, binary(RShift, one, two), three)},
+		{`1<<=2+3`, binary(INetContainedByOrEquals, one, binary(Plus, two, three))},
+		{`1>>=2<<3`, binary(LShift, binary(INetContainsOrEquals, one, two), three)},
 
 		// Power combined with lower precedence.
 		{`1*2^3`, binary(Mult, one, binary(Pow, two, three))},
@@ -35,9 +35,14 @@ const notUsableInfo = "Not usable; exposed only for compatibility with PostgreSQ
 // the existence of this map.
 var typeBuiltinsHaveUnderscore = map[oid.Oid]struct{}{
 	TypeAny.Oid():         {},
+	TypeBitArray.Oid():    {},
+	typeBit.Oid():         {},
+	typeCIDR.Oid():        {},
 	TypeDate.Oid():        {},
 	TypeDecimal.Oid():     {},
+	TypeINet.Oid():        {},
 	TypeInterval.Oid():    {},
+	TypeTime.Oid():        {},
 	TypeUUID.Oid():        {},
 	TypeTimestamp.Oid():   {},
 	TypeTimestampTZ.Oid(): {},
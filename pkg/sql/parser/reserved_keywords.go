@@ -28,6 +28,7 @@ var reservedKeywords = map[string]struct{}{
 	"CHARACTER":         {},
 	"CHARACTERISTICS":   {},
 	"CHECK":             {},
+	"CIDR":              {},
 	"COALESCE":          {},
 	"COLLATE":           {},
 	"COLLATION":         {},
@@ -73,6 +74,7 @@ var reservedKeywords = map[string]struct{}{
 	"ILIKE":             {},
 	"IN":                {},
 	"INDEX":             {},
+	"INET":              {},
 	"INITIALLY":         {},
 	"INNER":             {},
 	"INT":               {},
@@ -143,6 +145,7 @@ var reservedKeywords = map[string]struct{}{
 	"USING":             {},
 	"UUID":              {},
 	"VALUES":            {},
+	"VARBIT":            {},
 	"VARCHAR":           {},
 	"VARIADIC":          {},
 	"VIEW":              {},
@@ -40,6 +40,39 @@ func (node *ResumeJob) Format(buf *bytes.Buffer, f FmtFlags) {
 	FormatNode(buf, f, node.ID)
 }
 
+// PauseSchedule represents a PAUSE SCHEDULE statement.
+type PauseSchedule struct {
+	ID Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *PauseSchedule) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("PAUSE SCHEDULE ")
+	FormatNode(buf, f, node.ID)
+}
+
+// ResumeSchedule represents a RESUME SCHEDULE statement.
+type ResumeSchedule struct {
+	ID Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ResumeSchedule) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("RESUME SCHEDULE ")
+	FormatNode(buf, f, node.ID)
+}
+
+// DropSchedule represents a DROP SCHEDULE statement.
+type DropSchedule struct {
+	ID Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropSchedule) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("DROP SCHEDULE ")
+	FormatNode(buf, f, node.ID)
+}
+
 // CancelJob represents a CANCEL JOB statement.
 type CancelJob struct {
 	ID Expr
@@ -294,6 +294,11 @@ func (s *Scanner) scan(lval *sqlSymType) {
 		switch s.peek() {
 		case '<': // <<
 			s.pos++
+			if s.peek() == '=' { // <<=
+				s.pos++
+				lval.id = INET_CONTAINED_BY_OR_EQUALS
+				return
+			}
 			lval.id = LSHIFT
 			return
 		case '>': // <>
@@ -311,6 +316,11 @@ func (s *Scanner) scan(lval *sqlSymType) {
 		switch s.peek() {
 		case '>': // >>
 			s.pos++
+			if s.peek() == '=' { // >>=
+				s.pos++
+				lval.id = INET_CONTAINS_OR_EQUALS
+				return
+			}
 			lval.id = RSHIFT
 			return
 		case '=': // >=
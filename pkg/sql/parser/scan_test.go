@@ -41,9 +41,11 @@ func TestScanner(t *testing.T) {
 		{`<>`, []int{NOT_EQUALS}},
 		{`<=`, []int{LESS_EQUALS}},
 		{`<<`, []int{LSHIFT}},
+		{`<<=`, []int{INET_CONTAINED_BY_OR_EQUALS}},
 		{`>`, []int{'>'}},
 		{`>=`, []int{GREATER_EQUALS}},
 		{`>>`, []int{RSHIFT}},
+		{`>>=`, []int{INET_CONTAINS_OR_EQUALS}},
 		{`=`, []int{'='}},
 		{`:`, []int{':'}},
 		{`::`, []int{TYPECAST}},
@@ -273,11 +273,17 @@ type AliasedTableExpr struct {
 	Expr       TableExpr
 	Hints      *IndexHints
 	Ordinality bool
-	As         AliasClause
+	// Lateral is set when the table expression is preceded by LATERAL
+	// and can thus refer to the FROM items on its left.
+	Lateral bool
+	As      AliasClause
 }
 
 // Format implements the NodeFormatter interface.
 func (node *AliasedTableExpr) Format(buf *bytes.Buffer, f FmtFlags) {
+	if node.Lateral {
+		buf.WriteString("LATERAL ")
+	}
 	FormatNode(buf, f, node.Expr)
 	if node.Hints != nil {
 		FormatNode(buf, f, node.Hints)
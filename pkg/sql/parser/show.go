@@ -44,13 +44,19 @@ func (node *Show) Format(buf *bytes.Buffer, f FmtFlags) {
 
 // ShowBackup represents a SHOW BACKUP statement.
 type ShowBackup struct {
-	Path Expr
+	Path    Expr
+	Options KVOptions
 }
 
 // Format implements the NodeFormatter interface.
 func (node *ShowBackup) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString("SHOW BACKUP ")
 	FormatNode(buf, f, node.Path)
+	if node.Options != nil {
+		buf.WriteString(" WITH OPTIONS (")
+		FormatNode(buf, f, node.Options)
+		buf.WriteString(")")
+	}
 }
 
 // ShowColumns represents a SHOW COLUMNS statement.
@@ -222,6 +228,24 @@ func (node *ShowUsers) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString("SHOW USERS")
 }
 
+// ShowRoles represents a SHOW ROLES statement.
+type ShowRoles struct {
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ShowRoles) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("SHOW ROLES")
+}
+
+// ShowSchedules represents a SHOW SCHEDULES statement.
+type ShowSchedules struct {
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ShowSchedules) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("SHOW SCHEDULES")
+}
+
 // Help represents a HELP statement.
 type Help struct {
 	Name Name
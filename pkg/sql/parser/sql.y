@@ -24,6 +24,7 @@ import (
 
     "go/constant"
     "go/token"
+    "strings"
 
     "github.com/cockroachdb/cockroach/pkg/sql/privilege"
 )
@@ -102,9 +103,6 @@ func (u *sqlSymUnion) strVal() *StrVal {
 func (u *sqlSymUnion) bool() bool {
     return u.val.(bool)
 }
-func (u *sqlSymUnion) strPtr() *string {
-    return u.val.(*string)
-}
 func (u *sqlSymUnion) strs() []string {
     return u.val.([]string)
 }
@@ -267,9 +265,6 @@ func (u *sqlSymUnion) targetList() TargetList {
 func (u *sqlSymUnion) targetListPtr() *TargetList {
     return u.val.(*TargetList)
 }
-func (u *sqlSymUnion) privilegeType() privilege.Kind {
-    return u.val.(privilege.Kind)
-}
 func (u *sqlSymUnion) privilegeList() privilege.List {
     return u.val.(privilege.List)
 }
@@ -318,6 +313,15 @@ func (u *sqlSymUnion) dropBehavior() DropBehavior {
 func (u *sqlSymUnion) validationBehavior() ValidationBehavior {
     return u.val.(ValidationBehavior)
 }
+func (u *sqlSymUnion) auditMode() AuditMode {
+    return u.val.(AuditMode)
+}
+func (u *sqlSymUnion) policyCommand() PolicyCommand {
+    return u.val.(PolicyCommand)
+}
+func (u *sqlSymUnion) userOptions() UserOptions {
+    return u.val.(UserOptions)
+}
 func (u *sqlSymUnion) interleave() *InterleaveDef {
     return u.val.(*InterleaveDef)
 }
@@ -360,6 +364,7 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %token <str>   PLACEHOLDER
 %token <str>   TYPECAST TYPEANNOTATE DOT_DOT
 %token <str>   LESS_EQUALS GREATER_EQUALS NOT_EQUALS
+%token <str>   INET_CONTAINED_BY_OR_EQUALS INET_CONTAINS_OR_EQUALS
 %token <str>   NOT_REGMATCH REGIMATCH NOT_REGIMATCH
 %token <str>   ERROR
 
@@ -369,38 +374,38 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 // "Keyword category lists".
 
 // Ordinary key words in alphabetical order.
-%token <str>   ACTION ADD
+%token <str>   ACTION ADD ADMIN
 %token <str>   ALL ALTER ANALYSE ANALYZE AND ANY ANNOTATE_TYPE ARRAY AS ASC
 %token <str>   ASYMMETRIC AT
 
 %token <str>   BACKUP BEGIN BETWEEN BIGINT BIGSERIAL BIT
 %token <str>   BLOB BOOL BOOLEAN BOTH BY BYTEA BYTES
 
-%token <str>   CANCEL CASCADE CASE CAST CHAR
-%token <str>   CHARACTER CHARACTERISTICS CHECK
+%token <str>   CANCEL CASCADE CASE CAST CHANGEFEED CHAR
+%token <str>   CHARACTER CHARACTERISTICS CHECK CIDR
 %token <str>   CLUSTER COALESCE COLLATE COLLATION COLUMN COLUMNS COMMIT
-%token <str>   COMMITTED CONCAT CONFLICT CONSTRAINT CONSTRAINTS
+%token <str>   COMMITTED CONCAT CONFLICT CONNECTION CONSTRAINT CONSTRAINTS
 %token <str>   COPY COVERING CREATE
-%token <str>   CROSS CUBE CURRENT CURRENT_CATALOG CURRENT_DATE
+%token <str>   CROSS CSV CUBE CURRENT CURRENT_CATALOG CURRENT_DATE
 %token <str>   CURRENT_ROLE CURRENT_TIME CURRENT_TIMESTAMP
 %token <str>   CURRENT_USER CYCLE
 
 %token <str>   DATA DATABASE DATABASES DATE DAY DEC DECIMAL DEFAULT
-%token <str>   DEALLOCATE DEFERRABLE DELETE DESC
-%token <str>   DISTINCT DO DOUBLE DROP
+%token <str>   DEALLOCATE DEFERRABLE DELETE DELIMITER DESC
+%token <str>   DISABLE DISTINCT DO DOUBLE DROP
 
-%token <str>   ELSE ENCODING END ESCAPE EXCEPT
-%token <str>   EXISTS EXECUTE EXPERIMENTAL_FINGERPRINTS EXPLAIN EXTRACT EXTRACT_DURATION
+%token <str>   ELSE ENABLE ENCODING END ESCAPE EXCEPT
+%token <str>   EXISTS EXECUTE EXPERIMENTAL_AUDIT EXPERIMENTAL_FINGERPRINTS EXPLAIN EXPORT EXTRACT EXTRACT_DURATION
 
 %token <str>   FALSE FAMILY FETCH FILTER FIRST FLOAT FLOORDIV FOLLOWING FOR
 %token <str>   FORCE_INDEX FOREIGN FROM FULL
 
 %token <str>   GRANT GRANTS GREATEST GROUP GROUPING
 
-%token <str>   HAVING HELP HIGH HOUR
+%token <str>   HAVING HEADER HELP HIGH HOUR
 
-%token <str>   INCREMENTAL IF IFNULL ILIKE IN INTERLEAVE
-%token <str>   INDEX INDEXES INITIALLY
+%token <str>   IF IFNULL ILIKE IMPORT IN INCREMENTAL INTERLEAVE
+%token <str>   INDEX INDEXES INET INITIALLY
 %token <str>   INNER INSERT INT INT2VECTOR INT8 INT64 INTEGER
 %token <str>   INTERSECT INTERVAL INTO IS ISOLATION
 
@@ -410,42 +415,42 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 
 %token <str>   LATERAL LC_CTYPE LC_COLLATE
 %token <str>   LEADING LEAST LEFT LEVEL LIKE LIMIT LOCAL
-%token <str>   LOCALTIME LOCALTIMESTAMP LOW LSHIFT
+%token <str>   LOCALTIME LOCALTIMESTAMP LOGIN LOW LSHIFT
 
 %token <str>   MATCH MINUTE MONTH
 
-%token <str>   NAN NAME NAMES NATURAL NEXT NO NO_INDEX_JOIN NORMAL
+%token <str>   NAN NAME NAMES NATURAL NEXT NO NO_INDEX_JOIN NOLOGIN NORMAL
 %token <str>   NOT NOTHING NULL NULLIF
 %token <str>   NULLS NUMERIC
 
-%token <str>   OF OFF OFFSET OID ON ONLY OPTIONS OR
+%token <str>   OF OFF OFFSET OID ON ONLY OPTION OPTIONS OR
 %token <str>   ORDER ORDINALITY OUT OUTER OVER OVERLAPS OVERLAY
 
-%token <str>   PARENT PARTIAL PARTITION PASSWORD PAUSE PLACING POSITION
+%token <str>   PARENT PARTIAL PARTITION PASSWORD PAUSE PLACING POLICY POSITION
 %token <str>   PRECEDING PRECISION PREPARE PRIMARY PRIORITY
 
 %token <str>   QUERIES QUERY
 
-%token <str>   RANGE READ REAL RECURSIVE REF REFERENCES
+%token <str>   RANGE READ REAL RECURRING RECURSIVE REF REFERENCES
 %token <str>   REGCLASS REGPROC REGPROCEDURE REGNAMESPACE REGTYPE
 %token <str>   RENAME REPEATABLE
 %token <str>   RELEASE RESET RESTORE RESTRICT RESUME RETURNING REVOKE RIGHT
-%token <str>   ROLLBACK ROLLUP ROW ROWS RSHIFT
+%token <str>   ROLE ROLES ROLLBACK ROLLUP ROW ROWS RSHIFT
 
-%token <str>   SAVEPOINT SCATTER SEARCH SECOND SELECT
+%token <str>   SAVEPOINT SCATTER SCHEDULE SCHEDULES SEARCH SECOND SECURITY SELECT
 %token <str>   SERIAL SERIALIZABLE SESSION SESSIONS SESSION_USER SET SETTING SETTINGS
 %token <str>   SHOW SIMILAR SIMPLE SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
-%token <str>   START STATUS STDIN STRICT STRING STORING SUBSTRING
+%token <str>   START STATUS STDIN STDOUT STRICT STRING STORING SUBSTRING
 %token <str>   SYMMETRIC SYSTEM
 
 %token <str>   TABLE TABLES TEMPLATE TESTING_RANGES TESTING_RELOCATE TEXT THEN
 %token <str>   TIME TIMESTAMP TIMESTAMPTZ TO TRAILING TRACE TRANSACTION TREAT TRIM TRUE
 %token <str>   TRUNCATE TYPE
 
-%token <str>   UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN
+%token <str>   UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNTIL
 %token <str>   UPDATE UPSERT USE USER USERS USING UUID
 
-%token <str>   VALID VALIDATE VALUE VALUES VARCHAR VARIADIC VIEW VARYING
+%token <str>   VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VERIFY VIEW VARYING
 
 %token <str>   WHEN WHERE WINDOW WITH WITHIN WITHOUT WRITE
 
@@ -476,20 +481,28 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %type <Statement> stmt
 
 %type <Statement> alter_table_stmt
+%type <Statement> alter_user_stmt
 %type <Statement> backup_stmt
 %type <Statement> cancel_stmt
 %type <Statement> copy_from_stmt
+%type <Statement> copy_to_stmt
 %type <Statement> create_stmt
+%type <Statement> create_changefeed_stmt
 %type <Statement> create_database_stmt
+%type <Statement> create_schedule_stmt
 %type <Statement> create_index_stmt
 %type <Statement> create_table_stmt
 %type <Statement> create_table_as_stmt
+%type <Statement> create_policy_stmt
+%type <Statement> create_role_stmt
 %type <Statement> create_user_stmt
 %type <Statement> create_view_stmt
 %type <Statement> delete_stmt
 %type <Statement> drop_stmt
 %type <Statement> explain_stmt
+%type <Statement> export_stmt
 %type <Statement> help_stmt
+%type <Statement> import_stmt
 %type <Statement> prepare_stmt
 %type <Statement> preparable_stmt
 %type <Statement> execute_stmt
@@ -513,10 +526,14 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %type <Statement> truncate_stmt
 %type <Statement> update_stmt
 %type <Statement> use_stmt
+%type <Statement> verify_backup_stmt
 
 %type <[]string> opt_incremental
 %type <KVOption> kv_option
 %type <[]KVOption> kv_option_list opt_with_options
+%type <KVOption> copy_generic_option copy_legacy_option
+%type <[]KVOption> opt_copy_options copy_generic_option_list copy_legacy_option_list
+%type <str> copy_generic_option_arg
 %type <str> opt_equal_value
 
 %type <*Select> select_no_parens
@@ -532,12 +549,16 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %type <empty> opt_collate_clause
 
 %type <DropBehavior> opt_drop_behavior
+%type <AuditMode> audit_mode
+%type <PolicyCommand> opt_policy_command
+%type <NameList> opt_policy_roles
+%type <Expr> opt_policy_using opt_policy_with_check
 %type <DropBehavior> opt_interleave_drop_behavior
 
 %type <ValidationBehavior> opt_validate_behavior
 
 %type <str> opt_template_clause opt_encoding_clause opt_lc_collate_clause opt_lc_ctype_clause
-%type <*string> opt_password
+%type <UserOptions> opt_user_options user_options user_option
 
 %type <IsolationLevel> transaction_iso_level
 %type <UserPriority>  transaction_user_priority
@@ -545,6 +566,7 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 
 %type <str>   name opt_name opt_name_parens opt_to_savepoint
 %type <str>   savepoint_name
+%type <str>   import_format
 
 %type <operator> subquery_op
 %type <FunctionReference> func_name
@@ -677,7 +699,7 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %type <CastTargetType> postgres_oid
 %type <CastTargetType> cast_target
 %type <str> extract_arg
-%type <empty> opt_varying
+%type <bool> opt_varying
 
 %type <*NumVal>  signed_iconst
 %type <Expr>  opt_boolean_or_string
@@ -689,6 +711,7 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %type <Expr>  var_value
 %type <Expr>  zone_value
 %type <Expr> string_or_placeholder
+%type <Expr> opt_full_backup
 %type <Expr> string_or_placeholder_list
 
 %type <str>   unreserved_keyword type_func_name_keyword
@@ -721,8 +744,9 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %type <TargetList>    targets
 %type <*TargetList> on_privilege_target_clause
 %type <NameList>       grantee_list for_grantee_clause
-%type <privilege.List> privileges privilege_list
-%type <privilege.Kind> privilege
+%type <privilege.List> privileges
+%type <NameList>       privilege_list
+%type <str>            privilege
 
 // Precedence: lowest to highest
 %nonassoc  VALUES              // see value_clause
@@ -767,7 +791,7 @@ func (u *sqlSymUnion) transactionModes() TransactionModes {
 %left      '|'
 %left      '#'
 %left      '&'
-%left      LSHIFT RSHIFT
+%left      LSHIFT RSHIFT INET_CONTAINED_BY_OR_EQUALS INET_CONTAINS_OR_EQUALS
 %left      '+' '-'
 %left      '*' '/' FLOORDIV '%'
 %left      '^'
@@ -813,14 +837,18 @@ stmt_list:
 
 stmt:
   alter_table_stmt
+| alter_user_stmt
 | backup_stmt
 | cancel_stmt
 | copy_from_stmt
+| copy_to_stmt
 | create_stmt
 | delete_stmt
 | drop_stmt
 | explain_stmt
+| export_stmt
 | help_stmt
+| import_stmt
 | prepare_stmt
 | execute_stmt
 | deallocate_stmt
@@ -846,6 +874,7 @@ stmt:
 | truncate_stmt
 | update_stmt
 | use_stmt
+| verify_backup_stmt
 | /* EMPTY */
   {
     $$.val = Statement(nil)
@@ -962,6 +991,31 @@ alter_table_cmd:
       DropBehavior: $4.dropBehavior(),
     }
   }
+  // ALTER TABLE <name> EXPERIMENTAL_AUDIT SET <mode>
+| EXPERIMENTAL_AUDIT SET audit_mode
+  {
+    $$.val = &AlterTableSetAudit{Mode: $3.auditMode()}
+  }
+  // ALTER TABLE <name> ENABLE ROW LEVEL SECURITY
+| ENABLE ROW LEVEL SECURITY
+  {
+    $$.val = &AlterTableSetRowLevelSecurity{Enabled: true}
+  }
+  // ALTER TABLE <name> DISABLE ROW LEVEL SECURITY
+| DISABLE ROW LEVEL SECURITY
+  {
+    $$.val = &AlterTableSetRowLevelSecurity{Enabled: false}
+  }
+
+audit_mode:
+  READ WRITE
+  {
+    $$.val = AuditModeReadWrite
+  }
+| OFF
+  {
+    $$.val = AuditModeDisable
+  }
 
 alter_column_default:
   SET DEFAULT a_expr
@@ -1005,6 +1059,17 @@ alter_using:
   USING a_expr { return unimplemented(sqllex, "alter using") }
 | /* EMPTY */ {}
 
+// ALTER USER
+alter_user_stmt:
+  ALTER USER name opt_with user_options
+  {
+    $$.val = &AlterUser{Name: Name($3), IfExists: false, Options: $5.userOptions()}
+  }
+| ALTER USER IF EXISTS name opt_with user_options
+  {
+    $$.val = &AlterUser{Name: Name($5), IfExists: true, Options: $7.userOptions()}
+  }
+
 backup_stmt:
   BACKUP targets TO string_or_placeholder opt_as_of_clause opt_incremental opt_with_options
   {
@@ -1014,6 +1079,72 @@ backup_stmt:
   {
     $$.val = &Restore{Targets: $2.targetList(), From: $4.exprs(), AsOf: $5.asOfClause(), Options: $6.kvOptions()}
   }
+| RESTORE targets AS name FROM string_or_placeholder_list opt_as_of_clause opt_with_options
+  {
+    $$.val = &Restore{Targets: $2.targetList(), As: Name($4), From: $6.exprs(), AsOf: $7.asOfClause(), Options: $8.kvOptions()}
+  }
+
+// VERIFY BACKUP 'full', 'incremental', ... [WITH OPTIONS (...)]
+verify_backup_stmt:
+  VERIFY BACKUP string_or_placeholder_list opt_with_options
+  {
+    $$.val = &VerifyBackup{From: $3.exprs(), Options: $4.kvOptions()}
+  }
+
+// IMPORT TABLE name CREATE USING 'create.sql' CSV DATA ('file1', ...) [WITH OPTIONS (...)]
+// IMPORT TABLE name (table_elem_list) CSV DATA ('file1', ...) [WITH OPTIONS (...)]
+import_stmt:
+  IMPORT TABLE qualified_name CREATE USING string_or_placeholder import_format DATA '(' string_or_placeholder_list ')' opt_with_options
+  {
+    $$.val = &Import{Table: $3.normalizableTableName(), CreateFile: $6.expr(), FileFormat: $7, Files: $10.exprs(), Options: $12.kvOptions()}
+  }
+| IMPORT TABLE qualified_name '(' table_elem_list ')' import_format DATA '(' string_or_placeholder_list ')' opt_with_options
+  {
+    $$.val = &Import{Table: $3.normalizableTableName(), CreateDefs: $5.tblDefs(), FileFormat: $7, Files: $10.exprs(), Options: $12.kvOptions()}
+  }
+
+// CREATE CHANGEFEED FOR targets INTO 'sink' [WITH OPTIONS (...)]
+create_changefeed_stmt:
+  CREATE CHANGEFEED FOR targets INTO string_or_placeholder opt_with_options
+  {
+    $$.val = &CreateChangefeed{Targets: $4.targetList(), SinkURI: $6.expr(), Options: $7.kvOptions()}
+  }
+
+// CREATE SCHEDULE [name] FOR BACKUP targets TO 'base' [WITH OPTIONS (...)]
+//   RECURRING 'crontab' [FULL BACKUP 'crontab']
+create_schedule_stmt:
+  CREATE SCHEDULE opt_name FOR BACKUP targets TO string_or_placeholder opt_with_options RECURRING string_or_placeholder opt_full_backup
+  {
+    $$.val = &CreateSchedule{
+      Name: Name($3),
+      Backup: &Backup{Targets: $6.targetList(), To: $8.expr(), Options: $9.kvOptions()},
+      Recurrence: $11.expr(),
+      FullBackupRecurrence: $12.expr(),
+    }
+  }
+
+opt_full_backup:
+  FULL BACKUP string_or_placeholder
+  {
+    $$.val = $3.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = Expr(nil)
+  }
+
+// EXPORT INTO CSV 'destination' [WITH OPTIONS (...)] FROM select_stmt
+export_stmt:
+  EXPORT INTO import_format string_or_placeholder opt_with_options FROM select_stmt
+  {
+    $$.val = &Export{Query: $7.slct(), FileFormat: $3, File: $4.expr(), Options: $5.kvOptions()}
+  }
+
+import_format:
+  name
+  {
+    $$ = strings.ToUpper($1)
+  }
 
 string_or_placeholder:
   non_reserved_word_or_sconst
@@ -1092,6 +1223,107 @@ copy_from_stmt:
     $$.val = &CopyFrom{Table: $2.normalizableTableName(), Columns: $4.unresolvedNames(), Stdin: true}
   }
 
+// COPY table [(columns)] TO STDOUT [[WITH] (options)]
+// COPY (select) TO STDOUT [[WITH] (options)]
+copy_to_stmt:
+  COPY qualified_name TO STDOUT opt_copy_options
+  {
+    $$.val = &CopyTo{Table: $2.normalizableTableName(), Stdout: true, Options: $5.kvOptions()}
+  }
+| COPY qualified_name '(' qualified_name_list ')' TO STDOUT opt_copy_options
+  {
+    $$.val = &CopyTo{Table: $2.normalizableTableName(), Columns: $4.unresolvedNames(), Stdout: true, Options: $8.kvOptions()}
+  }
+| COPY select_with_parens TO STDOUT opt_copy_options
+  {
+    $$.val = &CopyTo{Query: &Select{Select: $2.selectStmt()}, Stdout: true, Options: $5.kvOptions()}
+  }
+
+opt_copy_options:
+  WITH '(' copy_generic_option_list ')'
+  {
+    $$.val = $3.kvOptions()
+  }
+| '(' copy_generic_option_list ')'
+  {
+    $$.val = $2.kvOptions()
+  }
+| WITH copy_legacy_option_list
+  {
+    $$.val = $2.kvOptions()
+  }
+| copy_legacy_option_list
+  {
+    $$.val = $1.kvOptions()
+  }
+| /* EMPTY */
+  {
+    $$.val = []KVOption(nil)
+  }
+
+copy_generic_option_list:
+  copy_generic_option
+  {
+    $$.val = []KVOption{$1.kvOption()}
+  }
+| copy_generic_option_list ',' copy_generic_option
+  {
+    $$.val = append($1.kvOptions(), $3.kvOption())
+  }
+
+copy_generic_option:
+  unrestricted_name copy_generic_option_arg
+  {
+    $$.val = KVOption{Key: $1, Value: $2}
+  }
+
+copy_generic_option_arg:
+  non_reserved_word_or_sconst
+| TRUE
+| FALSE
+| /* EMPTY */
+  {
+    $$ = ""
+  }
+
+// The pre-9.0 option syntax, which is still what psql's \copy and many
+// tools emit.
+copy_legacy_option_list:
+  copy_legacy_option
+  {
+    $$.val = []KVOption{$1.kvOption()}
+  }
+| copy_legacy_option_list copy_legacy_option
+  {
+    $$.val = append($1.kvOptions(), $2.kvOption())
+  }
+
+copy_legacy_option:
+  CSV
+  {
+    $$.val = KVOption{Key: "format", Value: "csv"}
+  }
+| HEADER
+  {
+    $$.val = KVOption{Key: "header", Value: "true"}
+  }
+| DELIMITER SCONST
+  {
+    $$.val = KVOption{Key: "delimiter", Value: $2}
+  }
+| DELIMITER AS SCONST
+  {
+    $$.val = KVOption{Key: "delimiter", Value: $3}
+  }
+| NULL SCONST
+  {
+    $$.val = KVOption{Key: "null", Value: $2}
+  }
+| NULL AS SCONST
+  {
+    $$.val = KVOption{Key: "null", Value: $3}
+  }
+
 // CANCEL [JOB|QUERY] id
 cancel_stmt:
   CANCEL JOB a_expr
@@ -1105,10 +1337,14 @@ cancel_stmt:
     $$.val = &CancelQuery{ID: $3.expr()}
   }
 
-// CREATE [DATABASE|INDEX|TABLE|TABLE AS|VIEW]
+// CREATE [DATABASE|INDEX|ROLE|TABLE|TABLE AS|USER|VIEW]
 create_stmt:
-  create_database_stmt
+  create_changefeed_stmt
+| create_database_stmt
+| create_schedule_stmt
 | create_index_stmt
+| create_policy_stmt
+| create_role_stmt
 | create_table_stmt
 | create_table_as_stmt
 | create_user_stmt
@@ -1131,6 +1367,14 @@ drop_stmt:
   {
     $$.val = &DropDatabase{Name: Name($5), IfExists: true}
   }
+| DROP POLICY name ON relation_expr
+  {
+    $$.val = &DropPolicy{Name: Name($3), Table: $5.normalizableTableName(), IfExists: false}
+  }
+| DROP POLICY IF EXISTS name ON relation_expr
+  {
+    $$.val = &DropPolicy{Name: Name($5), Table: $7.normalizableTableName(), IfExists: true}
+  }
 | DROP INDEX table_name_with_index_list opt_drop_behavior
   {
     $$.val = &DropIndex{
@@ -1171,6 +1415,18 @@ drop_stmt:
   {
     $$.val = &DropUser{Names: $5.nameList(), IfExists: true}
   }
+| DROP ROLE name_list
+  {
+    $$.val = &DropRole{Names: $3.nameList(), IfExists: false}
+  }
+| DROP ROLE IF EXISTS name_list
+  {
+    $$.val = &DropRole{Names: $5.nameList(), IfExists: true}
+  }
+| DROP SCHEDULE a_expr
+  {
+    $$.val = &DropSchedule{ID: $3.expr()}
+  }
 
 table_name_list:
   any_name
@@ -1310,19 +1566,45 @@ deallocate_stmt:
     $$.val = &Deallocate{}
   }
 
-// GRANT privileges ON targets TO grantee_list
+// GRANT privileges [(column_list)] ON targets TO grantee_list
+// GRANT role_list TO grantee_list [WITH ADMIN OPTION]
 grant_stmt:
   GRANT privileges ON targets TO grantee_list
   {
     $$.val = &Grant{Privileges: $2.privilegeList(), Grantees: $6.nameList(), Targets: $4.targetList()}
   }
+| GRANT privileges '(' name_list ')' ON targets TO grantee_list
+  {
+    $$.val = &Grant{Privileges: $2.privilegeList(), Columns: $4.nameList(), Grantees: $9.nameList(), Targets: $7.targetList()}
+  }
+| GRANT privilege_list TO grantee_list
+  {
+    $$.val = &GrantRole{Roles: $2.nameList(), Members: $4.nameList(), AdminOption: false}
+  }
+| GRANT privilege_list TO grantee_list WITH ADMIN OPTION
+  {
+    $$.val = &GrantRole{Roles: $2.nameList(), Members: $4.nameList(), AdminOption: true}
+  }
 
-// REVOKE privileges ON targets FROM grantee_list
+// REVOKE privileges [(column_list)] ON targets FROM grantee_list
+// REVOKE [ADMIN OPTION FOR] role_list FROM grantee_list
 revoke_stmt:
   REVOKE privileges ON targets FROM grantee_list
   {
     $$.val = &Revoke{Privileges: $2.privilegeList(), Grantees: $6.nameList(), Targets: $4.targetList()}
   }
+| REVOKE privileges '(' name_list ')' ON targets FROM grantee_list
+  {
+    $$.val = &Revoke{Privileges: $2.privilegeList(), Columns: $4.nameList(), Grantees: $9.nameList(), Targets: $7.targetList()}
+  }
+| REVOKE privilege_list FROM grantee_list
+  {
+    $$.val = &RevokeRole{Roles: $2.nameList(), Members: $4.nameList(), AdminOption: false}
+  }
+| REVOKE ADMIN OPTION FOR privilege_list FROM grantee_list
+  {
+    $$.val = &RevokeRole{Roles: $5.nameList(), Members: $7.nameList(), AdminOption: true}
+  }
 
 
 targets:
@@ -1345,48 +1627,37 @@ privileges:
   {
     $$.val = privilege.List{privilege.ALL}
   }
-  | privilege_list { }
+  | privilege_list
+  {
+    privList, err := privilege.ListFromStrings($1.nameList().ToStrings())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = privList
+  }
 
+// privilege_list is a list of privileges or, in GRANT and REVOKE statements
+// without an ON clause, of roles. The two can only be told apart by the
+// presence of the ON clause, so both are parsed as names.
 privilege_list:
   privilege
   {
-    $$.val = privilege.List{$1.privilegeType()}
+    $$.val = NameList{Name($1)}
   }
   | privilege_list ',' privilege
   {
-    $$.val = append($1.privilegeList(), $3.privilegeType())
+    $$.val = append($1.nameList(), Name($3))
   }
 
-// This list must match the list of privileges in sql/privilege/privilege.go.
+// Privileges are parsed as names and checked against the list of privileges
+// in sql/privilege/privilege.go. The reserved keywords that are privileges
+// have to be listed explicitly.
 privilege:
-  CREATE
-  {
-    $$.val = privilege.CREATE
-  }
-| DROP
-  {
-    $$.val = privilege.DROP
-  }
+  name
+| CREATE
 | GRANT
-  {
-    $$.val = privilege.GRANT
-  }
 | SELECT
-  {
-    $$.val = privilege.SELECT
-  }
-| INSERT
-  {
-    $$.val = privilege.INSERT
-  }
-| DELETE
-  {
-    $$.val = privilege.DELETE
-  }
-| UPDATE
-  {
-    $$.val = privilege.UPDATE
-  }
 
 // TODO(marc): this should not be 'name', but should instead be a
 // type just for usernames.
@@ -1658,9 +1929,9 @@ show_stmt:
   {
     $$.val = &Show{Name: $2}
   }
-| SHOW BACKUP string_or_placeholder
+| SHOW BACKUP string_or_placeholder opt_with_options
   {
-    $$.val = &ShowBackup{Path: $3.expr()}
+    $$.val = &ShowBackup{Path: $3.expr(), Options: $4.kvOptions()}
   }
 | SHOW CLUSTER SETTING any_name
   {
@@ -1786,6 +2057,14 @@ show_stmt:
   {
     $$.val = &ShowUsers{}
   }
+| SHOW ROLES
+  {
+    $$.val = &ShowRoles{}
+  }
+| SHOW SCHEDULES
+  {
+    $$.val = &ShowSchedules{}
+  }
 | SHOW TESTING_RANGES FROM TABLE qualified_name
   {
     /* SKIP DOC */
@@ -1829,13 +2108,17 @@ for_grantee_clause:
     $$.val = NameList(nil)
   }
 
-// PAUSE JOB id
+// PAUSE [JOB|SCHEDULE] id
 pause_stmt:
   PAUSE JOB a_expr
   {
     /* SKIP DOC */
     $$.val = &PauseJob{ID: $3.expr()}
   }
+| PAUSE SCHEDULE a_expr
+  {
+    $$.val = &PauseSchedule{ID: $3.expr()}
+  }
 
 split_stmt:
   ALTER TABLE qualified_name SPLIT AT select_stmt
@@ -2055,16 +2338,17 @@ col_qualification_elem:
  }
 
 index_def:
-  INDEX opt_name '(' index_params ')' opt_storing opt_interleave
+  INDEX opt_name '(' index_params ')' opt_storing opt_interleave where_clause
   {
     $$.val = &IndexTableDef{
       Name:    Name($2),
       Columns: $4.idxElems(),
       Storing: $6.nameList(),
       Interleave: $7.interleave(),
+      Predicate: $8.expr(),
     }
   }
-| UNIQUE INDEX opt_name '(' index_params ')' opt_storing opt_interleave
+| UNIQUE INDEX opt_name '(' index_params ')' opt_storing opt_interleave where_clause
   {
     $$.val = &UniqueConstraintTableDef{
       IndexTableDef: IndexTableDef {
@@ -2072,6 +2356,7 @@ index_def:
         Columns: $5.idxElems(),
         Storing: $7.nameList(),
         Interleave: $8.interleave(),
+        Predicate: $9.expr(),
       },
     }
   }
@@ -2106,13 +2391,14 @@ constraint_elem:
       Expr: $3.expr(),
     }
   }
-| UNIQUE '(' index_params ')' opt_storing opt_interleave
+| UNIQUE '(' index_params ')' opt_storing opt_interleave where_clause
   {
     $$.val = &UniqueConstraintTableDef{
       IndexTableDef: IndexTableDef{
         Columns: $3.idxElems(),
         Storing: $5.nameList(),
         Interleave: $6.interleave(),
+        Predicate: $7.expr(),
       },
     }
   }
@@ -2220,21 +2506,136 @@ truncate_stmt:
     $$.val = &Truncate{Tables: $3.tableNameReferences(), DropBehavior: $4.dropBehavior()}
   }
 
+// CREATE POLICY name ON relname [FOR command] [TO name, ...]
+//   [USING (expr)] [WITH CHECK (expr)]
+create_policy_stmt:
+  CREATE POLICY name ON relation_expr opt_policy_command opt_policy_roles opt_policy_using opt_policy_with_check
+  {
+    $$.val = &CreatePolicy{
+      Name: Name($3),
+      Table: $5.normalizableTableName(),
+      Command: $6.policyCommand(),
+      Roles: $7.nameList(),
+      Using: $8.expr(),
+      WithCheck: $9.expr(),
+    }
+  }
+
+opt_policy_command:
+  FOR ALL
+  {
+    $$.val = PolicyAll
+  }
+| FOR SELECT
+  {
+    $$.val = PolicySelect
+  }
+| FOR INSERT
+  {
+    $$.val = PolicyInsert
+  }
+| FOR UPDATE
+  {
+    $$.val = PolicyUpdate
+  }
+| FOR DELETE
+  {
+    $$.val = PolicyDelete
+  }
+| /* EMPTY */
+  {
+    $$.val = PolicyAll
+  }
+
+opt_policy_roles:
+  TO name_list
+  {
+    $$.val = $2.nameList()
+  }
+| /* EMPTY */
+  {
+    $$.val = NameList(nil)
+  }
+
+opt_policy_using:
+  USING '(' a_expr ')'
+  {
+    $$.val = $3.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = Expr(nil)
+  }
+
+opt_policy_with_check:
+  WITH CHECK '(' a_expr ')'
+  {
+    $$.val = $4.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = Expr(nil)
+  }
+
+// CREATE ROLE
+create_role_stmt:
+  CREATE ROLE name
+  {
+    $$.val = &CreateRole{Name: Name($3)}
+  }
+
 // CREATE USER
 create_user_stmt:
-  CREATE USER name opt_with opt_password
+  CREATE USER name opt_with opt_user_options
   {
-    $$.val = &CreateUser{Name: Name($3), Password: $5.strPtr()}
+    $$.val = &CreateUser{Name: Name($3), Options: $5.userOptions()}
   }
 
-opt_password:
+opt_user_options:
+  user_options
+| /* EMPTY */
+  {
+    $$.val = UserOptions{}
+  }
+
+user_options:
+  user_option
+| user_options user_option
+  {
+    opts := $1.userOptions()
+    if err := opts.merge($2.userOptions()); err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = opts
+  }
+
+user_option:
   PASSWORD SCONST
   {
     pwd := $2
-    $$.val = &pwd
+    $$.val = UserOptions{Password: &pwd}
   }
-| /* EMPTY */ {
-    $$.val = (*string)(nil)
+| LOGIN
+  {
+    login := true
+    $$.val = UserOptions{Login: &login}
+  }
+| NOLOGIN
+  {
+    login := false
+    $$.val = UserOptions{Login: &login}
+  }
+| VALID UNTIL SCONST
+  {
+    validUntil := $3
+    $$.val = UserOptions{ValidUntil: &validUntil}
+  }
+| CONNECTION LIMIT signed_iconst
+  {
+    limit, err := $3.numVal().AsInt64()
+    if err != nil { sqllex.Error(err.Error()); return 1 }
+    $$.val = UserOptions{ConnectionLimit: &limit}
   }
 
 // CREATE VIEW relname
@@ -2252,7 +2653,7 @@ create_view_stmt:
 
 // CREATE INDEX
 create_index_stmt:
-  CREATE opt_unique INDEX opt_name ON qualified_name '(' index_params ')' opt_storing opt_interleave
+  CREATE opt_unique INDEX opt_name ON qualified_name '(' index_params ')' opt_storing opt_interleave where_clause
   {
     $$.val = &CreateIndex{
       Name:    Name($4),
@@ -2261,9 +2662,10 @@ create_index_stmt:
       Columns: $8.idxElems(),
       Storing: $10.nameList(),
       Interleave: $11.interleave(),
+      Predicate: $12.expr(),
     }
   }
-| CREATE opt_unique INDEX IF NOT EXISTS name ON qualified_name '(' index_params ')' opt_storing opt_interleave
+| CREATE opt_unique INDEX IF NOT EXISTS name ON qualified_name '(' index_params ')' opt_storing opt_interleave where_clause
   {
     $$.val = &CreateIndex{
       Name:        Name($7),
@@ -2273,6 +2675,7 @@ create_index_stmt:
       Columns:     $11.idxElems(),
       Storing:     $13.nameList(),
       Interleave: $14.interleave(),
+      Predicate:   $15.expr(),
     }
   }
 
@@ -2304,8 +2707,14 @@ index_elem:
   {
     $$.val = IndexElem{Column: Name($1), Direction: $3.dir()}
   }
-| func_expr_windowless opt_collate opt_asc_desc { return unimplemented(sqllex, "index_elem func expr") }
-| '(' a_expr ')' opt_collate opt_asc_desc { return unimplemented(sqllex, "index_elem a_expr") }
+| func_expr_windowless opt_collate opt_asc_desc
+  {
+    $$.val = IndexElem{Expr: $1.expr(), Direction: $3.dir()}
+  }
+| '(' a_expr ')' opt_collate opt_asc_desc
+  {
+    $$.val = IndexElem{Expr: $2.expr(), Direction: $5.dir()}
+  }
 
 opt_collate:
   COLLATE unrestricted_name { return unimplementedWithIssue(sqllex, 16619) }
@@ -2386,13 +2795,17 @@ release_stmt:
   $$.val = &ReleaseSavepoint{Savepoint: $2}
  }
 
-// RESUME JOB id
+// RESUME [JOB|SCHEDULE] id
 resume_stmt:
   RESUME JOB a_expr
   {
     /* SKIP DOC */
     $$.val = &ResumeJob{ID: $3.expr()}
   }
+| RESUME SCHEDULE a_expr
+  {
+    $$.val = &ResumeSchedule{ID: $3.expr()}
+  }
 
 savepoint_stmt:
  SAVEPOINT savepoint_name
@@ -3238,6 +3651,10 @@ table_ref:
   {
     $$.val = &AliasedTableExpr{Expr: $1.newNormalizableTableName(), Hints: $2.indexHints(), Ordinality: $3.bool(), As: $4.aliasClause() }
   }
+| qualified_name '(' ')' opt_ordinality opt_alias_clause
+  {
+    $$.val = &AliasedTableExpr{Expr: &FuncExpr{Func: $1.resolvableFunctionReference()}, Ordinality: $4.bool(), As: $5.aliasClause() }
+  }
 | qualified_name '(' expr_list ')' opt_ordinality opt_alias_clause
   {
     $$.val = &AliasedTableExpr{Expr: &FuncExpr{Func: $1.resolvableFunctionReference(), Exprs: $3.exprs()}, Ordinality: $5.bool(), As: $6.aliasClause() }
@@ -3246,6 +3663,18 @@ table_ref:
   {
     $$.val = &AliasedTableExpr{Expr: &Subquery{Select: $1.selectStmt()}, Ordinality: $2.bool(), As: $3.aliasClause() }
   }
+| LATERAL select_with_parens opt_ordinality opt_alias_clause
+  {
+    $$.val = &AliasedTableExpr{Expr: &Subquery{Select: $2.selectStmt()}, Lateral: true, Ordinality: $3.bool(), As: $4.aliasClause() }
+  }
+| LATERAL qualified_name '(' ')' opt_ordinality opt_alias_clause
+  {
+    $$.val = &AliasedTableExpr{Expr: &FuncExpr{Func: $2.resolvableFunctionReference()}, Lateral: true, Ordinality: $5.bool(), As: $6.aliasClause() }
+  }
+| LATERAL qualified_name '(' expr_list ')' opt_ordinality opt_alias_clause
+  {
+    $$.val = &AliasedTableExpr{Expr: &FuncExpr{Func: $2.resolvableFunctionReference(), Exprs: $4.exprs()}, Lateral: true, Ordinality: $6.bool(), As: $7.aliasClause() }
+  }
 | joined_table
   {
     $$.val = $1.tblExpr()
@@ -3556,6 +3985,14 @@ simple_typename:
   {
     $$.val = uuidColTypeUUID
   }
+| INET
+  {
+    $$.val = inetColTypeINet
+  }
+| CIDR
+  {
+    $$.val = inetColTypeCIDR
+  }
 | BIGSERIAL
   {
     $$.val = intColTypeBigSerial
@@ -3746,7 +4183,21 @@ bit_with_length:
       sqllex.Error(err.Error())
       return 1
     }
-    bit, err := newIntBitType(int(n))
+    bit, err := newBitArrayColType(int(n), $2.bool())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = bit
+  }
+| VARBIT '(' ICONST ')'
+  {
+    n, err := $3.numVal().AsInt64()
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    bit, err := newBitArrayColType(int(n), true)
     if err != nil {
       sqllex.Error(err.Error())
       return 1
@@ -3755,9 +4206,17 @@ bit_with_length:
   }
 
 bit_without_length:
-  BIT opt_varying
+  BIT
   {
-    $$.val = intColTypeBit
+    $$.val = bitArrayColTypeBit
+  }
+| BIT VARYING
+  {
+    $$.val = bitArrayColTypeVarBit
+  }
+| VARBIT
+  {
+    $$.val = bitArrayColTypeVarBit
   }
 
 // SQL character data types
@@ -3811,8 +4270,14 @@ character_base:
   }
 
 opt_varying:
-  VARYING {}
-| /* EMPTY */ {}
+  VARYING
+  {
+    $$.val = true
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
 
 // SQL date/time types
 const_datetime:
@@ -3836,6 +4301,15 @@ const_datetime:
   {
     $$.val = timestampTzColTypeTimestampWithTZ
   }
+| TIME
+  {
+    $$.val = timeColTypeTime
+  }
+| TIME WITHOUT TIME ZONE
+  {
+    $$.val = timeColTypeTime
+  }
+| TIME WITH_LA TIME ZONE { return unimplemented(sqllex, "timetz") }
 
 const_interval:
   INTERVAL {
@@ -4026,6 +4500,14 @@ a_expr:
   {
     $$.val = &BinaryExpr{Operator: RShift, Left: $1.expr(), Right: $3.expr()}
   }
+| a_expr INET_CONTAINED_BY_OR_EQUALS a_expr
+  {
+    $$.val = &BinaryExpr{Operator: INetContainedByOrEquals, Left: $1.expr(), Right: $3.expr()}
+  }
+| a_expr INET_CONTAINS_OR_EQUALS a_expr
+  {
+    $$.val = &BinaryExpr{Operator: INetContainsOrEquals, Left: $1.expr(), Right: $3.expr()}
+  }
 | a_expr LESS_EQUALS a_expr
   {
     $$.val = &ComparisonExpr{Operator: LE, Left: $1.expr(), Right: $3.expr()}
@@ -4287,6 +4769,14 @@ b_expr:
   {
     $$.val = &BinaryExpr{Operator: RShift, Left: $1.expr(), Right: $3.expr()}
   }
+| b_expr INET_CONTAINED_BY_OR_EQUALS b_expr
+  {
+    $$.val = &BinaryExpr{Operator: INetContainedByOrEquals, Left: $1.expr(), Right: $3.expr()}
+  }
+| b_expr INET_CONTAINS_OR_EQUALS b_expr
+  {
+    $$.val = &BinaryExpr{Operator: INetContainsOrEquals, Left: $1.expr(), Right: $3.expr()}
+  }
 | b_expr LESS_EQUALS b_expr
   {
     $$.val = &ComparisonExpr{Operator: LE, Left: $1.expr(), Right: $3.expr()}
@@ -4443,8 +4933,14 @@ func_expr:
 // expressions are not allowed, where needed to disambiguate the grammar
 // (e.g. in CREATE INDEX).
 func_expr_windowless:
-  func_application { return unimplemented(sqllex, "func_application") }
-| func_expr_common_subexpr { return unimplemented(sqllex, "func_expr_common_subexpr") }
+  func_application
+  {
+    $$.val = $1.expr()
+  }
+| func_expr_common_subexpr
+  {
+    $$.val = $1.expr()
+  }
 
 // Special expressions that are considered to be functions.
 func_expr_common_subexpr:
@@ -4465,8 +4961,29 @@ func_expr_common_subexpr:
   {
     $$.val = &FuncExpr{Func: wrapFunction($1)}
   }
+| LOCALTIME
+  {
+    $$.val = &FuncExpr{Func: wrapFunction($1)}
+  }
+| LOCALTIME '(' ')'
+  {
+    $$.val = &FuncExpr{Func: wrapFunction($1)}
+  }
+// FAMILY is reserved for column family definitions, but the INET builtin of
+// the same name must remain callable.
+| FAMILY '(' a_expr ')'
+  {
+    $$.val = &FuncExpr{Func: wrapFunction($1), Exprs: Exprs{$3.expr()}}
+  }
 | CURRENT_ROLE { return unimplemented(sqllex, "current role") }
-| CURRENT_USER { return unimplemented(sqllex, "current user") }
+| CURRENT_USER
+  {
+    $$.val = &FuncExpr{Func: wrapFunction($1)}
+  }
+| CURRENT_USER '(' ')'
+  {
+    $$.val = &FuncExpr{Func: wrapFunction($1)}
+  }
 | SESSION_USER { return unimplemented(sqllex, "session user") }
 | USER { return unimplemented(sqllex, "user") }
 | CAST '(' a_expr AS cast_target ')'
@@ -5340,6 +5857,7 @@ unrestricted_name:
 unreserved_keyword:
   ACTION
 | ADD
+| ADMIN
 | ALTER
 | AT
 | BACKUP
@@ -5348,14 +5866,17 @@ unreserved_keyword:
 | BY
 | CANCEL
 | CASCADE
+| CHANGEFEED
 | CLUSTER
 | COLUMNS
 | COMMIT
 | COMMITTED
 | CONFLICT
+| CONNECTION
 | CONSTRAINTS
 | COPY
 | COVERING
+| CSV
 | CUBE
 | CURRENT
 | CYCLE
@@ -5365,20 +5886,27 @@ unreserved_keyword:
 | DAY
 | DEALLOCATE
 | DELETE
+| DELIMITER
+| DISABLE
 | DOUBLE
 | DROP
+| ENABLE
 | ENCODING
 | EXECUTE
+| EXPERIMENTAL_AUDIT
 | EXPERIMENTAL_FINGERPRINTS
 | EXPLAIN
+| EXPORT
 | FILTER
 | FIRST
 | FOLLOWING
 | FORCE_INDEX
 | GRANTS
+| HEADER
 | HELP
 | HIGH
 | HOUR
+| IMPORT
 | INCREMENTAL
 | INDEXES
 | INSERT
@@ -5393,6 +5921,7 @@ unreserved_keyword:
 | LC_CTYPE
 | LEVEL
 | LOCAL
+| LOGIN
 | LOW
 | MATCH
 | MINUTE
@@ -5401,12 +5930,14 @@ unreserved_keyword:
 | NAN
 | NEXT
 | NO
+| NOLOGIN
 | NORMAL
 | NO_INDEX_JOIN
 | NULLS
 | OF
 | OFF
 | OID
+| OPTION
 | OPTIONS
 | ORDINALITY
 | OVER
@@ -5415,6 +5946,7 @@ unreserved_keyword:
 | PARTITION
 | PASSWORD
 | PAUSE
+| POLICY
 | PRECEDING
 | PREPARE
 | PRIORITY
@@ -5422,6 +5954,7 @@ unreserved_keyword:
 | QUERY
 | RANGE
 | READ
+| RECURRING
 | RECURSIVE
 | REF
 | REGCLASS
@@ -5437,6 +5970,8 @@ unreserved_keyword:
 | RESTRICT
 | RESUME
 | REVOKE
+| ROLE
+| ROLES
 | ROLLBACK
 | ROLLUP
 | ROWS
@@ -5445,8 +5980,11 @@ unreserved_keyword:
 | STATUS
 | SAVEPOINT
 | SCATTER
+| SCHEDULE
+| SCHEDULES
 | SEARCH
 | SECOND
+| SECURITY
 | SERIALIZABLE
 | SESSION
 | SESSIONS
@@ -5457,6 +5995,7 @@ unreserved_keyword:
 | SQL
 | START
 | STDIN
+| STDOUT
 | STORING
 | STRICT
 | SPLIT
@@ -5473,6 +6012,7 @@ unreserved_keyword:
 | UNBOUNDED
 | UNCOMMITTED
 | UNKNOWN
+| UNTIL
 | UPDATE
 | UPSERT
 | USE
@@ -5481,6 +6021,7 @@ unreserved_keyword:
 | VALIDATE
 | VALUE
 | VARYING
+| VERIFY
 | WITHIN
 | WITHOUT
 | WRITE
@@ -5509,6 +6050,7 @@ col_name_keyword:
 | CHAR
 | CHARACTER
 | CHARACTERISTICS
+| CIDR
 | COALESCE
 | DATE
 | DEC
@@ -5521,6 +6063,7 @@ col_name_keyword:
 | GROUPING
 | IF
 | IFNULL
+| INET
 | INT
 | INT8
 | INT64
@@ -5548,6 +6091,7 @@ col_name_keyword:
 | TRIM
 | UUID
 | VALUES
+| VARBIT
 | VARCHAR
 
 // Type/function identifier --- keywords that can be type or function names.
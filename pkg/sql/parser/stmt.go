@@ -51,6 +51,8 @@ const (
 	Rows
 	// CopyIn indicates a COPY FROM statement.
 	CopyIn
+	// CopyOut indicates a COPY TO statement.
+	CopyOut
 	// Unknown indicates that the statement does not have a known
 	// return style at the time of parsing. This is not first in the
 	// enumeration because it is more convenient to have Ack as a zero
@@ -93,6 +95,12 @@ func (*AlterTable) StatementType() StatementType { return DDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*AlterTable) StatementTag() string { return "ALTER TABLE" }
 
+// StatementType implements the Statement interface.
+func (*AlterUser) StatementType() StatementType { return Ack }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*AlterUser) StatementTag() string { return "ALTER USER" }
+
 // StatementType implements the Statement interface.
 func (*Backup) StatementType() StatementType { return Rows }
 
@@ -133,6 +141,18 @@ func (*CopyFrom) StatementType() StatementType { return CopyIn }
 // StatementTag returns a short string identifying the type of statement.
 func (*CopyFrom) StatementTag() string { return "COPY" }
 
+// StatementType implements the Statement interface.
+func (*CopyTo) StatementType() StatementType { return CopyOut }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CopyTo) StatementTag() string { return "COPY" }
+
+// StatementType implements the Statement interface.
+func (*CreateChangefeed) StatementType() StatementType { return Rows }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateChangefeed) StatementTag() string { return "CREATE CHANGEFEED" }
+
 // StatementType implements the Statement interface.
 func (*CreateDatabase) StatementType() StatementType { return DDL }
 
@@ -145,6 +165,12 @@ func (*CreateIndex) StatementType() StatementType { return DDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*CreateIndex) StatementTag() string { return "CREATE INDEX" }
 
+// StatementType implements the Statement interface.
+func (*CreateSchedule) StatementType() StatementType { return Rows }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateSchedule) StatementTag() string { return "CREATE SCHEDULE" }
+
 // StatementType implements the Statement interface.
 func (*CreateTable) StatementType() StatementType { return DDL }
 
@@ -156,6 +182,18 @@ func (n *CreateTable) StatementTag() string {
 	return "CREATE TABLE"
 }
 
+// StatementType implements the Statement interface.
+func (*CreatePolicy) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreatePolicy) StatementTag() string { return "CREATE POLICY" }
+
+// StatementType implements the Statement interface.
+func (*CreateRole) StatementType() StatementType { return Ack }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateRole) StatementTag() string { return "CREATE ROLE" }
+
 // StatementType implements the Statement interface.
 func (*CreateUser) StatementType() StatementType { return Ack }
 
@@ -200,6 +238,18 @@ func (*DropIndex) StatementType() StatementType { return DDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*DropIndex) StatementTag() string { return "DROP INDEX" }
 
+// StatementType implements the Statement interface.
+func (*DropPolicy) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropPolicy) StatementTag() string { return "DROP POLICY" }
+
+// StatementType implements the Statement interface.
+func (*DropSchedule) StatementType() StatementType { return Ack }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropSchedule) StatementTag() string { return "DROP SCHEDULE" }
+
 // StatementType implements the Statement interface.
 func (*DropTable) StatementType() StatementType { return DDL }
 
@@ -212,6 +262,12 @@ func (*DropView) StatementType() StatementType { return DDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*DropView) StatementTag() string { return "DROP VIEW" }
 
+// StatementType implements the Statement interface.
+func (*DropRole) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropRole) StatementTag() string { return "DROP ROLE" }
+
 // StatementType implements the Statement interface.
 func (*DropUser) StatementType() StatementType { return RowsAffected }
 
@@ -232,6 +288,12 @@ func (*Explain) StatementTag() string { return "EXPLAIN" }
 
 func (*Explain) hiddenFromStats() {}
 
+// StatementType implements the Statement interface.
+func (*Export) StatementType() StatementType { return Rows }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*Export) StatementTag() string { return "EXPORT" }
+
 // StatementType implements the Statement interface.
 func (*Grant) StatementType() StatementType { return DDL }
 
@@ -240,6 +302,20 @@ func (*Grant) StatementTag() string { return "GRANT" }
 
 func (*Grant) hiddenFromStats() {}
 
+// StatementType implements the Statement interface.
+func (*GrantRole) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*GrantRole) StatementTag() string { return "GRANT" }
+
+func (*GrantRole) hiddenFromStats() {}
+
+// StatementType implements the Statement interface.
+func (*Import) StatementType() StatementType { return Rows }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*Import) StatementTag() string { return "IMPORT" }
+
 // StatementType implements the Statement interface.
 func (n *Insert) StatementType() StatementType { return n.Returning.statementType() }
 
@@ -258,6 +334,12 @@ func (*PauseJob) StatementType() StatementType { return Ack }
 // StatementTag returns a short string identifying the type of statement.
 func (*PauseJob) StatementTag() string { return "PAUSE JOB" }
 
+// StatementType implements the Statement interface.
+func (*PauseSchedule) StatementType() StatementType { return Ack }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*PauseSchedule) StatementTag() string { return "PAUSE SCHEDULE" }
+
 // StatementType implements the Statement interface.
 func (*Prepare) StatementType() StatementType { return Ack }
 
@@ -321,6 +403,12 @@ func (*ResumeJob) StatementType() StatementType { return Ack }
 // StatementTag returns a short string identifying the type of statement.
 func (*ResumeJob) StatementTag() string { return "RESUME JOB" }
 
+// StatementType implements the Statement interface.
+func (*ResumeSchedule) StatementType() StatementType { return Ack }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*ResumeSchedule) StatementTag() string { return "RESUME SCHEDULE" }
+
 // StatementType implements the Statement interface.
 func (*Revoke) StatementType() StatementType { return DDL }
 
@@ -329,6 +417,14 @@ func (*Revoke) StatementTag() string { return "REVOKE" }
 
 func (*Revoke) hiddenFromStats() {}
 
+// StatementType implements the Statement interface.
+func (*RevokeRole) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*RevokeRole) StatementTag() string { return "REVOKE" }
+
+func (*RevokeRole) hiddenFromStats() {}
+
 // StatementType implements the Statement interface.
 func (*RollbackToSavepoint) StatementType() StatementType { return Ack }
 
@@ -482,6 +578,15 @@ func (*ShowQueries) StatementTag() string { return "SHOW QUERIES" }
 func (*ShowQueries) hiddenFromStats()                   {}
 func (*ShowQueries) independentFromParallelizedPriors() {}
 
+// StatementType implements the Statement interface.
+func (*ShowSchedules) StatementType() StatementType { return Rows }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*ShowSchedules) StatementTag() string { return "SHOW SCHEDULES" }
+
+func (*ShowSchedules) hiddenFromStats()                   {}
+func (*ShowSchedules) independentFromParallelizedPriors() {}
+
 // StatementType implements the Statement interface.
 func (*ShowSessions) StatementType() StatementType { return Rows }
 
@@ -509,6 +614,15 @@ func (*ShowUsers) StatementTag() string { return "SHOW USERS" }
 func (*ShowUsers) hiddenFromStats()                   {}
 func (*ShowUsers) independentFromParallelizedPriors() {}
 
+// StatementType implements the Statement interface.
+func (*ShowRoles) StatementType() StatementType { return Rows }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*ShowRoles) StatementTag() string { return "SHOW ROLES" }
+
+func (*ShowRoles) hiddenFromStats()                   {}
+func (*ShowRoles) independentFromParallelizedPriors() {}
+
 // StatementType implements the Statement interface.
 func (*ShowRanges) StatementType() StatementType { return Rows }
 
@@ -582,78 +696,107 @@ func (ValuesClause) StatementType() StatementType { return Rows }
 // StatementTag returns a short string identifying the type of statement.
 func (ValuesClause) StatementTag() string { return "VALUES" }
 
-func (n *AlterTable) String() string               { return AsString(n) }
-func (n AlterTableCmds) String() string            { return AsString(n) }
-func (n *AlterTableAddColumn) String() string      { return AsString(n) }
-func (n *AlterTableAddConstraint) String() string  { return AsString(n) }
-func (n *AlterTableDropColumn) String() string     { return AsString(n) }
-func (n *AlterTableDropConstraint) String() string { return AsString(n) }
-func (n *AlterTableDropNotNull) String() string    { return AsString(n) }
-func (n *AlterTableSetDefault) String() string     { return AsString(n) }
-func (n *Backup) String() string                   { return AsString(n) }
-func (n *BeginTransaction) String() string         { return AsString(n) }
-func (n *CancelJob) String() string                { return AsString(n) }
-func (n *CancelQuery) String() string              { return AsString(n) }
-func (n *CommitTransaction) String() string        { return AsString(n) }
-func (n *CopyFrom) String() string                 { return AsString(n) }
-func (n *CreateDatabase) String() string           { return AsString(n) }
-func (n *CreateIndex) String() string              { return AsString(n) }
-func (n *CreateTable) String() string              { return AsString(n) }
-func (n *CreateUser) String() string               { return AsString(n) }
-func (n *CreateView) String() string               { return AsString(n) }
-func (n *Deallocate) String() string               { return AsString(n) }
-func (n *Delete) String() string                   { return AsString(n) }
-func (n *DropDatabase) String() string             { return AsString(n) }
-func (n *DropIndex) String() string                { return AsString(n) }
-func (n *DropTable) String() string                { return AsString(n) }
-func (n *DropView) String() string                 { return AsString(n) }
-func (n *DropUser) String() string                 { return AsString(n) }
-func (n *Execute) String() string                  { return AsString(n) }
-func (n *Explain) String() string                  { return AsString(n) }
-func (n *Grant) String() string                    { return AsString(n) }
-func (n *Help) String() string                     { return AsString(n) }
-func (n *Insert) String() string                   { return AsString(n) }
-func (n *ParenSelect) String() string              { return AsString(n) }
-func (n *PauseJob) String() string                 { return AsString(n) }
-func (n *Prepare) String() string                  { return AsString(n) }
-func (n *ReleaseSavepoint) String() string         { return AsString(n) }
-func (n *Relocate) String() string                 { return AsString(n) }
-func (n *RenameColumn) String() string             { return AsString(n) }
-func (n *RenameDatabase) String() string           { return AsString(n) }
-func (n *RenameIndex) String() string              { return AsString(n) }
-func (n *RenameTable) String() string              { return AsString(n) }
-func (n *Restore) String() string                  { return AsString(n) }
-func (n *ResumeJob) String() string                { return AsString(n) }
-func (n *Revoke) String() string                   { return AsString(n) }
-func (n *RollbackToSavepoint) String() string      { return AsString(n) }
-func (n *RollbackTransaction) String() string      { return AsString(n) }
-func (n *Savepoint) String() string                { return AsString(n) }
-func (n *Scatter) String() string                  { return AsString(n) }
-func (n *Select) String() string                   { return AsString(n) }
-func (n *SelectClause) String() string             { return AsString(n) }
-func (n *Set) String() string                      { return AsString(n) }
-func (n *SetDefaultIsolation) String() string      { return AsString(n) }
-func (n *SetTransaction) String() string           { return AsString(n) }
-func (n *Show) String() string                     { return AsString(n) }
-func (n *ShowBackup) String() string               { return AsString(n) }
-func (n *ShowColumns) String() string              { return AsString(n) }
-func (n *ShowCreateTable) String() string          { return AsString(n) }
-func (n *ShowCreateView) String() string           { return AsString(n) }
-func (n *ShowDatabases) String() string            { return AsString(n) }
-func (n *ShowGrants) String() string               { return AsString(n) }
-func (n *ShowIndex) String() string                { return AsString(n) }
-func (n *ShowConstraints) String() string          { return AsString(n) }
-func (n *ShowQueries) String() string              { return AsString(n) }
-func (n *ShowSessions) String() string             { return AsString(n) }
-func (n *ShowTables) String() string               { return AsString(n) }
-func (n *ShowTrace) String() string                { return AsString(n) }
-func (n *ShowTransactionStatus) String() string    { return AsString(n) }
-func (n *ShowUsers) String() string                { return AsString(n) }
-func (n *ShowRanges) String() string               { return AsString(n) }
-func (n *ShowFingerprints) String() string         { return AsString(n) }
-func (n *Split) String() string                    { return AsString(n) }
-func (l StatementList) String() string             { return AsString(l) }
-func (n *Truncate) String() string                 { return AsString(n) }
-func (n *UnionClause) String() string              { return AsString(n) }
-func (n *Update) String() string                   { return AsString(n) }
-func (n *ValuesClause) String() string             { return AsString(n) }
+// StatementType implements the Statement interface.
+func (*VerifyBackup) StatementType() StatementType { return Rows }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*VerifyBackup) StatementTag() string { return "VERIFY BACKUP" }
+
+func (*VerifyBackup) hiddenFromStats()                   {}
+func (*VerifyBackup) independentFromParallelizedPriors() {}
+
+func (n *AlterTable) String() string                    { return AsString(n) }
+func (n AlterTableCmds) String() string                 { return AsString(n) }
+func (n *AlterTableAddColumn) String() string           { return AsString(n) }
+func (n *AlterTableAddConstraint) String() string       { return AsString(n) }
+func (n *AlterTableDropColumn) String() string          { return AsString(n) }
+func (n *AlterTableDropConstraint) String() string      { return AsString(n) }
+func (n *AlterTableDropNotNull) String() string         { return AsString(n) }
+func (n *AlterTableSetAudit) String() string            { return AsString(n) }
+func (n *AlterTableSetRowLevelSecurity) String() string { return AsString(n) }
+func (n *AlterTableSetDefault) String() string          { return AsString(n) }
+func (n *AlterUser) String() string                     { return AsString(n) }
+func (n *Backup) String() string                        { return AsString(n) }
+func (n *BeginTransaction) String() string              { return AsString(n) }
+func (n *CancelJob) String() string                     { return AsString(n) }
+func (n *CancelQuery) String() string                   { return AsString(n) }
+func (n *CommitTransaction) String() string             { return AsString(n) }
+func (n *CopyFrom) String() string                      { return AsString(n) }
+func (n *CopyTo) String() string                        { return AsString(n) }
+func (n *CreateChangefeed) String() string              { return AsString(n) }
+func (n *CreateDatabase) String() string                { return AsString(n) }
+func (n *CreateIndex) String() string                   { return AsString(n) }
+func (n *CreatePolicy) String() string                  { return AsString(n) }
+func (n *CreateSchedule) String() string                { return AsString(n) }
+func (n *CreateRole) String() string                    { return AsString(n) }
+func (n *CreateTable) String() string                   { return AsString(n) }
+func (n *CreateUser) String() string                    { return AsString(n) }
+func (n *CreateView) String() string                    { return AsString(n) }
+func (n *Deallocate) String() string                    { return AsString(n) }
+func (n *Delete) String() string                        { return AsString(n) }
+func (n *DropDatabase) String() string                  { return AsString(n) }
+func (n *DropIndex) String() string                     { return AsString(n) }
+func (n *DropPolicy) String() string                    { return AsString(n) }
+func (n *DropRole) String() string                      { return AsString(n) }
+func (n *DropSchedule) String() string                  { return AsString(n) }
+func (n *DropTable) String() string                     { return AsString(n) }
+func (n *DropView) String() string                      { return AsString(n) }
+func (n *DropUser) String() string                      { return AsString(n) }
+func (n *Execute) String() string                       { return AsString(n) }
+func (n *Explain) String() string                       { return AsString(n) }
+func (n *Export) String() string                        { return AsString(n) }
+func (n *Grant) String() string                         { return AsString(n) }
+func (n *GrantRole) String() string                     { return AsString(n) }
+func (n *Help) String() string                          { return AsString(n) }
+func (n *Import) String() string                        { return AsString(n) }
+func (n *Insert) String() string                        { return AsString(n) }
+func (n *ParenSelect) String() string                   { return AsString(n) }
+func (n *PauseJob) String() string                      { return AsString(n) }
+func (n *PauseSchedule) String() string                 { return AsString(n) }
+func (n *Prepare) String() string                       { return AsString(n) }
+func (n *ReleaseSavepoint) String() string              { return AsString(n) }
+func (n *Relocate) String() string                      { return AsString(n) }
+func (n *RenameColumn) String() string                  { return AsString(n) }
+func (n *RenameDatabase) String() string                { return AsString(n) }
+func (n *RenameIndex) String() string                   { return AsString(n) }
+func (n *RenameTable) String() string                   { return AsString(n) }
+func (n *Restore) String() string                       { return AsString(n) }
+func (n *ResumeJob) String() string                     { return AsString(n) }
+func (n *ResumeSchedule) String() string                { return AsString(n) }
+func (n *Revoke) String() string                        { return AsString(n) }
+func (n *RevokeRole) String() string                    { return AsString(n) }
+func (n *RollbackToSavepoint) String() string           { return AsString(n) }
+func (n *RollbackTransaction) String() string           { return AsString(n) }
+func (n *Savepoint) String() string                     { return AsString(n) }
+func (n *Scatter) String() string                       { return AsString(n) }
+func (n *Select) String() string                        { return AsString(n) }
+func (n *SelectClause) String() string                  { return AsString(n) }
+func (n *Set) String() string                           { return AsString(n) }
+func (n *SetDefaultIsolation) String() string           { return AsString(n) }
+func (n *SetTransaction) String() string                { return AsString(n) }
+func (n *Show) String() string                          { return AsString(n) }
+func (n *ShowBackup) String() string                    { return AsString(n) }
+func (n *ShowColumns) String() string                   { return AsString(n) }
+func (n *ShowCreateTable) String() string               { return AsString(n) }
+func (n *ShowCreateView) String() string                { return AsString(n) }
+func (n *ShowDatabases) String() string                 { return AsString(n) }
+func (n *ShowGrants) String() string                    { return AsString(n) }
+func (n *ShowIndex) String() string                     { return AsString(n) }
+func (n *ShowConstraints) String() string               { return AsString(n) }
+func (n *ShowQueries) String() string                   { return AsString(n) }
+func (n *ShowSchedules) String() string                 { return AsString(n) }
+func (n *ShowSessions) String() string                  { return AsString(n) }
+func (n *ShowTables) String() string                    { return AsString(n) }
+func (n *ShowTrace) String() string                     { return AsString(n) }
+func (n *ShowTransactionStatus) String() string         { return AsString(n) }
+func (n *ShowUsers) String() string                     { return AsString(n) }
+func (n *ShowRanges) String() string                    { return AsString(n) }
+func (n *ShowRoles) String() string                     { return AsString(n) }
+func (n *ShowFingerprints) String() string              { return AsString(n) }
+func (n *Split) String() string                         { return AsString(n) }
+func (l StatementList) String() string                  { return AsString(l) }
+func (n *Truncate) String() string                      { return AsString(n) }
+func (n *UnionClause) String() string                   { return AsString(n) }
+func (n *Update) String() string                        { return AsString(n) }
+func (n *ValuesClause) String() string                  { return AsString(n) }
+func (n *VerifyBackup) String() string                  { return AsString(n) }
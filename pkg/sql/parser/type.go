@@ -91,6 +91,12 @@ var (
 	TypeInterval Type = tInterval{}
 	// TypeUUID is the type of a DUuid. Can be compared with ==.
 	TypeUUID Type = tUUID{}
+	// TypeINet is the type of a DIPAddr. Can be compared with ==.
+	TypeINet Type = tINet{}
+	// TypeTime is the type of a DTime. Can be compared with ==.
+	TypeTime Type = tTime{}
+	// TypeBitArray is the type of a DBitArray. Can be compared with ==.
+	TypeBitArray Type = tBitArray{}
 	// TypeTuple is the type family of a DTuple. CANNOT be compared with ==.
 	TypeTuple Type = TTuple(nil)
 	// TypeTable is the type family of a DTable. CANNOT be compared with ==.
@@ -146,6 +152,9 @@ var (
 		TypeTimestampTZ,
 		TypeInterval,
 		TypeUUID,
+		TypeINet,
+		TypeTime,
+		TypeBitArray,
 		TypeOid,
 	}
 )
@@ -156,6 +165,8 @@ var (
 	typeInt4      = wrapTypeWithOid(TypeInt, oid.T_int4)
 	typeFloat4    = wrapTypeWithOid(TypeFloat, oid.T_float4)
 	typeVarChar   = wrapTypeWithOid(TypeString, oid.T_varchar)
+	typeCIDR      = wrapTypeWithOid(TypeINet, oid.T_cidr)
+	typeBit       = wrapTypeWithOid(TypeBitArray, oid.T_bit)
 	typeInt2Array = TArray{typeInt2}
 	typeInt4Array = TArray{typeInt4}
 )
@@ -164,10 +175,13 @@ var (
 var OidToType = map[oid.Oid]Type{
 	oid.T_anyelement:   TypeAny,
 	oid.T_bool:         TypeBool,
+	oid.T_bit:          typeBit,
 	oid.T_bytea:        TypeBytes,
+	oid.T_cidr:         typeCIDR,
 	oid.T_date:         TypeDate,
 	oid.T_float4:       typeFloat4,
 	oid.T_float8:       TypeFloat,
+	oid.T_inet:         TypeINet,
 	oid.T_int2:         typeInt2,
 	oid.T_int4:         typeInt4,
 	oid.T_int8:         TypeInt,
@@ -187,9 +201,11 @@ var OidToType = map[oid.Oid]Type{
 	oid.T__int8:        TypeIntArray,
 	oid.T_record:       TypeTuple,
 	oid.T_text:         TypeString,
+	oid.T_time:         TypeTime,
 	oid.T_timestamp:    TypeTimestamp,
 	oid.T_timestamptz:  TypeTimestampTZ,
 	oid.T_uuid:         TypeUUID,
+	oid.T_varbit:       TypeBitArray,
 	oid.T_varchar:      typeVarChar,
 }
 
@@ -206,6 +222,8 @@ var aliasedOidToName = map[oid.Oid]string{
 	oid.T_int2vector: "int2vector",
 	oid.T_text:       "text",
 	oid.T_bytea:      "bytea",
+	oid.T_bit:        "bit",
+	oid.T_cidr:       "cidr",
 	oid.T_varchar:    "varchar",
 	oid.T_numeric:    "numeric",
 	oid.T_record:     "record",
@@ -397,6 +415,38 @@ func (tUUID) Oid() oid.Oid                { return oid.T_uuid }
 func (tUUID) SQLName() string             { return "uuid" }
 func (tUUID) IsAmbiguous() bool           { return false }
 
+type tINet struct{}
+
+func (tINet) String() string              { return "inet" }
+func (tINet) Equivalent(other Type) bool  { return UnwrapType(other) == TypeINet || other == TypeAny }
+func (tINet) FamilyEqual(other Type) bool { return UnwrapType(other) == TypeINet }
+func (tINet) Size() (uintptr, bool)       { return unsafe.Sizeof(DIPAddr{}), variableSize }
+func (tINet) Oid() oid.Oid                { return oid.T_inet }
+func (tINet) SQLName() string             { return "inet" }
+func (tINet) IsAmbiguous() bool           { return false }
+
+type tTime struct{}
+
+func (tTime) String() string              { return "time" }
+func (tTime) Equivalent(other Type) bool  { return UnwrapType(other) == TypeTime || other == TypeAny }
+func (tTime) FamilyEqual(other Type) bool { return UnwrapType(other) == TypeTime }
+func (tTime) Size() (uintptr, bool)       { return unsafe.Sizeof(DTime(0)), fixedSize }
+func (tTime) Oid() oid.Oid                { return oid.T_time }
+func (tTime) SQLName() string             { return "time without time zone" }
+func (tTime) IsAmbiguous() bool           { return false }
+
+type tBitArray struct{}
+
+func (tBitArray) String() string { return "varbit" }
+func (tBitArray) Equivalent(other Type) bool {
+	return UnwrapType(other) == TypeBitArray || other == TypeAny
+}
+func (tBitArray) FamilyEqual(other Type) bool { return UnwrapType(other) == TypeBitArray }
+func (tBitArray) Size() (uintptr, bool)       { return unsafe.Sizeof(DBitArray{}), variableSize }
+func (tBitArray) Oid() oid.Oid                { return oid.T_varbit }
+func (tBitArray) SQLName() string             { return "bit varying" }
+func (tBitArray) IsAmbiguous() bool           { return false }
+
 // TTuple is the type of a DTuple.
 type TTuple []Type
 
@@ -559,7 +609,12 @@ func (a TArray) IsAmbiguous() bool {
 
 // TTable is the type of a DTable.
 // See the comments at the start of generator_builtins.go for details.
-type TTable struct{ Cols TTuple }
+type TTable struct {
+	Cols TTuple
+	// Labels, if non-nil, contains the names of the columns of the
+	// table. Its length must then be equal to that of Cols.
+	Labels []string
+}
 
 func (a TTable) String() string { return "setof " + a.Cols.String() }
 
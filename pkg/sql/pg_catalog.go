@@ -941,6 +941,9 @@ func indexDefFromDescriptor(
 			Column:    parser.Name(name),
 			Direction: parser.Ascending,
 		}
+		if expr := index.ColumnExpr(i); expr != nil {
+			elem = parser.IndexElem{Expr: expr, Direction: parser.Ascending}
+		}
 		if index.ColumnDirections[i] == sqlbase.IndexDescriptor_DESC {
 			elem.Direction = parser.Descending
 		}
@@ -973,6 +976,13 @@ func indexDefFromDescriptor(
 		}
 		indexDef.Interleave = intlDef
 	}
+	if index.IsPartial() {
+		pred, err := parser.ParseExpr(index.Predicate)
+		if err != nil {
+			return "", err
+		}
+		indexDef.Predicate = pred
+	}
 	return indexDef.String(), nil
 }
 
@@ -1220,9 +1230,30 @@ CREATE TABLE pg_catalog.pg_roles (
 		// need to do the same. This shouldn't be an issue, because pg_roles doesn't
 		// include sensitive information such as password hashes.
 		h := makeOidHasher()
+		// The login restrictions are shown to everyone as well, like
+		// Postgres does.
+		rows, err := p.queryRowsAsRoot(ctx,
+			`SELECT username, "noLogin", "validUntil", "connectionLimit" FROM system.user_options`)
+		if err != nil {
+			return err
+		}
+		userOptions := make(map[string]parser.Datums, len(rows))
+		for _, row := range rows {
+			userOptions[string(parser.MustBeDString(row[0]))] = row[1:]
+		}
 		return forEachUser(ctx, p,
 			func(username string) error {
 				isRoot := parser.DBool(username == security.RootUser)
+				var canLogin, connLimit, validUntil parser.Datum = parser.DBoolTrue, negOneVal, parser.DNull
+				if opts, ok := userOptions[username]; ok {
+					if opts[0] == parser.DBoolTrue {
+						canLogin = parser.DBoolFalse
+					}
+					validUntil = opts[1]
+					if opts[2] != parser.DNull {
+						connLimit = opts[2]
+					}
+				}
 				return addRow(
 					h.UserOid(username),           // oid
 					parser.NewDName(username),     // rolname
@@ -1231,10 +1262,10 @@ CREATE TABLE pg_catalog.pg_roles (
 					parser.MakeDBool(isRoot),      // rolcreaterole
 					parser.MakeDBool(isRoot),      // rolcreatedb
 					parser.MakeDBool(false),       // rolcatupdate
-					parser.MakeDBool(true),        // rolcanlogin
-					negOneVal,                     // rolconnlimit
+					canLogin,                      // rolcanlogin
+					connLimit,                     // rolconnlimit
 					parser.NewDString("********"), // rolpassword
-					parser.DNull,                  // rolvaliduntil
+					validUntil,                    // rolvaliduntil
 					parser.NewDString("{}"),       // rolconfig
 				)
 			})
@@ -1533,6 +1564,9 @@ var datumToTypeCategory = map[reflect.Type]*parser.DString{
 	reflect.TypeOf(parser.TypeTable):       typCategoryPseudo,
 	reflect.TypeOf(parser.TypeOid):         typCategoryNumeric,
 	reflect.TypeOf(parser.TypeUUID):        typCategoryUserDefined,
+	reflect.TypeOf(parser.TypeINet):        typCategoryNetworkAddr,
+	reflect.TypeOf(parser.TypeTime):        typCategoryDateTime,
+	reflect.TypeOf(parser.TypeBitArray):    typCategoryBitString,
 }
 
 func typCategory(typ parser.Type) parser.Datum {
@@ -0,0 +1,181 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package pgwire
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// copyOutState is the state of a COPY TO STDOUT statement that is streaming
+// rows to the client.
+type copyOutState struct {
+	// active is set once the CopyOutResponse has been sent for the statement
+	// currently executing.
+	active bool
+	format sql.CopyOutFormat
+	// line accumulates the encoded row before it is sent as a CopyData
+	// message.
+	line bytes.Buffer
+	// datumBuf is scratch space used to render datums in their text format.
+	datumBuf writeBuffer
+}
+
+var _ sql.CopyOutWriter = &v3Conn{}
+
+// BeginCopyOut implements the sql.CopyOutWriter interface. It switches the
+// client into COPY OUT mode and sends the header line, if requested.
+// See: https://www.postgresql.org/docs/current/static/protocol-flow.html#PROTOCOL-COPY
+func (c *v3Conn) BeginCopyOut(
+	ctx context.Context, columns sqlbase.ResultColumns, format sql.CopyOutFormat,
+) error {
+	if c.copyOut.active {
+		// This is an automatic retry of the statement. The rows sent by the
+		// previous attempt cannot be taken back.
+		return errors.New("COPY TO STDOUT cannot be retried after rows were sent to the client")
+	}
+	c.copyOut.active = true
+	c.copyOut.format = format
+
+	c.writeBuf.initMsg(serverMsgCopyOutResponse)
+	c.writeBuf.writeByte(byte(formatText))
+	c.writeBuf.putInt16(int16(len(columns)))
+	for range columns {
+		c.writeBuf.putInt16(int16(formatText))
+	}
+	if err := c.writeBuf.finishMsg(c.wr); err != nil {
+		return err
+	}
+
+	if !format.Header {
+		return nil
+	}
+	line := &c.copyOut.line
+	line.Reset()
+	for i, col := range columns {
+		if i > 0 {
+			line.WriteByte(format.Delimiter)
+		}
+		writeCopyCSVField(line, []byte(col.Name), format)
+	}
+	line.WriteByte('\n')
+	return c.sendCopyData(line.Bytes())
+}
+
+// CopyOutRow implements the sql.CopyOutWriter interface. Each row is sent
+// in its own CopyData message, as Postgres does.
+func (c *v3Conn) CopyOutRow(ctx context.Context, row parser.Datums) error {
+	format := c.copyOut.format
+	line := &c.copyOut.line
+	line.Reset()
+	for i, d := range row {
+		if i > 0 {
+			line.WriteByte(format.Delimiter)
+		}
+		if d == parser.DNull {
+			line.WriteString(format.Null)
+			continue
+		}
+		text := c.copyOutDatumText(d)
+		if format.CSV {
+			writeCopyCSVField(line, text, format)
+		} else {
+			writeCopyTextField(line, text, format.Delimiter)
+		}
+	}
+	line.WriteByte('\n')
+	return c.sendCopyData(line.Bytes())
+}
+
+// copyOutDatumText returns the text format of a non-NULL datum, exactly as
+// it would appear in a DataRow message. The returned slice is only valid
+// until the next call.
+func (c *v3Conn) copyOutDatumText(d parser.Datum) []byte {
+	b := &c.copyOut.datumBuf
+	b.reset()
+	b.writeTextDatum(d, c.session.Location)
+	// Skip the length prefix.
+	return b.wrapped.Bytes()[4:]
+}
+
+func (c *v3Conn) sendCopyData(data []byte) error {
+	c.writeBuf.initMsg(serverMsgCopyData)
+	c.writeBuf.write(data)
+	return c.writeBuf.finishMsg(c.wr)
+}
+
+func (c *v3Conn) sendCopyDone() error {
+	c.writeBuf.initMsg(serverMsgCopyDone)
+	return c.writeBuf.finishMsg(c.wr)
+}
+
+// writeCopyTextField writes a field in the COPY text format, escaping the
+// characters that decodeCopy in the sql package unescapes.
+func writeCopyTextField(buf *bytes.Buffer, field []byte, delim byte) {
+	for _, ch := range field {
+		switch ch {
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\v':
+			buf.WriteString(`\v`)
+		default:
+			if ch == delim {
+				buf.WriteByte('\\')
+			}
+			buf.WriteByte(ch)
+		}
+	}
+}
+
+// writeCopyCSVField writes a field in the COPY CSV format. Fields are
+// quoted if they contain the delimiter, a quote or a line break, or if they
+// would otherwise be read back as NULL.
+func writeCopyCSVField(buf *bytes.Buffer, field []byte, format sql.CopyOutFormat) {
+	needsQuotes := string(field) == format.Null
+	for _, ch := range field {
+		if ch == format.Delimiter || ch == '"' || ch == '\n' || ch == '\r' {
+			needsQuotes = true
+			break
+		}
+	}
+	if !needsQuotes {
+		buf.Write(field)
+		return
+	}
+	buf.WriteByte('"')
+	for _, ch := range field {
+		if ch == '"' {
+			buf.WriteByte('"')
+		}
+		buf.WriteByte(ch)
+	}
+	buf.WriteByte('"')
+}
@@ -0,0 +1,74 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package pgwire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestWriteCopyTextField(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		field    string
+		delim    byte
+		expected string
+	}{
+		{`abc`, '\t', `abc`},
+		{"a\tb", '\t', `a\tb`},
+		{"a\nb\rc", '\t', `a\nb\rc`},
+		{`a\b`, '\t', `a\\b`},
+		{`a|b`, '|', `a\|b`},
+		{`a,b`, '\t', `a,b`},
+	}
+	for _, tc := range testCases {
+		var buf bytes.Buffer
+		writeCopyTextField(&buf, []byte(tc.field), tc.delim)
+		if actual := buf.String(); actual != tc.expected {
+			t.Errorf("%q: expected %q, got %q", tc.field, tc.expected, actual)
+		}
+	}
+}
+
+func TestWriteCopyCSVField(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	csv := sql.CopyOutFormat{CSV: true, Delimiter: ',', Null: ""}
+	testCases := []struct {
+		field    string
+		format   sql.CopyOutFormat
+		expected string
+	}{
+		{`abc`, csv, `abc`},
+		{`a,b`, csv, `"a,b"`},
+		{`a"b`, csv, `"a""b"`},
+		{"a\nb", csv, "\"a\nb\""},
+		{``, csv, `""`},
+		{`\N`, csv, `\N`},
+		{`\N`, sql.CopyOutFormat{CSV: true, Delimiter: ',', Null: `\N`}, `"\N"`},
+		{`a,b`, sql.CopyOutFormat{CSV: true, Delimiter: '|'}, `a,b`},
+	}
+	for _, tc := range testCases {
+		var buf bytes.Buffer
+		writeCopyCSVField(&buf, []byte(tc.field), tc.format)
+		if actual := buf.String(); actual != tc.expected {
+			t.Errorf("%q: expected %q, got %q", tc.field, tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package hba implements a parser for host-based authentication
+// configurations, modeled after PostgreSQL's pg_hba.conf.
+//
+// A configuration is a list of entries, one per line. Each entry has the
+// form:
+//
+//   TYPE DATABASE USER ADDRESS METHOD
+//
+// where ADDRESS is omitted for entries of type "local". Blank lines and
+// everything following a '#' are ignored. The first entry matching a
+// connection determines the authentication method used for it; connections
+// matching no entry are rejected.
+//
+// See: https://www.postgresql.org/docs/current/static/auth-pg-hba-conf.html
+package hba
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ConnType represents the type of a client connection. The ConnType of
+// an Entry is a bitmask of the connection types it matches.
+type ConnType int
+
+const (
+	// ConnLocal matches connections over a unix socket.
+	ConnLocal ConnType = 1 << iota
+	// ConnHostNoSSL matches TCP connections not using TLS.
+	ConnHostNoSSL
+	// ConnHostSSL matches TCP connections using TLS.
+	ConnHostSSL
+	// ConnHostAny matches all TCP connections.
+	ConnHostAny = ConnHostNoSSL | ConnHostSSL
+)
+
+var connTypeNames = map[string]ConnType{
+	"local":     ConnLocal,
+	"host":      ConnHostAny,
+	"hostssl":   ConnHostSSL,
+	"hostnossl": ConnHostNoSSL,
+}
+
+func (t ConnType) String() string {
+	switch t {
+	case ConnLocal:
+		return "local"
+	case ConnHostAny:
+		return "host"
+	case ConnHostSSL:
+		return "hostssl"
+	case ConnHostNoSSL:
+		return "hostnossl"
+	default:
+		return fmt.Sprintf("ConnType(%d)", int(t))
+	}
+}
+
+// Method is an authentication method.
+type Method string
+
+const (
+	// MethodCert requires the client to present a valid certificate.
+	MethodCert Method = "cert"
+	// MethodPassword requires the client to provide a password, even if
+	// it presented a certificate.
+	MethodPassword Method = "password"
+	// MethodCertPassword uses certificate authentication if the client
+	// presented a certificate, and password authentication otherwise.
+	MethodCertPassword Method = "cert-password"
+	// MethodScramSHA256 requires the client to prove that it knows the
+	// password with a SCRAM-SHA-256 exchange, which does not send the
+	// password over the wire.
+	MethodScramSHA256 Method = "scram-sha-256"
+	// MethodReject rejects the connection.
+	MethodReject Method = "reject"
+)
+
+var methods = map[Method]struct{}{
+	MethodCert:         {},
+	MethodPassword:     {},
+	MethodCertPassword: {},
+	MethodScramSHA256:  {},
+	MethodReject:       {},
+}
+
+// keywordAll matches all databases or all users.
+const keywordAll = "all"
+
+// Entry is a single line of a configuration.
+type Entry struct {
+	ConnType ConnType
+	// Database and User are the lists of database and user names matched
+	// by the entry. They are empty if the entry matches all of them.
+	Database []string
+	User     []string
+	// Address is the network matched by the entry. It is nil if the entry
+	// matches all addresses, or for local entries.
+	Address *net.IPNet
+	Method  Method
+}
+
+// Conf is a parsed configuration.
+type Conf struct {
+	Entries []Entry
+}
+
+// Parse parses a configuration. Database and user names are matched as
+// written, so callers are responsible for normalizing them.
+func Parse(input string) (*Conf, error) {
+	var conf Conf
+	for i, line := range strings.Split(input, "\n") {
+		if idx := strings.IndexByte(line, '#'); idx >= 0 {
+			line = line[:idx]
+		}
+		fields, err := splitFields(line)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", i+1)
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		entry, err := parseEntry(fields)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", i+1)
+		}
+		conf.Entries = append(conf.Entries, entry)
+	}
+	return &conf, nil
+}
+
+// splitFields splits a line into whitespace-separated fields. Names can be
+// enclosed in double quotes to include whitespace or commas; the quotes are
+// kept so that parseNames can tell quoted keywords from names.
+func splitFields(line string) ([]string, error) {
+	var fields []string
+	var buf bytes.Buffer
+	inQuotes := false
+	for _, ch := range line {
+		switch {
+		case ch == '"':
+			inQuotes = !inQuotes
+			buf.WriteRune(ch)
+		case !inQuotes && (ch == ' ' || ch == '\t' || ch == '\r'):
+			if buf.Len() > 0 {
+				fields = append(fields, buf.String())
+				buf.Reset()
+			}
+		default:
+			buf.WriteRune(ch)
+		}
+	}
+	if inQuotes {
+		return nil, errors.New("unterminated quoted string")
+	}
+	if buf.Len() > 0 {
+		fields = append(fields, buf.String())
+	}
+	return fields, nil
+}
+
+func parseEntry(fields []string) (Entry, error) {
+	var entry Entry
+	connType, ok := connTypeNames[fields[0]]
+	if !ok {
+		return entry, errors.Errorf("unknown connection type: %q", fields[0])
+	}
+	entry.ConnType = connType
+
+	expected := 5
+	if connType == ConnLocal {
+		expected = 4
+	}
+	if len(fields) != expected {
+		return entry, errors.Errorf("expected %d fields for connection type %s, found %d",
+			expected, connType, len(fields))
+	}
+
+	var err error
+	if entry.Database, err = parseNames(fields[1]); err != nil {
+		return entry, errors.Wrap(err, "invalid database")
+	}
+	if entry.User, err = parseNames(fields[2]); err != nil {
+		return entry, errors.Wrap(err, "invalid user")
+	}
+	if connType != ConnLocal {
+		if entry.Address, err = parseAddress(fields[3]); err != nil {
+			return entry, err
+		}
+	}
+
+	method := Method(fields[expected-1])
+	if _, ok := methods[method]; !ok {
+		return entry, errors.Errorf("unknown authentication method: %q", method)
+	}
+	entry.Method = method
+	return entry, nil
+}
+
+// parseNames parses a comma-separated list of names, possibly quoted. It
+// returns nil if the list contains the unquoted keyword "all".
+func parseNames(field string) ([]string, error) {
+	var names []string
+	matchAll := false
+	var buf bytes.Buffer
+	quoted, inQuotes := false, false
+	endName := func() error {
+		name := buf.String()
+		if name == "" {
+			return errors.Errorf("invalid name list: %q", field)
+		}
+		if !quoted && name == keywordAll {
+			matchAll = true
+		}
+		names = append(names, name)
+		buf.Reset()
+		quoted = false
+		return nil
+	}
+	for _, ch := range field {
+		switch {
+		case ch == '"':
+			inQuotes = !inQuotes
+			quoted = true
+		case ch == ',' && !inQuotes:
+			if err := endName(); err != nil {
+				return nil, err
+			}
+		default:
+			buf.WriteRune(ch)
+		}
+	}
+	if err := endName(); err != nil {
+		return nil, err
+	}
+	if matchAll {
+		return nil, nil
+	}
+	return names, nil
+}
+
+// parseAddress parses the address of an entry, which can be the keyword
+// "all", an IP address or a CIDR network.
+func parseAddress(field string) (*net.IPNet, error) {
+	if field == keywordAll {
+		return nil, nil
+	}
+	if strings.IndexByte(field, '/') >= 0 {
+		_, network, err := net.ParseCIDR(field)
+		if err != nil {
+			return nil, errors.Errorf("invalid address: %q", field)
+		}
+		return network, nil
+	}
+	ip := net.ParseIP(field)
+	if ip == nil {
+		return nil, errors.Errorf("invalid address: %q", field)
+	}
+	bits := 8 * net.IPv6len
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+		bits = 8 * net.IPv4len
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
+
+// Find returns the first entry matching a connection of the given type
+// to the given database, by the given user, from the given client address.
+// addr is ignored for local connections. The second return value is false
+// if no entry matches.
+func (c *Conf) Find(connType ConnType, database, user string, addr net.IP) (Entry, bool) {
+	if c == nil {
+		return Entry{}, false
+	}
+	for _, e := range c.Entries {
+		if e.Matches(connType, database, user, addr) {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Matches returns whether the entry matches a connection. See Find.
+func (e Entry) Matches(connType ConnType, database, user string, addr net.IP) bool {
+	if e.ConnType&connType == 0 {
+		return false
+	}
+	if !matchName(e.Database, database) || !matchName(e.User, user) {
+		return false
+	}
+	if connType != ConnLocal && e.Address != nil {
+		if addr == nil || !e.Address.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchName(names []string, name string) bool {
+	if names == nil {
+		return true
+	}
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package hba
+
+import (
+	"net"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+)
+
+func TestParseError(t *testing.T) {
+	testCases := []struct {
+		conf     string
+		expected string
+	}{
+		{`hosts all all all cert`, `line 1: unknown connection type: "hosts"`},
+		{"\nhost all all cert", `line 2: expected 5 fields for connection type host, found 4`},
+		{`local all all all cert`, `line 1: expected 4 fields for connection type local, found 5`},
+		{`host all all all trust`, `line 1: unknown authentication method: "trust"`},
+		{`host all all 10.0.0.0/33 cert`, `line 1: invalid address: "10.0.0.0/33"`},
+		{`host all all localhost cert`, `line 1: invalid address: "localhost"`},
+		{`host db, all all cert`, `line 1: invalid database: invalid name list: "db,"`},
+		{`host all "a b all all cert`, `line 1: unterminated quoted string`},
+	}
+	for _, tc := range testCases {
+		if _, err := Parse(tc.conf); !testutils.IsError(err, tc.expected) {
+			t.Errorf("%q: expected error %q, got %v", tc.conf, tc.expected, err)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	conf, err := Parse(`
+# Root may only connect locally.
+local     all        root         cert
+hostssl   all        root         127.0.0.1  cert
+host      all        root         all        reject
+
+# No passwords from the outside.
+hostssl   all        all          10.0.0.0/8 cert-password
+host      all        all          ::1/128    password
+hostssl   "my db",b  "all",carl   all        cert
+hostnossl all        dave         all        scram-sha-256
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		connType ConnType
+		database string
+		user     string
+		addr     string
+		found    bool
+		method   Method
+	}{
+		{ConnLocal, "db", "root", "", true, MethodCert},
+		{ConnHostSSL, "db", "root", "127.0.0.1", true, MethodCert},
+		{ConnHostNoSSL, "db", "root", "127.0.0.1", true, MethodReject},
+		{ConnHostSSL, "db", "root", "10.1.2.3", true, MethodReject},
+		{ConnHostSSL, "db", "bob", "10.1.2.3", true, MethodCertPassword},
+		{ConnHostNoSSL, "db", "bob", "10.1.2.3", false, ""},
+		{ConnHostNoSSL, "db", "bob", "::1", true, MethodPassword},
+		{ConnHostSSL, "my db", "all", "192.168.0.1", true, MethodCert},
+		{ConnHostSSL, "b", "carl", "192.168.0.1", true, MethodCert},
+		{ConnHostSSL, "c", "carl", "192.168.0.1", false, ""},
+		{ConnHostSSL, "b", "bob", "192.168.0.1", false, ""},
+		{ConnLocal, "db", "bob", "", false, ""},
+		{ConnHostNoSSL, "db", "dave", "192.168.0.1", true, MethodScramSHA256},
+	}
+	for _, tc := range testCases {
+		entry, found := conf.Find(tc.connType, tc.database, tc.user, net.ParseIP(tc.addr))
+		if found != tc.found || entry.Method != tc.method {
+			t.Errorf("%s %s %s %s: expected %t %q, got %t %q",
+				tc.connType, tc.database, tc.user, tc.addr, tc.found, tc.method, found, entry.Method)
+		}
+	}
+}
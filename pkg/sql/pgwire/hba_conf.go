@@ -0,0 +1,97 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package pgwire
+
+import (
+	"net"
+	"sync/atomic"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/hba"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+const hbaConfSettingName = "server.host_based_authentication.configuration"
+
+// hbaConf holds the parsed host-based authentication configuration, or
+// nil if the setting is empty. It is updated whenever the cluster setting
+// changes. See the hba package for the format of the configuration.
+var hbaConf = func() *atomic.Value {
+	setting := settings.RegisterValidatedStringSetting(
+		hbaConfSettingName,
+		"host-based authentication configuration to use during connection authentication",
+		"",
+		func(s string) error {
+			_, err := hba.Parse(s)
+			return err
+		},
+	)
+	var ref atomic.Value
+	ref.Store((*hba.Conf)(nil))
+	setting.OnChange(func() {
+		var conf *hba.Conf
+		if s := setting.Get(); s != "" {
+			var err error
+			if conf, err = hba.Parse(s); err != nil {
+				log.Warningf(context.Background(), "invalid %s: %v", hbaConfSettingName, err)
+				return
+			}
+		}
+		ref.Store(conf)
+	})
+	return &ref
+}()
+
+// lookupAuthMethod returns the authentication method to use for the
+// connection according to the host-based authentication configuration.
+// Without a configuration, clients authenticate with a certificate if they
+// present one and with a password otherwise. An error is returned if the
+// connection must be rejected.
+func (c *v3Conn) lookupAuthMethod(isTLS bool) (hba.Method, error) {
+	conf := hbaConf.Load().(*hba.Conf)
+	if conf == nil {
+		return hba.MethodCertPassword, nil
+	}
+
+	connType := hba.ConnHostNoSSL
+	if isTLS {
+		connType = hba.ConnHostSSL
+	}
+	var ip net.IP
+	addr := c.conn.RemoteAddr()
+	if addr.Network() == "unix" {
+		connType = hba.ConnLocal
+	} else if host, _, err := net.SplitHostPort(addr.String()); err == nil {
+		ip = net.ParseIP(host)
+	}
+
+	user, database := c.sessionArgs.User, parser.Name(c.sessionArgs.Database).Normalize()
+	entry, ok := conf.Find(connType, database, user, ip)
+	if !ok {
+		return "", pgerror.NewErrorf(pgerror.CodeInvalidAuthorizationSpecificationError,
+			"no %s entry for %s connection from %q, user %q, database %q",
+			hbaConfSettingName, connType, addr, user, database)
+	}
+	if entry.Method == hba.MethodReject {
+		return "", pgerror.NewErrorf(pgerror.CodeInvalidAuthorizationSpecificationError,
+			"%s rejects %s connection from %q, user %q, database %q",
+			hbaConfSettingName, connType, addr, user, database)
+	}
+	return entry.Method, nil
+}
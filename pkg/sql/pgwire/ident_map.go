@@ -0,0 +1,79 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package pgwire
+
+import (
+	"crypto/x509"
+	"sync/atomic"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/identmap"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+const identMapSettingName = "server.identity_map.configuration"
+
+// identMap holds the parsed identity map, or nil if the setting is empty.
+// It is updated whenever the cluster setting changes. See the identmap
+// package for the format of the map.
+var identMap = func() *atomic.Value {
+	setting := settings.RegisterValidatedStringSetting(
+		identMapSettingName,
+		"mapping of client certificate identities to SQL users, used during certificate authentication",
+		"",
+		func(s string) error {
+			_, err := identmap.Parse(s)
+			return err
+		},
+	)
+	var ref atomic.Value
+	ref.Store((*identmap.Conf)(nil))
+	setting.OnChange(func() {
+		var conf *identmap.Conf
+		if s := setting.Get(); s != "" {
+			var err error
+			if conf, err = identmap.Parse(s); err != nil {
+				log.Warningf(context.Background(), "invalid %s: %v", identMapSettingName, err)
+				return
+			}
+		}
+		ref.Store(conf)
+	})
+	return &ref
+}()
+
+// certificateUser returns the user authenticated by a client certificate
+// for a connection requesting the given user. This is the requested user
+// if the identity map maps the common name or a subject alternative name
+// of the certificate to it, and the normalized common name of the
+// certificate otherwise. Identities are never mapped to the node user,
+// which can act on behalf of all other users.
+func certificateUser(cert *x509.Certificate, requestedUser string) string {
+	conf := identMap.Load().(*identmap.Conf)
+	if conf != nil && requestedUser != security.NodeUser {
+		for _, id := range identmap.CertificateIdentities(cert) {
+			for _, user := range conf.Map(id.Value) {
+				if parser.Name(user).Normalize() == requestedUser {
+					return requestedUser
+				}
+			}
+		}
+	}
+	return parser.Name(cert.Subject.CommonName).Normalize()
+}
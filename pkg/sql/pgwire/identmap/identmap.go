@@ -0,0 +1,208 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package identmap implements a parser for identity maps, which map the
+// identities of client certificates to users. They are modeled after
+// PostgreSQL's pg_ident.conf, without the map name since there is a single
+// map.
+//
+// A map is a list of entries, one per line. Each entry has the form:
+//
+//   SYSTEM-IDENTITY USER
+//
+// If SYSTEM-IDENTITY starts with a '/', the remainder is a regular
+// expression matched against identities, and a "\1" in USER is replaced by
+// the first parenthesized subexpression of the match. Otherwise the entry
+// matches the identity as written. Fields can be enclosed in double quotes
+// to include whitespace or '#'. Blank lines and everything following a
+// '#' are ignored.
+//
+// See: https://www.postgresql.org/docs/current/static/auth-username-maps.html
+package identmap
+
+import (
+	"bytes"
+	"crypto/x509"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// backReference is replaced in the user of an entry by the first
+// subexpression matched by its regular expression.
+const backReference = `\1`
+
+// Entry is a single line of an identity map.
+type Entry struct {
+	// Identity is the identity matched by the entry. It is empty if the
+	// entry uses a regular expression.
+	Identity string
+	// Pattern is the regular expression matched by the entry, or nil.
+	Pattern *regexp.Regexp
+	// User is the user to which matching identities are mapped. It may
+	// contain a back reference if Pattern is set.
+	User string
+}
+
+// Conf is a parsed identity map.
+type Conf struct {
+	Entries []Entry
+}
+
+// Parse parses an identity map. User names are returned as written, so
+// callers are responsible for normalizing them.
+func Parse(input string) (*Conf, error) {
+	var conf Conf
+	for i, line := range strings.Split(input, "\n") {
+		fields, err := splitFields(line)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", i+1)
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		entry, err := parseEntry(fields)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", i+1)
+		}
+		conf.Entries = append(conf.Entries, entry)
+	}
+	return &conf, nil
+}
+
+// splitFields splits a line into whitespace-separated fields, dropping
+// the quotes around quoted fields and the comment ending the line.
+func splitFields(line string) ([]string, error) {
+	var fields []string
+	var buf bytes.Buffer
+	inQuotes, inField := false, false
+	endField := func() {
+		if inField {
+			fields = append(fields, buf.String())
+			buf.Reset()
+			inField = false
+		}
+	}
+	for _, ch := range line {
+		switch {
+		case ch == '"':
+			inQuotes = !inQuotes
+			inField = true
+		case inQuotes:
+			buf.WriteRune(ch)
+		case ch == '#':
+			endField()
+			return fields, nil
+		case ch == ' ' || ch == '\t' || ch == '\r':
+			endField()
+		default:
+			buf.WriteRune(ch)
+			inField = true
+		}
+	}
+	if inQuotes {
+		return nil, errors.New("unterminated quoted string")
+	}
+	endField()
+	return fields, nil
+}
+
+func parseEntry(fields []string) (Entry, error) {
+	var entry Entry
+	if len(fields) != 2 {
+		return entry, errors.Errorf("expected 2 fields, found %d", len(fields))
+	}
+	identity, user := fields[0], fields[1]
+	if identity == "" || user == "" {
+		return entry, errors.New("empty identity or user")
+	}
+	entry.User = user
+
+	if !strings.HasPrefix(identity, "/") {
+		if strings.Contains(user, backReference) {
+			return entry, errors.Errorf("user %q refers to a subexpression, but identity %q is not a regular expression",
+				user, identity)
+		}
+		entry.Identity = identity
+		return entry, nil
+	}
+
+	var err error
+	if entry.Pattern, err = regexp.Compile(identity[1:]); err != nil {
+		return entry, errors.Wrap(err, "invalid regular expression")
+	}
+	if strings.Contains(user, backReference) && entry.Pattern.NumSubexp() == 0 {
+		return entry, errors.Errorf("user %q refers to a subexpression, but regular expression %q has none",
+			user, entry.Pattern)
+	}
+	return entry, nil
+}
+
+// Map returns the users to which the given identity is mapped, in the
+// order of the entries of the map.
+func (c *Conf) Map(identity string) []string {
+	if c == nil {
+		return nil
+	}
+	var users []string
+	for _, e := range c.Entries {
+		if user, ok := e.Map(identity); ok {
+			users = append(users, user)
+		}
+	}
+	return users
+}
+
+// Map returns the user to which the entry maps the given identity. The
+// second return value is false if the entry does not match the identity.
+func (e Entry) Map(identity string) (string, bool) {
+	if e.Pattern == nil {
+		return e.User, e.Identity == identity
+	}
+	match := e.Pattern.FindStringSubmatch(identity)
+	if match == nil {
+		return "", false
+	}
+	if len(match) < 2 {
+		return e.User, true
+	}
+	return strings.Replace(e.User, backReference, match[1], -1), true
+}
+
+// Identity is an identity of a client certificate.
+type Identity struct {
+	// Field is the certificate field holding the identity: "CN" for the
+	// common name of the subject, or "DNS" or "email" for subject
+	// alternative names.
+	Field string
+	Value string
+}
+
+// CertificateIdentities returns the identities of a client certificate
+// that can be mapped to users: its common name, followed by its DNS names
+// and email addresses.
+func CertificateIdentities(cert *x509.Certificate) []Identity {
+	var ids []Identity
+	if cn := cert.Subject.CommonName; cn != "" {
+		ids = append(ids, Identity{Field: "CN", Value: cn})
+	}
+	for _, name := range cert.DNSNames {
+		ids = append(ids, Identity{Field: "DNS", Value: name})
+	}
+	for _, addr := range cert.EmailAddresses {
+		ids = append(ids, Identity{Field: "email", Value: addr})
+	}
+	return ids
+}
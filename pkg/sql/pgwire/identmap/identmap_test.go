@@ -0,0 +1,96 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package identmap
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+)
+
+func TestParseError(t *testing.T) {
+	testCases := []struct {
+		conf     string
+		expected string
+	}{
+		{`alice`, `line 1: expected 2 fields, found 1`},
+		{"\nalice bob carl", `line 2: expected 2 fields, found 3`},
+		{`"" bob`, `line 1: empty identity or user`},
+		{`/^(.*$ \1`, `line 1: invalid regular expression: error parsing regexp: missing closing )`},
+		{`/^.*$ \1`, `line 1: user "\\1" refers to a subexpression, but regular expression "^.*$" has none`},
+		{`alice \1`, `line 1: user "\\1" refers to a subexpression, but identity "alice" is not a regular expression`},
+		{`"alice bob`, `line 1: unterminated quoted string`},
+	}
+	for _, tc := range testCases {
+		if _, err := Parse(tc.conf); !testutils.IsError(err, regexp.QuoteMeta(tc.expected)) {
+			t.Errorf("%q: expected error %q, got %v", tc.conf, tc.expected, err)
+		}
+	}
+}
+
+func TestMap(t *testing.T) {
+	conf, err := Parse(`
+# Corporate email addresses map to the user of the same name.
+/^(.*)@example\.com$       \1
+/^(.*)@example\.com$       \1_ro   # and to its read-only user.
+backup.svc.example.com     backup
+"Build #12"                ci
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		identity string
+		users    []string
+	}{
+		{"alice@example.com", []string{"alice", "alice_ro"}},
+		{"alice@example.org", nil},
+		{"backup.svc.example.com", []string{"backup"}},
+		{"backup.svc.example.com.evil", nil},
+		{"Build #12", []string{"ci"}},
+	}
+	for _, tc := range testCases {
+		if users := conf.Map(tc.identity); !reflect.DeepEqual(users, tc.users) {
+			t.Errorf("%q: expected %q, got %q", tc.identity, tc.users, users)
+		}
+	}
+
+	var nilConf *Conf
+	if users := nilConf.Map("alice"); users != nil {
+		t.Errorf("expected no users, got %q", users)
+	}
+}
+
+func TestCertificateIdentities(t *testing.T) {
+	cert := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "Alice"},
+		DNSNames:       []string{"alice.example.com"},
+		EmailAddresses: []string{"alice@example.com", "a@example.com"},
+	}
+	expected := []Identity{
+		{Field: "CN", Value: "Alice"},
+		{Field: "DNS", Value: "alice.example.com"},
+		{Field: "email", Value: "alice@example.com"},
+		{Field: "email", Value: "a@example.com"},
+	}
+	if ids := CertificateIdentities(cert); !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected %v, got %v", expected, ids)
+	}
+}
@@ -836,6 +836,23 @@ func TestPGPreparedQuery(t *testing.T) {
 		"SELECT $1::UUID": {
 			baseTest.SetArgs("63616665-6630-3064-6465-616462656562").Results("63616665-6630-3064-6465-616462656562"),
 		},
+		"SELECT $1::INET": {
+			baseTest.SetArgs("192.168.0.1/24").Results("192.168.0.1/24"),
+			baseTest.SetArgs("::1").Results("::1"),
+		},
+		"SELECT $1::CIDR": {
+			baseTest.SetArgs("10.1.2.0/24").Results("10.1.2.0/24"),
+			baseTest.SetArgs("10.1.2.3/24").Results("10.1.2.0/24"),
+		},
+		"SELECT $1::TIME": {
+			baseTest.SetArgs("12:34:56.789").Results(time.Date(0, 1, 1, 12, 34, 56, 789000000, time.UTC)),
+		},
+		"SELECT $1::VARBIT, $1::VARBIT # '1010'": {
+			baseTest.SetArgs("0110").Results("0110", "1100"),
+		},
+		"SELECT ~$1": {
+			baseTest.SetArgs(5).Results(-6),
+		},
 
 		// TODO(jordan): blocked on #13651
 		//"SELECT $1::INT[]": {
@@ -1606,6 +1623,256 @@ func TestPGWireAuth(t *testing.T) {
 	})
 }
 
+func TestPGWireHBA(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.TODO())
+
+	rootPgURL, cleanupFn := sqlutils.PGUrl(
+		t, s.ServingAddr(), t.Name(), url.User(security.RootUser))
+	defer cleanupFn()
+	db, err := gosql.Open("postgres", rootPgURL.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(fmt.Sprintf("CREATE USER %s WITH PASSWORD 'abc'", server.TestUser)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("CREATE USER carl WITH PASSWORD 'abc'"); err != nil {
+		t.Fatal(err)
+	}
+
+	const conf = `
+# Root must use a certificate.
+hostssl  all  root      all  cert
+# testuser must use a password, even with a certificate.
+hostssl  all  testuser  all  password
+host     all  all       all  reject
+`
+	if _, err := db.Exec(
+		"SET CLUSTER SETTING server.host_based_authentication.configuration = '" + conf + "'",
+	); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if _, err := db.Exec(
+			"SET CLUSTER SETTING server.host_based_authentication.configuration = ''",
+		); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	testUserPgURL, cleanupFn := sqlutils.PGUrl(
+		t, s.ServingAddr(), t.Name(), url.User(server.TestUser))
+	defer cleanupFn()
+	// Wait for the setting to be applied.
+	testutils.SucceedsSoon(t, func() error {
+		if err := trivialQuery(testUserPgURL); !testutils.IsError(err, "pq: invalid password") {
+			return errors.Errorf("unexpected error: %v", err)
+		}
+		return nil
+	})
+	testUserPgURL.User = url.UserPassword(server.TestUser, "abc")
+	if err := trivialQuery(testUserPgURL); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := trivialQuery(rootPgURL); err != nil {
+		t.Fatal(err)
+	}
+
+	host, port, err := net.SplitHostPort(s.ServingAddr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	carlPgURL := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword("carl", "abc"),
+		Host:     net.JoinHostPort(host, port),
+		RawQuery: "sslmode=require",
+	}
+	if err := trivialQuery(carlPgURL); !testutils.IsError(err,
+		`server.host_based_authentication.configuration rejects hostssl connection`,
+	) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Invalid configurations are refused.
+	if _, err := db.Exec(
+		"SET CLUSTER SETTING server.host_based_authentication.configuration = 'host all all all trust'",
+	); !testutils.IsError(err, `unknown authentication method: "trust"`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPGWireUserOptions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.TODO())
+
+	rootPgURL, cleanupFn := sqlutils.PGUrl(
+		t, s.ServingAddr(), t.Name(), url.User(security.RootUser))
+	defer cleanupFn()
+	db, err := gosql.Open("postgres", rootPgURL.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(fmt.Sprintf(
+		"CREATE USER %s WITH PASSWORD 'abc' NOLOGIN", server.TestUser,
+	)); err != nil {
+		t.Fatal(err)
+	}
+
+	testUserPgURL, cleanupFn := sqlutils.PGUrl(
+		t, s.ServingAddr(), t.Name(), url.User(server.TestUser))
+	defer cleanupFn()
+	host, port, err := net.SplitHostPort(s.ServingAddr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	passwordPgURL := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(server.TestUser, "abc"),
+		Host:     net.JoinHostPort(host, port),
+		RawQuery: "sslmode=require",
+	}
+
+	if err := trivialQuery(testUserPgURL); !testutils.IsError(err,
+		"pq: user testuser is not permitted to log in",
+	) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// An expired password prevents password authentication only.
+	if _, err := db.Exec(fmt.Sprintf(
+		"ALTER USER %s WITH LOGIN VALID UNTIL '2017-01-01'", server.TestUser,
+	)); err != nil {
+		t.Fatal(err)
+	}
+	if err := trivialQuery(passwordPgURL); !testutils.IsError(err,
+		"pq: password of user testuser has expired",
+	) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := trivialQuery(testUserPgURL); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Exec(fmt.Sprintf(
+		"ALTER USER %s WITH VALID UNTIL 'infinity' CONNECTION LIMIT 1", server.TestUser,
+	)); err != nil {
+		t.Fatal(err)
+	}
+	if err := trivialQuery(passwordPgURL); err != nil {
+		t.Fatal(err)
+	}
+
+	// Hold a connection of testuser open with a transaction.
+	testUserDB, err := gosql.Open("postgres", testUserPgURL.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testUserDB.Close()
+	tx, err := testUserDB.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := trivialQuery(testUserPgURL); !testutils.IsError(err,
+		"pq: too many connections for user testuser",
+	) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if err := testUserDB.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The server notices the closed connection asynchronously.
+	testutils.SucceedsSoon(t, func() error {
+		return trivialQuery(testUserPgURL)
+	})
+}
+
+func TestPGWireIdentityMap(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.TODO())
+
+	rootPgURL, cleanupFn := sqlutils.PGUrl(
+		t, s.ServingAddr(), t.Name(), url.User(security.RootUser))
+	defer cleanupFn()
+	db, err := gosql.Open("postgres", rootPgURL.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, user := range []string{server.TestUser, "carl", "user_svc"} {
+		if _, err := db.Exec(fmt.Sprintf("CREATE USER %s", user)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The certificate of testuser is used to connect as other users.
+	testUserPgURL, cleanupFn := sqlutils.PGUrl(
+		t, s.ServingAddr(), t.Name(), url.User(server.TestUser))
+	defer cleanupFn()
+	carlPgURL, svcPgURL := testUserPgURL, testUserPgURL
+	carlPgURL.User = url.User("carl")
+	svcPgURL.User = url.User("user_svc")
+
+	if err := trivialQuery(carlPgURL); !testutils.IsError(err,
+		"pq: requested user is carl, but certificate is for testuser",
+	) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	const conf = `
+testuser         Carl
+/^test(.*)$      \1_svc
+`
+	if _, err := db.Exec(
+		"SET CLUSTER SETTING server.identity_map.configuration = '" + conf + "'",
+	); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if _, err := db.Exec(
+			"SET CLUSTER SETTING server.identity_map.configuration = ''",
+		); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// Wait for the setting to be applied.
+	testutils.SucceedsSoon(t, func() error {
+		return trivialQuery(carlPgURL)
+	})
+	if err := trivialQuery(svcPgURL); err != nil {
+		t.Fatal(err)
+	}
+	// The common name still authenticates the user of the same name.
+	if err := trivialQuery(testUserPgURL); err != nil {
+		t.Fatal(err)
+	}
+
+	// Invalid maps are refused.
+	if _, err := db.Exec(
+		"SET CLUSTER SETTING server.identity_map.configuration = 'testuser'",
+	); !testutils.IsError(err, `expected 2 fields, found 1`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestPGWireResultChange(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{})
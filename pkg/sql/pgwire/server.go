@@ -106,6 +106,9 @@ type Server struct {
 		// that is closed when the connection is done.
 		connCancelMap cancelChanMap
 		draining      bool
+		// userConns counts the authenticated connections of each user, to
+		// enforce the connection limits of the users.
+		userConns map[string]int64
 	}
 
 	sqlMemoryPool mon.MemoryMonitor
@@ -175,6 +178,7 @@ func MakeServer(
 
 	server.mu.Lock()
 	server.mu.connCancelMap = make(cancelChanMap)
+	server.mu.userConns = make(map[string]int64)
 	server.mu.Unlock()
 
 	return server
@@ -400,8 +404,15 @@ func (s *Server) ServeConn(ctx context.Context, conn net.Conn) error {
 
 		v3conn.sessionArgs.User = parser.Name(v3conn.sessionArgs.User).Normalize()
 		if err := v3conn.handleAuthentication(ctx, s.cfg.Insecure); err != nil {
-			return v3conn.sendInternalError(err.Error())
+			return v3conn.sendError(err)
+		}
+
+		user := v3conn.sessionArgs.User
+		if !s.acquireUserConn(user, v3conn.loginOptions.ConnectionLimit) {
+			return v3conn.sendError(pgerror.NewErrorf(pgerror.CodeTooManyConnectionsError,
+				"too many connections for user %s", user))
 		}
+		defer s.releaseUserConn(user)
 
 		// Reserve some memory for this connection using the server's
 		// monitor. This reduces pressure on the shared pool because the
@@ -429,3 +440,24 @@ func (s *Server) ServeConn(ctx context.Context, conn net.Conn) error {
 
 	return errors.Errorf("unknown protocol version %d", version)
 }
+
+// acquireUserConn counts a new connection of the given user, unless the user
+// already has limit connections. A negative limit means no limit.
+func (s *Server) acquireUserConn(user string, limit int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if limit >= 0 && s.mu.userConns[user] >= limit {
+		return false
+	}
+	s.mu.userConns[user]++
+	return true
+}
+
+// releaseUserConn uncounts a connection of the given user.
+func (s *Server) releaseUserConn(user string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.userConns[user]--; s.mu.userConns[user] == 0 {
+		delete(s.mu.userConns, user)
+	}
+}
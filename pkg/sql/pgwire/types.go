@@ -22,6 +22,7 @@ import (
 	"encoding/hex"
 	"math"
 	"math/big"
+	"net"
 	"strconv"
 	"strings"
 	"time"
@@ -29,8 +30,11 @@ import (
 	"golang.org/x/net/context"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/lib/pq"
 	"github.com/lib/pq/oid"
 	"github.com/pkg/errors"
@@ -164,6 +168,15 @@ func (b *writeBuffer) writeTextDatum(d parser.Datum, sessionLoc *time.Location)
 	case *parser.DInterval:
 		b.writeLengthPrefixedString(v.ValueAsString())
 
+	case *parser.DTime:
+		b.writeLengthPrefixedString(timeofday.TimeOfDay(*v).String())
+
+	case *parser.DIPAddr:
+		b.writeLengthPrefixedString(v.IPAddr.String())
+
+	case *parser.DBitArray:
+		b.writeLengthPrefixedString(v.BitArray.String())
+
 	case *parser.DTuple:
 		b.variablePutbuf.WriteString("(")
 		for i, d := range v.D {
@@ -327,6 +340,26 @@ func (b *writeBuffer) writeBinaryDatum(d parser.Datum, sessionLoc *time.Location
 		b.putInt32(4)
 		b.putInt32(dateToPgBinary(v))
 
+	case *parser.DTime:
+		b.putInt32(8)
+		b.putInt64(int64(*v))
+
+	case *parser.DIPAddr:
+		b.putInt32(int32(4 + len(v.Addr)))
+		b.writeByte(ipFamilyToPgBinary(v.Family))
+		b.writeByte(v.Mask)
+		// We don't distinguish INET and CIDR values, so the is_cidr flag is
+		// always unset.
+		b.writeByte(0)
+		b.writeByte(byte(len(v.Addr)))
+		b.write(v.Addr)
+
+	case *parser.DBitArray:
+		data, bitLen := v.EncodingParts()
+		b.putInt32(int32(4 + len(data)))
+		b.putInt32(int32(bitLen))
+		b.write(data)
+
 	case *parser.DArray:
 		if v.ParamTyp.FamilyEqual(parser.TypeAnyArray) {
 			b.setError(errors.New("unsupported binary serialization of multidimensional arrays"))
@@ -418,6 +451,47 @@ func pgBinaryToTime(i int64) time.Time {
 // dateToPgBinary calculates the Postgres binary format for a date. The date is
 // represented as the number of days between the given date and Jan 1, 2000
 // (dubbed the pgEpochJDate), stored within an int32.
+// Postgres' address family identifiers, used in the binary encoding of INET
+// and CIDR values. See PGSQL_AF_INET in src/include/utils/inet.h.
+const (
+	pgAFInet  = 2
+	pgAFInet6 = 3
+)
+
+func ipFamilyToPgBinary(f ipaddr.IPFamily) byte {
+	if f == ipaddr.IPv4family {
+		return pgAFInet
+	}
+	return pgAFInet6
+}
+
+// pgBinaryToIPAddr decodes the Postgres binary encoding of an INET or CIDR
+// value: the address family, the mask length, the is_cidr flag, the number of
+// address bytes and the address itself.
+func pgBinaryToIPAddr(b []byte) (*parser.DIPAddr, error) {
+	if len(b) < 4 {
+		return nil, errors.Errorf("inet requires at least 4 bytes for binary format")
+	}
+	var ipAddr ipaddr.IPAddr
+	switch b[0] {
+	case pgAFInet:
+		ipAddr.Family = ipaddr.IPv4family
+	case pgAFInet6:
+		ipAddr.Family = ipaddr.IPv6family
+	default:
+		return nil, errors.Errorf("unknown inet address family: %d", b[0])
+	}
+	ipAddr.Mask = b[1]
+	if ipAddr.Mask > ipAddr.Family.MaxMask() {
+		return nil, errors.Errorf("invalid inet mask length: %d", ipAddr.Mask)
+	}
+	if n := int(b[3]); n != int(ipAddr.Family.MaxMask()/8) || len(b) != 4+n {
+		return nil, errors.Errorf("invalid inet address length: %d", b[3])
+	}
+	ipAddr.Addr = append(net.IP(nil), b[4:]...)
+	return parser.NewDIPAddr(parser.DIPAddr{IPAddr: ipAddr}), nil
+}
+
 func dateToPgBinary(d *parser.DDate) int32 {
 	return int32(*d) - pgEpochJDateFromUnix
 }
@@ -515,6 +589,30 @@ func decodeOidDatum(id oid.Oid, code formatCode, b []byte) (parser.Datum, error)
 				return nil, errors.Errorf("could not parse string %q as uuid", b)
 			}
 			return d, nil
+		case oid.T_time:
+			d, err := parser.ParseDTime(string(b))
+			if err != nil {
+				return nil, errors.Errorf("could not parse string %q as time", b)
+			}
+			return d, nil
+		case oid.T_inet:
+			d, err := parser.ParseDIPAddrFromINetString(string(b))
+			if err != nil {
+				return nil, errors.Errorf("could not parse string %q as inet", b)
+			}
+			return d, nil
+		case oid.T_cidr:
+			d, err := parser.ParseDIPAddrFromCIDRString(string(b))
+			if err != nil {
+				return nil, errors.Errorf("could not parse string %q as cidr", b)
+			}
+			return d, nil
+		case oid.T_bit, oid.T_varbit:
+			d, err := parser.ParseDBitArray(string(b))
+			if err != nil {
+				return nil, errors.Errorf("could not parse string %q as bit string", b)
+			}
+			return d, nil
 		case oid.T__int2, oid.T__int4, oid.T__int8:
 			var arr pq.Int64Array
 			if err := (&arr).Scan(b); err != nil {
@@ -695,6 +793,24 @@ func decodeOidDatum(id oid.Oid, code formatCode, b []byte) (parser.Datum, error)
 				return nil, err
 			}
 			return u, nil
+		case oid.T_time:
+			if len(b) < 8 {
+				return nil, errors.Errorf("time requires 8 bytes for binary format")
+			}
+			i := int64(binary.BigEndian.Uint64(b))
+			return parser.MakeDTime(timeofday.FromInt(i)), nil
+		case oid.T_inet, oid.T_cidr:
+			return pgBinaryToIPAddr(b)
+		case oid.T_bit, oid.T_varbit:
+			if len(b) < 4 {
+				return nil, errors.Errorf("bit string requires at least 4 bytes for binary format")
+			}
+			bitLen := binary.BigEndian.Uint32(b)
+			a, err := bitarray.FromEncodingParts(b[4:], uint(bitLen))
+			if err != nil {
+				return nil, err
+			}
+			return &parser.DBitArray{BitArray: a}, nil
 		case oid.T__int2, oid.T__int4, oid.T__int8, oid.T__text, oid.T__name:
 			return decodeBinaryArray(b, code)
 		}
@@ -34,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/mon"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/hba"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -67,7 +68,10 @@ const (
 	serverMsgBindComplete         serverMessageType = '2'
 	serverMsgCommandComplete      serverMessageType = 'C'
 	serverMsgCloseComplete        serverMessageType = '3'
+	serverMsgCopyData             serverMessageType = 'd'
+	serverMsgCopyDone             serverMessageType = 'c'
 	serverMsgCopyInResponse       serverMessageType = 'G'
+	serverMsgCopyOutResponse      serverMessageType = 'H'
 	serverMsgDataRow              serverMessageType = 'D'
 	serverMsgEmptyQuery           serverMessageType = 'I'
 	serverMsgErrorResponse        serverMessageType = 'E'
@@ -105,6 +109,9 @@ const (
 const (
 	authOK                int32 = 0
 	authCleartextPassword int32 = 3
+	authSASL              int32 = 10
+	authSASLContinue      int32 = 11
+	authSASLFinal         int32 = 12
 )
 
 // preparedStatementMeta is pgwire-specific metadata which is attached to each
@@ -186,6 +193,13 @@ type v3Conn struct {
 	// it gets extra data after an error happened during a COPY operation.
 	doNotSendReadyForQuery bool
 
+	// copyOut holds the state of a COPY TO STDOUT that is in progress.
+	copyOut copyOutState
+
+	// loginOptions holds the login restrictions of the user, looked up
+	// during authentication.
+	loginOptions sql.UserLoginOptions
+
 	metrics *ServerMetrics
 
 	sqlMemoryPool *mon.MemoryMonitor
@@ -273,8 +287,29 @@ var statusReportParams = map[string]string{
 // name, if different from the one given initially. Note: at this
 // point the sql.Session does not exist yet! If need exists to access the
 // database to look up authentication data, use the internal executor.
+//
+// The authentication method is chosen by the host-based authentication
+// configuration, if one is set. Connections without TLS are not
+// authenticated, so only the reject method applies to them. A returned
+// error must be sent to the client before closing the connection.
+//
+// The login restrictions of the user are enforced here too, except for the
+// connection limit, which is left to the server.
 func (c *v3Conn) handleAuthentication(ctx context.Context, insecure bool) error {
-	if tlsConn, ok := c.conn.(*tls.Conn); ok {
+	tlsConn, isTLS := c.conn.(*tls.Conn)
+	method, err := c.lookupAuthMethod(isTLS)
+	if err != nil {
+		return err
+	}
+
+	c.loginOptions, err = sql.GetUserLoginOptions(
+		ctx, c.executor, c.metrics.internalMemMetrics, c.sessionArgs.User,
+	)
+	if err != nil {
+		return err
+	}
+
+	if isTLS {
 		var authenticationHook security.UserAuthHook
 
 		// Check that the requested user exists and retrieve the hashed
@@ -283,35 +318,76 @@ func (c *v3Conn) handleAuthentication(ctx context.Context, insecure bool) error
 			ctx, c.executor, c.metrics.internalMemMetrics, c.sessionArgs.User,
 		)
 		if err != nil {
-			return c.sendError(err)
+			return err
 		}
 
 		tlsState := tlsConn.ConnectionState()
-		// If no certificates are provided, default to password
-		// authentication.
-		if len(tlsState.PeerCertificates) == 0 {
-			password, err := c.sendAuthPasswordRequest()
+		usePassword, useScram := false, false
+		switch method {
+		case hba.MethodCert:
+			if len(tlsState.PeerCertificates) == 0 {
+				return pgerror.NewErrorf(pgerror.CodeInvalidAuthorizationSpecificationError,
+					"a client certificate is required to connect as user %s", c.sessionArgs.User)
+			}
+		case hba.MethodPassword:
+			usePassword = true
+		case hba.MethodScramSHA256:
+			useScram = true
+		default:
+			// If no certificates are provided, default to password
+			// authentication.
+			usePassword = len(tlsState.PeerCertificates) == 0
+		}
+
+		var password string
+		if useScram {
+			authenticationHook = security.UserAuthScramHook(
+				insecure, hashedPassword, c.handleScramExchange,
+			)
+		} else if usePassword {
+			password, err = c.sendAuthPasswordRequest()
 			if err != nil {
-				return c.sendError(err)
+				return err
 			}
 			authenticationHook = security.UserAuthPasswordHook(
 				insecure, password, hashedPassword,
 			)
 		} else {
-			// Normalize the username contained in the certificate.
-			tlsState.PeerCertificates[0].Subject.CommonName = parser.Name(
-				tlsState.PeerCertificates[0].Subject.CommonName,
-			).Normalize()
+			// Replace the username contained in the certificate by the user
+			// it authenticates, according to the identity map.
+			tlsState.PeerCertificates[0].Subject.CommonName = certificateUser(
+				tlsState.PeerCertificates[0], c.sessionArgs.User,
+			)
 			var err error
 			authenticationHook, err = security.UserAuthCertHook(insecure, &tlsState)
 			if err != nil {
-				return c.sendError(err)
+				return err
 			}
 		}
 
 		if err := authenticationHook(c.sessionArgs.User, true /* public */); err != nil {
-			return c.sendError(err)
+			return err
+		}
+
+		// An expired password only prevents password authentication.
+		if validUntil := c.loginOptions.ValidUntil; (usePassword || useScram) &&
+			!validUntil.IsZero() && timeutil.Now().After(validUntil) {
+			return pgerror.NewErrorf(pgerror.CodeInvalidPasswordError,
+				"password of user %s has expired", c.sessionArgs.User)
 		}
+
+		if usePassword && !insecure && security.StoreScramVerifiers.Get() &&
+			!security.IsScramVerifier(hashedPassword) {
+			// The user logged in with a password stored as a bcrypt hash.
+			// Now that we know the password, store it as a SCRAM verifier
+			// so that the user can use SCRAM authentication.
+			c.upgradeHashedPassword(ctx, hashedPassword, password)
+		}
+	}
+
+	if c.loginOptions.NoLogin {
+		return pgerror.NewErrorf(pgerror.CodeInvalidAuthorizationSpecificationError,
+			"user %s is not permitted to log in", c.sessionArgs.User)
 	}
 
 	c.writeBuf.initMsg(serverMsgAuth)
@@ -324,6 +400,7 @@ func (c *v3Conn) setupSession(ctx context.Context, reserved mon.BoundAccount) er
 		ctx, c.sessionArgs, c.executor, c.conn.RemoteAddr(), &c.metrics.SQLMemMetrics,
 	)
 	c.session.StartMonitor(c.sqlMemoryPool, reserved)
+	c.session.CopyOut = c
 	return nil
 }
 
@@ -496,17 +573,108 @@ func (c *v3Conn) sendAuthPasswordRequest() (string, error) {
 		return "", err
 	}
 
+	if err := c.readPasswordMessage(); err != nil {
+		return "", err
+	}
+	return c.readBuf.getString()
+}
+
+// handleScramExchange runs a SCRAM-SHA-256 SASL exchange with the client.
+// See: https://www.postgresql.org/docs/current/static/sasl-authentication.html
+func (c *v3Conn) handleScramExchange(srv *security.ScramServer) error {
+	// Advertise the only supported mechanism.
+	c.writeBuf.initMsg(serverMsgAuth)
+	c.writeBuf.putInt32(authSASL)
+	c.writeBuf.writeTerminatedString(security.ScramMechanism)
+	c.writeBuf.nullTerminate()
+	if err := c.writeBuf.finishMsg(c.wr); err != nil {
+		return err
+	}
+	if err := c.wr.Flush(); err != nil {
+		return err
+	}
+
+	// The SASLInitialResponse message contains the selected mechanism and
+	// the client-first-message.
+	if err := c.readPasswordMessage(); err != nil {
+		return err
+	}
+	mechanism, err := c.readBuf.getString()
+	if err != nil {
+		return err
+	}
+	if mechanism != security.ScramMechanism {
+		return errors.Errorf("unsupported SASL mechanism: %q", mechanism)
+	}
+	n, err := c.readBuf.getUint32()
+	if err != nil {
+		return err
+	}
+	if int32(n) < 0 {
+		return errors.New("missing SASL initial response")
+	}
+	clientFirst, err := c.readBuf.getBytes(int(n))
+	if err != nil {
+		return err
+	}
+	serverFirst, err := srv.ClientFirst(clientFirst)
+	if err != nil {
+		return err
+	}
+	c.writeBuf.initMsg(serverMsgAuth)
+	c.writeBuf.putInt32(authSASLContinue)
+	c.writeBuf.write(serverFirst)
+	if err := c.writeBuf.finishMsg(c.wr); err != nil {
+		return err
+	}
+	if err := c.wr.Flush(); err != nil {
+		return err
+	}
+
+	// The SASLResponse message contains the client-final-message.
+	if err := c.readPasswordMessage(); err != nil {
+		return err
+	}
+	serverFinal, err := srv.ClientFinal(c.readBuf.msg)
+	if err != nil {
+		return err
+	}
+	c.writeBuf.initMsg(serverMsgAuth)
+	c.writeBuf.putInt32(authSASLFinal)
+	c.writeBuf.write(serverFinal)
+	return c.writeBuf.finishMsg(c.wr)
+}
+
+// readPasswordMessage reads a response to an authentication request into
+// c.readBuf. PasswordMessage, SASLInitialResponse and SASLResponse messages
+// all share the same message type.
+func (c *v3Conn) readPasswordMessage() error {
 	typ, n, err := c.readBuf.readTypedMsg(c.rd)
 	c.metrics.BytesInCount.Inc(int64(n))
 	if err != nil {
-		return "", err
+		return err
 	}
-
 	if typ != clientMsgPassword {
-		return "", errors.Errorf("invalid response to authentication request: %s", typ)
+		return errors.Errorf("invalid response to authentication request: %s", typ)
 	}
+	return nil
+}
 
-	return c.readBuf.getString()
+// upgradeHashedPassword replaces a legacy hashed password with a SCRAM
+// verifier of the same password. Failures are only logged: the user is
+// already authenticated and the upgrade is attempted again at the next
+// login.
+func (c *v3Conn) upgradeHashedPassword(ctx context.Context, oldHashedPassword []byte, password string) {
+	newHashedPassword, err := security.HashPassword(password)
+	if err == nil {
+		err = sql.UpgradeUserHashedPassword(
+			ctx, c.executor, c.metrics.internalMemMetrics, c.sessionArgs.User,
+			oldHashedPassword, newHashedPassword,
+		)
+	}
+	if err != nil {
+		log.Warningf(ctx, "unable to upgrade the password hash of user %s: %v", c.sessionArgs.User, err)
+	}
 }
 
 func (c *v3Conn) handleSimpleQuery(buf *readBuffer) error {
@@ -516,6 +684,7 @@ func (c *v3Conn) handleSimpleQuery(buf *readBuffer) error {
 	}
 
 	tracing.AnnotateTrace()
+	c.copyOut.active = false
 	results := c.executor.ExecuteStatements(c.session, query, nil)
 	return c.finishExecute(results, nil, true, 0)
 }
@@ -838,6 +1007,7 @@ func (c *v3Conn) handleExecute(buf *readBuffer) error {
 
 	tracing.AnnotateTrace()
 
+	c.copyOut.active = false
 	results, err := c.executor.ExecutePreparedStatement(c.session, stmt, pinfo)
 	if err != nil {
 		return c.sendError(err)
@@ -1040,6 +1210,19 @@ func (c *v3Conn) sendResponse(
 				return err
 			}
 
+		case parser.CopyOut:
+			// The rows were already sent while the statement executed.
+			if err := c.sendCopyDone(); err != nil {
+				return err
+			}
+
+			// Send CommandComplete.
+			tag = append(tag, ' ')
+			tag = strconv.AppendInt(tag, int64(result.RowsAffected), 10)
+			if err := c.sendCommandComplete(tag); err != nil {
+				return err
+			}
+
 		default:
 			panic(fmt.Sprintf("unexpected result type %v", result.Type))
 		}
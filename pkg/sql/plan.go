@@ -147,7 +147,9 @@ type planNodeFastPath interface {
 }
 
 var _ planNode = &alterTableNode{}
+var _ planNode = &applyJoinNode{}
 var _ planNode = &copyNode{}
+var _ planNode = &copyToNode{}
 var _ planNode = &createDatabaseNode{}
 var _ planNode = &createIndexNode{}
 var _ planNode = &createTableNode{}
@@ -185,8 +187,15 @@ var _ planNode = &updateNode{}
 var _ planNode = &valueGenerator{}
 var _ planNode = &valuesNode{}
 var _ planNode = &windowNode{}
+var _ planNode = &alterUserNode{}
+var _ planNode = &createPolicyNode{}
+var _ planNode = &createRoleNode{}
 var _ planNode = &createUserNode{}
+var _ planNode = &dropPolicyNode{}
+var _ planNode = &dropRoleNode{}
 var _ planNode = &dropUserNode{}
+var _ planNode = &grantRoleNode{}
+var _ planNode = &revokeRoleNode{}
 
 var _ planNodeFastPath = &deleteNode{}
 var _ planNodeFastPath = &dropUserNode{}
@@ -274,6 +283,8 @@ func (p *planner) newPlan(
 	switch n := stmt.(type) {
 	case *parser.AlterTable:
 		return p.AlterTable(ctx, n)
+	case *parser.AlterUser:
+		return p.AlterUser(ctx, n)
 	case *parser.BeginTransaction:
 		return p.BeginTransaction(n)
 	case *parser.CancelQuery:
@@ -284,10 +295,16 @@ func (p *planner) newPlan(
 		return p.CopyData(ctx, n)
 	case *parser.CopyFrom:
 		return p.CopyFrom(ctx, n)
+	case *parser.CopyTo:
+		return p.CopyTo(ctx, n)
 	case *parser.CreateDatabase:
 		return p.CreateDatabase(n)
 	case *parser.CreateIndex:
 		return p.CreateIndex(ctx, n)
+	case *parser.CreatePolicy:
+		return p.CreatePolicy(ctx, n)
+	case *parser.CreateRole:
+		return p.CreateRole(ctx, n)
 	case *parser.CreateTable:
 		return p.CreateTable(ctx, n)
 	case *parser.CreateUser:
@@ -304,12 +321,20 @@ func (p *planner) newPlan(
 		return p.DropTable(ctx, n)
 	case *parser.DropView:
 		return p.DropView(ctx, n)
+	case *parser.DropPolicy:
+		return p.DropPolicy(ctx, n)
+	case *parser.DropRole:
+		return p.DropRole(ctx, n)
 	case *parser.DropUser:
 		return p.DropUser(ctx, n)
+	case *parser.DropSchedule:
+		return p.DropSchedule(ctx, n)
 	case *parser.Explain:
 		return p.Explain(ctx, n)
 	case *parser.Grant:
 		return p.Grant(ctx, n)
+	case *parser.GrantRole:
+		return p.GrantRole(ctx, n)
 	case *parser.Help:
 		return p.Help(ctx, n)
 	case *parser.Insert:
@@ -318,6 +343,8 @@ func (p *planner) newPlan(
 		return p.newPlan(ctx, n.Select, desiredTypes)
 	case *parser.PauseJob:
 		return p.PauseJob(ctx, n)
+	case *parser.PauseSchedule:
+		return p.PauseSchedule(ctx, n)
 	case *parser.Relocate:
 		return p.Relocate(ctx, n)
 	case *parser.RenameColumn:
@@ -330,8 +357,12 @@ func (p *planner) newPlan(
 		return p.RenameTable(ctx, n)
 	case *parser.ResumeJob:
 		return p.ResumeJob(ctx, n)
+	case *parser.ResumeSchedule:
+		return p.ResumeSchedule(ctx, n)
 	case *parser.Revoke:
 		return p.Revoke(ctx, n)
+	case *parser.RevokeRole:
+		return p.RevokeRole(ctx, n)
 	case *parser.Scatter:
 		return p.Scatter(ctx, n)
 	case *parser.Select:
@@ -362,6 +393,8 @@ func (p *planner) newPlan(
 		return p.ShowIndex(ctx, n)
 	case *parser.ShowQueries:
 		return p.ShowQueries(ctx, n)
+	case *parser.ShowSchedules:
+		return p.ShowSchedules(ctx, n)
 	case *parser.ShowSessions:
 		return p.ShowSessions(ctx, n)
 	case *parser.ShowTables:
@@ -372,6 +405,8 @@ func (p *planner) newPlan(
 		return p.ShowTransactionStatus()
 	case *parser.ShowUsers:
 		return p.ShowUsers(ctx, n)
+	case *parser.ShowRoles:
+		return p.ShowRoles(ctx, n)
 	case *parser.ShowRanges:
 		return p.ShowRanges(ctx, n)
 	case *parser.ShowFingerprints:
@@ -431,6 +466,8 @@ func (p *planner) prepare(ctx context.Context, stmt parser.Statement) (planNode,
 		return p.ShowConstraints(ctx, n)
 	case *parser.ShowQueries:
 		return p.ShowQueries(ctx, n)
+	case *parser.ShowSchedules:
+		return p.ShowSchedules(ctx, n)
 	case *parser.ShowSessions:
 		return p.ShowSessions(ctx, n)
 	case *parser.ShowTables:
@@ -439,6 +476,8 @@ func (p *planner) prepare(ctx context.Context, stmt parser.Statement) (planNode,
 		return p.ShowTrace(ctx, n)
 	case *parser.ShowUsers:
 		return p.ShowUsers(ctx, n)
+	case *parser.ShowRoles:
+		return p.ShowRoles(ctx, n)
 	case *parser.ShowTransactionStatus:
 		return p.ShowTransactionStatus()
 	case *parser.ShowRanges:
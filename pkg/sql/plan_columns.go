@@ -60,6 +60,8 @@ func getPlanColumns(plan planNode, mut bool) sqlbase.ResultColumns {
 		return n.header
 	case *joinNode:
 		return n.columns
+	case *applyJoinNode:
+		return n.columns
 	case *ordinalityNode:
 		return n.columns
 	case *renderNode:
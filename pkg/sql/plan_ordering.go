@@ -46,6 +46,8 @@ func planOrdering(plan planNode) orderingInfo {
 		// appropriately.
 	case *joinNode:
 		// TODO(knz): this can be ordered when not using hash join.
+	case *applyJoinNode:
+		// TODO(knz): this is ordered like the left operand.
 	case *unionNode:
 		// TODO(knz): this can be ordered if the source is ordered already.
 	case *insertNode:
@@ -81,6 +81,8 @@ func collectSpans(ctx context.Context, plan planNode) (reads, writes roachpb.Spa
 		return indexJoinSpans(ctx, n)
 	case *joinNode:
 		return concatSpans(ctx, n.left.plan, n.right.plan)
+	case *applyJoinNode:
+		return concatSpans(ctx, n.left.plan, n.right.plan)
 	case *unionNode:
 		return concatSpans(ctx, n.left, n.right)
 	}
@@ -19,6 +19,7 @@ package sql
 import (
 	"golang.org/x/net/context"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 )
@@ -43,6 +44,9 @@ var planHooks []planHookFn
 // the hooks that implement it.
 type PlanHookState interface {
 	ExecCfg() *ExecutorConfig
+	EvalContext() parser.EvalContext
+	DistLoader() *DistLoader
+	ExportCSV(ctx context.Context, query *parser.Select, spec distsqlrun.CSVWriterSpec) ([]parser.Datums, error)
 	LeaseMgr() *LeaseManager
 	TypeAsString(e parser.Expr, op string) (func() (string, error), error)
 	TypeAsStringArray(e parser.Exprs, op string) (func() ([]string, error), error)
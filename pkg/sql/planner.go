@@ -70,6 +70,16 @@ type planner struct {
 	// See executor_statement_metrics.go for details.
 	phaseTimes phaseTimes
 
+	// auditEvents accumulates the accesses to audited tables by the
+	// statement being planned. See maybeAudit.
+	auditEvents []auditEvent
+
+	// outerScopes is the stack of data sources whose columns can be
+	// referenced by the expressions currently being planned, in
+	// addition to their own data sources. The innermost scope is last.
+	// See applyJoinNode.
+	outerScopes []*outerScope
+
 	// Avoid allocations by embedding commonly used objects and visitors.
 	parser                parser.Parser
 	subqueryVisitor       subqueryVisitor
@@ -143,6 +153,16 @@ func (p *planner) ExecCfg() *ExecutorConfig {
 	return p.session.execCfg
 }
 
+// EvalContext implements the PlanHookState interface.
+func (p *planner) EvalContext() parser.EvalContext {
+	return p.evalCtx
+}
+
+// DistLoader implements the PlanHookState interface.
+func (p *planner) DistLoader() *DistLoader {
+	return &DistLoader{distSQLPlanner: p.session.distSQLPlanner}
+}
+
 func (p *planner) LeaseMgr() *LeaseManager {
 	return p.session.tables.leaseMgr
 }
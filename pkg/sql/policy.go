@@ -0,0 +1,423 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// Row level security restricts the rows of a table that users can access to
+// those allowed by the policies of the table. A policy applies to a kind of
+// statement (or to all of them) and to some users and roles (or to all of
+// them), and is made of two expressions:
+//
+// - the USING expression selects the existing rows that can be read,
+//   updated or deleted;
+// - the WITH CHECK expression must hold for the rows inserted or updated.
+//   The USING expression is used instead if there is none.
+//
+// The policies which apply to a statement are combined with OR. If row level
+// security is enabled on a table and no policy applies, no row can be
+// accessed. Reading a row always requires a SELECT (or ALL) policy; updating
+// or deleting it also requires an UPDATE or DELETE policy.
+//
+// root and the users holding the ALL privilege on a table bypass its row
+// level security.
+
+type createPolicyNode struct {
+	p         *planner
+	n         *parser.CreatePolicy
+	tableDesc *sqlbase.TableDescriptor
+}
+
+// CreatePolicy adds a row level security policy to a table.
+// Privileges: CREATE on table.
+//   Notes: postgres requires the user to own the table.
+func (p *planner) CreatePolicy(ctx context.Context, n *parser.CreatePolicy) (planNode, error) {
+	tableDesc, err := p.getPolicyTableDesc(ctx, n.Table)
+	if err != nil {
+		return nil, err
+	}
+	return &createPolicyNode{p: p, n: n, tableDesc: tableDesc}, nil
+}
+
+// getPolicyTableDesc returns the descriptor of the table whose policies are
+// changed, after checking that the user may change them.
+func (p *planner) getPolicyTableDesc(
+	ctx context.Context, table parser.NormalizableTableName,
+) (*sqlbase.TableDescriptor, error) {
+	tn, err := table.NormalizeWithDatabaseName(p.session.Database)
+	if err != nil {
+		return nil, err
+	}
+	tableDesc, err := getTableDesc(ctx, p.txn, p.getVirtualTabler(), tn)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc == nil {
+		return nil, sqlbase.NewUndefinedTableError(tn.String())
+	}
+	if err := p.CheckPrivilege(tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	return tableDesc, nil
+}
+
+func (n *createPolicyNode) Start(ctx context.Context) error {
+	name := string(n.n.Name)
+	if _, ok := findPolicy(n.tableDesc, name); ok {
+		return pgerror.NewErrorf(pgerror.CodeDuplicateObjectError,
+			"policy %q for table %q already exists", name, n.tableDesc.Name)
+	}
+	policy := sqlbase.TableDescriptor_Policy{Name: name}
+	switch n.n.Command {
+	case parser.PolicyAll:
+		policy.Command = sqlbase.TableDescriptor_Policy_ALL
+	case parser.PolicySelect:
+		policy.Command = sqlbase.TableDescriptor_Policy_SELECT
+	case parser.PolicyInsert:
+		policy.Command = sqlbase.TableDescriptor_Policy_INSERT
+	case parser.PolicyUpdate:
+		policy.Command = sqlbase.TableDescriptor_Policy_UPDATE
+	case parser.PolicyDelete:
+		policy.Command = sqlbase.TableDescriptor_Policy_DELETE
+	default:
+		return errors.Errorf("unknown policy command: %d", n.n.Command)
+	}
+
+	if n.n.Using != nil {
+		if policy.Command == sqlbase.TableDescriptor_Policy_INSERT {
+			return pgerror.NewErrorf(pgerror.CodeSyntaxError,
+				"only WITH CHECK expression allowed for INSERT")
+		}
+		if err := validatePolicyExpr(n.tableDesc, n.n.Using, n.p.session.SearchPath); err != nil {
+			return err
+		}
+		policy.UsingExpr = parser.Serialize(n.n.Using)
+	}
+	if n.n.WithCheck != nil {
+		if policy.Command == sqlbase.TableDescriptor_Policy_SELECT ||
+			policy.Command == sqlbase.TableDescriptor_Policy_DELETE {
+			return pgerror.NewErrorf(pgerror.CodeSyntaxError,
+				"WITH CHECK cannot be applied to SELECT or DELETE")
+		}
+		if err := validatePolicyExpr(n.tableDesc, n.n.WithCheck, n.p.session.SearchPath); err != nil {
+			return err
+		}
+		policy.WithCheckExpr = parser.Serialize(n.n.WithCheck)
+	}
+
+	internalExecutor := InternalExecutor{LeaseManager: n.p.LeaseMgr()}
+	for _, roleName := range n.n.Roles {
+		role, err := NormalizeAndValidateUsername(string(roleName))
+		if err != nil {
+			return err
+		}
+		if role == "public" {
+			// The policy applies to all users.
+			policy.Roles = nil
+			break
+		}
+		isUser, err := userExists(ctx, internalExecutor, n.p.txn, role)
+		if err != nil {
+			return err
+		}
+		if !isUser {
+			if isRole, err := roleExists(ctx, internalExecutor, n.p.txn, role); err != nil {
+				return err
+			} else if !isRole {
+				return errors.Errorf("user or role %s does not exist", role)
+			}
+		}
+		policy.Roles = append(policy.Roles, role)
+	}
+
+	n.tableDesc.Policies = append(n.tableDesc.Policies, policy)
+	return n.p.writePolicyChange(ctx, n.tableDesc, n.n)
+}
+
+func (*createPolicyNode) Next(context.Context) (bool, error) { return false, nil }
+func (*createPolicyNode) Close(context.Context)              {}
+
+func (*createPolicyNode) Values() parser.Datums      { return parser.Datums{} }
+func (*createPolicyNode) DebugValues() debugValues   { return debugValues{} }
+func (*createPolicyNode) MarkDebug(mode explainMode) {}
+
+type dropPolicyNode struct {
+	p         *planner
+	n         *parser.DropPolicy
+	tableDesc *sqlbase.TableDescriptor
+}
+
+// DropPolicy removes a row level security policy from a table.
+// Privileges: CREATE on table.
+//   Notes: postgres requires the user to own the table.
+func (p *planner) DropPolicy(ctx context.Context, n *parser.DropPolicy) (planNode, error) {
+	tableDesc, err := p.getPolicyTableDesc(ctx, n.Table)
+	if err != nil {
+		return nil, err
+	}
+	return &dropPolicyNode{p: p, n: n, tableDesc: tableDesc}, nil
+}
+
+func (n *dropPolicyNode) Start(ctx context.Context) error {
+	idx, ok := findPolicy(n.tableDesc, string(n.n.Name))
+	if !ok {
+		if n.n.IfExists {
+			return nil
+		}
+		return pgerror.NewErrorf(pgerror.CodeUndefinedObjectError,
+			"policy %q for table %q does not exist", string(n.n.Name), n.tableDesc.Name)
+	}
+	n.tableDesc.Policies = append(n.tableDesc.Policies[:idx], n.tableDesc.Policies[idx+1:]...)
+	return n.p.writePolicyChange(ctx, n.tableDesc, n.n)
+}
+
+func (*dropPolicyNode) Next(context.Context) (bool, error) { return false, nil }
+func (*dropPolicyNode) Close(context.Context)              {}
+
+func (*dropPolicyNode) Values() parser.Datums      { return parser.Datums{} }
+func (*dropPolicyNode) DebugValues() debugValues   { return debugValues{} }
+func (*dropPolicyNode) MarkDebug(mode explainMode) {}
+
+// findPolicy returns the index of the policy of a table with the given name.
+func findPolicy(desc *sqlbase.TableDescriptor, name string) (int, bool) {
+	for i := range desc.Policies {
+		if desc.Policies[i].Name == name {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// writePolicyChange writes a table descriptor whose policies were changed by
+// a statement.
+func (p *planner) writePolicyChange(
+	ctx context.Context, tableDesc *sqlbase.TableDescriptor, stmt parser.Statement,
+) error {
+	if err := tableDesc.SetUpVersion(); err != nil {
+		return err
+	}
+	if err := tableDesc.Validate(ctx, p.txn); err != nil {
+		return err
+	}
+	if err := p.writeTableDesc(ctx, tableDesc); err != nil {
+		return err
+	}
+
+	// Record this table alteration in the event log. This is an auditable log
+	// event and is recorded in the same transaction as the table descriptor
+	// update.
+	if err := MakeEventLogger(p.LeaseMgr()).InsertEventRecord(
+		ctx,
+		p.txn,
+		EventLogAlterTable,
+		int32(tableDesc.ID),
+		int32(p.evalCtx.NodeID),
+		struct {
+			TableName string
+			Statement string
+			User      string
+		}{tableDesc.Name, stmt.String(), p.session.User},
+	); err != nil {
+		return err
+	}
+
+	p.notifySchemaChange(tableDesc, sqlbase.InvalidMutationID)
+	return nil
+}
+
+// validatePolicyExpr checks that a policy expression is a boolean expression
+// over the columns of the table. Subqueries are not supported.
+func validatePolicyExpr(
+	desc *sqlbase.TableDescriptor, expr parser.Expr, searchPath parser.SearchPath,
+) error {
+	preFn := func(expr parser.Expr) (err error, recurse bool, newExpr parser.Expr) {
+		switch t := expr.(type) {
+		case *parser.Subquery:
+			return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"subqueries are not supported in policy expressions"), false, nil
+
+		case parser.VarName:
+			v, err := t.NormalizeVarName()
+			if err != nil {
+				return err, false, nil
+			}
+			c, ok := v.(*parser.ColumnItem)
+			if !ok {
+				return nil, true, expr
+			}
+			col, err := desc.FindActiveColumnByName(c.ColumnName)
+			if err != nil {
+				return err, false, nil
+			}
+			// Convert to a dummy node of the correct type.
+			return nil, false, dummyColumnItem{col.Type.ToDatumType()}
+		}
+		return nil, true, expr
+	}
+
+	expr, err := parser.SimpleVisit(expr, preFn)
+	if err != nil {
+		return err
+	}
+	var p parser.Parser
+	if err := p.AssertNoAggregationOrWindowing(expr, "policy expressions", searchPath); err != nil {
+		return err
+	}
+	_, err = sqlbase.SanitizeVarFreeExpr(expr, parser.TypeBool, "POLICY", searchPath)
+	return err
+}
+
+// checkColumnNotInPolicies returns an error if a column is referenced by a
+// policy of the table.
+func checkColumnNotInPolicies(desc *sqlbase.TableDescriptor, col sqlbase.ColumnDescriptor) error {
+	for _, policy := range desc.Policies {
+		for _, exprStr := range [...]string{policy.UsingExpr, policy.WithCheckExpr} {
+			if exprStr == "" {
+				continue
+			}
+			ids, err := desc.IndexExprSourceColumnIDs(exprStr)
+			if err != nil {
+				return err
+			}
+			for _, id := range ids {
+				if id == col.ID {
+					return fmt.Errorf("column %q is referenced by policy %q", col.Name, policy.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// rowLevelSecurityExpr returns the expression selecting the rows of a table
+// the user may access with a kind of statement, or nil if the user is not
+// restricted by row level security. If withCheck is set, the expression is
+// the one new rows must satisfy.
+func (p *planner) rowLevelSecurityExpr(
+	ctx context.Context,
+	desc *sqlbase.TableDescriptor,
+	cmd sqlbase.TableDescriptor_Policy_Command,
+	withCheck bool,
+) (parser.Expr, error) {
+	if !desc.RowLevelSecurity ||
+		p.session.User == security.RootUser || p.session.User == security.NodeUser {
+		return nil, nil
+	}
+	privs := desc.GetPrivileges()
+	if privs.CheckPrivilege(p.session.User, privilege.ALL) {
+		return nil, nil
+	}
+	memberOf, err := p.MemberOf(ctx, p.session.User)
+	if err != nil {
+		return nil, err
+	}
+	for role := range memberOf {
+		if privs.CheckPrivilege(role, privilege.ALL) {
+			return nil, nil
+		}
+	}
+
+	var expr parser.Expr
+	for _, policy := range desc.Policies {
+		if policy.Command != sqlbase.TableDescriptor_Policy_ALL && policy.Command != cmd {
+			continue
+		}
+		applies := len(policy.Roles) == 0
+		for _, role := range policy.Roles {
+			if _, ok := memberOf[role]; ok || role == p.session.User {
+				applies = true
+				break
+			}
+		}
+		if !applies {
+			continue
+		}
+
+		exprStr := policy.UsingExpr
+		if withCheck && policy.WithCheckExpr != "" {
+			exprStr = policy.WithCheckExpr
+		}
+		var policyExpr parser.Expr = parser.DBoolTrue
+		if exprStr != "" {
+			if policyExpr, err = parser.ParseExpr(exprStr); err != nil {
+				return nil, err
+			}
+		}
+		if expr == nil {
+			expr = policyExpr
+		} else {
+			expr = &parser.OrExpr{Left: expr, Right: policyExpr}
+		}
+	}
+	if expr == nil {
+		// No policy allows the user to access any row.
+		return parser.DBoolFalse, nil
+	}
+	return expr, nil
+}
+
+// initRowLevelSecurity restricts a scan to the rows the user may read.
+func (n *scanNode) initRowLevelSecurity(ctx context.Context, p *planner) error {
+	expr, err := p.rowLevelSecurityExpr(ctx, &n.desc, sqlbase.TableDescriptor_Policy_SELECT, false)
+	if err != nil || expr == nil {
+		return err
+	}
+	// The policies may refer to columns the user cannot read, so they are
+	// resolved against all the columns of the scan.
+	sourceInfo := newSourceInfoForSingleTable(
+		parser.TableName{TableName: parser.Name(n.desc.Name)},
+		sqlbase.ResultColumnsFromColDescs(n.cols),
+	)
+	filter, err := p.analyzeExpr(
+		ctx, expr, multiSourceInfo{sourceInfo}, n.filterVars, parser.TypeBool, true, "POLICY",
+	)
+	if err != nil {
+		return err
+	}
+	n.filter = filter
+	return nil
+}
+
+// rowLevelSecurityWhere restricts the WHERE clause of an UPDATE or DELETE
+// statement to the rows the user may modify.
+func (p *planner) rowLevelSecurityWhere(
+	ctx context.Context,
+	desc *sqlbase.TableDescriptor,
+	cmd sqlbase.TableDescriptor_Policy_Command,
+	where *parser.Where,
+) (*parser.Where, error) {
+	expr, err := p.rowLevelSecurityExpr(ctx, desc, cmd, false)
+	if err != nil || expr == nil {
+		return where, err
+	}
+	if where != nil {
+		expr = &parser.AndExpr{
+			Left:  &parser.ParenExpr{Expr: where.Expr},
+			Right: &parser.ParenExpr{Expr: expr},
+		}
+	}
+	return &parser.Where{Expr: expr}, nil
+}
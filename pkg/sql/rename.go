@@ -415,6 +415,62 @@ func (p *planner) RenameColumn(ctx context.Context, n *parser.RenameColumn) (pla
 			tableDesc.Checks[i].Expr = after
 		}
 	}
+	// Rename the column in the expressions of expression indexes and in the
+	// predicates of partial indexes.
+	renameInExpr := func(exprStr string) (string, error) {
+		expr, err := parser.ParseExpr(exprStr)
+		if err != nil {
+			return "", err
+		}
+		if expr, err = parser.SimpleVisit(expr, preFn); err != nil {
+			return "", err
+		}
+		return expr.String(), nil
+	}
+	renameInIndexExprs := func(index *sqlbase.IndexDescriptor) error {
+		for i, exprStr := range index.ColumnExprs {
+			if exprStr == "" {
+				continue
+			}
+			var err error
+			if index.ColumnExprs[i], err = renameInExpr(exprStr); err != nil {
+				return err
+			}
+		}
+		if index.IsPartial() {
+			var err error
+			if index.Predicate, err = renameInExpr(index.Predicate); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for i := range tableDesc.Indexes {
+		if err := renameInIndexExprs(&tableDesc.Indexes[i]); err != nil {
+			return nil, err
+		}
+	}
+	for _, m := range tableDesc.Mutations {
+		if index := m.GetIndex(); index != nil {
+			if err := renameInIndexExprs(index); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// Rename the column in the expressions of row level security policies.
+	for i := range tableDesc.Policies {
+		policy := &tableDesc.Policies[i]
+		if policy.UsingExpr != "" {
+			if policy.UsingExpr, err = renameInExpr(policy.UsingExpr); err != nil {
+				return nil, err
+			}
+		}
+		if policy.WithCheckExpr != "" {
+			if policy.WithCheckExpr, err = renameInExpr(policy.WithCheckExpr); err != nil {
+				return nil, err
+			}
+		}
+	}
 	// Rename the column in the indexes.
 	tableDesc.RenameColumnDescriptor(col, normNewColName)
 
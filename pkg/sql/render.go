@@ -240,9 +240,13 @@ func (p *planner) SelectClause(
 		return nil, err
 	}
 
+	parsed, err := r.decorrelateSubqueries(ctx, parsed)
+	if err != nil {
+		return nil, err
+	}
+
 	var where *filterNode
 	if parsed.Where != nil {
-		var err error
 		where, err = r.initWhere(ctx, parsed.Where.Expr)
 		if err != nil {
 			return nil, err
@@ -378,8 +382,9 @@ func (r *renderNode) initTargets(
 // expression with an IndexedVar that points at a new index at the end of the
 // ivarHelper. The extracted SRF is retained in the srf field.
 //
-// This visitor is intentionally limited to extracting only one SRF, because we
-// don't support lateral correlated subqueries.
+// This visitor is intentionally limited to extracting only one SRF per
+// expression: nested SRFs would require evaluating an SRF for every
+// row of another, which we do not support in render position.
 type srfExtractionVisitor struct {
 	err        error
 	srf        *parser.FuncExpr
@@ -420,9 +425,9 @@ func (v *srfExtractionVisitor) VisitPost(expr parser.Expr) parser.Expr {
 // the set-returning function replaced by an IndexedVar that points at the new
 // data source.
 //
-// Expressions with more than one SRF require lateral correlated subqueries,
-// which are not yet supported. For now, this function returns an error if more
-// than one SRF is present in the render expression.
+// Expressions with more than one SRF are not yet supported. For now, this
+// function returns an error if more than one SRF is present in the render
+// expression.
 func (r *renderNode) rewriteSRFs(
 	ctx context.Context, target parser.SelectExpr,
 ) (parser.SelectExpr, error) {
@@ -446,12 +451,9 @@ func (r *renderNode) rewriteSRFs(
 	}
 
 	// We rewrote exactly one SRF; cross-join it with our sources and return the
-	// new render expression.
-	src, err := r.planner.getDataSource(ctx, v.srf, nil, publicColumns)
-	if err != nil {
-		return target, err
-	}
-	src, err = r.planner.makeJoin(ctx, "CROSS JOIN", r.source, src, nil)
+	// new render expression. The SRF arguments can refer to the columns
+	// of our sources.
+	src, err := r.planner.joinSource(ctx, "CROSS JOIN", r.source, v.srf, nil, publicColumns)
 	if err != nil {
 		return target, err
 	}
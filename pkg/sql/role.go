@@ -0,0 +1,432 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// Roles are stored in system.roles, separately from the users in
+// system.users: a role cannot log in, but privileges can be granted to it
+// like to any user. The members of a role, users or other roles, are stored
+// in system.role_members and inherit all the privileges of the role.
+
+// normalizeRoleName case folds the specified role name and verifies that it
+// is a valid name for a role. Roles and users share the same namespace.
+func normalizeRoleName(name parser.Name) (string, error) {
+	if name == "" {
+		return "", errors.New("no role name specified")
+	}
+	normalized, err := NormalizeAndValidateUsername(string(name))
+	if err != nil {
+		return "", err
+	}
+	if normalized == security.RootUser {
+		return "", errors.Errorf("role name %q reserved", normalized)
+	}
+	return normalized, nil
+}
+
+// roleExists returns true if system.roles contains the given role.
+func roleExists(
+	ctx context.Context, ie InternalExecutor, txn *client.Txn, role string,
+) (bool, error) {
+	row, err := ie.QueryRowInTransaction(
+		ctx, "check-role", txn, `SELECT name FROM system.roles WHERE name = $1`, role,
+	)
+	return row != nil, err
+}
+
+// userExists returns true if system.users contains the given user.
+func userExists(
+	ctx context.Context, ie InternalExecutor, txn *client.Txn, user string,
+) (bool, error) {
+	row, err := ie.QueryRowInTransaction(
+		ctx, "check-user", txn, `SELECT username FROM system.users WHERE username = $1`, user,
+	)
+	return row != nil, err
+}
+
+// bumpRoleMembersVersion increments the version of the system.role_members
+// table descriptor. The new version reaches all the nodes through the system
+// config, and invalidates their cached role memberships.
+func (p *planner) bumpRoleMembersVersion(ctx context.Context) error {
+	tableDesc, err := sqlbase.GetTableDescFromID(ctx, p.txn, keys.RoleMembersTableID)
+	if err != nil {
+		return err
+	}
+	tableDesc.Version++
+	if err := p.writeTableDesc(ctx, tableDesc); err != nil {
+		return err
+	}
+	// Don't wait for the system config to invalidate the memberships cached
+	// on this node.
+	if cache := p.session.roleMembers; cache != nil {
+		cache.Lock()
+		cache.tableVersion = 0
+		cache.userCache = nil
+		cache.Unlock()
+	}
+	return nil
+}
+
+type createRoleNode struct {
+	p *planner
+	n *parser.CreateRole
+}
+
+// CreateRole creates a role.
+// Privileges: INSERT on system.roles.
+func (p *planner) CreateRole(ctx context.Context, n *parser.CreateRole) (planNode, error) {
+	tDesc, err := getTableDesc(ctx, p.txn, p.getVirtualTabler(), &parser.TableName{DatabaseName: "system", TableName: "roles"})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(tDesc, privilege.INSERT); err != nil {
+		return nil, err
+	}
+
+	return &createRoleNode{p: p, n: n}, nil
+}
+
+func (n *createRoleNode) Start(ctx context.Context) error {
+	normalizedName, err := normalizeRoleName(n.n.Name)
+	if err != nil {
+		return err
+	}
+
+	internalExecutor := InternalExecutor{LeaseManager: n.p.LeaseMgr()}
+	if exists, err := userExists(ctx, internalExecutor, n.p.txn, normalizedName); err != nil {
+		return err
+	} else if exists {
+		return errors.Errorf("a user named %s already exists", normalizedName)
+	}
+
+	rowsAffected, err := internalExecutor.ExecuteStatementInTransaction(
+		ctx,
+		"create-role",
+		n.p.txn,
+		"INSERT INTO system.roles VALUES ($1);",
+		normalizedName,
+	)
+	if err != nil {
+		if sqlbase.IsUniquenessConstraintViolationError(err) {
+			err = errors.Errorf("role %s already exists", normalizedName)
+		}
+		return err
+	} else if rowsAffected != 1 {
+		return errors.Errorf(
+			"%d rows affected by role creation; expected exactly one row affected", rowsAffected,
+		)
+	}
+
+	return nil
+}
+
+func (*createRoleNode) Next(context.Context) (bool, error) { return false, nil }
+func (*createRoleNode) Close(context.Context)              {}
+
+func (*createRoleNode) Values() parser.Datums      { return parser.Datums{} }
+func (*createRoleNode) DebugValues() debugValues   { return debugValues{} }
+func (*createRoleNode) MarkDebug(mode explainMode) {}
+
+type dropRoleNode struct {
+	p *planner
+	n *parser.DropRole
+}
+
+// DropRole drops a list of roles. The memberships of the roles, both as a
+// role and as a member, are removed as well. Roles that still have
+// privileges, or to which row level security policies apply, cannot be
+// dropped.
+// Privileges: DELETE on system.roles.
+func (p *planner) DropRole(ctx context.Context, n *parser.DropRole) (planNode, error) {
+	tDesc, err := getTableDesc(ctx, p.txn, p.getVirtualTabler(), &parser.TableName{DatabaseName: "system", TableName: "roles"})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(tDesc, privilege.DELETE); err != nil {
+		return nil, err
+	}
+
+	return &dropRoleNode{p: p, n: n}, nil
+}
+
+func (n *dropRoleNode) Start(ctx context.Context) error {
+	internalExecutor := InternalExecutor{LeaseManager: n.p.LeaseMgr()}
+	var descs []sqlbase.DescriptorProto
+	for _, name := range n.n.Names {
+		normalizedName, err := normalizeRoleName(name)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := internalExecutor.ExecuteStatementInTransaction(
+			ctx,
+			"drop-role",
+			n.p.txn,
+			"DELETE FROM system.roles WHERE name=$1",
+			normalizedName,
+		)
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			if !n.n.IfExists {
+				return errors.Errorf("role %s does not exist", normalizedName)
+			}
+			continue
+		}
+
+		// Privileges are granted by name, so a new user or role of the same
+		// name would inherit the privileges left behind by the role.
+		if descs == nil {
+			if descs, err = getAllDescriptors(ctx, n.p.txn); err != nil {
+				return err
+			}
+		}
+		if err := checkRoleUnused(descs, normalizedName); err != nil {
+			return err
+		}
+
+		if _, err := internalExecutor.ExecuteStatementInTransaction(
+			ctx,
+			"drop-role-members",
+			n.p.txn,
+			"DELETE FROM system.role_members WHERE role=$1 OR member=$1",
+			normalizedName,
+		); err != nil {
+			return err
+		}
+	}
+
+	return n.p.bumpRoleMembersVersion(ctx)
+}
+
+// checkRoleUnused returns an error if the role has privileges on a database
+// or table, or if a row level security policy applies to it.
+func checkRoleUnused(descs []sqlbase.DescriptorProto, role string) error {
+	for _, desc := range descs {
+		if table, ok := desc.(*sqlbase.TableDescriptor); ok {
+			if table.Dropped() {
+				continue
+			}
+			for _, policy := range table.Policies {
+				for _, r := range policy.Roles {
+					if r == role {
+						return sqlbase.NewDependentObjectError(fmt.Sprintf(
+							"role %s cannot be dropped because policy %q on table %s applies to it",
+							role, policy.Name, table.Name))
+					}
+				}
+			}
+		}
+		if desc.GetPrivileges().AnyPrivilege(role) {
+			return sqlbase.NewDependentObjectError(fmt.Sprintf(
+				"role %s cannot be dropped because it has privileges on %s %s",
+				role, desc.TypeName(), desc.GetName()))
+		}
+	}
+	return nil
+}
+
+func (*dropRoleNode) Next(context.Context) (bool, error) { return false, nil }
+func (*dropRoleNode) Close(context.Context)              {}
+func (*dropRoleNode) Values() parser.Datums              { return parser.Datums{} }
+func (*dropRoleNode) DebugValues() debugValues           { return debugValues{} }
+func (*dropRoleNode) MarkDebug(mode explainMode)         {}
+
+// checkRoleAdmin normalizes the given role names and verifies that the
+// roles exist and that the session user is allowed to change their members.
+func (p *planner) checkRoleAdmin(
+	ctx context.Context, ie InternalExecutor, roles parser.NameList,
+) ([]string, error) {
+	var memberOf map[string]bool
+	if p.session.User != security.RootUser {
+		var err error
+		memberOf, err = p.MemberOf(ctx, p.session.User)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ret := make([]string, len(roles))
+	for i, name := range roles {
+		role, err := normalizeRoleName(name)
+		if err != nil {
+			return nil, err
+		}
+		if exists, err := roleExists(ctx, ie, p.txn, role); err != nil {
+			return nil, err
+		} else if !exists {
+			return nil, errors.Errorf("role %s does not exist", role)
+		}
+		if p.session.User != security.RootUser && !memberOf[role] {
+			return nil, errors.Errorf("%s must have admin option on role %s", p.session.User, role)
+		}
+		ret[i] = role
+	}
+	return ret, nil
+}
+
+// normalizeRoleMembers normalizes the given member names and verifies that
+// each of them is an existing user or role.
+func (p *planner) normalizeRoleMembers(
+	ctx context.Context, ie InternalExecutor, members parser.NameList,
+) ([]string, error) {
+	ret := make([]string, len(members))
+	for i, name := range members {
+		member, err := NormalizeAndValidateUsername(string(name))
+		if err != nil {
+			return nil, err
+		}
+		if member == security.RootUser {
+			return nil, errors.Errorf("%s cannot be a member of a role", security.RootUser)
+		}
+		isUser, err := userExists(ctx, ie, p.txn, member)
+		if err != nil {
+			return nil, err
+		}
+		if !isUser {
+			if isRole, err := roleExists(ctx, ie, p.txn, member); err != nil {
+				return nil, err
+			} else if !isRole {
+				return nil, errors.Errorf("user or role %s does not exist", member)
+			}
+		}
+		ret[i] = member
+	}
+	return ret, nil
+}
+
+type grantRoleNode struct {
+	p *planner
+	n *parser.GrantRole
+}
+
+// GrantRole adds users or roles as members of roles.
+// Privileges: ADMIN OPTION on the roles, or root.
+func (p *planner) GrantRole(ctx context.Context, n *parser.GrantRole) (planNode, error) {
+	return &grantRoleNode{p: p, n: n}, nil
+}
+
+func (n *grantRoleNode) Start(ctx context.Context) error {
+	internalExecutor := InternalExecutor{LeaseManager: n.p.LeaseMgr()}
+	roles, err := n.p.checkRoleAdmin(ctx, internalExecutor, n.n.Roles)
+	if err != nil {
+		return err
+	}
+	members, err := n.p.normalizeRoleMembers(ctx, internalExecutor, n.n.Members)
+	if err != nil {
+		return err
+	}
+
+	stmt := `INSERT INTO system.role_members VALUES ($1, $2, false) ON CONFLICT (role, member) DO NOTHING`
+	if n.n.AdminOption {
+		stmt = `UPSERT INTO system.role_members VALUES ($1, $2, true)`
+	}
+	for _, role := range roles {
+		for _, member := range members {
+			if role == member {
+				return errors.Errorf("role %s cannot be a member of itself", role)
+			}
+			// Check for cycles: the role cannot be a member of the new member,
+			// directly or indirectly. The memberships added by this statement so
+			// far are visible to the check.
+			roleMemberOf, err := n.p.resolveMemberOf(ctx, role)
+			if err != nil {
+				return err
+			}
+			if _, ok := roleMemberOf[member]; ok {
+				return errors.Errorf(
+					"making %s a member of %s would create a cycle", member, role,
+				)
+			}
+			if _, err := internalExecutor.ExecuteStatementInTransaction(
+				ctx, "grant-role", n.p.txn, stmt, role, member,
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	return n.p.bumpRoleMembersVersion(ctx)
+}
+
+func (*grantRoleNode) Next(context.Context) (bool, error) { return false, nil }
+func (*grantRoleNode) Close(context.Context)              {}
+func (*grantRoleNode) Values() parser.Datums              { return parser.Datums{} }
+func (*grantRoleNode) DebugValues() debugValues           { return debugValues{} }
+func (*grantRoleNode) MarkDebug(mode explainMode)         {}
+
+type revokeRoleNode struct {
+	p *planner
+	n *parser.RevokeRole
+}
+
+// RevokeRole removes users or roles from the members of roles, or only
+// takes away their ADMIN OPTION on the roles.
+// Privileges: ADMIN OPTION on the roles, or root.
+func (p *planner) RevokeRole(ctx context.Context, n *parser.RevokeRole) (planNode, error) {
+	return &revokeRoleNode{p: p, n: n}, nil
+}
+
+func (n *revokeRoleNode) Start(ctx context.Context) error {
+	internalExecutor := InternalExecutor{LeaseManager: n.p.LeaseMgr()}
+	roles, err := n.p.checkRoleAdmin(ctx, internalExecutor, n.n.Roles)
+	if err != nil {
+		return err
+	}
+	members, err := n.p.normalizeRoleMembers(ctx, internalExecutor, n.n.Members)
+	if err != nil {
+		return err
+	}
+
+	stmt := `DELETE FROM system.role_members WHERE role = $1 AND member = $2`
+	if n.n.AdminOption {
+		stmt = `UPDATE system.role_members SET "isAdmin" = false WHERE role = $1 AND member = $2`
+	}
+	for _, role := range roles {
+		for _, member := range members {
+			// Like Postgres, revoking a membership that does not exist is not an
+			// error.
+			if _, err := internalExecutor.ExecuteStatementInTransaction(
+				ctx, "revoke-role", n.p.txn, stmt, role, member,
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	return n.p.bumpRoleMembersVersion(ctx)
+}
+
+func (*revokeRoleNode) Next(context.Context) (bool, error) { return false, nil }
+func (*revokeRoleNode) Close(context.Context)              {}
+func (*revokeRoleNode) Values() parser.Datums              { return parser.Datums{} }
+func (*revokeRoleNode) DebugValues() debugValues           { return debugValues{} }
+func (*revokeRoleNode) MarkDebug(mode explainMode)         {}
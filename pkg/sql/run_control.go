@@ -19,16 +19,92 @@ package sql
 import (
 	"golang.org/x/net/context"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/jobs"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/pkg/errors"
 )
 
+// resumeHookFn is a function that can continue the work of a paused job. If the
+// function does not handle the job (e.g. because of its type), it returns a nil
+// fn. Otherwise fn is called by RESUME JOB after the job has been marked as
+// running again, and is expected to run the job until it completes or is
+// paused again, or to start running it in the background.
+type resumeHookFn func(*jobs.JobLogger, PlanHookState) (fn func(context.Context) error)
+
+var resumeHooks []resumeHookFn
+
+// AddResumeHook adds a hook used to continue jobs resumed with RESUME JOB.
+func AddResumeHook(f resumeHookFn) {
+	resumeHooks = append(resumeHooks, f)
+}
+
+// controlJob returns a planNode that loads the job with the given ID during
+// Start and runs fn on it. Jobs can only be controlled by the user that created
+// them or by the root user.
+func (p *planner) controlJob(
+	ctx context.Context, id parser.Expr, op string, fn func(context.Context, *jobs.JobLogger) error,
+) (planNode, error) {
+	typedID, err := parser.TypeCheckAndRequire(id, &p.semaCtx, parser.TypeInt, op)
+	if err != nil {
+		return nil, err
+	}
+	return &hookFnNode{f: func(ctx context.Context) ([]parser.Datums, error) {
+		d, err := typedID.Eval(&p.evalCtx)
+		if err != nil {
+			return nil, err
+		}
+		if d == parser.DNull {
+			return nil, errors.Errorf("%s: job ID cannot be NULL", op)
+		}
+		jl, err := jobs.GetJobLogger(
+			ctx, p.ExecCfg().DB, InternalExecutor{LeaseManager: p.LeaseMgr()},
+			int64(parser.MustBeDInt(d)),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if jl.Job.Username != p.User() {
+			if err := p.RequireSuperUser(op); err != nil {
+				return nil, err
+			}
+		}
+		return nil, fn(ctx, jl)
+	}}, nil
+}
+
+// resumeFn returns the function that continues the work of the given job, or
+// nil if the job cannot be resumed.
+func (p *planner) resumeFn(jl *jobs.JobLogger) func(context.Context) error {
+	for _, hook := range resumeHooks {
+		if fn := hook(jl, p); fn != nil {
+			return fn
+		}
+	}
+	return nil
+}
+
 func (p *planner) PauseJob(ctx context.Context, n *parser.PauseJob) (planNode, error) {
-	return nil, pgerror.Unimplemented("pause-job", "unimplemented")
+	return p.controlJob(ctx, n.ID, "PAUSE JOB", func(ctx context.Context, jl *jobs.JobLogger) error {
+		// Only pause jobs that can be resumed later on.
+		if p.resumeFn(jl) == nil {
+			return errors.Errorf("PAUSE JOB: job %d (%s) cannot be paused", *jl.JobID(), jl.Job.Description)
+		}
+		return jl.Paused(ctx)
+	})
 }
 
 func (p *planner) ResumeJob(ctx context.Context, n *parser.ResumeJob) (planNode, error) {
-	return nil, pgerror.Unimplemented("resume-job", "unimplemented")
+	return p.controlJob(ctx, n.ID, "RESUME JOB", func(ctx context.Context, jl *jobs.JobLogger) error {
+		resumeFn := p.resumeFn(jl)
+		if resumeFn == nil {
+			return errors.Errorf("RESUME JOB: job %d (%s) cannot be resumed", *jl.JobID(), jl.Job.Description)
+		}
+		if err := jl.Resumed(ctx); err != nil {
+			return err
+		}
+		return resumeFn(ctx)
+	})
 }
 
 func (p *planner) CancelJob(ctx context.Context, n *parser.CancelJob) (planNode, error) {
@@ -238,6 +238,7 @@ func (n *scanNode) Next(ctx context.Context) (bool, error) {
 
 // Initializes a scanNode with a table descriptor.
 func (n *scanNode) initTable(
+	ctx context.Context,
 	p *planner,
 	desc *sqlbase.TableDescriptor,
 	indexHints *parser.IndexHints,
@@ -246,11 +247,21 @@ func (n *scanNode) initTable(
 ) error {
 	n.desc = *desc
 
+	if err := n.initDescDefaults(scanVisibility, wantedColumns); err != nil {
+		return err
+	}
 	if !p.skipSelectPrivilegeChecks {
 		if err := p.CheckPrivilege(&n.desc, privilege.SELECT); err != nil {
-			return err
+			// The user may still read the columns it was granted SELECT on.
+			if err := p.denyColumns(&n.desc, n.cols, n.resultColumns, err); err != nil {
+				return err
+			}
 		}
 	}
+	p.maybeAudit(&n.desc, privilege.SELECT)
+	if err := n.initRowLevelSecurity(ctx, p); err != nil {
+		return err
+	}
 
 	if indexHints != nil {
 		if err := n.lookupSpecifiedIndex(indexHints); err != nil {
@@ -258,7 +269,7 @@ func (n *scanNode) initTable(
 		}
 	}
 	n.noIndexJoin = (indexHints != nil && indexHints.NoIndexJoin)
-	return n.initDescDefaults(scanVisibility, wantedColumns)
+	return nil
 }
 
 func (n *scanNode) lookupSpecifiedIndex(indexHints *parser.IndexHints) error {
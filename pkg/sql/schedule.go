@@ -0,0 +1,294 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/jobs"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// SchedulerInterval is the interval at which each node checks the
+// system.schedules table for schedules that are due to run.
+var SchedulerInterval = settings.RegisterValidatedDurationSetting(
+	"jobs.scheduler.poll_interval",
+	"the interval at which each node checks for scheduled jobs that are due to run",
+	30*time.Second,
+	func(v time.Duration) error {
+		if v <= 0 {
+			return errors.Errorf("cannot set jobs.scheduler.poll_interval to a non-positive duration: %s", v)
+		}
+		return nil
+	},
+)
+
+// scheduleHeartbeatInterval is the interval at which a node running a schedule
+// records that the run is still in progress. It must be well below
+// jobs.ScheduleRunExpiration.
+const scheduleHeartbeatInterval = time.Minute
+
+// ScheduleExecFn executes a statement on behalf of a schedule, as the user
+// that created the schedule, and returns the rows it produced.
+type ScheduleExecFn func(ctx context.Context, stmt string) ([]parser.Datums, error)
+
+// scheduleRunnerFn is a function that can run a schedule when it is due. If the
+// function does not handle the schedule (e.g. because of its type), it returns
+// a nil fn. Otherwise fn is called by the scheduler with the time at which the
+// schedule was found to be due, and is expected to run it by executing
+// statements with exec. Any changes fn makes to the payload, such as recording
+// the job it started, are saved with the schedule once it returns.
+type scheduleRunnerFn func(*jobs.SchedulePayload) (fn func(ctx context.Context, now time.Time, exec ScheduleExecFn) error)
+
+var scheduleRunners []scheduleRunnerFn
+
+// AddScheduleRunner adds a hook used by the scheduler to run due schedules.
+func AddScheduleRunner(f scheduleRunnerFn) {
+	scheduleRunners = append(scheduleRunners, f)
+}
+
+// Scheduler runs the schedules in the system.schedules table when they are
+// due. Every node runs a Scheduler, and each time a schedule is due it is
+// claimed and run by exactly one of them.
+type Scheduler struct {
+	executor   *Executor
+	memMetrics *MemoryMetrics
+}
+
+// NewScheduler creates a Scheduler that runs schedules using executor.
+func NewScheduler(executor *Executor, memMetrics *MemoryMetrics) *Scheduler {
+	return &Scheduler{executor: executor, memMetrics: memMetrics}
+}
+
+// Start runs the scheduler until the stopper quiesces. It must be called after
+// the migration creating the system.schedules table has run.
+func (s *Scheduler) Start(stopper *stop.Stopper) {
+	stopper.RunWorker(context.TODO(), func(ctx context.Context) {
+		ctx = s.executor.AnnotateCtx(ctx)
+		for {
+			select {
+			case <-time.After(SchedulerInterval.Get()):
+				s.runDue(ctx, stopper)
+			case <-stopper.ShouldQuiesce():
+				return
+			}
+		}
+	})
+}
+
+// runDue claims and runs schedules until none are due.
+func (s *Scheduler) runDue(ctx context.Context, stopper *stop.Stopper) {
+	ex := InternalExecutor{LeaseManager: s.executor.cfg.LeaseManager}
+	for {
+		select {
+		case <-stopper.ShouldQuiesce():
+			return
+		default:
+		}
+		now := timeutil.Now()
+		id, payload, err := jobs.ClaimDueSchedule(ctx, s.executor.cfg.DB, ex, now)
+		if err != nil {
+			log.Warningf(ctx, "failed to claim due schedule: %s", err)
+			return
+		}
+		if payload == nil {
+			return
+		}
+		payload.Error = ""
+		done := make(chan struct{})
+		if err := stopper.RunAsyncTask(ctx, "schedule-heartbeat", func(ctx context.Context) {
+			s.heartbeat(ctx, stopper, ex, id, payload.LastRunMicros, done)
+		}); err != nil {
+			return
+		}
+		err = s.run(ctx, payload, now)
+		close(done)
+		if err != nil {
+			log.Warningf(ctx, "schedule %d (%s) failed: %s", id, payload.Name, err)
+			payload.Error = err.Error()
+		}
+		if err := jobs.SaveScheduleRun(ctx, s.executor.cfg.DB, ex, id, payload); err != nil {
+			log.Warningf(ctx, "failed to record run of schedule %d: %s", id, err)
+		}
+	}
+}
+
+// heartbeat records that the run of the schedule with the given ID that
+// started at lastRunMicros is in progress until done is closed, so that the
+// schedule is not claimed again in the meantime.
+func (s *Scheduler) heartbeat(
+	ctx context.Context,
+	stopper *stop.Stopper,
+	ex InternalExecutor,
+	id int64,
+	lastRunMicros int64,
+	done <-chan struct{},
+) {
+	for {
+		select {
+		case <-time.After(scheduleHeartbeatInterval):
+			if err := jobs.HeartbeatScheduleRun(
+				ctx, s.executor.cfg.DB, ex, id, lastRunMicros, timeutil.Now(),
+			); err != nil {
+				log.Warningf(ctx, "failed to heartbeat run of schedule %d: %s", id, err)
+			}
+		case <-done:
+			return
+		case <-stopper.ShouldQuiesce():
+			return
+		}
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, payload *jobs.SchedulePayload, now time.Time) error {
+	for _, runner := range scheduleRunners {
+		if fn := runner(payload); fn != nil {
+			return fn(ctx, now, func(ctx context.Context, stmt string) ([]parser.Datums, error) {
+				return s.exec(ctx, payload.Username, stmt)
+			})
+		}
+	}
+	return errors.Errorf("no runner for %s schedules", payload.Typ())
+}
+
+// exec executes a single statement in a new session for the given user.
+func (s *Scheduler) exec(ctx context.Context, user, stmt string) ([]parser.Datums, error) {
+	session := NewSession(ctx, SessionArgs{User: user}, s.executor, nil, s.memMetrics)
+	session.StartUnlimitedMonitor()
+	defer session.Finish(s.executor)
+
+	res := s.executor.ExecuteStatements(session, stmt, nil)
+	defer res.Close(ctx)
+	if len(res.ResultList) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(res.ResultList))
+	}
+	result := res.ResultList[0]
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	var rows []parser.Datums
+	if result.Rows != nil {
+		for i := 0; i < result.Rows.Len(); i++ {
+			rows = append(rows, append(parser.Datums(nil), result.Rows.At(i)...))
+		}
+	}
+	return rows, nil
+}
+
+// controlSchedule returns a planNode that loads the schedule with the given ID
+// during Start and runs fn on it. Schedules can only be controlled by the user
+// that created them or by the root user.
+func (p *planner) controlSchedule(
+	ctx context.Context,
+	id parser.Expr,
+	op string,
+	fn func(context.Context, InternalExecutor, int64, jobs.ScheduleStatus, *jobs.SchedulePayload) error,
+) (planNode, error) {
+	typedID, err := parser.TypeCheckAndRequire(id, &p.semaCtx, parser.TypeInt, op)
+	if err != nil {
+		return nil, err
+	}
+	return &hookFnNode{f: func(ctx context.Context) ([]parser.Datums, error) {
+		d, err := typedID.Eval(&p.evalCtx)
+		if err != nil {
+			return nil, err
+		}
+		if d == parser.DNull {
+			return nil, errors.Errorf("%s: schedule ID cannot be NULL", op)
+		}
+		scheduleID := int64(parser.MustBeDInt(d))
+		ex := InternalExecutor{LeaseManager: p.LeaseMgr()}
+		status, payload, err := jobs.GetSchedule(ctx, ex, p.txn, scheduleID)
+		if err != nil {
+			return nil, err
+		}
+		if payload.Username != p.User() {
+			if err := p.RequireSuperUser(op); err != nil {
+				return nil, err
+			}
+		}
+		return nil, fn(ctx, ex, scheduleID, status, payload)
+	}}, nil
+}
+
+// PauseSchedule stops a schedule from running until it is resumed.
+// Privileges: owner of the schedule or root.
+func (p *planner) PauseSchedule(ctx context.Context, n *parser.PauseSchedule) (planNode, error) {
+	return p.controlSchedule(ctx, n.ID, "PAUSE SCHEDULE", func(
+		ctx context.Context, ex InternalExecutor, id int64, status jobs.ScheduleStatus, payload *jobs.SchedulePayload,
+	) error {
+		if status != jobs.ScheduleStatusActive {
+			return errors.Errorf("PAUSE SCHEDULE: schedule %d is %s", id, status)
+		}
+		return p.setScheduleStatus(ctx, ex, id, jobs.ScheduleStatusPaused, payload)
+	})
+}
+
+// ResumeSchedule resumes a paused schedule, which next runs at its first
+// occurrence after it is resumed.
+// Privileges: owner of the schedule or root.
+func (p *planner) ResumeSchedule(ctx context.Context, n *parser.ResumeSchedule) (planNode, error) {
+	return p.controlSchedule(ctx, n.ID, "RESUME SCHEDULE", func(
+		ctx context.Context, ex InternalExecutor, id int64, status jobs.ScheduleStatus, payload *jobs.SchedulePayload,
+	) error {
+		if status != jobs.ScheduleStatusPaused {
+			return errors.Errorf("RESUME SCHEDULE: schedule %d is %s", id, status)
+		}
+		return p.setScheduleStatus(ctx, ex, id, jobs.ScheduleStatusActive, payload)
+	})
+}
+
+func (p *planner) setScheduleStatus(
+	ctx context.Context,
+	ex InternalExecutor,
+	id int64,
+	status jobs.ScheduleStatus,
+	payload *jobs.SchedulePayload,
+) error {
+	recurrence, err := jobs.ParseRecurrence(payload.Recurrence)
+	if err != nil {
+		return err
+	}
+	nextRun := recurrence.Next(p.evalCtx.GetStmtTimestamp())
+	return jobs.UpdateSchedule(ctx, ex, p.txn, id, status, nextRun, payload)
+}
+
+// DropSchedule removes a schedule. Jobs it already started are unaffected.
+// Privileges: owner of the schedule or root.
+func (p *planner) DropSchedule(ctx context.Context, n *parser.DropSchedule) (planNode, error) {
+	return p.controlSchedule(ctx, n.ID, "DROP SCHEDULE", func(
+		ctx context.Context, ex InternalExecutor, id int64, _ jobs.ScheduleStatus, _ *jobs.SchedulePayload,
+	) error {
+		return jobs.DeleteSchedule(ctx, ex, p.txn, id)
+	})
+}
+
+// ShowSchedules returns all the schedules.
+// Privileges: SELECT on system.schedules.
+func (p *planner) ShowSchedules(ctx context.Context, n *parser.ShowSchedules) (planNode, error) {
+	stmt, err := parser.ParseOne(`SELECT id, name, type, status, recurrence, next_run, last_run, last_job_id, error
+FROM crdb_internal.schedules ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	return p.newPlan(ctx, stmt, nil)
+}
@@ -41,6 +41,10 @@ type nameResolutionVisitor struct {
 	iVarHelper parser.IndexedVarHelper
 	searchPath parser.SearchPath
 
+	// outerScopes are the enclosing data sources that column
+	// references can refer to if they are not found in sources.
+	outerScopes []*outerScope
+
 	// foundDependentVars is set to true during the analysis if an
 	// expression was found which can change values between rows of the
 	// same data source, for example IndexedVars and calls to the
@@ -68,6 +72,14 @@ func (v *nameResolutionVisitor) VisitPre(expr parser.Expr) (recurse bool, newNod
 			v.err = err
 			return false, expr
 		}
+		// Ordinal references must not bypass the column privileges.
+		for srcIdx, src := range v.sources {
+			colIdx := t.Idx - v.colOffsets[srcIdx]
+			if colIdx >= 0 && colIdx < len(src.sourceColumns) && src.sourceColumns[colIdx].Denied {
+				v.err = newColumnPrivilegeError(src.sourceColumns[colIdx].Name)
+				return false, expr
+			}
+		}
 
 		// We allow resolving IndexedVars on expressions that have already been resolved by this
 		// resolver. This is used in some cases when adding render targets for grouping or sorting.
@@ -87,6 +99,15 @@ func (v *nameResolutionVisitor) VisitPre(expr parser.Expr) (recurse bool, newNod
 	case *parser.ColumnItem:
 		srcIdx, colIdx, err := v.sources.findColumn(t)
 		if err != nil {
+			if _, ok := err.(*unresolvedNameError); ok {
+				ov, outerErr := v.findOuterColumn(t)
+				if outerErr != nil {
+					err = outerErr
+				} else if ov != nil {
+					v.foundDependentVars = true
+					return false, ov
+				}
+			}
 			v.err = err
 			return false, expr
 		}
@@ -190,6 +211,7 @@ func (p *planner) resolveNames(
 		colOffsets:         make([]int, len(sources)),
 		iVarHelper:         ivarHelper,
 		searchPath:         p.session.SearchPath,
+		outerScopes:        p.outerScopes,
 		foundDependentVars: false,
 	}
 	colOffset := 0
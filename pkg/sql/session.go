@@ -249,6 +249,9 @@ type Session struct {
 	// distSQLPlanner is in charge of distSQL physical planning and running
 	// logic.
 	distSQLPlanner *distSQLPlanner
+	// roleMembers caches the role memberships of users. It is shared by all
+	// the sessions of an Executor.
+	roleMembers *roleMembershipCache
 	// context is the Session's base context, to be used for all
 	// SQL-related logging. See Ctx().
 	context context.Context
@@ -290,6 +293,10 @@ type Session struct {
 	// If set, contains the in progress COPY FROM columns.
 	copyFrom *copyNode
 
+	// CopyOut, if set, receives the rows of COPY TO STDOUT statements. It is
+	// set by pgwire; other clients cannot use COPY TO STDOUT.
+	CopyOut CopyOutWriter
+
 	// mu contains of all elements of the struct that can be changed
 	// after initialization, and may be accessed from another thread.
 	mu struct {
@@ -415,6 +422,7 @@ func NewSession(
 		virtualSchemas:   e.virtualSchemas,
 		execCfg:          &e.cfg,
 		distSQLPlanner:   e.distSQLPlanner,
+		roleMembers:      &e.roleMembers,
 		parallelizeQueue: MakeParallelizeQueue(NewSpanBasedDependencyAnalyzer()),
 		memMetrics:       memMetrics,
 		sqlStats:         &e.sqlStats,
@@ -574,6 +582,7 @@ func (s *Session) resetPlanner(p *planner, e *Executor, txn *client.Txn) {
 	p.session = s
 	// phaseTimes is an array, not a slice, so this performs a copy-by-value.
 	p.phaseTimes = s.phaseTimes
+	p.auditEvents = nil
 
 	p.semaCtx = parser.MakeSemaContext(s.User == security.RootUser)
 	p.semaCtx.Location = &s.Location
@@ -612,6 +621,7 @@ func (s *Session) evalCtx() parser.EvalContext {
 	return parser.EvalContext{
 		Location:   &s.Location,
 		Database:   s.Database,
+		User:       s.User,
 		SearchPath: s.SearchPath,
 		Ctx:        s.Ctx,
 		Mon:        &s.TxnState.mon,
@@ -447,15 +447,24 @@ func (p *planner) showCreateTable(
 			if err != nil {
 				return "", err
 			}
-			fmt.Fprintf(&buf, ",\n\t%s%s",
+			var predicate string
+			if idx.IsPartial() {
+				predicate = " WHERE " + idx.Predicate
+			}
+			fmt.Fprintf(&buf, ",\n\t%s%s%s",
 				idx.SQLString(""),
 				interleave,
+				predicate,
 			)
 		}
 	}
 	for _, fam := range desc.Families {
 		activeColumnNames := make([]string, 0, len(fam.ColumnNames))
 		for i, colID := range fam.ColumnIDs {
+			if desc.IsIndexExprColumn(colID) {
+				// Recreated along with the expression index.
+				continue
+			}
 			if _, err := desc.FindActiveColumnByID(colID); err == nil {
 				activeColumnNames = append(activeColumnNames, fam.ColumnNames[i])
 			}
@@ -803,8 +812,7 @@ func (p *planner) ShowGrants(ctx context.Context, n *parser.ShowGrants) (planNod
 				if len(paramHolders) == 0 {
 					return v, nil
 				}
-				tableGrants := fmt.Sprintf(`SELECT TABLE_NAME, GRANTEE, PRIVILEGE_TYPE FROM information_schema.table_privileges
-									WHERE (TABLE_SCHEMA, TABLE_NAME) IN (%s)`, strings.Join(paramHolders, ","))
+				filter := fmt.Sprintf(`(TABLE_SCHEMA, TABLE_NAME) IN (%s)`, strings.Join(paramHolders, ","))
 				if n.Grantees != nil {
 					paramHolders = paramHolders[:0]
 					for _, grantee := range n.Grantees.ToStrings() {
@@ -812,12 +820,21 @@ func (p *planner) ShowGrants(ctx context.Context, n *parser.ShowGrants) (planNod
 						params = append(params, grantee)
 						paramSeq++
 					}
-					tableGrants = fmt.Sprintf(`%s AND GRANTEE IN(%s)`, tableGrants, strings.Join(paramHolders, ","))
+					filter = fmt.Sprintf(`%s AND GRANTEE IN(%s)`, filter, strings.Join(paramHolders, ","))
 				}
+				tableGrants := fmt.Sprintf(`SELECT TABLE_NAME, GRANTEE, PRIVILEGE_TYPE FROM information_schema.table_privileges
+									WHERE %s`, filter)
 				if err := queryFn(tableGrants, params...); err != nil {
 					v.rows.Close(ctx)
 					return nil, err
 				}
+				// Column privileges are shown as e.g. "SELECT (a)".
+				columnGrants := fmt.Sprintf(`SELECT TABLE_NAME, GRANTEE, PRIVILEGE_TYPE || ' (' || COLUMN_NAME || ')'
+									FROM information_schema.column_privileges WHERE %s`, filter)
+				if err := queryFn(columnGrants, params...); err != nil {
+					v.rows.Close(ctx)
+					return nil, err
+				}
 			}
 
 			// Sort the result by target name, user name and privileges.
@@ -1205,6 +1222,16 @@ func (p *planner) ShowUsers(ctx context.Context, n *parser.ShowUsers) (planNode,
 	return p.newPlan(ctx, stmt, nil)
 }
 
+// ShowRoles returns all the roles.
+// Privileges: SELECT on system.roles.
+func (p *planner) ShowRoles(ctx context.Context, n *parser.ShowRoles) (planNode, error) {
+	stmt, err := parser.ParseOne(`SELECT name FROM system.roles ORDER BY 1`)
+	if err != nil {
+		return nil, err
+	}
+	return p.newPlan(ctx, stmt, nil)
+}
+
 // Help returns usage information for the builtin functions
 // Privileges: None
 func (p *planner) Help(ctx context.Context, n *parser.Help) (planNode, error) {
@@ -0,0 +1,395 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/mon"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+)
+
+// IndexExprColumns returns the hidden columns which back the expression
+// elements of the table's indexes, along with their expressions. Only the
+// columns which must be computed when a row is written are returned: columns
+// of indexes being dropped and columns still in the DELETE_ONLY state are
+// skipped.
+func (desc *TableDescriptor) IndexExprColumns() ([]ColumnDescriptor, []string) {
+	var cols []ColumnDescriptor
+	var exprs []string
+	addIndex := func(index *IndexDescriptor) {
+		for i, expr := range index.ColumnExprs {
+			if expr == "" {
+				continue
+			}
+			if col, ok := desc.writableColumnByID(index.ColumnIDs[i]); ok {
+				cols = append(cols, col)
+				exprs = append(exprs, expr)
+			}
+		}
+	}
+	for i := range desc.Indexes {
+		addIndex(&desc.Indexes[i])
+	}
+	for _, m := range desc.Mutations {
+		if index := m.GetIndex(); index != nil && m.Direction == DescriptorMutation_ADD {
+			addIndex(index)
+		}
+	}
+	return cols, exprs
+}
+
+// IsIndexExprColumn returns true if the column with the given ID backs an
+// expression element of one of the table's indexes.
+func (desc *TableDescriptor) IsIndexExprColumn(id ColumnID) bool {
+	isExprColumn := func(index *IndexDescriptor) bool {
+		for i, expr := range index.ColumnExprs {
+			if expr != "" && index.ColumnIDs[i] == id {
+				return true
+			}
+		}
+		return false
+	}
+	for i := range desc.Indexes {
+		if isExprColumn(&desc.Indexes[i]) {
+			return true
+		}
+	}
+	for _, m := range desc.Mutations {
+		if index := m.GetIndex(); index != nil && isExprColumn(index) {
+			return true
+		}
+	}
+	return false
+}
+
+// writableColumnByID returns the column with the given ID if it is public or
+// in the DELETE_AND_WRITE_ONLY state.
+func (desc *TableDescriptor) writableColumnByID(id ColumnID) (ColumnDescriptor, bool) {
+	for _, c := range desc.Columns {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	for _, m := range desc.Mutations {
+		if c := m.GetColumn(); c != nil && c.ID == id {
+			return *c, m.Direction == DescriptorMutation_ADD &&
+				m.State == DescriptorMutation_DELETE_AND_WRITE_ONLY
+		}
+	}
+	return ColumnDescriptor{}, false
+}
+
+// ParseIndexExpr parses the expression of an index element or the predicate
+// of a partial index and resolves the column names it refers to against the
+// public columns of the table. The returned IndexedVars are created by
+// ivarHelper and refer to the columns by their position in desc.Columns.
+func ParseIndexExpr(
+	desc *TableDescriptor, exprStr string, ivarHelper *parser.IndexedVarHelper,
+) (parser.Expr, error) {
+	expr, err := parser.ParseExpr(exprStr)
+	if err != nil {
+		return nil, err
+	}
+	v := indexExprNameResolver{desc: desc, ivarHelper: ivarHelper}
+	expr, _ = parser.WalkExpr(&v, expr)
+	if v.err != nil {
+		return nil, v.err
+	}
+	return expr, nil
+}
+
+// IndexExprSourceColumnIDs returns the IDs of the columns the expression of
+// an index element or the predicate of a partial index refers to.
+func (desc *TableDescriptor) IndexExprSourceColumnIDs(exprStr string) ([]ColumnID, error) {
+	ivarHelper := parser.MakeIndexedVarHelper(nil, len(desc.Columns))
+	expr, err := ParseIndexExpr(desc, exprStr, &ivarHelper)
+	if err != nil {
+		return nil, err
+	}
+	v := indexedVarCollector{cols: desc.Columns}
+	parser.WalkExprConst(&v, expr)
+	return v.ids, nil
+}
+
+type indexExprNameResolver struct {
+	desc       *TableDescriptor
+	ivarHelper *parser.IndexedVarHelper
+	err        error
+}
+
+var _ parser.Visitor = &indexExprNameResolver{}
+
+func (v *indexExprNameResolver) VisitPre(expr parser.Expr) (recurse bool, newExpr parser.Expr) {
+	if v.err != nil {
+		return false, expr
+	}
+	if vBase, ok := expr.(parser.VarName); ok {
+		vn, err := vBase.NormalizeVarName()
+		if err != nil {
+			v.err = err
+			return false, expr
+		}
+		c, ok := vn.(*parser.ColumnItem)
+		if !ok {
+			v.err = fmt.Errorf("invalid index expression: %s", expr)
+			return false, expr
+		}
+		normName := c.ColumnName.Normalize()
+		for i, col := range v.desc.Columns {
+			if parser.ReNormalizeName(col.Name) == normName {
+				return false, v.ivarHelper.IndexedVar(i)
+			}
+		}
+		v.err = fmt.Errorf("column %q does not exist", normName)
+		return false, expr
+	}
+	return true, expr
+}
+
+func (*indexExprNameResolver) VisitPost(expr parser.Expr) parser.Expr { return expr }
+
+// indexRowContainer binds the IndexedVars of expressions parsed by
+// ParseIndexExpr to the values of the row being written.
+type indexRowContainer struct {
+	desc  *TableDescriptor
+	ivars []parser.IndexedVar
+	// curSourceRow holds the values of desc.Columns for the row being
+	// written, indexed like the IndexedVars.
+	curSourceRow parser.Datums
+
+	// The expressions are evaluated outside of any session, so they get
+	// their own unbounded memory monitor, cleared after every row and
+	// stopped by close.
+	evalCtx parser.EvalContext
+	mon     mon.MemoryMonitor
+	acc     mon.BoundAccount
+}
+
+// init parses and type checks the given expressions, which must have the
+// given types.
+func (c *indexRowContainer) init(
+	desc *TableDescriptor, exprStrs []string, types []parser.Type,
+) ([]parser.TypedExpr, error) {
+	c.desc = desc
+	exprs := make([]parser.TypedExpr, len(exprStrs))
+	ivarHelper := parser.MakeIndexedVarHelper(c, len(desc.Columns))
+	for i, exprStr := range exprStrs {
+		if exprStr == "" {
+			continue
+		}
+		expr, err := ParseIndexExpr(desc, exprStr, &ivarHelper)
+		if err != nil {
+			return nil, err
+		}
+		typedExpr, err := parser.TypeCheck(expr, nil, types[i])
+		if err != nil {
+			return nil, err
+		}
+		exprs[i] = typedExpr
+	}
+	c.ivars = ivarHelper.GetIndexedVars()
+	c.curSourceRow = make(parser.Datums, len(desc.Columns))
+
+	c.mon = mon.MakeMonitor(
+		"index-exprs",
+		nil,           /* curCount */
+		nil,           /* maxHist */
+		-1,            /* increment */
+		math.MaxInt64, /* noteworthy */
+	)
+	c.mon.Start(context.Background(), nil, mon.MakeStandaloneBudget(math.MaxInt64))
+	c.acc = c.mon.MakeBoundAccount()
+	c.evalCtx.Mon = &c.mon
+	c.evalCtx.Ctx = context.Background
+	c.evalCtx.ActiveMemAcc = &c.acc
+	return exprs, nil
+}
+
+// close stops the memory monitor of the container.
+func (c *indexRowContainer) close(ctx context.Context) {
+	c.acc.Close(ctx)
+	c.mon.Stop(ctx)
+}
+
+// setRow binds the IndexedVars to the given row, whose layout is described
+// by colIDtoRowIndex. Columns missing from the row are considered NULL.
+func (c *indexRowContainer) setRow(colIDtoRowIndex map[ColumnID]int, row parser.Datums) {
+	for _, ivar := range c.ivars {
+		if ivar.Idx == parser.InvalidColIdx {
+			continue
+		}
+		if ri, ok := colIDtoRowIndex[c.desc.Columns[ivar.Idx].ID]; ok {
+			c.curSourceRow[ivar.Idx] = row[ri]
+		} else {
+			c.curSourceRow[ivar.Idx] = parser.DNull
+		}
+	}
+}
+
+// exprColumnIDs returns the IDs of the columns the given expressions refer
+// to.
+func (c *indexRowContainer) exprColumnIDs(exprs []parser.TypedExpr) []ColumnID {
+	var ids []ColumnID
+	for _, expr := range exprs {
+		if expr == nil {
+			continue
+		}
+		v := indexedVarCollector{cols: c.desc.Columns}
+		parser.WalkExprConst(&v, expr)
+		ids = append(ids, v.ids...)
+	}
+	return ids
+}
+
+// IndexedVarEval implements the parser.IndexedVarContainer interface.
+func (c *indexRowContainer) IndexedVarEval(idx int, ctx *parser.EvalContext) (parser.Datum, error) {
+	return c.curSourceRow[idx].Eval(ctx)
+}
+
+// IndexedVarResolvedType implements the parser.IndexedVarContainer interface.
+func (c *indexRowContainer) IndexedVarResolvedType(idx int) parser.Type {
+	return c.desc.Columns[idx].Type.ToDatumType()
+}
+
+// IndexedVarFormat implements the parser.IndexedVarContainer interface.
+func (c *indexRowContainer) IndexedVarFormat(buf *bytes.Buffer, f parser.FmtFlags, idx int) {
+	parser.FormatNode(buf, f, parser.Name(c.desc.Columns[idx].Name))
+}
+
+type indexedVarCollector struct {
+	cols []ColumnDescriptor
+	ids  []ColumnID
+}
+
+var _ parser.Visitor = &indexedVarCollector{}
+
+func (v *indexedVarCollector) VisitPre(expr parser.Expr) (recurse bool, newExpr parser.Expr) {
+	if ivar, ok := expr.(*parser.IndexedVar); ok {
+		v.ids = append(v.ids, v.cols[ivar.Idx].ID)
+	}
+	return true, expr
+}
+
+func (*indexedVarCollector) VisitPost(expr parser.Expr) parser.Expr { return expr }
+
+// indexExprHelper computes the values of the hidden columns which back the
+// expression elements of a table's indexes.
+type indexExprHelper struct {
+	indexRowContainer
+	// targets are the hidden columns computed by exprs.
+	targets []ColumnDescriptor
+	exprs   []parser.TypedExpr
+}
+
+// init prepares the helper to compute the given hidden columns.
+func (h *indexExprHelper) init(
+	desc *TableDescriptor, targets []ColumnDescriptor, exprStrs []string,
+) error {
+	h.targets = targets
+	types := make([]parser.Type, len(targets))
+	for i := range targets {
+		types[i] = targets[i].Type.ToDatumType()
+	}
+	var err error
+	h.exprs, err = h.indexRowContainer.init(desc, exprStrs, types)
+	return err
+}
+
+// close releases the resources of the helper, which may be nil.
+func (h *indexExprHelper) close(ctx context.Context) {
+	if h != nil {
+		h.indexRowContainer.close(ctx)
+	}
+}
+
+// sourceColumnIDs returns the IDs of the columns the i-th expression refers
+// to, or of all the columns referred to by any expression if i is negative.
+func (h *indexExprHelper) sourceColumnIDs(i int) []ColumnID {
+	if i >= 0 {
+		return h.exprColumnIDs(h.exprs[i : i+1])
+	}
+	return h.exprColumnIDs(h.exprs)
+}
+
+// eval computes the value of every target column from the given row, whose
+// layout is described by colIDtoRowIndex, and stores it in the row. Every
+// target column must be part of the row; source columns missing from the row
+// are considered NULL.
+func (h *indexExprHelper) eval(colIDtoRowIndex map[ColumnID]int, row parser.Datums) error {
+	h.setRow(colIDtoRowIndex, row)
+	defer h.acc.Clear(h.evalCtx.Ctx())
+	for i, expr := range h.exprs {
+		d, err := expr.Eval(&h.evalCtx)
+		if err != nil {
+			return err
+		}
+		row[colIDtoRowIndex[h.targets[i].ID]] = d
+	}
+	return nil
+}
+
+// makeIndexExprUpdateHelper returns the columns which must be written when
+// updateCols are updated: updateCols themselves, followed by the hidden
+// columns of expression indexes whose source columns are being updated. It
+// also returns a helper computing those hidden columns, or nil if there are
+// none.
+func makeIndexExprUpdateHelper(
+	desc *TableDescriptor, updateCols []ColumnDescriptor,
+) ([]ColumnDescriptor, *indexExprHelper, error) {
+	exprCols, exprs := desc.IndexExprColumns()
+	if len(exprCols) == 0 {
+		return updateCols, nil, nil
+	}
+	updated := ColIDtoRowIndexFromCols(updateCols)
+
+	h := &indexExprHelper{}
+	if err := h.init(desc, exprCols, exprs); err != nil {
+		return nil, nil, err
+	}
+	var targets []ColumnDescriptor
+	var targetExprs []string
+	for i, col := range exprCols {
+		_, needed := updated[col.ID]
+		for _, id := range h.sourceColumnIDs(i) {
+			if _, ok := updated[id]; ok {
+				needed = true
+			}
+		}
+		if needed {
+			targets = append(targets, col)
+			targetExprs = append(targetExprs, exprs[i])
+		}
+	}
+	if len(targets) == 0 {
+		return updateCols, nil, nil
+	}
+
+	h = &indexExprHelper{}
+	if err := h.init(desc, targets, targetExprs); err != nil {
+		return nil, nil, err
+	}
+	allUpdateCols := updateCols[:len(updateCols):len(updateCols)]
+	for _, col := range targets {
+		if _, ok := updated[col.ID]; !ok {
+			allUpdateCols = append(allUpdateCols, col)
+		}
+	}
+	return allUpdateCols, h, nil
+}
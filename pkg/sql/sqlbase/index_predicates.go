@@ -0,0 +1,122 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+)
+
+// IsPartial returns true if the index only contains the rows which satisfy
+// its predicate.
+func (desc *IndexDescriptor) IsPartial() bool {
+	return desc.Predicate != ""
+}
+
+// isDroppingIndex returns true if the index with the given ID is being
+// dropped.
+func (desc *TableDescriptor) isDroppingIndex(id IndexID) bool {
+	for _, m := range desc.Mutations {
+		if index := m.GetIndex(); index != nil && index.ID == id {
+			return m.Direction == DescriptorMutation_DROP
+		}
+	}
+	return false
+}
+
+// IndexPredicates evaluates the predicates of partial indexes to determine
+// which rows they contain.
+type IndexPredicates struct {
+	indexRowContainer
+	// preds parallels the indexes the helper was created for. The entry of an
+	// index over all the rows of the table is nil.
+	preds []parser.TypedExpr
+}
+
+// MakeIndexPredicates returns an IndexPredicates for the given indexes of the
+// table, or nil if none of them is partial. Indexes being dropped are
+// considered to contain every row.
+func MakeIndexPredicates(
+	desc *TableDescriptor, indexes []IndexDescriptor,
+) (*IndexPredicates, error) {
+	exprStrs := make([]string, len(indexes))
+	types := make([]parser.Type, len(indexes))
+	partial := false
+	for i := range indexes {
+		// The predicates of indexes being dropped are ignored: the columns
+		// they refer to may be dropped as well, and writing or deleting the
+		// entries of rows such an index does not contain is harmless.
+		if !indexes[i].IsPartial() || desc.isDroppingIndex(indexes[i].ID) {
+			continue
+		}
+		exprStrs[i] = indexes[i].Predicate
+		types[i] = parser.TypeBool
+		partial = true
+	}
+	if !partial {
+		return nil, nil
+	}
+	ip := &IndexPredicates{}
+	var err error
+	if ip.preds, err = ip.init(desc, exprStrs, types); err != nil {
+		return nil, err
+	}
+	return ip, nil
+}
+
+// ColumnIDs returns the IDs of the columns the predicates refer to. These
+// columns must be part of the rows passed to Filter.
+func (ip *IndexPredicates) ColumnIDs() []ColumnID {
+	if ip == nil {
+		return nil
+	}
+	return ip.exprColumnIDs(ip.preds)
+}
+
+// Close releases the resources of the IndexPredicates, which may be nil.
+func (ip *IndexPredicates) Close(ctx context.Context) {
+	if ip != nil {
+		ip.close(ctx)
+	}
+}
+
+// Filter clears the key of the entries of the partial indexes which do not
+// contain the given row, whose layout is described by colIDtoRowIndex. The
+// entries parallel the indexes the IndexPredicates was created for. A row is
+// contained in a partial index if the predicate evaluates to true; NULL is
+// treated like false.
+func (ip *IndexPredicates) Filter(
+	colIDtoRowIndex map[ColumnID]int, row parser.Datums, entries []IndexEntry,
+) error {
+	if ip == nil {
+		return nil
+	}
+	ip.setRow(colIDtoRowIndex, row)
+	defer ip.acc.Clear(ip.evalCtx.Ctx())
+	for i, pred := range ip.preds {
+		if pred == nil {
+			continue
+		}
+		d, err := pred.Eval(&ip.evalCtx)
+		if err != nil {
+			return err
+		}
+		if b, ok := d.(*parser.DBool); !ok || !bool(*b) {
+			entries[i] = IndexEntry{}
+		}
+	}
+	return nil
+}
@@ -80,6 +80,44 @@ func (p *PrivilegeDescriptor) removeUser(user string) {
 	p.Users = append(p.Users[:idx], p.Users[idx+1:]...)
 }
 
+// findColumnIndex looks for a given column and returns its index in the
+// Columns array if found. Returns -1 otherwise.
+func (p PrivilegeDescriptor) findColumnIndex(id ColumnID) int {
+	idx := sort.Search(len(p.Columns), func(i int) bool {
+		return p.Columns[i].ColumnID >= id
+	})
+	if idx < len(p.Columns) && p.Columns[idx].ColumnID == id {
+		return idx
+	}
+	return -1
+}
+
+// findOrCreateColumn looks for a specific column in the list, creating it if
+// needed.
+func (p *PrivilegeDescriptor) findOrCreateColumn(id ColumnID) *ColumnPrivileges {
+	idx := sort.Search(len(p.Columns), func(i int) bool {
+		return p.Columns[i].ColumnID >= id
+	})
+	if idx == len(p.Columns) {
+		p.Columns = append(p.Columns, ColumnPrivileges{ColumnID: id})
+	} else if p.Columns[idx].ColumnID != id {
+		p.Columns = append(p.Columns, ColumnPrivileges{})
+		copy(p.Columns[idx+1:], p.Columns[idx:])
+		p.Columns[idx] = ColumnPrivileges{ColumnID: id}
+	}
+	return &p.Columns[idx]
+}
+
+// findColumnUser looks for the privileges of a user on a column.
+// Returns (nil, false) if not found, or (obj, true) if found.
+func (p PrivilegeDescriptor) findColumnUser(id ColumnID, user string) (*UserPrivileges, bool) {
+	idx := p.findColumnIndex(id)
+	if idx == -1 {
+		return nil, false
+	}
+	return PrivilegeDescriptor{Users: p.Columns[idx].Users}.findUser(user)
+}
+
 // NewPrivilegeDescriptor returns a privilege descriptor for the given
 // user with the specified list of privileges.
 func NewPrivilegeDescriptor(user string, priv privilege.List) *PrivilegeDescriptor {
@@ -128,7 +166,12 @@ func (p *PrivilegeDescriptor) Grant(user string, privList privilege.List) {
 }
 
 // Revoke removes privileges from this descriptor for a given list of users.
+// The privileges are also revoked on all the columns.
 func (p *PrivilegeDescriptor) Revoke(user string, privList privilege.List) {
+	for i := len(p.Columns) - 1; i >= 0; i-- {
+		p.RevokeColumn(p.Columns[i].ColumnID, user, privList)
+	}
+
 	userPriv, ok := p.findUser(user)
 	if !ok || userPriv.Privileges == 0 {
 		// Removing privileges from a user without privileges is a no-op.
@@ -163,6 +206,62 @@ func (p *PrivilegeDescriptor) Revoke(user string, privList privilege.List) {
 	}
 }
 
+// AllowedColumnPrivileges is the list of privileges which can be granted on
+// columns. Granting ALL privileges on a column grants all of these.
+var AllowedColumnPrivileges = privilege.List{privilege.SELECT, privilege.UPDATE}
+
+// columnPrivilegeBits returns the bitfield of privileges to grant or revoke
+// on a column, expanding ALL to AllowedColumnPrivileges.
+func columnPrivilegeBits(privList privilege.List) uint32 {
+	bits := privList.ToBitField()
+	if isPrivilegeSet(bits, privilege.ALL) {
+		return AllowedColumnPrivileges.ToBitField()
+	}
+	return bits
+}
+
+// GrantColumn adds new privileges on a column of this descriptor for a
+// given user.
+func (p *PrivilegeDescriptor) GrantColumn(id ColumnID, user string, privList privilege.List) {
+	colPriv := p.findOrCreateColumn(id)
+	users := PrivilegeDescriptor{Users: colPriv.Users}
+	users.findOrCreateUser(user).Privileges |= columnPrivilegeBits(privList)
+	colPriv.Users = users.Users
+}
+
+// RevokeColumn removes privileges on a column of this descriptor for a
+// given user.
+func (p *PrivilegeDescriptor) RevokeColumn(id ColumnID, user string, privList privilege.List) {
+	idx := p.findColumnIndex(id)
+	if idx == -1 {
+		// No privileges on this column.
+		return
+	}
+	users := PrivilegeDescriptor{Users: p.Columns[idx].Users}
+	userPriv, ok := users.findUser(user)
+	if !ok {
+		return
+	}
+	userPriv.Privileges &^= columnPrivilegeBits(privList)
+	if userPriv.Privileges == 0 {
+		users.removeUser(user)
+	}
+	p.Columns[idx].Users = users.Users
+	if len(users.Users) == 0 {
+		p.RemoveColumn(id)
+	}
+}
+
+// RemoveColumn removes all the privileges on a column, e.g. when the column
+// is dropped.
+func (p *PrivilegeDescriptor) RemoveColumn(id ColumnID) {
+	idx := p.findColumnIndex(id)
+	if idx == -1 {
+		return
+	}
+	p.Columns = append(p.Columns[:idx], p.Columns[idx+1:]...)
+}
+
 // Validate is called when writing a database or table descriptor.
 // It takes the descriptor ID which is used to determine if
 // it belongs to a system descriptor, in which case the maximum
@@ -185,6 +284,11 @@ func (p PrivilegeDescriptor) Validate(id ID) error {
 				security.RootUser, allowedPrivileges)
 		}
 
+		// Privileges cannot be granted on the columns of system tables.
+		if len(p.Columns) > 0 {
+			return fmt.Errorf("privileges must not be granted on the columns of this system object")
+		}
+
 		// For all users, no other privileges must be granted.
 		if !isPrivilegeSet(rootPriv.Privileges, privilege.ALL) {
 			for _, u := range p.Users {
@@ -199,6 +303,19 @@ func (p PrivilegeDescriptor) Validate(id ID) error {
 		// privileges for the root user.
 		return fmt.Errorf("user %s does not have ALL privileges", security.RootUser)
 	}
+
+	allowedColumnPrivileges := AllowedColumnPrivileges.ToBitField()
+	for i, c := range p.Columns {
+		if i > 0 && p.Columns[i-1].ColumnID >= c.ColumnID {
+			return fmt.Errorf("column privileges are not sorted by column ID")
+		}
+		for _, u := range c.Users {
+			if remaining := u.Privileges &^ allowedColumnPrivileges; remaining != 0 {
+				return fmt.Errorf("user %s must not have %s privileges on column %d",
+					u.User, privilege.ListFromBitField(remaining), c.ColumnID)
+			}
+		}
+	}
 	return nil
 }
 
@@ -241,11 +358,53 @@ func (p PrivilegeDescriptor) CheckPrivilege(user string, priv privilege.Kind) bo
 	return isPrivilegeSet(userPriv.Privileges, priv)
 }
 
-// AnyPrivilege returns true if 'user' has any privilege on this descriptor.
-func (p PrivilegeDescriptor) AnyPrivilege(user string) bool {
-	userPriv, ok := p.findUser(user)
+// ShowColumn returns the list of {username, privileges} on a column sorted
+// by username.
+func (p PrivilegeDescriptor) ShowColumn(id ColumnID) []UserPrivilegeString {
+	idx := p.findColumnIndex(id)
+	if idx == -1 {
+		return nil
+	}
+	return PrivilegeDescriptor{Users: p.Columns[idx].Users}.Show()
+}
+
+// CheckColumnPrivilege returns true if 'user' has 'privilege' on a column of
+// this descriptor, either because it was granted on the column or on the
+// whole table.
+func (p PrivilegeDescriptor) CheckColumnPrivilege(
+	user string, priv privilege.Kind, id ColumnID,
+) bool {
+	if p.CheckPrivilege(user, priv) {
+		return true
+	}
+	userPriv, ok := p.findColumnUser(id, user)
 	if !ok {
 		return false
 	}
-	return userPriv.Privileges != 0
+	return isPrivilegeSet(userPriv.Privileges, priv)
+}
+
+// AnyColumnPrivilege returns true if 'user' has 'privilege' on at least one
+// column of this descriptor.
+func (p PrivilegeDescriptor) AnyColumnPrivilege(user string, priv privilege.Kind) bool {
+	for _, c := range p.Columns {
+		if p.CheckColumnPrivilege(user, priv, c.ColumnID) {
+			return true
+		}
+	}
+	return false
+}
+
+// AnyPrivilege returns true if 'user' has any privilege on this descriptor,
+// or on any of its columns.
+func (p PrivilegeDescriptor) AnyPrivilege(user string) bool {
+	if userPriv, ok := p.findUser(user); ok && userPriv.Privileges != 0 {
+		return true
+	}
+	for _, c := range p.Columns {
+		if (PrivilegeDescriptor{Users: c.Users}).AnyPrivilege(user) {
+			return true
+		}
+	}
+	return false
 }
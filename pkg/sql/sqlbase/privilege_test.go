@@ -161,6 +161,93 @@ func TestAnyPrivilege(t *testing.T) {
 	}
 }
 
+func TestColumnPrivilege(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	descriptor := NewDefaultPrivilegeDescriptor()
+	descriptor.Grant("bar", privilege.List{privilege.UPDATE})
+
+	descriptor.GrantColumn(2, "foo", privilege.List{privilege.SELECT})
+	descriptor.GrantColumn(1, "foo", privilege.List{privilege.ALL})
+	descriptor.GrantColumn(1, "bar", privilege.List{privilege.SELECT})
+
+	testCases := []struct {
+		user string
+		priv privilege.Kind
+		id   ColumnID
+		exp  bool
+	}{
+		{"foo", privilege.SELECT, 1, true},
+		{"foo", privilege.UPDATE, 1, true},
+		{"foo", privilege.SELECT, 2, true},
+		{"foo", privilege.UPDATE, 2, false},
+		{"foo", privilege.SELECT, 3, false},
+		{"foo", privilege.DELETE, 1, false},
+		{"bar", privilege.SELECT, 1, true},
+		{"bar", privilege.SELECT, 2, false},
+		// Table-level privileges apply to all the columns.
+		{"bar", privilege.UPDATE, 3, true},
+		{security.RootUser, privilege.SELECT, 3, true},
+		{"baz", privilege.SELECT, 1, false},
+	}
+	for tcNum, tc := range testCases {
+		if found := descriptor.CheckColumnPrivilege(tc.user, tc.priv, tc.id); found != tc.exp {
+			t.Errorf("#%d: CheckColumnPrivilege(%s, %v, %d) for descriptor %+v = %t, expected %t",
+				tcNum, tc.user, tc.priv, tc.id, descriptor, found, tc.exp)
+		}
+	}
+
+	if !descriptor.AnyPrivilege("foo") {
+		t.Errorf("expected foo to have privileges on descriptor %+v", descriptor)
+	}
+	if err := descriptor.Validate(ID(keys.MaxReservedDescID + 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The columns are sorted, and ALL is expanded to the column privileges.
+	if len(descriptor.Columns) != 2 || descriptor.Columns[0].ColumnID != 1 ||
+		descriptor.Columns[1].ColumnID != 2 {
+		t.Fatalf("unexpected column privileges %+v", descriptor.Columns)
+	}
+	show := descriptor.ShowColumn(1)
+	if len(show) != 2 || show[0].User != "bar" || show[0].PrivilegeString() != "SELECT" ||
+		show[1].User != "foo" || show[1].PrivilegeString() != "SELECT,UPDATE" {
+		t.Fatalf("unexpected privileges on column 1: %+v", show)
+	}
+
+	// Revoking a privilege on the table revokes it on all the columns.
+	descriptor.Revoke("foo", privilege.List{privilege.SELECT})
+	if descriptor.CheckColumnPrivilege("foo", privilege.SELECT, 1) ||
+		!descriptor.CheckColumnPrivilege("foo", privilege.UPDATE, 1) {
+		t.Fatalf("unexpected column privileges %+v", descriptor.Columns)
+	}
+	if len(descriptor.Columns) != 1 {
+		t.Fatalf("expected column 2 to have no privileges, got %+v", descriptor.Columns)
+	}
+
+	descriptor.RevokeColumn(1, "foo", privilege.List{privilege.ALL})
+	descriptor.RevokeColumn(1, "bar", privilege.List{privilege.SELECT})
+	if len(descriptor.Columns) != 0 {
+		t.Fatalf("expected no column privileges, got %+v", descriptor.Columns)
+	}
+	if descriptor.AnyPrivilege("foo") {
+		t.Errorf("expected foo to have no privileges on descriptor %+v", descriptor)
+	}
+
+	descriptor.GrantColumn(1, "foo", privilege.List{privilege.SELECT})
+	descriptor.RemoveColumn(1)
+	if len(descriptor.Columns) != 0 {
+		t.Fatalf("expected no column privileges, got %+v", descriptor.Columns)
+	}
+
+	// Only SELECT and UPDATE can be granted on columns.
+	descriptor.GrantColumn(1, "foo", privilege.List{privilege.INSERT})
+	if err := descriptor.Validate(ID(keys.MaxReservedDescID + 1)); !testutils.IsError(
+		err, "user foo must not have INSERT privileges on column 1",
+	) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
 // TestPrivilegeValidate exercises validation for non-system descriptors.
 func TestPrivilegeValidate(t *testing.T) {
 	defer leaktest.AfterTest(t)()
@@ -33,6 +33,10 @@ type ResultColumn struct {
 
 	// If set, a value won't be produced for this column; used internally.
 	Omitted bool
+
+	// If set, the user does not have the privilege to read this column, which
+	// cannot be referenced by queries.
+	Denied bool
 }
 
 // ResultColumns is the type used throughout the sql module to
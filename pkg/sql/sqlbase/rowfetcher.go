@@ -210,6 +210,19 @@ func (rf *RowFetcher) StartScan(
 	return err
 }
 
+// StartScanFrom initializes a scan over kvs, which must be sorted and must not
+// split the keys of a row, instead of over the KV layer. This is used to
+// decode rows that were read from somewhere other than the cluster, such as a
+// backup. Can be used multiple times.
+func (rf *RowFetcher) StartScanFrom(ctx context.Context, kvs []client.KeyValue) error {
+	rf.indexKey = nil
+	rf.kvFetcher = kvFetcher{kvs: kvs, fetchEnd: true}
+
+	// Retrieve the first key.
+	_, err := rf.NextKey(ctx)
+	return err
+}
+
 // NextKey retrieves the next key/value and sets kv/kvEnd. Returns whether a row
 // has been completed.
 // TODO(andrei): change to return error
@@ -45,6 +45,9 @@ type rowHelper struct {
 	TableDesc    *TableDescriptor
 	Indexes      []IndexDescriptor
 	indexEntries []IndexEntry
+	// predicates determines which rows the partial indexes among Indexes
+	// contain. It is nil if there are none.
+	predicates *IndexPredicates
 
 	// Computed and cached.
 	primaryIndexKeyPrefix []byte
@@ -52,6 +55,20 @@ type rowHelper struct {
 	sortedColumnFamilies  map[FamilyID][]ColumnID
 }
 
+// makeRowHelper returns a rowHelper for the given indexes of the table.
+func makeRowHelper(tableDesc *TableDescriptor, indexes []IndexDescriptor) (rowHelper, error) {
+	predicates, err := MakeIndexPredicates(tableDesc, indexes)
+	if err != nil {
+		return rowHelper{}, err
+	}
+	return rowHelper{TableDesc: tableDesc, Indexes: indexes, predicates: predicates}, nil
+}
+
+// close releases the resources of the rowHelper.
+func (rh *rowHelper) close(ctx context.Context) {
+	rh.predicates.Close(ctx)
+}
+
 // encodeIndexes encodes the primary and secondary index keys. The
 // secondaryIndexEntries are only valid until the next call to encodeIndexes or
 // encodeSecondaryIndexes.
@@ -76,7 +93,8 @@ func (rh *rowHelper) encodeIndexes(
 
 // encodeSecondaryIndexes encodes the secondary index keys. The
 // secondaryIndexEntries are only valid until the next call to encodeIndexes or
-// encodeSecondaryIndexes.
+// encodeSecondaryIndexes. The entries of partial indexes which do not contain
+// the row have a nil key.
 func (rh *rowHelper) encodeSecondaryIndexes(
 	colIDtoRowIndex map[ColumnID]int, values []parser.Datum,
 ) (secondaryIndexEntries []IndexEntry, err error) {
@@ -88,6 +106,9 @@ func (rh *rowHelper) encodeSecondaryIndexes(
 	if err != nil {
 		return nil, err
 	}
+	if err := rh.predicates.Filter(colIDtoRowIndex, values, rh.indexEntries); err != nil {
+		return nil, err
+	}
 	return rh.indexEntries, nil
 }
 
@@ -147,11 +168,19 @@ type RowInserter struct {
 	InsertColIDtoRowIndex map[ColumnID]int
 	Fks                   fkInsertHelper
 
+	// insertCols and insertColIDtoRowIndex extend InsertCols with the hidden
+	// columns which back the expression elements of the table's indexes, and
+	// indexExprs computes them.
+	insertCols            []ColumnDescriptor
+	insertColIDtoRowIndex map[ColumnID]int
+	indexExprs            *indexExprHelper
+
 	// For allocation avoidance.
-	marshalled []roachpb.Value
-	key        roachpb.Key
-	valueBuf   []byte
-	value      roachpb.Value
+	insertValues parser.Datums
+	marshalled   []roachpb.Value
+	key          roachpb.Key
+	valueBuf     []byte
+	value        roachpb.Value
 }
 
 // MakeRowInserter creates a RowInserter for the given table.
@@ -175,11 +204,14 @@ func MakeRowInserter(
 		}
 	}
 
+	helper, err := makeRowHelper(tableDesc, indexes)
+	if err != nil {
+		return RowInserter{}, err
+	}
 	ri := RowInserter{
-		Helper:                rowHelper{TableDesc: tableDesc, Indexes: indexes},
+		Helper:                helper,
 		InsertCols:            insertCols,
 		InsertColIDtoRowIndex: ColIDtoRowIndexFromCols(insertCols),
-		marshalled:            make([]roachpb.Value, len(insertCols)),
 	}
 
 	for i, col := range tableDesc.PrimaryIndex.ColumnIDs {
@@ -188,8 +220,24 @@ func MakeRowInserter(
 		}
 	}
 
+	ri.insertCols, ri.insertColIDtoRowIndex = ri.InsertCols, ri.InsertColIDtoRowIndex
+	if exprCols, exprs := tableDesc.IndexExprColumns(); len(exprCols) > 0 {
+		ri.insertCols = insertCols[:len(insertCols):len(insertCols)]
+		for _, col := range exprCols {
+			if _, ok := ri.InsertColIDtoRowIndex[col.ID]; !ok {
+				ri.insertCols = append(ri.insertCols, col)
+			}
+		}
+		ri.insertColIDtoRowIndex = ColIDtoRowIndexFromCols(ri.insertCols)
+		ri.indexExprs = &indexExprHelper{}
+		if err := ri.indexExprs.init(tableDesc, exprCols, exprs); err != nil {
+			return RowInserter{}, err
+		}
+		ri.insertValues = make(parser.Datums, len(ri.insertCols))
+	}
+	ri.marshalled = make([]roachpb.Value, len(ri.insertCols))
+
 	if checkFKs {
-		var err error
 		if ri.Fks, err = makeFKInsertHelper(txn, *tableDesc, fkTables, ri.InsertColIDtoRowIndex); err != nil {
 			return ri, err
 		}
@@ -197,6 +245,13 @@ func MakeRowInserter(
 	return ri, nil
 }
 
+// Close releases the resources of the RowInserter. It must be called once
+// the RowInserter is no longer in use.
+func (ri *RowInserter) Close(ctx context.Context) {
+	ri.Helper.close(ctx)
+	ri.indexExprs.close(ctx)
+}
+
 // insertCPutFn is used by insertRow when conflicts (i.e. the key already exists)
 // should generate errors.
 func insertCPutFn(
@@ -233,6 +288,13 @@ func (ri *RowInserter) InsertRow(
 	if len(values) != len(ri.InsertCols) {
 		return errors.Errorf("got %d values but expected %d", len(values), len(ri.InsertCols))
 	}
+	if ri.indexExprs != nil {
+		copy(ri.insertValues, values)
+		if err := ri.indexExprs.eval(ri.insertColIDtoRowIndex, ri.insertValues); err != nil {
+			return err
+		}
+		values = ri.insertValues
+	}
 
 	putFn := insertCPutFn
 	if ignoreConflicts {
@@ -245,7 +307,7 @@ func (ri *RowInserter) InsertRow(
 	for i, val := range values {
 		// Make sure the value can be written to the column before proceeding.
 		var err error
-		if ri.marshalled[i], err = MarshalColumnValue(ri.InsertCols[i], val); err != nil {
+		if ri.marshalled[i], err = MarshalColumnValue(ri.insertCols[i], val); err != nil {
 			return err
 		}
 	}
@@ -254,7 +316,7 @@ func (ri *RowInserter) InsertRow(
 		return err
 	}
 
-	primaryIndexKey, secondaryIndexEntries, err := ri.Helper.encodeIndexes(ri.InsertColIDtoRowIndex, values)
+	primaryIndexKey, secondaryIndexEntries, err := ri.Helper.encodeIndexes(ri.insertColIDtoRowIndex, values)
 	if err != nil {
 		return err
 	}
@@ -275,7 +337,7 @@ func (ri *RowInserter) InsertRow(
 			// Storage optimization to store DefaultColumnID directly as a value. Also
 			// backwards compatible with the original BaseFormatVersion.
 
-			idx, ok := ri.InsertColIDtoRowIndex[family.DefaultColumnID]
+			idx, ok := ri.insertColIDtoRowIndex[family.DefaultColumnID]
 			if !ok {
 				continue
 			}
@@ -302,7 +364,7 @@ func (ri *RowInserter) InsertRow(
 			panic("invalid family sorted column id map")
 		}
 		for _, colID := range familySortedColumnIDs {
-			idx, ok := ri.InsertColIDtoRowIndex[colID]
+			idx, ok := ri.insertColIDtoRowIndex[colID]
 			if !ok || values[idx] == parser.DNull {
 				// Column not being inserted.
 				continue
@@ -314,7 +376,7 @@ func (ri *RowInserter) InsertRow(
 				continue
 			}
 
-			col := ri.InsertCols[idx]
+			col := ri.insertCols[idx]
 
 			if lastColID > col.ID {
 				panic(fmt.Errorf("cannot write column id %d after %d", col.ID, lastColID))
@@ -337,6 +399,10 @@ func (ri *RowInserter) InsertRow(
 
 	for i := range secondaryIndexEntries {
 		e := &secondaryIndexEntries[i]
+		if e.Key == nil {
+			// The row is not part of this partial index.
+			continue
+		}
 		putFn(ctx, b, &e.Key, &e.Value, traceKV)
 	}
 
@@ -353,6 +419,13 @@ type RowUpdater struct {
 	deleteOnlyIndex       map[int]struct{}
 	primaryKeyColChange   bool
 
+	// updateCols extends UpdateCols with the hidden columns which back
+	// expression elements of the table's indexes and must be recomputed
+	// because their source columns are being updated. indexExprs computes
+	// them.
+	updateCols []ColumnDescriptor
+	indexExprs *indexExprHelper
+
 	rd RowDeleter
 	ri RowInserter
 
@@ -361,6 +434,7 @@ type RowUpdater struct {
 	// For allocation avoidance.
 	marshalled      []roachpb.Value
 	newValues       []parser.Datum
+	updateValues    []parser.Datum
 	key             roachpb.Key
 	indexEntriesBuf []IndexEntry
 	valueBuf        []byte
@@ -394,8 +468,6 @@ func MakeRowUpdater(
 	requestedCols []ColumnDescriptor,
 	updateType rowUpdaterType,
 ) (RowUpdater, error) {
-	updateColIDtoRowIndex := ColIDtoRowIndexFromCols(updateCols)
-
 	primaryIndexCols := make(map[ColumnID]struct{}, len(tableDesc.PrimaryIndex.ColumnIDs))
 	for _, colID := range tableDesc.PrimaryIndex.ColumnIDs {
 		primaryIndexCols[colID] = struct{}{}
@@ -409,27 +481,58 @@ func MakeRowUpdater(
 		}
 	}
 
+	// When the primary key changes the row is reinserted, and the RowInserter
+	// computes the hidden columns of expression indexes itself.
+	allUpdateCols := updateCols
+	var indexExprs *indexExprHelper
+	if !primaryKeyColChange {
+		var err error
+		allUpdateCols, indexExprs, err = makeIndexExprUpdateHelper(tableDesc, updateCols)
+		if err != nil {
+			return RowUpdater{}, err
+		}
+	}
+	updateColIDtoRowIndex := ColIDtoRowIndexFromCols(allUpdateCols)
+
 	// Secondary indexes needing updating.
-	needsUpdate := func(index IndexDescriptor) bool {
+	needsUpdate := func(index IndexDescriptor) (bool, error) {
 		if updateType == RowUpdaterOnlyColumns {
 			// Only update columns.
-			return false
+			return false, nil
 		}
 		// If the primary key changed, we need to update all of them.
 		if primaryKeyColChange {
-			return true
+			return true, nil
 		}
-		return index.RunOverAllColumns(func(id ColumnID) error {
+		if index.RunOverAllColumns(func(id ColumnID) error {
 			if _, ok := updateColIDtoRowIndex[id]; ok {
 				return returnTruePseudoError
 			}
 			return nil
-		}) != nil
+		}) != nil {
+			return true, nil
+		}
+		// A row can enter or leave a partial index when the columns of the
+		// predicate are updated.
+		if index.IsPartial() && !tableDesc.isDroppingIndex(index.ID) {
+			predCols, err := tableDesc.IndexExprSourceColumnIDs(index.Predicate)
+			if err != nil {
+				return false, err
+			}
+			for _, id := range predCols {
+				if _, ok := updateColIDtoRowIndex[id]; ok {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
 	}
 
 	indexes := make([]IndexDescriptor, 0, len(tableDesc.Indexes)+len(tableDesc.Mutations))
 	for _, index := range tableDesc.Indexes {
-		if needsUpdate(index) {
+		if needed, err := needsUpdate(index); err != nil {
+			return RowUpdater{}, err
+		} else if needed {
 			indexes = append(indexes, index)
 		}
 	}
@@ -437,7 +540,11 @@ func MakeRowUpdater(
 	var deleteOnlyIndex map[int]struct{}
 	for _, m := range tableDesc.Mutations {
 		if index := m.GetIndex(); index != nil {
-			if needsUpdate(*index) {
+			needed, err := needsUpdate(*index)
+			if err != nil {
+				return RowUpdater{}, err
+			}
+			if needed {
 				indexes = append(indexes, *index)
 
 				switch m.State {
@@ -454,19 +561,25 @@ func MakeRowUpdater(
 		}
 	}
 
+	helper, err := makeRowHelper(tableDesc, indexes)
+	if err != nil {
+		return RowUpdater{}, err
+	}
 	ru := RowUpdater{
-		Helper:                rowHelper{TableDesc: tableDesc, Indexes: indexes},
+		Helper:                helper,
 		UpdateCols:            updateCols,
 		updateColIDtoRowIndex: updateColIDtoRowIndex,
 		deleteOnlyIndex:       deleteOnlyIndex,
 		primaryKeyColChange:   primaryKeyColChange,
-		marshalled:            make([]roachpb.Value, len(updateCols)),
+		updateCols:            allUpdateCols,
+		indexExprs:            indexExprs,
+		marshalled:            make([]roachpb.Value, len(allUpdateCols)),
 		newValues:             make([]parser.Datum, len(tableDesc.Columns)+len(tableDesc.Mutations)),
 	}
 
 	if primaryKeyColChange {
 		// These fields are only used when the primary key is changing.
-		var err error
+		//
 		// When changing the primary key, we delete the old values and reinsert
 		// them, so request them all.
 		if ru.rd, err = MakeRowDeleter(txn, tableDesc, fkTables, tableDesc.Columns, SkipFKs); err != nil {
@@ -518,9 +631,20 @@ func MakeRowUpdater(
 				return RowUpdater{}, err
 			}
 		}
+		if indexExprs != nil {
+			for _, colID := range indexExprs.sourceColumnIDs(-1) {
+				if err := maybeAddCol(colID); err != nil {
+					return RowUpdater{}, err
+				}
+			}
+		}
+		for _, colID := range ru.Helper.predicates.ColumnIDs() {
+			if err := maybeAddCol(colID); err != nil {
+				return RowUpdater{}, err
+			}
+		}
 	}
 
-	var err error
 	if ru.Fks, err = makeFKUpdateHelper(txn, *tableDesc, fkTables, ru.FetchColIDtoRowIndex); err != nil {
 		return RowUpdater{}, err
 	}
@@ -559,18 +683,35 @@ func (ru *RowUpdater) UpdateRow(
 	secondaryIndexEntries = append(ru.indexEntriesBuf[:0], secondaryIndexEntries...)
 	ru.indexEntriesBuf = secondaryIndexEntries
 
+	if ru.indexExprs != nil {
+		// Compute the hidden columns of expression indexes from the updated row
+		// and update them along with the other columns.
+		copy(ru.newValues, oldValues)
+		for i, updateCol := range ru.UpdateCols {
+			ru.newValues[ru.FetchColIDtoRowIndex[updateCol.ID]] = updateValues[i]
+		}
+		if err := ru.indexExprs.eval(ru.FetchColIDtoRowIndex, ru.newValues); err != nil {
+			return nil, err
+		}
+		ru.updateValues = ru.updateValues[:0]
+		for _, updateCol := range ru.updateCols {
+			ru.updateValues = append(ru.updateValues, ru.newValues[ru.FetchColIDtoRowIndex[updateCol.ID]])
+		}
+		updateValues = ru.updateValues
+	}
+
 	// Check that the new value types match the column types. This needs to
 	// happen before index encoding because certain datum types (i.e. tuple)
 	// cannot be used as index values.
 	for i, val := range updateValues {
-		if ru.marshalled[i], err = MarshalColumnValue(ru.UpdateCols[i], val); err != nil {
+		if ru.marshalled[i], err = MarshalColumnValue(ru.updateCols[i], val); err != nil {
 			return nil, err
 		}
 	}
 
 	// Update the row values.
 	copy(ru.newValues, oldValues)
-	for i, updateCol := range ru.UpdateCols {
+	for i, updateCol := range ru.updateCols {
 		ru.newValues[ru.FetchColIDtoRowIndex[updateCol.ID]] = updateValues[i]
 	}
 
@@ -719,15 +860,23 @@ func (ru *RowUpdater) UpdateRow(
 				return nil, err
 			}
 
-			if traceKV {
-				log.VEventf(ctx, 2, "Del %s", secondaryIndexEntry.Key)
+			// The old key is nil if the old row was not part of this partial
+			// index.
+			if secondaryIndexEntry.Key != nil {
+				if traceKV {
+					log.VEventf(ctx, 2, "Del %s", secondaryIndexEntry.Key)
+				}
+				b.Del(secondaryIndexEntry.Key)
 			}
-			b.Del(secondaryIndexEntry.Key)
 		} else if !bytes.Equal(newSecondaryIndexEntry.Value.RawBytes, secondaryIndexEntry.Value.RawBytes) {
 			expValue = &secondaryIndexEntry.Value
 		} else {
 			continue
 		}
+		if newSecondaryIndexEntry.Key == nil {
+			// The new row is not part of this partial index.
+			continue
+		}
 		// Do not update Indexes in the DELETE_ONLY state.
 		if _, ok := ru.deleteOnlyIndex[i]; !ok {
 			if traceKV {
@@ -740,6 +889,15 @@ func (ru *RowUpdater) UpdateRow(
 	return ru.newValues, nil
 }
 
+// Close releases the resources of the RowUpdater. It must be called once
+// the RowUpdater is no longer in use.
+func (ru *RowUpdater) Close(ctx context.Context) {
+	ru.Helper.close(ctx)
+	ru.indexExprs.close(ctx)
+	ru.rd.Close(ctx)
+	ru.ri.Close(ctx)
+}
+
 // IsColumnOnlyUpdate returns true if this RowUpdater is only updating column
 // data (in contrast to updating the primary key or other indexes).
 func (ru *RowUpdater) IsColumnOnlyUpdate() bool {
@@ -814,14 +972,22 @@ func MakeRowDeleter(
 			}
 		}
 	}
+	helper, err := makeRowHelper(tableDesc, indexes)
+	if err != nil {
+		return RowDeleter{}, err
+	}
+	for _, colID := range helper.predicates.ColumnIDs() {
+		if err := maybeAddCol(colID); err != nil {
+			return RowDeleter{}, err
+		}
+	}
 
 	rd := RowDeleter{
-		Helper:               rowHelper{TableDesc: tableDesc, Indexes: indexes},
+		Helper:               helper,
 		FetchCols:            fetchCols,
 		FetchColIDtoRowIndex: fetchColIDtoRowIndex,
 	}
 	if checkFKs {
-		var err error
 		if rd.Fks, err = makeFKDeleteHelper(txn, *tableDesc, fkTables, fetchColIDtoRowIndex); err != nil {
 			return RowDeleter{}, err
 		}
@@ -830,6 +996,12 @@ func MakeRowDeleter(
 	return rd, nil
 }
 
+// Close releases the resources of the RowDeleter. It must be called once
+// the RowDeleter is no longer in use.
+func (rd *RowDeleter) Close(ctx context.Context) {
+	rd.Helper.close(ctx)
+}
+
 // DeleteRow adds to the batch the kv operations necessary to delete a table row
 // with the given values.
 func (rd *RowDeleter) DeleteRow(
@@ -845,6 +1017,10 @@ func (rd *RowDeleter) DeleteRow(
 	}
 
 	for _, secondaryIndexEntry := range secondaryIndexEntries {
+		if secondaryIndexEntry.Key == nil {
+			// The row is not part of this partial index.
+			continue
+		}
 		if traceKV {
 			log.VEventf(ctx, 2, "Del %s", secondaryIndexEntry.Key)
 		}
@@ -206,6 +206,9 @@ func (desc *IndexDescriptor) FillColumns(elems parser.IndexElemList) error {
 	desc.ColumnNames = make([]string, 0, len(elems))
 	desc.ColumnDirections = make([]IndexDescriptor_Direction, 0, len(elems))
 	for _, c := range elems {
+		if c.Expr != nil {
+			return fmt.Errorf("expression %s not supported in this index", c.Expr)
+		}
 		desc.ColumnNames = append(desc.ColumnNames, string(c.Column))
 		switch c.Direction {
 		case parser.Ascending, parser.DefaultDirection:
@@ -271,11 +274,30 @@ func (desc *IndexDescriptor) ColNamesString() string {
 		if i > 0 {
 			buf.WriteString(", ")
 		}
+		if expr := desc.ColumnExpr(i); expr != nil {
+			fmt.Fprintf(&buf, "%s %s", parser.AsString(parser.IndexElem{Expr: expr}), desc.ColumnDirections[i])
+			continue
+		}
 		fmt.Fprintf(&buf, "%s %s", parser.Name(name), desc.ColumnDirections[i])
 	}
 	return buf.String()
 }
 
+// ColumnExpr returns the parsed expression of the i-th element of the index,
+// or nil if the element is a plain column.
+func (desc *IndexDescriptor) ColumnExpr(i int) parser.Expr {
+	if i >= len(desc.ColumnExprs) || desc.ColumnExprs[i] == "" {
+		return nil
+	}
+	expr, err := parser.ParseExpr(desc.ColumnExprs[i])
+	if err != nil {
+		// The expression was serialized by us, so this should not happen; show
+		// the hidden column instead.
+		return nil
+	}
+	return expr
+}
+
 var isUnique = map[bool]string{true: "UNIQUE "}
 
 // SQLString returns the SQL string describing this index. If non-empty,
@@ -1060,10 +1082,31 @@ func (desc *TableDescriptor) ValidateTable() error {
 		}
 	}
 
+	if err := desc.validatePolicies(); err != nil {
+		return err
+	}
+
 	// Validate the privilege descriptor.
 	return desc.Privileges.Validate(desc.GetID())
 }
 
+func (desc *TableDescriptor) validatePolicies() error {
+	if (desc.RowLevelSecurity || len(desc.Policies) > 0) && !desc.IsTable() {
+		return fmt.Errorf("row level security is only supported on tables")
+	}
+	policyNames := map[string]struct{}{}
+	for _, policy := range desc.Policies {
+		if err := validateName(policy.Name, "policy"); err != nil {
+			return err
+		}
+		if _, ok := policyNames[policy.Name]; ok {
+			return fmt.Errorf("duplicate policy name: %q", policy.Name)
+		}
+		policyNames[policy.Name] = struct{}{}
+	}
+	return nil
+}
+
 func (desc *TableDescriptor) validateColumnFamilies(
 	columnIDs map[ColumnID]string,
 ) (map[ColumnID]FamilyID, error) {
@@ -1174,6 +1217,10 @@ func (desc *TableDescriptor) validateTableIndexes(
 			return fmt.Errorf("mismatched column IDs (%d) and directions (%d)",
 				len(index.ColumnIDs), len(index.ColumnDirections))
 		}
+		if len(index.ColumnExprs) != 0 && len(index.ColumnExprs) != len(index.ColumnNames) {
+			return fmt.Errorf("mismatched column expressions (%d) and names (%d)",
+				len(index.ColumnExprs), len(index.ColumnNames))
+		}
 
 		if len(index.ColumnIDs) == 0 {
 			return fmt.Errorf("index \"%s\" must contain at least 1 column", index.Name)
@@ -1214,7 +1261,7 @@ func upperBoundColumnValueEncodedSize(col ColumnDescriptor) (int, bool) {
 	case ColumnType_BOOL:
 		typ = encoding.True
 	case ColumnType_INT, ColumnType_DATE, ColumnType_TIMESTAMP,
-		ColumnType_TIMESTAMPTZ, ColumnType_OID:
+		ColumnType_TIMESTAMPTZ, ColumnType_OID, ColumnType_TIME:
 		typ, size = encoding.Int, int(col.Type.Width)
 	case ColumnType_FLOAT:
 		typ = encoding.Float
@@ -1226,6 +1273,10 @@ func upperBoundColumnValueEncodedSize(col ColumnDescriptor) (int, bool) {
 		typ, size = encoding.Bytes, int(col.Type.Width)
 	case ColumnType_DECIMAL:
 		typ, size = encoding.Decimal, int(col.Type.Precision)
+	case ColumnType_INET, ColumnType_CIDR:
+		typ = encoding.IPAddr
+	case ColumnType_BIT, ColumnType_VARBIT:
+		typ, size = encoding.BitArray, int(col.Type.Width)
 	default:
 		panic(errors.Errorf("unknown column type: %s", col.Type.SemanticType))
 	}
@@ -1607,6 +1658,9 @@ func (desc *TableDescriptor) MakeMutationComplete(m DescriptorMutation) {
 		switch t := m.Descriptor_.(type) {
 		case *DescriptorMutation_Column:
 			desc.RemoveColumnFromFamily(t.Column.ID)
+			if desc.Privileges != nil {
+				desc.Privileges.RemoveColumn(t.Column.ID)
+			}
 		}
 		// Nothing else to be done. The column/index was already removed from the
 		// set of column/index descriptors at mutation creation time.
@@ -1670,6 +1724,21 @@ func (desc *TableDescriptor) Renamed() bool {
 	return len(desc.Renames) > 0
 }
 
+// SetAuditMode configures the audit mode on the descriptor. It returns
+// whether the mode was changed.
+func (desc *TableDescriptor) SetAuditMode(mode parser.AuditMode) (bool, error) {
+	prev := desc.AuditMode
+	switch mode {
+	case parser.AuditModeDisable:
+		desc.AuditMode = TableDescriptor_DISABLED
+	case parser.AuditModeReadWrite:
+		desc.AuditMode = TableDescriptor_READWRITE
+	default:
+		return false, errors.Errorf("unknown audit mode: %d", mode)
+	}
+	return prev != desc.AuditMode, nil
+}
+
 // SetUpVersion sets the up_version marker on the table descriptor (see the proto
 func (desc *TableDescriptor) SetUpVersion() error {
 	if desc.Dropped() {
@@ -1731,6 +1800,12 @@ func (c *ColumnType) SQLString() string {
 		}
 	case ColumnType_TIMESTAMPTZ:
 		return "TIMESTAMP WITH TIME ZONE"
+	case ColumnType_BIT:
+		return fmt.Sprintf("%s(%d)", c.SemanticType.String(), c.Width)
+	case ColumnType_VARBIT:
+		if c.Width > 0 {
+			return fmt.Sprintf("%s(%d)", c.SemanticType.String(), c.Width)
+		}
 	case ColumnType_COLLATEDSTRING:
 		if c.Locale == nil {
 			panic("locale is required for COLLATEDSTRING")
@@ -1750,7 +1825,8 @@ func (c *ColumnType) SQLString() string {
 // type is not a character or bit string, or if the string's length is not bounded.
 func (c *ColumnType) MaxCharacterLength() (int32, bool) {
 	switch c.SemanticType {
-	case ColumnType_INT, ColumnType_STRING, ColumnType_COLLATEDSTRING:
+	case ColumnType_INT, ColumnType_STRING, ColumnType_COLLATEDSTRING,
+		ColumnType_BIT, ColumnType_VARBIT:
 		if c.Width > 0 {
 			return c.Width, true
 		}
@@ -1834,6 +1910,12 @@ func DatumTypeToColumnType(ptyp parser.Type) ColumnType {
 		ctyp.SemanticType = ColumnType_INTERVAL
 	case parser.TypeUUID:
 		ctyp.SemanticType = ColumnType_UUID
+	case parser.TypeINet:
+		ctyp.SemanticType = ColumnType_INET
+	case parser.TypeTime:
+		ctyp.SemanticType = ColumnType_TIME
+	case parser.TypeBitArray:
+		ctyp.SemanticType = ColumnType_VARBIT
 	case parser.TypeOid:
 		ctyp.SemanticType = ColumnType_OID
 	case parser.TypeNull:
@@ -1879,6 +1961,12 @@ func (c *ColumnType) ToDatumType() parser.Type {
 		return parser.TypeInterval
 	case ColumnType_UUID:
 		return parser.TypeUUID
+	case ColumnType_INET, ColumnType_CIDR:
+		return parser.TypeINet
+	case ColumnType_TIME:
+		return parser.TypeTime
+	case ColumnType_BIT, ColumnType_VARBIT:
+		return parser.TypeBitArray
 	case ColumnType_COLLATEDSTRING:
 		if c.Locale == nil {
 			panic("locale is required for COLLATEDSTRING")
@@ -588,6 +588,30 @@ func TestValidateTableDesc(t *testing.T) {
 				NextFamilyID: 1,
 				NextIndexID:  2,
 			}},
+		{`duplicate policy name: "baz"`,
+			TableDescriptor{
+				ID:            2,
+				ParentID:      1,
+				Name:          "foo",
+				FormatVersion: FamilyFormatVersion,
+				Columns: []ColumnDescriptor{
+					{ID: 1, Name: "bar"},
+				},
+				Families: []ColumnFamilyDescriptor{
+					{ID: 0, Name: "primary", ColumnIDs: []ColumnID{1}, ColumnNames: []string{"bar"}},
+				},
+				PrimaryIndex: IndexDescriptor{ID: 1, Name: "bar", ColumnIDs: []ColumnID{1},
+					ColumnNames:      []string{"bar"},
+					ColumnDirections: []IndexDescriptor_Direction{IndexDescriptor_ASC},
+				},
+				Policies: []TableDescriptor_Policy{
+					{Name: "baz"},
+					{Name: "baz", Command: TableDescriptor_Policy_SELECT},
+				},
+				NextColumnID: 2,
+				NextFamilyID: 1,
+				NextIndexID:  2,
+			}},
 	}
 	for i, d := range testData {
 		if err := d.desc.ValidateTable(); err == nil {
@@ -853,6 +877,12 @@ func TestColumnTypeSQLString(t *testing.T) {
 		{ColumnType{SemanticType: ColumnType_STRING}, "STRING"},
 		{ColumnType{SemanticType: ColumnType_STRING, Width: 10}, "STRING(10)"},
 		{ColumnType{SemanticType: ColumnType_BYTES}, "BYTES"},
+		{ColumnType{SemanticType: ColumnType_TIME}, "TIME"},
+		{ColumnType{SemanticType: ColumnType_INET}, "INET"},
+		{ColumnType{SemanticType: ColumnType_CIDR}, "CIDR"},
+		{ColumnType{SemanticType: ColumnType_BIT, Width: 3}, "BIT(3)"},
+		{ColumnType{SemanticType: ColumnType_VARBIT}, "VARBIT"},
+		{ColumnType{SemanticType: ColumnType_VARBIT, Width: 4}, "VARBIT(4)"},
 	}
 	for i, d := range testData {
 		sql := d.colType.SQLString()
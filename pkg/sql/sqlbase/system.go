@@ -123,6 +123,44 @@ CREATE TABLE system.jobs (
 	INDEX (status, created),
 	FAMILY (id, status, created, payload)
 );`
+
+	SchedulesTableSchema = `
+CREATE TABLE system.schedules (
+	id                INT       DEFAULT unique_rowid() PRIMARY KEY,
+	status            STRING    NOT NULL,
+	created           TIMESTAMP NOT NULL DEFAULT now(),
+	next_run          TIMESTAMP NOT NULL,
+	payload           BYTES     NOT NULL,
+	FAMILY (id, status, created, next_run, payload)
+);`
+
+	RolesTableSchema = `
+CREATE TABLE system.roles (
+	name              STRING    PRIMARY KEY
+);`
+
+	// role_members records which users and roles are members of each role.
+	// Membership is transitive: the members of a role are also members of
+	// every role that role belongs to.
+	RoleMembersTableSchema = `
+CREATE TABLE system.role_members (
+	role              STRING    NOT NULL,
+	member            STRING    NOT NULL,
+	"isAdmin"         BOOL      NOT NULL,
+	PRIMARY KEY (role, member),
+	INDEX (member),
+	FAMILY (role, member, "isAdmin")
+);`
+
+	// user_options records the login restrictions of users. A NULL option
+	// places no restriction.
+	UserOptionsTableSchema = `
+CREATE TABLE system.user_options (
+	username          STRING      PRIMARY KEY,
+	"noLogin"         BOOL,
+	"validUntil"      TIMESTAMPTZ,
+	"connectionLimit" INT
+);`
 )
 
 func pk(name string) IndexDescriptor {
@@ -174,7 +212,11 @@ var SystemAllowedPrivileges = map[ID]privilege.Lists{
 	// users will be able to modify system tables' schemas at will. CREATE and
 	// DROP privileges are allowed on the above system tables for backwards
 	// compatibility reasons only!
-	keys.JobsTableID: {privilege.ReadWriteData},
+	keys.JobsTableID:        {privilege.ReadWriteData},
+	keys.SchedulesTableID:   {privilege.ReadWriteData},
+	keys.RolesTableID:       {privilege.ReadWriteData},
+	keys.RoleMembersTableID: {privilege.ReadWriteData},
+	keys.UserOptionsTableID: {privilege.ReadWriteData},
 }
 
 // SystemDesiredPrivileges returns the desired privilege list (i.e., the
@@ -188,12 +230,14 @@ func SystemDesiredPrivileges(id ID) privilege.List {
 
 // Helpers used to make some of the TableDescriptor literals below more concise.
 var (
-	colTypeInt       = ColumnType{SemanticType: ColumnType_INT}
-	colTypeString    = ColumnType{SemanticType: ColumnType_STRING}
-	colTypeBytes     = ColumnType{SemanticType: ColumnType_BYTES}
-	colTypeTimestamp = ColumnType{SemanticType: ColumnType_TIMESTAMP}
-	singleASC        = []IndexDescriptor_Direction{IndexDescriptor_ASC}
-	singleID1        = []ColumnID{1}
+	colTypeBool        = ColumnType{SemanticType: ColumnType_BOOL}
+	colTypeInt         = ColumnType{SemanticType: ColumnType_INT}
+	colTypeString      = ColumnType{SemanticType: ColumnType_STRING}
+	colTypeBytes       = ColumnType{SemanticType: ColumnType_BYTES}
+	colTypeTimestamp   = ColumnType{SemanticType: ColumnType_TIMESTAMP}
+	colTypeTimestampTZ = ColumnType{SemanticType: ColumnType_TIMESTAMPTZ}
+	singleASC          = []IndexDescriptor_Direction{IndexDescriptor_ASC}
+	singleID1          = []ColumnID{1}
 )
 
 // These system config TableDescriptor literals should match the descriptor
@@ -522,6 +566,132 @@ var (
 		FormatVersion:  InterleavedFormatVersion,
 		NextMutationID: 1,
 	}
+
+	// SchedulesTable is the descriptor for the schedules table.
+	SchedulesTable = TableDescriptor{
+		Name:     "schedules",
+		ID:       keys.SchedulesTableID,
+		ParentID: 1,
+		Version:  1,
+		Columns: []ColumnDescriptor{
+			{Name: "id", ID: 1, Type: colTypeInt, DefaultExpr: &uniqueRowIDString},
+			{Name: "status", ID: 2, Type: colTypeString},
+			{Name: "created", ID: 3, Type: colTypeTimestamp, DefaultExpr: &nowString},
+			{Name: "next_run", ID: 4, Type: colTypeTimestamp},
+			{Name: "payload", ID: 5, Type: colTypeBytes},
+		},
+		NextColumnID: 6,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name:        "fam_0_id_status_created_next_run_payload",
+				ID:          0,
+				ColumnNames: []string{"id", "status", "created", "next_run", "payload"},
+				ColumnIDs:   []ColumnID{1, 2, 3, 4, 5},
+			},
+		},
+		NextFamilyID:   1,
+		PrimaryIndex:   pk("id"),
+		NextIndexID:    2,
+		Privileges:     NewPrivilegeDescriptor(security.RootUser, SystemDesiredPrivileges(keys.SchedulesTableID)),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
+
+	// RolesTable is the descriptor for the roles table.
+	RolesTable = TableDescriptor{
+		Name:     "roles",
+		ID:       keys.RolesTableID,
+		ParentID: 1,
+		Version:  1,
+		Columns: []ColumnDescriptor{
+			{Name: "name", ID: 1, Type: colTypeString},
+		},
+		NextColumnID: 2,
+		Families: []ColumnFamilyDescriptor{
+			{Name: "primary", ID: 0, ColumnNames: []string{"name"}, ColumnIDs: singleID1},
+		},
+		NextFamilyID:   1,
+		PrimaryIndex:   pk("name"),
+		NextIndexID:    2,
+		Privileges:     NewPrivilegeDescriptor(security.RootUser, SystemDesiredPrivileges(keys.RolesTableID)),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
+
+	// RoleMembersTable is the descriptor for the role_members table.
+	RoleMembersTable = TableDescriptor{
+		Name:     "role_members",
+		ID:       keys.RoleMembersTableID,
+		ParentID: 1,
+		Version:  1,
+		Columns: []ColumnDescriptor{
+			{Name: "role", ID: 1, Type: colTypeString},
+			{Name: "member", ID: 2, Type: colTypeString},
+			{Name: "isAdmin", ID: 3, Type: colTypeBool},
+		},
+		NextColumnID: 4,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name:        "fam_0_role_member_isAdmin",
+				ID:          0,
+				ColumnNames: []string{"role", "member", "isAdmin"},
+				ColumnIDs:   []ColumnID{1, 2, 3},
+			},
+		},
+		NextFamilyID: 1,
+		PrimaryIndex: IndexDescriptor{
+			Name:             "primary",
+			ID:               1,
+			Unique:           true,
+			ColumnNames:      []string{"role", "member"},
+			ColumnDirections: []IndexDescriptor_Direction{IndexDescriptor_ASC, IndexDescriptor_ASC},
+			ColumnIDs:        []ColumnID{1, 2},
+		},
+		Indexes: []IndexDescriptor{
+			{
+				Name:             "role_members_member_idx",
+				ID:               2,
+				Unique:           false,
+				ColumnNames:      []string{"member"},
+				ColumnDirections: singleASC,
+				ColumnIDs:        []ColumnID{2},
+				ExtraColumnIDs:   []ColumnID{1},
+			},
+		},
+		NextIndexID:    3,
+		Privileges:     NewPrivilegeDescriptor(security.RootUser, SystemDesiredPrivileges(keys.RoleMembersTableID)),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
+
+	// UserOptionsTable is the descriptor for the user_options table.
+	UserOptionsTable = TableDescriptor{
+		Name:     "user_options",
+		ID:       keys.UserOptionsTableID,
+		ParentID: 1,
+		Version:  1,
+		Columns: []ColumnDescriptor{
+			{Name: "username", ID: 1, Type: colTypeString},
+			{Name: "noLogin", ID: 2, Type: colTypeBool, Nullable: true},
+			{Name: "validUntil", ID: 3, Type: colTypeTimestampTZ, Nullable: true},
+			{Name: "connectionLimit", ID: 4, Type: colTypeInt, Nullable: true},
+		},
+		NextColumnID: 5,
+		Families: []ColumnFamilyDescriptor{
+			{
+				Name:        "primary",
+				ID:          0,
+				ColumnNames: []string{"username", "noLogin", "validUntil", "connectionLimit"},
+				ColumnIDs:   []ColumnID{1, 2, 3, 4},
+			},
+		},
+		NextFamilyID:   1,
+		PrimaryIndex:   pk("username"),
+		NextIndexID:    2,
+		Privileges:     NewPrivilegeDescriptor(security.RootUser, SystemDesiredPrivileges(keys.UserOptionsTableID)),
+		FormatVersion:  InterleavedFormatVersion,
+		NextMutationID: 1,
+	}
 )
 
 // Create the key/value pair for the default zone config entry.
@@ -30,8 +30,10 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
@@ -98,7 +100,6 @@ func MakeColumnDefDescs(
 	switch t := d.Type.(type) {
 	case *parser.BoolColType:
 	case *parser.IntColType:
-		col.Type.Width = int32(t.N)
 		if t.IsSerial() {
 			if d.HasDefaultExpr() {
 				return nil, nil, fmt.Errorf("SERIAL column %q cannot have a default value", col.Name)
@@ -129,6 +130,16 @@ func MakeColumnDefDescs(
 	case *parser.TimestampTZColType:
 	case *parser.IntervalColType:
 	case *parser.UUIDColType:
+	case *parser.INetColType:
+		if t.IsCIDR() {
+			col.Type.SemanticType = ColumnType_CIDR
+		}
+	case *parser.TimeColType:
+	case *parser.BitArrayColType:
+		if !t.Variable {
+			col.Type.SemanticType = ColumnType_BIT
+		}
+		col.Type.Width = int32(t.Width)
 	case *parser.StringColType:
 		col.Type.Width = int32(t.N)
 	case *parser.NameColType:
@@ -535,6 +546,22 @@ func EncodeTableKey(b []byte, val parser.Datum, dir encoding.Direction) ([]byte,
 			return encoding.EncodeBytesAscending(b, t.GetBytes()), nil
 		}
 		return encoding.EncodeBytesDescending(b, t.GetBytes()), nil
+	case *parser.DIPAddr:
+		data := t.ToBuffer(nil)
+		if dir == encoding.Ascending {
+			return encoding.EncodeBytesAscending(b, data), nil
+		}
+		return encoding.EncodeBytesDescending(b, data), nil
+	case *parser.DTime:
+		if dir == encoding.Ascending {
+			return encoding.EncodeVarintAscending(b, int64(*t)), nil
+		}
+		return encoding.EncodeVarintDescending(b, int64(*t)), nil
+	case *parser.DBitArray:
+		if dir == encoding.Ascending {
+			return encoding.EncodeBitArrayAscending(b, t.BitArray), nil
+		}
+		return encoding.EncodeBitArrayDescending(b, t.BitArray), nil
 	case *parser.DTuple:
 		for _, datum := range t.D {
 			var err error
@@ -599,6 +626,12 @@ func EncodeTableValue(appendTo []byte, colID ColumnID, val parser.Datum) ([]byte
 		return encoding.EncodeDurationValue(appendTo, uint32(colID), t.Duration), nil
 	case *parser.DUuid:
 		return encoding.EncodeUUIDValue(appendTo, uint32(colID), t.UUID), nil
+	case *parser.DIPAddr:
+		return encoding.EncodeIPAddrValue(appendTo, uint32(colID), t.IPAddr), nil
+	case *parser.DTime:
+		return encoding.EncodeIntValue(appendTo, uint32(colID), int64(*t)), nil
+	case *parser.DBitArray:
+		return encoding.EncodeBitArrayValue(appendTo, uint32(colID), t.BitArray), nil
 	case *parser.DCollatedString:
 		return encoding.EncodeBytesValue(appendTo, uint32(colID), []byte(t.Contents)), nil
 	case *parser.DOid:
@@ -900,6 +933,9 @@ type DatumAlloc struct {
 	dtimestampTzAlloc []parser.DTimestampTZ
 	dintervalAlloc    []parser.DInterval
 	duuidAlloc        []parser.DUuid
+	dipAddrAlloc      []parser.DIPAddr
+	dtimeAlloc        []parser.DTime
+	dbitArrayAlloc    []parser.DBitArray
 	doidAlloc         []parser.DOid
 	env               parser.CollationEnvironment
 }
@@ -1029,6 +1065,42 @@ func (a *DatumAlloc) NewDUuid(v parser.DUuid) *parser.DUuid {
 	return r
 }
 
+// NewDIPAddr allocates a DIPAddr.
+func (a *DatumAlloc) NewDIPAddr(v parser.DIPAddr) *parser.DIPAddr {
+	buf := &a.dipAddrAlloc
+	if len(*buf) == 0 {
+		*buf = make([]parser.DIPAddr, datumAllocSize)
+	}
+	r := &(*buf)[0]
+	*r = v
+	*buf = (*buf)[1:]
+	return r
+}
+
+// NewDTime allocates a DTime.
+func (a *DatumAlloc) NewDTime(v parser.DTime) *parser.DTime {
+	buf := &a.dtimeAlloc
+	if len(*buf) == 0 {
+		*buf = make([]parser.DTime, datumAllocSize)
+	}
+	r := &(*buf)[0]
+	*r = v
+	*buf = (*buf)[1:]
+	return r
+}
+
+// NewDBitArray allocates a DBitArray.
+func (a *DatumAlloc) NewDBitArray(v parser.DBitArray) *parser.DBitArray {
+	buf := &a.dbitArrayAlloc
+	if len(*buf) == 0 {
+		*buf = make([]parser.DBitArray, datumAllocSize)
+	}
+	r := &(*buf)[0]
+	*r = v
+	*buf = (*buf)[1:]
+	return r
+}
+
 // NewDOid allocates a DOid.
 func (a *DatumAlloc) NewDOid(v parser.DOid) parser.Datum {
 	buf := &a.doidAlloc
@@ -1158,6 +1230,34 @@ func DecodeTableKey(
 		}
 		u, err := uuid.FromBytes(r)
 		return a.NewDUuid(parser.DUuid{UUID: u}), rkey, err
+	case parser.TypeINet:
+		var r []byte
+		if dir == encoding.Ascending {
+			rkey, r, err = encoding.DecodeBytesAscending(key, nil)
+		} else {
+			rkey, r, err = encoding.DecodeBytesDescending(key, nil)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		ipAddr, _, err := ipaddr.FromBuffer(r)
+		return a.NewDIPAddr(parser.DIPAddr{IPAddr: ipAddr}), rkey, err
+	case parser.TypeTime:
+		var t int64
+		if dir == encoding.Ascending {
+			rkey, t, err = encoding.DecodeVarintAscending(key)
+		} else {
+			rkey, t, err = encoding.DecodeVarintDescending(key)
+		}
+		return a.NewDTime(parser.DTime(t)), rkey, err
+	case parser.TypeBitArray:
+		var r bitarray.BitArray
+		if dir == encoding.Ascending {
+			rkey, r, err = encoding.DecodeBitArrayAscending(key)
+		} else {
+			rkey, r, err = encoding.DecodeBitArrayDescending(key)
+		}
+		return a.NewDBitArray(parser.DBitArray{BitArray: r}), rkey, err
 	case parser.TypeOid:
 		var i int64
 		if dir == encoding.Ascending {
@@ -1240,6 +1340,18 @@ func DecodeTableValue(a *DatumAlloc, valType parser.Type, b []byte) (parser.Datu
 		var u uuid.UUID
 		b, u, err = encoding.DecodeUUIDValue(b)
 		return a.NewDUuid(parser.DUuid{UUID: u}), b, err
+	case parser.TypeINet:
+		var ipAddr ipaddr.IPAddr
+		b, ipAddr, err = encoding.DecodeIPAddrValue(b)
+		return a.NewDIPAddr(parser.DIPAddr{IPAddr: ipAddr}), b, err
+	case parser.TypeTime:
+		var i int64
+		b, i, err = encoding.DecodeIntValue(b)
+		return a.NewDTime(parser.DTime(i)), b, err
+	case parser.TypeBitArray:
+		var d bitarray.BitArray
+		b, d, err = encoding.DecodeBitArrayValue(b)
+		return a.NewDBitArray(parser.DBitArray{BitArray: d}), b, err
 
 	case parser.TypeOid:
 		var i int64
@@ -1467,6 +1579,21 @@ func MarshalColumnValue(col ColumnDescriptor, val parser.Datum) (roachpb.Value,
 			r.SetBytes(v.GetBytes())
 			return r, nil
 		}
+	case ColumnType_INET, ColumnType_CIDR:
+		if v, ok := val.(*parser.DIPAddr); ok {
+			r.SetBytes(v.ToBuffer(nil))
+			return r, nil
+		}
+	case ColumnType_TIME:
+		if v, ok := val.(*parser.DTime); ok {
+			r.SetInt(int64(*v))
+			return r, nil
+		}
+	case ColumnType_BIT, ColumnType_VARBIT:
+		if v, ok := val.(*parser.DBitArray); ok {
+			r.SetBytes(encoding.EncodeUntaggedBitArrayValue(nil, v.BitArray))
+			return r, nil
+		}
 	case ColumnType_COLLATEDSTRING:
 		if col.Type.Locale == nil {
 			panic("locale is required for COLLATEDSTRING")
@@ -1579,6 +1706,32 @@ func UnmarshalColumnValue(
 			return nil, err
 		}
 		return a.NewDUuid(parser.DUuid{UUID: u}), nil
+	case ColumnType_INET, ColumnType_CIDR:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		ipAddr, _, err := ipaddr.FromBuffer(v)
+		if err != nil {
+			return nil, err
+		}
+		return a.NewDIPAddr(parser.DIPAddr{IPAddr: ipAddr}), nil
+	case ColumnType_TIME:
+		v, err := value.GetInt()
+		if err != nil {
+			return nil, err
+		}
+		return a.NewDTime(parser.DTime(v)), nil
+	case ColumnType_BIT, ColumnType_VARBIT:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		_, d, err := encoding.DecodeUntaggedBitArrayValue(v)
+		if err != nil {
+			return nil, err
+		}
+		return a.NewDBitArray(parser.DBitArray{BitArray: d}), nil
 	case ColumnType_NAME:
 		v, err := value.GetBytes()
 		if err != nil {
@@ -1630,6 +1783,27 @@ func CheckValueWidth(col ColumnDescriptor, val parser.Datum) error {
 				}
 			}
 		}
+	case ColumnType_BIT:
+		if v, ok := val.(*parser.DBitArray); ok {
+			if v.BitLen() != uint(col.Type.Width) {
+				return fmt.Errorf("bit string length %d does not match type %s (column %q)",
+					v.BitLen(), col.Type.SQLString(), col.Name)
+			}
+		}
+	case ColumnType_VARBIT:
+		if v, ok := val.(*parser.DBitArray); ok {
+			if col.Type.Width > 0 && v.BitLen() > uint(col.Type.Width) {
+				return fmt.Errorf("bit string too long for type %s (column %q)",
+					col.Type.SQLString(), col.Name)
+			}
+		}
+	case ColumnType_CIDR:
+		if v, ok := val.(*parser.DIPAddr); ok {
+			if !v.IsNetwork() {
+				return fmt.Errorf("value %s has bits set to right of mask for type %s (column %q)",
+					v, col.Type.SQLString(), col.Name)
+			}
+		}
 	case ColumnType_DECIMAL:
 		if v, ok := val.(*parser.DDecimal); ok {
 			if err := parser.LimitDecimalWidth(&v.Decimal, int(col.Type.Precision), int(col.Type.Width)); err != nil {
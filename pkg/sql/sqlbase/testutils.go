@@ -30,7 +30,10 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
@@ -98,6 +101,18 @@ func RandDatum(rng *rand.Rand, typ ColumnType, null bool) parser.Datum {
 		}}
 	case ColumnType_UUID:
 		return parser.NewDUuid(parser.DUuid{UUID: uuid.MakeV4()})
+	case ColumnType_INET:
+		return parser.NewDIPAddr(parser.DIPAddr{IPAddr: ipaddr.RandIPAddr(rng)})
+	case ColumnType_CIDR:
+		return parser.NewDIPAddr(parser.DIPAddr{IPAddr: ipaddr.RandIPAddr(rng).Network()})
+	case ColumnType_TIME:
+		return parser.MakeDTime(timeofday.Random(rng))
+	case ColumnType_BIT, ColumnType_VARBIT:
+		width := uint(typ.Width)
+		if width == 0 || (typ.SemanticType == ColumnType_VARBIT && rng.Intn(2) == 0) {
+			width = uint(rng.Intn(100))
+		}
+		return &parser.DBitArray{BitArray: bitarray.Rand(rng, width)}
 	case ColumnType_STRING:
 		// Generate a random ASCII string.
 		p := make([]byte, rng.Intn(10))
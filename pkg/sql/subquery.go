@@ -42,6 +42,20 @@ type subquery struct {
 	started  bool
 	plan     planNode
 	result   parser.Datum
+
+	// scopes is set for correlated sub-queries, i.e. sub-queries that
+	// refer to the columns of the data sources of the surrounding
+	// expression or of an enclosing query. These sub-queries are
+	// planned and run anew every time they are evaluated, with the
+	// current values of the referenced columns made available through
+	// the scopes. plan is nil for correlated sub-queries.
+	scopes []*outerScope
+	// outerVars are the expressions that compute the values of the
+	// columns referenced in the data sources of the surrounding
+	// expression; outerCols indicates where to store these values
+	// before the sub-query is planned.
+	outerVars []parser.TypedExpr
+	outerCols []outerVar
 }
 
 type subqueryExecMode int
@@ -85,7 +99,29 @@ func (s *subquery) Format(buf *bytes.Buffer, f parser.FmtFlags) {
 func (s *subquery) String() string { return parser.AsString(s) }
 
 func (s *subquery) Walk(v parser.Visitor) parser.Expr {
-	return s
+	var newVars []parser.TypedExpr
+	for i, e := range s.outerVars {
+		newExpr, changed := parser.WalkExpr(v, e)
+		if changed {
+			if newVars == nil {
+				newVars = append([]parser.TypedExpr(nil), s.outerVars...)
+			}
+			newVars[i] = newExpr.(parser.TypedExpr)
+		}
+	}
+	if newVars == nil {
+		return s
+	}
+	sCopy := *s
+	sCopy.outerVars = newVars
+	return &sCopy
+}
+
+// correlated returns true if the sub-query refers to the columns of
+// the data sources of the surrounding expression or of an enclosing
+// query.
+func (s *subquery) correlated() bool {
+	return s.scopes != nil
 }
 
 func (s *subquery) Variable() {}
@@ -104,13 +140,53 @@ func (s *subquery) TypeCheck(_ *parser.SemaContext, desired parser.Type) (parser
 
 func (s *subquery) ResolvedType() parser.Type { return s.typ }
 
-func (s *subquery) Eval(_ *parser.EvalContext) (parser.Datum, error) {
+func (s *subquery) Eval(ctx *parser.EvalContext) (parser.Datum, error) {
+	if s.correlated() {
+		return s.evalCorrelated(ctx)
+	}
 	if s.result == nil {
 		panic("subquery was not pre-evaluated properly")
 	}
 	return s.result, nil
 }
 
+// evalCorrelated plans, starts and evaluates a correlated sub-query
+// for the current values of the columns it refers to.
+func (s *subquery) evalCorrelated(evalCtx *parser.EvalContext) (parser.Datum, error) {
+	for i, e := range s.outerVars {
+		d, err := e.Eval(evalCtx)
+		if err != nil {
+			return nil, err
+		}
+		c := s.outerCols[i]
+		c.scope.row[c.colIdx] = d
+	}
+
+	ctx := evalCtx.Ctx()
+	p := s.planner
+	savedScopes := p.outerScopes
+	p.outerScopes = s.scopes
+	plan, err := p.newPlan(ctx, s.subquery.Select, nil)
+	p.outerScopes = savedScopes
+	if err != nil {
+		return nil, err
+	}
+
+	s.plan, s.expanded, s.started = plan, false, false
+	i := subqueryInitializer{p: p}
+	if err := i.subqueryNode(ctx, s); err != nil {
+		s.plan.Close(ctx)
+		s.plan = nil
+		return nil, err
+	}
+	if err := p.startPlan(ctx, s.plan); err != nil {
+		s.plan.Close(ctx)
+		s.plan = nil
+		return nil, err
+	}
+	return s.doEval(ctx)
+}
+
 func (s *subquery) doEval(ctx context.Context) (result parser.Datum, err error) {
 	// After evaluation, there is no plan remaining.
 	defer func() { s.plan.Close(ctx); s.plan = nil }()
@@ -240,6 +316,10 @@ type subqueryPlanVisitor struct {
 }
 
 func (v *subqueryPlanVisitor) subqueryNode(ctx context.Context, sq *subquery) error {
+	if sq.correlated() {
+		// Correlated sub-queries are started upon evaluation.
+		return nil
+	}
 	if !sq.expanded {
 		panic("subquery was not expanded properly")
 	}
@@ -285,6 +365,11 @@ type subquerySpanCollector struct {
 }
 
 func (v *subquerySpanCollector) subqueryNode(ctx context.Context, sq *subquery) error {
+	if sq.correlated() {
+		// TODO(knz): the spans of correlated sub-queries are only known
+		// upon evaluation.
+		return nil
+	}
 	reads, writes, err := collectSpans(ctx, sq.plan)
 	if err != nil {
 		return err
@@ -311,6 +396,13 @@ func collectSubquerySpans(ctx context.Context, plan planNode) (roachpb.Spans, er
 type subqueryVisitor struct {
 	*planner
 	columns int
+
+	// sources and ivarHelper describe the data sources of the
+	// surrounding expression, if any. Sub-queries can refer to their
+	// columns.
+	sources    multiSourceInfo
+	ivarHelper parser.IndexedVarHelper
+
 	path    []parser.Expr // parent expressions
 	pathBuf [4]parser.Expr
 	err     error
@@ -346,17 +438,64 @@ func (v *subqueryVisitor) VisitPre(expr parser.Expr) (recurse bool, newExpr pars
 		}
 	}
 
+	// The sub-query can refer to the columns of the data sources of
+	// the surrounding expression, in addition to those of the
+	// enclosing queries. The "used" flags of the enclosing scopes are
+	// saved, so as to determine whether this particular sub-query is
+	// correlated.
+	savedScopes := v.planner.outerScopes
+	scopes := append([]*outerScope(nil), savedScopes...)
+	for _, src := range v.sources {
+		scopes = append(scopes, &outerScope{info: src})
+	}
+	savedUsed := make([]bool, len(savedScopes))
+	for i, scope := range savedScopes {
+		savedUsed[i], scope.used = scope.used, false
+	}
+	sources, ivarHelper := v.sources, v.ivarHelper
+
 	// Calling newPlan() might recursively invoke expandSubqueries, so we need to preserve
 	// the state of the visitor across the call to newPlan().
 	visitorCopy := v.planner.subqueryVisitor
+	v.planner.outerScopes = scopes
 	plan, err := v.planner.newPlan(v.ctx, sq.Select, nil)
+	v.planner.outerScopes = savedScopes
 	v.planner.subqueryVisitor = visitorCopy
+
+	correlated := false
+	for _, scope := range scopes {
+		correlated = correlated || scope.used
+	}
+	for i, scope := range savedScopes {
+		scope.used = scope.used || savedUsed[i]
+	}
 	if err != nil {
 		v.err = err
 		return false, expr
 	}
 
 	result := &subquery{planner: v.planner, subquery: sq, plan: plan}
+	if correlated {
+		// The plan is only used to determine the result type below; the
+		// sub-query is planned anew upon every evaluation.
+		result.scopes = scopes
+		colOffset := 0
+		for i, src := range sources {
+			scope := scopes[len(savedScopes)+i]
+			scope.row = make(parser.Datums, len(src.sourceColumns))
+			for colIdx, used := range scope.usedCols {
+				if used {
+					result.outerVars = append(result.outerVars, ivarHelper.IndexedVar(colOffset+colIdx))
+					result.outerCols = append(result.outerCols, outerVar{scope: scope, colIdx: colIdx})
+				}
+			}
+			colOffset += len(src.sourceColumns)
+		}
+		defer func() {
+			plan.Close(v.ctx)
+			result.plan = nil
+		}()
+	}
 
 	if exists != nil {
 		result.execMode = execModeExists
@@ -404,10 +543,24 @@ func (v *subqueryVisitor) VisitPost(expr parser.Expr) parser.Expr {
 	return expr
 }
 
+// replaceSubqueries replaces the sub-queries of the expression by
+// subquery nodes carrying their query plans. columns is the number of
+// columns expected from a sub-query forming the whole expression.
+//
+// The sub-queries can refer to the columns of the given data sources,
+// whose IndexedVars are provided by ivarHelper. The sources can be
+// nil if the expression is not evaluated in the context of a data
+// source.
 func (p *planner) replaceSubqueries(
-	ctx context.Context, expr parser.Expr, columns int,
+	ctx context.Context,
+	expr parser.Expr,
+	columns int,
+	sources multiSourceInfo,
+	ivarHelper parser.IndexedVarHelper,
 ) (parser.Expr, error) {
-	p.subqueryVisitor = subqueryVisitor{planner: p, columns: columns, ctx: ctx}
+	p.subqueryVisitor = subqueryVisitor{
+		planner: p, columns: columns, sources: sources, ivarHelper: ivarHelper, ctx: ctx,
+	}
 	p.subqueryVisitor.path = p.subqueryVisitor.pathBuf[:0]
 	expr, _ = parser.WalkExpr(&p.subqueryVisitor, expr)
 	return expr, p.subqueryVisitor.err
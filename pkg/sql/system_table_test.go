@@ -119,6 +119,10 @@ func TestSystemTableLiterals(t *testing.T) {
 		{keys.UITableID, sqlbase.UITableSchema, sqlbase.UITable},
 		{keys.JobsTableID, sqlbase.JobsTableSchema, sqlbase.JobsTable},
 		{keys.SettingsTableID, sqlbase.SettingsTableSchema, sqlbase.SettingsTable},
+		{keys.SchedulesTableID, sqlbase.SchedulesTableSchema, sqlbase.SchedulesTable},
+		{keys.RolesTableID, sqlbase.RolesTableSchema, sqlbase.RolesTable},
+		{keys.RoleMembersTableID, sqlbase.RoleMembersTableSchema, sqlbase.RoleMembersTable},
+		{keys.UserOptionsTableID, sqlbase.UserOptionsTableSchema, sqlbase.UserOptionsTable},
 	} {
 		gen, err := sql.CreateTestTableDescriptor(
 			context.TODO(),
@@ -57,12 +57,22 @@ func TestMakeTableDescColumns(t *testing.T) {
 	}{
 		{
 			"BIT",
-			sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT, Width: 1},
+			sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_BIT, Width: 1},
 			true,
 		},
 		{
 			"BIT(3)",
-			sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT, Width: 3},
+			sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_BIT, Width: 3},
+			true,
+		},
+		{
+			"VARBIT",
+			sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_VARBIT},
+			true,
+		},
+		{
+			"VARBIT(3)",
+			sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_VARBIT, Width: 3},
 			true,
 		},
 		{
@@ -100,6 +110,21 @@ func TestMakeTableDescColumns(t *testing.T) {
 			sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INTERVAL},
 			true,
 		},
+		{
+			"TIME",
+			sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_TIME},
+			true,
+		},
+		{
+			"INET",
+			sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INET},
+			true,
+		},
+		{
+			"CIDR",
+			sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_CIDR},
+			true,
+		},
 		{
 			"CHAR",
 			sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_STRING},
@@ -50,6 +50,7 @@ type expressionCarrier interface {
 //   }
 //   err := tw.finalize()
 //   // Handle err.
+//   tw.close()
 type tableWriter interface {
 	expressionCarrier
 
@@ -75,6 +76,10 @@ type tableWriter interface {
 	// this a separate parameter as opposed to a Value field on the context.
 	finalize(ctx context.Context, traceKV bool) error
 
+	// close releases the resources of the tableWriter. It is called once the
+	// tableWriter is no longer in use.
+	close(ctx context.Context)
+
 	// spans collects the upper bound set of read and write spans that the
 	// tableWriter will touch when executed. This is contractual, and the
 	// tableWriter will not touch any keys outside of the spans reported here.
@@ -132,6 +137,10 @@ func (ti *tableInserter) finalize(ctx context.Context, _ bool) error {
 	return nil
 }
 
+func (ti *tableInserter) close(ctx context.Context) {
+	ti.ri.Close(ctx)
+}
+
 func (ti *tableInserter) spans() (reads, writes roachpb.Spans, err error) {
 	return collectTableWriterSpans(ti.ri.Helper.TableDesc, ti.ri.Fks)
 }
@@ -179,6 +188,10 @@ func (tu *tableUpdater) finalize(ctx context.Context, _ bool) error {
 	return nil
 }
 
+func (tu *tableUpdater) close(ctx context.Context) {
+	tu.ru.Close(ctx)
+}
+
 func (tu *tableUpdater) spans() (reads, writes roachpb.Spans, err error) {
 	return collectTableWriterSpans(tu.ru.Helper.TableDesc, tu.ru.Fks)
 }
@@ -522,6 +535,11 @@ func (tu *tableUpserter) finalize(ctx context.Context, traceKV bool) error {
 	return tu.flush(ctx, true /* finalize */, traceKV)
 }
 
+func (tu *tableUpserter) close(ctx context.Context) {
+	tu.ri.Close(ctx)
+	tu.ru.Close(ctx)
+}
+
 func (tu *tableUpserter) spans() (reads, writes roachpb.Spans, err error) {
 	return collectTableWriterSpans(tu.ri.Helper.TableDesc, tu.ri.Fks)
 }
@@ -530,6 +548,12 @@ func (tu *tableUpserter) spans() (reads, writes roachpb.Spans, err error) {
 type tableDeleter struct {
 	rd         sqlbase.RowDeleter
 	autoCommit bool
+	// db, if set along with autoCommit, is used to delete spans of rows by
+	// writing range tombstones instead of deleting every key in the
+	// transaction. Range tombstones are written outside of the transaction,
+	// so this must only be set if the deletion is the transaction's only
+	// write.
+	db *client.DB
 
 	// Set by init.
 	txn *client.Txn
@@ -560,6 +584,10 @@ func (td *tableDeleter) finalize(ctx context.Context, _ bool) error {
 	return td.txn.Run(ctx, td.b)
 }
 
+func (td *tableDeleter) close(ctx context.Context) {
+	td.rd.Close(ctx)
+}
+
 // fastPathAvailable returns true if the fastDelete optimization can be used.
 func (td *tableDeleter) fastPathAvailable(ctx context.Context) bool {
 	if len(td.rd.Helper.Indexes) != 0 {
@@ -583,15 +611,49 @@ func (td *tableDeleter) fastPathAvailable(ctx context.Context) bool {
 	return true
 }
 
+// useRangeTombstones returns true if spans of rows can be deleted by writing
+// range tombstones.
+func (td *tableDeleter) useRangeTombstones() bool {
+	return td.autoCommit && td.db != nil
+}
+
+// isSingleRowSpan returns true if the span only covers the keys of a single
+// row. Such a span isn't worth a range tombstone, which makes all the reads of
+// its range look for range tombstones until it is garbage collected.
+func isSingleRowSpan(scan *scanNode, span roachpb.Span) bool {
+	if !span.Key.PrefixEnd().Equal(span.EndKey) {
+		return false
+	}
+	// The key of a span covering several rows is missing some of the primary
+	// key columns and fails to decode.
+	_, ok, err := scan.fetcher.ReadIndexKey(span.Key)
+	return ok && err == nil
+}
+
 // fastDelete adds to the batch the kv operations necessary to delete sql rows
-// without knowing the values that are currently present. fastDelete calls
-// finalize, so it should not be called after.
+// without knowing the values that are currently present. The spans covering
+// more than a single row are deleted using range tombstones if possible.
+// fastDelete calls finalize, so it should not be called after.
 func (td *tableDeleter) fastDelete(
 	ctx context.Context, scan *scanNode, traceKV bool,
 ) (rowCount int, err error) {
 
+	var results []client.Result
 	for _, span := range scan.spans {
 		log.VEvent(ctx, 2, "fast delete: skipping scan")
+		if td.useRangeTombstones() && !isSingleRowSpan(scan, span) {
+			if traceKV {
+				log.VEventf(ctx, 2, "DelRangeUsingTombstone %s - %s", span.Key, span.EndKey)
+			}
+			// Range tombstones can't be batched with other requests.
+			b := &client.Batch{}
+			b.DelRangeUsingTombstone(span.Key, span.EndKey, true)
+			if err := td.db.Run(ctx, b); err != nil {
+				return 0, err
+			}
+			results = append(results, b.Results...)
+			continue
+		}
 		if traceKV {
 			log.VEventf(ctx, 2, "DelRange %s - %s", span.Key, span.EndKey)
 		}
@@ -602,8 +664,9 @@ func (td *tableDeleter) fastDelete(
 	if err != nil {
 		return 0, err
 	}
+	results = append(results, td.b.Results...)
 
-	for _, r := range td.b.Results {
+	for _, r := range results {
 		var prev []byte
 		for _, i := range r.Keys {
 			// If prefix is same, don't bother decoding key.
@@ -664,6 +727,14 @@ func (td *tableDeleter) deleteAllRowsFast(
 			EndKey: tablePrefix.PrefixEnd(),
 		}
 	}
+	if td.useRangeTombstones() {
+		if traceKV {
+			log.VEventf(ctx, 2, "DelRangeUsingTombstone %s - %s", resume.Key, resume.EndKey)
+		}
+		// A range tombstone deletes the rows in a constant number of writes,
+		// so there is no need to resume the deletion.
+		return roachpb.Span{}, td.db.DelRangeUsingTombstone(ctx, resume.Key, resume.EndKey)
+	}
 	if traceKV {
 		log.VEventf(ctx, 2, "DelRange %s - %s", resume.Key, resume.EndKey)
 	}
@@ -751,6 +822,14 @@ func (td *tableDeleter) deleteIndexFast(
 		resume = td.rd.Helper.TableDesc.IndexSpan(idx.ID)
 	}
 
+	if td.useRangeTombstones() {
+		if traceKV {
+			log.VEventf(ctx, 2, "DelRangeUsingTombstone %s - %s", resume.Key, resume.EndKey)
+		}
+		// A range tombstone deletes the rows in a constant number of writes,
+		// so there is no need to resume the deletion.
+		return roachpb.Span{}, td.db.DelRangeUsingTombstone(ctx, resume.Key, resume.EndKey)
+	}
 	if traceKV {
 		log.VEventf(ctx, 2, "DelRange %s - %s", resume.Key, resume.EndKey)
 	}
@@ -95,8 +95,14 @@ func (p *planner) Truncate(ctx context.Context, n *parser.Truncate) (planNode, e
 
 	// TODO(knz): move truncate logic to Start/Next so it can be used with SHOW TRACE FOR.
 	traceKV := p.session.Tracing.KVTracingEnabled()
+	// A single table can be truncated with a range tombstone, which is written
+	// outside of the transaction, if it is the only write of an auto-committed
+	// transaction.
+	autoCommit := p.autoCommit && len(toTruncate) == 1
 	for _, tableDesc := range toTruncate {
-		if err := truncateTable(p.session.Ctx(), tableDesc, p.txn, traceKV); err != nil {
+		if err := truncateTable(
+			p.session.Ctx(), tableDesc, p.txn, p.ExecCfg().DB, autoCommit, traceKV,
+		); err != nil {
 			return nil, err
 		}
 	}
@@ -106,15 +112,23 @@ func (p *planner) Truncate(ctx context.Context, n *parser.Truncate) (planNode, e
 
 // truncateTable truncates the data of a table in a single transaction. It
 // deletes a range of data for the table, which includes the PK and all
-// indexes.
+// indexes. If autoCommit is true, the truncation must be the only write of the
+// transaction, which it may commit, and the data is deleted using a range
+// tombstone written through db if the table isn't interleaved.
 func truncateTable(
-	ctx context.Context, tableDesc *sqlbase.TableDescriptor, txn *client.Txn, traceKV bool,
+	ctx context.Context,
+	tableDesc *sqlbase.TableDescriptor,
+	txn *client.Txn,
+	db *client.DB,
+	autoCommit bool,
+	traceKV bool,
 ) error {
 	rd, err := sqlbase.MakeRowDeleter(txn, tableDesc, nil, nil, false)
 	if err != nil {
 		return err
 	}
-	td := tableDeleter{rd: rd}
+	td := tableDeleter{rd: rd, autoCommit: autoCommit, db: db}
+	defer td.close(ctx)
 	if err := td.init(txn); err != nil {
 		return err
 	}
@@ -123,10 +137,20 @@ func truncateTable(
 }
 
 // truncateTableInChunks truncates the data of a table in chunks. It deletes a
-// range of data for the table, which includes the PK and all indexes.
+// range of data for the table, which includes the PK and all indexes. The
+// table must no longer be in use: unless it is interleaved, its data is
+// deleted at once using a range tombstone.
 func truncateTableInChunks(
 	ctx context.Context, tableDesc *sqlbase.TableDescriptor, db *client.DB, traceKV bool,
 ) error {
+	if !tableDesc.IsInterleaved() {
+		span := tableDesc.TableSpan()
+		if traceKV {
+			log.VEventf(ctx, 2, "DelRangeUsingTombstone %s - %s", span.Key, span.EndKey)
+		}
+		return db.DelRangeUsingTombstone(ctx, span.Key, span.EndKey)
+	}
+
 	const chunkSize = TableTruncateChunkSize
 	var resume roachpb.Span
 	for row, done := 0, false; !done; row += chunkSize {
@@ -140,6 +164,7 @@ func truncateTableInChunks(
 				return err
 			}
 			td := tableDeleter{rd: rd}
+			defer td.close(ctx)
 			if err := td.init(txn); err != nil {
 				return err
 			}
@@ -43,19 +43,15 @@ type editNodeBase struct {
 func (p *planner) makeEditNode(
 	ctx context.Context, tn *parser.TableName, priv privilege.Kind,
 ) (editNodeBase, error) {
-	tableDesc, err := p.session.tables.getTableVersion(ctx, p.txn, p.getVirtualTabler(), tn)
+	tableDesc, err := p.getEditTableDesc(ctx, tn, priv)
 	if err != nil {
 		return editNodeBase{}, err
 	}
-	// We don't support update on views, only real tables.
-	if !tableDesc.IsTable() {
-		return editNodeBase{},
-			errors.Errorf("cannot run %s on view %q - views are not updateable", priv, tn)
-	}
 
 	if err := p.CheckPrivilege(tableDesc, priv); err != nil {
 		return editNodeBase{}, err
 	}
+	p.maybeAudit(tableDesc, priv)
 
 	return editNodeBase{
 		p:         p,
@@ -63,6 +59,23 @@ func (p *planner) makeEditNode(
 	}, nil
 }
 
+// getEditTableDesc returns the descriptor of the table modified by a
+// row-modifying statement requiring privilege priv. The privilege is not
+// checked.
+func (p *planner) getEditTableDesc(
+	ctx context.Context, tn *parser.TableName, priv privilege.Kind,
+) (*sqlbase.TableDescriptor, error) {
+	tableDesc, err := p.session.tables.getTableVersion(ctx, p.txn, p.getVirtualTabler(), tn)
+	if err != nil {
+		return nil, err
+	}
+	// We don't support update on views, only real tables.
+	if !tableDesc.IsTable() {
+		return nil, errors.Errorf("cannot run %s on view %q - views are not updateable", priv, tn)
+	}
+	return tableDesc, nil
+}
+
 // editNodeRun holds the runtime (execute) state needed to run
 // row-modifying statements.
 type editNodeRun struct {
@@ -217,6 +230,9 @@ func addOrMergeExpr(
 
 // Update updates columns for a selection of rows from a table.
 // Privileges: UPDATE and SELECT on table. We currently always use a select statement.
+//   UPDATE can also be granted on the updated columns only. SELECT is then still
+//   needed on the columns fetched by the statement: the primary key, the columns
+//   of the updated column families and the columns read by the expressions.
 //   Notes: postgres requires UPDATE. Requires SELECT with WHERE clause with table.
 //          mysql requires UPDATE. Also requires SELECT with WHERE clause with table.
 // TODO(guanqun): need to support CHECK in UPDATE
@@ -230,7 +246,7 @@ func (p *planner) Update(
 		return nil, err
 	}
 
-	en, err := p.makeEditNode(ctx, tn, privilege.UPDATE)
+	tableDesc, err := p.getEditTableDesc(ctx, tn, privilege.UPDATE)
 	if err != nil {
 		return nil, err
 	}
@@ -238,7 +254,9 @@ func (p *planner) Update(
 	setExprs := make([]*parser.UpdateExpr, len(n.Exprs))
 	for i, expr := range n.Exprs {
 		// Replace the sub-query nodes.
-		newExpr, err := p.replaceSubqueries(ctx, expr.Expr, len(expr.Names))
+		newExpr, err := p.replaceSubqueries(
+			ctx, expr.Expr, len(expr.Names), nil, parser.IndexedVarHelper{},
+		)
 		if err != nil {
 			return nil, err
 		}
@@ -251,18 +269,38 @@ func (p *planner) Update(
 		return nil, err
 	}
 
-	updateCols, err := p.processColumns(en.tableDesc, names)
+	updateCols, err := p.processColumns(tableDesc, names)
 	if err != nil {
 		return nil, err
 	}
 
+	// The UPDATE privilege is needed on the table, or on all the updated
+	// columns.
+	if err := p.checkColumnPrivileges(tableDesc, privilege.UPDATE, updateCols); err != nil {
+		return nil, err
+	}
+	p.maybeAudit(tableDesc, privilege.UPDATE)
+	en := editNodeBase{
+		p:         p,
+		tableDesc: tableDesc,
+	}
+
 	defaultExprs, err := sqlbase.MakeDefaultExprs(updateCols, &p.parser, &p.evalCtx)
 	if err != nil {
 		return nil, err
 	}
 
+	where, err := p.rowLevelSecurityWhere(ctx, en.tableDesc, sqlbase.TableDescriptor_Policy_UPDATE, n.Where)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := p.rowLevelSecurityExpr(ctx, en.tableDesc, sqlbase.TableDescriptor_Policy_UPDATE, true)
+	if err != nil {
+		return nil, err
+	}
+
 	var requestedCols []sqlbase.ColumnDescriptor
-	if _, retExprs := n.Returning.(*parser.ReturningExprs); retExprs || len(en.tableDesc.Checks) > 0 {
+	if _, retExprs := n.Returning.(*parser.ReturningExprs); retExprs || len(en.tableDesc.Checks) > 0 || policy != nil {
 		// TODO(dan): This could be made tighter, just the rows needed for RETURNING
 		// exprs.
 		requestedCols = en.tableDesc.Columns
@@ -285,7 +323,7 @@ func (p *planner) Update(
 	rows, err := p.SelectClause(ctx, &parser.SelectClause{
 		Exprs: sqlbase.ColumnsSelectors(ru.FetchCols),
 		From:  &parser.From{Tables: []parser.TableExpr{n.Table}},
-		Where: n.Where,
+		Where: where,
 	}, nil, nil, nil, publicAndNonPublicColumns)
 	if err != nil {
 		return nil, err
@@ -382,7 +420,7 @@ func (p *planner) Update(
 		tw:            tw,
 		sourceSlots:   sourceSlots,
 	}
-	if err := un.checkHelper.init(ctx, p, tn, en.tableDesc); err != nil {
+	if err := un.checkHelper.init(ctx, p, tn, en.tableDesc, policy); err != nil {
 		return nil, err
 	}
 	if err := un.run.initEditNode(
@@ -401,6 +439,7 @@ func (u *updateNode) Start(ctx context.Context) error {
 
 func (u *updateNode) Close(ctx context.Context) {
 	u.run.rows.Close(ctx)
+	u.tw.close(ctx)
 }
 
 func (u *updateNode) Next(ctx context.Context) (bool, error) {
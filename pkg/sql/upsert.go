@@ -199,7 +199,9 @@ func upsertExprsAndIndex(
 	}
 
 	indexMatch := func(index sqlbase.IndexDescriptor) bool {
-		if !index.Unique {
+		// A partial index only enforces uniqueness among the rows it contains,
+		// so it cannot be used to find the conflicting rows.
+		if !index.Unique || index.IsPartial() {
 			return false
 		}
 		if len(index.ColumnNames) != len(onConflict.Columns) {
@@ -17,6 +17,8 @@
 package sql
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 
@@ -57,3 +59,80 @@ func GetUserHashedPassword(
 
 	return hashedPassword, nil
 }
+
+// UpgradeUserHashedPassword replaces the hashed password of the given user
+// with newHashedPassword, unless the password was changed concurrently and
+// is no longer oldHashedPassword. It is used to replace legacy password
+// hashes with SCRAM verifiers when users log in with their password.
+func UpgradeUserHashedPassword(
+	ctx context.Context,
+	executor *Executor,
+	metrics *MemoryMetrics,
+	username string,
+	oldHashedPassword, newHashedPassword []byte,
+) error {
+	normalizedUsername := parser.Name(username).Normalize()
+	return executor.cfg.DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		p := makeInternalPlanner("upgrade-pwd", txn, security.RootUser, metrics)
+		defer finishInternalPlanner(p)
+		const upgradeHashedPassword = `UPDATE system.users SET "hashedPassword" = $3 ` +
+			`WHERE username = $1 AND "hashedPassword" = $2`
+		_, err := p.exec(ctx, upgradeHashedPassword,
+			normalizedUsername, oldHashedPassword, newHashedPassword)
+		return err
+	})
+}
+
+// UserLoginOptions are the login restrictions of a user, as set by the
+// options of CREATE USER and ALTER USER.
+type UserLoginOptions struct {
+	// NoLogin prevents the user from opening SQL connections.
+	NoLogin bool
+	// ValidUntil is the time after which the password of the user is no
+	// longer valid. The zero value means the password never expires.
+	ValidUntil time.Time
+	// ConnectionLimit is the maximum number of concurrent connections of
+	// the user to a node. A negative value means there is no limit.
+	ConnectionLimit int64
+}
+
+// GetUserLoginOptions returns the login restrictions of the given username,
+// as found in system.user_options.
+func GetUserLoginOptions(
+	ctx context.Context, executor *Executor, metrics *MemoryMetrics, username string,
+) (UserLoginOptions, error) {
+	opts := UserLoginOptions{ConnectionLimit: -1}
+	normalizedUsername := parser.Name(username).Normalize()
+	// The root user is not in system.users.
+	if normalizedUsername == security.RootUser {
+		return opts, nil
+	}
+
+	if err := executor.cfg.DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		p := makeInternalPlanner("get-user-options", txn, security.RootUser, metrics)
+		defer finishInternalPlanner(p)
+		const getUserOptions = `SELECT "noLogin", "validUntil", "connectionLimit" ` +
+			`FROM system.user_options WHERE username=$1`
+		values, err := p.QueryRow(ctx, getUserOptions, normalizedUsername)
+		if err != nil {
+			return errors.Errorf("error looking up user %s", normalizedUsername)
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		if values[0] != parser.DNull {
+			opts.NoLogin = bool(*values[0].(*parser.DBool))
+		}
+		if values[1] != parser.DNull {
+			opts.ValidUntil = values[1].(*parser.DTimestampTZ).Time
+		}
+		if values[2] != parser.DNull {
+			opts.ConnectionLimit = int64(*values[2].(*parser.DInt))
+		}
+		return nil
+	}); err != nil {
+		return UserLoginOptions{}, err
+	}
+
+	return opts, nil
+}
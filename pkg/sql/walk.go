@@ -191,6 +191,10 @@ func (v *planVisitor) visit(plan planNode) {
 				jType = "right outer"
 			case joinTypeFullOuter:
 				jType = "full outer"
+			case joinTypeSemi:
+				jType = "semi"
+			case joinTypeAnti:
+				jType = "anti"
 			}
 			v.observer.attr(name, "type", jType)
 
@@ -209,6 +213,24 @@ func (v *planVisitor) visit(plan planNode) {
 		v.visit(n.left.plan)
 		v.visit(n.right.plan)
 
+	case *applyJoinNode:
+		if v.observer.attr != nil {
+			jType := "inner"
+			if n.joinType == joinTypeLeftOuter {
+				jType = "left outer"
+			}
+			v.observer.attr(name, "type", jType)
+		}
+		subplans := v.expr(name, "pred", -1, n.pred.onCond, nil)
+		v.subqueries(name, subplans)
+		v.visit(n.left.plan)
+		if v.observer.subqueryNode == nil {
+			// The right operand is planned and started anew for every
+			// left row; its sub-queries are handled at that point, and
+			// the plan built during construction is only descriptive.
+			v.visit(n.right.plan)
+		}
+
 	case *limitNode:
 		subplans := v.expr(name, "count", -1, n.countExpr, nil)
 		subplans = v.expr(name, "offset", -1, n.offsetExpr, subplans)
@@ -279,6 +301,9 @@ func (v *planVisitor) visit(plan planNode) {
 	case *relocateNode:
 		v.visit(n.rows)
 
+	case *copyToNode:
+		v.visit(n.source)
+
 	case *insertNode:
 		if v.observer.attr != nil {
 			var buf bytes.Buffer
@@ -301,6 +326,9 @@ func (v *planVisitor) visit(plan planNode) {
 		for i, cexpr := range n.checkHelper.exprs {
 			subplans = v.expr(name, "check", i, cexpr, subplans)
 		}
+		if n.checkHelper.policyExpr != nil {
+			subplans = v.expr(name, "policy", -1, n.checkHelper.policyExpr, subplans)
+		}
 		for i, rexpr := range n.rh.exprs {
 			subplans = v.expr(name, "returning", i, rexpr, subplans)
 		}
@@ -492,10 +520,15 @@ func nodeName(plan planNode) string {
 // be changed without changing the output of "EXPLAIN".
 var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&alterTableNode{}):       "alter table",
+	reflect.TypeOf(&alterUserNode{}):        "alter user",
+	reflect.TypeOf(&applyJoinNode{}):        "apply-join",
 	reflect.TypeOf(&copyNode{}):             "copy",
+	reflect.TypeOf(&copyToNode{}):           "copy to",
 	reflect.TypeOf(&createDatabaseNode{}):   "create database",
 	reflect.TypeOf(&createIndexNode{}):      "create index",
 	reflect.TypeOf(&createTableNode{}):      "create table",
+	reflect.TypeOf(&createPolicyNode{}):     "create policy",
+	reflect.TypeOf(&createRoleNode{}):       "create role",
 	reflect.TypeOf(&createUserNode{}):       "create user",
 	reflect.TypeOf(&createViewNode{}):       "create view",
 	reflect.TypeOf(&delayedNode{}):          "virtual table",
@@ -505,6 +538,8 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&dropIndexNode{}):        "drop index",
 	reflect.TypeOf(&dropTableNode{}):        "drop table",
 	reflect.TypeOf(&dropViewNode{}):         "drop view",
+	reflect.TypeOf(&dropPolicyNode{}):       "drop policy",
+	reflect.TypeOf(&dropRoleNode{}):         "drop role",
 	reflect.TypeOf(&dropUserNode{}):         "drop user",
 	reflect.TypeOf(&emptyNode{}):            "empty",
 	reflect.TypeOf(&explainDebugNode{}):     "explain debug",
@@ -512,6 +547,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&explainPlanNode{}):      "explain plan",
 	reflect.TypeOf(&traceNode{}):            "show trace for",
 	reflect.TypeOf(&filterNode{}):           "filter",
+	reflect.TypeOf(&grantRoleNode{}):        "grant role",
 	reflect.TypeOf(&groupNode{}):            "group",
 	reflect.TypeOf(&hookFnNode{}):           "plugin",
 	reflect.TypeOf(&indexJoinNode{}):        "index-join",
@@ -521,6 +557,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&ordinalityNode{}):       "ordinality",
 	reflect.TypeOf(&relocateNode{}):         "relocate",
 	reflect.TypeOf(&renderNode{}):           "render",
+	reflect.TypeOf(&revokeRoleNode{}):       "revoke role",
 	reflect.TypeOf(&scanNode{}):             "scan",
 	reflect.TypeOf(&scatterNode{}):          "scatter",
 	reflect.TypeOf(&showRangesNode{}):       "showRanges",
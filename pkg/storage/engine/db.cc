@@ -35,6 +35,7 @@
 #include "cockroach/pkg/storage/engine/enginepb/mvcc.pb.h"
 #include "db.h"
 #include "encoding.h"
+#include "encryption.h"
 #include "eventlistener.h"
 
 extern "C" {
@@ -70,7 +71,7 @@ struct DBEngine {
 };
 
 struct DBImpl : public DBEngine {
-  std::unique_ptr<rocksdb::Env> memenv;
+  std::unique_ptr<rocksdb::Env> env;
   std::unique_ptr<rocksdb::DB> rep_deleter;
   std::shared_ptr<rocksdb::Cache> block_cache;
   std::shared_ptr<DBEventListener> event_listener;
@@ -78,10 +79,10 @@ struct DBImpl : public DBEngine {
   // Construct a new DBImpl from the specified DB and Env. Both the DB
   // and Env will be deleted when the DBImpl is deleted. It is ok to
   // pass NULL for the Env.
-  DBImpl(rocksdb::DB* r, rocksdb::Env* m, std::shared_ptr<rocksdb::Cache> bc,
+  DBImpl(rocksdb::DB* r, rocksdb::Env* e, std::shared_ptr<rocksdb::Cache> bc,
     std::shared_ptr<DBEventListener> event_listener)
       : DBEngine(r),
-        memenv(m),
+        env(e),
         rep_deleter(r),
         block_cache(bc),
         event_listener(event_listener) {
@@ -180,6 +181,9 @@ struct DBIterator {
 // storage/engine/keys.go. Both kKeyLocalRangeIDPrefix and
 // kKeyLocalRangePrefix are the mvcc-encoded prefixes.
 const rocksdb::Slice kKeyLocalRangeIDPrefix("\x01i", 2);
+// kKeyLocalRangeTombstonePrefix is keys.LocalRangeTombstonePrefix. Range
+// tombstones are stored with it under their mvcc-encoded end key.
+const rocksdb::Slice kKeyLocalRangeTombstonePrefix("\x01t", 2);
 const rocksdb::Slice kKeyLocalMax("\x02", 1);
 
 const DBStatus kSuccess = { NULL, 0 };
@@ -1630,10 +1634,16 @@ DBStatus DBOpen(DBEngine **db, DBSlice dir, DBOptions db_opts) {
   std::shared_ptr<DBEventListener> event_listener(new DBEventListener);
   options.listeners.emplace_back(event_listener);
 
-  std::unique_ptr<rocksdb::Env> memenv;
+  std::unique_ptr<rocksdb::Env> env;
   if (dir.len == 0) {
-    memenv.reset(rocksdb::NewMemEnv(rocksdb::Env::Default()));
-    options.env = memenv.get();
+    env.reset(rocksdb::NewMemEnv(rocksdb::Env::Default()));
+    options.env = env.get();
+  } else if (db_opts.use_encryption) {
+    env.reset(NewEncryptedEnv(rocksdb::Env::Default(), ToString(db_opts.encryption_key_id)));
+    options.env = env.get();
+    // Encrypted files start with a header, which breaks the alignment
+    // required by direct I/O.
+    options.use_direct_writes = false;
   }
 
   rocksdb::DB *db_ptr;
@@ -1641,7 +1651,7 @@ DBStatus DBOpen(DBEngine **db, DBSlice dir, DBOptions db_opts) {
   if (!status.ok()) {
     return ToDBStatus(status);
   }
-  *db = new DBImpl(db_ptr, memenv.release(),
+  *db = new DBImpl(db_ptr, env.release(),
       db_opts.cache != nullptr ? db_opts.cache->rep : nullptr,
       event_listener);
   return kSuccess;
@@ -2015,7 +2025,7 @@ DBStatus DBImpl::EnvWriteFile(DBSlice path, DBSlice contents) {
     return ToDBStatus(s);
   }
 
-  return kSuccess;
+  return ToDBStatus(destfile->Close());
 }
 
 DBStatus DBBatch::EnvWriteFile(DBSlice path, DBSlice contents) {
@@ -2268,6 +2278,9 @@ MVCCStatsResult MVCCComputeStatsInternal(
     const int64_t total_bytes = value.size() + kMVCCVersionTimestampSize;
     if (isSys) {
       stats.sys_bytes += total_bytes;
+      if (decoded_key.starts_with(kKeyLocalRangeTombstonePrefix)) {
+        stats.range_tombstone_count++;
+      }
     } else {
       if (first) {
         first = false;
@@ -74,6 +74,11 @@ typedef struct {
   bool logging_enabled;
   int num_cpu;
   int max_open_files;
+  // use_encryption enables reading and writing encrypted files. New
+  // files are encrypted with the key identified by encryption_key_id, or
+  // written in plaintext if it is empty.
+  bool use_encryption;
+  DBSlice encryption_key_id;
 } DBOptions;
 
 // Create a new cache with the specified size.
@@ -215,6 +220,7 @@ typedef struct {
   int64_t gc_bytes_age;
   int64_t sys_bytes;
   int64_t sys_count;
+  int64_t range_tombstone_count;
   int64_t last_update_nanos;
 } MVCCStatsResult;
 
@@ -0,0 +1,448 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+#include <cstdlib>
+#include <cstring>
+#include "db.h"
+#include "encryption.h"
+
+extern "C" {
+#include "_cgo_export.h"
+}  // extern "C"
+
+namespace {
+
+// GoStatus converts an error message returned by one of the Go
+// encryption functions to a status, freeing the message.
+rocksdb::Status GoStatus(const std::string& fname, char* err) {
+  if (err == NULL) {
+    return rocksdb::Status::OK();
+  }
+  rocksdb::Status s = rocksdb::Status::IOError(fname, err);
+  free(err);
+  return s;
+}
+
+// XORKeyStream encrypts or decrypts in place the n bytes of data found at
+// the given offset of the contents of a file with the given header.
+rocksdb::Status XORKeyStream(
+    const std::string& header, uint64_t offset, char* data, size_t n) {
+  return GoStatus("encryption", rocksDBEncryptionXORKeyStream(
+      const_cast<char*>(header.data()), offset, data, n));
+}
+
+// DecryptRead decrypts the result of a read at the given offset. The
+// result is decrypted in scratch, which is where most files put it
+// anyway.
+rocksdb::Status DecryptRead(
+    const std::string& header, uint64_t offset, rocksdb::Slice* result, char* scratch) {
+  if (result->empty()) {
+    return rocksdb::Status::OK();
+  }
+  if (result->data() != scratch) {
+    memmove(scratch, result->data(), result->size());
+    *result = rocksdb::Slice(scratch, result->size());
+  }
+  return XORKeyStream(header, offset, scratch, result->size());
+}
+
+class EncryptedSequentialFile : public rocksdb::SequentialFile {
+ public:
+  // file must be positioned after the header.
+  EncryptedSequentialFile(std::unique_ptr<rocksdb::SequentialFile> file,
+                          const std::string& header)
+      : file_(std::move(file)),
+        header_(header),
+        offset_(0) {
+  }
+
+  virtual rocksdb::Status Read(size_t n, rocksdb::Slice* result, char* scratch) override {
+    rocksdb::Status s = file_->Read(n, result, scratch);
+    if (!s.ok()) {
+      return s;
+    }
+    s = DecryptRead(header_, offset_, result, scratch);
+    offset_ += result->size();
+    return s;
+  }
+
+  virtual rocksdb::Status Skip(uint64_t n) override {
+    rocksdb::Status s = file_->Skip(n);
+    if (s.ok()) {
+      offset_ += n;
+    }
+    return s;
+  }
+
+  virtual rocksdb::Status InvalidateCache(size_t offset, size_t length) override {
+    return file_->InvalidateCache(offset + kEncryptionHeaderSize, length);
+  }
+
+ private:
+  std::unique_ptr<rocksdb::SequentialFile> file_;
+  const std::string header_;
+  uint64_t offset_;
+};
+
+class EncryptedRandomAccessFile : public rocksdb::RandomAccessFile {
+ public:
+  EncryptedRandomAccessFile(std::unique_ptr<rocksdb::RandomAccessFile> file,
+                            const std::string& header)
+      : file_(std::move(file)),
+        header_(header) {
+  }
+
+  virtual rocksdb::Status Read(uint64_t offset, size_t n, rocksdb::Slice* result,
+                               char* scratch) const override {
+    rocksdb::Status s = file_->Read(offset + kEncryptionHeaderSize, n, result, scratch);
+    if (!s.ok()) {
+      return s;
+    }
+    return DecryptRead(header_, offset, result, scratch);
+  }
+
+  virtual bool ShouldForwardRawRequest() const override {
+    return file_->ShouldForwardRawRequest();
+  }
+
+  virtual void EnableReadAhead() override {
+    file_->EnableReadAhead();
+  }
+
+  virtual size_t GetUniqueId(char* id, size_t max_size) const override {
+    return file_->GetUniqueId(id, max_size);
+  }
+
+  virtual void Hint(AccessPattern pattern) override {
+    file_->Hint(pattern);
+  }
+
+  virtual rocksdb::Status InvalidateCache(size_t offset, size_t length) override {
+    return file_->InvalidateCache(offset + kEncryptionHeaderSize, length);
+  }
+
+ private:
+  std::unique_ptr<rocksdb::RandomAccessFile> file_;
+  const std::string header_;
+};
+
+class EncryptedWritableFile : public rocksdb::WritableFile {
+ public:
+  // The header must already have been written to file.
+  EncryptedWritableFile(std::unique_ptr<rocksdb::WritableFile> file,
+                        const std::string& header)
+      : file_(std::move(file)),
+        header_(header),
+        offset_(0) {
+  }
+
+  virtual rocksdb::Status Append(const rocksdb::Slice& data) override {
+    buf_.assign(data.data(), data.size());
+    rocksdb::Status s = XORKeyStream(header_, offset_, &buf_[0], buf_.size());
+    if (!s.ok()) {
+      return s;
+    }
+    s = file_->Append(buf_);
+    if (s.ok()) {
+      offset_ += data.size();
+    }
+    return s;
+  }
+
+  virtual rocksdb::Status Truncate(uint64_t size) override {
+    rocksdb::Status s = file_->Truncate(size + kEncryptionHeaderSize);
+    if (s.ok()) {
+      offset_ = size;
+    }
+    return s;
+  }
+
+  virtual rocksdb::Status Close() override { return file_->Close(); }
+  virtual rocksdb::Status Flush() override { return file_->Flush(); }
+  virtual rocksdb::Status Sync() override { return file_->Sync(); }
+  virtual rocksdb::Status Fsync() override { return file_->Fsync(); }
+  virtual bool IsSyncThreadSafe() const override { return file_->IsSyncThreadSafe(); }
+
+  virtual void SetIOPriority(rocksdb::Env::IOPriority pri) override {
+    file_->SetIOPriority(pri);
+  }
+
+  virtual rocksdb::Env::IOPriority GetIOPriority() override {
+    return file_->GetIOPriority();
+  }
+
+  virtual uint64_t GetFileSize() override {
+    return offset_;
+  }
+
+  virtual void SetPreallocationBlockSize(size_t size) override {
+    file_->SetPreallocationBlockSize(size);
+  }
+
+  virtual void GetPreallocationStatus(size_t* block_size,
+                                      size_t* last_allocated_block) override {
+    file_->GetPreallocationStatus(block_size, last_allocated_block);
+  }
+
+  virtual size_t GetUniqueId(char* id, size_t max_size) const override {
+    return file_->GetUniqueId(id, max_size);
+  }
+
+  virtual rocksdb::Status InvalidateCache(size_t offset, size_t length) override {
+    return file_->InvalidateCache(offset + kEncryptionHeaderSize, length);
+  }
+
+  virtual rocksdb::Status RangeSync(uint64_t offset, uint64_t nbytes) override {
+    return file_->RangeSync(offset + kEncryptionHeaderSize, nbytes);
+  }
+
+  virtual void PrepareWrite(size_t offset, size_t len) override {
+    file_->PrepareWrite(offset + kEncryptionHeaderSize, len);
+  }
+
+ private:
+  std::unique_ptr<rocksdb::WritableFile> file_;
+  const std::string header_;
+  uint64_t offset_;
+  // buf_ holds the encrypted data being appended. It is reused across
+  // calls to Append to avoid allocations.
+  std::string buf_;
+};
+
+class EncryptedRandomRWFile : public rocksdb::RandomRWFile {
+ public:
+  // The header must already have been written to file.
+  EncryptedRandomRWFile(std::unique_ptr<rocksdb::RandomRWFile> file,
+                        const std::string& header)
+      : file_(std::move(file)),
+        header_(header) {
+  }
+
+  virtual rocksdb::Status Write(uint64_t offset, const rocksdb::Slice& data) override {
+    std::string buf(data.data(), data.size());
+    rocksdb::Status s = XORKeyStream(header_, offset, &buf[0], buf.size());
+    if (!s.ok()) {
+      return s;
+    }
+    return file_->Write(offset + kEncryptionHeaderSize, buf);
+  }
+
+  virtual rocksdb::Status Read(uint64_t offset, size_t n, rocksdb::Slice* result,
+                               char* scratch) const override {
+    rocksdb::Status s = file_->Read(offset + kEncryptionHeaderSize, n, result, scratch);
+    if (!s.ok()) {
+      return s;
+    }
+    return DecryptRead(header_, offset, result, scratch);
+  }
+
+  virtual rocksdb::Status Flush() override { return file_->Flush(); }
+  virtual rocksdb::Status Sync() override { return file_->Sync(); }
+  virtual rocksdb::Status Fsync() override { return file_->Fsync(); }
+  virtual rocksdb::Status Close() override { return file_->Close(); }
+
+ private:
+  std::unique_ptr<rocksdb::RandomRWFile> file_;
+  const std::string header_;
+};
+
+class EncryptedEnv : public rocksdb::EnvWrapper {
+ public:
+  EncryptedEnv(rocksdb::Env* base_env, const std::string& key_id)
+      : rocksdb::EnvWrapper(base_env),
+        key_id_(key_id) {
+  }
+
+  virtual rocksdb::Status NewSequentialFile(const std::string& fname,
+                                            std::unique_ptr<rocksdb::SequentialFile>* result,
+                                            const rocksdb::EnvOptions& options) override {
+    std::string header;
+    bool encrypted;
+    rocksdb::Status s = ReadHeader(fname, &header, &encrypted);
+    if (!s.ok()) {
+      return s;
+    }
+    std::unique_ptr<rocksdb::SequentialFile> file;
+    s = target()->NewSequentialFile(fname, &file, options);
+    if (!s.ok() || !encrypted) {
+      *result = std::move(file);
+      return s;
+    }
+    s = file->Skip(kEncryptionHeaderSize);
+    if (!s.ok()) {
+      return s;
+    }
+    result->reset(new EncryptedSequentialFile(std::move(file), header));
+    return rocksdb::Status::OK();
+  }
+
+  virtual rocksdb::Status NewRandomAccessFile(const std::string& fname,
+                                              std::unique_ptr<rocksdb::RandomAccessFile>* result,
+                                              const rocksdb::EnvOptions& options) override {
+    std::string header;
+    bool encrypted;
+    rocksdb::Status s = ReadHeader(fname, &header, &encrypted);
+    if (!s.ok()) {
+      return s;
+    }
+    std::unique_ptr<rocksdb::RandomAccessFile> file;
+    s = target()->NewRandomAccessFile(fname, &file, options);
+    if (!s.ok() || !encrypted) {
+      *result = std::move(file);
+      return s;
+    }
+    result->reset(new EncryptedRandomAccessFile(std::move(file), header));
+    return rocksdb::Status::OK();
+  }
+
+  virtual rocksdb::Status NewWritableFile(const std::string& fname,
+                                          std::unique_ptr<rocksdb::WritableFile>* result,
+                                          const rocksdb::EnvOptions& options) override {
+    std::unique_ptr<rocksdb::WritableFile> file;
+    rocksdb::Status s = target()->NewWritableFile(fname, &file, options);
+    if (!s.ok() || key_id_.empty()) {
+      *result = std::move(file);
+      return s;
+    }
+    std::string header;
+    s = NewHeader(fname, &header);
+    if (!s.ok()) {
+      return s;
+    }
+    s = file->Append(header);
+    if (!s.ok()) {
+      return s;
+    }
+    result->reset(new EncryptedWritableFile(std::move(file), header));
+    return rocksdb::Status::OK();
+  }
+
+  // Recycled files are overwritten from the start, which would leave
+  // stale data encrypted with another key or IV at the end of the file.
+  // Start from an empty file instead.
+  virtual rocksdb::Status ReuseWritableFile(const std::string& fname,
+                                            const std::string& old_fname,
+                                            std::unique_ptr<rocksdb::WritableFile>* result,
+                                            const rocksdb::EnvOptions& options) override {
+    rocksdb::Status s = target()->RenameFile(old_fname, fname);
+    if (!s.ok()) {
+      return s;
+    }
+    return NewWritableFile(fname, result, options);
+  }
+
+  virtual rocksdb::Status NewRandomRWFile(const std::string& fname,
+                                          std::unique_ptr<rocksdb::RandomRWFile>* result,
+                                          const rocksdb::EnvOptions& options) override {
+    std::string header;
+    bool encrypted = false;
+    if (target()->FileExists(fname).ok()) {
+      rocksdb::Status s = ReadHeader(fname, &header, &encrypted);
+      if (!s.ok()) {
+        return s;
+      }
+    }
+    std::unique_ptr<rocksdb::RandomRWFile> file;
+    rocksdb::Status s = target()->NewRandomRWFile(fname, &file, options);
+    if (!s.ok()) {
+      return s;
+    }
+    if (header.empty() && !key_id_.empty()) {
+      // The file is new or empty: encrypt it with the active key.
+      s = NewHeader(fname, &header);
+      if (!s.ok()) {
+        return s;
+      }
+      s = file->Write(0, header);
+      if (!s.ok()) {
+        return s;
+      }
+      encrypted = true;
+    }
+    if (!encrypted) {
+      *result = std::move(file);
+      return rocksdb::Status::OK();
+    }
+    result->reset(new EncryptedRandomRWFile(std::move(file), header));
+    return rocksdb::Status::OK();
+  }
+
+  // GetFileSize returns the size of the contents of the file, excluding
+  // the encryption header.
+  virtual rocksdb::Status GetFileSize(const std::string& fname, uint64_t* size) override {
+    rocksdb::Status s = target()->GetFileSize(fname, size);
+    if (!s.ok() || *size < kEncryptionHeaderSize) {
+      return s;
+    }
+    std::string header;
+    bool encrypted;
+    s = ReadHeader(fname, &header, &encrypted);
+    if (!s.ok()) {
+      return s;
+    }
+    if (encrypted) {
+      *size -= kEncryptionHeaderSize;
+    }
+    return rocksdb::Status::OK();
+  }
+
+  // EnvWrapper forwards GetChildrenFileAttributes to the wrapped Env,
+  // which would report sizes including the encryption header. The
+  // default implementation uses GetFileSize instead.
+  virtual rocksdb::Status GetChildrenFileAttributes(
+      const std::string& dir, std::vector<rocksdb::Env::FileAttributes>* result) override {
+    return rocksdb::Env::GetChildrenFileAttributes(dir, result);
+  }
+
+ private:
+  // ReadHeader reads the header of an existing file. encrypted is set to
+  // false if the file is in plaintext. An error is returned if the file is
+  // encrypted with an unknown key.
+  rocksdb::Status ReadHeader(const std::string& fname, std::string* header, bool* encrypted) {
+    *encrypted = false;
+    std::unique_ptr<rocksdb::RandomAccessFile> file;
+    rocksdb::Status s = target()->NewRandomAccessFile(fname, &file, rocksdb::EnvOptions());
+    if (!s.ok()) {
+      return s;
+    }
+    char scratch[kEncryptionHeaderSize];
+    rocksdb::Slice data;
+    s = file->Read(0, kEncryptionHeaderSize, &data, scratch);
+    if (!s.ok()) {
+      return s;
+    }
+    header->assign(data.data(), data.size());
+    int result = 0;
+    DBSlice slice = { const_cast<char*>(header->data()), static_cast<int>(header->size()) };
+    s = GoStatus(fname, rocksDBEncryptionCheckHeader(slice, &result));
+    *encrypted = result != 0;
+    return s;
+  }
+
+  // NewHeader generates the header of a new file encrypted with the
+  // active key.
+  rocksdb::Status NewHeader(const std::string& fname, std::string* header) {
+    header->assign(kEncryptionHeaderSize, '\0');
+    DBSlice key_id = { const_cast<char*>(key_id_.data()), static_cast<int>(key_id_.size()) };
+    return GoStatus(fname, rocksDBEncryptionNewHeader(key_id, &(*header)[0]));
+  }
+
+  const std::string key_id_;
+};
+
+}  // namespace
+
+rocksdb::Env* NewEncryptedEnv(rocksdb::Env* base_env, const std::string& key_id) {
+  return new EncryptedEnv(base_env, key_id);
+}
@@ -0,0 +1,322 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package engine
+
+// #include <stdlib.h>
+// #include "db.h"
+import "C"
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// Encryption at rest is implemented by a RocksDB Env (see encryption.cc)
+// which calls into the functions below for all cryptographic operations.
+//
+// Encrypted files start with a header of encryptionHeaderSize bytes:
+//
+//   magic (8 bytes) | key ID (32 bytes) | IV (16 bytes) | reserved (8 bytes)
+//
+// followed by the contents of the file, encrypted with AES in counter
+// mode. The key ID is the SHA-256 hash of the key, so that each file can
+// be decrypted with the key it was written with: files encrypted with an
+// older key remain readable after a key rotation until compactions have
+// rewritten them with the new key. Files without the magic are read as
+// plaintext, which lets encryption be enabled on existing stores.
+const (
+	// encryptionHeaderSize must match kEncryptionHeaderSize in
+	// encryption.h.
+	encryptionHeaderSize = 64
+	encryptionIVOffset   = len(encryptionMagic) + sha256.Size
+)
+
+const encryptionMagic = "crdbenc1"
+
+// EncryptionOptions configures encryption at rest for a RocksDB instance.
+// The zero value disables encryption.
+type EncryptionOptions struct {
+	// KeyFile is the path to a file containing the AES key (16, 24 or 32
+	// bytes) used to encrypt new files. If empty, new files are written in
+	// plaintext.
+	KeyFile string
+	// OldKeyFiles are the paths to keys previously used by the store. They
+	// are needed to read the files not yet rewritten with the current key.
+	OldKeyFiles []string
+}
+
+// Enabled returns whether encrypted files can be read or written.
+func (opts EncryptionOptions) Enabled() bool {
+	return opts.KeyFile != "" || len(opts.OldKeyFiles) > 0
+}
+
+type encryptionKeyID [sha256.Size]byte
+
+func (id encryptionKeyID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// encryptionKeys holds all the keys loaded by the stores of this process,
+// by key ID. Keys are never removed: an engine's files may be read by
+// RocksDB until the engine is closed, and there are few keys.
+var encryptionKeys struct {
+	syncutil.RWMutex
+	m map[encryptionKeyID]cipher.Block
+}
+
+// loadEncryptionKey reads a key from a file and makes it available to
+// encrypt and decrypt files. It returns the ID of the key.
+func loadEncryptionKey(path string) (encryptionKeyID, error) {
+	var id encryptionKeyID
+	key, err := ioutil.ReadFile(path)
+	if err != nil {
+		return id, errors.Wrap(err, "could not read encryption key")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return id, errors.Wrapf(err, "invalid encryption key %s", path)
+	}
+	id = sha256.Sum256(key)
+
+	encryptionKeys.Lock()
+	defer encryptionKeys.Unlock()
+	if encryptionKeys.m == nil {
+		encryptionKeys.m = make(map[encryptionKeyID]cipher.Block)
+	}
+	encryptionKeys.m[id] = block
+	return id, nil
+}
+
+// loadEncryptionKeys loads all the keys of the options. It returns the ID
+// of the key to use for new files, or nil if new files are not encrypted.
+func loadEncryptionKeys(opts EncryptionOptions) ([]byte, error) {
+	for _, path := range opts.OldKeyFiles {
+		if _, err := loadEncryptionKey(path); err != nil {
+			return nil, err
+		}
+	}
+	if opts.KeyFile == "" {
+		return nil, nil
+	}
+	id, err := loadEncryptionKey(opts.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return id[:], nil
+}
+
+func lookupEncryptionKey(id encryptionKeyID) (cipher.Block, bool) {
+	encryptionKeys.RLock()
+	defer encryptionKeys.RUnlock()
+	block, ok := encryptionKeys.m[id]
+	return block, ok
+}
+
+// parseEncryptionHeader returns the key ID and IV recorded in the header
+// of a file. ok is false if the file is not encrypted.
+func parseEncryptionHeader(header []byte) (id encryptionKeyID, iv []byte, ok bool) {
+	if len(header) < encryptionHeaderSize || !bytes.HasPrefix(header, []byte(encryptionMagic)) {
+		return id, nil, false
+	}
+	copy(id[:], header[len(encryptionMagic):])
+	return id, header[encryptionIVOffset : encryptionIVOffset+aes.BlockSize], true
+}
+
+// encryptionXORKeyStream encrypts or decrypts data in place, given its
+// offset in the file.
+func encryptionXORKeyStream(block cipher.Block, iv []byte, offset uint64, data []byte) {
+	// The counter of the block containing offset is the IV plus the
+	// number of blocks preceding it, as a 128-bit big-endian integer.
+	var counter [aes.BlockSize]byte
+	copy(counter[:], iv)
+	hi := binary.BigEndian.Uint64(counter[:8])
+	lo := binary.BigEndian.Uint64(counter[8:])
+	if sum := lo + offset/aes.BlockSize; sum < lo {
+		hi++
+	}
+	binary.BigEndian.PutUint64(counter[:8], hi)
+	binary.BigEndian.PutUint64(counter[8:], lo+offset/aes.BlockSize)
+
+	stream := cipher.NewCTR(block, counter[:])
+	if skip := offset % aes.BlockSize; skip != 0 {
+		var discard [aes.BlockSize]byte
+		stream.XORKeyStream(discard[:skip], discard[:skip])
+	}
+	stream.XORKeyStream(data, data)
+}
+
+//export rocksDBEncryptionNewHeader
+func rocksDBEncryptionNewHeader(keyID C.DBSlice, header *C.char) *C.char {
+	var id encryptionKeyID
+	copy(id[:], cSliceToUnsafeGoBytes(keyID))
+	if _, ok := lookupEncryptionKey(id); !ok {
+		return C.CString(fmt.Sprintf("unknown encryption key %s", id))
+	}
+	h := (*[encryptionHeaderSize]byte)(unsafe.Pointer(header))[:]
+	for i := range h {
+		h[i] = 0
+	}
+	copy(h, encryptionMagic)
+	copy(h[len(encryptionMagic):], id[:])
+	if _, err := rand.Read(h[encryptionIVOffset : encryptionIVOffset+aes.BlockSize]); err != nil {
+		return C.CString(err.Error())
+	}
+	return nil
+}
+
+//export rocksDBEncryptionCheckHeader
+func rocksDBEncryptionCheckHeader(header C.DBSlice, encrypted *C.int) *C.char {
+	id, _, ok := parseEncryptionHeader(cSliceToUnsafeGoBytes(header))
+	if !ok {
+		*encrypted = 0
+		return nil
+	}
+	*encrypted = 1
+	if _, ok := lookupEncryptionKey(id); !ok {
+		return C.CString(fmt.Sprintf("file is encrypted with unknown key %s", id))
+	}
+	return nil
+}
+
+//export rocksDBEncryptionXORKeyStream
+func rocksDBEncryptionXORKeyStream(
+	header *C.char, offset C.uint64_t, data *C.char, n C.size_t,
+) *C.char {
+	id, iv, ok := parseEncryptionHeader((*[encryptionHeaderSize]byte)(unsafe.Pointer(header))[:])
+	if !ok {
+		return C.CString("invalid encryption header")
+	}
+	block, ok := lookupEncryptionKey(id)
+	if !ok {
+		return C.CString(fmt.Sprintf("unknown encryption key %s", id))
+	}
+	if n > 0 {
+		buf := (*[maxArrayLen]byte)(unsafe.Pointer(data))[:n:n]
+		encryptionXORKeyStream(block, iv, uint64(offset), buf)
+	}
+	return nil
+}
+
+// EncryptionKeyUsage reports how much of the data of a store is encrypted
+// with a given key.
+type EncryptionKeyUsage struct {
+	// KeyID identifies the key. It is empty for files stored in plaintext.
+	KeyID string
+	Files int64
+	Bytes int64
+}
+
+// EncryptionStatus reports the progress of the encryption of a store.
+type EncryptionStatus struct {
+	// ActiveKeyID identifies the key used to encrypt new files. It is empty
+	// if new files are written in plaintext.
+	ActiveKeyID string
+	// Usage breaks down the data files of the store by key, sorted by key
+	// ID.
+	Usage []EncryptionKeyUsage
+}
+
+// isRocksDBDataFile returns whether a file of the RocksDB directory holds
+// user data: sstables, write-ahead logs and manifests.
+func isRocksDBDataFile(name string) bool {
+	return strings.HasSuffix(name, ".sst") || strings.HasSuffix(name, ".log") ||
+		strings.HasPrefix(name, "MANIFEST-")
+}
+
+// readEncryptionKeyID returns the ID of the key a file is encrypted with,
+// or an empty string if it is stored in plaintext.
+func readEncryptionKeyID(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	header := make([]byte, encryptionHeaderSize)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	id, _, ok := parseEncryptionHeader(header[:n])
+	if !ok {
+		return "", nil
+	}
+	return id.String(), nil
+}
+
+// EncryptionStatus reports which keys the data files of the engine are
+// encrypted with. After a key rotation, or after encryption is enabled on
+// an existing store, the remaining files are rewritten with the active key
+// as RocksDB compacts them.
+func (r *RocksDB) EncryptionStatus() (EncryptionStatus, error) {
+	var status EncryptionStatus
+	if r.encryptionKeyID != nil {
+		var id encryptionKeyID
+		copy(id[:], r.encryptionKeyID)
+		status.ActiveKeyID = id.String()
+	}
+	if len(r.dir) == 0 {
+		return status, nil
+	}
+
+	infos, err := ioutil.ReadDir(r.dir)
+	if err != nil {
+		return EncryptionStatus{}, err
+	}
+	usage := make(map[string]*EncryptionKeyUsage)
+	for _, info := range infos {
+		if !info.Mode().IsRegular() || !isRocksDBDataFile(info.Name()) {
+			continue
+		}
+		keyID, err := readEncryptionKeyID(filepath.Join(r.dir, info.Name()))
+		if err != nil {
+			if os.IsNotExist(err) {
+				// The file was deleted by a compaction.
+				continue
+			}
+			return EncryptionStatus{}, err
+		}
+		u, ok := usage[keyID]
+		if !ok {
+			u = &EncryptionKeyUsage{KeyID: keyID}
+			usage[keyID] = u
+		}
+		u.Files++
+		u.Bytes += info.Size()
+	}
+	for _, u := range usage {
+		status.Usage = append(status.Usage, *u)
+	}
+	sort.Slice(status.Usage, func(i, j int) bool {
+		return status.Usage[i].KeyID < status.Usage[j].KeyID
+	})
+	return status, nil
+}
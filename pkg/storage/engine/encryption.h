@@ -0,0 +1,37 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+#ifndef ROACHLIB_ENCRYPTION_H
+#define ROACHLIB_ENCRYPTION_H
+
+#include <string>
+
+#include <rocksdb/env.h>
+
+// kEncryptionHeaderSize is the size of the header prepended to encrypted
+// files. It must match encryptionHeaderSize in encryption.go, which
+// defines the layout of the header.
+const size_t kEncryptionHeaderSize = 64;
+
+// NewEncryptedEnv returns an Env which encrypts the files it writes with
+// the key identified by key_id, or writes them in plaintext if key_id is
+// empty. Files are read with the key recorded in their header, so that
+// files written before a key rotation, or before encryption was enabled,
+// remain readable. The cryptography itself is implemented in Go (see
+// encryption.go), which also holds the keys.
+//
+// The returned Env wraps base_env, which must outlive it.
+rocksdb::Env* NewEncryptedEnv(rocksdb::Env* base_env, const std::string& key_id);
+
+#endif // ROACHLIB_ENCRYPTION_H
@@ -0,0 +1,173 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package engine
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestEncryptionXORKeyStream(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	block, err := aes.NewCipher(bytes.Repeat([]byte{'k'}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The low half of the IV overflows after the first block, which tests
+	// the carry into the high half of the counter.
+	iv := []byte{0, 0, 0, 0, 0, 0, 0, 1, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	plaintext := make([]byte, 200)
+	for i := range plaintext {
+		plaintext[i] = byte(i)
+	}
+	expected := make([]byte, len(plaintext))
+	cipher.NewCTR(block, iv).XORKeyStream(expected, plaintext)
+
+	// Encrypting the data in chunks at arbitrary offsets must give the same
+	// result as encrypting it at once.
+	for _, chunkSize := range []int{1, 7, 16, 33, 200} {
+		data := append([]byte(nil), plaintext...)
+		for offset := 0; offset < len(data); offset += chunkSize {
+			end := offset + chunkSize
+			if end > len(data) {
+				end = len(data)
+			}
+			encryptionXORKeyStream(block, iv, uint64(offset), data[offset:end])
+		}
+		if !bytes.Equal(data, expected) {
+			t.Errorf("chunk size %d: expected %x, got %x", chunkSize, expected, data)
+		}
+		// Decryption is the same operation.
+		encryptionXORKeyStream(block, iv, 0, data)
+		if !bytes.Equal(data, plaintext) {
+			t.Errorf("chunk size %d: decryption failed", chunkSize)
+		}
+	}
+}
+
+func TestRocksDBEncryption(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	dir, dirCleanup := testutils.TempDir(t)
+	defer dirCleanup()
+
+	dbDir := filepath.Join(dir, "db")
+	keyFiles := make([]string, 2)
+	for i := range keyFiles {
+		keyFiles[i] = filepath.Join(dir, fmt.Sprintf("key%d", i))
+		if err := ioutil.WriteFile(keyFiles[i], bytes.Repeat([]byte{byte('a' + i)}, 32), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	const secret = "confidential-value"
+	key := MVCCKey{Key: roachpb.Key("a")}
+
+	open := func(opts EncryptionOptions) (*RocksDB, error) {
+		return NewEncryptedRocksDB(roachpb.Attributes{}, dbDir, RocksDBCache{}, 0, DefaultMaxOpenFiles, opts)
+	}
+	checkValue := func(db *RocksDB) {
+		value, err := db.Get(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(value) != secret {
+			t.Fatalf("expected %q, got %q", secret, value)
+		}
+	}
+	// checkStatus checks that all the data files are encrypted with the
+	// active key, and returns it.
+	checkStatus := func(db *RocksDB) string {
+		status, err := db.EncryptionStatus()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.ActiveKeyID == "" {
+			t.Fatal("expected an active key")
+		}
+		if len(status.Usage) != 1 || status.Usage[0].KeyID != status.ActiveKeyID {
+			t.Fatalf("expected all files to be encrypted with key %s, got %+v",
+				status.ActiveKeyID, status.Usage)
+		}
+		return status.ActiveKeyID
+	}
+
+	// Write a value with the first key.
+	db, err := open(EncryptionOptions{KeyFile: keyFiles[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put(key, []byte(secret)); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	checkValue(db)
+	firstKey := checkStatus(db)
+	db.Close()
+
+	// None of the data files may contain the value in plaintext.
+	infos, err := ioutil.ReadDir(dbDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, info := range infos {
+		if !isRocksDBDataFile(info.Name()) {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dbDir, info.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(data, []byte(secret)) {
+			t.Errorf("%s contains plaintext data", info.Name())
+		}
+	}
+
+	// The store cannot be opened without the key its files are encrypted
+	// with. Forget the keys loaded so far to check this.
+	encryptionKeys.Lock()
+	encryptionKeys.m = nil
+	encryptionKeys.Unlock()
+	if _, err := open(EncryptionOptions{KeyFile: keyFiles[1]}); !testutils.IsError(err, "unknown key") {
+		t.Fatalf("expected unknown key error, got %v", err)
+	}
+
+	// Rotate the key: the old files are rewritten with the new key by
+	// compactions.
+	db, err = open(EncryptionOptions{KeyFile: keyFiles[1], OldKeyFiles: keyFiles[:1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	checkValue(db)
+	if err := db.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	checkValue(db)
+	if secondKey := checkStatus(db); secondKey == firstKey {
+		t.Fatalf("expected the files to be rewritten with a new key, got %s", secondKey)
+	}
+}
@@ -99,6 +99,7 @@ func (ms *MVCCStats) Add(oms MVCCStats) {
 	ms.IntentCount += oms.IntentCount
 	ms.SysBytes += oms.SysBytes
 	ms.SysCount += oms.SysCount
+	ms.RangeTombstoneCount += oms.RangeTombstoneCount
 }
 
 // Subtract removes oms from ms. The ages will be moved forward to the larger of
@@ -123,6 +124,7 @@ func (ms *MVCCStats) Subtract(oms MVCCStats) {
 	ms.IntentCount -= oms.IntentCount
 	ms.SysBytes -= oms.SysBytes
 	ms.SysCount -= oms.SysCount
+	ms.RangeTombstoneCount -= oms.RangeTombstoneCount
 }
 
 // IsInline returns true if the value is inlined in the metadata.
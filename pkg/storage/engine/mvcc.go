@@ -21,6 +21,7 @@ import (
 	"bytes"
 	"fmt"
 	"math"
+	"sort"
 	"sync"
 	"time"
 
@@ -535,6 +536,9 @@ func (b *getBuffer) release() {
 // WriteIntentErrors. If set to false, a possible intent on the key will be
 // ignored for reading the value (but returned via the roachpb.Intent slice);
 // the previous value (if any) is read instead.
+//
+// Versions deleted by a range tombstone (see MVCCDeleteRangeUsingTombstone)
+// are treated like versions shadowed by a deletion.
 func MVCCGet(
 	ctx context.Context,
 	engine Reader,
@@ -543,10 +547,53 @@ func MVCCGet(
 	consistent bool,
 	txn *roachpb.Transaction,
 ) (*roachpb.Value, []roachpb.Intent, error) {
-	iter := engine.NewIterator(true)
+	return mvccGet(ctx, engine, key, timestamp, consistent, txn, true /* rangeTombstones */)
+}
+
+// MVCCGetIgnoringRangeTombstones is like MVCCGet, but doesn't look for range
+// tombstones covering the key. It may only be used to read from a range known
+// to hold no range tombstones, e.g. because the RangeTombstoneCount of its
+// MVCCStats is zero.
+func MVCCGetIgnoringRangeTombstones(
+	ctx context.Context,
+	engine Reader,
+	key roachpb.Key,
+	timestamp hlc.Timestamp,
+	consistent bool,
+	txn *roachpb.Transaction,
+) (*roachpb.Value, []roachpb.Intent, error) {
+	return mvccGet(ctx, engine, key, timestamp, consistent, txn, false /* rangeTombstones */)
+}
+
+func mvccGet(
+	ctx context.Context,
+	engine Reader,
+	key roachpb.Key,
+	timestamp hlc.Timestamp,
+	consistent bool,
+	txn *roachpb.Transaction,
+	rangeTombstones bool,
+) (*roachpb.Value, []roachpb.Intent, error) {
+	// Range tombstones only cover non-local keys. Looking them up takes a
+	// non-prefix iterator, which then serves both the key and its tombstones.
+	rangeTombstones = rangeTombstones && bytes.Compare(key, keys.LocalMax) >= 0
+	iter := engine.NewIterator(!rangeTombstones)
 	defer iter.Close()
 
-	return mvccGetUsingIter(ctx, iter, key, timestamp, consistent, txn)
+	value, intents, err := mvccGetUsingIter(ctx, iter, key, timestamp, consistent, txn)
+	if !rangeTombstones || value == nil || err != nil {
+		return value, intents, err
+	}
+	uncertaintyLimit := readUncertaintyLimit(timestamp, txn)
+	tombstones, err := mvccRangeTombstonesUsingIter(iter, key, key.Next(), uncertaintyLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+	if value, err = applyRangeTombstones(
+		tombstones, key, value, timestamp, uncertaintyLimit); err != nil {
+		return nil, nil, err
+	}
+	return value, intents, nil
 }
 
 func mvccGetUsingIter(
@@ -984,6 +1031,7 @@ func maybeGetValue(
 	readTS hlc.Timestamp,
 	txn *roachpb.Transaction,
 	buf *putBuffer,
+	tombstones []RangeTombstone,
 	valueFn func(*roachpb.Value) ([]byte, error),
 ) ([]byte, error) {
 	// If a valueFn is specified, read existing value using the iter.
@@ -1000,6 +1048,12 @@ func maybeGetValue(
 			ctx, iter, metaKey, readTS, true /* consistent */, safeValue, txn, getBuf); err != nil {
 			return nil, err
 		}
+		if exVal != nil {
+			if exVal, err = applyRangeTombstones(
+				tombstones, metaKey.Key, exVal, readTS, readTS); err != nil {
+				return nil, err
+			}
+		}
 	}
 	return valueFn(exVal)
 }
@@ -1046,7 +1100,7 @@ func mvccPutInternal(
 		}
 		var metaKeySize, metaValSize int64
 		if value, err = maybeGetValue(
-			ctx, iter, metaKey, value, ok, timestamp, txn, buf, valueFn); err != nil {
+			ctx, iter, metaKey, value, ok, timestamp, txn, buf, nil, valueFn); err != nil {
 			return err
 		}
 		if value == nil {
@@ -1061,11 +1115,27 @@ func mvccPutInternal(
 		return err
 	}
 
+	// Range tombstones count as writes to all of the keys they cover. Blind
+	// puts, which don't pass an iterator, are by contract writing keys which
+	// were never written and don't need to look for them, and inline values
+	// are never covered.
+	var tombstones []RangeTombstone
+	if reader, isReader := engine.(Reader); isReader && iter != nil && !putIsInline {
+		if tombstones, err = MVCCRangeTombstones(
+			reader, key, key.Next(), hlc.MaxTimestamp); err != nil {
+			return err
+		}
+	}
+	rangeTombstoneTS := rangeTombstoneTimestamp(tombstones, key, hlc.MaxTimestamp)
+
 	var meta *enginepb.MVCCMetadata
 	var maybeTooOldErr error
 	if ok {
 		// There is existing metadata for this key; ensure our write is permitted.
 		meta = &buf.meta
+		// A range tombstone newer than the latest version is the latest write.
+		latest := meta.Timestamp
+		latest.Forward(rangeTombstoneTS)
 
 		if meta.Txn != nil {
 			// There is an uncommitted write intent.
@@ -1087,7 +1157,7 @@ func mvccPutInternal(
 			// version.  For example, a conditional put within same
 			// transaction should read previous write.
 			if value, err = maybeGetValue(
-				ctx, iter, metaKey, value, ok, timestamp, txn, buf, valueFn); err != nil {
+				ctx, iter, metaKey, value, ok, timestamp, txn, buf, tombstones, valueFn); err != nil {
 				return err
 			}
 			// We are replacing our own older write intent. If we are
@@ -1100,7 +1170,7 @@ func mvccPutInternal(
 					return err
 				}
 			}
-		} else if !meta.Timestamp.Less(timestamp) {
+		} else if !latest.Less(timestamp) {
 			// This is the case where we're trying to write under a
 			// committed value. Obviously we can't do that, but we can
 			// increment our timestamp to one logical tick past the existing
@@ -1108,13 +1178,13 @@ func mvccPutInternal(
 			// error indicating what the timestamp ended up being. This
 			// timestamp can then be used to increment the txn timestamp and
 			// be returned with the response.
-			actualTimestamp := meta.Timestamp.Next()
+			actualTimestamp := latest.Next()
 			maybeTooOldErr = &roachpb.WriteTooOldError{Timestamp: timestamp, ActualTimestamp: actualTimestamp}
 			// If we're in a transaction, always get the value at the orig
 			// timestamp.
 			if txn != nil {
 				if value, err = maybeGetValue(
-					ctx, iter, metaKey, value, ok, timestamp, txn, buf, valueFn); err != nil {
+					ctx, iter, metaKey, value, ok, timestamp, txn, buf, tombstones, valueFn); err != nil {
 					return err
 				}
 			} else {
@@ -1122,22 +1192,29 @@ func mvccPutInternal(
 				// the write timestamp to the latest value's timestamp + 1. The
 				// new timestamp is returned to the caller in maybeTooOldErr.
 				if value, err = maybeGetValue(
-					ctx, iter, metaKey, value, ok, actualTimestamp, txn, buf, valueFn); err != nil {
+					ctx, iter, metaKey, value, ok, actualTimestamp, txn, buf, tombstones, valueFn); err != nil {
 					return err
 				}
 			}
 			timestamp = actualTimestamp
 		} else {
 			if value, err = maybeGetValue(
-				ctx, iter, metaKey, value, ok, timestamp, txn, buf, valueFn); err != nil {
+				ctx, iter, metaKey, value, ok, timestamp, txn, buf, tombstones, valueFn); err != nil {
 				return err
 			}
 		}
 	} else {
 		// There is no existing value for this key. Even if the new value is
-		// nil write a deletion tombstone for the key.
+		// nil write a deletion tombstone for the key. A range tombstone at or
+		// above our timestamp forces the write above it, as a newer version
+		// would.
+		if !rangeTombstoneTS.Less(timestamp) {
+			actualTimestamp := rangeTombstoneTS.Next()
+			maybeTooOldErr = &roachpb.WriteTooOldError{Timestamp: timestamp, ActualTimestamp: actualTimestamp}
+			timestamp = actualTimestamp
+		}
 		if value, err = maybeGetValue(
-			ctx, iter, metaKey, value, ok, timestamp, txn, buf, valueFn); err != nil {
+			ctx, iter, metaKey, value, ok, timestamp, txn, buf, tombstones, valueFn); err != nil {
 			return err
 		}
 	}
@@ -1453,6 +1530,614 @@ func MVCCDeleteRange(
 	return keys, resumeSpan, num, err
 }
 
+// RangeTombstone is a fragment of an MVCC range tombstone. It deletes all
+// versions of the keys in [StartKey, EndKey) written below Timestamp, as if a
+// deletion tombstone had been written for each of these keys at Timestamp.
+type RangeTombstone struct {
+	StartKey  roachpb.Key
+	EndKey    roachpb.Key
+	Timestamp hlc.Timestamp
+}
+
+// Covers returns whether the key lies within the fragment.
+func (t RangeTombstone) Covers(key roachpb.Key) bool {
+	return bytes.Compare(t.StartKey, key) <= 0 && bytes.Compare(key, t.EndKey) < 0
+}
+
+// mvccKey returns the key under which the fragment is stored.
+func (t RangeTombstone) mvccKey() MVCCKey {
+	return MVCCKey{Key: keys.RangeTombstoneKey(t.EndKey), Timestamp: t.Timestamp}
+}
+
+// putRangeTombstone writes the fragment, overwriting the fragment ending at
+// the same key at the same timestamp, if any. Its value is the start key,
+// which is only a lower bound: the versions of a fragment never extend before
+// the end key of the fragment stored before it, so that a range tombstone
+// which ends within an existing fragment doesn't need to rewrite it.
+func putRangeTombstone(engine Writer, t RangeTombstone) error {
+	return engine.Put(t.mvccKey(), t.StartKey)
+}
+
+// MVCCRangeTombstones returns the fragments of the range tombstones
+// overlapping [key, endKey) which were written at or below the specified
+// timestamp, ordered by end key and, within a fragment, by descending
+// timestamp. The versions of a fragment may start at different keys.
+// Fragments are clipped to start at or after key, but the last one may end
+// after endKey. Range tombstones only ever cover non-local keys.
+func MVCCRangeTombstones(
+	engine Reader, key, endKey roachpb.Key, timestamp hlc.Timestamp,
+) ([]RangeTombstone, error) {
+	if bytes.Compare(endKey, keys.LocalMax) <= 0 {
+		return nil, nil
+	}
+	iter := engine.NewIterator(false)
+	defer iter.Close()
+	tombstones, err := mvccRangeTombstonesUsingIter(iter, key, endKey, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tombstones {
+		if bytes.Compare(tombstones[i].StartKey, key) < 0 {
+			tombstones[i].StartKey = key
+		}
+	}
+	return tombstones, nil
+}
+
+// mvccRangeTombstonesUsingIter is like MVCCRangeTombstones, but uses the
+// supplied non-prefix iterator, whose position it changes, and doesn't clip
+// the fragments to key. Since the fragment stored before the first one isn't
+// read, the start keys of the first fragment are only lower bounds.
+//
+// Besides the fragments ending in (key, endKey], only the fragment ending
+// first after endKey is read. Requests only declare the keys of the former,
+// which include the keys both are looked up at (see SpanSetIterator).
+func mvccRangeTombstonesUsingIter(
+	iter Iterator, key, endKey roachpb.Key, timestamp hlc.Timestamp,
+) ([]RangeTombstone, error) {
+	if bytes.Compare(endKey, keys.LocalMax) <= 0 {
+		return nil, nil
+	}
+	if bytes.Compare(key, keys.LocalMax) < 0 {
+		key = keys.LocalMax
+	}
+
+	var tombstones []RangeTombstone
+	var prevEndKey roachpb.Key
+	seekKey := keys.RangeTombstoneKey(key.Next())
+	for reseeked := false; ; reseeked = true {
+		for iter.Seek(MakeMVCCMetadataKey(seekKey)); ; {
+			if ok, err := iter.Valid(); err != nil {
+				return nil, err
+			} else if !ok {
+				break
+			}
+			unsafeKey := iter.UnsafeKey()
+			if !bytes.HasPrefix(unsafeKey.Key, keys.LocalRangeTombstonePrefix) {
+				return tombstones, nil
+			}
+			fragKey := append(roachpb.Key(nil), unsafeKey.Key...)
+			fragEndKey, err := keys.DecodeRangeTombstoneKey(fragKey)
+			if err != nil {
+				return nil, err
+			}
+			if tombstones, err = appendRangeTombstoneVersions(
+				tombstones, iter, fragKey, prevEndKey, fragEndKey, endKey, timestamp,
+			); err != nil {
+				return nil, err
+			}
+			if bytes.Compare(fragEndKey, endKey) >= 0 {
+				// None of the fragments stored after this one overlap the span.
+				return tombstones, nil
+			}
+			prevEndKey = fragEndKey
+		}
+		if reseeked {
+			return tombstones, nil
+		}
+		// The iteration may have stopped short of the fragment ending after
+		// endKey because its key is outside of the keys declared by the
+		// request, so look it up directly. No fragment ends at endKey.
+		seekKey = keys.RangeTombstoneKey(endKey)
+	}
+}
+
+// appendRangeTombstoneVersions appends the versions at or below timestamp of
+// the fragment stored at fragKey, on which the iterator is positioned, to
+// tombstones, and advances the iterator past them. Versions are clipped to
+// start at or after prevEndKey, the end key of the fragment stored before it
+// (if known), and left out if they don't overlap [..., endKey).
+func appendRangeTombstoneVersions(
+	tombstones []RangeTombstone,
+	iter Iterator,
+	fragKey, prevEndKey, fragEndKey, endKey roachpb.Key,
+	timestamp hlc.Timestamp,
+) ([]RangeTombstone, error) {
+	for ; ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return nil, err
+		} else if !ok {
+			return tombstones, nil
+		}
+		unsafeKey := iter.UnsafeKey()
+		if !unsafeKey.Key.Equal(fragKey) {
+			return tombstones, nil
+		}
+		if !unsafeKey.IsValue() {
+			return nil, errors.Errorf("unexpected metadata for range tombstone %s", unsafeKey)
+		}
+		if timestamp.Less(unsafeKey.Timestamp) {
+			continue
+		}
+		startKey := prevEndKey
+		if bytes.Compare(iter.UnsafeValue(), prevEndKey) > 0 {
+			startKey = iter.Value()
+		}
+		if bytes.Compare(startKey, endKey) >= 0 || bytes.Compare(startKey, fragEndKey) >= 0 {
+			continue
+		}
+		tombstones = append(tombstones, RangeTombstone{
+			StartKey:  startKey,
+			EndKey:    fragEndKey,
+			Timestamp: unsafeKey.Timestamp,
+		})
+	}
+}
+
+// coveringRangeTombstones returns the versions of the fragment covering the
+// key among tombstones, which must be ordered as returned by
+// MVCCRangeTombstones.
+func coveringRangeTombstones(tombstones []RangeTombstone, key roachpb.Key) []RangeTombstone {
+	i := sort.Search(len(tombstones), func(i int) bool {
+		return bytes.Compare(key, tombstones[i].EndKey) < 0
+	})
+	j := i
+	for j < len(tombstones) && tombstones[j].EndKey.Equal(tombstones[i].EndKey) {
+		j++
+	}
+	// The versions of the fragment may start at different keys, so only
+	// copy the ones covering the key if some of them don't.
+	covering := tombstones[i:j]
+	for k, t := range covering {
+		if t.Covers(key) {
+			continue
+		}
+		filtered := append([]RangeTombstone(nil), covering[:k]...)
+		for _, t := range covering[k+1:] {
+			if t.Covers(key) {
+				filtered = append(filtered, t)
+			}
+		}
+		return filtered
+	}
+	return covering
+}
+
+// rangeTombstoneTimestamp returns the timestamp of the most recent range
+// tombstone covering the key at or below the specified timestamp, or the zero
+// timestamp if there is none.
+func rangeTombstoneTimestamp(
+	tombstones []RangeTombstone, key roachpb.Key, timestamp hlc.Timestamp,
+) hlc.Timestamp {
+	for _, t := range coveringRangeTombstones(tombstones, key) {
+		if !timestamp.Less(t.Timestamp) {
+			return t.Timestamp
+		}
+	}
+	return hlc.Timestamp{}
+}
+
+// readUncertaintyLimit returns the timestamp up to which a read at the
+// specified timestamp has to consider writes as possibly preceding it.
+func readUncertaintyLimit(timestamp hlc.Timestamp, txn *roachpb.Transaction) hlc.Timestamp {
+	if txn != nil && timestamp.Less(txn.MaxTimestamp) {
+		return txn.MaxTimestamp
+	}
+	return timestamp
+}
+
+// applyRangeTombstones returns nil if the value of the key read at readTS is
+// deleted by one of the range tombstones, and a
+// ReadWithinUncertaintyIntervalError if it isn't but is deleted by one written
+// in (readTS, uncertaintyLimit]. Otherwise, the value is returned unchanged.
+func applyRangeTombstones(
+	tombstones []RangeTombstone,
+	key roachpb.Key,
+	value *roachpb.Value,
+	readTS, uncertaintyLimit hlc.Timestamp,
+) (*roachpb.Value, error) {
+	if value.Timestamp == (hlc.Timestamp{}) {
+		// Inline values are never covered by range tombstones.
+		return value, nil
+	}
+	var uncertainTS hlc.Timestamp
+	for _, t := range coveringRangeTombstones(tombstones, key) {
+		if !value.Timestamp.Less(t.Timestamp) {
+			// The versions are ordered by descending timestamp, so none of the
+			// remaining ones are more recent than the value either.
+			break
+		}
+		if !readTS.Less(t.Timestamp) {
+			return nil, nil
+		}
+		if uncertainTS == (hlc.Timestamp{}) && !uncertaintyLimit.Less(t.Timestamp) {
+			uncertainTS = t.Timestamp
+		}
+	}
+	if uncertainTS != (hlc.Timestamp{}) {
+		return nil, roachpb.NewReadWithinUncertaintyIntervalError(readTS, uncertainTS)
+	}
+	return value, nil
+}
+
+// computeStats computes the stats of the data in [start, end).
+func computeStats(
+	reader Reader, start, end MVCCKey, nowNanos int64,
+) (enginepb.MVCCStats, error) {
+	iter := reader.NewIterator(false)
+	defer iter.Close()
+	return iter.ComputeStats(start, end, nowNanos)
+}
+
+// rangeTombstoneStatsSpan returns the span of the keys under which the range
+// tombstone fragments ending in [startKey, endKey] are stored.
+func rangeTombstoneStatsSpan(startKey, endKey roachpb.Key) (MVCCKey, MVCCKey) {
+	return MakeMVCCMetadataKey(keys.RangeTombstoneKey(startKey)),
+		MakeMVCCMetadataKey(keys.RangeTombstoneKey(endKey).Next())
+}
+
+// MVCCDeleteRangeUsingTombstone deletes the keys in [key, endKey) by writing
+// an MVCC range tombstone at the specified timestamp. Unlike MVCCDeleteRange,
+// the number of writes doesn't depend on the number of keys deleted: the
+// tombstone is stored as a new version of each of the fragments of the range
+// tombstones already ending within the span, plus a fragment ending at
+// endKey, and readers treat all the versions it covers as deleted until it
+// is garbage collected (see MVCCGarbageCollectRangeTombstones). Only the
+// range tombstone keys of the fragments ending in (key, endKey] are written.
+//
+// Range tombstones are written non-transactionally. The deletion fails with a
+// WriteIntentError if there are intents in the span, and with a
+// WriteTooOldError if any key in the span was written at or above the
+// timestamp. Note that the keys deleted remain accounted for as live in the
+// stats until they are garbage collected. If returnKeys is true, the keys
+// which were live below the tombstone are returned.
+func MVCCDeleteRangeUsingTombstone(
+	ctx context.Context,
+	engine ReadWriter,
+	ms *enginepb.MVCCStats,
+	key,
+	endKey roachpb.Key,
+	timestamp hlc.Timestamp,
+	returnKeys bool,
+) ([]roachpb.Key, error) {
+	if len(endKey) == 0 {
+		return nil, emptyKeyError()
+	}
+	if bytes.Compare(key, keys.LocalMax) < 0 {
+		return nil, errors.Errorf("cannot write range tombstone over local keys [%s,%s)", key, endKey)
+	}
+	if bytes.Compare(key, endKey) >= 0 {
+		return nil, errors.Errorf("invalid range tombstone span [%s,%s)", key, endKey)
+	}
+	if timestamp == (hlc.Timestamp{}) {
+		return nil, errors.Errorf("cannot write range tombstone without timestamp")
+	}
+
+	iter := engine.NewIterator(false)
+	defer iter.Close()
+	existing, err := mvccRangeTombstonesUsingIter(iter, key, endKey, hlc.MaxTimestamp)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := checkRangeTombstoneConflicts(engine, existing, key, endKey, timestamp, returnKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	statsStart, statsEnd := rangeTombstoneStatsSpan(key.Next(), endKey)
+	var origMS enginepb.MVCCStats
+	if ms != nil {
+		if origMS, err = computeStats(engine, statsStart, statsEnd, timestamp.WallTime); err != nil {
+			return nil, err
+		}
+	}
+
+	// Add the new version to the fragments ending within the span. Each of
+	// them starts at key at the earliest, since the fragment before it ends
+	// there at the latest.
+	var lastEndKey roachpb.Key
+	for _, t := range existing {
+		if bytes.Compare(t.EndKey, endKey) > 0 || t.EndKey.Equal(lastEndKey) {
+			continue
+		}
+		if err := putRangeTombstone(engine, RangeTombstone{
+			StartKey: key, EndKey: t.EndKey, Timestamp: timestamp,
+		}); err != nil {
+			return nil, err
+		}
+		lastEndKey = t.EndKey
+	}
+	if !endKey.Equal(lastEndKey) {
+		// The new fragment ending at endKey cuts the fragment straddling it, if
+		// any, which it takes the versions of the part before endKey over from.
+		for _, t := range existing {
+			if bytes.Compare(t.EndKey, endKey) > 0 {
+				if err := putRangeTombstone(engine, RangeTombstone{
+					StartKey: t.StartKey, EndKey: endKey, Timestamp: t.Timestamp,
+				}); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := putRangeTombstone(engine, RangeTombstone{
+			StartKey: key, EndKey: endKey, Timestamp: timestamp,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if ms != nil {
+		newMS, err := computeStats(engine, statsStart, statsEnd, timestamp.WallTime)
+		if err != nil {
+			return nil, err
+		}
+		ms.Add(newMS)
+		ms.Subtract(origMS)
+	}
+	log.Eventf(ctx, "wrote range tombstone over [%s,%s) at %s", key, endKey, timestamp)
+	return deleted, nil
+}
+
+// checkRangeTombstoneConflicts returns a WriteIntentError if there are
+// intents in [key, endKey), and a WriteTooOldError if a key in the span, or
+// one of the existing range tombstones overlapping it, was written at or
+// above the timestamp. If returnKeys is true, it returns the keys whose most
+// recent version is neither a deletion nor covered by the existing range
+// tombstones.
+func checkRangeTombstoneConflicts(
+	engine Reader,
+	existing []RangeTombstone,
+	key,
+	endKey roachpb.Key,
+	timestamp hlc.Timestamp,
+	returnKeys bool,
+) ([]roachpb.Key, error) {
+	var latest hlc.Timestamp
+	for _, t := range existing {
+		latest.Forward(t.Timestamp)
+	}
+
+	iter := engine.NewIterator(false)
+	defer iter.Close()
+
+	var intents []roachpb.Intent
+	var deleted []roachpb.Key
+	var meta enginepb.MVCCMetadata
+	encEndKey := MakeMVCCMetadataKey(endKey)
+	// The metadata or most recent version of every key comes first, so we
+	// only need to look at one entry per key.
+	for iter.Seek(MakeMVCCMetadataKey(key)); ; iter.NextKey() {
+		if ok, err := iter.Valid(); err != nil {
+			return nil, err
+		} else if !ok {
+			break
+		}
+		unsafeKey := iter.UnsafeKey()
+		if !unsafeKey.Less(encEndKey) {
+			break
+		}
+		if unsafeKey.IsValue() {
+			latest.Forward(unsafeKey.Timestamp)
+			if returnKeys && len(iter.UnsafeValue()) > 0 &&
+				!unsafeKey.Timestamp.Less(rangeTombstoneTimestamp(existing, unsafeKey.Key, hlc.MaxTimestamp)) {
+				deleted = append(deleted, iter.Key().Key)
+			}
+			continue
+		}
+		if err := iter.ValueProto(&meta); err != nil {
+			return nil, err
+		}
+		if meta.Txn != nil {
+			intents = append(intents, roachpb.Intent{
+				Span: roachpb.Span{Key: iter.Key().Key}, Status: roachpb.PENDING, Txn: *meta.Txn,
+			})
+		}
+		latest.Forward(meta.Timestamp)
+	}
+
+	if len(intents) > 0 {
+		return nil, &roachpb.WriteIntentError{Intents: intents}
+	}
+	if !latest.Less(timestamp) {
+		return nil, &roachpb.WriteTooOldError{Timestamp: timestamp, ActualTimestamp: latest.Next()}
+	}
+	return deleted, nil
+}
+
+// MVCCIterateVersions calls f with the versions of the keys in [key, endKey)
+// written in (startTime, endTime], in key order and, for each key, by
+// ascending timestamp. Deletions are passed as values without data, which
+// includes the deletion of a key by a range tombstone written in the
+// interval over a version which wasn't deleted yet. The provisional values
+// of intents are skipped, and their metadata is passed to intentFn instead,
+// if it is non-nil. Inline values are skipped.
+func MVCCIterateVersions(
+	engine Reader,
+	key,
+	endKey roachpb.Key,
+	startTime,
+	endTime hlc.Timestamp,
+	f func(roachpb.KeyValue) error,
+	intentFn func(roachpb.Key, enginepb.MVCCMetadata) error,
+) error {
+	tombstones, err := MVCCRangeTombstones(engine, key, endKey, endTime)
+	if err != nil {
+		return err
+	}
+
+	iter := engine.NewIterator(false)
+	defer iter.Close()
+
+	var meta enginepb.MVCCMetadata
+	var versions []roachpb.KeyValue
+	encEndKey := MakeMVCCMetadataKey(endKey)
+	iter.Seek(MakeMVCCMetadataKey(key))
+	for {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+		unsafeKey := iter.UnsafeKey()
+		if !unsafeKey.Less(encEndKey) {
+			return nil
+		}
+		curKey := iter.Key().Key
+
+		// Skip the metadata and, for an intent, the provisional value.
+		var intentTS hlc.Timestamp
+		if !unsafeKey.IsValue() {
+			if err := iter.ValueProto(&meta); err != nil {
+				return err
+			}
+			if meta.IsInline() {
+				iter.NextKey()
+				continue
+			}
+			if meta.Txn != nil {
+				intentTS = meta.Timestamp
+				if intentFn != nil {
+					if err := intentFn(curKey, meta); err != nil {
+						return err
+					}
+				}
+			}
+			iter.Next()
+		}
+
+		// Collect the versions of the key, which are ordered by descending
+		// timestamp.
+		versions = versions[:0]
+		for ; ; iter.Next() {
+			if ok, err := iter.Valid(); err != nil {
+				return err
+			} else if !ok {
+				break
+			}
+			unsafeKey := iter.UnsafeKey()
+			if !unsafeKey.Key.Equal(curKey) {
+				break
+			}
+			if endTime.Less(unsafeKey.Timestamp) || unsafeKey.Timestamp == intentTS {
+				continue
+			}
+			versions = append(versions, roachpb.KeyValue{
+				Key: curKey,
+				Value: roachpb.Value{
+					RawBytes:  append([]byte(nil), iter.UnsafeValue()...),
+					Timestamp: unsafeKey.Timestamp,
+				},
+			})
+		}
+
+		// Merge the versions with the range tombstones covering the key, from
+		// the oldest to the most recent one, keeping track of whether the key
+		// is live to know whether a range tombstone deletes it.
+		covering := coveringRangeTombstones(tombstones, curKey)
+		i, j := len(versions)-1, len(covering)-1
+		live := false
+		for i >= 0 || j >= 0 {
+			if j < 0 || (i >= 0 && !covering[j].Timestamp.Less(versions[i].Value.Timestamp)) {
+				kv := versions[i]
+				i--
+				live = len(kv.Value.RawBytes) > 0
+				if startTime.Less(kv.Value.Timestamp) {
+					if err := f(kv); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			ts := covering[j].Timestamp
+			j--
+			if live && startTime.Less(ts) {
+				if err := f(roachpb.KeyValue{Key: curKey, Value: roachpb.Value{Timestamp: ts}}); err != nil {
+					return err
+				}
+			}
+			live = false
+		}
+	}
+}
+
+// MVCCSplitRangeTombstones splits the range tombstone fragments straddling
+// splitKey, so that no fragment crosses the boundary between the ranges
+// resulting from a split at it. The versions of the first fragment ending
+// after splitKey are made to start at or after it, so that the fragments of
+// the right-hand range don't depend on the ones of the left-hand range.
+func MVCCSplitRangeTombstones(
+	ctx context.Context, engine ReadWriter, ms *enginepb.MVCCStats, splitKey roachpb.Key, nowNanos int64,
+) error {
+	iter := engine.NewIterator(false)
+	defer iter.Close()
+	tombstones, err := mvccRangeTombstonesUsingIter(iter, splitKey, splitKey.Next(), hlc.MaxTimestamp)
+	if err != nil {
+		return err
+	}
+	var straddling []RangeTombstone
+	for _, t := range tombstones {
+		if !t.EndKey.Equal(tombstones[0].EndKey) {
+			break
+		}
+		if bytes.Compare(t.StartKey, splitKey) < 0 {
+			straddling = append(straddling, t)
+		}
+	}
+	if len(straddling) == 0 {
+		return nil
+	}
+	// The start keys of the versions are only lower bounds: they don't cross
+	// splitKey if a fragment ends there.
+	splitKeyTombstoneKey := MakeMVCCMetadataKey(keys.RangeTombstoneKey(splitKey))
+	iter.Seek(splitKeyTombstoneKey)
+	ok, err := iter.Valid()
+	if err != nil {
+		return err
+	}
+	crossing := !ok || !iter.UnsafeKey().Key.Equal(splitKeyTombstoneKey.Key)
+
+	statsStart, statsEnd := rangeTombstoneStatsSpan(splitKey, straddling[0].EndKey)
+	var origMS enginepb.MVCCStats
+	if ms != nil {
+		if origMS, err = computeStats(engine, statsStart, statsEnd, nowNanos); err != nil {
+			return err
+		}
+	}
+	for _, t := range straddling {
+		splits := []RangeTombstone{{StartKey: splitKey, EndKey: t.EndKey, Timestamp: t.Timestamp}}
+		if crossing {
+			splits = append(splits, RangeTombstone{
+				StartKey: t.StartKey, EndKey: splitKey, Timestamp: t.Timestamp,
+			})
+		}
+		for _, split := range splits {
+			if err := putRangeTombstone(engine, split); err != nil {
+				return err
+			}
+		}
+	}
+	if ms != nil {
+		newMS, err := computeStats(engine, statsStart, statsEnd, nowNanos)
+		if err != nil {
+			return err
+		}
+		ms.Add(newMS)
+		ms.Subtract(origMS)
+	}
+	log.Eventf(ctx, "split %d range tombstone versions at %s", len(straddling), splitKey)
+	return nil
+}
+
 // getScanMeta returns the MVCCMetadata the iterator is currently pointed at
 // (reconstructing it if the metadata is implicit). Note that the returned
 // MVCCKey is unsafe and will be invalidated by the next call to
@@ -1623,6 +2308,14 @@ func MVCCIterate(
 		return nil, emptyKeyError()
 	}
 
+	// Versions deleted by range tombstones are skipped like versions shadowed
+	// by deletions.
+	uncertaintyLimit := readUncertaintyLimit(timestamp, txn)
+	tombstones, err := MVCCRangeTombstones(engine, startKey, endKey, uncertaintyLimit)
+	if err != nil {
+		return nil, err
+	}
+
 	buf := newGetBuffer()
 	defer buf.release()
 
@@ -1693,6 +2386,13 @@ func MVCCIterate(
 		// Indicate that we're fine with an unsafe Value.RawBytes being returned.
 		value, newIntents, valueSafety, err := mvccGetInternal(
 			ctx, iter, metaKey, timestamp, consistent, unsafeValue, txn, buf)
+		if value != nil && len(tombstones) > 0 {
+			var tErr error
+			if value, tErr = applyRangeTombstones(
+				tombstones, metaKey.Key, value, timestamp, uncertaintyLimit); tErr != nil {
+				return nil, tErr
+			}
+		}
 		intents = append(intents, newIntents...)
 		if value != nil {
 			if valueSafety == unsafeValue {
@@ -2211,6 +2911,194 @@ func MVCCGarbageCollect(
 	return nil
 }
 
+// MVCCGarbageCollectRangeTombstones garbage collects the range tombstones
+// in [key, endKey) written at or below threshold. For each fragment, all
+// versions of the keys it covers which are older than its most recent
+// version covering them at or below threshold are cleared, followed by these
+// fragment versions themselves. Fragments not contained in the span are left
+// alone. The timestamp parameter is used to compute the GC'able bytes age of
+// the remaining data. Garbage collection stops after clearing maxClears
+// values (to limit the size of the WriteBatch produced); the fragments whose
+// keys weren't all cleared are kept and picked up on the next call.
+func MVCCGarbageCollectRangeTombstones(
+	ctx context.Context,
+	engine ReadWriter,
+	ms *enginepb.MVCCStats,
+	key,
+	endKey roachpb.Key,
+	threshold hlc.Timestamp,
+	timestamp hlc.Timestamp,
+	maxClears int64,
+) error {
+	iter := engine.NewIterator(false)
+	defer iter.Close()
+	tombstones, err := mvccRangeTombstonesUsingIter(iter, key, endKey, threshold)
+	if err != nil {
+		return err
+	}
+
+	var count int64
+	defer func(begin time.Time) {
+		log.Eventf(ctx, "done with range tombstone GC evaluation for %d versions at %.2f versions/sec. "+
+			"Deleted %d versions", len(tombstones), float64(len(tombstones))*1E9/float64(timeutil.Since(begin)), count)
+	}(timeutil.Now())
+
+	for i := 0; i < len(tombstones); {
+		// The versions of a fragment are adjacent, the most recent one first.
+		t := tombstones[i]
+		j := i + 1
+		for j < len(tombstones) && tombstones[j].EndKey.Equal(t.EndKey) {
+			j++
+		}
+		versions := tombstones[i:j]
+		i = j
+		if bytes.Compare(endKey, t.EndKey) < 0 {
+			continue
+		}
+		startKey := t.EndKey
+		for _, v := range versions {
+			if bytes.Compare(v.StartKey, startKey) < 0 {
+				startKey = v.StartKey
+			}
+		}
+		if bytes.Compare(startKey, key) < 0 {
+			continue
+		}
+
+		dataStart, dataEnd := MakeMVCCMetadataKey(startKey), MakeMVCCMetadataKey(t.EndKey)
+		tombstoneStart, tombstoneEnd := rangeTombstoneStatsSpan(t.EndKey, t.EndKey)
+		var origMS enginepb.MVCCStats
+		if ms != nil {
+			dataMS, err := computeStats(engine, dataStart, dataEnd, timestamp.WallTime)
+			if err != nil {
+				return err
+			}
+			tombstoneMS, err := computeStats(engine, tombstoneStart, tombstoneEnd, timestamp.WallTime)
+			if err != nil {
+				return err
+			}
+			origMS.Add(dataMS)
+			origMS.Add(tombstoneMS)
+		}
+
+		done, err := func() (bool, error) {
+			iter := engine.NewIterator(false)
+			defer iter.Close()
+			for iter.Seek(dataStart); ; iter.Next() {
+				if ok, err := iter.Valid(); err != nil {
+					return false, err
+				} else if !ok {
+					break
+				}
+				unsafeIterKey := iter.UnsafeKey()
+				if !unsafeIterKey.Less(dataEnd) {
+					break
+				}
+				if !unsafeIterKey.IsValue() || !unsafeIterKey.Timestamp.Less(
+					rangeTombstoneTimestamp(versions, unsafeIterKey.Key, threshold)) {
+					continue
+				}
+				if err := engine.Clear(unsafeIterKey); err != nil {
+					return false, err
+				}
+				count++
+				if count >= maxClears {
+					return false, nil
+				}
+			}
+			for _, v := range versions {
+				if err := engine.Clear(v.mvccKey()); err != nil {
+					return false, err
+				}
+				count++
+			}
+			return true, nil
+		}()
+		if err != nil {
+			return err
+		}
+
+		if ms != nil {
+			var newMS enginepb.MVCCStats
+			dataMS, err := computeStats(engine, dataStart, dataEnd, timestamp.WallTime)
+			if err != nil {
+				return err
+			}
+			tombstoneMS, err := computeStats(engine, tombstoneStart, tombstoneEnd, timestamp.WallTime)
+			if err != nil {
+				return err
+			}
+			newMS.Add(dataMS)
+			newMS.Add(tombstoneMS)
+			ms.Add(newMS)
+			ms.Subtract(origMS)
+		}
+		if done {
+			if err := clampNextRangeTombstone(engine, ms, t.EndKey, timestamp.WallTime); err != nil {
+				return err
+			}
+		}
+		if !done || count >= maxClears {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// clampNextRangeTombstone makes the versions of the fragment stored after
+// the one ending at endKey start at or after endKey if the latter no longer
+// has any versions, since the start keys of the former are only lower bounds
+// which the latter used to enforce.
+func clampNextRangeTombstone(
+	engine ReadWriter, ms *enginepb.MVCCStats, endKey roachpb.Key, nowNanos int64,
+) error {
+	iter := engine.NewIterator(false)
+	defer iter.Close()
+	iter.Seek(MakeMVCCMetadataKey(keys.RangeTombstoneKey(endKey)))
+	if ok, err := iter.Valid(); err != nil || !ok {
+		return err
+	}
+	fragKey := iter.Key().Key
+	if !bytes.HasPrefix(fragKey, keys.LocalRangeTombstonePrefix) ||
+		fragKey.Equal(keys.RangeTombstoneKey(endKey)) {
+		return nil
+	}
+	fragEndKey, err := keys.DecodeRangeTombstoneKey(fragKey)
+	if err != nil {
+		return err
+	}
+	// The versions starting at or after endKey are left out.
+	versions, err := appendRangeTombstoneVersions(
+		nil, iter, fragKey, nil /* prevEndKey */, fragEndKey, endKey, hlc.MaxTimestamp)
+	if err != nil || len(versions) == 0 {
+		return err
+	}
+
+	statsStart, statsEnd := rangeTombstoneStatsSpan(fragEndKey, fragEndKey)
+	var origMS enginepb.MVCCStats
+	if ms != nil {
+		if origMS, err = computeStats(engine, statsStart, statsEnd, nowNanos); err != nil {
+			return err
+		}
+	}
+	for _, t := range versions {
+		t.StartKey = endKey
+		if err := putRangeTombstone(engine, t); err != nil {
+			return err
+		}
+	}
+	if ms != nil {
+		newMS, err := computeStats(engine, statsStart, statsEnd, nowNanos)
+		if err != nil {
+			return err
+		}
+		ms.Add(newMS)
+		ms.Subtract(origMS)
+	}
+	return nil
+}
+
 // IsValidSplitKey returns whether the key is a valid split key. Certain key
 // ranges cannot be split (the meta1 span and the system DB span); split keys
 // chosen within any of these ranges are considered invalid. And a split key
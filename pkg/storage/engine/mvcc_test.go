@@ -1898,6 +1898,396 @@ func TestMVCCDeleteRangeInline(t *testing.T) {
 	}
 }
 
+// scanKeys returns the keys MVCCScan finds in the whole keyspace at the
+// timestamp.
+func scanKeys(t *testing.T, engine Reader, ts hlc.Timestamp) []roachpb.Key {
+	kvs, _, _, err := MVCCScan(context.Background(), engine, keyMin, keyMax, math.MaxInt64, ts, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []roachpb.Key
+	for _, kv := range kvs {
+		keys = append(keys, kv.Key)
+	}
+	return keys
+}
+
+// verifyComputedStats verifies that the aggregated stats match the ones
+// computed over the whole keyspace.
+func verifyComputedStats(t *testing.T, debug string, engine Reader, ms *enginepb.MVCCStats, nowNanos int64) {
+	iter := engine.NewIterator(false)
+	expMS, err := iter.ComputeStats(mvccKey(roachpb.KeyMin), mvccKey(roachpb.KeyMax), nowNanos)
+	iter.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyStats(debug, ms, &expMS, t)
+}
+
+func TestMVCCDeleteRangeUsingTombstone(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	engine := createTestEngine()
+	defer engine.Close()
+
+	ctx := context.Background()
+	ts1 := hlc.Timestamp{WallTime: 1}
+	ts2 := hlc.Timestamp{WallTime: 2}
+	ts3 := hlc.Timestamp{WallTime: 3}
+	ts4 := hlc.Timestamp{WallTime: 4}
+
+	ms := &enginepb.MVCCStats{}
+	for i, kv := range []roachpb.KeyValue{
+		{Key: testKey1, Value: value1},
+		{Key: testKey2, Value: value2},
+		{Key: testKey3, Value: value3},
+		{Key: testKey4, Value: value4},
+		{Key: testKey5, Value: value5},
+		{Key: testKey6, Value: value6},
+	} {
+		if err := MVCCPut(ctx, engine, ms, kv.Key, ts1, kv.Value, nil); err != nil {
+			t.Fatalf("%d: %s", i, err)
+		}
+	}
+
+	deleted, err := MVCCDeleteRangeUsingTombstone(ctx, engine, ms, testKey2, testKey5, ts2, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expKeys := []roachpb.Key{testKey2, testKey3, testKey4}; !reflect.DeepEqual(deleted, expKeys) {
+		t.Fatalf("expected deleted keys %s; got %s", expKeys, deleted)
+	}
+	verifyComputedStats(t, "after range tombstone", engine, ms, ts2.WallTime)
+	if ms.RangeTombstoneCount != 1 {
+		t.Fatalf("expected 1 range tombstone version; got %d", ms.RangeTombstoneCount)
+	}
+
+	// Reads below the tombstone still see the keys.
+	if value, _, err := MVCCGet(ctx, engine, testKey3, ts1, true, nil); err != nil {
+		t.Fatal(err)
+	} else if value == nil {
+		t.Fatal("expected to read value below range tombstone")
+	}
+	if value, _, err := MVCCGet(ctx, engine, testKey3, ts2, true, nil); err != nil {
+		t.Fatal(err)
+	} else if value != nil {
+		t.Fatalf("expected deleted value; got %+v", value)
+	}
+	if keys := scanKeys(t, engine, ts1); len(keys) != 6 {
+		t.Fatalf("expected 6 keys below range tombstone; got %s", keys)
+	}
+	if keys, expKeys := scanKeys(t, engine, ts2), []roachpb.Key{testKey1, testKey5, testKey6}; !reflect.DeepEqual(keys, expKeys) {
+		t.Fatalf("expected keys %s; got %s", expKeys, keys)
+	}
+
+	// A transactional read below the tombstone whose uncertainty interval
+	// contains it must restart.
+	txn := makeTxn(*txn1, ts1)
+	txn.MaxTimestamp = ts2
+	if _, _, err := MVCCGet(ctx, engine, testKey3, ts1, true, txn); err == nil {
+		t.Fatal("expected uncertainty error")
+	} else if _, ok := err.(*roachpb.ReadWithinUncertaintyIntervalError); !ok {
+		t.Fatalf("expected uncertainty error; got %s", err)
+	}
+
+	// Writes at or below the tombstone are pushed above it.
+	err = MVCCPut(ctx, engine, ms, testKey3, ts2, value1, nil)
+	if wtoErr, ok := err.(*roachpb.WriteTooOldError); !ok || wtoErr.ActualTimestamp != ts2.Next() {
+		t.Fatalf("expected WriteTooOldError with actual timestamp %s; got %v", ts2.Next(), err)
+	}
+	if value, _, err := MVCCGet(ctx, engine, testKey3, ts3, true, nil); err != nil {
+		t.Fatal(err)
+	} else if value == nil || !bytes.Equal(value.RawBytes, value1.RawBytes) {
+		t.Fatalf("expected value %+v; got %+v", value1, value)
+	}
+
+	// An overlapping tombstone splits the fragments of the existing one. Only
+	// the keys the existing one doesn't cover are returned as deleted.
+	deleted, err = MVCCDeleteRangeUsingTombstone(ctx, engine, ms, testKey4, testKey6, ts3, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expKeys := []roachpb.Key{testKey5}; !reflect.DeepEqual(deleted, expKeys) {
+		t.Fatalf("expected deleted keys %s; got %s", expKeys, deleted)
+	}
+	verifyComputedStats(t, "after overlapping range tombstone", engine, ms, ts3.WallTime)
+	if keys, expKeys := scanKeys(t, engine, ts3), []roachpb.Key{testKey1, testKey3, testKey6}; !reflect.DeepEqual(keys, expKeys) {
+		t.Fatalf("expected keys %s; got %s", expKeys, keys)
+	}
+	if keys, expKeys := scanKeys(t, engine, ts2), []roachpb.Key{testKey1, testKey5, testKey6}; !reflect.DeepEqual(keys, expKeys) {
+		t.Fatalf("expected keys %s; got %s", expKeys, keys)
+	}
+	tombstones, err := MVCCRangeTombstones(engine, keyMin, keyMax, hlc.MaxTimestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expTombstones := []RangeTombstone{
+		{StartKey: testKey4, EndKey: testKey5, Timestamp: ts3},
+		{StartKey: testKey2, EndKey: testKey5, Timestamp: ts2},
+		{StartKey: testKey5, EndKey: testKey6, Timestamp: ts3},
+	}
+	if !reflect.DeepEqual(tombstones, expTombstones) {
+		t.Fatalf("expected range tombstones %+v; got %+v", expTombstones, tombstones)
+	}
+	if ms.RangeTombstoneCount != int64(len(expTombstones)) {
+		t.Fatalf("expected %d range tombstone versions; got %d", len(expTombstones), ms.RangeTombstoneCount)
+	}
+
+	// Range tombstones can't be written over intents or below existing writes.
+	if err := MVCCPut(ctx, engine, ms, testKey6, ts4, value2, makeTxn(*txn1, ts4)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MVCCDeleteRangeUsingTombstone(
+		ctx, engine, ms, testKey6, testKey6.Next(), ts4.Next(), false,
+	); !testutils.IsError(err, "conflicting intents") {
+		t.Fatalf("expected WriteIntentError; got %v", err)
+	}
+	_, err = MVCCDeleteRangeUsingTombstone(ctx, engine, ms, testKey1, testKey4, ts2, false)
+	if wtoErr, ok := err.(*roachpb.WriteTooOldError); !ok || wtoErr.ActualTimestamp != ts2.Next().Next() {
+		t.Fatalf("expected WriteTooOldError with actual timestamp %s; got %v", ts2.Next().Next(), err)
+	}
+	if _, err := MVCCDeleteRangeUsingTombstone(ctx, engine, ms, keys.LocalMax, testKey1, ts4, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MVCCDeleteRangeUsingTombstone(
+		ctx, engine, ms, keys.LocalRangePrefix, testKey1, ts4, false,
+	); !testutils.IsError(err, "cannot write range tombstone over local keys") {
+		t.Fatalf("expected error for local keys; got %v", err)
+	}
+}
+
+func TestMVCCGarbageCollectRangeTombstones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	engine := createTestEngine()
+	defer engine.Close()
+
+	ctx := context.Background()
+	ts1 := hlc.Timestamp{WallTime: 1}
+	ts2 := hlc.Timestamp{WallTime: 2}
+	ts3 := hlc.Timestamp{WallTime: 3}
+	ts4 := hlc.Timestamp{WallTime: 4}
+
+	ms := &enginepb.MVCCStats{}
+	for i, key := range []roachpb.Key{testKey1, testKey2, testKey3, testKey4} {
+		if err := MVCCPut(ctx, engine, ms, key, ts1, value1, nil); err != nil {
+			t.Fatalf("%d: %s", i, err)
+		}
+	}
+	if _, err := MVCCDeleteRangeUsingTombstone(ctx, engine, ms, testKey2, testKey4, ts2, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MVCCDeleteRangeUsingTombstone(ctx, engine, ms, testKey1, testKey2, ts4, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := MVCCPut(ctx, engine, ms, testKey3, ts3, value2, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the tombstone below the threshold is collected, along with the
+	// versions it deletes.
+	if err := MVCCGarbageCollectRangeTombstones(
+		ctx, engine, ms, keyMin, keyMax, ts3, ts4, math.MaxInt64,
+	); err != nil {
+		t.Fatal(err)
+	}
+	verifyComputedStats(t, "after GC", engine, ms, ts4.WallTime)
+
+	tombstones, err := MVCCRangeTombstones(engine, keyMin, keyMax, hlc.MaxTimestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expTombstones := []RangeTombstone{{StartKey: testKey1, EndKey: testKey2, Timestamp: ts4}}
+	if !reflect.DeepEqual(tombstones, expTombstones) {
+		t.Fatalf("expected range tombstones %+v; got %+v", expTombstones, tombstones)
+	}
+	// Reading below the GC threshold shows which versions were removed.
+	if keys, expKeys := scanKeys(t, engine, ts1), []roachpb.Key{testKey1, testKey4}; !reflect.DeepEqual(keys, expKeys) {
+		t.Fatalf("expected keys %s; got %s", expKeys, keys)
+	}
+	if keys, expKeys := scanKeys(t, engine, ts3), []roachpb.Key{testKey1, testKey3, testKey4}; !reflect.DeepEqual(keys, expKeys) {
+		t.Fatalf("expected keys %s; got %s", expKeys, keys)
+	}
+}
+
+func TestMVCCSplitRangeTombstones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	engine := createTestEngine()
+	defer engine.Close()
+
+	ctx := context.Background()
+	ts1 := hlc.Timestamp{WallTime: 1}
+	ts2 := hlc.Timestamp{WallTime: 2}
+
+	ms := &enginepb.MVCCStats{}
+	if _, err := MVCCDeleteRangeUsingTombstone(ctx, engine, ms, testKey1, testKey5, ts1, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MVCCDeleteRangeUsingTombstone(ctx, engine, ms, testKey2, testKey5, ts2, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := MVCCSplitRangeTombstones(ctx, engine, ms, testKey3, ts2.WallTime); err != nil {
+		t.Fatal(err)
+	}
+	verifyComputedStats(t, "after split", engine, ms, ts2.WallTime)
+
+	tombstones, err := MVCCRangeTombstones(engine, keyMin, keyMax, hlc.MaxTimestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expTombstones := []RangeTombstone{
+		{StartKey: testKey2, EndKey: testKey3, Timestamp: ts2},
+		{StartKey: testKey1, EndKey: testKey3, Timestamp: ts1},
+		{StartKey: testKey3, EndKey: testKey5, Timestamp: ts2},
+		{StartKey: testKey3, EndKey: testKey5, Timestamp: ts1},
+	}
+	if !reflect.DeepEqual(tombstones, expTombstones) {
+		t.Fatalf("expected range tombstones %+v; got %+v", expTombstones, tombstones)
+	}
+
+	// Splitting at a fragment boundary is a no-op.
+	if err := MVCCSplitRangeTombstones(ctx, engine, ms, testKey3, ts2.WallTime); err != nil {
+		t.Fatal(err)
+	}
+	if tombstones, err = MVCCRangeTombstones(engine, keyMin, keyMax, hlc.MaxTimestamp); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(tombstones, expTombstones) {
+		t.Fatalf("expected range tombstones %+v; got %+v", expTombstones, tombstones)
+	}
+}
+
+// TestMVCCRangeTombstoneStartKeys verifies that a range tombstone ending
+// within an existing fragment doesn't rewrite it, and that the start key of
+// the latter is enforced once the former is garbage collected.
+func TestMVCCRangeTombstoneStartKeys(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	engine := createTestEngine()
+	defer engine.Close()
+
+	ctx := context.Background()
+	ts1 := hlc.Timestamp{WallTime: 1}
+	ts2 := hlc.Timestamp{WallTime: 2}
+	ts3 := hlc.Timestamp{WallTime: 3}
+
+	ms := &enginepb.MVCCStats{}
+	if _, err := MVCCDeleteRangeUsingTombstone(ctx, engine, ms, testKey1, testKey5, ts1, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MVCCDeleteRangeUsingTombstone(ctx, engine, ms, testKey2, testKey3, ts2, false); err != nil {
+		t.Fatal(err)
+	}
+	verifyComputedStats(t, "after range tombstones", engine, ms, ts2.WallTime)
+
+	// The fragment ending at testKey5 is stored as it was written.
+	tombstoneKey := MVCCKey{Key: keys.RangeTombstoneKey(testKey5), Timestamp: ts1}
+	if startKey, err := engine.Get(tombstoneKey); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(startKey, testKey1) {
+		t.Fatalf("expected %s to start at %s; got %s", tombstoneKey, testKey1, startKey)
+	}
+	tombstones, err := MVCCRangeTombstones(engine, keyMin, keyMax, hlc.MaxTimestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expTombstones := []RangeTombstone{
+		{StartKey: testKey2, EndKey: testKey3, Timestamp: ts2},
+		{StartKey: testKey1, EndKey: testKey3, Timestamp: ts1},
+		{StartKey: testKey3, EndKey: testKey5, Timestamp: ts1},
+	}
+	if !reflect.DeepEqual(tombstones, expTombstones) {
+		t.Fatalf("expected range tombstones %+v; got %+v", expTombstones, tombstones)
+	}
+
+	if err := MVCCGarbageCollectRangeTombstones(
+		ctx, engine, ms, keyMin, testKey4, ts2, ts3, math.MaxInt64,
+	); err != nil {
+		t.Fatal(err)
+	}
+	verifyComputedStats(t, "after GC", engine, ms, ts3.WallTime)
+	if tombstones, err = MVCCRangeTombstones(engine, keyMin, keyMax, hlc.MaxTimestamp); err != nil {
+		t.Fatal(err)
+	}
+	expTombstones = []RangeTombstone{{StartKey: testKey3, EndKey: testKey5, Timestamp: ts1}}
+	if !reflect.DeepEqual(tombstones, expTombstones) {
+		t.Fatalf("expected range tombstones %+v; got %+v", expTombstones, tombstones)
+	}
+}
+
+func TestMVCCIterateVersions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	engine := createTestEngine()
+	defer engine.Close()
+
+	ctx := context.Background()
+	ts := func(wallTime int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wallTime} }
+	for _, put := range []struct {
+		key   roachpb.Key
+		ts    hlc.Timestamp
+		value roachpb.Value
+	}{
+		{testKey1, ts(1), value1},
+		{testKey1, ts(3), value2},
+		{testKey2, ts(2), value1},
+		{testKey3, ts(1), value3},
+	} {
+		if err := MVCCPut(ctx, engine, nil, put.key, put.ts, put.value, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := MVCCDelete(ctx, engine, nil, testKey2, ts(4), nil); err != nil {
+		t.Fatal(err)
+	}
+	// The range tombstone deletes testKey1 and testKey3, but testKey2 was
+	// already deleted.
+	if _, err := MVCCDeleteRangeUsingTombstone(ctx, engine, nil, testKey1, testKey4, ts(5), false); err != nil {
+		t.Fatal(err)
+	}
+	if err := MVCCPut(ctx, engine, nil, testKey1, ts(6), value3, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := MVCCPut(ctx, engine, nil, testKey4, ts(7), value4, makeTxn(*txn1, ts(7))); err != nil {
+		t.Fatal(err)
+	}
+
+	type version struct {
+		key   string
+		ts    hlc.Timestamp
+		value string
+	}
+	var versions []version
+	var intents []roachpb.Key
+	if err := MVCCIterateVersions(engine, keyMin, keyMax, ts(2), ts(7),
+		func(kv roachpb.KeyValue) error {
+			var value []byte
+			if len(kv.Value.RawBytes) > 0 {
+				var err error
+				if value, err = kv.Value.GetBytes(); err != nil {
+					return err
+				}
+			}
+			versions = append(versions, version{string(kv.Key), kv.Value.Timestamp, string(value)})
+			return nil
+		},
+		func(key roachpb.Key, meta enginepb.MVCCMetadata) error {
+			intents = append(intents, key)
+			return nil
+		},
+	); err != nil {
+		t.Fatal(err)
+	}
+	expVersions := []version{
+		{string(testKey1), ts(3), "testValue2"},
+		{string(testKey1), ts(5), ""},
+		{string(testKey1), ts(6), "testValue3"},
+		{string(testKey2), ts(4), ""},
+		{string(testKey3), ts(5), ""},
+	}
+	if !reflect.DeepEqual(versions, expVersions) {
+		t.Errorf("expected versions %+v; got %+v", expVersions, versions)
+	}
+	if expIntents := []roachpb.Key{testKey4}; !reflect.DeepEqual(intents, expIntents) {
+		t.Errorf("expected intents %s; got %s", expIntents, intents)
+	}
+}
+
 func TestMVCCConditionalPut(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	engine := createTestEngine()
@@ -298,6 +298,9 @@ type RocksDB struct {
 	maxOpenFiles int                // The maximum number of open files this instance will use.
 	deallocated  chan struct{}      // Closed when the underlying handle is deallocated.
 
+	encryption      EncryptionOptions
+	encryptionKeyID []byte // ID of the key encrypting new files, if any.
+
 	commit struct {
 		syncutil.Mutex
 		cond        *sync.Cond
@@ -319,6 +322,19 @@ var _ Engine = &RocksDB{}
 // needed.
 func NewRocksDB(
 	attrs roachpb.Attributes, dir string, cache RocksDBCache, maxSize int64, maxOpenFiles int,
+) (*RocksDB, error) {
+	return NewEncryptedRocksDB(attrs, dir, cache, maxSize, maxOpenFiles, EncryptionOptions{})
+}
+
+// NewEncryptedRocksDB is like NewRocksDB, but encrypts the files of the
+// database with the given keys. See EncryptionOptions.
+func NewEncryptedRocksDB(
+	attrs roachpb.Attributes,
+	dir string,
+	cache RocksDBCache,
+	maxSize int64,
+	maxOpenFiles int,
+	encryption EncryptionOptions,
 ) (*RocksDB, error) {
 	if dir == "" {
 		panic("dir must be non-empty")
@@ -331,6 +347,7 @@ func NewRocksDB(
 		maxSize:      maxSize,
 		maxOpenFiles: maxOpenFiles,
 		deallocated:  make(chan struct{}),
+		encryption:   encryption,
 	}
 
 	auxDir := filepath.Join(dir, "auxiliary")
@@ -394,6 +411,13 @@ func (r *RocksDB) open() error {
 		ver = versionCurrent
 	}
 
+	if r.encryption.Enabled() {
+		var err error
+		if r.encryptionKeyID, err = loadEncryptionKeys(r.encryption); err != nil {
+			return err
+		}
+	}
+
 	blockSize := envutil.EnvOrDefaultBytes("COCKROACH_ROCKSDB_BLOCK_SIZE", defaultBlockSize)
 	walTTL := envutil.EnvOrDefaultDuration("COCKROACH_ROCKSDB_WAL_TTL", 0).Seconds()
 
@@ -406,6 +430,8 @@ func (r *RocksDB) open() error {
 			logging_enabled:   C.bool(log.V(3)),
 			num_cpu:           C.int(runtime.NumCPU()),
 			max_open_files:    C.int(r.maxOpenFiles),
+			use_encryption:    C.bool(r.encryption.Enabled()),
+			encryption_key_id: goToCSlice(r.encryptionKeyID),
 		})
 	if err := statusToError(status); err != nil {
 		return errors.Errorf("could not open rocksdb instance: %s", err)
@@ -1585,6 +1611,7 @@ func cStatsToGoStats(stats C.MVCCStatsResult, nowNanos int64) (enginepb.MVCCStat
 	ms.GCBytesAge = int64(stats.gc_bytes_age)
 	ms.SysBytes = int64(stats.sys_bytes)
 	ms.SysCount = int64(stats.sys_count)
+	ms.RangeTombstoneCount = int64(stats.range_tombstone_count)
 	ms.LastUpdateNanos = nowNanos
 	return ms, nil
 }
@@ -17,6 +17,7 @@
 package storage
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 	"math/rand"
@@ -188,6 +189,18 @@ func makeGCQueueScore(
 	if (gcThreshold != hlc.Timestamp{}) {
 		r.LikelyLastGC = time.Duration(now.WallTime - gcThreshold.Add(r.TTL.Nanoseconds(), 0).WallTime)
 	}
+	// The data deleted by range tombstones is still accounted for as live in
+	// the stats, so the score doesn't reflect it. Queue the replica whenever
+	// one of its range tombstones has expired instead.
+	if !r.ShouldQueue {
+		tombstones, err := engine.MVCCRangeTombstones(repl.store.Engine(),
+			desc.StartKey.AsRawKey(), desc.EndKey.AsRawKey(), now.Add(-r.TTL.Nanoseconds(), 0))
+		if err != nil {
+			log.Errorf(ctx, "could not look up range tombstones for range %s: %s", repl, err)
+		} else if len(tombstones) > 0 {
+			r.ShouldQueue = true
+		}
+	}
 	return r
 }
 
@@ -554,6 +567,14 @@ func chunkGCRequest(
 
 	ret = append(ret, gc1)
 
+	// The range tombstones are collected by a separate request, which runs
+	// after gc1 has bumped the threshold they are collected below.
+	if info.RangeTombstonesConsidered > 0 {
+		gc2 := template
+		gc2.RangeTombstones = true
+		ret = append(ret, gc2)
+	}
+
 	size := 0
 	idx := 0
 	for i, key := range gcKeys {
@@ -666,6 +687,15 @@ type GCInfo struct {
 	ResolveSuccess int
 	// Threshold is the computed expiration timestamp. Equal to `Now - Policy`.
 	Threshold hlc.Timestamp
+	// RangeTombstonesConsidered is the number of range tombstone fragment
+	// versions old enough to be garbage collected.
+	RangeTombstonesConsidered int
+}
+
+// isRangeTombstoneKey returns whether the key is the key of a range tombstone
+// fragment.
+func isRangeTombstoneKey(key roachpb.Key) bool {
+	return bytes.HasPrefix(key, keys.LocalRangeTombstonePrefix)
 }
 
 func (info *GCInfo) updateMetrics(metrics *StoreMetrics) {
@@ -802,6 +832,14 @@ func RunGC(
 			break
 		}
 		iterKey := iter.Key()
+		if isRangeTombstoneKey(iterKey.Key) {
+			// Range tombstones are garbage collected along with the data they
+			// cover by a separate request.
+			if iterKey.IsValue() && !gc.Threshold.Less(iterKey.Timestamp) {
+				infoMu.RangeTombstonesConsidered++
+			}
+			continue
+		}
 		if !iterKey.IsValue() || !iterKey.Key.Equal(expBaseKey) {
 			// Moving to the next key (& values).
 			processKeysAndValues()
@@ -254,6 +254,16 @@ type Replica struct {
 	// Contains the lease history when enabled.
 	leaseHistory *leaseHistory
 
+	rangeFeedMu struct {
+		// Protects all fields in the rangeFeedMu struct.
+		//
+		// Locking notes: Replica.raftMu < Replica.rangeFeedMu
+		syncutil.Mutex
+		// The range feeds registered with the replica, which are sent the
+		// MVCC writes applied to their spans (see Replica.RangeFeed).
+		registrations []*rangeFeedRegistration
+	}
+
 	cmdQMu struct {
 		// Protects all fields in the cmdQMu struct.
 		//
@@ -4022,6 +4032,9 @@ func (r *Replica) processRaftCommand(
 		}
 		raftCmd.ReplicatedEvalResult.Delta, pErr = r.applyRaftCommand(
 			ctx, idKey, raftCmd.ReplicatedEvalResult, writeBatch)
+		if pErr == nil {
+			r.handleRangeFeedRaftMuLocked(ctx, raftCmd.ReplicatedEvalResult, writeBatch)
+		}
 
 		if filter := r.store.cfg.TestingKnobs.TestingPostApplyFilter; pErr == nil && filter != nil {
 			pErr = filter(storagebase.ApplyFilterArgs{
@@ -121,16 +121,35 @@ func DefaultDeclareKeys(
 		spans.Add(SpanReadOnly, roachpb.Span{Key: keys.RangeLeaseKey(header.RangeID)})
 		spans.Add(SpanReadOnly, roachpb.Span{Key: keys.RangeDescriptorKey(desc.StartKey)})
 	}
+	if !keys.IsLocal(req.Header().Key) {
+		// Reads and writes of non-local keys consult the range tombstones
+		// covering them.
+		declareRangeTombstoneKeys(SpanReadOnly, req.Header(), spans)
+	}
+}
+
+// declareRangeTombstoneKeys declares the keys of the range tombstone
+// fragments ending within the span. The fragment straddling the end of the
+// span is stored at some unknown key past it, which is read without being
+// declared (see engine.MVCCRangeTombstones): the range tombstone writes
+// conflicting with the request are serialized by the keys they declare
+// within its span.
+func declareRangeTombstoneKeys(access SpanAccess, span roachpb.Span, spans *SpanSet) {
+	endKey := span.EndKey
+	if len(endKey) == 0 {
+		endKey = span.Key.Next()
+	}
+	spans.Add(access, keys.MakeRangeTombstoneSpan(span.Key, endKey))
 }
 
 var commands = map[roachpb.Method]Command{
-	roachpb.Get:                {DeclareKeys: DefaultDeclareKeys, Eval: evalGet},
+	roachpb.Get:                {DeclareKeys: declareKeysGet, Eval: evalGet},
 	roachpb.Put:                {DeclareKeys: DefaultDeclareKeys, Eval: evalPut},
 	roachpb.ConditionalPut:     {DeclareKeys: DefaultDeclareKeys, Eval: evalConditionalPut},
 	roachpb.InitPut:            {DeclareKeys: DefaultDeclareKeys, Eval: evalInitPut},
 	roachpb.Increment:          {DeclareKeys: DefaultDeclareKeys, Eval: evalIncrement},
 	roachpb.Delete:             {DeclareKeys: DefaultDeclareKeys, Eval: evalDelete},
-	roachpb.DeleteRange:        {DeclareKeys: DefaultDeclareKeys, Eval: evalDeleteRange},
+	roachpb.DeleteRange:        {DeclareKeys: declareKeysDeleteRange, Eval: evalDeleteRange},
 	roachpb.Scan:               {DeclareKeys: DefaultDeclareKeys, Eval: evalScan},
 	roachpb.ReverseScan:        {DeclareKeys: DefaultDeclareKeys, Eval: evalReverseScan},
 	roachpb.BeginTransaction:   {DeclareKeys: declareKeysBeginTransaction, Eval: evalBeginTransaction},
@@ -263,6 +282,13 @@ func intentsToEvalResult(intents []roachpb.Intent, args roachpb.Request) EvalRes
 	return pd
 }
 
+func declareKeysGet(
+	desc roachpb.RangeDescriptor, header roachpb.Header, req roachpb.Request, spans *SpanSet,
+) {
+	DefaultDeclareKeys(desc, header, req, spans)
+	spans.Add(SpanReadOnly, roachpb.Span{Key: keys.RangeStatsKey(header.RangeID)})
+}
+
 // evalGet returns the value for a specified key.
 func evalGet(
 	ctx context.Context, batch engine.ReadWriter, cArgs CommandArgs, resp roachpb.Response,
@@ -271,7 +297,16 @@ func evalGet(
 	h := cArgs.Header
 	reply := resp.(*roachpb.GetResponse)
 
-	val, intents, err := engine.MVCCGet(ctx, batch, args.Key, h.Timestamp, h.ReadConsistency == roachpb.CONSISTENT, h.Txn)
+	// Skip looking for range tombstones in ranges which don't have any. A
+	// range tombstone covering the key is declared to conflict with this
+	// request, so it has been applied to the stats by the time we get here.
+	get := engine.MVCCGet
+	if ms, err := cArgs.EvalCtx.GetMVCCStats(); err != nil {
+		return EvalResult{}, err
+	} else if ms.RangeTombstoneCount == 0 {
+		get = engine.MVCCGetIgnoringRangeTombstones
+	}
+	val, intents, err := get(ctx, batch, args.Key, h.Timestamp, h.ReadConsistency == roachpb.CONSISTENT, h.Txn)
 	reply.Value = val
 	return intentsToEvalResult(intents, args), err
 }
@@ -376,6 +411,17 @@ func evalDelete(
 	return EvalResult{}, engine.MVCCDelete(ctx, batch, cArgs.Stats, args.Key, h.Timestamp, h.Txn)
 }
 
+func declareKeysDeleteRange(
+	desc roachpb.RangeDescriptor, header roachpb.Header, req roachpb.Request, spans *SpanSet,
+) {
+	DefaultDeclareKeys(desc, header, req, spans)
+	if req.(*roachpb.DeleteRangeRequest).UseRangeTombstone {
+		// Writing a range tombstone only writes the fragments ending within its
+		// span.
+		declareRangeTombstoneKeys(SpanReadWrite, req.Header(), spans)
+	}
+}
+
 // evalDeleteRange deletes the range of key/value pairs specified by
 // start and end keys.
 func evalDeleteRange(
@@ -385,6 +431,21 @@ func evalDeleteRange(
 	h := cArgs.Header
 	reply := resp.(*roachpb.DeleteRangeResponse)
 
+	if args.UseRangeTombstone {
+		if h.Txn != nil {
+			return EvalResult{}, errors.Errorf("cannot write range tombstone within a transaction")
+		}
+		if args.Inline {
+			return EvalResult{}, errors.Errorf("cannot write range tombstone deleting inline values")
+		}
+		deleted, err := engine.MVCCDeleteRangeUsingTombstone(
+			ctx, batch, cArgs.Stats, args.Key, args.EndKey, h.Timestamp, args.ReturnKeys)
+		if err == nil {
+			reply.Keys = deleted
+		}
+		return EvalResult{}, err
+	}
+
 	var timestamp hlc.Timestamp
 	if !args.Inline {
 		timestamp = h.Timestamp
@@ -592,6 +653,9 @@ func declareKeysEndTransaction(
 				Key:    keys.MakeRangeKeyPrefix(st.LeftDesc.StartKey),
 				EndKey: keys.MakeRangeKeyPrefix(st.RightDesc.EndKey).PrefixEnd(),
 			})
+			// The range tombstones straddling the split key are split.
+			spans.Add(SpanReadWrite, keys.MakeRangeTombstoneSpan(
+				st.LeftDesc.StartKey.AsRawKey(), st.RightDesc.EndKey.AsRawKey()))
 			leftRangeIDPrefix := keys.MakeRangeIDReplicatedPrefix(header.RangeID)
 			spans.Add(SpanReadOnly, roachpb.Span{
 				Key:    leftRangeIDPrefix,
@@ -1394,6 +1458,14 @@ func declareKeysGC(
 	if gcr.Threshold != (hlc.Timestamp{}) {
 		spans.Add(SpanReadWrite, roachpb.Span{Key: keys.RangeLastGCKey(header.RangeID)})
 	}
+	if gcr.RangeTombstones {
+		// Garbage collecting range tombstones clears the keys they cover and
+		// reads the replica's GC threshold.
+		spans.Add(SpanReadWrite, gcr.Span)
+		spans.Add(SpanReadWrite, keys.MakeRangeTombstoneSpan(
+			desc.StartKey.AsRawKey(), desc.EndKey.AsRawKey()))
+		spans.Add(SpanReadOnly, roachpb.Span{Key: keys.RangeLastGCKey(header.RangeID)})
+	}
 	if gcr.TxnSpanGCThreshold != (hlc.Timestamp{}) {
 		spans.Add(SpanReadWrite, roachpb.Span{
 			// TODO(bdarnell): since this must be checked by all
@@ -1438,6 +1510,20 @@ func evalGC(
 		return EvalResult{}, err
 	}
 
+	// Garbage collect the range tombstones below the GC threshold, along
+	// with the data they delete.
+	if args.RangeTombstones {
+		threshold, err := cArgs.EvalCtx.GCThreshold()
+		if err != nil {
+			return EvalResult{}, err
+		}
+		if err := engine.MVCCGarbageCollectRangeTombstones(
+			ctx, batch, cArgs.Stats, args.Key, args.EndKey, threshold, h.Timestamp, gcBatchSize.Get(),
+		); err != nil {
+			return EvalResult{}, err
+		}
+	}
+
 	// Protect against multiple GC requests arriving out of order; we track
 	// the maximum timestamps.
 
@@ -2968,6 +3054,14 @@ func splitTrigger(
 		return enginepb.MVCCStats{}, EvalResult{}, err
 	}
 
+	// Split the range tombstones straddling the split key so that each side
+	// holds the fragments covering its own keys.
+	if err := engine.MVCCSplitRangeTombstones(
+		ctx, batch, &bothDeltaMS, split.RightDesc.StartKey.AsRawKey(), ts.WallTime,
+	); err != nil {
+		return enginepb.MVCCStats{}, EvalResult{}, errors.Wrap(err, "unable to split range tombstones")
+	}
+
 	// TODO(d4l3k): we should check which side of the split is smaller
 	// and compute stats for it instead of having a constraint that the
 	// left hand side is smaller.
@@ -3326,7 +3420,7 @@ func mergeTrigger(
 	if err != nil {
 		return EvalResult{}, err
 	}
-	rightMS.SysBytes, rightMS.SysCount = 0, 0
+	rightMS.SysBytes, rightMS.SysCount, rightMS.RangeTombstoneCount = 0, 0, 0
 	mergedMS.Add(rightMS)
 
 	// Copy the RHS range's abort cache to the new LHS one.
@@ -51,7 +51,7 @@ func makeReplicatedKeyRanges(d *roachpb.RangeDescriptor) []keyRange {
 	return makeReplicaKeyRanges(d, keys.MakeRangeIDReplicatedPrefix)
 }
 
-// makeReplicaKeyRanges returns a slice of 3 key ranges. The last key range in
+// makeReplicaKeyRanges returns a slice of 4 key ranges. The last key range in
 // the returned slice corresponds to the actual range data (i.e. not the range
 // metadata).
 func makeReplicaKeyRanges(
@@ -65,6 +65,7 @@ func makeReplicaKeyRanges(
 		dataStartKey = keys.LocalMax
 	}
 	sysRangeIDKey := metaFunc(d.RangeID)
+	rangeTombstoneSpan := keys.MakeRangeTombstoneSpan(d.StartKey.AsRawKey(), d.EndKey.AsRawKey())
 	return []keyRange{
 		{
 			start: engine.MakeMVCCMetadataKey(sysRangeIDKey),
@@ -74,6 +75,10 @@ func makeReplicaKeyRanges(
 			start: engine.MakeMVCCMetadataKey(keys.MakeRangeKeyPrefix(d.StartKey)),
 			end:   engine.MakeMVCCMetadataKey(keys.MakeRangeKeyPrefix(d.EndKey)),
 		},
+		{
+			start: engine.MakeMVCCMetadataKey(rangeTombstoneSpan.Key),
+			end:   engine.MakeMVCCMetadataKey(rangeTombstoneSpan.EndKey),
+		},
 		{
 			start: engine.MakeMVCCMetadataKey(dataStartKey),
 			end:   engine.MakeMVCCMetadataKey(d.EndKey.AsRawKey()),
@@ -89,6 +89,7 @@ func createRangeData(t *testing.T, r *Replica) []engine.MVCCKey {
 		{keys.TransactionKey(roachpb.Key(desc.StartKey), uuid.MakeV4()), ts0},
 		{keys.TransactionKey(roachpb.Key(desc.StartKey.Next()), uuid.MakeV4()), ts0},
 		{keys.TransactionKey(fakePrevKey(desc.EndKey), uuid.MakeV4()), ts0},
+		{keys.RangeTombstoneKey(desc.EndKey.AsRawKey()), ts},
 		// TODO(bdarnell): KeyMin.Next() results in a key in the reserved system-local space.
 		// Once we have resolved https://github.com/cockroachdb/cockroach/issues/437,
 		// replace this with something that reliably generates the first valid key in the range.
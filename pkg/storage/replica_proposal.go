@@ -505,7 +505,13 @@ func (r *Replica) addSSTablePostApply(ctx context.Context, data []byte) {
 	} else {
 		path = filepath.Join(r.store.engine.GetAuxiliaryDir(), fmt.Sprintf("addsstable-%x", checksum))
 		move = true
-		if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		writeFile := func() error { return ioutil.WriteFile(path, data, 0600) }
+		if rocksdb, ok := r.store.engine.(*engine.RocksDB); ok {
+			// Write the file through the RocksDB env so that it is encrypted
+			// like the rest of the store's files.
+			writeFile = func() error { return rocksdb.WriteFile(path, data) }
+		}
+		if err := writeFile(); err != nil {
 			panic(err)
 		}
 	}
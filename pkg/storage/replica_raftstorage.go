@@ -564,7 +564,7 @@ func clearRangeData(desc *roachpb.RangeDescriptor, eng engine.Engine, batch engi
 	iter := eng.NewIterator(false)
 	defer iter.Close()
 
-	const metadataRanges = 2
+	const metadataRanges = 3
 	for i, keyRange := range makeAllKeyRanges(desc) {
 		// The metadata ranges have a relatively small number of keys making usage
 		// of range tombstones (as created by ClearRange) a pessimization.
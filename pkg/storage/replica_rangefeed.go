@@ -0,0 +1,538 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/storage/storagebase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// rangeFeedCheckpointInterval is the interval at which range feeds try to
+// advance their resolved timestamp.
+var rangeFeedCheckpointInterval = settings.RegisterNonNegativeDurationSetting(
+	"kv.rangefeed.checkpoint_interval",
+	"the interval at which range feeds checkpoint the resolved timestamp of their span",
+	200*time.Millisecond,
+)
+
+const (
+	// rangeFeedBufferSize is the number of operations which can be queued for
+	// a range feed before it is disconnected.
+	rangeFeedBufferSize = 4096
+
+	// rangeFeedPushIntentsAge is the age above which the transactions of the
+	// intents holding back the resolved timestamp of a range feed are pushed,
+	// so that abandoned ones are cleaned up.
+	rangeFeedPushIntentsAge = 10 * time.Second
+)
+
+// RangeFeedEventSink is the stream the events of a range feed are sent to.
+type RangeFeedEventSink interface {
+	Context() context.Context
+	Send(*roachpb.RangeFeedEvent) error
+}
+
+type rangeFeedOpType int
+
+const (
+	// rangeFeedValueOp is the write of a committed value, or of a deletion.
+	rangeFeedValueOp rangeFeedOpType = iota
+	// rangeFeedWriteIntentOp is the write of an intent, or the update of its
+	// timestamp.
+	rangeFeedWriteIntentOp
+	// rangeFeedRemoveIntentOp is the resolution of an intent, which comes
+	// along with a rangeFeedValueOp if the intent was committed.
+	rangeFeedRemoveIntentOp
+)
+
+// rangeFeedOp is an MVCC write applied to a replica, as seen by its range
+// feeds.
+type rangeFeedOp struct {
+	typ rangeFeedOpType
+	key roachpb.Key
+	// value is set for a rangeFeedValueOp. Deletions have no data.
+	value roachpb.Value
+	// txn is set for a rangeFeedWriteIntentOp.
+	txn *enginepb.TxnMeta
+	// intentTS is the timestamp of the intent of a rangeFeedWriteIntentOp.
+	intentTS hlc.Timestamp
+}
+
+// rangeFeedRegistration is a range feed registered with a replica, which
+// receives the operations applied to its span.
+type rangeFeedRegistration struct {
+	span roachpb.Span
+	ops  chan rangeFeedOp
+	errC chan *roachpb.Error
+}
+
+// disconnect makes the range feed return the error, unless it was already
+// disconnected.
+func (reg *rangeFeedRegistration) disconnect(pErr *roachpb.Error) {
+	select {
+	case reg.errC <- pErr:
+	default:
+	}
+}
+
+// RangeFeed registers a range feed for the span of the request with the
+// replica and sends the MVCC writes applied to the span at timestamps above
+// the one of the request to the stream, as RangeFeedValue events, until the
+// context is canceled or an error occurs. The writes which were applied
+// before the registration are read from the engine first. Values may be sent
+// more than once, and the values of different keys aren't ordered by
+// timestamp. RangeFeedCheckpoint events are sent periodically to promise
+// that no more values will be sent for the span at or below their resolved
+// timestamp, which requires the replica to hold the range lease.
+//
+// The returned error is retryable if it's a NotLeaseHolderError,
+// RangeKeyMismatchError, RangeNotFoundError or NodeUnavailableError, in
+// which case the range feed can be restarted from the last resolved
+// timestamp on the right replica.
+func (r *Replica) RangeFeed(
+	args *roachpb.RangeFeedRequest, stream RangeFeedEventSink,
+) *roachpb.Error {
+	ctx := r.AnnotateCtx(stream.Context())
+
+	if bytes.Compare(args.Span.Key, keys.LocalMax) < 0 {
+		return roachpb.NewErrorf("range feeds are not supported over local keys: %s", args.Span)
+	}
+	rSpan := roachpb.RSpan{Key: roachpb.RKey(args.Span.Key), EndKey: roachpb.RKey(args.Span.EndKey)}
+	if _, pErr := r.redirectOnOrAcquireLease(ctx); pErr != nil {
+		return pErr
+	}
+	if err := r.requestCanProceed(rSpan, args.Timestamp); err != nil {
+		return roachpb.NewError(err)
+	}
+
+	// Register the range feed along with a snapshot of the engine, so that
+	// the writes are either in the snapshot or sent to the registration.
+	reg := &rangeFeedRegistration{
+		span: args.Span,
+		ops:  make(chan rangeFeedOp, rangeFeedBufferSize),
+		errC: make(chan *roachpb.Error, 1),
+	}
+	r.raftMu.Lock()
+	snap := r.store.Engine().NewSnapshot()
+	r.rangeFeedMu.Lock()
+	r.rangeFeedMu.registrations = append(r.rangeFeedMu.registrations, reg)
+	r.rangeFeedMu.Unlock()
+	r.raftMu.Unlock()
+	defer r.unregisterRangeFeed(reg)
+
+	f := rangeFeed{
+		repl:     r,
+		reg:      reg,
+		rSpan:    rSpan,
+		stream:   stream,
+		intents:  make(map[string]rangeFeedIntent),
+		resolved: args.Timestamp,
+	}
+	err := f.catchUp(snap, args.Timestamp)
+	snap.Close()
+	if err != nil {
+		return roachpb.NewError(err)
+	}
+	return f.run(ctx)
+}
+
+// unregisterRangeFeed removes the registration from the replica.
+func (r *Replica) unregisterRangeFeed(reg *rangeFeedRegistration) {
+	r.rangeFeedMu.Lock()
+	defer r.rangeFeedMu.Unlock()
+	for i, existing := range r.rangeFeedMu.registrations {
+		if existing == reg {
+			regs := r.rangeFeedMu.registrations
+			r.rangeFeedMu.registrations = append(regs[:i:i], regs[i+1:]...)
+			return
+		}
+	}
+}
+
+// rangeFeedIntent is an intent which holds back the resolved timestamp of a
+// range feed.
+type rangeFeedIntent struct {
+	txn enginepb.TxnMeta
+	ts  hlc.Timestamp
+}
+
+// rangeFeed is the state of a range feed registered with a replica.
+type rangeFeed struct {
+	repl   *Replica
+	reg    *rangeFeedRegistration
+	rSpan  roachpb.RSpan
+	stream RangeFeedEventSink
+	// intents are the intents in the span, by key.
+	intents map[string]rangeFeedIntent
+	// resolved is the last resolved timestamp sent.
+	resolved hlc.Timestamp
+	lastPush time.Time
+}
+
+// catchUp sends the values written to the span above the start timestamp
+// which are in the snapshot, and records its intents.
+func (f *rangeFeed) catchUp(snap engine.Reader, startTS hlc.Timestamp) error {
+	span := f.reg.span
+	return engine.MVCCIterateVersions(snap, span.Key, span.EndKey, startTS, hlc.MaxTimestamp,
+		func(kv roachpb.KeyValue) error {
+			return f.sendValue(kv.Key, kv.Value)
+		},
+		func(key roachpb.Key, meta enginepb.MVCCMetadata) error {
+			f.intents[string(key)] = rangeFeedIntent{txn: *meta.Txn, ts: meta.Timestamp}
+			return nil
+		},
+	)
+}
+
+// run handles the operations sent to the registration and periodically
+// checkpoints the resolved timestamp, until the range feed is disconnected.
+func (f *rangeFeed) run(ctx context.Context) *roachpb.Error {
+	ticker := time.NewTicker(rangeFeedCheckpointInterval.Get())
+	defer ticker.Stop()
+	for {
+		select {
+		case op := <-f.reg.ops:
+			if err := f.handleOp(op); err != nil {
+				return roachpb.NewError(err)
+			}
+		case <-ticker.C:
+			if pErr := f.checkpoint(ctx); pErr != nil {
+				return pErr
+			}
+		case pErr := <-f.reg.errC:
+			return pErr
+		case <-ctx.Done():
+			return roachpb.NewError(ctx.Err())
+		case <-f.repl.store.Stopper().ShouldQuiesce():
+			return roachpb.NewError(&roachpb.NodeUnavailableError{})
+		}
+	}
+}
+
+func (f *rangeFeed) handleOp(op rangeFeedOp) error {
+	switch op.typ {
+	case rangeFeedValueOp:
+		return f.sendValue(op.key, op.value)
+	case rangeFeedWriteIntentOp:
+		f.intents[string(op.key)] = rangeFeedIntent{txn: *op.txn, ts: op.intentTS}
+	case rangeFeedRemoveIntentOp:
+		delete(f.intents, string(op.key))
+	}
+	return nil
+}
+
+func (f *rangeFeed) sendValue(key roachpb.Key, value roachpb.Value) error {
+	return f.stream.Send(&roachpb.RangeFeedEvent{
+		Val: &roachpb.RangeFeedValue{Key: key, Value: value},
+	})
+}
+
+// checkpoint advances the resolved timestamp of the range feed, if possible.
+// The span is read at the current time through the command queue and the
+// timestamp cache, so that the writes at or below that time have all been
+// applied, and that later ones are pushed above it. The resolved timestamp is
+// the time of the read, or the timestamp preceding the oldest intent in the
+// span, which can still be committed at its timestamp.
+func (f *rangeFeed) checkpoint(ctx context.Context) *roachpb.Error {
+	r := f.repl
+	status, pErr := r.redirectOnOrAcquireLease(ctx)
+	if pErr != nil {
+		return pErr
+	}
+
+	var ba roachpb.BatchRequest
+	ba.Timestamp = status.timestamp
+	ba.Add(&roachpb.ScanRequest{Span: f.reg.span})
+	var spans SpanSet
+	spans.Add(SpanReadOnly, f.reg.span)
+	ec, err := r.beginCmds(ctx, &ba, &spans)
+	if err != nil {
+		return roachpb.NewError(err)
+	}
+	pErr = func() *roachpb.Error {
+		if err := r.IsDestroyed(); err != nil {
+			return roachpb.NewError(err)
+		}
+		if err := r.requestCanProceed(f.rSpan, ba.Timestamp); err != nil {
+			return roachpb.NewError(err)
+		}
+		return nil
+	}()
+	var br roachpb.BatchResponse
+	br.Add(&roachpb.ScanResponse{})
+	ec.done(&br, pErr, proposalNoRetry)
+	if pErr != nil {
+		return pErr
+	}
+
+	// The operations of the writes which preceded the read are all queued.
+	for n := len(f.reg.ops); n > 0; n-- {
+		if err := f.handleOp(<-f.reg.ops); err != nil {
+			return roachpb.NewError(err)
+		}
+	}
+
+	resolved := ba.Timestamp
+	var oldIntents []roachpb.Intent
+	pushBelow := resolved.Add(-rangeFeedPushIntentsAge.Nanoseconds(), 0)
+	for key, intent := range f.intents {
+		if ts := intent.ts.Prev(); ts.Less(resolved) {
+			resolved = ts
+		}
+		if intent.ts.Less(pushBelow) {
+			oldIntents = append(oldIntents, roachpb.Intent{
+				Span: roachpb.Span{Key: roachpb.Key(key)}, Txn: intent.txn, Status: roachpb.PENDING,
+			})
+		}
+	}
+	if len(oldIntents) > 0 && timeutil.Since(f.lastPush) > rangeFeedPushIntentsAge {
+		f.lastPush = timeutil.Now()
+		log.VEventf(ctx, 2, "pushing %d intents holding back range feed", len(oldIntents))
+		r.store.intentResolver.processIntentsAsync(r, []intentsWithArg{{
+			args: &roachpb.ScanRequest{Span: f.reg.span}, intents: oldIntents,
+		}})
+	}
+
+	if !f.resolved.Less(resolved) {
+		return nil
+	}
+	f.resolved = resolved
+	if err := f.stream.Send(&roachpb.RangeFeedEvent{
+		Checkpoint: &roachpb.RangeFeedCheckpoint{Span: f.reg.span, ResolvedTS: resolved},
+	}); err != nil {
+		return roachpb.NewError(err)
+	}
+	return nil
+}
+
+// rangeFeedKeyWrites are the writes to a key in a WriteBatch.
+type rangeFeedKeyWrites struct {
+	key            roachpb.Key
+	meta           *enginepb.MVCCMetadata
+	metaClear      bool
+	versions       []roachpb.Value
+	versionCleared bool
+}
+
+// handleRangeFeedRaftMuLocked sends the operations of the MVCC writes in a
+// WriteBatch which was just applied to the range feeds registered with the
+// replica. Requires raftMu to be locked.
+func (r *Replica) handleRangeFeedRaftMuLocked(
+	ctx context.Context, rResult storagebase.ReplicatedEvalResult, writeBatch *storagebase.WriteBatch,
+) {
+	r.rangeFeedMu.Lock()
+	defer r.rangeFeedMu.Unlock()
+	if len(r.rangeFeedMu.registrations) == 0 {
+		return
+	}
+
+	if rResult.AddSSTable.Data != nil {
+		// The writes of an ingested SSTable aren't in the WriteBatch, and their
+		// span isn't known here.
+		for _, reg := range r.rangeFeedMu.registrations {
+			reg.disconnect(roachpb.NewErrorf("range feeds are not supported over ingested SSTables"))
+		}
+		return
+	}
+	if writeBatch == nil {
+		return
+	}
+
+	ops, err := r.rangeFeedOps(rResult, writeBatch)
+	if err != nil {
+		log.Errorf(ctx, "unable to decode the range feed operations of the WriteBatch: %s", err)
+		for _, reg := range r.rangeFeedMu.registrations {
+			reg.disconnect(roachpb.NewError(err))
+		}
+		return
+	}
+	for _, op := range ops {
+		for _, reg := range r.rangeFeedMu.registrations {
+			if !reg.span.Contains(roachpb.Span{Key: op.key}) {
+				continue
+			}
+			select {
+			case reg.ops <- op:
+			default:
+				// The range feed can't keep up, and holding on to more operations
+				// could use an unbounded amount of memory. It can be restarted
+				// from its last resolved timestamp, which is why the error is a
+				// retryable one.
+				reg.disconnect(roachpb.NewError(&roachpb.NodeUnavailableError{}))
+			}
+		}
+	}
+}
+
+// rangeFeedOps returns the range feed operations of the MVCC writes to
+// global keys in the WriteBatch, which has just been applied.
+func (r *Replica) rangeFeedOps(
+	rResult storagebase.ReplicatedEvalResult, writeBatch *storagebase.WriteBatch,
+) ([]rangeFeedOp, error) {
+	reader, err := engine.NewRocksDBBatchReader(writeBatch.Data)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]*rangeFeedKeyWrites)
+	var writes []*rangeFeedKeyWrites
+	var tombstoneSpan roachpb.Span
+	for reader.Next() {
+		if reader.BatchType() == engine.BatchTypeMerge {
+			continue
+		}
+		mvccKey, err := engine.DecodeKey(reader.UnsafeKey())
+		if err != nil {
+			return nil, err
+		}
+
+		if bytes.HasPrefix(mvccKey.Key, keys.LocalRangeTombstonePrefix) {
+			// Only the new range tombstones are written at the timestamp of the
+			// command: the existing fragments are rewritten at their original
+			// timestamps by splits and range tombstones, but keep deleting the
+			// same versions.
+			if reader.BatchType() != engine.BatchTypeValue || mvccKey.Timestamp != rResult.Timestamp ||
+				rResult.Split != nil || rResult.Merge != nil {
+				continue
+			}
+			endKey, err := keys.DecodeRangeTombstoneKey(mvccKey.Key)
+			if err != nil {
+				return nil, err
+			}
+			startKey := roachpb.Key(reader.UnsafeValue())
+			if tombstoneSpan.Key == nil || bytes.Compare(startKey, tombstoneSpan.Key) < 0 {
+				tombstoneSpan.Key = append(roachpb.Key(nil), startKey...)
+			}
+			if bytes.Compare(endKey, tombstoneSpan.EndKey) > 0 {
+				tombstoneSpan.EndKey = endKey
+			}
+			continue
+		}
+		if bytes.Compare(mvccKey.Key, keys.LocalMax) < 0 {
+			continue
+		}
+
+		w, ok := byKey[string(mvccKey.Key)]
+		if !ok {
+			w = &rangeFeedKeyWrites{key: mvccKey.Key}
+			byKey[string(mvccKey.Key)] = w
+			writes = append(writes, w)
+		}
+		switch {
+		case !mvccKey.IsValue() && reader.BatchType() == engine.BatchTypeValue:
+			w.meta = &enginepb.MVCCMetadata{}
+			if err := w.meta.Unmarshal(reader.UnsafeValue()); err != nil {
+				return nil, err
+			}
+		case !mvccKey.IsValue():
+			w.metaClear = true
+		case reader.BatchType() == engine.BatchTypeValue:
+			w.versions = append(w.versions, roachpb.Value{
+				RawBytes:  append([]byte(nil), reader.UnsafeValue()...),
+				Timestamp: mvccKey.Timestamp,
+			})
+		default:
+			w.versionCleared = true
+		}
+	}
+	if err := reader.Error(); err != nil {
+		return nil, err
+	}
+
+	var ops []rangeFeedOp
+	for _, w := range writes {
+		if w.meta != nil {
+			if w.meta.Txn != nil {
+				// The version written along with the intent is provisional.
+				ops = append(ops, rangeFeedOp{
+					typ: rangeFeedWriteIntentOp, key: w.key, txn: w.meta.Txn, intentTS: w.meta.Timestamp,
+				})
+			}
+			// Otherwise, the value is inline and not versioned.
+			continue
+		}
+		if w.metaClear {
+			ops = append(ops, rangeFeedOp{typ: rangeFeedRemoveIntentOp, key: w.key})
+			if len(w.versions) == 0 && !w.versionCleared {
+				// The intent was committed at its timestamp, leaving its version
+				// in place.
+				value, err := r.rangeFeedLatestVersion(w.key)
+				if err != nil {
+					return nil, err
+				}
+				if value != nil {
+					ops = append(ops, rangeFeedOp{typ: rangeFeedValueOp, key: w.key, value: *value})
+				}
+				continue
+			}
+		}
+		// Versions cleared without an intent being resolved are garbage
+		// collected and don't change the value of the key.
+		sort.Slice(w.versions, func(i, j int) bool {
+			return w.versions[i].Timestamp.Less(w.versions[j].Timestamp)
+		})
+		for _, value := range w.versions {
+			ops = append(ops, rangeFeedOp{typ: rangeFeedValueOp, key: w.key, value: value})
+		}
+	}
+
+	if tombstoneSpan.Key != nil {
+		// Send the deletions of the keys which the new range tombstones delete.
+		if err := engine.MVCCIterateVersions(
+			r.store.Engine(), tombstoneSpan.Key, tombstoneSpan.EndKey,
+			rResult.Timestamp.Prev(), rResult.Timestamp,
+			func(kv roachpb.KeyValue) error {
+				ops = append(ops, rangeFeedOp{typ: rangeFeedValueOp, key: kv.Key, value: kv.Value})
+				return nil
+			},
+			nil, /* intentFn */
+		); err != nil {
+			return nil, err
+		}
+	}
+	return ops, nil
+}
+
+// rangeFeedLatestVersion returns the most recent version of the key in the
+// engine, if any.
+func (r *Replica) rangeFeedLatestVersion(key roachpb.Key) (*roachpb.Value, error) {
+	iter := r.store.Engine().NewIterator(true /* prefix */)
+	defer iter.Close()
+	iter.Seek(engine.MakeMVCCMetadataKey(key))
+	if ok, err := iter.Valid(); err != nil || !ok {
+		return nil, err
+	}
+	unsafeKey := iter.UnsafeKey()
+	if !unsafeKey.Key.Equal(key) {
+		return nil, nil
+	}
+	if !unsafeKey.IsValue() {
+		return nil, errors.Errorf("unexpected metadata for %s after the resolution of its intent", key)
+	}
+	return &roachpb.Value{RawBytes: iter.Value(), Timestamp: unsafeKey.Timestamp}, nil
+}
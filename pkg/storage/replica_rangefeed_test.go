@@ -0,0 +1,135 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+)
+
+// testRangeFeedStream is a RangeFeedEventSink which sends the events on a
+// channel.
+type testRangeFeedStream struct {
+	ctx    context.Context
+	events chan *roachpb.RangeFeedEvent
+}
+
+func (s *testRangeFeedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testRangeFeedStream) Send(event *roachpb.RangeFeedEvent) error {
+	select {
+	case s.events <- event:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+// TestReplicaRangeFeed verifies that a range feed sends the writes preceding
+// its registration above its start timestamp, followed by the writes applied
+// after it, and checkpoints the resolved timestamp.
+func TestReplicaRangeFeed(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := testContext{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	tc.Start(t, stopper)
+
+	send := func(args roachpb.Request) hlc.Timestamp {
+		_, respH, pErr := SendWrapped(context.Background(), tc.Sender(), roachpb.Header{}, args)
+		if pErr != nil {
+			t.Fatal(pErr)
+		}
+		return respH.Timestamp
+	}
+	put := func(key, value string) hlc.Timestamp {
+		args := putArgs(roachpb.Key(key), []byte(value))
+		return send(&args)
+	}
+
+	start := put("a", "1")
+	put("b", "2")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &testRangeFeedStream{ctx: ctx, events: make(chan *roachpb.RangeFeedEvent)}
+	errC := make(chan *roachpb.Error, 1)
+	go func() {
+		errC <- tc.repl.RangeFeed(&roachpb.RangeFeedRequest{
+			Header: roachpb.Header{Timestamp: start},
+			Span:   roachpb.Span{Key: roachpb.Key("a"), EndKey: roachpb.Key("z")},
+		}, stream)
+	}()
+
+	next := func() *roachpb.RangeFeedEvent {
+		select {
+		case event := <-stream.events:
+			return event
+		case pErr := <-errC:
+			t.Fatalf("range feed failed: %s", pErr)
+		case <-time.After(testutils.DefaultSucceedsSoonDuration):
+			t.Fatal("no event sent by the range feed")
+		}
+		return nil
+	}
+	// nextValue returns the next value sent, formatted as key=value, where the
+	// value is empty for deletions.
+	nextValue := func() string {
+		for {
+			if val := next().Val; val != nil {
+				if len(val.Value.RawBytes) == 0 {
+					return fmt.Sprintf("%s=", val.Key)
+				}
+				bytes, err := val.Value.GetBytes()
+				if err != nil {
+					t.Fatal(err)
+				}
+				return fmt.Sprintf("%s=%s", val.Key, bytes)
+			}
+		}
+	}
+
+	if v := nextValue(); v != `"b"=2` {
+		t.Fatalf("expected the write preceding the range feed, got %s", v)
+	}
+	put("c", "3")
+	del := deleteArgs(roachpb.Key("a"))
+	deleted := send(&del)
+	for _, expected := range []string{`"c"=3`, `"a"=`} {
+		if v := nextValue(); v != expected {
+			t.Fatalf("expected %s, got %s", expected, v)
+		}
+	}
+	for {
+		if checkpoint := next().Checkpoint; checkpoint != nil && !checkpoint.ResolvedTS.Less(deleted) {
+			break
+		}
+	}
+
+	cancel()
+	if pErr := <-errC; !testutils.IsPError(pErr, "context canceled") {
+		t.Fatalf("expected the range feed to stop when canceled, got %v", pErr)
+	}
+}
@@ -2535,6 +2535,73 @@ func TestReplicaCommandQueueSplitDeclaresWrites(t *testing.T) {
 	}
 }
 
+// TestReplicaCommandQueueRangeTombstoneDeclaresOverlap verifies that
+// writing a range tombstone only conflicts, through the keys of the range
+// tombstone fragments, with the requests overlapping its span.
+func TestReplicaCommandQueueRangeTombstoneDeclaresOverlap(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	desc := roachpb.RangeDescriptor{StartKey: roachpb.RKey("a"), EndKey: roachpb.RKey("z")}
+	declare := func(req roachpb.Request) [numSpanAccess][]roachpb.Span {
+		var spans SpanSet
+		commands[req.Method()].DeclareKeys(desc, roachpb.Header{}, req, &spans)
+		var tombstoneSpans [numSpanAccess][]roachpb.Span
+		for access := SpanAccess(0); access < numSpanAccess; access++ {
+			for _, span := range spans.getSpans(access, spanLocal) {
+				if bytes.HasPrefix(span.Key, keys.LocalRangeTombstonePrefix) {
+					tombstoneSpans[access] = append(tombstoneSpans[access], span)
+				}
+			}
+		}
+		return tombstoneSpans
+	}
+	conflict := func(spans, otherSpans [numSpanAccess][]roachpb.Span) bool {
+		for access := SpanAccess(0); access < numSpanAccess; access++ {
+			for otherAccess := SpanAccess(0); otherAccess < numSpanAccess; otherAccess++ {
+				if access == SpanReadOnly && otherAccess == SpanReadOnly {
+					continue
+				}
+				for _, span := range spans[access] {
+					for _, otherSpan := range otherSpans[otherAccess] {
+						if span.Overlaps(otherSpan) {
+							return true
+						}
+					}
+				}
+			}
+		}
+		return false
+	}
+	tombstoneSpans := declare(&roachpb.DeleteRangeRequest{
+		Span:              roachpb.Span{Key: roachpb.Key("d"), EndKey: roachpb.Key("g")},
+		UseRangeTombstone: true,
+	})
+
+	for _, tc := range []struct {
+		req      roachpb.Request
+		conflict bool
+	}{
+		{&roachpb.GetRequest{Span: roachpb.Span{Key: roachpb.Key("b")}}, false},
+		{&roachpb.GetRequest{Span: roachpb.Span{Key: roachpb.Key("d")}}, true},
+		{&roachpb.PutRequest{Span: roachpb.Span{Key: roachpb.Key("f")}}, true},
+		{&roachpb.PutRequest{Span: roachpb.Span{Key: roachpb.Key("g")}}, false},
+		{&roachpb.ScanRequest{Span: roachpb.Span{Key: roachpb.Key("a"), EndKey: roachpb.Key("e")}}, true},
+		{&roachpb.ScanRequest{Span: roachpb.Span{Key: roachpb.Key("g"), EndKey: roachpb.Key("z")}}, false},
+		{&roachpb.DeleteRangeRequest{
+			Span:              roachpb.Span{Key: roachpb.Key("a"), EndKey: roachpb.Key("d")},
+			UseRangeTombstone: true,
+		}, false},
+		{&roachpb.DeleteRangeRequest{
+			Span:              roachpb.Span{Key: roachpb.Key("f"), EndKey: roachpb.Key("k")},
+			UseRangeTombstone: true,
+		}, true},
+	} {
+		if c := conflict(declare(tc.req), tombstoneSpans); c != tc.conflict {
+			t.Errorf("%s %s: expected conflict %t, got %t", tc.req.Method(), tc.req.Header(), tc.conflict, c)
+		}
+	}
+}
+
 func SendWrapped(
 	ctx context.Context, sender client.Sender, header roachpb.Header, args roachpb.Request,
 ) (roachpb.Response, roachpb.BatchResponse_Header, *roachpb.Error) {
@@ -3936,6 +4003,7 @@ func TestEndTransactionDirectGC(t *testing.T) {
 				var gr roachpb.GetResponse
 				if _, err := evalGet(
 					ctx, tc.engine, CommandArgs{
+						EvalCtx: ReplicaEvalContext{tc.repl, nil},
 						Args: &roachpb.GetRequest{Span: roachpb.Span{
 							Key: keys.TransactionKey(txn.Key, *txn.ID),
 						}},
@@ -133,6 +133,10 @@ type SpanSetIterator struct {
 	// Reaching an out-of-bounds key with Next/Prev invalidates the
 	// iterator but does not set err.
 	invalid bool
+	// Seek only checks the key sought, so it may land on an out-of-bounds
+	// key, whose other versions Next may move on to. The caller is
+	// responsible for the correctness of reading that key.
+	landingKey roachpb.Key
 }
 
 var _ engine.Iterator = &SpanSetIterator{}
@@ -154,6 +158,14 @@ func (s *SpanSetIterator) Seek(key engine.MVCCKey) {
 		s.invalid = false
 	}
 	s.i.Seek(key)
+	s.landingKey = nil
+	if ok, _ := s.i.Valid(); ok && s.err == nil {
+		if landingKey := s.i.UnsafeKey().Key; s.spans.checkAllowed(
+			SpanReadOnly, roachpb.Span{Key: landingKey},
+		) != nil {
+			s.landingKey = append(roachpb.Key(nil), landingKey...)
+		}
+	}
 }
 
 // SeekReverse implements engine.Iterator.
@@ -163,6 +175,7 @@ func (s *SpanSetIterator) SeekReverse(key engine.MVCCKey) {
 		s.invalid = false
 	}
 	s.i.SeekReverse(key)
+	s.landingKey = nil
 }
 
 // Valid implements engine.Iterator.
@@ -180,6 +193,9 @@ func (s *SpanSetIterator) Valid() (bool, error) {
 // Next implements engine.Iterator.
 func (s *SpanSetIterator) Next() {
 	s.i.Next()
+	if s.landingKey != nil && s.UnsafeKey().Key.Equal(s.landingKey) {
+		return
+	}
 	if s.spans.checkAllowed(SpanReadOnly, roachpb.Span{Key: s.UnsafeKey().Key}) != nil {
 		s.invalid = true
 	}
@@ -288,7 +304,7 @@ func (s spanSetReader) Iterate(
 }
 
 func (s spanSetReader) NewIterator(prefix bool) engine.Iterator {
-	return &SpanSetIterator{s.r.NewIterator(prefix), s.spans, nil, false}
+	return &SpanSetIterator{s.r.NewIterator(prefix), s.spans, nil, false, nil}
 }
 
 func (s spanSetReader) NewTimeBoundIterator(start, end hlc.Timestamp) engine.Iterator {
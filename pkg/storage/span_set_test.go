@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/pkg/errors"
 )
@@ -265,7 +266,7 @@ func TestSpanSetBatch(t *testing.T) {
 	if err := batch.Commit(true); err != nil {
 		t.Fatal(err)
 	}
-	iter := &SpanSetIterator{eng.NewIterator(false), &ss, nil, false}
+	iter := &SpanSetIterator{eng.NewIterator(false), &ss, nil, false, nil}
 	defer iter.Close()
 	iter.SeekReverse(outsideKey)
 	if _, err := iter.Valid(); !isReadSpanErr(err) {
@@ -299,3 +300,41 @@ func TestSpanSetBatch(t *testing.T) {
 		t.Fatalf("expected valid iterator, err=%v", err)
 	}
 }
+
+// TestSpanSetIteratorSeekLanding verifies that after seeking within the
+// declared spans and landing on a key outside of them, an iterator can move
+// on to the other versions of that key, but not beyond.
+func TestSpanSetIteratorSeekLanding(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	eng := engine.NewInMem(roachpb.Attributes{}, 10<<20)
+	defer eng.Close()
+
+	var ss SpanSet
+	ss.Add(SpanReadOnly, roachpb.Span{Key: roachpb.Key("c"), EndKey: roachpb.Key("g")})
+	landingKey := engine.MVCCKey{Key: roachpb.Key("m"), Timestamp: hlc.Timestamp{WallTime: 2}}
+	landingKey2 := engine.MVCCKey{Key: roachpb.Key("m"), Timestamp: hlc.Timestamp{WallTime: 1}}
+	outsideKey := engine.MakeMVCCMetadataKey(roachpb.Key("n"))
+	for _, key := range []engine.MVCCKey{landingKey, landingKey2, outsideKey} {
+		if err := eng.Put(key, []byte("value")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	iter := makeSpanSetReadWriter(eng, &ss).NewIterator(false)
+	defer iter.Close()
+	iter.Seek(engine.MakeMVCCMetadataKey(roachpb.Key("d")))
+	for _, key := range []engine.MVCCKey{landingKey, landingKey2} {
+		if ok, err := iter.Valid(); !ok {
+			t.Fatalf("expected valid iterator, err=%v", err)
+		}
+		if !reflect.DeepEqual(iter.Key(), key) {
+			t.Fatalf("expected key %s, got %s", key, iter.Key())
+		}
+		iter.Next()
+	}
+	if ok, err := iter.Valid(); ok {
+		t.Fatalf("expected invalid iterator; found valid at key %s", iter.Key())
+	} else if err != nil {
+		t.Errorf("unexpected error on iterator: %s", err)
+	}
+}
@@ -2628,6 +2628,30 @@ func (s *Store) Send(
 	}
 }
 
+// RangeFeed registers a range feed with the replica of the range specified
+// by the request and streams its events (see Replica.RangeFeed).
+func (s *Store) RangeFeed(
+	args *roachpb.RangeFeedRequest, stream RangeFeedEventSink,
+) *roachpb.Error {
+	if err := verifyKeys(args.Span.Key, args.Span.EndKey, true); err != nil {
+		return roachpb.NewError(err)
+	}
+
+	repl, err := s.GetReplica(args.RangeID)
+	if err != nil {
+		return roachpb.NewError(err)
+	}
+	if !repl.IsInitialized() {
+		// The replica is waiting for its snapshot, so redirect the client to the
+		// replica which caused it to be created, like Send does.
+		return roachpb.NewError(&roachpb.NotLeaseHolderError{
+			RangeID:     args.RangeID,
+			LeaseHolder: repl.creatingReplica,
+		})
+	}
+	return repl.RangeFeed(args, stream)
+}
+
 // maybeWaitInPushTxnQueue potentially diverts the incoming request to
 // the push txn queue, where it will wait for updates to the target
 // transaction.
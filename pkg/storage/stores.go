@@ -191,6 +191,18 @@ func (ls *Stores) Send(
 	return br, pErr
 }
 
+// RangeFeed registers a range feed on the store specified by the request
+// (see Store.RangeFeed).
+func (ls *Stores) RangeFeed(
+	args *roachpb.RangeFeedRequest, stream RangeFeedEventSink,
+) *roachpb.Error {
+	store, err := ls.GetStore(args.Replica.StoreID)
+	if err != nil {
+		return roachpb.NewError(err)
+	}
+	return store.RangeFeed(args, stream)
+}
+
 // LookupReplica looks up replica by key [range]. Lookups are done
 // by consulting each store in turn via Store.LookupReplica(key).
 // Returns RangeID and replica on success; RangeKeyMismatch error
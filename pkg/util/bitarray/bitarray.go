@@ -0,0 +1,242 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package bitarray implements the bit strings stored by the BIT and VARBIT
+// SQL types.
+package bitarray
+
+import (
+	"bytes"
+	"math/rand"
+
+	"github.com/pkg/errors"
+)
+
+// BitArray is an immutable string of bits. The bits are packed most
+// significant bit first into bytes; the unused bits of the last byte are
+// always zero.
+type BitArray struct {
+	data   []byte
+	bitLen uint
+}
+
+func bytesForBits(n uint) int {
+	return int((n + 7) / 8)
+}
+
+// Parse parses a string of '0' and '1' characters.
+func Parse(s string) (BitArray, error) {
+	a := BitArray{data: make([]byte, bytesForBits(uint(len(s)))), bitLen: uint(len(s))}
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '0':
+		case '1':
+			a.data[i/8] |= 0x80 >> uint(i%8)
+		default:
+			return BitArray{}, errors.Errorf("%q is not a valid binary digit", s[i])
+		}
+	}
+	return a, nil
+}
+
+// FromEncodingParts constructs a BitArray from the packed bytes and length
+// returned by EncodingParts.
+func FromEncodingParts(data []byte, bitLen uint) (BitArray, error) {
+	if len(data) != bytesForBits(bitLen) {
+		return BitArray{}, errors.Errorf("%d bytes cannot hold exactly %d bits", len(data), bitLen)
+	}
+	if unused := uint(len(data))*8 - bitLen; unused > 0 && data[len(data)-1]&(1<<unused-1) != 0 {
+		return BitArray{}, errors.Errorf("unused trailing bits of bit array are not zero")
+	}
+	return BitArray{data: append([]byte(nil), data...), bitLen: bitLen}, nil
+}
+
+// EncodingParts returns the packed bytes and the length of the array. The
+// bytes must not be modified.
+func (a BitArray) EncodingParts() ([]byte, uint) {
+	return a.data, a.bitLen
+}
+
+// FromInt constructs an array of bitLen bits holding the low bits of the two's
+// complement representation of v, or v sign extended if it has fewer than
+// bitLen bits.
+func FromInt(bitLen uint, v int64) BitArray {
+	a := BitArray{data: make([]byte, bytesForBits(bitLen)), bitLen: bitLen}
+	for i := uint(0); i < bitLen; i++ {
+		shift := bitLen - 1 - i
+		if shift >= 64 {
+			shift = 63
+		}
+		if v&(1<<shift) != 0 {
+			a.data[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return a
+}
+
+// AsInt returns the integer whose two's complement representation holds the
+// last 64 bits of the array. Like in Postgres, shorter arrays are zero
+// extended.
+func (a BitArray) AsInt() int64 {
+	var v int64
+	for i := uint(0); i < a.bitLen; i++ {
+		v <<= 1
+		if a.Get(i) {
+			v |= 1
+		}
+	}
+	return v
+}
+
+// BitLen returns the number of bits in the array.
+func (a BitArray) BitLen() uint {
+	return a.bitLen
+}
+
+// Get returns true if the i-th bit of the array, counting from the left, is
+// set.
+func (a BitArray) Get(i uint) bool {
+	return a.data[i/8]&(0x80>>(i%8)) != 0
+}
+
+// String formats the array as a string of '0' and '1' characters.
+func (a BitArray) String() string {
+	buf := make([]byte, a.bitLen)
+	for i := uint(0); i < a.bitLen; i++ {
+		buf[i] = '0'
+		if a.Get(i) {
+			buf[i] = '1'
+		}
+	}
+	return string(buf)
+}
+
+// Compare returns -1, 0 or 1 if a sorts before, together with or after b.
+// Arrays compare bit by bit, and a proper prefix of an array sorts before it.
+func Compare(a, b BitArray) int {
+	if c := bytes.Compare(a.data, b.data); c != 0 {
+		return c
+	}
+	if a.bitLen < b.bitLen {
+		return -1
+	} else if a.bitLen > b.bitLen {
+		return 1
+	}
+	return 0
+}
+
+// ToWidth returns the array truncated or padded with zeros on the right to
+// contain exactly n bits.
+func (a BitArray) ToWidth(n uint) BitArray {
+	if n == a.bitLen {
+		return a
+	}
+	r := BitArray{data: make([]byte, bytesForBits(n)), bitLen: n}
+	copy(r.data, a.data)
+	r.clearUnusedBits()
+	return r
+}
+
+func (a *BitArray) clearUnusedBits() {
+	if unused := uint(len(a.data))*8 - a.bitLen; unused > 0 {
+		a.data[len(a.data)-1] &^= 1<<unused - 1
+	}
+}
+
+// Concat returns the concatenation of a and b.
+func Concat(a, b BitArray) BitArray {
+	r := a.ToWidth(a.bitLen + b.bitLen)
+	for i := uint(0); i < b.bitLen; i++ {
+		if b.Get(i) {
+			j := a.bitLen + i
+			r.data[j/8] |= 0x80 >> (j % 8)
+		}
+	}
+	return r
+}
+
+func checkSameLength(op string, a, b BitArray) error {
+	if a.bitLen != b.bitLen {
+		return errors.Errorf("cannot %s bit strings of different sizes", op)
+	}
+	return nil
+}
+
+// And returns the bitwise AND of two arrays of the same length.
+func And(a, b BitArray) (BitArray, error) {
+	if err := checkSameLength("AND", a, b); err != nil {
+		return BitArray{}, err
+	}
+	r := BitArray{data: make([]byte, len(a.data)), bitLen: a.bitLen}
+	for i := range r.data {
+		r.data[i] = a.data[i] & b.data[i]
+	}
+	return r, nil
+}
+
+// Or returns the bitwise OR of two arrays of the same length.
+func Or(a, b BitArray) (BitArray, error) {
+	if err := checkSameLength("OR", a, b); err != nil {
+		return BitArray{}, err
+	}
+	r := BitArray{data: make([]byte, len(a.data)), bitLen: a.bitLen}
+	for i := range r.data {
+		r.data[i] = a.data[i] | b.data[i]
+	}
+	return r, nil
+}
+
+// Xor returns the bitwise XOR of two arrays of the same length.
+func Xor(a, b BitArray) (BitArray, error) {
+	if err := checkSameLength("XOR", a, b); err != nil {
+		return BitArray{}, err
+	}
+	r := BitArray{data: make([]byte, len(a.data)), bitLen: a.bitLen}
+	for i := range r.data {
+		r.data[i] = a.data[i] ^ b.data[i]
+	}
+	return r, nil
+}
+
+// Not returns the bitwise complement of the array.
+func Not(a BitArray) BitArray {
+	r := BitArray{data: make([]byte, len(a.data)), bitLen: a.bitLen}
+	for i := range r.data {
+		r.data[i] = ^a.data[i]
+	}
+	r.clearUnusedBits()
+	return r
+}
+
+// LeftShift returns the array shifted left by n bits, or right if n is
+// negative, filling the vacated positions with zeros. The length of the array
+// is preserved.
+func (a BitArray) LeftShift(n int64) BitArray {
+	r := BitArray{data: make([]byte, len(a.data)), bitLen: a.bitLen}
+	for i := uint(0); i < a.bitLen; i++ {
+		src := int64(i) + n
+		if src >= 0 && src < int64(a.bitLen) && a.Get(uint(src)) {
+			r.data[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return r
+}
+
+// Rand generates a random array of n bits.
+func Rand(rng *rand.Rand, n uint) BitArray {
+	a := BitArray{data: make([]byte, bytesForBits(n)), bitLen: n}
+	rng.Read(a.data)
+	a.clearUnusedBits()
+	return a
+}
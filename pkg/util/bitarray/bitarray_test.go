@@ -0,0 +1,120 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package bitarray
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+)
+
+func mustParse(t *testing.T, s string) BitArray {
+	a, err := Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestParseAndString(t *testing.T) {
+	for _, s := range []string{"", "0", "1", "0101", "11111111", "101010101"} {
+		if a := mustParse(t, s); a.String() != s || a.BitLen() != uint(len(s)) {
+			t.Errorf("expected %s, got %s (%d bits)", s, a, a.BitLen())
+		}
+	}
+	if _, err := Parse("012"); err == nil || !strings.Contains(err.Error(), "not a valid binary digit") {
+		t.Errorf("expected error, got %v", err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// Each array sorts after the previous one.
+	ordered := []string{"", "0", "00", "0001", "01", "1", "10", "100", "1000000000", "11"}
+	for i := 1; i < len(ordered); i++ {
+		a, b := mustParse(t, ordered[i-1]), mustParse(t, ordered[i])
+		if c := Compare(a, b); c != -1 {
+			t.Errorf("%s vs %s: expected -1, got %d", a, b, c)
+		}
+		if c := Compare(b, a); c != 1 {
+			t.Errorf("%s vs %s: expected 1, got %d", b, a, c)
+		}
+		if c := Compare(a, a); c != 0 {
+			t.Errorf("%s vs %s: expected 0, got %d", a, a, c)
+		}
+	}
+}
+
+func TestOperations(t *testing.T) {
+	a, b := mustParse(t, "1100110011"), mustParse(t, "1010101010")
+	testCases := []struct {
+		op       string
+		actual   func() (BitArray, error)
+		expected string
+	}{
+		{"and", func() (BitArray, error) { return And(a, b) }, "1000100010"},
+		{"or", func() (BitArray, error) { return Or(a, b) }, "1110111011"},
+		{"xor", func() (BitArray, error) { return Xor(a, b) }, "0110011001"},
+		{"not", func() (BitArray, error) { return Not(a), nil }, "0011001100"},
+		{"concat", func() (BitArray, error) { return Concat(a, mustParse(t, "01")), nil }, "110011001101"},
+		{"lshift", func() (BitArray, error) { return a.LeftShift(3), nil }, "0110011000"},
+		{"rshift", func() (BitArray, error) { return a.LeftShift(-3), nil }, "0001100110"},
+		{"truncate", func() (BitArray, error) { return a.ToWidth(3), nil }, "110"},
+		{"pad", func() (BitArray, error) { return a.ToWidth(12), nil }, "110011001100"},
+		{"fromInt", func() (BitArray, error) { return FromInt(4, 5), nil }, "0101"},
+		{"fromNegInt", func() (BitArray, error) { return FromInt(70, -2), nil }, strings.Repeat("1", 69) + "0"},
+	}
+	for _, tc := range testCases {
+		r, err := tc.actual()
+		if err != nil {
+			t.Errorf("%s: unexpected error %s", tc.op, err)
+		} else if r.String() != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.op, tc.expected, r)
+		}
+	}
+	if _, err := And(a, mustParse(t, "1")); err == nil {
+		t.Error("expected error for AND of bit strings of different sizes")
+	}
+	if v := mustParse(t, "0101").AsInt(); v != 5 {
+		t.Errorf("expected 5, got %d", v)
+	}
+	if v := mustParse(t, "1110").AsInt(); v != 14 {
+		t.Errorf("expected 14, got %d", v)
+	}
+	if v := FromInt(64, -2).AsInt(); v != -2 {
+		t.Errorf("expected -2, got %d", v)
+	}
+}
+
+func TestEncodingParts(t *testing.T) {
+	rng, _ := randutil.NewPseudoRand()
+	for i := 0; i < 100; i++ {
+		a := Rand(rng, uint(rng.Intn(100)))
+		data, n := a.EncodingParts()
+		r, err := FromEncodingParts(data, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if Compare(a, r) != 0 {
+			t.Fatalf("expected %s, got %s", a, r)
+		}
+	}
+	if _, err := FromEncodingParts([]byte{0x81}, 1); err == nil {
+		t.Error("expected error for non-zero unused bits")
+	}
+	if _, err := FromEncodingParts([]byte{0x80, 0}, 1); err == nil {
+		t.Error("expected error for extra bytes")
+	}
+}
@@ -31,7 +31,9 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
@@ -70,6 +72,9 @@ const (
 	decimalNaNDesc          = decimalInfinity + 1 // NaN encoded descendingly
 	decimalTerminator       = 0x00
 
+	bitArrayMarker     = decimalNaNDesc + 1
+	bitArrayDescMarker = bitArrayMarker + 1
+
 	// IntMin is chosen such that the range of int tags does not overlap the
 	// ascii character set that is frequently used in testing.
 	IntMin      = 0x80
@@ -461,6 +466,9 @@ type escapes struct {
 var (
 	ascendingEscapes  = escapes{escape, escapedTerm, escaped00, escapedFF, bytesMarker}
 	descendingEscapes = escapes{^escape, ^escapedTerm, ^escaped00, ^escapedFF, bytesDescMarker}
+
+	bitArrayAscendingEscapes  = escapes{escape, escapedTerm, escaped00, escapedFF, bitArrayMarker}
+	bitArrayDescendingEscapes = escapes{^escape, ^escapedTerm, ^escaped00, ^escapedFF, bitArrayDescMarker}
 )
 
 // EncodeBytesAscending encodes the []byte value using an escape-based
@@ -470,6 +478,12 @@ var (
 // and the resulting buffer is returned.
 func EncodeBytesAscending(b []byte, data []byte) []byte {
 	b = append(b, bytesMarker)
+	return encodeBytesAscendingWithoutMarker(b, data)
+}
+
+// encodeBytesAscendingWithoutMarker encodes the []byte value like
+// EncodeBytesAscending, but does not prefix it with bytesMarker.
+func encodeBytesAscendingWithoutMarker(b []byte, data []byte) []byte {
 	for {
 		// IndexByte is implemented by the go runtime in assembly and is
 		// much faster than looping over the bytes in the slice.
@@ -839,6 +853,75 @@ func DecodeDurationDescending(b []byte) ([]byte, duration.Duration, error) {
 	return b, d, nil
 }
 
+// EncodeBitArrayAscending encodes a bitarray.BitArray value, appends it to
+// the supplied buffer, and returns the final buffer. The packed bits are
+// encoded like bytes and followed by the number of bits, so that an array
+// sorts after its prefixes.
+func EncodeBitArrayAscending(b []byte, d bitarray.BitArray) []byte {
+	data, bitLen := d.EncodingParts()
+	b = append(b, bitArrayMarker)
+	b = encodeBytesAscendingWithoutMarker(b, data)
+	return EncodeUvarintAscending(b, uint64(bitLen))
+}
+
+// EncodeBitArrayDescending is the descending version of
+// EncodeBitArrayAscending.
+func EncodeBitArrayDescending(b []byte, d bitarray.BitArray) []byte {
+	data, bitLen := d.EncodingParts()
+	n := len(b)
+	b = append(b, bitArrayDescMarker)
+	b = encodeBytesAscendingWithoutMarker(b, data)
+	onesComplement(b[n+1:])
+	return EncodeUvarintDescending(b, uint64(bitLen))
+}
+
+// DecodeBitArrayAscending decodes a bitarray.BitArray value which was encoded
+// using EncodeBitArrayAscending. The remainder of the input buffer and the
+// decoded bit array are returned.
+func DecodeBitArrayAscending(b []byte) ([]byte, bitarray.BitArray, error) {
+	b, data, err := decodeBytesInternal(b, nil, bitArrayAscendingEscapes, true)
+	if err != nil {
+		return nil, bitarray.BitArray{}, err
+	}
+	b, bitLen, err := DecodeUvarintAscending(b)
+	if err != nil {
+		return nil, bitarray.BitArray{}, err
+	}
+	d, err := bitarray.FromEncodingParts(data, uint(bitLen))
+	return b, d, err
+}
+
+// DecodeBitArrayDescending decodes a bitarray.BitArray value which was
+// encoded using EncodeBitArrayDescending.
+func DecodeBitArrayDescending(b []byte) ([]byte, bitarray.BitArray, error) {
+	// Always pass an `r` to make sure we never get back a sub-slice of `b`,
+	// since we're going to modify the contents of the slice.
+	b, data, err := decodeBytesInternal(b, []byte{}, bitArrayDescendingEscapes, true)
+	if err != nil {
+		return nil, bitarray.BitArray{}, err
+	}
+	onesComplement(data)
+	b, bitLen, err := DecodeUvarintDescending(b)
+	if err != nil {
+		return nil, bitarray.BitArray{}, err
+	}
+	d, err := bitarray.FromEncodingParts(data, uint(bitLen))
+	return b, d, err
+}
+
+// getBitArrayLength finds the length of a bit array encoding.
+func getBitArrayLength(b []byte, e escapes) (int, error) {
+	n, err := getBytesLength(b, e)
+	if err != nil {
+		return 0, err
+	}
+	if n >= len(b) {
+		return 0, errors.Errorf("missing length of bit array in buffer %#x", b)
+	}
+	m, err := getVarintLen(b[n:])
+	return n + m, err
+}
+
 // Type represents the type of a value encoded by
 // Encode{Null,NotNull,Varint,Uvarint,Float,Bytes}.
 //go:generate stringer -type=Type
@@ -860,7 +943,10 @@ const (
 	True
 	False
 	UUID
+	IPAddr
+	BitArray
 	SentinelType Type = 15 // Used in the Value encoding.
+	BitArrayDesc Type = 16 // BitArray encoded descendingly
 )
 
 // PeekType peeks at the type of the value encoded at the start of b.
@@ -886,6 +972,10 @@ func PeekType(b []byte) Type {
 			return Float
 		case m >= decimalNaN && m <= decimalNaNDesc:
 			return Decimal
+		case m == bitArrayMarker:
+			return BitArray
+		case m == bitArrayDescMarker:
+			return BitArrayDesc
 		}
 	}
 	return Unknown
@@ -938,6 +1028,10 @@ func PeekLength(b []byte) (int, error) {
 		return GetMultiVarintLen(b, 2)
 	case durationBigNegMarker, durationMarker, durationBigPosMarker:
 		return GetMultiVarintLen(b, 3)
+	case bitArrayMarker:
+		return getBitArrayLength(b, bitArrayAscendingEscapes)
+	case bitArrayDescMarker:
+		return getBitArrayLength(b, bitArrayDescendingEscapes)
 	case floatNeg, floatPos:
 		// the marker is followed by 8 bytes
 		if len(b) < 9 {
@@ -1031,6 +1125,20 @@ func prettyPrintFirstValue(b []byte) ([]byte, string, error) {
 			return b, "", err
 		}
 		return b, d.String(), nil
+	case BitArray:
+		var d bitarray.BitArray
+		b, d, err = DecodeBitArrayAscending(b)
+		if err != nil {
+			return b, "", err
+		}
+		return b, "B" + d.String(), nil
+	case BitArrayDesc:
+		var d bitarray.BitArray
+		b, d, err = DecodeBitArrayDescending(b)
+		if err != nil {
+			return b, "", err
+		}
+		return b, "B" + d.String(), nil
 	default:
 		// This shouldn't ever happen, but if it does, return an empty slice.
 		return nil, strconv.Quote(string(b)), nil
@@ -1248,6 +1356,28 @@ func EncodeUUIDValue(appendTo []byte, colID uint32, u uuid.UUID) []byte {
 	return append(appendTo, u.GetBytes()...)
 }
 
+// EncodeIPAddrValue encodes an ipaddr.IPAddr value, appends it to the
+// supplied buffer, and returns the final buffer.
+func EncodeIPAddrValue(appendTo []byte, colID uint32, u ipaddr.IPAddr) []byte {
+	appendTo = encodeValueTag(appendTo, colID, IPAddr)
+	return u.ToBuffer(appendTo)
+}
+
+// EncodeBitArrayValue encodes a bitarray.BitArray value, appends it to the
+// supplied buffer, and returns the final buffer.
+func EncodeBitArrayValue(appendTo []byte, colID uint32, d bitarray.BitArray) []byte {
+	appendTo = encodeValueTag(appendTo, colID, BitArray)
+	return EncodeUntaggedBitArrayValue(appendTo, d)
+}
+
+// EncodeUntaggedBitArrayValue encodes a bitarray.BitArray value without a
+// value tag, appends it to the supplied buffer, and returns the final buffer.
+func EncodeUntaggedBitArrayValue(appendTo []byte, d bitarray.BitArray) []byte {
+	data, bitLen := d.EncodingParts()
+	appendTo = EncodeNonsortingUvarint(appendTo, uint64(bitLen))
+	return append(appendTo, data...)
+}
+
 // DecodeValueTag decodes a value encoded by encodeValueTag, used as a prefix in
 // each of the other EncodeFooValue methods.
 //
@@ -1421,6 +1551,47 @@ func DecodeUUIDValue(b []byte) (remaining []byte, u uuid.UUID, err error) {
 	return b[uuidValueEncodedLength:], u, nil
 }
 
+// DecodeIPAddrValue decodes a value encoded by EncodeIPAddrValue.
+func DecodeIPAddrValue(b []byte) (remaining []byte, u ipaddr.IPAddr, err error) {
+	b, err = decodeValueTypeAssert(b, IPAddr)
+	if err != nil {
+		return b, u, err
+	}
+	u, b, err = ipaddr.FromBuffer(b)
+	return b, u, err
+}
+
+// DecodeBitArrayValue decodes a value encoded by EncodeBitArrayValue.
+func DecodeBitArrayValue(b []byte) (remaining []byte, d bitarray.BitArray, err error) {
+	b, err = decodeValueTypeAssert(b, BitArray)
+	if err != nil {
+		return b, d, err
+	}
+	return DecodeUntaggedBitArrayValue(b)
+}
+
+// DecodeUntaggedBitArrayValue decodes a value encoded by
+// EncodeUntaggedBitArrayValue.
+func DecodeUntaggedBitArrayValue(b []byte) (remaining []byte, d bitarray.BitArray, err error) {
+	var bitLen uint64
+	b, _, bitLen, err = DecodeNonsortingUvarint(b)
+	if err != nil {
+		return b, d, err
+	}
+	n := bitArrayValueDataLen(bitLen)
+	if len(b) < n {
+		return b, d, errors.Errorf("bit array value of %d bits should have %d bytes: %d", bitLen, n, len(b))
+	}
+	d, err = bitarray.FromEncodingParts(b[:n], uint(bitLen))
+	return b[n:], d, err
+}
+
+// bitArrayValueDataLen returns the number of bytes holding the packed bits of
+// a bit array value of the given length.
+func bitArrayValueDataLen(bitLen uint64) int {
+	return int((bitLen + 7) / 8)
+}
+
 func decodeValueTypeAssert(b []byte, expected Type) ([]byte, error) {
 	_, dataOffset, _, typ, err := DecodeValueTag(b)
 	if err != nil {
@@ -1478,6 +1649,12 @@ func PeekValueLength(b []byte) (typeOffset int, length int, err error) {
 		return typeOffset, dataOffset + n, err
 	case UUID:
 		return typeOffset, dataOffset + uuidValueEncodedLength, err
+	case IPAddr:
+		n, err := ipaddr.PeekEncodedLen(b)
+		return typeOffset, dataOffset + n, err
+	case BitArray:
+		_, n, bitLen, err := DecodeNonsortingUvarint(b)
+		return typeOffset, dataOffset + n + bitArrayValueDataLen(bitLen), err
 	default:
 		return 0, 0, errors.Errorf("unknown type %s", typ)
 	}
@@ -1510,6 +1687,13 @@ func UpperBoundValueEncodingSize(colID uint32, typ Type, size int) (int, bool) {
 		return len(encodedTag) + 2*maxVarintSize, true
 	case Duration:
 		return len(encodedTag) + 3*maxVarintSize, true
+	case IPAddr:
+		return len(encodedTag) + ipaddr.IPAddr{Family: ipaddr.IPv6family}.EncodedLen(), true
+	case BitArray:
+		if size > 0 {
+			return len(encodedTag) + maxVarintSize + bitArrayValueDataLen(uint64(size)), true
+		}
+		return 0, false
 	default:
 		panic(fmt.Errorf("unknown type: %s", typ))
 	}
@@ -1579,6 +1763,20 @@ func PrettyPrintValueEncoded(b []byte) ([]byte, string, error) {
 			return b, "", err
 		}
 		return b, d.String(), nil
+	case IPAddr:
+		var ipAddr ipaddr.IPAddr
+		b, ipAddr, err = DecodeIPAddrValue(b)
+		if err != nil {
+			return b, "", err
+		}
+		return b, ipAddr.String(), nil
+	case BitArray:
+		var d bitarray.BitArray
+		b, d, err = DecodeBitArrayValue(b)
+		if err != nil {
+			return b, "", err
+		}
+		return b, "B" + d.String(), nil
 	default:
 		return b, "", errors.Errorf("unknown type %s", typ)
 	}
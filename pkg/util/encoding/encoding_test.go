@@ -27,7 +27,9 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
@@ -839,6 +841,45 @@ func TestEncodeDecodeDescending(t *testing.T) {
 	testCustomEncodeDuration(testCases, EncodeDurationDescending, DecodeDurationDescending, t)
 }
 
+func TestEncodeDecodeBitArray(t *testing.T) {
+	rng, seed := randutil.NewPseudoRand()
+	for _, dir := range []Direction{Ascending, Descending} {
+		enc, dec := EncodeBitArrayAscending, DecodeBitArrayAscending
+		if dir == Descending {
+			enc, dec = EncodeBitArrayDescending, DecodeBitArrayDescending
+		}
+		var last bitarray.BitArray
+		var lastEnc []byte
+		for i := 0; i < 1000; i++ {
+			// Keep the arrays short and sparse so that prefixes and zero bytes,
+			// which must be escaped, are frequent.
+			v := bitarray.Rand(rng, uint(rng.Intn(20)))
+			if rng.Intn(2) == 0 {
+				v = v.ToWidth(uint(rng.Intn(20)))
+			}
+			encoded := enc(nil, v)
+			testPeekLength(t, encoded)
+			remaining, decoded, err := dec(append(encoded, 0x42))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bitarray.Compare(v, decoded) != 0 || !bytes.Equal(remaining, []byte{0x42}) {
+				t.Fatalf("seed %d: expected %s, got %s (remaining %x)", seed, v, decoded, remaining)
+			}
+			if i > 0 {
+				c := bytes.Compare(lastEnc, encoded)
+				if dir == Descending {
+					c = -c
+				}
+				if e := bitarray.Compare(last, v); c != e {
+					t.Fatalf("seed %d: %s vs %s: encodings compare %d, expected %d", seed, last, v, c, e)
+				}
+			}
+			last, lastEnc = v, encoded
+		}
+	}
+}
+
 func TestPeekType(t *testing.T) {
 	encodedDurationAscending, _ := EncodeDurationAscending(nil, duration.Duration{})
 	encodedDurationDescending, _ := EncodeDurationDescending(nil, duration.Duration{})
@@ -864,6 +905,8 @@ func TestPeekType(t *testing.T) {
 		{EncodeTimeDescending(nil, timeutil.Now()), Time},
 		{encodedDurationAscending, Duration},
 		{encodedDurationDescending, Duration},
+		{EncodeBitArrayAscending(nil, bitarray.BitArray{}), BitArray},
+		{EncodeBitArrayDescending(nil, bitarray.BitArray{}), BitArrayDesc},
 	}
 	for i, c := range testCases {
 		typ := PeekType(c.enc)
@@ -1562,6 +1605,12 @@ func randValueEncode(rd randData, buf []byte, colID uint32, typ Type) ([]byte, i
 	case Duration:
 		x := rd.duration()
 		return EncodeDurationValue(buf, colID, x), x, true
+	case IPAddr:
+		x := ipaddr.RandIPAddr(rd.Rand)
+		return EncodeIPAddrValue(buf, colID, x), x, true
+	case BitArray:
+		x := bitarray.Rand(rd.Rand, uint(rd.Intn(100)))
+		return EncodeBitArrayValue(buf, colID, x), x, true
 	default:
 		return buf, nil, false
 	}
@@ -1711,6 +1760,10 @@ func TestValueEncodingRand(t *testing.T) {
 			buf, decoded, err = DecodeTimeValue(buf)
 		case Duration:
 			buf, decoded, err = DecodeDurationValue(buf)
+		case IPAddr:
+			buf, decoded, err = DecodeIPAddrValue(buf)
+		case BitArray:
+			buf, decoded, err = DecodeBitArrayValue(buf)
 		default:
 			err = errors.Errorf("unknown type %s", typ)
 		}
@@ -1729,6 +1782,14 @@ func TestValueEncodingRand(t *testing.T) {
 			if d.Cmp(&val) != 0 {
 				t.Fatalf("seed %d: %s got %v expected %v", seed, typ, decoded, value)
 			}
+		case IPAddr:
+			if !decoded.(ipaddr.IPAddr).Equal(value.(ipaddr.IPAddr)) {
+				t.Fatalf("seed %d: %s got %v expected %v", seed, typ, decoded, value)
+			}
+		case BitArray:
+			if bitarray.Compare(decoded.(bitarray.BitArray), value.(bitarray.BitArray)) != 0 {
+				t.Fatalf("seed %d: %s got %v expected %v", seed, typ, decoded, value)
+			}
 		default:
 			if decoded != value {
 				t.Fatalf("seed %d: %s got %v expected %v", seed, typ, decoded, value)
@@ -1756,6 +1817,9 @@ func TestUpperBoundValueEncodingSize(t *testing.T) {
 		{colID: 0, typ: Duration, size: 28},
 		{colID: 0, typ: Bytes, size: -1},
 		{colID: 0, typ: Bytes, width: 100, size: 110},
+		{colID: 0, typ: IPAddr, size: 19},
+		{colID: 0, typ: BitArray, size: -1},
+		{colID: 0, typ: BitArray, width: 100, size: 23},
 
 		{colID: 8, typ: True, size: 2},
 	}
@@ -1793,6 +1857,9 @@ func TestPrettyPrintValueEncoded(t *testing.T) {
 			duration.Duration{Months: 1, Days: 2, Nanos: 3}), "1mon2d3ns"},
 		{EncodeBytesValue(nil, NoColumnID, []byte{0x1, 0x2, 0xF, 0xFF}), "01020fff"},
 		{EncodeBytesValue(nil, NoColumnID, []byte("foo")), "foo"},
+		{EncodeIPAddrValue(nil, NoColumnID, ipaddr.IPAddr{
+			Family: ipaddr.IPv4family, Addr: []byte{192, 168, 0, 1}, Mask: 24}), "192.168.0.1/24"},
+		{EncodeBitArrayValue(nil, NoColumnID, bitarray.FromInt(10, 5)), "B0000000101"},
 	}
 	for i, test := range tests {
 		remaining, str, err := PrettyPrintValueEncoded(test.buf)
@@ -0,0 +1,284 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package ipaddr implements the IP network addresses stored by the INET and
+// CIDR SQL types.
+package ipaddr
+
+import (
+	"bytes"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// IPFamily denotes the version of the IP protocol an address belongs to.
+type IPFamily byte
+
+const (
+	// IPv4family is the family of IPv4 addresses.
+	IPv4family IPFamily = 4
+	// IPv6family is the family of IPv6 addresses.
+	IPv6family IPFamily = 6
+)
+
+// IPAddr is an IP address together with the length of the prefix of its
+// network. Unlike the CIDR notation of net.IPNet, the host bits of the address
+// are preserved.
+type IPAddr struct {
+	Family IPFamily
+	// Addr holds 4 bytes for IPv4 addresses and 16 bytes for IPv6 addresses.
+	Addr net.IP
+	// Mask is the number of leading bits of Addr that identify the network.
+	Mask byte
+}
+
+// MaxMask returns the number of bits of addresses of the given family.
+func (f IPFamily) MaxMask() byte {
+	if f == IPv4family {
+		return 32
+	}
+	return 128
+}
+
+func (f IPFamily) addrLen() int {
+	return int(f.MaxMask() / 8)
+}
+
+// ParseINet parses an IP address with an optional prefix length, as in
+// "192.168.0.1/24" or "::1". When the prefix length is omitted, the address
+// identifies a single host.
+func ParseINet(s string) (IPAddr, error) {
+	addrStr, maskStr := s, ""
+	if i := strings.IndexByte(s, '/'); i >= 0 {
+		addrStr, maskStr = s[:i], s[i+1:]
+	}
+	ip := net.ParseIP(addrStr)
+	if ip == nil {
+		return IPAddr{}, errors.Errorf("invalid IP address %q", addrStr)
+	}
+	ipAddr := IPAddr{Family: IPv6family, Addr: ip.To16()}
+	if strings.IndexByte(addrStr, ':') < 0 {
+		ipAddr.Family, ipAddr.Addr = IPv4family, ip.To4()
+	}
+	ipAddr.Mask = ipAddr.Family.MaxMask()
+	if maskStr != "" {
+		mask, err := strconv.Atoi(maskStr)
+		if err != nil || mask < 0 || mask > int(ipAddr.Family.MaxMask()) {
+			return IPAddr{}, errors.Errorf("invalid mask length %q", maskStr)
+		}
+		ipAddr.Mask = byte(mask)
+	}
+	return ipAddr, nil
+}
+
+// ParseCIDR parses a network address in CIDR notation. Unlike ParseINet, it
+// rejects addresses which have bits set to the right of the prefix.
+func ParseCIDR(s string) (IPAddr, error) {
+	ipAddr, err := ParseINet(s)
+	if err != nil {
+		return IPAddr{}, err
+	}
+	if !ipAddr.IsNetwork() {
+		return IPAddr{}, errors.Errorf("invalid cidr value %q: value has bits set to right of mask", s)
+	}
+	return ipAddr, nil
+}
+
+// String formats the address. The prefix length is omitted when the address
+// identifies a single host.
+func (ipAddr IPAddr) String() string {
+	if ipAddr.Mask == ipAddr.Family.MaxMask() {
+		return ipAddr.Host()
+	}
+	return ipAddr.CIDRString()
+}
+
+// CIDRString formats the address, always including the prefix length.
+func (ipAddr IPAddr) CIDRString() string {
+	return ipAddr.Host() + "/" + strconv.Itoa(int(ipAddr.Mask))
+}
+
+// Host formats the address without its prefix length.
+func (ipAddr IPAddr) Host() string {
+	if ipAddr.Family == IPv6family {
+		// net.IP formats IPv4-mapped IPv6 addresses as IPv4 addresses.
+		if v4 := ipAddr.Addr.To4(); v4 != nil {
+			return "::ffff:" + v4.String()
+		}
+	}
+	return ipAddr.Addr.String()
+}
+
+// Compare returns -1, 0 or 1 if ipAddr sorts before, together with or after
+// other. IPv4 addresses sort before IPv6 addresses; addresses of the same
+// family sort by address and then by prefix length.
+func (ipAddr IPAddr) Compare(other IPAddr) int {
+	if ipAddr.Family != other.Family {
+		if ipAddr.Family < other.Family {
+			return -1
+		}
+		return 1
+	}
+	if c := bytes.Compare(ipAddr.Addr, other.Addr); c != 0 {
+		return c
+	}
+	if ipAddr.Mask != other.Mask {
+		if ipAddr.Mask < other.Mask {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// Equal returns true if both addresses and their prefix lengths are equal.
+func (ipAddr IPAddr) Equal(other IPAddr) bool {
+	return ipAddr.Compare(other) == 0
+}
+
+// maskBytes returns the bytes of the network mask of the given length.
+func (f IPFamily) maskBytes(mask byte) net.IP {
+	return net.IP(net.CIDRMask(int(mask), int(f.MaxMask())))
+}
+
+// Netmask returns the network mask of the address, as a host address.
+func (ipAddr IPAddr) Netmask() IPAddr {
+	return IPAddr{
+		Family: ipAddr.Family,
+		Addr:   ipAddr.Family.maskBytes(ipAddr.Mask),
+		Mask:   ipAddr.Family.MaxMask(),
+	}
+}
+
+// Hostmask returns the complement of the network mask of the address, as a
+// host address.
+func (ipAddr IPAddr) Hostmask() IPAddr {
+	m := ipAddr.Family.maskBytes(ipAddr.Mask)
+	for i := range m {
+		m[i] = ^m[i]
+	}
+	return IPAddr{Family: ipAddr.Family, Addr: m, Mask: ipAddr.Family.MaxMask()}
+}
+
+// Network returns the address of the network, obtained by clearing the host
+// bits of the address.
+func (ipAddr IPAddr) Network() IPAddr {
+	return IPAddr{
+		Family: ipAddr.Family,
+		Addr:   ipAddr.Addr.Mask(net.CIDRMask(int(ipAddr.Mask), int(ipAddr.Family.MaxMask()))),
+		Mask:   ipAddr.Mask,
+	}
+}
+
+// Broadcast returns the broadcast address of the network, obtained by setting
+// the host bits of the address.
+func (ipAddr IPAddr) Broadcast() IPAddr {
+	h := ipAddr.Hostmask()
+	addr := make(net.IP, len(ipAddr.Addr))
+	for i := range addr {
+		addr[i] = ipAddr.Addr[i] | h.Addr[i]
+	}
+	return IPAddr{Family: ipAddr.Family, Addr: addr, Mask: ipAddr.Mask}
+}
+
+// IsNetwork returns true if none of the host bits of the address is set.
+func (ipAddr IPAddr) IsNetwork() bool {
+	return ipAddr.Network().Addr.Equal(ipAddr.Addr)
+}
+
+// SetMask returns the address with its prefix length changed to mask.
+func (ipAddr IPAddr) SetMask(mask int) (IPAddr, error) {
+	if mask < 0 || mask > int(ipAddr.Family.MaxMask()) {
+		return IPAddr{}, errors.Errorf("invalid mask length %d for IPv%d address", mask, ipAddr.Family)
+	}
+	ipAddr.Mask = byte(mask)
+	return ipAddr, nil
+}
+
+// ContainsOrEquals returns true if the network of other is the same as or a
+// subnet of the network of ipAddr.
+func (ipAddr IPAddr) ContainsOrEquals(other IPAddr) bool {
+	if ipAddr.Family != other.Family || ipAddr.Mask > other.Mask {
+		return false
+	}
+	m := net.CIDRMask(int(ipAddr.Mask), int(ipAddr.Family.MaxMask()))
+	return ipAddr.Addr.Mask(m).Equal(other.Addr.Mask(m))
+}
+
+// Contains returns true if the network of other is a strict subnet of the
+// network of ipAddr.
+func (ipAddr IPAddr) Contains(other IPAddr) bool {
+	return ipAddr.Mask < other.Mask && ipAddr.ContainsOrEquals(other)
+}
+
+// ToBuffer appends the encoding of the address to appendTo. The encoding
+// sorts like Compare.
+func (ipAddr IPAddr) ToBuffer(appendTo []byte) []byte {
+	appendTo = append(appendTo, byte(ipAddr.Family))
+	appendTo = append(appendTo, ipAddr.Addr...)
+	return append(appendTo, ipAddr.Mask)
+}
+
+// FromBuffer decodes an address encoded by ToBuffer, returning the remaining
+// bytes.
+func FromBuffer(b []byte) (IPAddr, []byte, error) {
+	if len(b) == 0 {
+		return IPAddr{}, nil, errors.New("empty buffer for IP address")
+	}
+	f := IPFamily(b[0])
+	if f != IPv4family && f != IPv6family {
+		return IPAddr{}, nil, errors.Errorf("unknown IP family %d", b[0])
+	}
+	n := f.addrLen()
+	if len(b) < n+2 {
+		return IPAddr{}, nil, errors.Errorf("buffer too short for IPv%d address: %d", f, len(b))
+	}
+	ipAddr := IPAddr{Family: f, Addr: append(net.IP(nil), b[1:n+1]...), Mask: b[n+1]}
+	if ipAddr.Mask > f.MaxMask() {
+		return IPAddr{}, nil, errors.Errorf("invalid mask length %d for IPv%d address", ipAddr.Mask, f)
+	}
+	return ipAddr, b[n+2:], nil
+}
+
+// EncodedLen returns the length of the encoding of the address produced by
+// ToBuffer.
+func (ipAddr IPAddr) EncodedLen() int {
+	return ipAddr.Family.addrLen() + 2
+}
+
+// PeekEncodedLen returns the length of the encoded address at the start of b.
+func PeekEncodedLen(b []byte) (int, error) {
+	if len(b) == 0 {
+		return 0, errors.New("empty buffer for IP address")
+	}
+	return IPFamily(b[0]).addrLen() + 2, nil
+}
+
+// RandIPAddr generates a random IPv4 or IPv6 address.
+func RandIPAddr(rng *rand.Rand) IPAddr {
+	ipAddr := IPAddr{Family: IPv4family}
+	if rng.Intn(2) == 0 {
+		ipAddr.Family = IPv6family
+	}
+	ipAddr.Addr = make(net.IP, ipAddr.Family.addrLen())
+	for i := range ipAddr.Addr {
+		ipAddr.Addr[i] = byte(rng.Intn(256))
+	}
+	ipAddr.Mask = byte(rng.Intn(int(ipAddr.Family.MaxMask()) + 1))
+	return ipAddr
+}
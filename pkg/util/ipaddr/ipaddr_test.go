@@ -0,0 +1,157 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package ipaddr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+)
+
+func TestParseINet(t *testing.T) {
+	testCases := []struct {
+		in       string
+		expected string
+		err      bool
+	}{
+		{"192.168.1.2", "192.168.1.2", false},
+		{"192.168.1.2/32", "192.168.1.2", false},
+		{"192.168.1.2/24", "192.168.1.2/24", false},
+		{"10.0.0.0/8", "10.0.0.0/8", false},
+		{"0.0.0.0/0", "0.0.0.0/0", false},
+		{"::1", "::1", false},
+		{"2001:db8::1/64", "2001:db8::1/64", false},
+		{"::ffff:1.2.3.4", "::ffff:1.2.3.4", false},
+		{"192.168.1.2/33", "", true},
+		{"::1/129", "", true},
+		{"192.168.1.2/-1", "", true},
+		{"192.168.1.2/a", "", true},
+		{"192.168.1", "", true},
+		{"", "", true},
+	}
+	for _, tc := range testCases {
+		ipAddr, err := ParseINet(tc.in)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%s: expected error, got %s", tc.in, ipAddr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.in, err)
+			continue
+		}
+		if s := ipAddr.String(); s != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.in, tc.expected, s)
+		}
+	}
+}
+
+func TestParseCIDR(t *testing.T) {
+	if _, err := ParseCIDR("10.1.0.0/16"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseCIDR("10.1.2.0/16"); err == nil ||
+		!strings.Contains(err.Error(), "bits set to right of mask") {
+		t.Fatalf("expected error, got %v", err)
+	}
+}
+
+func TestNetworkFunctions(t *testing.T) {
+	testCases := []struct {
+		in                                    string
+		network, broadcast, netmask, hostmask string
+	}{
+		{"192.168.1.5/24", "192.168.1.0/24", "192.168.1.255/24", "255.255.255.0", "0.0.0.255"},
+		{"10.1.2.3/12", "10.0.0.0/12", "10.15.255.255/12", "255.240.0.0", "0.15.255.255"},
+		{"10.1.2.3", "10.1.2.3", "10.1.2.3", "255.255.255.255", "0.0.0.0"},
+		{"2001:db8::1/32", "2001:db8::/32", "2001:db8:ffff:ffff:ffff:ffff:ffff:ffff/32",
+			"ffff:ffff::", "::ffff:ffff:ffff:ffff:ffff:ffff"},
+	}
+	for _, tc := range testCases {
+		ipAddr, err := ParseINet(tc.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range []struct {
+			name          string
+			actual, expec string
+		}{
+			{"network", ipAddr.Network().String(), tc.network},
+			{"broadcast", ipAddr.Broadcast().String(), tc.broadcast},
+			{"netmask", ipAddr.Netmask().String(), tc.netmask},
+			{"hostmask", ipAddr.Hostmask().String(), tc.hostmask},
+		} {
+			if c.actual != c.expec {
+				t.Errorf("%s(%s): expected %s, got %s", c.name, tc.in, c.expec, c.actual)
+			}
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	testCases := []struct {
+		a, b                       string
+		contains, containsOrEquals bool
+	}{
+		{"192.168.0.0/16", "192.168.1.0/24", true, true},
+		{"192.168.0.0/16", "192.168.1.5", true, true},
+		{"192.168.0.0/16", "192.168.0.0/16", false, true},
+		{"192.168.1.0/24", "192.168.0.0/16", false, false},
+		{"192.168.0.0/16", "192.169.0.0/24", false, false},
+		{"0.0.0.0/0", "8.8.8.8", true, true},
+		{"0.0.0.0/0", "::1", false, false},
+		{"2001:db8::/32", "2001:db8:1::/48", true, true},
+	}
+	for _, tc := range testCases {
+		a, err := ParseINet(tc.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ParseINet(tc.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c := a.Contains(b); c != tc.contains {
+			t.Errorf("%s >> %s: expected %t, got %t", tc.a, tc.b, tc.contains, c)
+		}
+		if c := a.ContainsOrEquals(b); c != tc.containsOrEquals {
+			t.Errorf("%s >>= %s: expected %t, got %t", tc.a, tc.b, tc.containsOrEquals, c)
+		}
+	}
+}
+
+func TestBufferRoundTripAndOrder(t *testing.T) {
+	rng, _ := randutil.NewPseudoRand()
+	for i := 0; i < 1000; i++ {
+		a, b := RandIPAddr(rng), RandIPAddr(rng)
+		encA, encB := a.ToBuffer(nil), b.ToBuffer(nil)
+		if n, err := PeekEncodedLen(encA); err != nil || n != len(encA) || n != a.EncodedLen() {
+			t.Fatalf("%s: unexpected encoded length %d (%v), expected %d", a, n, err, len(encA))
+		}
+		decoded, rest, err := FromBuffer(append(encA, 'x'))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !decoded.Equal(a) || !bytes.Equal(rest, []byte{'x'}) {
+			t.Fatalf("expected %s, got %s (remaining %q)", a, decoded, rest)
+		}
+		if c, e := bytes.Compare(encA, encB), a.Compare(b); c != e {
+			t.Fatalf("%s vs %s: encodings compare %d, addresses compare %d", a, b, c, e)
+		}
+	}
+}
@@ -596,6 +596,7 @@ func init() {
 	logging.stderrThreshold = Severity_INFO
 	logging.fileThreshold = Severity_INFO
 
+	logging.prefix = program
 	logging.setVState(0, nil, false)
 	logging.exitFunc = os.Exit
 	logging.gcNotify = make(chan struct{}, 1)
@@ -619,6 +620,11 @@ func StartGCDaemon() {
 // Flush flushes all pending log I/O.
 func Flush() {
 	logging.lockAndFlushAll()
+	secondaryLogRegistry.mu.Lock()
+	defer secondaryLogRegistry.mu.Unlock()
+	for _, l := range secondaryLogRegistry.mu.loggers {
+		l.logger.lockAndFlushAll()
+	}
 }
 
 // SetSync configures whether logging synchronizes all writes.
@@ -638,6 +644,9 @@ type loggingT struct {
 
 	noStderrRedirect bool
 
+	// Name prefix for log files.
+	prefix string
+
 	// Level flag for output to stderr. Handled atomically.
 	stderrThreshold Severity
 	// Level flag for output to files.
@@ -961,7 +970,7 @@ func (sb *syncBuffer) rotateFile(now time.Time) error {
 		}
 	}
 	var err error
-	sb.file, sb.lastRotation, _, err = create(now, sb.lastRotation)
+	sb.file, sb.lastRotation, _, err = create(sb.logger.prefix, now, sb.lastRotation)
 	sb.nbytes = 0
 	if err != nil {
 		return err
@@ -971,7 +980,7 @@ func (sb *syncBuffer) rotateFile(now time.Time) error {
 	// stack traces that are written by the Go runtime to stderr. Note that if
 	// --logtostderr is true we'll never enter this code path and panic stack
 	// traces will go to the original stderr as you would expect.
-	if logging.stderrThreshold > Severity_INFO && !logging.noStderrRedirect {
+	if sb.logger.redirectsStderr() {
 		// NB: any concurrent output to stderr may straddle the old and new
 		// files. This doesn't apply to log messages as we won't reach this code
 		// unless we're not logging to stderr.
@@ -1006,11 +1015,11 @@ func (sb *syncBuffer) rotateFile(now time.Time) error {
 		if err != nil {
 			return err
 		}
-		logging.putBuffer(buf)
+		sb.logger.putBuffer(buf)
 	}
 
 	select {
-	case logging.gcNotify <- struct{}{}:
+	case sb.logger.gcNotify <- struct{}{}:
 	default:
 	}
 	return nil
@@ -1030,9 +1039,19 @@ func (l *loggingT) closeFileLocked() error {
 		}
 		l.file = nil
 	}
+	if l != &logging {
+		// Only the main logger redirects stderr.
+		return nil
+	}
 	return restoreStderr()
 }
 
+// redirectsStderr returns whether the log files created by this logger
+// should also capture the process' stderr.
+func (l *loggingT) redirectsStderr() bool {
+	return l == &logging && l.stderrThreshold > Severity_INFO && !l.noStderrRedirect
+}
+
 // createFile creates the log file.
 // l.mu is held.
 func (l *loggingT) createFile() error {
@@ -1110,8 +1129,17 @@ func (l *loggingT) gcOldFiles() {
 		return
 	}
 
+	// Only consider the files created by this logger; the files of the
+	// other loggers sharing the directory are collected separately.
+	var ownFiles []FileInfo
+	for _, f := range allFiles {
+		if f.Details.Program == removePeriods(l.prefix) {
+			ownFiles = append(ownFiles, f)
+		}
+	}
+
 	logFilesCombinedMaxSize := atomic.LoadInt64(&LogFilesCombinedMaxSize)
-	files := selectFiles(allFiles, math.MaxInt64)
+	files := selectFiles(ownFiles, math.MaxInt64)
 	if len(files) == 0 {
 		return
 	}
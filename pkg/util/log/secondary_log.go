@@ -0,0 +1,97 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package log
+
+import (
+	"os"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/util/caller"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// SecondaryLogger represents a secondary / auxiliary logging channel
+// whose logging events go to a different set of files than the main
+// logging facility. The files live in the same directory as the main
+// log files, but their name is prefixed with the name of the logger.
+type SecondaryLogger struct {
+	logger loggingT
+}
+
+// secondaryLogRegistry keeps track of the secondary loggers so that
+// they can be flushed alongside the main logger.
+var secondaryLogRegistry struct {
+	mu struct {
+		syncutil.Mutex
+		loggers []*SecondaryLogger
+	}
+}
+
+// NewSecondaryLogger creates a secondary logger with the given name.
+//
+// The given name is appended to the program name to form the prefix of
+// the log files. If enableGc is set, the old files of this logger are
+// removed using the same size limit as the main log files. If
+// forceSyncWrites is set, every entry is synced to disk before Logf
+// returns.
+func NewSecondaryLogger(name string, enableGc bool, forceSyncWrites bool) *SecondaryLogger {
+	l := &SecondaryLogger{
+		logger: loggingT{
+			prefix:           program + "-" + name,
+			noStderrRedirect: true,
+			stderrThreshold:  Severity_NONE,
+			fileThreshold:    Severity_INFO,
+			syncWrites:       forceSyncWrites,
+			exitFunc:         os.Exit,
+			gcNotify:         make(chan struct{}, 1),
+		},
+	}
+	secondaryLogRegistry.mu.Lock()
+	secondaryLogRegistry.mu.loggers = append(secondaryLogRegistry.mu.loggers, l)
+	secondaryLogRegistry.mu.Unlock()
+
+	go l.logger.flushDaemon()
+	if enableGc {
+		go l.logger.gcDaemon()
+	}
+	return l
+}
+
+// Logf logs an event on a secondary logger.
+func (l *SecondaryLogger) Logf(ctx context.Context, format string, args ...interface{}) {
+	file, line, _ := caller.Lookup(1)
+	msg := MakeMessage(ctx, format, args)
+	// MakeMessage already added the tags when forming msg, we don't want
+	// eventInternal to prepend them again.
+	eventInternal(ctx, false /*isErr*/, false /*withTags*/, "%s:%d %s", file, line, msg)
+	l.logger.outputLogEntry(Severity_INFO, file, line, msg)
+}
+
+// closeSecondaryLogFiles closes the current files of all the secondary
+// loggers, so that new files are created on the next logging event.
+func closeSecondaryLogFiles() error {
+	secondaryLogRegistry.mu.Lock()
+	defer secondaryLogRegistry.mu.Unlock()
+	for _, l := range secondaryLogRegistry.mu.loggers {
+		l.logger.mu.Lock()
+		err := l.logger.closeFileLocked()
+		l.logger.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
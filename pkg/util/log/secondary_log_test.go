@@ -0,0 +1,72 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package log
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func TestSecondaryLog(t *testing.T) {
+	s := ScopeWithoutShowLogs(t)
+	defer s.Close(t)
+
+	ctx := context.Background()
+	l := NewSecondaryLogger("woo", false /* enableGc */, true /* forceSyncWrites */)
+	l.Logf(ctx, "test%d", 123)
+	Infof(ctx, "main%d", 456)
+	Flush()
+
+	dir, err := logDir.get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := ListLogFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mainContents, secondaryContents string
+	for _, f := range files {
+		b, err := ioutil.ReadFile(filepath.Join(dir, f.Name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch f.Details.Program {
+		case removePeriods(program):
+			mainContents += string(b)
+		case removePeriods(program + "-woo"):
+			secondaryContents += string(b)
+		default:
+			t.Errorf("unexpected log file %s", f.Name)
+		}
+	}
+
+	if !strings.Contains(secondaryContents, "test123") {
+		t.Errorf("expected secondary log to contain test123:\n%s", secondaryContents)
+	}
+	if strings.Contains(secondaryContents, "main456") {
+		t.Errorf("expected secondary log to not contain main456:\n%s", secondaryContents)
+	}
+	if !strings.Contains(mainContents, "main456") {
+		t.Errorf("expected main log to contain main456:\n%s", mainContents)
+	}
+	if strings.Contains(mainContents, "test123") {
+		t.Errorf("expected main log to not contain test123:\n%s", mainContents)
+	}
+}
@@ -168,6 +168,9 @@ func dirTestOverride(expected, newDir string) error {
 	// When we change the directory we close the current logging
 	// output, so that a rotation to the new directory is forced on
 	// the next logging event.
+	if err := closeSecondaryLogFiles(); err != nil {
+		return err
+	}
 	return logging.closeFileLocked()
 }
 
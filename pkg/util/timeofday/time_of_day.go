@@ -0,0 +1,122 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package timeofday implements the times of day, without a date or a time
+// zone, stored by the TIME SQL type.
+package timeofday
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+)
+
+// TimeOfDay is the number of microseconds since midnight.
+type TimeOfDay int64
+
+const (
+	// Min is the minimum TimeOfDay value (midnight).
+	Min = TimeOfDay(0)
+	// Max is the maximum TimeOfDay value (1 microsecond before midnight).
+	Max = TimeOfDay(microsecondsPerDay - 1)
+
+	microsecondsPerSecond = 1000000
+	microsecondsPerMinute = 60 * microsecondsPerSecond
+	microsecondsPerHour   = 60 * microsecondsPerMinute
+	microsecondsPerDay    = 24 * microsecondsPerHour
+	nanosPerMicro         = 1000
+)
+
+// New constructs a TimeOfDay from its components. The components are not
+// range checked; out of range values wrap around midnight.
+func New(hour, min, sec, micro int) TimeOfDay {
+	micros := int64(hour)*microsecondsPerHour + int64(min)*microsecondsPerMinute +
+		int64(sec)*microsecondsPerSecond + int64(micro)
+	return FromInt(micros)
+}
+
+// FromInt constructs a TimeOfDay from a number of microseconds since
+// midnight, wrapping around midnight if necessary.
+func FromInt(micros int64) TimeOfDay {
+	micros %= microsecondsPerDay
+	if micros < 0 {
+		micros += microsecondsPerDay
+	}
+	return TimeOfDay(micros)
+}
+
+// FromTime constructs a TimeOfDay from the wall clock time of t, truncated to
+// microsecond precision.
+func FromTime(t time.Time) TimeOfDay {
+	return New(t.Hour(), t.Minute(), t.Second(), t.Nanosecond()/nanosPerMicro)
+}
+
+// ToTime returns the time of day on the Unix epoch date, in UTC.
+func (t TimeOfDay) ToTime() time.Time {
+	return time.Unix(0, int64(t)*nanosPerMicro).UTC()
+}
+
+// Hour returns the hour of the day, in the range [0, 23].
+func (t TimeOfDay) Hour() int {
+	return int(int64(t) / microsecondsPerHour)
+}
+
+// Minute returns the minute of the hour, in the range [0, 59].
+func (t TimeOfDay) Minute() int {
+	return int(int64(t) % microsecondsPerHour / microsecondsPerMinute)
+}
+
+// Second returns the second of the minute, in the range [0, 59].
+func (t TimeOfDay) Second() int {
+	return int(int64(t) % microsecondsPerMinute / microsecondsPerSecond)
+}
+
+// Microsecond returns the microsecond of the second, in the range [0, 999999].
+func (t TimeOfDay) Microsecond() int {
+	return int(int64(t) % microsecondsPerSecond)
+}
+
+// String formats the time as HH:MM:SS, followed by the fractional seconds if
+// they are not zero.
+func (t TimeOfDay) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%02d:%02d:%02d", t.Hour(), t.Minute(), t.Second())
+	if micros := t.Microsecond(); micros > 0 {
+		fmt.Fprintf(&buf, ".%06d", micros)
+		buf.Truncate(len(strings.TrimRight(buf.String(), "0")))
+	}
+	return buf.String()
+}
+
+// Add returns the time of day d after t, wrapping around midnight. The month
+// and day components of d are ignored, since they always amount to a whole
+// number of days.
+func (t TimeOfDay) Add(d duration.Duration) TimeOfDay {
+	return FromInt(int64(t) + d.Nanos/nanosPerMicro)
+}
+
+// Difference returns the interval between t1 and t2, which is negative if t1
+// is before t2.
+func Difference(t1 TimeOfDay, t2 TimeOfDay) duration.Duration {
+	return duration.Duration{Nanos: (int64(t1) - int64(t2)) * nanosPerMicro}
+}
+
+// Random generates a random TimeOfDay.
+func Random(rng *rand.Rand) TimeOfDay {
+	return TimeOfDay(rng.Int63n(microsecondsPerDay))
+}
@@ -0,0 +1,75 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package timeofday
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+)
+
+func TestString(t *testing.T) {
+	testCases := []struct {
+		t        TimeOfDay
+		expected string
+	}{
+		{Min, "00:00:00"},
+		{New(13, 4, 5, 0), "13:04:05"},
+		{New(13, 4, 5, 120000), "13:04:05.12"},
+		{New(13, 4, 5, 1), "13:04:05.000001"},
+		{Max, "23:59:59.999999"},
+	}
+	for _, tc := range testCases {
+		if s := tc.t.String(); s != tc.expected {
+			t.Errorf("expected %s, got %s", tc.expected, s)
+		}
+	}
+}
+
+func TestFromTime(t *testing.T) {
+	tm := time.Date(2017, 1, 2, 13, 4, 5, 123456789, time.UTC)
+	if expected, actual := New(13, 4, 5, 123456), FromTime(tm); expected != actual {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+	if expected, actual := time.Date(1970, 1, 1, 13, 4, 5, 123456000, time.UTC),
+		New(13, 4, 5, 123456).ToTime(); !expected.Equal(actual) {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	testCases := []struct {
+		t        TimeOfDay
+		d        duration.Duration
+		expected TimeOfDay
+	}{
+		{New(12, 0, 0, 0), duration.Duration{Nanos: int64(time.Hour)}, New(13, 0, 0, 0)},
+		{New(23, 0, 0, 0), duration.Duration{Nanos: int64(2 * time.Hour)}, New(1, 0, 0, 0)},
+		{New(1, 0, 0, 0), duration.Duration{Nanos: -int64(2 * time.Hour)}, New(23, 0, 0, 0)},
+		{New(1, 0, 0, 0), duration.Duration{Months: 1, Days: 2}, New(1, 0, 0, 0)},
+		{Max, duration.Duration{Nanos: int64(time.Microsecond)}, Min},
+	}
+	for _, tc := range testCases {
+		if actual := tc.t.Add(tc.d); actual != tc.expected {
+			t.Errorf("%s + %s: expected %s, got %s", tc.t, tc.d, tc.expected, actual)
+		}
+	}
+
+	d := Difference(New(10, 0, 0, 0), New(12, 30, 0, 0))
+	if expected := (duration.Duration{Nanos: -int64(150 * time.Minute)}); d != expected {
+		t.Errorf("expected %s, got %s", expected, d)
+	}
+}